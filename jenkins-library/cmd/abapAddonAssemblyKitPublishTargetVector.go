@@ -51,7 +51,11 @@ func runAbapAddonAssemblyKitPublishTargetVector(config *abapAddonAssemblyKitPubl
 	}
 
 	if err := targetVector.PublishTargetVector(conn, aakaas.TargetVectorStatus(config.TargetVectorScope)); err != nil {
-		return err
+		// a previous run of the step may have started or finished the publishing already
+		if !publishedByPreviousRun(targetVector, conn, aakaas.TargetVectorStatus(config.TargetVectorScope)) {
+			return err
+		}
+		log.Entry().Infof("Publishing of target vector %s was already started by a previous run", addonDescriptor.TargetVectorID)
 	}
 
 	log.Entry().Info("Waiting for target vector publishing to finish")
@@ -62,3 +66,16 @@ func runAbapAddonAssemblyKitPublishTargetVector(config *abapAddonAssemblyKitPubl
 	log.Entry().Info("Success: Publishing finished")
 	return nil
 }
+
+func publishedByPreviousRun(targetVector *aakaas.TargetVector, conn *abapbuild.Connector, scope aakaas.TargetVectorStatus) bool {
+	if err := targetVector.GetTargetVector(conn); err != nil {
+		return false
+	}
+	switch aakaas.TargetVectorStatus(targetVector.PublishStatus) {
+	case aakaas.TargetVectorPublishStatusRunning:
+		return true
+	case aakaas.TargetVectorPublishStatusSuccess:
+		return aakaas.TargetVectorStatus(targetVector.Status) == scope
+	}
+	return false
+}
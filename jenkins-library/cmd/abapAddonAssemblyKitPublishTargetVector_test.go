@@ -59,6 +59,23 @@ func TestPublishTargetVectorStep(t *testing.T) {
 		assert.NoError(t, err, "Did not expect error")
 	})
 
+	t.Run("step resumes publishing of previous run", func(t *testing.T) {
+		//arrange
+		config.TargetVectorScope = "P"
+		mc := abapbuild.NewMockClient()
+		mc.AddData(aakaas.AAKaaSHead)
+		// no mock data for publishing, the request fails
+		mc.AddData(aakaas.AAKaaSGetTVPublishRunning)
+		mc.AddData(aakaas.AAKaaSGetTVPublishProdSuccess)
+		bundle := aakaas.NewAakBundleMockNewMC(&mc)
+		utils := bundle.GetUtils()
+
+		//act
+		err := runAbapAddonAssemblyKitPublishTargetVector(&config, &utils)
+		//assert
+		assert.NoError(t, err, "Did not expect error")
+	})
+
 	t.Run("step fail http", func(t *testing.T) {
 		//arrange
 		bundle := aakaas.NewAakBundleMock()
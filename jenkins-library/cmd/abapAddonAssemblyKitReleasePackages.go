@@ -55,6 +55,7 @@ func runAbapAddonAssemblyKitReleasePackages(config *abapAddonAssemblyKitReleaseP
 	addonDescriptor.Repositories = sortingBack(packagesWithReposLocked, packagesWithReposNotLocked)
 	log.Entry().Info("Writing package status to CommonPipelineEnvironment")
 	cpe.abap.addonDescriptor = string(addonDescriptor.AsJSON())
+	writeAddonStatusReport("abapAddonAssemblyKitReleasePackages", &addonDescriptor, *utils)
 	return nil
 }
 
@@ -112,7 +113,11 @@ func releaseAndPoll(pckgWR []aakaas.PackageWithRepository, utils *aakaas.AakUtil
 			for i := range pckgWR {
 				if pckgWR[i].Package.Status != aakaas.PackageStatusReleased {
 					err := pckgWR[i].Package.Release()
-					// if there is an error, release is not yet finished
+					// if there is an error, release is not yet finished unless a previous run of the step has released the package already
+					if err != nil && releasedByPreviousRun(&pckgWR[i].Package) {
+						log.Entry().Infof("Package %s was already released by a previous run", pckgWR[i].Package.PackageName)
+						continue
+					}
 					if err != nil {
 						log.Entry().Error(err)
 						log.Entry().Infof("Release of %s is not yet finished, check again in %s", pckgWR[i].Package.PackageName, (*utils).GetPollingInterval())
@@ -127,3 +132,12 @@ func releaseAndPoll(pckgWR []aakaas.PackageWithRepository, utils *aakaas.AakUtil
 		}
 	}
 }
+
+// releasedByPreviousRun checks the current status of the package in AAKaaS, since a rerun of the step after an
+// interruption starts with the status of the addon descriptor before the release
+func releasedByPreviousRun(p *aakaas.Package) bool {
+	if err := p.GetPackageAndNamespace(); err != nil {
+		return false
+	}
+	return p.Status == aakaas.PackageStatusReleased
+}
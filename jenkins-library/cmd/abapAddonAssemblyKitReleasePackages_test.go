@@ -5,11 +5,15 @@ package cmd
 
 import (
 	"encoding/json"
+	"net/http"
+	"os"
 	"testing"
 	"time"
 
 	"github.com/SAP/jenkins-library/pkg/abap/aakaas"
+	abapbuild "github.com/SAP/jenkins-library/pkg/abap/build"
 	"github.com/SAP/jenkins-library/pkg/abaputils"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -46,6 +50,9 @@ func TestReleasePackagesStep(t *testing.T) {
 		err = json.Unmarshal([]byte(cpe.abap.addonDescriptor), &addonDescriptorFinal)
 		assert.NoError(t, err)
 		assert.Equal(t, "R", addonDescriptorFinal.Repositories[0].Status)
+		report, err := bundle.FileRead("abapAddonStatus_report.json")
+		assert.NoError(t, err)
+		assert.Contains(t, string(report), `"packageStatus": "released"`)
 	})
 
 	t.Run("step error - invalid input", func(t *testing.T) {
@@ -87,6 +94,39 @@ func TestReleasePackagesStep(t *testing.T) {
 		assert.Equal(t, err.Error(), "Release of all packages failed/timed out - Aborting as abapEnvironmentAssembleConfirm step is not needed: Timed out")
 	})
 }
+
+func TestReleasePackagesStepResume(t *testing.T) {
+	t.Run("package released by a previous run", func(t *testing.T) {
+		//arrange
+		mc := abapbuild.NewMockClient()
+		mc.AddData(aakaas.AAKaaSHead)
+		// the release fails since the package is not locked anymore
+		mc.AddBody("POST", "/odata/aas_ocs_package/ReleasePackage?Name='SAPK-001AAINDRNMSPC'", "package is already released", http.StatusBadRequest, http.Header{})
+		mc.Add("GET", "/odata/aas_ocs_package/OcsPackageSet('SAPK-001AAINDRNMSPC')", responseRelease)
+		bundle := aakaas.NewAakBundleMockNewMC(&mc)
+		bundle.Files = &piperutils.Files{}
+		utils := bundle.GetUtils()
+		dir := t.TempDir()
+		oldCWD, _ := os.Getwd()
+		_ = os.Chdir(dir)
+		defer func() {
+			_ = os.Chdir(oldCWD)
+		}()
+		config := abapAddonAssemblyKitReleasePackagesOptions{Username: "dummyUser", Password: "dummyPassword"}
+		adoDesc, _ := json.Marshal(abaputils.AddonDescriptor{
+			Repositories: []abaputils.Repository{{PackageName: "SAPK-001AAINDRNMSPC", Status: "L"}},
+		})
+		config.AddonDescriptor = string(adoDesc)
+		var cpe abapAddonAssemblyKitReleasePackagesCommonPipelineEnvironment
+		//act
+		err := runAbapAddonAssemblyKitReleasePackages(&config, &utils, &cpe)
+		//assert
+		assert.NoError(t, err)
+		assert.Contains(t, cpe.abap.addonDescriptor, `"Status":"R"`)
+		assert.FileExists(t, "abapAddonStatus_report.json")
+	})
+}
+
 func TestReleasePackagesStepMix(t *testing.T) {
 	var config abapAddonAssemblyKitReleasePackagesOptions
 	var cpe abapAddonAssemblyKitReleasePackagesCommonPipelineEnvironment
@@ -16,6 +16,11 @@ import (
 	"github.com/pkg/errors"
 )
 
+const (
+	addonStatusReportJSON     = "abapAddonStatus_report.json"
+	addonStatusReportMarkdown = "abapAddonStatus_report.md"
+)
+
 type buildWithRepository struct {
 	build abapbuild.Build
 	repo  abaputils.Repository
@@ -53,7 +58,10 @@ func runAbapEnvironmentAssemblePackages(config *abapEnvironmentAssemblePackagesO
 		return errors.Wrap(err, "Reading AddonDescriptor failed [Make sure abapAddonAssemblyKit...CheckCVs|CheckPV|ReserveNextPackages steps have been run before]")
 	}
 
-	builds, assembleError := runAssemblePackages(config, com, utils, client, addonDescriptor)
+	builds, assembleError := runAssemblePackages(config, com, utils, client, addonDescriptor, func() {
+		cpe.abap.addonDescriptor = addonDescriptor.AsJSONstring()
+		persistAssemblyState(cpe)
+	})
 	if assembleError != nil && builds != nil {
 		addonDescriptor.ErrorText = assembleError.Error()
 		log.Entry().Info("---------------------------------")
@@ -77,16 +85,46 @@ func runAbapEnvironmentAssemblePackages(config *abapEnvironmentAssemblePackagesO
 	addonDescriptor.SetRepositories(reposBackToCPE)
 	cpe.abap.addonDescriptor = addonDescriptor.AsJSONstring()
 
+	writeAddonStatusReport("abapEnvironmentAssemblePackages", addonDescriptor, utils)
+
 	return assembleError
 }
 
-func runAssemblePackages(config *abapEnvironmentAssemblePackagesOptions, com abaputils.Communication, utils piperutils.FileUtils, client abapbuild.HTTPSendLoader, addonDescriptor *abaputils.AddonDescriptor) ([]buildWithRepository, error) {
+// persistAssemblyState writes the commonPipelineEnvironment including the IDs of the started builds right away, so
+// that a rerun of the step after an interruption resumes the running builds instead of starting them again
+var persistAssemblyState = func(cpe *abapEnvironmentAssemblePackagesCommonPipelineEnvironment) {
+	cpe.persist(GeneralConfig.EnvRootPath, "commonPipelineEnvironment")
+}
+
+// writeAddonStatusReport writes the consolidated report of all software component versions and package states of the addon product version
+func writeAddonStatusReport(stepName string, addonDescriptor *abaputils.AddonDescriptor, utils piperutils.FileUtils) {
+	report := addonDescriptor.StatusReport()
+	jsonReport, err := report.ToJSON()
+	if err == nil {
+		err = utils.FileWrite(addonStatusReportJSON, jsonReport, 0o666)
+	}
+	if err == nil {
+		err = utils.FileWrite(addonStatusReportMarkdown, []byte(report.ToMarkdown()), 0o666)
+	}
+	if err != nil {
+		log.Entry().WithError(err).Warn("Writing the addon status report failed")
+		return
+	}
+	if err := piperutils.PersistReportsAndLinks(stepName, "", utils, []piperutils.Path{
+		{Name: "Addon Status Report (JSON)", Target: addonStatusReportJSON},
+		{Name: "Addon Status Report (Markdown)", Target: addonStatusReportMarkdown},
+	}, nil); err != nil {
+		log.Entry().WithError(err).Warn("failed to persist reports")
+	}
+}
+
+func runAssemblePackages(config *abapEnvironmentAssemblePackagesOptions, com abaputils.Communication, utils piperutils.FileUtils, client abapbuild.HTTPSendLoader, addonDescriptor *abaputils.AddonDescriptor, persistState func()) ([]buildWithRepository, error) {
 	connBuild := new(abapbuild.Connector)
 	if errConBuild := initAssemblePackagesConnection(connBuild, config, com, client); errConBuild != nil {
 		return nil, errConBuild
 	}
 
-	builds, err := executeBuilds(addonDescriptor, *connBuild, time.Duration(config.MaxRuntimeInMinutes)*time.Minute, time.Duration(config.PollIntervalsInMilliseconds)*time.Millisecond, config.AlternativePhaseName)
+	builds, err := executeBuilds(addonDescriptor, *connBuild, time.Duration(config.MaxRuntimeInMinutes)*time.Minute, time.Duration(config.PollIntervalsInMilliseconds)*time.Millisecond, config.AlternativePhaseName, persistState)
 	if err != nil {
 		return builds, errors.Wrap(err, "Starting Builds for Repositories with reserved AAKaaS packages failed")
 	}
@@ -111,10 +149,10 @@ func runAssemblePackages(config *abapEnvironmentAssemblePackagesOptions, com aba
 	return builds, nil
 }
 
-func executeBuilds(addonDescriptor *abaputils.AddonDescriptor, conn abapbuild.Connector, maxRuntimeInMinutes time.Duration, pollInterval time.Duration, altenativePhaseName string) ([]buildWithRepository, error) {
+func executeBuilds(addonDescriptor *abaputils.AddonDescriptor, conn abapbuild.Connector, maxRuntimeInMinutes time.Duration, pollInterval time.Duration, altenativePhaseName string, persistState func()) ([]buildWithRepository, error) {
 	var builds []buildWithRepository
 
-	for _, repo := range addonDescriptor.Repositories {
+	for i, repo := range addonDescriptor.Repositories {
 
 		buildRepo := buildWithRepository{
 			build: abapbuild.Build{
@@ -125,13 +163,20 @@ func executeBuilds(addonDescriptor *abaputils.AddonDescriptor, conn abapbuild.Co
 
 		if repo.Status == "P" {
 			buildRepo.repo.InBuildScope = true
-			err := buildRepo.start(addonDescriptor, altenativePhaseName)
+			var err error
+			if !buildRepo.resume() {
+				err = buildRepo.start(addonDescriptor, altenativePhaseName)
+				if err == nil {
+					addonDescriptor.Repositories[i].BuildID = buildRepo.build.BuildID
+					persistState()
+				}
+			}
 			if err != nil {
 				buildRepo.build.RunState = abapbuild.Failed
 				buildRepo.repo.ErrorText = fmt.Sprint(err)
 				log.Entry().Error(err)
 				log.Entry().Info("Continueing with other builds (if any)")
-			} else {
+			} else if !buildRepo.build.IsFinished() {
 				err = buildRepo.waitToBeFinished(maxRuntimeInMinutes, pollInterval)
 				if err != nil {
 					buildRepo.build.RunState = abapbuild.Failed
@@ -140,6 +185,9 @@ func executeBuilds(addonDescriptor *abaputils.AddonDescriptor, conn abapbuild.Co
 					log.Entry().Error("Continuing with other builds (if any) but keep in Mind that even if this build finishes beyond timeout the result is not trustworthy due to possible side effects!")
 				}
 			}
+			buildRepo.repo.BuildID = buildRepo.build.BuildID
+			buildRepo.repo.BuildRunState = string(buildRepo.build.RunState)
+			buildRepo.repo.BuildResultState = string(buildRepo.build.ResultState)
 		} else {
 			log.Entry().Infof("Packages %s is in status '%s'. No need to run the assembly", repo.PackageName, repo.Status)
 		}
@@ -149,6 +197,24 @@ func executeBuilds(addonDescriptor *abaputils.AddonDescriptor, conn abapbuild.Co
 	return builds, nil
 }
 
+// resume attaches to the build of a previous run of the step, as long as this build is still running or finished without errors
+func (br *buildWithRepository) resume() bool {
+	if br.repo.BuildID == "" {
+		return false
+	}
+	br.build.BuildID = br.repo.BuildID
+	if err := br.build.Get(); err != nil {
+		log.Entry().WithError(err).Warnf("Build %s of package %s from a previous run could not be retrieved, starting a new build", br.repo.BuildID, br.repo.PackageName)
+	} else if br.build.RunState == abapbuild.Failed || br.build.ResultState == abapbuild.Erroneous || br.build.ResultState == abapbuild.Aborted {
+		log.Entry().Infof("Build %s of package %s from a previous run ended with run state '%s' and result state '%s', starting a new build", br.repo.BuildID, br.repo.PackageName, br.build.RunState, br.build.ResultState)
+	} else {
+		log.Entry().Infof("Resuming build %s of package %s from a previous run with run state '%s'", br.repo.BuildID, br.repo.PackageName, br.build.RunState)
+		return true
+	}
+	br.build = abapbuild.Build{Connector: br.build.Connector}
+	return false
+}
+
 func (br *buildWithRepository) waitToBeFinished(maxRuntimeInMinutes time.Duration, pollInterval time.Duration) error {
 	timeout := time.After(maxRuntimeInMinutes)
 	ticker := time.Tick(pollInterval)
@@ -4,6 +4,7 @@
 package cmd
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -72,7 +73,7 @@ func TestStartingInvalidInput(t *testing.T) {
 				},
 			},
 		}
-		builds, err := executeBuilds(&aD, *conn, time.Duration(0*time.Second), time.Duration(1*time.Millisecond), "")
+		builds, err := executeBuilds(&aD, *conn, time.Duration(0*time.Second), time.Duration(1*time.Millisecond), "", func() {})
 		assert.NoError(t, err)
 		assert.Equal(t, 1, len(builds))
 		assert.Equal(t, abapbuild.Failed, builds[0].build.RunState)
@@ -87,6 +88,12 @@ func TestStep(t *testing.T) {
 	}
 	client := abapbuild.GetBuildMockClient()
 	cpe := &abapEnvironmentAssemblePackagesCommonPipelineEnvironment{}
+	persistedStates := []string{}
+	originalPersistAssemblyState := persistAssemblyState
+	persistAssemblyState = func(cpe *abapEnvironmentAssemblePackagesCommonPipelineEnvironment) {
+		persistedStates = append(persistedStates, cpe.abap.addonDescriptor)
+	}
+	defer func() { persistAssemblyState = originalPersistAssemblyState }()
 
 	t.Run("abapEnvironmentAssemblePackages: nothing to do", func(t *testing.T) {
 		config := &abapEnvironmentAssemblePackagesOptions{
@@ -111,6 +118,86 @@ func TestStep(t *testing.T) {
 		assert.Contains(t, cpe.abap.addonDescriptor, `SAPK-001AAINITAPC1.SAR`)
 		assert.Contains(t, cpe.abap.addonDescriptor, `"InBuildScope":true`)
 	})
+	t.Run("abapEnvironmentAssemblePackages: build state is persisted after start", func(t *testing.T) {
+		config := &abapEnvironmentAssemblePackagesOptions{
+			AddonDescriptor:             cpeAbapAddonDescriptorPackageReserved,
+			MaxRuntimeInMinutes:         1,
+			PollIntervalsInMilliseconds: 1,
+		}
+		client := abapbuild.GetBuildMockClient()
+		files := &mock.FilesMock{}
+		persistedStates = []string{}
+
+		err := runAbapEnvironmentAssemblePackages(config, autils, files, &client, cpe)
+		assert.NoError(t, err)
+		if assert.Len(t, persistedStates, 1) {
+			assert.Contains(t, persistedStates[0], `"BuildID":"AKO22FYOFYPOXHOBVKXUTX3A3Q"`)
+			assert.NotContains(t, persistedStates[0], `"BuildRunState":"FINISHED"`)
+		}
+		assert.Contains(t, cpe.abap.addonDescriptor, `"BuildID":"AKO22FYOFYPOXHOBVKXUTX3A3Q"`)
+		assert.Contains(t, cpe.abap.addonDescriptor, `"BuildRunState":"FINISHED"`)
+		report, err := files.FileRead("abapAddonStatus_report.json")
+		if assert.NoError(t, err) {
+			assert.Contains(t, string(report), `"buildResultState": "SUCCESSFUL"`)
+		}
+		assert.True(t, files.HasWrittenFile("abapAddonStatus_report.md"))
+		reports, err := files.FileRead("abapEnvironmentAssemblePackages_reports.json")
+		if assert.NoError(t, err) {
+			assert.Contains(t, string(reports), `"name":"Addon Status Report (JSON)"`)
+			assert.Contains(t, string(reports), `"name":"Addon Status Report (Markdown)"`)
+		}
+	})
+	t.Run("abapEnvironmentAssemblePackages: resume build of previous run", func(t *testing.T) {
+		config := &abapEnvironmentAssemblePackagesOptions{
+			AddonDescriptor:             strings.Replace(cpeAbapAddonDescriptorPackageReserved, `"Status":"P",`, `"Status":"P","BuildID":"AKO22FYOFYPOXHOBVKXUTX3A3Q",`, 1),
+			MaxRuntimeInMinutes:         1,
+			PollIntervalsInMilliseconds: 1,
+		}
+		client := abapbuild.GetBuildMockClient()
+
+		err := runAbapEnvironmentAssemblePackages(config, autils, &mock.FilesMock{}, &client, cpe)
+		assert.NoError(t, err)
+		// the build is not started again
+		assert.Contains(t, client.Data, "POST/sap/opu/odata/BUILD/CORE_SRV/builds")
+		assert.Contains(t, cpe.abap.addonDescriptor, `SAPK-001AAINITAPC1.SAR`)
+	})
+}
+
+func TestResumeBuild(t *testing.T) {
+	t.Run("failed build of previous run is not resumed", func(t *testing.T) {
+		client := abapbuild.NewMockClient()
+		client.AddData(abapbuild.MockData{
+			Method:     `GET`,
+			Url:        `/builds('ABIFNLDCSQPOVMXK4DNPBDRW2M')`,
+			Body:       `{"d":{"build_id":"ABIFNLDCSQPOVMXK4DNPBDRW2M","run_state":"FAILED","result_state":""}}`,
+			StatusCode: 200,
+		})
+		br := buildWithRepository{
+			build: testSetup(&client, ""),
+			repo:  abaputils.Repository{PackageName: "SAPK-001AAINITAPC1", BuildID: "ABIFNLDCSQPOVMXK4DNPBDRW2M"},
+		}
+
+		assert.False(t, br.resume())
+		assert.Empty(t, br.build.BuildID)
+		assert.Empty(t, br.build.RunState)
+	})
+	t.Run("unknown build of previous run is not resumed", func(t *testing.T) {
+		client := abapbuild.NewMockClient()
+		br := buildWithRepository{
+			build: testSetup(&client, ""),
+			repo:  abaputils.Repository{PackageName: "SAPK-001AAINITAPC1", BuildID: "ABIFNLDCSQPOVMXK4DNPBDRW2M"},
+		}
+
+		assert.False(t, br.resume())
+		assert.Empty(t, br.build.BuildID)
+	})
+	t.Run("no build of previous run", func(t *testing.T) {
+		br := buildWithRepository{
+			build: testSetup(&abapbuild.ClMock{}, ""),
+		}
+
+		assert.False(t, br.resume())
+	})
 }
 
 var cpeAbapAddonDescriptorPackageLocked = `{
@@ -15,8 +15,10 @@ import (
 
 	"github.com/SAP/jenkins-library/pkg/abaputils"
 	"github.com/SAP/jenkins-library/pkg/command"
+	piperGithub "github.com/SAP/jenkins-library/pkg/github"
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/orchestrator"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 	"github.com/pkg/errors"
@@ -71,7 +73,7 @@ func runAbapEnvironmentRunATCCheck(autils abaputils.AbapUtils, client piperhttp.
 	if err != nil {
 		return err
 	}
-	if err = fetchAndPersistATCResults(resp, details, &client, &fileUtils, options.AtcResultsFileName, options.GenerateHTML, options.FailOnSeverity); err != nil {
+	if err = fetchAndPersistATCResults(resp, details, &client, &fileUtils, &options); err != nil {
 		return err
 	}
 
@@ -79,7 +81,7 @@ func runAbapEnvironmentRunATCCheck(autils abaputils.AbapUtils, client piperhttp.
 	return nil
 }
 
-func fetchAndPersistATCResults(resp *http.Response, details abaputils.ConnectionDetailsHTTP, client piperhttp.Sender, utils piperutils.FileUtils, atcResultFileName string, generateHTML bool, failOnSeverityLevel string) error {
+func fetchAndPersistATCResults(resp *http.Response, details abaputils.ConnectionDetailsHTTP, client piperhttp.Sender, utils piperutils.FileUtils, options *abapEnvironmentRunATCCheckOptions) error {
 	var err error
 	var failStep bool
 	abapEndpoint := details.URL
@@ -97,7 +99,7 @@ func fetchAndPersistATCResults(resp *http.Response, details abaputils.Connection
 	}
 	if err == nil {
 		defer resp.Body.Close()
-		err, failStep = logAndPersistAndEvaluateATCResults(utils, body, atcResultFileName, generateHTML, failOnSeverityLevel)
+		err, failStep = logAndPersistAndEvaluateATCResults(utils, body, options)
 	}
 	if err != nil {
 		return errors.Errorf("Handling ATC result failed: %v", err)
@@ -214,8 +216,9 @@ func getATCObjectSet(ATCConfig ATCConfiguration) (objectSet string, err error) {
 	return objectSet, nil
 }
 
-func logAndPersistAndEvaluateATCResults(utils piperutils.FileUtils, body []byte, atcResultFileName string, generateHTML bool, failOnSeverityLevel string) (error, bool) {
+func logAndPersistAndEvaluateATCResults(utils piperutils.FileUtils, body []byte, options *abapEnvironmentRunATCCheckOptions) (error, bool) {
 	var failStep bool
+	atcResultFileName := options.AtcResultsFileName
 	if len(body) == 0 {
 		return errors.Errorf("Parsing ATC result failed: %v", errors.New("Body is empty, can't parse empty body")), failStep
 	}
@@ -234,6 +237,20 @@ func logAndPersistAndEvaluateATCResults(utils piperutils.FileUtils, body []byte,
 		log.Entry().Info("There were no results from this run, most likely the checked Software Components are empty or contain no ATC findings")
 	}
 
+	findings := parsedXML.findings()
+	// without a baseline all findings are evaluated
+	var newFindings []abaputils.ATCFinding
+	evaluatedFindings := findings
+	if len(options.AtcBaselineFile) > 0 {
+		baseline, err := readATCBaseline(utils, options.AtcBaselineFile)
+		if err != nil {
+			return err, failStep
+		}
+		newFindings = abaputils.NewATCFindings(findings, baseline)
+		evaluatedFindings = newFindings
+		log.Entry().Infof("%d of %d ATC findings are not contained in the baseline %s", len(newFindings), len(findings), options.AtcBaselineFile)
+	}
+
 	err := os.WriteFile(atcResultFileName, body, 0o644)
 	if err == nil {
 		log.Entry().Infof("Writing %s file was successful", atcResultFileName)
@@ -242,12 +259,14 @@ func logAndPersistAndEvaluateATCResults(utils piperutils.FileUtils, body []byte,
 		for _, s := range parsedXML.Files {
 			for _, t := range s.ATCErrors {
 				log.Entry().Infof("%s in file '%s': %s in line %s found by %s", t.Severity, s.Key, t.Message, t.Line, t.Source)
-				if !failStep {
-					failStep = checkStepFailing(t.Severity, failOnSeverityLevel)
-				}
 			}
 		}
-		if generateHTML {
+		for _, finding := range evaluatedFindings {
+			if !failStep {
+				failStep = checkStepFailing(finding.Severity, options.FailOnSeverity)
+			}
+		}
+		if options.GenerateHTML {
 			htmlString := generateHTMLDocument(parsedXML)
 			htmlStringByte := []byte(htmlString)
 			atcResultHTMLFileName := strings.Trim(atcResultFileName, ".xml") + ".html"
@@ -257,6 +276,21 @@ func logAndPersistAndEvaluateATCResults(utils piperutils.FileUtils, body []byte,
 				reports = append(reports, piperutils.Path{Target: atcResultFileName, Name: "ATC Results HTML file", Mandatory: true})
 			}
 		}
+		if err == nil && options.GenerateSarif {
+			atcResultSarifFileName := strings.TrimSuffix(atcResultFileName, ".xml") + ".sarif"
+			sarif, _ := json.MarshalIndent(abaputils.CreateATCSarif(findings, options.SarifSourceDirectory), "", "  ")
+			err = utils.FileWrite(atcResultSarifFileName, sarif, 0o644)
+			if err == nil {
+				log.Entry().Info("Writing " + atcResultSarifFileName + " file was successful")
+				reports = append(reports, piperutils.Path{Target: atcResultSarifFileName, Name: "ATC Results SARIF file", Mandatory: true})
+			}
+		}
+		if err == nil && options.CreatePullRequestComment {
+			summary := abaputils.ATCSummaryMarkdown(findings, newFindings, options.SarifSourceDirectory)
+			if commentErr := commentATCSummaryOnPullRequest(options, summary); commentErr != nil {
+				log.Entry().WithError(commentErr).Warning("failed to comment ATC results on pull request")
+			}
+		}
 		piperutils.PersistReportsAndLinks("abapEnvironmentRunATCCheck", "", utils, reports, nil)
 	}
 	if err != nil {
@@ -264,6 +298,40 @@ func logAndPersistAndEvaluateATCResults(utils piperutils.FileUtils, body []byte,
 	}
 	return nil, failStep
 }
+
+func readATCBaseline(utils piperutils.FileUtils, baselineFile string) ([]abaputils.ATCFinding, error) {
+	exists, err := utils.FileExists(baselineFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to check ATC baseline %s", baselineFile)
+	}
+	if !exists {
+		log.Entry().Warningf("ATC baseline %s does not exist, all findings are considered new", baselineFile)
+		return nil, nil
+	}
+	content, err := utils.FileRead(baselineFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read ATC baseline %s", baselineFile)
+	}
+	baseline := new(Result)
+	if err := xml.Unmarshal(content, baseline); err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, errors.Wrapf(err, "failed to parse ATC baseline %s", baselineFile)
+	}
+	return baseline.findings(), nil
+}
+
+func commentATCSummaryOnPullRequest(options *abapEnvironmentRunATCCheckOptions, summary string) error {
+	number, err := orchestrator.PullRequestNumber()
+	if err != nil {
+		return err
+	}
+	if number == 0 {
+		log.Entry().Info("Not running for a pull request, skipping ATC results comment")
+		return nil
+	}
+	return piperGithub.CreatePullRequestComment(options.GithubToken, options.GithubAPIURL, options.Owner, options.Repository, number, summary)
+}
+
 func checkStepFailing(severity string, failOnSeverityLevel string) bool {
 	switch failOnSeverityLevel {
 	case "error":
@@ -499,6 +567,22 @@ type Result struct {
 	Files   []File   `xml:"file"`
 }
 
+func (result *Result) findings() []abaputils.ATCFinding {
+	findings := []abaputils.ATCFinding{}
+	for _, file := range result.Files {
+		for _, atcError := range file.ATCErrors {
+			findings = append(findings, abaputils.ATCFinding{
+				File:     file.Key,
+				Line:     abaputils.ATCStartLine(atcError.Line, file.Key),
+				Severity: atcError.Severity,
+				Message:  atcError.Message,
+				Source:   atcError.Source,
+			})
+		}
+	}
+	return findings
+}
+
 // File that contains ATC check with error for checked file
 type File struct {
 	Key       string     `xml:"name,attr"`
@@ -17,19 +17,27 @@ import (
 )
 
 type abapEnvironmentRunATCCheckOptions struct {
-	AtcConfig          string `json:"atcConfig,omitempty"`
-	Repositories       string `json:"repositories,omitempty"`
-	CfAPIEndpoint      string `json:"cfApiEndpoint,omitempty"`
-	CfOrg              string `json:"cfOrg,omitempty"`
-	CfServiceInstance  string `json:"cfServiceInstance,omitempty"`
-	CfServiceKeyName   string `json:"cfServiceKeyName,omitempty"`
-	CfSpace            string `json:"cfSpace,omitempty"`
-	Username           string `json:"username,omitempty"`
-	Password           string `json:"password,omitempty"`
-	Host               string `json:"host,omitempty"`
-	AtcResultsFileName string `json:"atcResultsFileName,omitempty"`
-	GenerateHTML       bool   `json:"generateHTML,omitempty"`
-	FailOnSeverity     string `json:"failOnSeverity,omitempty"`
+	AtcConfig                string `json:"atcConfig,omitempty"`
+	Repositories             string `json:"repositories,omitempty"`
+	CfAPIEndpoint            string `json:"cfApiEndpoint,omitempty"`
+	CfOrg                    string `json:"cfOrg,omitempty"`
+	CfServiceInstance        string `json:"cfServiceInstance,omitempty"`
+	CfServiceKeyName         string `json:"cfServiceKeyName,omitempty"`
+	CfSpace                  string `json:"cfSpace,omitempty"`
+	Username                 string `json:"username,omitempty"`
+	Password                 string `json:"password,omitempty"`
+	Host                     string `json:"host,omitempty"`
+	AtcResultsFileName       string `json:"atcResultsFileName,omitempty"`
+	GenerateHTML             bool   `json:"generateHTML,omitempty"`
+	FailOnSeverity           string `json:"failOnSeverity,omitempty"`
+	GenerateSarif            bool   `json:"generateSarif,omitempty"`
+	SarifSourceDirectory     string `json:"sarifSourceDirectory,omitempty"`
+	AtcBaselineFile          string `json:"atcBaselineFile,omitempty"`
+	CreatePullRequestComment bool   `json:"createPullRequestComment,omitempty"`
+	GithubAPIURL             string `json:"githubApiUrl,omitempty"`
+	Owner                    string `json:"owner,omitempty"`
+	Repository               string `json:"repository,omitempty"`
+	GithubToken              string `json:"githubToken,omitempty"`
 }
 
 // AbapEnvironmentRunATCCheckCommand Runs an ATC Check
@@ -53,7 +61,10 @@ Please provide either of the following options:
 * The Cloud Foundry parameters (API endpoint, organization, space), credentials, the service instance for the ABAP service and the service key for the Communication Scenario SAP_COM_0901.
 * Only provide one of those options with the respective credentials. If all values are provided, the direct communication (via host) has priority.
 
-Regardless of the option you chose, please make sure to provide the configuration the object set (e.g. with Software Components and Packages) that you want to be checked analog to the examples listed on this page.`,
+Regardless of the option you chose, please make sure to provide the configuration the object set (e.g. with Software Components and Packages) that you want to be checked analog to the examples listed on this page.
+
+With ` + "`" + `generateSarif` + "`" + ` the findings are additionally written in SARIF format, e.g. to upload them to a code scanning dashboard. The objects and includes of the findings are mapped to the files of the abapGit repository layout below ` + "`" + `sarifSourceDirectory` + "`" + `.
+If an ` + "`" + `atcBaselineFile` + "`" + ` with the results of a previous run is provided, only findings which are not contained in the baseline are considered for ` + "`" + `failOnSeverity` + "`" + `.`,
 		PreRunE: func(cmd *cobra.Command, _ []string) error {
 			startTime = time.Now()
 			log.SetStepName(STEP_NAME)
@@ -86,6 +97,7 @@ Regardless of the option you chose, please make sure to provide the configuratio
 			log.SetStepErrors(stepErrors)
 			log.RegisterSecret(stepConfig.Username)
 			log.RegisterSecret(stepConfig.Password)
+			log.RegisterSecret(stepConfig.GithubToken)
 
 			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
 				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
@@ -185,6 +197,14 @@ func addAbapEnvironmentRunATCCheckFlags(cmd *cobra.Command, stepConfig *abapEnvi
 	cmd.Flags().StringVar(&stepConfig.AtcResultsFileName, "atcResultsFileName", `ATCResults.xml`, "Specifies output file name for the results from the ATC run. This file name will also be used for generating the HTML file")
 	cmd.Flags().BoolVar(&stepConfig.GenerateHTML, "generateHTML", false, "Specifies whether the ATC results should also be generated as an HTML document")
 	cmd.Flags().StringVar(&stepConfig.FailOnSeverity, "failOnSeverity", os.Getenv("PIPER_failOnSeverity"), "Specifies the severity level, for which the ATC step should fail if at least one message with this severity (or \"higher\") level is returned by the ATC Check Run (possible values - error, warning, info). Initial value is default behavior and ATC findings of any severity do not fail the step")
+	cmd.Flags().BoolVar(&stepConfig.GenerateSarif, "generateSarif", false, "Specifies whether the ATC results should also be generated in SARIF format. The file name is derived from `atcResultsFileName`.")
+	cmd.Flags().StringVar(&stepConfig.SarifSourceDirectory, "sarifSourceDirectory", `src`, "Directory of the abapGit repository containing the source files (`STARTING_FOLDER` of the abapGit repository). The artifact locations of the SARIF results are relative to the repository root. The files are expected directly in this directory, folders of sub packages are not resolved.")
+	cmd.Flags().StringVar(&stepConfig.AtcBaselineFile, "atcBaselineFile", os.Getenv("PIPER_atcBaselineFile"), "Path to the ATC results (XML) of a previous run, e.g. of the main branch. If provided, only findings which are not contained in the baseline fail the step and are listed in the pull request comment. Findings are compared by object, check and message, independent of their line.")
+	cmd.Flags().BoolVar(&stepConfig.CreatePullRequestComment, "createPullRequestComment", false, "Posts a summary of the ATC findings as comment to the GitHub pull request which triggered the pipeline.")
+	cmd.Flags().StringVar(&stepConfig.GithubAPIURL, "githubApiUrl", `https://api.github.com`, "Set the GitHub API URL.")
+	cmd.Flags().StringVar(&stepConfig.Owner, "owner", os.Getenv("PIPER_owner"), "Set the GitHub organization.")
+	cmd.Flags().StringVar(&stepConfig.Repository, "repository", os.Getenv("PIPER_repository"), "Set the GitHub repository.")
+	cmd.Flags().StringVar(&stepConfig.GithubToken, "githubToken", os.Getenv("PIPER_githubToken"), "GitHub personal access token as per https://help.github.com/en/github/authenticating-to-github/creating-a-personal-access-token-for-the-command-line")
 
 	cmd.MarkFlagRequired("username")
 	cmd.MarkFlagRequired("password")
@@ -202,6 +222,7 @@ func abapEnvironmentRunATCCheckMetadata() config.StepData {
 			Inputs: config.StepInputs{
 				Secrets: []config.StepSecrets{
 					{Name: "abapCredentialsId", Description: "Jenkins credentials ID containing user and password to authenticate to the Cloud Platform ABAP Environment system or the Cloud Foundry API", Type: "jenkins", Aliases: []config.Alias{{Name: "cfCredentialsId", Deprecated: false}}},
+					{Name: "githubTokenCredentialsId", Description: "Jenkins 'Secret text' credentials ID containing token to authenticate to GitHub.", Type: "jenkins"},
 				},
 				Parameters: []config.StepParameters{
 					{
@@ -333,6 +354,99 @@ func abapEnvironmentRunATCCheckMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     os.Getenv("PIPER_failOnSeverity"),
 					},
+					{
+						Name:        "generateSarif",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS", "GENERAL"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name:        "sarifSourceDirectory",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS", "GENERAL"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `src`,
+					},
+					{
+						Name:        "atcBaselineFile",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_atcBaselineFile"),
+					},
+					{
+						Name:        "createPullRequestComment",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name:        "githubApiUrl",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `https://api.github.com`,
+					},
+					{
+						Name: "owner",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "github/owner",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{{Name: "githubOrg"}},
+						Default:   os.Getenv("PIPER_owner"),
+					},
+					{
+						Name: "repository",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "github/repository",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{{Name: "githubRepo"}},
+						Default:   os.Getenv("PIPER_repository"),
+					},
+					{
+						Name: "githubToken",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "githubTokenCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:    "githubVaultSecretName",
+								Type:    "vaultSecret",
+								Default: "github",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{{Name: "access_token"}},
+						Default:   os.Getenv("PIPER_githubToken"),
+					},
 				},
 			},
 			Containers: []config.Container{
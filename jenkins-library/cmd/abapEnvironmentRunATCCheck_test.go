@@ -264,7 +264,7 @@ func TestParseATCResult(t *testing.T) {
 			</file>
 		</checkstyle>`
 		body := []byte(bodyString)
-		err, failStep := logAndPersistAndEvaluateATCResults(&mock.FilesMock{}, body, "ATCResults.xml", false, "")
+		err, failStep := logAndPersistAndEvaluateATCResults(&mock.FilesMock{}, body, &abapEnvironmentRunATCCheckOptions{AtcResultsFileName: "ATCResults.xml"})
 		assert.Equal(t, false, failStep)
 		assert.Equal(t, nil, err)
 	})
@@ -291,7 +291,7 @@ func TestParseATCResult(t *testing.T) {
 		</checkstyle>`
 		body := []byte(bodyString)
 		doFailOnSeverityLevel := "error"
-		err, failStep := logAndPersistAndEvaluateATCResults(&mock.FilesMock{}, body, "ATCResults.xml", false, doFailOnSeverityLevel)
+		err, failStep := logAndPersistAndEvaluateATCResults(&mock.FilesMock{}, body, &abapEnvironmentRunATCCheckOptions{AtcResultsFileName: "ATCResults.xml", FailOnSeverity: doFailOnSeverityLevel})
 		//fail true
 		assert.Equal(t, true, failStep)
 		//but no error here
@@ -320,7 +320,7 @@ func TestParseATCResult(t *testing.T) {
 		</checkstyle>`
 		body := []byte(bodyString)
 		doFailOnSeverityLevel := "warning"
-		err, failStep := logAndPersistAndEvaluateATCResults(&mock.FilesMock{}, body, "ATCResults.xml", false, doFailOnSeverityLevel)
+		err, failStep := logAndPersistAndEvaluateATCResults(&mock.FilesMock{}, body, &abapEnvironmentRunATCCheckOptions{AtcResultsFileName: "ATCResults.xml", FailOnSeverity: doFailOnSeverityLevel})
 		//fail true
 		assert.Equal(t, true, failStep)
 		//but no error here
@@ -349,7 +349,7 @@ func TestParseATCResult(t *testing.T) {
 		</checkstyle>`
 		body := []byte(bodyString)
 		doFailOnSeverityLevel := "info"
-		err, failStep := logAndPersistAndEvaluateATCResults(&mock.FilesMock{}, body, "ATCResults.xml", false, doFailOnSeverityLevel)
+		err, failStep := logAndPersistAndEvaluateATCResults(&mock.FilesMock{}, body, &abapEnvironmentRunATCCheckOptions{AtcResultsFileName: "ATCResults.xml", FailOnSeverity: doFailOnSeverityLevel})
 		//fail true
 		assert.Equal(t, true, failStep)
 		//but no error here
@@ -378,7 +378,7 @@ func TestParseATCResult(t *testing.T) {
 		</checkstyle>`
 		body := []byte(bodyString)
 		doFailOnSeverityLevel := "warning"
-		err, failStep := logAndPersistAndEvaluateATCResults(&mock.FilesMock{}, body, "ATCResults.xml", false, doFailOnSeverityLevel)
+		err, failStep := logAndPersistAndEvaluateATCResults(&mock.FilesMock{}, body, &abapEnvironmentRunATCCheckOptions{AtcResultsFileName: "ATCResults.xml", FailOnSeverity: doFailOnSeverityLevel})
 		//fail true
 		assert.Equal(t, true, failStep)
 		//but no error here
@@ -407,7 +407,7 @@ func TestParseATCResult(t *testing.T) {
 		</checkstyle>`
 		body := []byte(bodyString)
 		doFailOnSeverityLevel := "info"
-		err, failStep := logAndPersistAndEvaluateATCResults(&mock.FilesMock{}, body, "ATCResults.xml", false, doFailOnSeverityLevel)
+		err, failStep := logAndPersistAndEvaluateATCResults(&mock.FilesMock{}, body, &abapEnvironmentRunATCCheckOptions{AtcResultsFileName: "ATCResults.xml", FailOnSeverity: doFailOnSeverityLevel})
 		//fail true
 		assert.Equal(t, true, failStep)
 		//but no error here
@@ -436,7 +436,7 @@ func TestParseATCResult(t *testing.T) {
 		</checkstyle>`
 		body := []byte(bodyString)
 		doFailOnSeverityLevel := "info"
-		err, failStep := logAndPersistAndEvaluateATCResults(&mock.FilesMock{}, body, "ATCResults.xml", false, doFailOnSeverityLevel)
+		err, failStep := logAndPersistAndEvaluateATCResults(&mock.FilesMock{}, body, &abapEnvironmentRunATCCheckOptions{AtcResultsFileName: "ATCResults.xml", FailOnSeverity: doFailOnSeverityLevel})
 		//fail true
 		assert.Equal(t, true, failStep)
 		//but no error here
@@ -465,7 +465,7 @@ func TestParseATCResult(t *testing.T) {
 		</checkstyle>`
 		body := []byte(bodyString)
 		doFailOnSeverityLevel := "warning"
-		err, failStep := logAndPersistAndEvaluateATCResults(&mock.FilesMock{}, body, "ATCResults.xml", false, doFailOnSeverityLevel)
+		err, failStep := logAndPersistAndEvaluateATCResults(&mock.FilesMock{}, body, &abapEnvironmentRunATCCheckOptions{AtcResultsFileName: "ATCResults.xml", FailOnSeverity: doFailOnSeverityLevel})
 		//fail false
 		assert.Equal(t, false, failStep)
 		//no error here
@@ -494,7 +494,7 @@ func TestParseATCResult(t *testing.T) {
 		</checkstyle>`
 		body := []byte(bodyString)
 		doFailOnSeverityLevel := "error"
-		err, failStep := logAndPersistAndEvaluateATCResults(&mock.FilesMock{}, body, "ATCResults.xml", false, doFailOnSeverityLevel)
+		err, failStep := logAndPersistAndEvaluateATCResults(&mock.FilesMock{}, body, &abapEnvironmentRunATCCheckOptions{AtcResultsFileName: "ATCResults.xml", FailOnSeverity: doFailOnSeverityLevel})
 		//fail false
 		assert.Equal(t, false, failStep)
 		//no error here
@@ -512,7 +512,7 @@ func TestParseATCResult(t *testing.T) {
 		<checkstyle>
 		</checkstyle>`
 		body := []byte(bodyString)
-		err, failStep := logAndPersistAndEvaluateATCResults(&mock.FilesMock{}, body, "ATCResults.xml", false, "")
+		err, failStep := logAndPersistAndEvaluateATCResults(&mock.FilesMock{}, body, &abapEnvironmentRunATCCheckOptions{AtcResultsFileName: "ATCResults.xml"})
 		assert.Equal(t, false, failStep)
 		assert.Equal(t, nil, err)
 	})
@@ -520,7 +520,7 @@ func TestParseATCResult(t *testing.T) {
 		var bodyString string
 		body := []byte(bodyString)
 
-		err, failStep := logAndPersistAndEvaluateATCResults(&mock.FilesMock{}, body, "ATCResults.xml", false, "")
+		err, failStep := logAndPersistAndEvaluateATCResults(&mock.FilesMock{}, body, &abapEnvironmentRunATCCheckOptions{AtcResultsFileName: "ATCResults.xml"})
 		assert.Equal(t, false, failStep)
 		assert.EqualError(t, err, "Parsing ATC result failed: Body is empty, can't parse empty body")
 	})
@@ -534,12 +534,92 @@ func TestParseATCResult(t *testing.T) {
 		}()
 		bodyString := `<html><head><title>HTMLTestResponse</title</head></html>`
 		body := []byte(bodyString)
-		err, failStep := logAndPersistAndEvaluateATCResults(&mock.FilesMock{}, body, "ATCResults.xml", false, "")
+		err, failStep := logAndPersistAndEvaluateATCResults(&mock.FilesMock{}, body, &abapEnvironmentRunATCCheckOptions{AtcResultsFileName: "ATCResults.xml"})
 		assert.Equal(t, false, failStep)
 		assert.EqualError(t, err, "The Software Component could not be checked. Please make sure the respective Software Component has been cloned successfully on the system")
 	})
 }
 
+func TestATCSarifAndBaseline(t *testing.T) {
+	bodyString := `<?xml version="1.0" encoding="UTF-8"?>
+		<checkstyle>
+			<file name="/sap/bc/adt/oo/classes/zcl_example/source/main">
+				<error message="Syntax error" source="Syntax Check" line="12" severity="error">
+				</error>
+				<error message="Unused variable" source="Extended Check" line="20" severity="warning">
+				</error>
+			</file>
+		</checkstyle>`
+	baselineString := `<?xml version="1.0" encoding="UTF-8"?>
+		<checkstyle>
+			<file name="/sap/bc/adt/oo/classes/zcl_example/source/main">
+				<error message="Syntax error" source="Syntax Check" line="10" severity="error">
+				</error>
+			</file>
+		</checkstyle>`
+
+	t.Run("sarif output", func(t *testing.T) {
+		dir := t.TempDir()
+		oldCWD, _ := os.Getwd()
+		_ = os.Chdir(dir)
+		defer func() {
+			_ = os.Chdir(oldCWD)
+		}()
+		utils := &mock.FilesMock{}
+		options := &abapEnvironmentRunATCCheckOptions{AtcResultsFileName: "ATCResults.xml", GenerateSarif: true, SarifSourceDirectory: "src"}
+
+		err, failStep := logAndPersistAndEvaluateATCResults(utils, []byte(bodyString), options)
+
+		assert.NoError(t, err)
+		assert.False(t, failStep)
+		sarif, err := utils.FileRead("ATCResults.sarif")
+		if assert.NoError(t, err) {
+			assert.Contains(t, string(sarif), `"uri": "src/zcl_example.clas.abap"`)
+			assert.Contains(t, string(sarif), `"level": "error"`)
+		}
+	})
+
+	t.Run("only new findings fail the step", func(t *testing.T) {
+		dir := t.TempDir()
+		oldCWD, _ := os.Getwd()
+		_ = os.Chdir(dir)
+		defer func() {
+			_ = os.Chdir(oldCWD)
+		}()
+		utils := &mock.FilesMock{}
+		utils.AddFile("baseline/ATCResults.xml", []byte(baselineString))
+
+		err, failStep := logAndPersistAndEvaluateATCResults(utils, []byte(bodyString), &abapEnvironmentRunATCCheckOptions{AtcResultsFileName: "ATCResults.xml", FailOnSeverity: "error", AtcBaselineFile: "baseline/ATCResults.xml"})
+		assert.NoError(t, err)
+		assert.False(t, failStep)
+
+		err, failStep = logAndPersistAndEvaluateATCResults(utils, []byte(bodyString), &abapEnvironmentRunATCCheckOptions{AtcResultsFileName: "ATCResults.xml", FailOnSeverity: "warning", AtcBaselineFile: "baseline/ATCResults.xml"})
+		assert.NoError(t, err)
+		assert.True(t, failStep)
+	})
+
+	t.Run("missing baseline", func(t *testing.T) {
+		dir := t.TempDir()
+		oldCWD, _ := os.Getwd()
+		_ = os.Chdir(dir)
+		defer func() {
+			_ = os.Chdir(oldCWD)
+		}()
+
+		err, failStep := logAndPersistAndEvaluateATCResults(&mock.FilesMock{}, []byte(bodyString), &abapEnvironmentRunATCCheckOptions{AtcResultsFileName: "ATCResults.xml", FailOnSeverity: "error", AtcBaselineFile: "baseline/ATCResults.xml"})
+		assert.NoError(t, err)
+		assert.True(t, failStep)
+	})
+
+	t.Run("invalid baseline", func(t *testing.T) {
+		utils := &mock.FilesMock{}
+		utils.AddFile("baseline/ATCResults.xml", []byte("<checkstyle>"))
+
+		err, _ := logAndPersistAndEvaluateATCResults(utils, []byte(bodyString), &abapEnvironmentRunATCCheckOptions{AtcResultsFileName: "ATCResults.xml", AtcBaselineFile: "baseline/ATCResults.xml"})
+		assert.ErrorContains(t, err, "failed to parse ATC baseline baseline/ATCResults.xml")
+	})
+}
+
 func TestBuildATCCheckBody(t *testing.T) {
 	t.Run("Test build body with no ATC Object set - no software component and package", func(t *testing.T) {
 		expectedObjectSet := "<obj:objectSet></obj:objectSet>"
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/SAP/jenkins-library/pkg/apim"
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/pkg/errors"
+)
+
+const apiManagementPlanReport = "apiManagementSync_plan.json"
+
+type apiManagementSyncUtils interface {
+	FileWrite(path string, content []byte, perm os.FileMode) error
+	WriteFile(path string, content []byte, perm os.FileMode) error
+}
+
+func apiManagementSync(config apiManagementSyncOptions, telemetryData *telemetry.CustomData) {
+	httpClient := &piperhttp.Client{}
+	fileUtils := &piperutils.Files{}
+
+	err := runApiManagementSync(&config, httpClient, fileUtils)
+	if err != nil {
+		log.Entry().WithError(err).Fatal("step execution failed")
+	}
+}
+
+func runApiManagementSync(config *apiManagementSyncOptions, httpClient piperhttp.Sender, utils apiManagementSyncUtils) error {
+	desired, err := apim.ReadDesiredState(config.SourceDirectory)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return errors.Wrap(err, "failed to read desired state")
+	}
+
+	apimData := apim.Bundle{APIServiceKey: config.APIServiceKey, Client: httpClient}
+	if err := apim.Utils.InitAPIM(&apimData); err != nil {
+		return err
+	}
+
+	plan, err := apimData.CreatePlan(desired, config.ManagedEntityPatterns)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorService)
+		return errors.Wrap(err, "failed to compare desired state with the tenant")
+	}
+	for _, change := range plan.Changes {
+		switch change.Action {
+		case apim.ActionNone:
+			log.Entry().Debugf("%v %v is in the desired state", change.Kind, change.Name)
+		case apim.ActionKeep:
+			log.Entry().Infof("%v %v is not part of the desired state and is kept, since it is not managed", change.Kind, change.Name)
+		default:
+			log.Entry().Infof("%v %v: %v", change.Kind, change.Name, change.Action)
+		}
+	}
+	log.Entry().Infof("Plan: %v", plan.Summary())
+
+	report, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to create plan report")
+	}
+	if err := utils.FileWrite(apiManagementPlanReport, report, 0666); err != nil {
+		return errors.Wrapf(err, "failed to write %v", apiManagementPlanReport)
+	}
+	if err := piperutils.PersistReportsAndLinks("apiManagementSync", "", utils, []piperutils.Path{{Name: "API Management Plan", Target: apiManagementPlanReport}}, nil); err != nil {
+		log.Entry().WithError(err).Warn("failed to persist reports")
+	}
+
+	if config.DryRun {
+		log.Entry().Info("Dry run, the plan is not applied")
+		return nil
+	}
+	if !plan.HasChanges() {
+		log.Entry().Info("Tenant is in the desired state")
+		return nil
+	}
+	if err := apimData.ApplyPlan(plan); err != nil {
+		log.SetErrorCategory(log.ErrorService)
+		return errors.Wrap(err, "failed to apply plan")
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+//go:build unit
+// +build unit
+
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/apim"
+	apimmocks "github.com/SAP/jenkins-library/pkg/apim/mocks"
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunApiManagementSync(t *testing.T) {
+	t.Parallel()
+
+	newState := func(t *testing.T) string {
+		dir := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "apiProviders"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "apiProviders", "backend.json"), []byte(`{"name": "Backend", "host": "new.example.com"}`), 0644))
+		return dir
+	}
+	newServer := func(t *testing.T) *apimmocks.Server {
+		server := apimmocks.NewServer()
+		t.Cleanup(server.Close)
+		server.AddEntity("APIProviders", map[string]interface{}{"name": "Backend", "host": "old.example.com"})
+		server.AddEntity("APIProviders", map[string]interface{}{"name": "Unmanaged", "host": "other.example.com"})
+		return server
+	}
+
+	t.Run("dry run", func(t *testing.T) {
+		t.Parallel()
+		server := newServer(t)
+		utils := &mock.FilesMock{}
+
+		err := runApiManagementSync(&apiManagementSyncOptions{APIServiceKey: server.ServiceKey(), SourceDirectory: newState(t), DryRun: true}, &piperhttp.Client{}, utils)
+
+		require.NoError(t, err)
+		backend, _ := server.Entity("APIProviders", "Backend")
+		assert.Equal(t, "old.example.com", backend["host"])
+		report, err := utils.FileRead(apiManagementPlanReport)
+		require.NoError(t, err)
+		var plan apim.Plan
+		require.NoError(t, json.Unmarshal(report, &plan))
+		assert.Equal(t, []apim.PlannedChange{
+			{Kind: apim.KindAPIProvider, Name: "Backend", Action: apim.ActionUpdate},
+			{Kind: apim.KindAPIProvider, Name: "Unmanaged", Action: apim.ActionKeep},
+		}, plan.Changes)
+	})
+
+	t.Run("apply", func(t *testing.T) {
+		t.Parallel()
+		server := newServer(t)
+
+		err := runApiManagementSync(&apiManagementSyncOptions{APIServiceKey: server.ServiceKey(), SourceDirectory: newState(t)}, &piperhttp.Client{}, &mock.FilesMock{})
+
+		require.NoError(t, err)
+		backend, _ := server.Entity("APIProviders", "Backend")
+		assert.Equal(t, "new.example.com", backend["host"])
+		_, ok := server.Entity("APIProviders", "Unmanaged")
+		assert.True(t, ok)
+	})
+
+	t.Run("invalid desired state", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "keyValueMaps"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "keyValueMaps", "map.json"), []byte(`no json`), 0644))
+
+		err := runApiManagementSync(&apiManagementSyncOptions{APIServiceKey: "{}", SourceDirectory: dir}, &piperhttp.Client{}, &mock.FilesMock{})
+
+		assert.ErrorContains(t, err, "failed to read desired state: invalid JSON content in")
+	})
+}
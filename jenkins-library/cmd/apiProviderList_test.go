@@ -26,7 +26,7 @@ func TestRunApiProviderList(t *testing.T) {
 		if assert.NoError(t, err) {
 			assert.EqualValues(t, seOut.custom.APIProviderList, "{\"some\": \"test\"}")
 			t.Run("check url", func(t *testing.T) {
-				assert.Equal(t, "/apiportal/api/1.0/Management.svc/APIProviders?orderby=value&$select=name&$top=2", httpClientMock.URL)
+				assert.Equal(t, "/apiportal/api/1.0/Management.svc/APIProviders?$orderby=value&$select=name&$top=2", httpClientMock.URL)
 			})
 			t.Run("check method", func(t *testing.T) {
 				assert.Equal(t, "GET", httpClientMock.Method)
@@ -42,7 +42,7 @@ func TestRunApiProviderList(t *testing.T) {
 		// test
 		err := getApiProviderList(&config, apim, &seOut)
 		// assert
-		assert.EqualError(t, err, "HTTP GET request to /apiportal/api/1.0/Management.svc/APIProviders?orderby=value&$select=name&$top=2 failed with error: : Bad Request")
+		assert.EqualError(t, err, "HTTP GET request to /apiportal/api/1.0/Management.svc/APIProviders?$orderby=value&$select=name&$top=2 failed with error: : Bad Request")
 	})
 }
 
@@ -26,7 +26,7 @@ func TestRunApiProxyList(t *testing.T) {
 		if assert.NoError(t, err) {
 			assert.EqualValues(t, seOut.custom.APIProxyList, "{\"some\": \"test\"}")
 			t.Run("check url", func(t *testing.T) {
-				assert.Equal(t, "/apiportal/api/1.0/Management.svc/APIProxies?filter=isCopy+eq+false&$orderby=name&$skip=1&$top=4", httpClientMock.URL)
+				assert.Equal(t, "/apiportal/api/1.0/Management.svc/APIProxies?$filter=isCopy+eq+false&$orderby=name&$skip=1&$top=4", httpClientMock.URL)
 			})
 			t.Run("check method", func(t *testing.T) {
 				assert.Equal(t, "GET", httpClientMock.Method)
@@ -42,7 +42,7 @@ func TestRunApiProxyList(t *testing.T) {
 		// test
 		err := getApiProxyList(&config, apim, &seOut)
 		// assert
-		assert.EqualError(t, err, "HTTP GET request to /apiportal/api/1.0/Management.svc/APIProxies?filter=isCopy+eq+false&$orderby=name&$skip=1&$top=4 failed with error: : Bad Request")
+		assert.EqualError(t, err, "HTTP GET request to /apiportal/api/1.0/Management.svc/APIProxies?$filter=isCopy+eq+false&$orderby=name&$skip=1&$top=4 failed with error: : Bad Request")
 	})
 }
 
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"strconv"
+
+	"github.com/SAP/jenkins-library/pkg/buildcache"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+)
+
+// restoreBuildCache restores the dependencies of the tool if a cache backend is configured.
+// The cache only speeds up the build, therefore its errors are logged but never fail the step.
+func restoreBuildCache(tool buildcache.Tool, backend, location, endpoint string, telemetryData *telemetry.CustomData) *buildcache.Cache {
+	if len(backend) == 0 {
+		return nil
+	}
+	cacheBackend, err := buildcache.NewBackend(buildcache.Options{
+		Backend:            backend,
+		Location:           location,
+		Endpoint:           endpoint,
+		GCPJsonKeyFilePath: GeneralConfig.GCPJsonKeyFilePath,
+	})
+	if err != nil {
+		log.Entry().WithError(err).Warn("dependency cache is disabled")
+		return nil
+	}
+	cache := buildcache.New(cacheBackend, tool, &piperutils.Files{})
+	if err := cache.Restore(); err != nil {
+		log.Entry().WithError(err).Warn("failed to restore the dependency cache")
+	}
+	reportBuildCache(cache, telemetryData)
+	return cache
+}
+
+// saveBuildCache saves the dependencies after a successful build, if they have not been restored from the cache
+func saveBuildCache(cache *buildcache.Cache, telemetryData *telemetry.CustomData) {
+	if cache == nil {
+		return
+	}
+	if err := cache.Save(); err != nil {
+		log.Entry().WithError(err).Warn("failed to save the dependency cache")
+	}
+	reportBuildCache(cache, telemetryData)
+}
+
+func reportBuildCache(cache *buildcache.Cache, telemetryData *telemetry.CustomData) {
+	if telemetryData == nil {
+		return
+	}
+	telemetryData.BuildCacheResult = cache.Result()
+	if cache.Size() > 0 {
+		telemetryData.BuildCacheSize = strconv.FormatInt(cache.Size(), 10)
+	}
+}
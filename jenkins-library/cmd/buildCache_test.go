@@ -0,0 +1,81 @@
+//go:build unit
+// +build unit
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/buildcache"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildCache(t *testing.T) {
+	dir := t.TempDir()
+	oldCWD, _ := os.Getwd()
+	require.NoError(t, os.Chdir(dir))
+	defer func() {
+		_ = os.Chdir(oldCWD)
+	}()
+	require.NoError(t, os.WriteFile("package-lock.json", []byte(`{"lockfileVersion": 3}`), 0o644))
+	location := filepath.Join(t.TempDir(), "cache")
+	tool := func(directory string) buildcache.Tool {
+		return buildcache.Tool{Name: "npm", LockFiles: []string{"package-lock.json"}, Directories: []string{directory}}
+	}
+
+	t.Run("save after cache miss", func(t *testing.T) {
+		dependencies := filepath.Join(t.TempDir(), ".npm")
+		require.NoError(t, os.MkdirAll(dependencies, 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dependencies, "entry"), []byte("cached"), 0o644))
+		telemetryData := &telemetry.CustomData{}
+
+		cache := restoreBuildCache(tool(dependencies), "local", location, "", telemetryData)
+		require.NotNil(t, cache)
+		assert.Equal(t, buildcache.ResultMiss, telemetryData.BuildCacheResult)
+		saveBuildCache(cache, telemetryData)
+
+		assert.FileExists(t, filepath.Join(location, cache.Key()+".tar.gz"))
+		assert.NotEmpty(t, telemetryData.BuildCacheSize)
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		dependencies := filepath.Join(t.TempDir(), ".npm")
+		telemetryData := &telemetry.CustomData{}
+
+		cache := restoreBuildCache(tool(dependencies), "local", location, "", telemetryData)
+		saveBuildCache(cache, telemetryData)
+
+		assert.Equal(t, buildcache.ResultHit, telemetryData.BuildCacheResult)
+		content, err := os.ReadFile(filepath.Join(dependencies, "entry"))
+		require.NoError(t, err)
+		assert.Equal(t, "cached", string(content))
+		entries, _ := os.ReadDir(location)
+		assert.Len(t, entries, 1)
+	})
+
+	t.Run("cache miss after changed lock file", func(t *testing.T) {
+		require.NoError(t, os.WriteFile("package-lock.json", []byte(`{"lockfileVersion": 3, "packages": {}}`), 0o644))
+		dependencies := filepath.Join(t.TempDir(), ".npm")
+		telemetryData := &telemetry.CustomData{}
+
+		cache := restoreBuildCache(tool(dependencies), "local", location, "", telemetryData)
+
+		assert.Equal(t, buildcache.ResultMiss, telemetryData.BuildCacheResult)
+		assert.Empty(t, telemetryData.BuildCacheSize)
+		assert.NoDirExists(t, dependencies)
+		saveBuildCache(cache, telemetryData)
+		assert.FileExists(t, filepath.Join(location, cache.Key()+".tar.gz"))
+		entries, _ := os.ReadDir(location)
+		assert.Len(t, entries, 2)
+	})
+
+	t.Run("cache is disabled", func(t *testing.T) {
+		assert.Nil(t, restoreBuildCache(tool(dir), "", "", "", &telemetry.CustomData{}))
+		assert.Nil(t, restoreBuildCache(tool(dir), "unknown", location, "", &telemetry.CustomData{}))
+		saveBuildCache(nil, &telemetry.CustomData{})
+	})
+}
@@ -0,0 +1,214 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/SAP/jenkins-library/pkg/buildcache"
+	"github.com/SAP/jenkins-library/pkg/buildsettings"
+	"github.com/SAP/jenkins-library/pkg/command"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+)
+
+const (
+	cargoNextestPackage   = "cargo-nextest"
+	cargoLlvmCovPackage   = "cargo-llvm-cov"
+	cargoCycloneDXPackage = "cargo-cyclonedx"
+	cargoTestOutput       = "TEST-cargo.xml"
+	cargoCoverageOutput   = "cobertura-coverage.xml"
+	cargoSbomFilename     = "bom-cargo"
+	// cargoNextestProfile is the nextest profile writing the JUnit report, it is defined in a configuration file written by the step
+	cargoNextestProfile = "piper"
+	// cargoRegistry is the name of the registry the packages are published to, it is configured via environment variables
+	cargoRegistry = "piper"
+)
+
+const cargoNextestConfig = `[profile.piper]
+fail-fast = false
+
+[profile.piper.junit]
+path = "junit.xml"
+`
+
+type cargoBuildUtils interface {
+	command.ExecRunner
+	piperutils.FileUtils
+
+	getDockerImageValue(stepName string) (string, error)
+}
+
+type cargoBuildUtilsBundle struct {
+	*command.Command
+	*piperutils.Files
+}
+
+func (c *cargoBuildUtilsBundle) getDockerImageValue(stepName string) (string, error) {
+	return GetDockerImageValue(stepName)
+}
+
+func newCargoBuildUtils() cargoBuildUtils {
+	utils := cargoBuildUtilsBundle{
+		Command: &command.Command{
+			StepName: "cargoBuild",
+		},
+		Files: &piperutils.Files{},
+	}
+	// Reroute command output to logging framework
+	utils.Stdout(log.Writer())
+	utils.Stderr(log.Writer())
+	return &utils
+}
+
+func cargoBuild(config cargoBuildOptions, telemetryData *telemetry.CustomData, commonPipelineEnvironment *cargoBuildCommonPipelineEnvironment) {
+	utils := newCargoBuildUtils()
+
+	cache := restoreBuildCache(buildcache.Cargo(), config.BuildCacheBackend, config.BuildCacheLocation, config.BuildCacheEndpoint, telemetryData)
+	err := runCargoBuild(&config, telemetryData, utils, commonPipelineEnvironment)
+	if err != nil {
+		log.Entry().WithError(err).Fatal("execution of cargo build failed")
+	}
+	saveBuildCache(cache, telemetryData)
+}
+
+func runCargoBuild(config *cargoBuildOptions, telemetryData *telemetry.CustomData, utils cargoBuildUtils, commonPipelineEnvironment *cargoBuildCommonPipelineEnvironment) error {
+	if err := installCargoTools(config, utils); err != nil {
+		return err
+	}
+
+	buildOptions := append([]string{"build"}, cargoPackageArgs(config.Packages)...)
+	buildOptions = append(buildOptions, config.BuildFlags...)
+	if err := utils.RunExecutable("cargo", buildOptions...); err != nil {
+		log.SetErrorCategory(log.ErrorBuild)
+		return fmt.Errorf("failed to run cargo build: %w", err)
+	}
+
+	if config.RunTests {
+		if err := runCargoTests(config, utils); err != nil {
+			return err
+		}
+	}
+
+	if config.CreateBOM {
+		if err := utils.RunExecutable("cargo", "cyclonedx", "--format", "xml", "--spec-version", "1.4", "--override-filename", cargoSbomFilename); err != nil {
+			return fmt.Errorf("BOM creation failed: %w", err)
+		}
+	}
+
+	log.Entry().Debugf("creating build settings information...")
+	dockerImage, err := utils.getDockerImageValue("cargoBuild")
+	if err != nil {
+		return err
+	}
+	buildConfig := buildsettings.BuildOptions{
+		CreateBOM:         config.CreateBOM,
+		Publish:           config.Publish,
+		BuildSettingsInfo: config.BuildSettingsInfo,
+		DockerImage:       dockerImage,
+	}
+	buildSettingsInfo, err := buildsettings.CreateBuildSettingsInfo(&buildConfig, "cargoBuild")
+	if err != nil {
+		log.Entry().Warnf("failed to create build settings info: %v", err)
+	}
+	commonPipelineEnvironment.custom.buildSettingsInfo = buildSettingsInfo
+
+	if config.Publish {
+		return publishCargoPackages(config, utils)
+	}
+	return nil
+}
+
+func installCargoTools(config *cargoBuildOptions, utils cargoBuildUtils) error {
+	tools := []string{}
+	if config.RunTests {
+		tools = append(tools, cargoNextestPackage)
+		if config.ReportCoverage {
+			tools = append(tools, cargoLlvmCovPackage)
+		}
+	}
+	if config.CreateBOM {
+		tools = append(tools, cargoCycloneDXPackage)
+	}
+	for _, tool := range tools {
+		if err := utils.RunExecutable("cargo", "install", "--locked", tool); err != nil {
+			return fmt.Errorf("failed to install pre-requisite %v: %w", tool, err)
+		}
+	}
+	if config.RunTests && config.ReportCoverage {
+		if err := utils.RunExecutable("rustup", "component", "add", "llvm-tools-preview"); err != nil {
+			return fmt.Errorf("failed to install pre-requisite llvm-tools-preview: %w", err)
+		}
+	}
+	return nil
+}
+
+// runCargoTests runs the tests with nextest, which writes the JUnit report into the target directory
+func runCargoTests(config *cargoBuildOptions, utils cargoBuildUtils) error {
+	targetDir := os.Getenv("CARGO_TARGET_DIR")
+	if len(targetDir) == 0 {
+		targetDir = "target"
+	}
+	configFile := filepath.Join(targetDir, "piper-nextest.toml")
+	if err := utils.MkdirAll(targetDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create directory %v: %w", targetDir, err)
+	}
+	if err := utils.FileWrite(configFile, []byte(cargoNextestConfig), 0o644); err != nil {
+		return fmt.Errorf("failed to write nextest configuration: %w", err)
+	}
+
+	testOptions := []string{"nextest", "run"}
+	if config.ReportCoverage {
+		testOptions = []string{"llvm-cov", "nextest", "--cobertura", "--output-path", cargoCoverageOutput}
+	}
+	testOptions = append(testOptions, "--config-file", configFile, "--profile", cargoNextestProfile)
+	testOptions = append(testOptions, cargoPackageArgs(config.Packages)...)
+	testOptions = append(testOptions, config.TestOptions...)
+	testErr := utils.RunExecutable("cargo", testOptions...)
+
+	junitReport := filepath.Join(targetDir, "nextest", cargoNextestProfile, "junit.xml")
+	if exists, _ := utils.FileExists(junitReport); exists {
+		if _, err := utils.Copy(junitReport, cargoTestOutput); err != nil {
+			return fmt.Errorf("failed to copy test results: %w", err)
+		}
+	} else {
+		log.Entry().Warnf("no test results found at %v", junitReport)
+	}
+
+	if testErr != nil {
+		log.SetErrorCategory(log.ErrorTest)
+		return fmt.Errorf("some tests failed: %w", testErr)
+	}
+	return nil
+}
+
+// publishCargoPackages publishes the packages to the registry configured via environment variables, see https://doc.rust-lang.org/cargo/reference/registries.html
+func publishCargoPackages(config *cargoBuildOptions, utils cargoBuildUtils) error {
+	utils.AppendEnv([]string{
+		fmt.Sprintf("CARGO_REGISTRIES_PIPER_INDEX=%v", config.TargetRepositoryURL),
+		fmt.Sprintf("CARGO_REGISTRIES_PIPER_TOKEN=%v", config.TargetRepositoryToken),
+	})
+	// the version has been updated by artifactPrepareVersion, thus the working tree is dirty
+	publishOptions := []string{"publish", "--registry", cargoRegistry, "--allow-dirty"}
+	if len(config.Packages) == 0 {
+		if err := utils.RunExecutable("cargo", publishOptions...); err != nil {
+			return fmt.Errorf("failed to publish: %w", err)
+		}
+		return nil
+	}
+	for _, pkg := range config.Packages {
+		if err := utils.RunExecutable("cargo", append(publishOptions, "--package", pkg)...); err != nil {
+			return fmt.Errorf("failed to publish package %v: %w", pkg, err)
+		}
+	}
+	return nil
+}
+
+func cargoPackageArgs(packages []string) []string {
+	args := []string{}
+	for _, pkg := range packages {
+		args = append(args, "--package", pkg)
+	}
+	return args
+}
@@ -0,0 +1,20 @@
+//go:build unit
+// +build unit
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCargoBuildCommand(t *testing.T) {
+	t.Parallel()
+
+	testCmd := CargoBuildCommand()
+
+	// only high level testing performed - details are tested in step generation procedure
+	assert.Equal(t, "cargoBuild", testCmd.Use, "command name incorrect")
+
+}
@@ -0,0 +1,135 @@
+//go:build unit
+// +build unit
+
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/stretchr/testify/assert"
+)
+
+type cargoBuildMockUtils struct {
+	*mock.ExecMockRunner
+	*mock.FilesMock
+}
+
+func (c *cargoBuildMockUtils) getDockerImageValue(stepName string) (string, error) {
+	return "rust:1", nil
+}
+
+func newCargoBuildTestsUtils() *cargoBuildMockUtils {
+	return &cargoBuildMockUtils{
+		ExecMockRunner: &mock.ExecMockRunner{},
+		FilesMock:      &mock.FilesMock{},
+	}
+}
+
+func TestRunCargoBuild(t *testing.T) {
+	t.Parallel()
+	junitReport := filepath.Join("target", "nextest", "piper", "junit.xml")
+
+	t.Run("success - build and tests with coverage", func(t *testing.T) {
+		t.Parallel()
+		config := cargoBuildOptions{BuildFlags: []string{"--release"}, RunTests: true, ReportCoverage: true}
+		utils := newCargoBuildTestsUtils()
+		utils.AddFile(junitReport, []byte("<testsuites/>"))
+		cpe := cargoBuildCommonPipelineEnvironment{}
+
+		err := runCargoBuild(&config, &telemetry.CustomData{}, utils, &cpe)
+
+		assert.NoError(t, err)
+		if assert.Len(t, utils.Calls, 5) {
+			assert.Equal(t, mock.ExecCall{Exec: "cargo", Params: []string{"install", "--locked", "cargo-nextest"}}, utils.Calls[0])
+			assert.Equal(t, mock.ExecCall{Exec: "cargo", Params: []string{"install", "--locked", "cargo-llvm-cov"}}, utils.Calls[1])
+			assert.Equal(t, mock.ExecCall{Exec: "rustup", Params: []string{"component", "add", "llvm-tools-preview"}}, utils.Calls[2])
+			assert.Equal(t, mock.ExecCall{Exec: "cargo", Params: []string{"build", "--release"}}, utils.Calls[3])
+			assert.Equal(t, mock.ExecCall{Exec: "cargo", Params: []string{"llvm-cov", "nextest", "--cobertura", "--output-path", "cobertura-coverage.xml", "--config-file", filepath.Join("target", "piper-nextest.toml"), "--profile", "piper"}}, utils.Calls[4])
+		}
+		assert.True(t, utils.HasWrittenFile(filepath.Join("target", "piper-nextest.toml")))
+		assert.True(t, utils.HasCopiedFile(junitReport, "TEST-cargo.xml"))
+		assert.Contains(t, cpe.custom.buildSettingsInfo, `"dockerImage":"rust:1"`)
+	})
+
+	t.Run("success - workspace packages without coverage", func(t *testing.T) {
+		t.Parallel()
+		config := cargoBuildOptions{Packages: []string{"server", "client"}, RunTests: true, TestOptions: []string{"--features", "integration"}}
+		utils := newCargoBuildTestsUtils()
+
+		err := runCargoBuild(&config, &telemetry.CustomData{}, utils, &cargoBuildCommonPipelineEnvironment{})
+
+		assert.NoError(t, err)
+		if assert.Len(t, utils.Calls, 3) {
+			assert.Equal(t, []string{"build", "--package", "server", "--package", "client"}, utils.Calls[1].Params)
+			assert.Equal(t, []string{"nextest", "run", "--config-file", filepath.Join("target", "piper-nextest.toml"), "--profile", "piper", "--package", "server", "--package", "client", "--features", "integration"}, utils.Calls[2].Params)
+		}
+	})
+
+	t.Run("success - BOM and publish", func(t *testing.T) {
+		t.Parallel()
+		config := cargoBuildOptions{CreateBOM: true, Publish: true, TargetRepositoryURL: "sparse+https://my.nexus.com/repository/cargo/", TargetRepositoryToken: "token"}
+		utils := newCargoBuildTestsUtils()
+
+		err := runCargoBuild(&config, &telemetry.CustomData{}, utils, &cargoBuildCommonPipelineEnvironment{})
+
+		assert.NoError(t, err)
+		if assert.Len(t, utils.Calls, 4) {
+			assert.Equal(t, []string{"install", "--locked", "cargo-cyclonedx"}, utils.Calls[0].Params)
+			assert.Equal(t, []string{"cyclonedx", "--format", "xml", "--spec-version", "1.4", "--override-filename", "bom-cargo"}, utils.Calls[2].Params)
+			assert.Equal(t, []string{"publish", "--registry", "piper", "--allow-dirty"}, utils.Calls[3].Params)
+		}
+		assert.Contains(t, utils.Env, "CARGO_REGISTRIES_PIPER_INDEX=sparse+https://my.nexus.com/repository/cargo/")
+		assert.Contains(t, utils.Env, "CARGO_REGISTRIES_PIPER_TOKEN=token")
+	})
+
+	t.Run("success - publish workspace packages", func(t *testing.T) {
+		t.Parallel()
+		config := cargoBuildOptions{Packages: []string{"server", "client"}, Publish: true, TargetRepositoryURL: "sparse+https://my.nexus.com/repository/cargo/"}
+		utils := newCargoBuildTestsUtils()
+
+		err := runCargoBuild(&config, &telemetry.CustomData{}, utils, &cargoBuildCommonPipelineEnvironment{})
+
+		assert.NoError(t, err)
+		if assert.Len(t, utils.Calls, 3) {
+			assert.Equal(t, []string{"publish", "--registry", "piper", "--allow-dirty", "--package", "server"}, utils.Calls[1].Params)
+			assert.Equal(t, []string{"publish", "--registry", "piper", "--allow-dirty", "--package", "client"}, utils.Calls[2].Params)
+		}
+	})
+
+	t.Run("failure - build fails", func(t *testing.T) {
+		t.Parallel()
+		utils := newCargoBuildTestsUtils()
+		utils.ShouldFailOnCommand = map[string]error{"cargo build": fmt.Errorf("compile error")}
+
+		err := runCargoBuild(&cargoBuildOptions{}, &telemetry.CustomData{}, utils, &cargoBuildCommonPipelineEnvironment{})
+
+		assert.EqualError(t, err, "failed to run cargo build: compile error")
+	})
+
+	t.Run("failure - tests fail, results are kept", func(t *testing.T) {
+		t.Parallel()
+		config := cargoBuildOptions{RunTests: true}
+		utils := newCargoBuildTestsUtils()
+		utils.AddFile(junitReport, []byte("<testsuites/>"))
+		utils.ShouldFailOnCommand = map[string]error{"cargo nextest run": fmt.Errorf("exit status 100")}
+
+		err := runCargoBuild(&config, &telemetry.CustomData{}, utils, &cargoBuildCommonPipelineEnvironment{})
+
+		assert.EqualError(t, err, "some tests failed: exit status 100")
+		assert.True(t, utils.HasCopiedFile(junitReport, "TEST-cargo.xml"))
+	})
+
+	t.Run("failure - tool installation fails", func(t *testing.T) {
+		t.Parallel()
+		utils := newCargoBuildTestsUtils()
+		utils.ShouldFailOnCommand = map[string]error{"cargo install --locked cargo-nextest": fmt.Errorf("network error")}
+
+		err := runCargoBuild(&cargoBuildOptions{RunTests: true}, &telemetry.CustomData{}, utils, &cargoBuildCommonPipelineEnvironment{})
+
+		assert.EqualError(t, err, "failed to install pre-requisite cargo-nextest: network error")
+	})
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/changeimpact"
+	"github.com/SAP/jenkins-library/pkg/git"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/orchestrator"
+)
+
+// changedFiles returns the files changed since the base reference, it is a variable to be replaced in tests
+var changedFiles = func(baseRef string) ([]string, error) {
+	repo, err := git.PlainOpen(".")
+	if err != nil {
+		return nil, err
+	}
+	return git.ChangedFiles(repo, baseRef, "HEAD")
+}
+
+// changedLines returns the lines changed since the base reference per file, it is a variable to be replaced in tests
+var changedLines = func(baseRef string) (map[string][]int, error) {
+	repo, err := git.PlainOpen(".")
+	if err != nil {
+		return nil, err
+	}
+	return git.ChangedLines(repo, baseRef, "HEAD")
+}
+
+// pullRequestBaseRef returns the given base reference or, if empty, the target branch of the pull request provided by
+// the orchestrator. The second return value is false if no base reference is given and no pull request is detected.
+func pullRequestBaseRef(baseRef string) (string, bool) {
+	if len(baseRef) > 0 {
+		return baseRef, true
+	}
+	provider, err := orchestrator.GetOrchestratorConfigProvider(nil)
+	if err != nil || !provider.IsPullRequest() {
+		return "", false
+	}
+	return provider.PullRequestConfig().Base, true
+}
+
+// affectedModules restricts the modules to the ones affected by the changes since the base reference, which defaults to
+// the target branch of the pull request. The second return value is false if the affected modules cannot be determined,
+// in that case all modules have to be processed. Failures are only logged since they never justify skipping modules.
+func affectedModules(modules []changeimpact.Module, baseRef string, globalPatterns []string) ([]changeimpact.Module, bool) {
+	baseRef, isPullRequest := pullRequestBaseRef(baseRef)
+	if !isPullRequest {
+		log.Entry().Info("no pull request detected, processing all modules")
+		return modules, false
+	}
+	if len(baseRef) == 0 {
+		log.Entry().Info("base reference of the pull request is unknown, processing all modules")
+		return modules, false
+	}
+	files, err := changedFiles(baseRef)
+	if err != nil {
+		log.Entry().WithError(err).Warn("failed to detect the changed files, processing all modules")
+		return modules, false
+	}
+	affected := changeimpact.Affected(modules, files, globalPatterns)
+	log.Entry().Infof("%v of %v modules affected by the changes since '%v': %v", len(affected), len(modules), baseRef, strings.Join(changeimpact.Names(affected), ", "))
+	return affected, true
+}
+
+// modulePaths returns the directories of the modules
+func modulePaths(modules []changeimpact.Module) []string {
+	paths := make([]string, 0, len(modules))
+	for _, module := range modules {
+		paths = append(paths, module.Path)
+	}
+	return paths
+}
@@ -0,0 +1,260 @@
+//go:build unit
+// +build unit
+
+package cmd
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mockChangedFiles(t *testing.T, files []string, err error) {
+	original := changedFiles
+	changedFiles = func(baseRef string) ([]string, error) {
+		assert.Equal(t, "main", baseRef)
+		return files, err
+	}
+	t.Cleanup(func() { changedFiles = original })
+}
+
+func TestAffectedPackageJSONFiles(t *testing.T) {
+	utils := &mock.FilesMock{}
+	utils.AddFile("package.json", []byte(`{"name": "root", "workspaces": ["packages/*"]}`))
+	utils.AddFile("packages/core/package.json", []byte(`{"name": "core"}`))
+	utils.AddFile("packages/ui/package.json", []byte(`{"name": "ui", "dependencies": {"core": "*"}}`))
+	utils.AddFile("packages/docs/package.json", []byte(`{"name": "docs"}`))
+	packageJSONFiles := []string{"package.json", "packages/core/package.json", "packages/docs/package.json", "packages/ui/package.json"}
+
+	t.Run("changed package and its dependents", func(t *testing.T) {
+		mockChangedFiles(t, []string{"packages/core/index.js"}, nil)
+
+		files, err := affectedPackageJSONFiles(utils, packageJSONFiles, "main")
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"packages/core/package.json", "packages/ui/package.json"}, files)
+	})
+
+	t.Run("changed lock file affects all packages", func(t *testing.T) {
+		mockChangedFiles(t, []string{"package-lock.json"}, nil)
+
+		files, err := affectedPackageJSONFiles(utils, packageJSONFiles, "main")
+
+		require.NoError(t, err)
+		assert.Equal(t, packageJSONFiles, files)
+	})
+
+	t.Run("failing change detection affects all packages", func(t *testing.T) {
+		mockChangedFiles(t, nil, fmt.Errorf("base not found"))
+
+		files, err := affectedPackageJSONFiles(utils, packageJSONFiles, "main")
+
+		require.NoError(t, err)
+		assert.Equal(t, packageJSONFiles, files)
+	})
+}
+
+func TestMavenBuildOnlyAffectedModules(t *testing.T) {
+	SetConfigOptions(ConfigCommandOptions{
+		OpenFile: config.OpenPiperFile,
+	})
+	addPoms := func(utils *mavenMockUtils) {
+		utils.AddFile("pom.xml", []byte(`<project><groupId>com.acme</groupId><artifactId>parent</artifactId>
+			<modules><module>core</module><module>app</module><module>tools</module></modules></project>`))
+		utils.AddFile("core/pom.xml", []byte(`<project><parent><groupId>com.acme</groupId><artifactId>parent</artifactId></parent><artifactId>core</artifactId></project>`))
+		utils.AddFile("app/pom.xml", []byte(`<project><parent><groupId>com.acme</groupId><artifactId>parent</artifactId></parent><artifactId>app</artifactId>
+			<dependencies><dependency><groupId>com.acme</groupId><artifactId>core</artifactId></dependency></dependencies></project>`))
+		utils.AddFile("tools/pom.xml", []byte(`<project><parent><groupId>com.acme</groupId><artifactId>parent</artifactId></parent><artifactId>tools</artifactId></project>`))
+	}
+	options := mavenBuildOptions{OnlyAffectedModules: true, ChangeDetectionBaseRef: "main"}
+
+	t.Run("build is restricted to the affected modules", func(t *testing.T) {
+		mockChangedFiles(t, []string{"core/src/main/java/Core.java"}, nil)
+		mockedUtils := newMavenMockUtils()
+		addPoms(&mockedUtils)
+
+		err := runMavenBuild(&options, nil, &mockedUtils, &cpe)
+
+		require.NoError(t, err)
+		if assert.Len(t, mockedUtils.Calls, 1) {
+			assert.Contains(t, mockedUtils.Calls[0].Params, "core,app")
+			assert.Contains(t, mockedUtils.Calls[0].Params, "--also-make")
+		}
+	})
+
+	t.Run("change of the parent builds all modules", func(t *testing.T) {
+		mockChangedFiles(t, []string{"pom.xml"}, nil)
+		mockedUtils := newMavenMockUtils()
+		addPoms(&mockedUtils)
+
+		err := runMavenBuild(&options, nil, &mockedUtils, &cpe)
+
+		require.NoError(t, err)
+		if assert.Len(t, mockedUtils.Calls, 1) {
+			assert.NotContains(t, mockedUtils.Calls[0].Params, "-pl")
+			assert.NotContains(t, mockedUtils.Calls[0].Params, "--also-make")
+		}
+	})
+}
+
+func TestGolangTestPackages(t *testing.T) {
+	utils := newGolangBuildTestsUtils()
+	utils.AddFile("go.mod", []byte("module github.com/acme/tool\n"))
+	utils.AddFile("main.go", []byte("package main\n\nimport \"github.com/acme/tool/pkg/api\"\n"))
+	utils.AddFile("pkg/api/api.go", []byte("package api\n"))
+	utils.AddFile("pkg/util/util.go", []byte("package util\n"))
+
+	t.Run("all packages without restriction", func(t *testing.T) {
+		packages, err := golangTestPackages(&golangBuildOptions{}, utils)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"./..."}, packages)
+	})
+
+	t.Run("affected packages", func(t *testing.T) {
+		mockChangedFiles(t, []string{"pkg/api/api.go"}, nil)
+
+		packages, err := golangTestPackages(&golangBuildOptions{OnlyAffectedModules: true, ChangeDetectionBaseRef: "main"}, utils)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"./.", "./pkg/api"}, packages)
+	})
+
+	t.Run("no affected package", func(t *testing.T) {
+		mockChangedFiles(t, []string{}, nil)
+
+		packages, err := golangTestPackages(&golangBuildOptions{OnlyAffectedModules: true, ChangeDetectionBaseRef: "main"}, utils)
+
+		require.NoError(t, err)
+		assert.Empty(t, packages)
+	})
+}
+
+const changeImpactTestMtaYaml = `
+ID: acme
+version: 1.0.0
+modules:
+  - name: srv
+    type: nodejs
+    path: srv
+    provides:
+      - name: srv-api
+  - name: db
+    type: hdb
+    path: db
+  - name: app
+    type: approuter.nodejs
+    path: app
+    requires:
+      - name: srv-api
+`
+
+func TestMtaBuildOnlyAffectedModules(t *testing.T) {
+	SetConfigOptions(ConfigCommandOptions{
+		OpenFile: config.OpenPiperFile,
+	})
+	options := mtaBuildOptions{Platform: "CF", MtarName: "acme.mtar", Source: "./", Target: "./", OnlyAffectedModules: true, ChangeDetectionBaseRef: "main"}
+
+	t.Run("build is restricted to the affected modules", func(t *testing.T) {
+		mockChangedFiles(t, []string{"srv/server.js"}, nil)
+		utilsMock := newMtaBuildTestUtilsBundle()
+		utilsMock.AddFile("mta.yaml", []byte(changeImpactTestMtaYaml))
+		cpe := mtaBuildCommonPipelineEnvironment{}
+
+		err := runMtaBuild(options, &cpe, utilsMock)
+
+		require.NoError(t, err)
+		if assert.Len(t, utilsMock.Calls, 1) {
+			assert.Equal(t, mock.ExecCall{Exec: "mbt", Params: []string{"module-build", "--modules=srv,app", "--with-all-dependencies", "--source", "./"}}, utilsMock.Calls[0])
+		}
+		assert.Empty(t, cpe.mtarFilePath)
+	})
+
+	t.Run("no affected module", func(t *testing.T) {
+		mockChangedFiles(t, []string{}, nil)
+		utilsMock := newMtaBuildTestUtilsBundle()
+		utilsMock.AddFile("mta.yaml", []byte(changeImpactTestMtaYaml))
+
+		err := runMtaBuild(options, &mtaBuildCommonPipelineEnvironment{}, utilsMock)
+
+		require.NoError(t, err)
+		assert.Empty(t, utilsMock.Calls)
+	})
+
+	t.Run("change of the mta.yaml builds the archive", func(t *testing.T) {
+		mockChangedFiles(t, []string{"mta.yaml"}, nil)
+		utilsMock := newMtaBuildTestUtilsBundle()
+		utilsMock.AddFile("mta.yaml", []byte(changeImpactTestMtaYaml))
+
+		err := runMtaBuild(options, &mtaBuildCommonPipelineEnvironment{}, utilsMock)
+
+		require.NoError(t, err)
+		if assert.Len(t, utilsMock.Calls, 1) {
+			assert.Equal(t, "build", utilsMock.Calls[0].Params[0])
+		}
+	})
+
+	t.Run("published archive is built completely", func(t *testing.T) {
+		utilsMock := newMtaBuildTestUtilsBundle()
+		utilsMock.AddFile("mta.yaml", []byte(changeImpactTestMtaYaml))
+		publishOptions := options
+		publishOptions.Publish = true
+
+		_ = runMtaBuild(publishOptions, &mtaBuildCommonPipelineEnvironment{}, utilsMock)
+
+		if assert.NotEmpty(t, utilsMock.Calls) {
+			assert.Equal(t, "build", utilsMock.Calls[0].Params[0])
+		}
+	})
+}
+
+func TestUnaffectedDetectDirectories(t *testing.T) {
+	t.Run("unaffected mta modules are excluded", func(t *testing.T) {
+		mockChangedFiles(t, []string{"db/schema.cds"}, nil)
+		utils := newDetectTestUtilsBundle(true)
+		utils.AddFile("mta.yaml", []byte(changeImpactTestMtaYaml))
+
+		directories, err := unaffectedDetectDirectories(detectExecuteScanOptions{BuildTool: "mta", OnlyAffectedModules: true, ChangeDetectionBaseRef: "main"}, utils)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"srv", "app"}, directories)
+	})
+
+	t.Run("parent of an affected maven module is scanned", func(t *testing.T) {
+		mockChangedFiles(t, []string{"tools/Tool.java"}, nil)
+		utils := newDetectTestUtilsBundle(true)
+		utils.AddFile("pom.xml", []byte(`<project><groupId>com.acme</groupId><artifactId>parent</artifactId>
+			<modules><module>core</module><module>tools</module></modules></project>`))
+		utils.AddFile("core/pom.xml", []byte(`<project><parent><groupId>com.acme</groupId><artifactId>parent</artifactId></parent><artifactId>core</artifactId></project>`))
+		utils.AddFile("tools/pom.xml", []byte(`<project><parent><groupId>com.acme</groupId><artifactId>parent</artifactId></parent><artifactId>tools</artifactId></project>`))
+
+		directories, err := unaffectedDetectDirectories(detectExecuteScanOptions{BuildTool: "maven", OnlyAffectedModules: true, ChangeDetectionBaseRef: "main"}, utils)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"core"}, directories)
+	})
+
+	t.Run("npm packages without build descriptor list", func(t *testing.T) {
+		mockChangedFiles(t, []string{"packages/ui/index.js"}, nil)
+		utils := newDetectTestUtilsBundle(true)
+		utils.AddFile("packages/core/package.json", []byte(`{"name": "core"}`))
+		utils.AddFile("packages/ui/package.json", []byte(`{"name": "ui", "dependencies": {"core": "*"}}`))
+		utils.AddFile("packages/ui/node_modules/core/package.json", []byte(`{"name": "core"}`))
+
+		directories, err := unaffectedDetectDirectories(detectExecuteScanOptions{BuildTool: "npm", OnlyAffectedModules: true, ChangeDetectionBaseRef: "main"}, utils)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"packages/core"}, directories)
+	})
+
+	t.Run("build tool without module detection", func(t *testing.T) {
+		directories, err := unaffectedDetectDirectories(detectExecuteScanOptions{BuildTool: "golang", OnlyAffectedModules: true}, newDetectTestUtilsBundle(true))
+
+		require.NoError(t, err)
+		assert.Empty(t, directories)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"regexp"
 	"slices"
@@ -13,6 +14,7 @@ import (
 
 	"github.com/SAP/jenkins-library/pkg/cloudfoundry"
 	"github.com/SAP/jenkins-library/pkg/command"
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
@@ -54,7 +56,7 @@ func cfLogout(c command.ExecRunner) error {
 	return cf.Logout()
 }
 
-func cloudFoundryDeploy(config cloudFoundryDeployOptions, telemetryData *telemetry.CustomData, influxData *cloudFoundryDeployInflux) {
+func cloudFoundryDeploy(config cloudFoundryDeployOptions, telemetryData *telemetry.CustomData, commonPipelineEnvironment *cloudFoundryDeployCommonPipelineEnvironment, influxData *cloudFoundryDeployInflux) {
 	// for command execution use Command
 	c := command.Command{}
 	// reroute command output to logging framework
@@ -66,13 +68,13 @@ func cloudFoundryDeploy(config cloudFoundryDeployOptions, telemetryData *telemet
 	// Example: step checkmarxExecuteScan.go
 
 	// error situations should stop execution through log.Entry().Fatal() call which leads to an os.Exit(1) in the end
-	err := runCloudFoundryDeploy(&config, telemetryData, influxData, &c)
+	err := runCloudFoundryDeploy(&config, telemetryData, commonPipelineEnvironment, influxData, &c)
 	if err != nil {
 		log.Entry().WithError(err).Fatalf("step execution failed: %s", err)
 	}
 }
 
-func runCloudFoundryDeploy(config *cloudFoundryDeployOptions, telemetryData *telemetry.CustomData, influxData *cloudFoundryDeployInflux, command command.ExecRunner) error {
+func runCloudFoundryDeploy(config *cloudFoundryDeployOptions, telemetryData *telemetry.CustomData, commonPipelineEnvironment *cloudFoundryDeployCommonPipelineEnvironment, influxData *cloudFoundryDeployInflux, command command.ExecRunner) error {
 
 	log.Entry().Infof("General parameters: deployTool='%s', deployType='%s', cfApiEndpoint='%s', cfOrg='%s', cfSpace='%s'",
 		config.DeployTool, config.DeployType, config.APIEndpoint, config.Org, config.Space)
@@ -90,6 +92,9 @@ func runCloudFoundryDeploy(config *cloudFoundryDeployOptions, telemetryData *tel
 	if config.DeployTool == "mtaDeployPlugin" {
 		deployTriggered = true
 		err = handleMTADeployment(config, command)
+	} else if config.DeployTool == "cf_native" && (config.DeployType == cloudfoundry.StrategyRolling || config.DeployType == cloudfoundry.StrategyCanary) {
+		deployTriggered = true
+		err = handleCFV3Deployment(config, commonPipelineEnvironment, &piperhttp.Client{})
 	} else if config.DeployTool == "cf_native" {
 		deployTriggered = true
 		err = handleCFNativeDeployment(config, command)
@@ -245,7 +250,7 @@ func handleCFNativeDeployment(config *cloudFoundryDeployOptions, command command
 			return errors.Wrapf(err, "Cannot prepare cf push native deployment. DeployType '%s'", config.DeployType)
 		}
 	} else {
-		return fmt.Errorf("Invalid deploy type received: '%s'. Supported value: standard", config.DeployType)
+		return fmt.Errorf("Invalid deploy type received: '%s'. Supported values: standard, rolling, canary", config.DeployType)
 	}
 
 	appName, err := getAppName(config)
@@ -280,6 +285,142 @@ func handleCFNativeDeployment(config *cloudFoundryDeployOptions, command command
 	return deployCfNative(myDeployConfig, config, additionalEnvironment, command)
 }
 
+// for simplify mocking of the in-process Cloud Foundry v3 client
+var _newCfV3Client = func(apiEndpoint string, client piperhttp.Sender) cfV3Deployer {
+	return cloudfoundry.NewV3Client(apiEndpoint, client)
+}
+
+type cfV3Deployer interface {
+	Login(username, password string) error
+	DeployV3(options cloudfoundry.V3DeploymentOptions) (cloudfoundry.DeploymentResult, error)
+}
+
+func handleCFV3Deployment(config *cloudFoundryDeployOptions, commonPipelineEnvironment *cloudFoundryDeployCommonPipelineEnvironment, httpClient piperhttp.Sender) error {
+
+	appName, err := getAppName(config)
+	if err != nil {
+		return err
+	}
+
+	manifestFile, err := getManifestFileName(config)
+	if err != nil {
+		return err
+	}
+
+	options := cloudfoundry.V3DeploymentOptions{
+		Org:         config.Org,
+		Space:       config.Space,
+		AppName:     appName,
+		Strategy:    config.DeployType,
+		AppPath:     ".",
+		DockerImage: config.DeployDockerImage,
+		DockerUser:  config.DockerUsername,
+		DockerPass:  config.DockerPassword,
+		Timeout:     time.Duration(config.DeploymentTimeout) * time.Second,
+	}
+
+	manifestExists, err := fileUtils.FileExists(manifestFile)
+	if err != nil {
+		return errors.Wrapf(err, "Cannot check if file '%s' exists", manifestFile)
+	}
+	if manifestExists {
+		options.Manifest, options.AppPath, err = prepareV3Manifest(config, manifestFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(config.HealthCheckEndpoint) > 0 {
+		options.VerifyHealth = func(result cloudfoundry.DeploymentResult) error {
+			return verifyCfAppHealth(httpClient, appName, result.Routes, config.HealthCheckEndpoint)
+		}
+	}
+
+	log.Entry().Infof("CF %s deployment via v3 API with:", config.DeployType)
+	log.Entry().Infof("cfAppName='%s'", appName)
+	log.Entry().Infof("cfManifest='%s' (exists: %v)", manifestFile, manifestExists)
+	log.Entry().Infof("cfdeployDockerImage: '%s'", config.DeployDockerImage)
+
+	cf := _newCfV3Client(config.APIEndpoint, httpClient)
+	if err := cf.Login(config.Username, config.Password); err != nil {
+		return errors.Wrap(err, "Failed to login to Cloud Foundry")
+	}
+
+	result, err := cf.DeployV3(options)
+	commonPipelineEnvironment.custom.cfAppGuid = result.AppGUID
+	commonPipelineEnvironment.custom.cfDropletGuid = result.DropletGUID
+	commonPipelineEnvironment.custom.cfDeploymentGuid = result.DeploymentGUID
+	commonPipelineEnvironment.custom.cfAppRoutes = result.Routes
+	if err != nil {
+		return errors.Wrapf(err, "%s deployment of app '%s' failed", config.DeployType, appName)
+	}
+	log.Entry().Infof("App '%s' deployed with droplet '%s', routes: %v", appName, result.DropletGUID, result.Routes)
+	return nil
+}
+
+// prepareV3Manifest resolves the manifest variables since - in contrast to cf push - the v3 API does not substitute them.
+// The application path is taken from the first application of the manifest.
+func prepareV3Manifest(config *cloudFoundryDeployOptions, manifestFile string) ([]byte, string, error) {
+	appPath := "."
+
+	replacements := map[string]interface{}{}
+	for _, v := range config.ManifestVariables {
+		kv := strings.SplitN(v, "=", 2)
+		if len(kv) != 2 {
+			return nil, "", fmt.Errorf("Invalid manifest variable '%s', expected format 'key=value'", v)
+		}
+		replacements[kv[0]] = kv[1]
+	}
+	varsFiles := []string{}
+	for _, f := range config.ManifestVariablesFiles {
+		exists, err := fileUtils.FileExists(f)
+		if err != nil {
+			return nil, "", errors.Wrapf(err, "Cannot check if file '%s' exists", f)
+		}
+		if !exists {
+			log.Entry().Warningf("We skip adding not-existing file '%s' as a vars-file", f)
+			continue
+		}
+		varsFiles = append(varsFiles, f)
+	}
+	if _, err := _replaceVariables(manifestFile, replacements, varsFiles); err != nil {
+		return nil, "", errors.Wrapf(err, "Cannot substitute variables in manifest '%s'", manifestFile)
+	}
+
+	manifest, err := _getManifest(manifestFile)
+	if err != nil {
+		return nil, "", err
+	}
+	if hasPath, _ := manifest.ApplicationHasProperty(0, "path"); hasPath {
+		if p, _ := manifest.GetApplicationProperty(0, "path"); p != nil {
+			appPath = fmt.Sprintf("%v", p)
+		}
+	}
+
+	content, err := fileUtils.FileRead(manifestFile)
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "Cannot read manifest '%s'", manifestFile)
+	}
+	return content, appPath, nil
+}
+
+func verifyCfAppHealth(httpClient piperhttp.Sender, appName string, routes []string, endpoint string) error {
+	if len(routes) == 0 {
+		return fmt.Errorf("No route mapped to app '%s', cannot verify health endpoint '%s'", appName, endpoint)
+	}
+	healthURL := "https://" + strings.TrimSuffix(routes[0], "/") + "/" + strings.TrimPrefix(endpoint, "/")
+	log.Entry().Infof("Verifying health of app '%s' via '%s'", appName, healthURL)
+	response, err := httpClient.SendRequest(http.MethodGet, healthURL, nil, nil, nil)
+	if err != nil {
+		return errors.Wrapf(err, "Health check '%s' failed", healthURL)
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("Health check '%s' returned status code %d", healthURL, response.StatusCode)
+	}
+	return nil
+}
+
 func deployCfNative(deployConfig deployConfig, config *cloudFoundryDeployOptions, additionalEnvironment []string, cmd command.ExecRunner) error {
 
 	deployStatement := []string{
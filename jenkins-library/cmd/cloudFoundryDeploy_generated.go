@@ -30,8 +30,10 @@ type cloudFoundryDeployOptions struct {
 	DeployTool               string                 `json:"deployTool,omitempty"`
 	BuildTool                string                 `json:"buildTool,omitempty"`
 	DeployType               string                 `json:"deployType,omitempty"`
+	DeploymentTimeout        int                    `json:"deploymentTimeout,omitempty"`
 	DockerPassword           string                 `json:"dockerPassword,omitempty"`
 	DockerUsername           string                 `json:"dockerUsername,omitempty"`
+	HealthCheckEndpoint      string                 `json:"healthCheckEndpoint,omitempty"`
 	KeepOldInstance          bool                   `json:"keepOldInstance,omitempty"`
 	LoginParameters          string                 `json:"loginParameters,omitempty"`
 	Manifest                 string                 `json:"manifest,omitempty"`
@@ -47,6 +49,40 @@ type cloudFoundryDeployOptions struct {
 	Username                 string                 `json:"username,omitempty"`
 }
 
+type cloudFoundryDeployCommonPipelineEnvironment struct {
+	custom struct {
+		cfAppGuid        string
+		cfDropletGuid    string
+		cfDeploymentGuid string
+		cfAppRoutes      []string
+	}
+}
+
+func (p *cloudFoundryDeployCommonPipelineEnvironment) persist(path, resourceName string) {
+	content := []struct {
+		category string
+		name     string
+		value    interface{}
+	}{
+		{category: "custom", name: "cfAppGuid", value: p.custom.cfAppGuid},
+		{category: "custom", name: "cfDropletGuid", value: p.custom.cfDropletGuid},
+		{category: "custom", name: "cfDeploymentGuid", value: p.custom.cfDeploymentGuid},
+		{category: "custom", name: "cfAppRoutes", value: p.custom.cfAppRoutes},
+	}
+
+	errCount := 0
+	for _, param := range content {
+		err := piperenv.SetResourceParameter(path, resourceName, filepath.Join(param.category, param.name), param.value)
+		if err != nil {
+			log.Entry().WithError(err).Error("Error persisting piper environment.")
+			errCount++
+		}
+	}
+	if errCount > 0 {
+		log.Entry().Error("failed to persist Piper environment")
+	}
+}
+
 type cloudFoundryDeployInflux struct {
 	deployment_data struct {
 		fields struct {
@@ -105,6 +141,7 @@ func CloudFoundryDeployCommand() *cobra.Command {
 	metadata := cloudFoundryDeployMetadata()
 	var stepConfig cloudFoundryDeployOptions
 	var startTime time.Time
+	var commonPipelineEnvironment cloudFoundryDeployCommonPipelineEnvironment
 	var influx cloudFoundryDeployInflux
 	var logCollector *log.CollectorHook
 	var splunkClient *splunk.Splunk
@@ -192,6 +229,7 @@ The step achieves this via following deploy tools
 			stepTelemetryData := telemetry.CustomData{}
 			stepTelemetryData.ErrorCode = "1"
 			handler := func() {
+				commonPipelineEnvironment.persist(GeneralConfig.EnvRootPath, "commonPipelineEnvironment")
 				influx.persist(GeneralConfig.EnvRootPath, "influx")
 				config.RemoveVaultSecretFiles()
 				stepTelemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
@@ -232,7 +270,7 @@ The step achieves this via following deploy tools
 			log.DeferExitHandler(handler)
 			defer handler()
 			telemetryClient.Initialize(STEP_NAME)
-			cloudFoundryDeploy(stepConfig, &stepTelemetryData, &influx)
+			cloudFoundryDeploy(stepConfig, &stepTelemetryData, &commonPipelineEnvironment, &influx)
 			stepTelemetryData.ErrorCode = "0"
 			log.Entry().Info("SUCCESS")
 		},
@@ -253,9 +291,11 @@ func addCloudFoundryDeployFlags(cmd *cobra.Command, stepConfig *cloudFoundryDepl
 	cmd.Flags().StringVar(&stepConfig.DeployDockerImage, "deployDockerImage", os.Getenv("PIPER_deployDockerImage"), "Docker image deployments are supported [via manifest file in general](https://docs.cloudfoundry.org/devguide/deploy-apps/manifest-attributes.html#docker). If no manifest is used, this parameter defines the image to be deployed. The specified name of the image is passed to the `--docker-image` parameter of the cf CLI and must adhere it's naming pattern (e.g. REPO/IMAGE:TAG). See [cf CLI documentation](https://docs.cloudfoundry.org/devguide/deploy-apps/push-docker.html)x`x` for details. Note: The used Docker registry must be visible for the targeted Cloud Foundry instance.")
 	cmd.Flags().StringVar(&stepConfig.DeployTool, "deployTool", os.Getenv("PIPER_deployTool"), "Defines the tool which should be used for deployment. Mandatory if `buildTool` is not found in pipeline environment")
 	cmd.Flags().StringVar(&stepConfig.BuildTool, "buildTool", os.Getenv("PIPER_buildTool"), "Defines the tool which is used for building the artifact. If provided, `deployTool` is automatically derived from it. For MTA projects, `deployTool` defaults to `mtaDeployPlugin`. For other projects `cf_native` will be used.")
-	cmd.Flags().StringVar(&stepConfig.DeployType, "deployType", `standard`, "Defines the type of deployment -`standard` or `blue-green` deployment. For mta build tool, possible values are `standard`, `blue-green` or `bg-deploy`. For cf native build tools, possible value is `standard`. To eliminate system downtime, an alternative is to pass '--strategy rolling' to the parameter `cfNativeDeployParameters`.")
+	cmd.Flags().StringVar(&stepConfig.DeployType, "deployType", `standard`, "Defines the type of deployment -`standard` or `blue-green` deployment. For mta build tool, possible values are `standard`, `blue-green` or `bg-deploy`. For cf native build tools, possible values are `standard`, `rolling` or `canary`. `rolling` and `canary` deploy via the Cloud Foundry v3 deployments API without using the cf CLI. A `rolling` or `canary` deployment failing while it is active is cancelled automatically. If the health verification fails after the deployment is finished, the previous droplet is rolled out again.")
+	cmd.Flags().IntVar(&stepConfig.DeploymentTimeout, "deploymentTimeout", 600, "Timeout in seconds for staging and rolling out the application with deployType `rolling` or `canary`.")
 	cmd.Flags().StringVar(&stepConfig.DockerPassword, "dockerPassword", os.Getenv("PIPER_dockerPassword"), "If the specified image in `deployDockerImage` is contained in a Docker registry, which requires authorization, this defines the password to be used.")
 	cmd.Flags().StringVar(&stepConfig.DockerUsername, "dockerUsername", os.Getenv("PIPER_dockerUsername"), "If the specified image in `deployDockerImage` is contained in a Docker registry, which requires authorization, this defines the username to be used.")
+	cmd.Flags().StringVar(&stepConfig.HealthCheckEndpoint, "healthCheckEndpoint", os.Getenv("PIPER_healthCheckEndpoint"), "Path of an HTTP endpoint (e.g. `/health`) which is requested on the first route of the application after the instances of a `rolling` or `canary` deployment are running. The deployment is cancelled unless the endpoint responds with a 2xx status code. For `canary` deployments the check runs while the deployment is paused after the canary instance has been started.")
 	cmd.Flags().BoolVar(&stepConfig.KeepOldInstance, "keepOldInstance", false, "If this option is set to true the old instance will remain stopped in the Cloud Foundry space.\"")
 	cmd.Flags().StringVar(&stepConfig.LoginParameters, "loginParameters", os.Getenv("PIPER_loginParameters"), "Addition command line options for cf login command. No escaping/quoting is performed. Not recommended for productive environments.")
 	cmd.Flags().StringVar(&stepConfig.Manifest, "manifest", os.Getenv("PIPER_manifest"), "Defines the manifest file name to be used for deployment to Cloud Foundry. Defaults to `manifest.yml`")
@@ -406,6 +446,15 @@ func cloudFoundryDeployMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     `standard`,
 					},
+					{
+						Name:        "deploymentTimeout",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS", "GENERAL"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     600,
+					},
 					{
 						Name: "dockerPassword",
 						ResourceRef: []config.ResourceReference{
@@ -436,6 +485,15 @@ func cloudFoundryDeployMetadata() config.StepData {
 						Aliases:   []config.Alias{},
 						Default:   os.Getenv("PIPER_dockerUsername"),
 					},
+					{
+						Name:        "healthCheckEndpoint",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS", "GENERAL"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_healthCheckEndpoint"),
+					},
 					{
 						Name:        "keepOldInstance",
 						ResourceRef: []config.ResourceReference{},
@@ -588,6 +646,16 @@ func cloudFoundryDeployMetadata() config.StepData {
 			},
 			Outputs: config.StepOutputs{
 				Resources: []config.StepResources{
+					{
+						Name: "commonPipelineEnvironment",
+						Type: "piperEnvironment",
+						Parameters: []map[string]interface{}{
+							{"name": "custom/cfAppGuid"},
+							{"name": "custom/cfDropletGuid"},
+							{"name": "custom/cfDeploymentGuid"},
+							{"name": "custom/cfAppRoutes", "type": "[]string"},
+						},
+					},
 					{
 						Name: "influx",
 						Type: "influx",
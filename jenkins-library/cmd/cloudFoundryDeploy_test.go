@@ -5,12 +5,16 @@ package cmd
 
 import (
 	"fmt"
+	"io"
+	"net/http"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/SAP/jenkins-library/pkg/cloudfoundry"
 	"github.com/SAP/jenkins-library/pkg/command"
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/mock"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/SAP/jenkins-library/pkg/yaml"
@@ -160,7 +164,7 @@ func TestCfDeployment(t *testing.T) {
 		defer cleanup()
 		config.AppName = "a_z"
 		s := mock.ExecMockRunner{}
-		err := runCloudFoundryDeploy(&config, nil, nil, &s)
+		err := runCloudFoundryDeploy(&config, nil, &cloudFoundryDeployCommonPipelineEnvironment{}, nil, &s)
 
 		assert.EqualError(t, err, "Your application name 'a_z' contains a '_' (underscore) which is not allowed, only letters, dashes and numbers can be used. Please change the name to fit this requirement(s). For more details please visit https://docs.cloudfoundry.org/devguide/deploy-apps/deploy-app.html#basic-settings.")
 	})
@@ -173,7 +177,7 @@ func TestCfDeployment(t *testing.T) {
 
 		config.DeployTool = "invalid"
 
-		err := runCloudFoundryDeploy(&config, nil, nil, &s)
+		err := runCloudFoundryDeploy(&config, nil, &cloudFoundryDeployCommonPipelineEnvironment{}, nil, &s)
 
 		if assert.NoError(t, err) {
 			noopCfAPICalls(t, s)
@@ -192,7 +196,7 @@ func TestCfDeployment(t *testing.T) {
 
 		s := mock.ExecMockRunner{}
 
-		err := runCloudFoundryDeploy(&config, nil, nil, &s)
+		err := runCloudFoundryDeploy(&config, nil, &cloudFoundryDeployCommonPipelineEnvironment{}, nil, &s)
 
 		if assert.NoError(t, err) {
 
@@ -237,7 +241,7 @@ func TestCfDeployment(t *testing.T) {
 
 		influxData := cloudFoundryDeployInflux{}
 
-		err := runCloudFoundryDeploy(&config, nil, &influxData, &s)
+		err := runCloudFoundryDeploy(&config, nil, &cloudFoundryDeployCommonPipelineEnvironment{}, &influxData, &s)
 
 		if assert.NoError(t, err) {
 
@@ -274,7 +278,7 @@ func TestCfDeployment(t *testing.T) {
 
 		s := mock.ExecMockRunner{}
 
-		err := runCloudFoundryDeploy(&config, nil, nil, &s)
+		err := runCloudFoundryDeploy(&config, nil, &cloudFoundryDeployCommonPipelineEnvironment{}, nil, &s)
 
 		if assert.NoError(t, err) {
 
@@ -312,7 +316,7 @@ func TestCfDeployment(t *testing.T) {
 
 		s := mock.ExecMockRunner{}
 
-		err := runCloudFoundryDeploy(&config, nil, nil, &s)
+		err := runCloudFoundryDeploy(&config, nil, &cloudFoundryDeployCommonPipelineEnvironment{}, nil, &s)
 
 		if assert.NoError(t, err) {
 			t.Run("check shell calls", func(t *testing.T) {
@@ -354,7 +358,7 @@ func TestCfDeployment(t *testing.T) {
 
 		s := mock.ExecMockRunner{}
 
-		err := runCloudFoundryDeploy(&config, nil, nil, &s)
+		err := runCloudFoundryDeploy(&config, nil, &cloudFoundryDeployCommonPipelineEnvironment{}, nil, &s)
 
 		if assert.NoError(t, err) {
 
@@ -391,7 +395,7 @@ func TestCfDeployment(t *testing.T) {
 
 		s := mock.ExecMockRunner{}
 
-		err := runCloudFoundryDeploy(&config, nil, nil, &s)
+		err := runCloudFoundryDeploy(&config, nil, &cloudFoundryDeployCommonPipelineEnvironment{}, nil, &s)
 
 		if assert.NoError(t, err) {
 
@@ -427,7 +431,7 @@ func TestCfDeployment(t *testing.T) {
 
 		s := mock.ExecMockRunner{}
 
-		err := runCloudFoundryDeploy(&config, nil, nil, &s)
+		err := runCloudFoundryDeploy(&config, nil, &cloudFoundryDeployCommonPipelineEnvironment{}, nil, &s)
 
 		if assert.EqualError(t, err, "Blue-green deployment type is deprecated for cf native builds."+
 			"Instead set parameter `cfNativeDeployParameters: '--strategy rolling'`. "+
@@ -457,9 +461,9 @@ func TestCfDeployment(t *testing.T) {
 
 		s := mock.ExecMockRunner{}
 
-		err := runCloudFoundryDeploy(&config, nil, nil, &s)
+		err := runCloudFoundryDeploy(&config, nil, &cloudFoundryDeployCommonPipelineEnvironment{}, nil, &s)
 
-		if assert.EqualError(t, err, "Invalid deploy type received: 'blue'. Supported value: standard") {
+		if assert.EqualError(t, err, "Invalid deploy type received: 'blue'. Supported values: standard, rolling, canary") {
 
 			t.Run("check shell calls", func(t *testing.T) {
 				noopCfAPICalls(t, s)
@@ -480,7 +484,7 @@ func TestCfDeployment(t *testing.T) {
 
 		s := mock.ExecMockRunner{}
 
-		err := runCloudFoundryDeploy(&config, nil, nil, &s)
+		err := runCloudFoundryDeploy(&config, nil, &cloudFoundryDeployCommonPipelineEnvironment{}, nil, &s)
 
 		if assert.EqualError(t, err, "appName from manifest 'test-manifest.yml' is empty") {
 
@@ -504,7 +508,7 @@ func TestCfDeployment(t *testing.T) {
 		s := mock.ExecMockRunner{}
 
 		s.ShouldFailOnCommand = map[string]error{"cf.*push.*": fmt.Errorf("cf deploy failed")}
-		err := runCloudFoundryDeploy(&config, nil, nil, &s)
+		err := runCloudFoundryDeploy(&config, nil, &cloudFoundryDeployCommonPipelineEnvironment{}, nil, &s)
 
 		if assert.EqualError(t, err, "cf deploy failed") {
 			t.Run("check shell calls", func(t *testing.T) {
@@ -541,7 +545,7 @@ func TestCfDeployment(t *testing.T) {
 
 		s := mock.ExecMockRunner{}
 
-		err := runCloudFoundryDeploy(&config, nil, nil, &s)
+		err := runCloudFoundryDeploy(&config, nil, &cloudFoundryDeployCommonPipelineEnvironment{}, nil, &s)
 
 		if assert.EqualError(t, err, "Unable to login") {
 			t.Run("check shell calls", func(t *testing.T) {
@@ -571,7 +575,7 @@ func TestCfDeployment(t *testing.T) {
 
 		s := mock.ExecMockRunner{}
 
-		err := runCloudFoundryDeploy(&config, nil, nil, &s)
+		err := runCloudFoundryDeploy(&config, nil, &cloudFoundryDeployCommonPipelineEnvironment{}, nil, &s)
 
 		if assert.NoError(t, err) {
 
@@ -617,7 +621,7 @@ func TestCfDeployment(t *testing.T) {
 
 		s := mock.ExecMockRunner{}
 
-		err := runCloudFoundryDeploy(&config, nil, nil, &s)
+		err := runCloudFoundryDeploy(&config, nil, &cloudFoundryDeployCommonPipelineEnvironment{}, nil, &s)
 
 		if assert.NoError(t, err) {
 
@@ -686,7 +690,7 @@ func TestCfDeployment(t *testing.T) {
 
 		s := mock.ExecMockRunner{}
 
-		err := runCloudFoundryDeploy(&config, nil, nil, &s)
+		err := runCloudFoundryDeploy(&config, nil, &cloudFoundryDeployCommonPipelineEnvironment{}, nil, &s)
 
 		if assert.NoError(t, err) {
 
@@ -760,7 +764,7 @@ func TestCfDeployment(t *testing.T) {
 			return []string{"--vars-file", "vars.yaml"}, nil
 		}
 
-		err := runCloudFoundryDeploy(&config, nil, nil, &s)
+		err := runCloudFoundryDeploy(&config, nil, &cloudFoundryDeployCommonPipelineEnvironment{}, nil, &s)
 
 		if assert.NoError(t, err) {
 
@@ -818,7 +822,7 @@ func TestCfDeployment(t *testing.T) {
 			// restor the expected working dir.
 			assert.NoError(t, filesMock.Chdir("/home/me"))
 			s := mock.ExecMockRunner{}
-			err := runCloudFoundryDeploy(&config, nil, nil, &s)
+			err := runCloudFoundryDeploy(&config, nil, &cloudFoundryDeployCommonPipelineEnvironment{}, nil, &s)
 
 			if assert.NoError(t, err) {
 
@@ -837,7 +841,7 @@ func TestCfDeployment(t *testing.T) {
 			defer func() { config.MtaPath = "" }()
 			config.MtaPath = "my.mtar"
 			s := mock.ExecMockRunner{}
-			err := runCloudFoundryDeploy(&config, nil, nil, &s)
+			err := runCloudFoundryDeploy(&config, nil, &cloudFoundryDeployCommonPipelineEnvironment{}, nil, &s)
 			assert.EqualError(t, err, "mtar file 'my.mtar' retrieved from configuration does not exist")
 		})
 
@@ -1108,3 +1112,135 @@ func TestEnvVarKeyModification(t *testing.T) {
 	envVarCompatibleKey := toEnvVarKey("Mta.EXtensionCredential~Credential_Id1Abc")
 	assert.Equal(t, "MTA_EXTENSION_CREDENTIAL_CREDENTIAL_ID1_ABC", envVarCompatibleKey)
 }
+
+type cfV3DeployerMock struct {
+	loginUser string
+	options   cloudfoundry.V3DeploymentOptions
+	result    cloudfoundry.DeploymentResult
+	err       error
+}
+
+func (m *cfV3DeployerMock) Login(username, password string) error {
+	m.loginUser = username
+	return nil
+}
+
+func (m *cfV3DeployerMock) DeployV3(options cloudfoundry.V3DeploymentOptions) (cloudfoundry.DeploymentResult, error) {
+	m.options = options
+	return m.result, m.err
+}
+
+func TestCfV3Deployment(t *testing.T) {
+	defer func() {
+		fileUtils = &piperutils.Files{}
+		_replaceVariables = yaml.Substitute
+		_getManifest = getManifest
+		_newCfV3Client = func(apiEndpoint string, client piperhttp.Sender) cfV3Deployer {
+			return cloudfoundry.NewV3Client(apiEndpoint, client)
+		}
+	}()
+
+	config := cloudFoundryDeployOptions{
+		Org:                    "myOrg",
+		Space:                  "mySpace",
+		Username:               "me",
+		Password:               "******",
+		APIEndpoint:            "https://examples.sap.com/cf",
+		DeployTool:             "cf_native",
+		DeployType:             "rolling",
+		DeploymentTimeout:      600,
+		Manifest:               "manifest.yml",
+		ManifestVariables:      []string{"appName=testAppName"},
+		ManifestVariablesFiles: []string{"vars.yml", "missing.yml"},
+	}
+
+	t.Run("rolling deployment with manifest", func(t *testing.T) {
+		filesMock := mock.FilesMock{}
+		filesMock.AddFile("manifest.yml", []byte("applications:\n- name: ((appName))\n  path: dist\n"))
+		filesMock.AddFile("vars.yml", []byte("instances: 2"))
+		fileUtils = &filesMock
+
+		var receivedReplacements map[string]interface{}
+		var receivedFiles []string
+		_replaceVariables = func(manifest string, replacements map[string]interface{}, replacementsFiles []string) (bool, error) {
+			receivedReplacements = replacements
+			receivedFiles = replacementsFiles
+			return true, nil
+		}
+		_getManifest = func(name string) (cloudfoundry.Manifest, error) {
+			return manifestMock{manifestFileName: name, apps: []map[string]interface{}{{"name": "testAppName", "path": "dist"}}}, nil
+		}
+		deployer := &cfV3DeployerMock{result: cloudfoundry.DeploymentResult{
+			AppGUID:        "app-guid",
+			DropletGUID:    "droplet-guid",
+			DeploymentGUID: "deployment-guid",
+			Routes:         []string{"testAppName.cfapps.example.com"},
+		}}
+		_newCfV3Client = func(apiEndpoint string, client piperhttp.Sender) cfV3Deployer { return deployer }
+
+		cpe := cloudFoundryDeployCommonPipelineEnvironment{}
+		err := runCloudFoundryDeploy(&config, nil, &cpe, nil, &mock.ExecMockRunner{})
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, "me", deployer.loginUser)
+			assert.Equal(t, "testAppName", deployer.options.AppName)
+			assert.Equal(t, "rolling", deployer.options.Strategy)
+			assert.Equal(t, "dist", deployer.options.AppPath)
+			assert.Equal(t, 10*time.Minute, deployer.options.Timeout)
+			assert.NotEmpty(t, deployer.options.Manifest)
+			assert.Nil(t, deployer.options.VerifyHealth)
+			assert.Equal(t, map[string]interface{}{"appName": "testAppName"}, receivedReplacements)
+			assert.Equal(t, []string{"vars.yml"}, receivedFiles)
+			assert.Equal(t, "app-guid", cpe.custom.cfAppGuid)
+			assert.Equal(t, "droplet-guid", cpe.custom.cfDropletGuid)
+			assert.Equal(t, "deployment-guid", cpe.custom.cfDeploymentGuid)
+			assert.Equal(t, []string{"testAppName.cfapps.example.com"}, cpe.custom.cfAppRoutes)
+		}
+	})
+
+	t.Run("canary deployment failure", func(t *testing.T) {
+		fileUtils = &mock.FilesMock{}
+		deployer := &cfV3DeployerMock{err: fmt.Errorf("canary verification failed")}
+		_newCfV3Client = func(apiEndpoint string, client piperhttp.Sender) cfV3Deployer { return deployer }
+
+		canaryConfig := config
+		canaryConfig.AppName = "testAppName"
+		canaryConfig.DeployType = "canary"
+		canaryConfig.HealthCheckEndpoint = "/health"
+		err := runCloudFoundryDeploy(&canaryConfig, nil, &cloudFoundryDeployCommonPipelineEnvironment{}, nil, &mock.ExecMockRunner{})
+
+		assert.EqualError(t, err, "canary deployment of app 'testAppName' failed: canary verification failed")
+		assert.Equal(t, ".", deployer.options.AppPath)
+		assert.Empty(t, deployer.options.Manifest)
+		assert.NotNil(t, deployer.options.VerifyHealth)
+	})
+}
+
+type cfHealthSenderMock struct {
+	statusCode int
+	url        string
+}
+
+func (m *cfHealthSenderMock) SendRequest(method, url string, body io.Reader, header http.Header, cookies []*http.Cookie) (*http.Response, error) {
+	m.url = url
+	return &http.Response{StatusCode: m.statusCode, Body: io.NopCloser(strings.NewReader(""))}, nil
+}
+
+func (m *cfHealthSenderMock) SetOptions(options piperhttp.ClientOptions) {}
+
+func TestVerifyCfAppHealth(t *testing.T) {
+	t.Run("healthy", func(t *testing.T) {
+		httpClient := &cfHealthSenderMock{statusCode: 200}
+		assert.NoError(t, verifyCfAppHealth(httpClient, "app", []string{"app.example.com"}, "/health"))
+		assert.Equal(t, "https://app.example.com/health", httpClient.url)
+	})
+	t.Run("unhealthy", func(t *testing.T) {
+		httpClient := &cfHealthSenderMock{statusCode: 503}
+		err := verifyCfAppHealth(httpClient, "app", []string{"app.example.com"}, "health")
+		assert.EqualError(t, err, "Health check 'https://app.example.com/health' returned status code 503")
+	})
+	t.Run("no routes", func(t *testing.T) {
+		err := verifyCfAppHealth(&cfHealthSenderMock{}, "app", nil, "/health")
+		assert.EqualError(t, err, "No route mapped to app 'app', cannot verify health endpoint '/health'")
+	})
+}
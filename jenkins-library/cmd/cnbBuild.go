@@ -7,6 +7,7 @@ import (
 	"path"
 	"path/filepath"
 	"slices"
+	"time"
 
 	"dario.cat/mergo"
 	"github.com/SAP/jenkins-library/pkg/buildpacks"
@@ -119,10 +120,37 @@ func cnbBuild(config cnbBuildOptions, telemetryData *telemetry.CustomData, commo
 
 	client := &piperhttp.Client{}
 
+	startedOn := time.Now()
 	err := callCnbBuild(&config, telemetryData, utils, commonPipelineEnvironment, client)
 	if err != nil {
 		log.Entry().WithError(err).Fatal("step execution failed")
 	}
+	if config.CreateProvenance {
+		if err := createCnbProvenance(&config, startedOn, commonPipelineEnvironment, utils); err != nil {
+			log.Entry().WithError(err).Fatal("failed to create provenance")
+		}
+	}
+}
+
+// createCnbProvenance creates the provenance of the built images and pushes it as referrer of the images
+func createCnbProvenance(config *cnbBuildOptions, startedOn time.Time, commonPipelineEnvironment *cnbBuildCommonPipelineEnvironment, utils provenanceUtils) error {
+	settings := provenanceSettings{
+		stepName:          "cnbBuild",
+		buildTool:         "cnb",
+		signingKey:        config.ProvenanceSigningKey,
+		dockerConfigJSON:  config.DockerConfigJSON,
+		buildSettingsInfo: commonPipelineEnvironment.custom.buildSettingsInfo,
+		externalParameters: map[string]interface{}{
+			"path":           config.Path,
+			"buildpacks":     config.Buildpacks,
+			"buildEnvVars":   config.BuildEnvVars,
+			"additionalTags": config.AdditionalTags,
+		},
+		startedOn:   startedOn,
+		bomPatterns: []string{"bom-*.xml"},
+	}
+	images := builtImages(commonPipelineEnvironment.container.registryURL, commonPipelineEnvironment.container.imageNameTags, commonPipelineEnvironment.container.imageDigests)
+	return createProvenance(settings, nil, images, utils)
 }
 
 func isBuilder(utils cnbutils.BuildUtils) error {
@@ -46,6 +46,8 @@ type cnbBuildOptions struct {
 	SyftDownloadURL           string                   `json:"syftDownloadUrl,omitempty"`
 	RunImage                  string                   `json:"runImage,omitempty"`
 	DefaultProcess            string                   `json:"defaultProcess,omitempty"`
+	CreateProvenance          bool                     `json:"createProvenance,omitempty"`
+	ProvenanceSigningKey      string                   `json:"provenanceSigningKey,omitempty"`
 }
 
 type cnbBuildCommonPipelineEnvironment struct {
@@ -101,6 +103,7 @@ func (p *cnbBuildReports) persist(stepConfig cnbBuildOptions, gcpJsonKeyFilePath
 	log.Entry().Info("Uploading reports to Google Cloud Storage...")
 	content := []gcs.ReportOutputParam{
 		{FilePattern: "**/bom-*.xml", ParamRef: "", StepResultType: "sbom"},
+		{FilePattern: "**/provenance-*.intoto.jsonl", ParamRef: "", StepResultType: "provenance"},
 	}
 
 	gcsClient, err := gcs.NewClient(gcpJsonKeyFilePath, "")
@@ -174,6 +177,7 @@ func CnbBuildCommand() *cobra.Command {
 			log.SetStepErrors(stepErrors)
 			log.RegisterSecret(stepConfig.DockerConfigJSON)
 			log.RegisterSecret(stepConfig.DockerConfigJSONCPE)
+			log.RegisterSecret(stepConfig.ProvenanceSigningKey)
 
 			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
 				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
@@ -284,6 +288,8 @@ func addCnbBuildFlags(cmd *cobra.Command, stepConfig *cnbBuildOptions) {
 	cmd.Flags().StringVar(&stepConfig.SyftDownloadURL, "syftDownloadUrl", `https://github.com/anchore/syft/releases/download/v1.22.0/syft_1.22.0_linux_amd64.tar.gz`, "Specifies the download url of the Syft Linux amd64 tar binary file. This can be found at https://github.com/anchore/syft/releases/.")
 	cmd.Flags().StringVar(&stepConfig.RunImage, "runImage", os.Getenv("PIPER_runImage"), "Base image from which application images are built. Will be defaulted to the image provided by the builder. See also https://buildpacks.io/docs/for-app-developers/concepts/base-images/.")
 	cmd.Flags().StringVar(&stepConfig.DefaultProcess, "defaultProcess", os.Getenv("PIPER_defaultProcess"), "Process that should be started by default. See https://buildpacks.io/docs/app-developer-guide/run-an-app/")
+	cmd.Flags().BoolVar(&stepConfig.CreateProvenance, "createProvenance", false, "Creates a [SLSA v1 provenance](https://slsa.dev/spec/v1.0/provenance) of the build outputs as in-toto statement in a DSSE envelope, which is published as step report `provenance-<stepName>.intoto.jsonl`. For the built images the provenance is additionally pushed to the registry as OCI artifact referring to the image.")
+	cmd.Flags().StringVar(&stepConfig.ProvenanceSigningKey, "provenanceSigningKey", os.Getenv("PIPER_provenanceSigningKey"), "Path to the PEM encoded ECDSA, Ed25519 or RSA private key the provenance is signed with. Without key the provenance is not signed.")
 
 	cmd.MarkFlagRequired("containerImageTag")
 	cmd.MarkFlagRequired("containerRegistryUrl")
@@ -301,6 +307,7 @@ func cnbBuildMetadata() config.StepData {
 			Inputs: config.StepInputs{
 				Secrets: []config.StepSecrets{
 					{Name: "dockerConfigJsonCredentialsId", Description: "Jenkins 'Secret file' credentials ID containing Docker config.json (with registry credential(s)) in the following format:\n\n```json\n{\n  \"auths\": {\n    \"$server\": {\n      \"auth\": \"base64($username + ':' + $password)\"\n    }\n  }\n}\n```\n\nExample:\n\n```json\n{\n  \"auths\": {\n    \"example.com\": {\n      \"auth\": \"dXNlcm5hbWU6cGFzc3dvcmQ=\"\n    }\n  }\n}\n```\n", Type: "jenkins"},
+					{Name: "provenanceSigningKeyCredentialsId", Description: "Jenkins 'Secret file' credentials ID containing the private key the provenance is signed with.", Type: "jenkins"},
 				},
 				Parameters: []config.StepParameters{
 					{
@@ -558,6 +565,35 @@ func cnbBuildMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     os.Getenv("PIPER_defaultProcess"),
 					},
+					{
+						Name:        "createProvenance",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "GENERAL", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name: "provenanceSigningKey",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "provenanceSigningKeyCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:    "provenanceSigningKeyVaultSecretName",
+								Type:    "vaultSecretFile",
+								Default: "provenance-signing-key",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_provenanceSigningKey"),
+					},
 				},
 			},
 			Containers: []config.Container{
@@ -583,6 +619,7 @@ func cnbBuildMetadata() config.StepData {
 						Type: "reports",
 						Parameters: []map[string]interface{}{
 							{"filePattern": "**/bom-*.xml", "type": "sbom"},
+							{"filePattern": "**/provenance-*.intoto.jsonl", "type": "provenance"},
 						},
 					},
 				},
@@ -6,17 +6,30 @@ import (
 	"os"
 
 	"github.com/SAP/jenkins-library/pkg/command"
+	"github.com/SAP/jenkins-library/pkg/containerstructure"
+	"github.com/SAP/jenkins-library/pkg/docker"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/SAP/jenkins-library/pkg/testreport"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/pkg/errors"
 )
 
+// structureTestsJUnitReport is the test report written by the native test driver
+const structureTestsJUnitReport = "TEST-container-structure-tests.xml"
+
+// loadStructureTestImage loads the image tested by the native test driver, it is a variable to be replaced in tests
+var loadStructureTestImage = containerstructure.LoadImage
+
 type containerExecuteStructureTestsUtils interface {
 	Stdout(out io.Writer)
 	Stderr(err io.Writer)
 	RunExecutable(e string, p ...string) error
 	Glob(pattern string) (matches []string, err error)
+	FileRead(path string) ([]byte, error)
+	FileWrite(path string, content []byte, perm os.FileMode) error
 }
 
 type containerExecuteStructureTestsUtilsBundle struct {
@@ -63,13 +76,16 @@ func runContainerExecuteStructureTests(config *containerExecuteStructureTestsOpt
 		log.SetErrorCategory(log.ErrorConfiguration)
 		return errors.New("config files mustn't be missing")
 	}
+	if config.TestDriver == "native" {
+		return runNativeStructureTests(config, configFiles, utils)
+	}
 	for _, config := range configFiles {
 		parameters = append(parameters, "--config", config)
 	}
 	if config.TestDriver != "" {
 		if config.TestDriver != "docker" && config.TestDriver != "tar" {
 			log.SetErrorCategory(log.ErrorConfiguration)
-			return fmt.Errorf("test driver %s is incorrect. Possible drivers: docker, tar", config.TestDriver)
+			return fmt.Errorf("test driver %s is incorrect. Possible drivers: docker, tar, native", config.TestDriver)
 		}
 		parameters = append(parameters, "--driver", config.TestDriver)
 	} else if os.Getenv("ON_K8S") == "true" {
@@ -91,3 +107,73 @@ func runContainerExecuteStructureTests(config *containerExecuteStructureTestsOpt
 
 	return nil
 }
+
+// runNativeStructureTests executes the tests without container runtime by reading the image with go-containerregistry
+func runNativeStructureTests(config *containerExecuteStructureTestsOptions, configFiles []string, utils containerExecuteStructureTestsUtils) error {
+	var dockerConfig []byte
+	if len(config.DockerConfigJSON) > 0 {
+		var err error
+		if dockerConfig, err = utils.FileRead(config.DockerConfigJSON); err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return errors.Wrapf(err, "failed to read %v", config.DockerConfigJSON)
+		}
+	}
+	keychain, err := docker.NewKeychain(dockerConfig)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return err
+	}
+	platform, err := v1.ParsePlatform(config.TestImagePlatform)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return errors.Wrapf(err, "invalid platform '%v'", config.TestImagePlatform)
+	}
+
+	tests := []testreport.TestCase{}
+	testConfigs := map[string]containerstructure.Config{}
+	for _, file := range configFiles {
+		content, err := utils.FileRead(file)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read test configuration %v", file)
+		}
+		testConfig, err := containerstructure.ParseConfig(content)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return errors.Wrapf(err, "invalid test configuration %v", file)
+		}
+		testConfigs[file] = testConfig
+	}
+
+	image, err := loadStructureTestImage(config.TestImage, *platform, remote.WithAuthFromKeychain(keychain))
+	if err != nil {
+		return err
+	}
+	for _, file := range configFiles {
+		fileTests, err := containerstructure.Run(image, testConfigs[file], file)
+		if err != nil {
+			return errors.Wrapf(err, "failed to execute the tests of %v", file)
+		}
+		tests = append(tests, fileTests...)
+	}
+
+	junit, err := testreport.WriteJUnit(tests)
+	if err != nil {
+		return errors.Wrap(err, "failed to create test report")
+	}
+	if err := utils.FileWrite(structureTestsJUnitReport, junit, 0o644); err != nil {
+		return errors.Wrapf(err, "failed to write test report %v", structureTestsJUnitReport)
+	}
+
+	summary := testreport.Summarize(tests)
+	log.Entry().Infof("%v container structure tests: %v passed, %v failed, %v skipped", summary.Total, summary.Passed, summary.Failed, summary.Skipped)
+	for _, test := range tests {
+		if test.Status == testreport.StatusFailed {
+			log.Entry().Errorf("%v '%v' of %v failed: %v", test.ClassName, test.Name, test.Suite, test.Message)
+		}
+	}
+	if summary.Failed > 0 {
+		log.SetErrorCategory(log.ErrorTest)
+		return fmt.Errorf("%v of %v container structure tests failed", summary.Failed, summary.Total)
+	}
+	return nil
+}
@@ -5,25 +5,65 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"reflect"
+	"strings"
 	"time"
 
 	"github.com/SAP/jenkins-library/pkg/config"
 	"github.com/SAP/jenkins-library/pkg/gcp"
+	"github.com/SAP/jenkins-library/pkg/gcs"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/splunk"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 	"github.com/SAP/jenkins-library/pkg/validation"
+	"github.com/bmatcuk/doublestar"
 	"github.com/spf13/cobra"
 )
 
 type containerExecuteStructureTestsOptions struct {
 	PullImage          bool   `json:"pullImage,omitempty"`
 	TestConfiguration  string `json:"testConfiguration,omitempty"`
-	TestDriver         string `json:"testDriver,omitempty"`
+	TestDriver         string `json:"testDriver,omitempty" validate:"possible-values=docker tar native"`
 	TestImage          string `json:"testImage,omitempty"`
+	TestImagePlatform  string `json:"testImagePlatform,omitempty"`
+	DockerConfigJSON   string `json:"dockerConfigJSON,omitempty"`
 	TestReportFilePath string `json:"testReportFilePath,omitempty"`
 }
 
+type containerExecuteStructureTestsReports struct {
+}
+
+func (p *containerExecuteStructureTestsReports) persist(stepConfig containerExecuteStructureTestsOptions, gcpJsonKeyFilePath string, gcsBucketId string, gcsFolderPath string, gcsSubFolder string) {
+	if gcsBucketId == "" {
+		log.Entry().Info("persisting reports to GCS is disabled, because gcsBucketId is empty")
+		return
+	}
+	log.Entry().Info("Uploading reports to Google Cloud Storage...")
+	content := []gcs.ReportOutputParam{
+		{FilePattern: "TEST-container-structure-tests.xml", ParamRef: "", StepResultType: "junit"},
+	}
+
+	gcsClient, err := gcs.NewClient(gcpJsonKeyFilePath, "")
+	if err != nil {
+		log.Entry().Errorf("creation of GCS client failed: %v", err)
+		return
+	}
+	defer gcsClient.Close()
+	structVal := reflect.ValueOf(&stepConfig).Elem()
+	inputParameters := map[string]string{}
+	for i := 0; i < structVal.NumField(); i++ {
+		field := structVal.Type().Field(i)
+		if field.Type.String() == "string" {
+			paramName := strings.Split(field.Tag.Get("json"), ",")
+			paramValue, _ := structVal.Field(i).Interface().(string)
+			inputParameters[paramName[0]] = paramValue
+		}
+	}
+	if err := gcs.PersistReportsToGCS(gcsClient, content, inputParameters, gcsFolderPath, gcsBucketId, gcsSubFolder, doublestar.Glob, os.Stat); err != nil {
+		log.Entry().Errorf("failed to persist reports: %v", err)
+	}
+}
+
 // ContainerExecuteStructureTestsCommand In this step [Container Structure Tests](https://github.com/GoogleContainerTools/container-structure-test) are executed.
 func ContainerExecuteStructureTestsCommand() *cobra.Command {
 	const STEP_NAME = "containerExecuteStructureTests"
@@ -31,6 +71,7 @@ func ContainerExecuteStructureTestsCommand() *cobra.Command {
 	metadata := containerExecuteStructureTestsMetadata()
 	var stepConfig containerExecuteStructureTestsOptions
 	var startTime time.Time
+	var reports containerExecuteStructureTestsReports
 	var logCollector *log.CollectorHook
 	var splunkClient *splunk.Splunk
 	telemetryClient := &telemetry.Telemetry{}
@@ -42,7 +83,13 @@ func ContainerExecuteStructureTestsCommand() *cobra.Command {
 - Command tests (only if a Docker Deamon is available)
 - File existence tests
 - File content tests
-- Metadata test`,
+- Metadata test
+
+With the test driver ` + "`" + `native` + "`" + ` the tests are executed by the step itself without the ` + "`" + `container-structure-test` + "`" + ` binary and without a Docker daemon.
+The image is loaded from a registry or from a tar file or OCI image layout, e.g. as written by ` + "`" + `containerSaveImage` + "`" + ` or ` + "`" + `kanikoExecute` + "`" + `.
+In addition to the tests of ` + "`" + `container-structure-test` + "`" + ` the native driver supports a ` + "`" + `sizeTest` + "`" + ` limiting the size of the image.
+Command and license tests are not supported by the native driver and reported as skipped, the step fails if all tests of a configuration are skipped.
+The results are written in JUnit format to ` + "`" + `TEST-container-structure-tests.xml` + "`" + `.`,
 		PreRunE: func(cmd *cobra.Command, _ []string) error {
 			startTime = time.Now()
 			log.SetStepName(STEP_NAME)
@@ -73,6 +120,7 @@ func ContainerExecuteStructureTestsCommand() *cobra.Command {
 				}
 			}
 			log.SetStepErrors(stepErrors)
+			log.RegisterSecret(stepConfig.DockerConfigJSON)
 
 			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
 				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
@@ -109,6 +157,7 @@ func ContainerExecuteStructureTestsCommand() *cobra.Command {
 			stepTelemetryData := telemetry.CustomData{}
 			stepTelemetryData.ErrorCode = "1"
 			handler := func() {
+				reports.persist(stepConfig, GeneralConfig.GCPJsonKeyFilePath, GeneralConfig.GCSBucketId, GeneralConfig.GCSFolderPath, GeneralConfig.GCSSubFolder)
 				config.RemoveVaultSecretFiles()
 				stepTelemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
 				stepTelemetryData.ErrorCategory = log.GetErrorCategory().String()
@@ -161,9 +210,11 @@ func ContainerExecuteStructureTestsCommand() *cobra.Command {
 func addContainerExecuteStructureTestsFlags(cmd *cobra.Command, stepConfig *containerExecuteStructureTestsOptions) {
 	cmd.Flags().BoolVar(&stepConfig.PullImage, "pullImage", false, "Force a pull of the tested image before running tests. Only relevant for testDriver 'docker'.")
 	cmd.Flags().StringVar(&stepConfig.TestConfiguration, "testConfiguration", os.Getenv("PIPER_testConfiguration"), "Container structure test configuration in yml or json format. You can pass a pattern in order to execute multiple tests.")
-	cmd.Flags().StringVar(&stepConfig.TestDriver, "testDriver", os.Getenv("PIPER_testDriver"), "Container structure test driver to be used for testing, please see https://github.com/GoogleContainerTools/container-structure-test for details.")
-	cmd.Flags().StringVar(&stepConfig.TestImage, "testImage", os.Getenv("PIPER_testImage"), "Image to be tested")
-	cmd.Flags().StringVar(&stepConfig.TestReportFilePath, "testReportFilePath", `cst-report.json`, "Path and name of the test report which will be generated")
+	cmd.Flags().StringVar(&stepConfig.TestDriver, "testDriver", os.Getenv("PIPER_testDriver"), "Container structure test driver to be used for testing, please see https://github.com/GoogleContainerTools/container-structure-test for details. The driver `native` executes the tests without a container runtime.")
+	cmd.Flags().StringVar(&stepConfig.TestImage, "testImage", os.Getenv("PIPER_testImage"), "Image to be tested. For testDriver 'native' this can also be the path of a tar file or of an OCI image layout directory.")
+	cmd.Flags().StringVar(&stepConfig.TestImagePlatform, "testImagePlatform", `linux/amd64`, "Platform of the image to be tested in case of a multi-arch image. Only relevant for testDriver 'native'.")
+	cmd.Flags().StringVar(&stepConfig.DockerConfigJSON, "dockerConfigJSON", os.Getenv("PIPER_dockerConfigJSON"), "Path to the file `.docker/config.json` containing the credentials of the registry - this is typically provided by your CI/CD system. Only relevant for testDriver 'native'.")
+	cmd.Flags().StringVar(&stepConfig.TestReportFilePath, "testReportFilePath", `cst-report.json`, "Path and name of the test report which will be generated. Not relevant for testDriver 'native' which writes a JUnit report.")
 
 	cmd.MarkFlagRequired("testConfiguration")
 	cmd.MarkFlagRequired("testImage")
@@ -179,6 +230,9 @@ func containerExecuteStructureTestsMetadata() config.StepData {
 		},
 		Spec: config.StepSpec{
 			Inputs: config.StepInputs{
+				Secrets: []config.StepSecrets{
+					{Name: "dockerConfigJsonCredentialsId", Description: "Jenkins 'Secret file' credentials ID containing Docker config.json (with registry credential(s)). Only relevant for testDriver 'native'.", Type: "jenkins"},
+				},
 				Parameters: []config.StepParameters{
 					{
 						Name:        "pullImage",
@@ -216,6 +270,40 @@ func containerExecuteStructureTestsMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     os.Getenv("PIPER_testImage"),
 					},
+					{
+						Name:        "testImagePlatform",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"STEPS", "STAGES", "PARAMETERS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `linux/amd64`,
+					},
+					{
+						Name: "dockerConfigJSON",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "custom/dockerConfigJSON",
+							},
+
+							{
+								Name: "dockerConfigJsonCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:    "dockerConfigFileVaultSecretName",
+								Type:    "vaultSecretFile",
+								Default: "docker-config",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_dockerConfigJSON"),
+					},
 					{
 						Name:        "testReportFilePath",
 						ResourceRef: []config.ResourceReference{},
@@ -230,6 +318,17 @@ func containerExecuteStructureTestsMetadata() config.StepData {
 			Containers: []config.Container{
 				{Image: "gcr.io/gcp-runtimes/container-structure-test:debug", Options: []config.Option{{Name: "-u", Value: "0"}, {Name: "--entrypoint", Value: ""}}},
 			},
+			Outputs: config.StepOutputs{
+				Resources: []config.StepResources{
+					{
+						Name: "reports",
+						Type: "reports",
+						Parameters: []map[string]interface{}{
+							{"filePattern": "TEST-container-structure-tests.xml", "type": "junit"},
+						},
+					},
+				},
+			},
 		},
 	}
 	return theMetaData
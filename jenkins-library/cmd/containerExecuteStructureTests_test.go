@@ -9,8 +9,13 @@ import (
 	"testing"
 
 	"github.com/SAP/jenkins-library/pkg/mock"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type containerStructureTestsMockUtils struct {
@@ -227,6 +232,76 @@ func TestRunContainerExecuteStructureTests(t *testing.T) {
 		// test
 		err := runContainerExecuteStructureTests(config, &mockUtils)
 		// assert
-		assert.EqualError(t, err, "test driver wrongDriver is incorrect. Possible drivers: docker, tar")
+		assert.EqualError(t, err, "test driver wrongDriver is incorrect. Possible drivers: docker, tar, native")
+	})
+}
+
+func mockStructureTestImage(t *testing.T, config v1.Config, err error) {
+	image, configErr := mutate.Config(empty.Image, config)
+	require.NoError(t, configErr)
+	original := loadStructureTestImage
+	loadStructureTestImage = func(reference string, platform v1.Platform, options ...remote.Option) (v1.Image, error) {
+		assert.Equal(t, "image.tar", reference)
+		assert.Equal(t, "arm64", platform.Architecture)
+		return image, err
+	}
+	t.Cleanup(func() { loadStructureTestImage = original })
+}
+
+func TestRunNativeStructureTests(t *testing.T) {
+	config := &containerExecuteStructureTestsOptions{
+		TestConfiguration: "**.yaml",
+		TestDriver:        "native",
+		TestImage:         "image.tar",
+		TestImagePlatform: "linux/arm64",
+	}
+
+	t.Run("success case", func(t *testing.T) {
+		mockStructureTestImage(t, v1.Config{User: "1000", WorkingDir: "/app"}, nil)
+		mockUtils := newContainerStructureTestsMockUtils()
+		mockUtils.AddFile("config1.yaml", []byte("schemaVersion: 2.0.0\nmetadataTest:\n  user: \"1000\"\n"))
+		mockUtils.AddFile("config2.yaml", []byte("schemaVersion: 2.0.0\nmetadataTest:\n  workdir: /app\ncommandTests:\n  - name: version\n"))
+
+		err := runContainerExecuteStructureTests(config, &mockUtils)
+
+		require.NoError(t, err)
+		assert.Empty(t, mockUtils.Calls)
+		report, err := mockUtils.FileRead(structureTestsJUnitReport)
+		require.NoError(t, err)
+		assert.Contains(t, string(report), `<testsuites tests="3" failures="0" errors="0" skipped="1"`)
+	})
+
+	t.Run("error case - failed tests", func(t *testing.T) {
+		mockStructureTestImage(t, v1.Config{User: "root"}, nil)
+		mockUtils := newContainerStructureTestsMockUtils()
+		mockUtils.AddFile("config1.yaml", []byte("metadataTest:\n  user: \"1000\"\n"))
+		mockUtils.AddFile("config2.yaml", []byte("fileExistenceTests:\n  - name: shell\n    path: /bin/sh\n    shouldExist: false\n"))
+
+		err := runContainerExecuteStructureTests(config, &mockUtils)
+
+		assert.EqualError(t, err, "1 of 2 container structure tests failed")
+		assert.True(t, mockUtils.HasWrittenFile(structureTestsJUnitReport))
+	})
+
+	t.Run("error case - invalid configuration", func(t *testing.T) {
+		mockStructureTestImage(t, v1.Config{}, nil)
+		mockUtils := newContainerStructureTestsMockUtils()
+		mockUtils.AddFile("config1.yaml", []byte("schemaVersion: 1.0.0"))
+		mockUtils.AddFile("config2.yaml", []byte(""))
+
+		err := runContainerExecuteStructureTests(config, &mockUtils)
+
+		assert.EqualError(t, err, "invalid test configuration config1.yaml: unsupported schema version '1.0.0' of test configuration, supported: 2.0.0")
+	})
+
+	t.Run("error case - image not available", func(t *testing.T) {
+		mockStructureTestImage(t, v1.Config{}, fmt.Errorf("failed to load image"))
+		mockUtils := newContainerStructureTestsMockUtils()
+		mockUtils.AddFile("config1.yaml", []byte(""))
+		mockUtils.AddFile("config2.yaml", []byte(""))
+
+		err := runContainerExecuteStructureTests(config, &mockUtils)
+
+		assert.EqualError(t, err, "failed to load image")
 	})
 }
@@ -14,6 +14,7 @@ import (
 	"time"
 
 	bd "github.com/SAP/jenkins-library/pkg/blackduck"
+	"github.com/SAP/jenkins-library/pkg/changeimpact"
 	"github.com/SAP/jenkins-library/pkg/command"
 	piperDocker "github.com/SAP/jenkins-library/pkg/docker"
 	piperGithub "github.com/SAP/jenkins-library/pkg/github"
@@ -486,6 +487,19 @@ func addDetectArgs(args []string, config detectExecuteScanOptions, utils detectU
 
 	}
 
+	// the project files of .NET repositories without solution file are located in sub directories, e.g. src/App/App.csproj
+	if config.BuildTool == "dotnet" && !checkIfArgumentIsInScanProperties(config, "detect.detector.search.depth") {
+		args = append(args, "--detect.detector.search.depth=3")
+	}
+
+	if config.OnlyAffectedModules {
+		unaffected, err := unaffectedDetectDirectories(config, utils)
+		if err != nil {
+			return nil, err
+		}
+		config.ExcludedDirectories = append(config.ExcludedDirectories, unaffected...)
+	}
+
 	// Handle excluded directories
 	handleExcludedDirectories(&args, &config)
 
@@ -1168,6 +1182,73 @@ func handleExcludedDirectories(args *[]string, config *detectExecuteScanOptions)
 	}
 }
 
+// unaffectedDetectDirectories returns the directories of the modules which are not affected by the changes and can be
+// excluded from the scan. Directories containing an affected module, e.g. of a Maven parent, remain part of the scan.
+func unaffectedDetectDirectories(config detectExecuteScanOptions, utils detectUtils) ([]string, error) {
+	var modules []changeimpact.Module
+	var globalFiles []string
+	var err error
+	switch config.BuildTool {
+	case "maven":
+		pomPath := config.PomPath
+		if len(pomPath) == 0 {
+			pomPath = "pom.xml"
+		}
+		modules, err = changeimpact.MavenModules(utils, pomPath)
+		globalFiles = mavenGlobalFiles
+	case "npm":
+		packageJSONFiles := config.BuildDescriptorList
+		if len(packageJSONFiles) == 0 {
+			matches, err := utils.Glob("**/package.json")
+			if err != nil {
+				return nil, err
+			}
+			for _, match := range matches {
+				if !strings.Contains(filepath.ToSlash(match), "node_modules/") {
+					packageJSONFiles = append(packageJSONFiles, match)
+				}
+			}
+		}
+		modules, err = changeimpact.NpmModules(utils, packageJSONFiles)
+		globalFiles = npmGlobalFiles
+	case "mta":
+		modules, err = changeimpact.MtaModules(utils, "mta.yaml")
+		globalFiles = mtaGlobalFiles
+	default:
+		log.Entry().Infof("modules of build tool '%v' cannot be detected, scanning all modules", config.BuildTool)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to detect the modules")
+	}
+
+	affected, ok := affectedModules(modules, config.ChangeDetectionBaseRef, globalFiles)
+	if !ok || len(affected) == len(modules) {
+		return nil, nil
+	}
+	affectedPaths := modulePaths(affected)
+	directories := []string{}
+	for _, directory := range modulePaths(modules) {
+		if !containsAnyModule(directory, affectedPaths) {
+			directories = append(directories, directory)
+		}
+	}
+	return directories, nil
+}
+
+// containsAnyModule returns true if one of the module directories is the directory or located below it
+func containsAnyModule(directory string, moduleDirectories []string) bool {
+	if directory == "." {
+		return true
+	}
+	for _, moduleDirectory := range moduleDirectories {
+		if moduleDirectory == directory || strings.HasPrefix(moduleDirectory, directory+"/") {
+			return true
+		}
+	}
+	return false
+}
+
 func excludeConfigDirectory(directories []string) []string {
 	configDirectory := configPath
 	for i := range directories {
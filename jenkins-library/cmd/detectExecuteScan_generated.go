@@ -79,6 +79,8 @@ type detectExecuteScanOptions struct {
 	UseDetect8                      bool     `json:"useDetect8,omitempty"`
 	UseDetect9                      bool     `json:"useDetect9,omitempty"`
 	ContainerScan                   bool     `json:"containerScan,omitempty"`
+	OnlyAffectedModules             bool     `json:"onlyAffectedModules,omitempty"`
+	ChangeDetectionBaseRef          string   `json:"changeDetectionBaseRef,omitempty"`
 }
 
 type detectExecuteScanInflux struct {
@@ -369,6 +371,8 @@ func addDetectExecuteScanFlags(cmd *cobra.Command, stepConfig *detectExecuteScan
 	cmd.Flags().BoolVar(&stepConfig.UseDetect8, "useDetect8", false, "DEPRECATED: This flag enables the use of the supported version 8 of the Detect script instead of default version 10")
 	cmd.Flags().BoolVar(&stepConfig.UseDetect9, "useDetect9", false, "This flag enables the use of the supported version 9 of the Detect script instead of default version 10")
 	cmd.Flags().BoolVar(&stepConfig.ContainerScan, "containerScan", false, "When set to true, Container Scanning will be used instead of Docker Inspector as the Detect tool for scanning images, and all other detect tools will be ignored in the scan")
+	cmd.Flags().BoolVar(&stepConfig.OnlyAffectedModules, "onlyAffectedModules", false, "Restricts the scan to the modules of a monorepo affected by the changes of a pull request, including the modules which depend on them. The directories of the other modules are added to the excluded directories. Modules are detected for the build tools `maven`, `npm` and `mta`. Since the results only cover the affected modules, use it for pull request scans only, e.g. together with `--detect.blackduck.scan.mode=RAPID`. Without pull request or base reference all modules are scanned.")
+	cmd.Flags().StringVar(&stepConfig.ChangeDetectionBaseRef, "changeDetectionBaseRef", os.Getenv("PIPER_changeDetectionBaseRef"), "Branch or commit the changes are detected against if `onlyAffectedModules` is active. Defaults to the target branch of the pull request provided by the orchestrator.")
 
 	cmd.MarkFlagRequired("token")
 	cmd.MarkFlagRequired("projectName")
@@ -985,6 +989,24 @@ func detectExecuteScanMetadata() config.StepData {
 						Aliases:     []config.Alias{{Name: "detect/containerScan"}},
 						Default:     false,
 					},
+					{
+						Name:        "onlyAffectedModules",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "GENERAL", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name:        "changeDetectionBaseRef",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "GENERAL", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_changeDetectionBaseRef"),
+					},
 				},
 			},
 			Containers: []config.Container{
@@ -433,6 +433,31 @@ func TestAddDetectArgs(t *testing.T) {
 				"--detect.source.path='.'",
 			},
 		},
+		{
+			args: []string{"--testProp1=1"},
+			options: detectExecuteScanOptions{
+				BuildTool:       "dotnet",
+				ServerURL:       "https://server.url",
+				Token:           "apiToken",
+				ProjectName:     "testName",
+				Version:         "1.0",
+				VersioningModel: "major-minor",
+				CodeLocation:    "testLocation",
+				Scanners:        []string{"source"},
+			},
+			expected: []string{
+				"--testProp1=1",
+				"--detect.detector.search.depth=3",
+				"--detect.excluded.directories=.pipeline/*",
+				"--blackduck.url=https://server.url",
+				"--blackduck.api.token=apiToken",
+				"\"--detect.project.name=testName\"",
+				"\"--detect.project.version.name=1.0\"",
+				"\"--detect.code.location.name=testLocation\"",
+				"\"--detect.force.success.on.skip=true\"",
+				"--detect.source.path='.'",
+			},
+		},
 		{
 			args: []string{"--testProp1=1"},
 			options: detectExecuteScanOptions{
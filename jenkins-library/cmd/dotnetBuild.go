@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/buildcache"
+	"github.com/SAP/jenkins-library/pkg/buildsettings"
+	"github.com/SAP/jenkins-library/pkg/command"
+	"github.com/SAP/jenkins-library/pkg/dotnet"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+)
+
+const (
+	dotnetCycloneDXPackage = "CycloneDX"
+	dotnetSbomFilename     = "bom-dotnet.xml"
+	dotnetTestResultsDir   = "TestResults"
+	dotnetTestOutputPrefix = "TEST-dotnet-"
+	// dotnetCoverageCollector is the data collector of coverlet writing coverage.cobertura.xml into the test results directory
+	dotnetCoverageCollector = "XPlat Code Coverage"
+)
+
+var (
+	// dotnetToolsPath is the directory the .NET tools required by the step are installed to
+	dotnetToolsPath = filepath.Join(".pipeline", "dotnet-tools")
+	// dotnetPackagesPath is the directory the NuGet packages are written to before they are pushed
+	dotnetPackagesPath = filepath.Join(".pipeline", "nupkgs")
+)
+
+type dotnetBuildUtils interface {
+	command.ExecRunner
+	piperutils.FileUtils
+
+	getDockerImageValue(stepName string) (string, error)
+}
+
+type dotnetBuildUtilsBundle struct {
+	*command.Command
+	*piperutils.Files
+}
+
+func (d *dotnetBuildUtilsBundle) getDockerImageValue(stepName string) (string, error) {
+	return GetDockerImageValue(stepName)
+}
+
+func newDotnetBuildUtils() dotnetBuildUtils {
+	utils := dotnetBuildUtilsBundle{
+		Command: &command.Command{
+			StepName: "dotnetBuild",
+		},
+		Files: &piperutils.Files{},
+	}
+	// Reroute command output to logging framework
+	utils.Stdout(log.Writer())
+	utils.Stderr(log.Writer())
+	return &utils
+}
+
+func dotnetBuild(config dotnetBuildOptions, telemetryData *telemetry.CustomData, commonPipelineEnvironment *dotnetBuildCommonPipelineEnvironment) {
+	utils := newDotnetBuildUtils()
+
+	cache := restoreBuildCache(buildcache.Nuget(), config.BuildCacheBackend, config.BuildCacheLocation, config.BuildCacheEndpoint, telemetryData)
+	err := runDotnetBuild(&config, telemetryData, utils, commonPipelineEnvironment)
+	if err != nil {
+		log.Entry().WithError(err).Fatal("execution of dotnet build failed")
+	}
+	saveBuildCache(cache, telemetryData)
+}
+
+func runDotnetBuild(config *dotnetBuildOptions, telemetryData *telemetry.CustomData, utils dotnetBuildUtils, commonPipelineEnvironment *dotnetBuildCommonPipelineEnvironment) error {
+	// the CLI resolves the solution or project file of the current directory if no project is given
+	projects := config.Projects
+	if len(projects) == 0 {
+		projects = []string{""}
+	}
+
+	for _, project := range projects {
+		if err := utils.RunExecutable("dotnet", dotnetArgs([]string{"restore"}, project)...); err != nil {
+			log.SetErrorCategory(log.ErrorBuild)
+			return fmt.Errorf("failed to restore dependencies: %w", err)
+		}
+		buildOptions := dotnetArgs([]string{"build"}, project, "--configuration", config.Configuration, "--no-restore")
+		if err := utils.RunExecutable("dotnet", append(buildOptions, config.BuildOptions...)...); err != nil {
+			log.SetErrorCategory(log.ErrorBuild)
+			return fmt.Errorf("failed to run dotnet build: %w", err)
+		}
+	}
+
+	if config.RunTests {
+		if err := runDotnetTests(config, projects, utils); err != nil {
+			return err
+		}
+	}
+
+	if config.CreateBOM {
+		if err := createDotnetBOM(projects, utils); err != nil {
+			return err
+		}
+	}
+
+	log.Entry().Debugf("creating build settings information...")
+	dockerImage, err := utils.getDockerImageValue("dotnetBuild")
+	if err != nil {
+		return err
+	}
+	buildConfig := buildsettings.BuildOptions{
+		CreateBOM:         config.CreateBOM,
+		Publish:           config.Publish,
+		BuildSettingsInfo: config.BuildSettingsInfo,
+		DockerImage:       dockerImage,
+	}
+	buildSettingsInfo, err := buildsettings.CreateBuildSettingsInfo(&buildConfig, "dotnetBuild")
+	if err != nil {
+		log.Entry().Warnf("failed to create build settings info: %v", err)
+	}
+	commonPipelineEnvironment.custom.buildSettingsInfo = buildSettingsInfo
+
+	if config.Publish {
+		return publishDotnetPackages(config, projects, utils)
+	}
+	return nil
+}
+
+// runDotnetTests runs the tests of all projects and converts the test results written by the trx logger into JUnit format
+func runDotnetTests(config *dotnetBuildOptions, projects []string, utils dotnetBuildUtils) error {
+	var testErr error
+	for _, project := range projects {
+		testOptions := dotnetArgs([]string{"test"}, project, "--configuration", config.Configuration, "--no-build", "--logger", "trx", "--results-directory", dotnetTestResultsDir)
+		if config.ReportCoverage {
+			testOptions = append(testOptions, "--collect", dotnetCoverageCollector)
+		}
+		// all projects are tested even if tests of a project fail
+		if err := utils.RunExecutable("dotnet", append(testOptions, config.TestOptions...)...); err != nil && testErr == nil {
+			testErr = err
+		}
+	}
+
+	results, err := utils.Glob(filepath.Join(dotnetTestResultsDir, "*.trx"))
+	if err != nil {
+		return fmt.Errorf("failed to search for test results: %w", err)
+	}
+	if len(results) == 0 {
+		log.Entry().Warnf("no test results found in %v", dotnetTestResultsDir)
+	}
+	for _, result := range results {
+		trx, err := utils.FileRead(result)
+		if err != nil {
+			return fmt.Errorf("failed to read test results %v: %w", result, err)
+		}
+		junit, err := dotnet.TrxToJUnit(trx)
+		if err != nil {
+			return fmt.Errorf("failed to convert test results %v: %w", result, err)
+		}
+		junitReport := dotnetTestOutputPrefix + strings.TrimSuffix(filepath.Base(result), filepath.Ext(result)) + ".xml"
+		if err := utils.FileWrite(junitReport, junit, 0o644); err != nil {
+			return fmt.Errorf("failed to write test results %v: %w", junitReport, err)
+		}
+	}
+
+	if testErr != nil {
+		log.SetErrorCategory(log.ErrorTest)
+		return fmt.Errorf("some tests failed: %w", testErr)
+	}
+	return nil
+}
+
+// createDotnetBOM creates the BOM of every project next to its project file
+func createDotnetBOM(projects []string, utils dotnetBuildUtils) error {
+	if err := utils.RunExecutable("dotnet", "tool", "update", dotnetCycloneDXPackage, "--tool-path", dotnetToolsPath); err != nil {
+		return fmt.Errorf("failed to install pre-requisite %v: %w", dotnetCycloneDXPackage, err)
+	}
+	cycloneDX := filepath.Join(dotnetToolsPath, "dotnet-CycloneDX")
+	for _, project := range projects {
+		path, outputDir := project, filepath.Dir(project)
+		if len(project) == 0 {
+			path = "."
+		}
+		if err := utils.RunExecutable(cycloneDX, path, "-o", outputDir, "-fn", dotnetSbomFilename); err != nil {
+			return fmt.Errorf("BOM creation failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// publishDotnetPackages packs the projects and pushes the packages to the NuGet feed
+func publishDotnetPackages(config *dotnetBuildOptions, projects []string, utils dotnetBuildUtils) error {
+	// packages of previous runs in the same workspace must not be pushed again
+	stalePackages, err := utils.Glob(filepath.Join(dotnetPackagesPath, "*.*nupkg"))
+	if err != nil {
+		return err
+	}
+	for _, stalePackage := range stalePackages {
+		if err := utils.FileRemove(stalePackage); err != nil {
+			return fmt.Errorf("failed to remove package %v of a previous run: %w", stalePackage, err)
+		}
+	}
+	for _, project := range projects {
+		packOptions := dotnetArgs([]string{"pack"}, project, "--configuration", config.Configuration, "--no-build", "--output", dotnetPackagesPath)
+		if err := utils.RunExecutable("dotnet", packOptions...); err != nil {
+			log.SetErrorCategory(log.ErrorBuild)
+			return fmt.Errorf("failed to pack: %w", err)
+		}
+	}
+	// versions which are already published, e.g. by a rerun of the pipeline, are skipped
+	pushOptions := []string{"nuget", "push", filepath.Join(dotnetPackagesPath, "*.nupkg"), "--source", config.TargetRepositoryURL, "--skip-duplicate"}
+	if len(config.TargetRepositoryAPIKey) > 0 {
+		pushOptions = append(pushOptions, "--api-key", config.TargetRepositoryAPIKey)
+	}
+	if err := utils.RunExecutable("dotnet", pushOptions...); err != nil {
+		return fmt.Errorf("failed to push packages to %v: %w", config.TargetRepositoryURL, err)
+	}
+	return nil
+}
+
+// dotnetArgs returns the arguments of a dotnet command, the project is omitted if empty
+func dotnetArgs(command []string, project string, options ...string) []string {
+	args := append([]string{}, command...)
+	if len(project) > 0 {
+		args = append(args, project)
+	}
+	return append(args, options...)
+}
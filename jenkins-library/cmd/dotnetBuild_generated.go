@@ -0,0 +1,449 @@
+// Code generated by piper's step-generator. DO NOT EDIT.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/gcp"
+	"github.com/SAP/jenkins-library/pkg/gcs"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperenv"
+	"github.com/SAP/jenkins-library/pkg/splunk"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/SAP/jenkins-library/pkg/validation"
+	"github.com/bmatcuk/doublestar"
+	"github.com/spf13/cobra"
+)
+
+type dotnetBuildOptions struct {
+	Projects               []string `json:"projects,omitempty"`
+	Configuration          string   `json:"configuration,omitempty"`
+	BuildOptions           []string `json:"buildOptions,omitempty"`
+	BuildSettingsInfo      string   `json:"buildSettingsInfo,omitempty"`
+	RunTests               bool     `json:"runTests,omitempty"`
+	TestOptions            []string `json:"testOptions,omitempty"`
+	ReportCoverage         bool     `json:"reportCoverage,omitempty"`
+	CreateBOM              bool     `json:"createBOM,omitempty"`
+	Publish                bool     `json:"publish,omitempty"`
+	TargetRepositoryURL    string   `json:"targetRepositoryURL,omitempty" validate:"required_if=Publish true"`
+	TargetRepositoryAPIKey string   `json:"targetRepositoryApiKey,omitempty"`
+	BuildCacheBackend      string   `json:"buildCacheBackend,omitempty" validate:"possible-values=local s3 gcs"`
+	BuildCacheLocation     string   `json:"buildCacheLocation,omitempty"`
+	BuildCacheEndpoint     string   `json:"buildCacheEndpoint,omitempty"`
+}
+
+type dotnetBuildCommonPipelineEnvironment struct {
+	custom struct {
+		buildSettingsInfo string
+	}
+}
+
+func (p *dotnetBuildCommonPipelineEnvironment) persist(path, resourceName string) {
+	content := []struct {
+		category string
+		name     string
+		value    interface{}
+	}{
+		{category: "custom", name: "buildSettingsInfo", value: p.custom.buildSettingsInfo},
+	}
+
+	errCount := 0
+	for _, param := range content {
+		err := piperenv.SetResourceParameter(path, resourceName, filepath.Join(param.category, param.name), param.value)
+		if err != nil {
+			log.Entry().WithError(err).Error("Error persisting piper environment.")
+			errCount++
+		}
+	}
+	if errCount > 0 {
+		log.Entry().Error("failed to persist Piper environment")
+	}
+}
+
+type dotnetBuildReports struct {
+}
+
+func (p *dotnetBuildReports) persist(stepConfig dotnetBuildOptions, gcpJsonKeyFilePath string, gcsBucketId string, gcsFolderPath string, gcsSubFolder string) {
+	if gcsBucketId == "" {
+		log.Entry().Info("persisting reports to GCS is disabled, because gcsBucketId is empty")
+		return
+	}
+	log.Entry().Info("Uploading reports to Google Cloud Storage...")
+	content := []gcs.ReportOutputParam{
+		{FilePattern: "**/bom-dotnet.xml", ParamRef: "", StepResultType: "sbom"},
+		{FilePattern: "**/TEST-*.xml", ParamRef: "", StepResultType: "junit"},
+		{FilePattern: "**/coverage.cobertura.xml", ParamRef: "", StepResultType: "cobertura-coverage"},
+	}
+
+	gcsClient, err := gcs.NewClient(gcpJsonKeyFilePath, "")
+	if err != nil {
+		log.Entry().Errorf("creation of GCS client failed: %v", err)
+		return
+	}
+	defer gcsClient.Close()
+	structVal := reflect.ValueOf(&stepConfig).Elem()
+	inputParameters := map[string]string{}
+	for i := 0; i < structVal.NumField(); i++ {
+		field := structVal.Type().Field(i)
+		if field.Type.String() == "string" {
+			paramName := strings.Split(field.Tag.Get("json"), ",")
+			paramValue, _ := structVal.Field(i).Interface().(string)
+			inputParameters[paramName[0]] = paramValue
+		}
+	}
+	if err := gcs.PersistReportsToGCS(gcsClient, content, inputParameters, gcsFolderPath, gcsBucketId, gcsSubFolder, doublestar.Glob, os.Stat); err != nil {
+		log.Entry().Errorf("failed to persist reports: %v", err)
+	}
+}
+
+// DotnetBuildCommand This step will execute a .NET build with the dotnet CLI.
+func DotnetBuildCommand() *cobra.Command {
+	const STEP_NAME = "dotnetBuild"
+
+	metadata := dotnetBuildMetadata()
+	var stepConfig dotnetBuildOptions
+	var startTime time.Time
+	var commonPipelineEnvironment dotnetBuildCommonPipelineEnvironment
+	var reports dotnetBuildReports
+	var logCollector *log.CollectorHook
+	var splunkClient *splunk.Splunk
+	telemetryClient := &telemetry.Telemetry{}
+
+	var createDotnetBuildCmd = &cobra.Command{
+		Use:   STEP_NAME,
+		Short: "This step will execute a .NET build with the dotnet CLI.",
+		Long: `This step will build a .NET project or solution with the [dotnet CLI](https://learn.microsoft.com/en-us/dotnet/core/tools/).
+The dependencies are restored and the projects are built with ` + "`" + `dotnet build` + "`" + `.
+The tests are executed with ` + "`" + `dotnet test` + "`" + `, the test results are converted from the Visual Studio format (TRX) into JUnit format
+and the test coverage is measured with [coverlet](https://github.com/coverlet-coverage/coverlet).
+
+The bill of materials (BOM) is created with [CycloneDX for .NET](https://github.com/CycloneDX/cyclonedx-dotnet).
+If the build is successful the projects can be packed and pushed to a NuGet feed.`,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			startTime = time.Now()
+			log.SetStepName(STEP_NAME)
+			log.SetVerbose(GeneralConfig.Verbose)
+
+			GeneralConfig.GitHubAccessTokens = ResolveAccessTokens(GeneralConfig.GitHubTokens)
+
+			path, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+			fatalHook := &log.FatalHook{CorrelationID: GeneralConfig.CorrelationID, Path: path}
+			log.RegisterHook(fatalHook)
+
+			err = PrepareConfig(cmd, &metadata, STEP_NAME, &stepConfig, config.OpenPiperFile)
+			if err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+
+			// Set step error patterns for improved error detection
+			stepErrors := make([]log.StepError, len(metadata.Metadata.Errors))
+			for i, err := range metadata.Metadata.Errors {
+				stepErrors[i] = log.StepError{
+					Pattern:  err.Pattern,
+					Message:  err.Message,
+					Category: err.Category,
+				}
+			}
+			log.SetStepErrors(stepErrors)
+			log.RegisterSecret(stepConfig.TargetRepositoryAPIKey)
+
+			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
+				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
+				log.RegisterHook(&sentryHook)
+			}
+
+			if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 || len(GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint) > 0 {
+				splunkClient = &splunk.Splunk{}
+				logCollector = &log.CollectorHook{CorrelationID: GeneralConfig.CorrelationID}
+				log.RegisterHook(logCollector)
+			}
+
+			if err = log.RegisterANSHookIfConfigured(GeneralConfig.CorrelationID); err != nil {
+				log.Entry().WithError(err).Warn("failed to set up SAP Alert Notification Service log hook")
+			}
+
+			validation, err := validation.New(validation.WithJSONNamesForStructFields(), validation.WithPredefinedErrorMessages())
+			if err != nil {
+				return err
+			}
+			if err = validation.ValidateStruct(stepConfig); err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+
+			return nil
+		},
+		Run: func(_ *cobra.Command, _ []string) {
+			vaultClient := config.GlobalVaultClient()
+			if vaultClient != nil {
+				defer vaultClient.MustRevokeToken()
+			}
+
+			stepTelemetryData := telemetry.CustomData{}
+			stepTelemetryData.ErrorCode = "1"
+			handler := func() {
+				commonPipelineEnvironment.persist(GeneralConfig.EnvRootPath, "commonPipelineEnvironment")
+				reports.persist(stepConfig, GeneralConfig.GCPJsonKeyFilePath, GeneralConfig.GCSBucketId, GeneralConfig.GCSFolderPath, GeneralConfig.GCSSubFolder)
+				config.RemoveVaultSecretFiles()
+				stepTelemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
+				stepTelemetryData.ErrorCategory = log.GetErrorCategory().String()
+				stepTelemetryData.PiperCommitHash = GitCommit
+				telemetryClient.SetData(&stepTelemetryData)
+				telemetryClient.LogStepTelemetryData()
+				if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+					splunkClient.Initialize(GeneralConfig.CorrelationID,
+						GeneralConfig.HookConfig.SplunkConfig.Dsn,
+						GeneralConfig.HookConfig.SplunkConfig.Token,
+						GeneralConfig.HookConfig.SplunkConfig.Index,
+						GeneralConfig.HookConfig.SplunkConfig.SendLogs)
+					splunkClient.Send(telemetryClient.GetData(), logCollector)
+				}
+				if len(GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint) > 0 {
+					splunkClient.Initialize(GeneralConfig.CorrelationID,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblToken,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblIndex,
+						GeneralConfig.HookConfig.SplunkConfig.SendLogs)
+					splunkClient.Send(telemetryClient.GetData(), logCollector)
+				}
+				if GeneralConfig.HookConfig.GCPPubSubConfig.Enabled {
+					err := gcp.NewGcpPubsubClient(
+						vaultClient,
+						GeneralConfig.HookConfig.GCPPubSubConfig.ProjectNumber,
+						GeneralConfig.HookConfig.GCPPubSubConfig.IdentityPool,
+						GeneralConfig.HookConfig.GCPPubSubConfig.IdentityProvider,
+						GeneralConfig.CorrelationID,
+						GeneralConfig.HookConfig.OIDCConfig.RoleID,
+					).Publish(GeneralConfig.HookConfig.GCPPubSubConfig.Topic, telemetryClient.GetDataBytes())
+					if err != nil {
+						log.Entry().WithError(err).Warn("event publish failed")
+					}
+				}
+			}
+			log.DeferExitHandler(handler)
+			defer handler()
+			telemetryClient.Initialize(STEP_NAME)
+			dotnetBuild(stepConfig, &stepTelemetryData, &commonPipelineEnvironment)
+			stepTelemetryData.ErrorCode = "0"
+			log.Entry().Info("SUCCESS")
+		},
+	}
+
+	addDotnetBuildFlags(createDotnetBuildCmd, &stepConfig)
+	return createDotnetBuildCmd
+}
+
+func addDotnetBuildFlags(cmd *cobra.Command, stepConfig *dotnetBuildOptions) {
+	cmd.Flags().StringSliceVar(&stepConfig.Projects, "projects", []string{}, "Solution or project files which are built, tested and packed. If not set, the dotnet CLI uses the solution or project file of the current directory.")
+	cmd.Flags().StringVar(&stepConfig.Configuration, "configuration", `Release`, "Build configuration passed as `--configuration` to the dotnet CLI.")
+	cmd.Flags().StringSliceVar(&stepConfig.BuildOptions, "buildOptions", []string{}, "Defines list of options passed to `dotnet build`, e.g. `--runtime` or MSBuild properties like `-p:TreatWarningsAsErrors=true`.")
+	cmd.Flags().StringVar(&stepConfig.BuildSettingsInfo, "buildSettingsInfo", os.Getenv("PIPER_buildSettingsInfo"), "build settings info is typically filled by the step automatically to create information about the build settings that were used during the dotnet build. This information is typically used for compliance related processes.")
+	cmd.Flags().BoolVar(&stepConfig.RunTests, "runTests", true, "Activates execution of the tests using `dotnet test`, the results are written to `TEST-dotnet-*.xml`.")
+	cmd.Flags().StringSliceVar(&stepConfig.TestOptions, "testOptions", []string{}, "Options passed to `dotnet test`, e.g. `--filter` or `--settings`.")
+	cmd.Flags().BoolVar(&stepConfig.ReportCoverage, "reportCoverage", true, "Defines if a coverage report in Cobertura format should be created, using the data collector `XPlat Code Coverage` of [coverlet](https://github.com/coverlet-coverage/coverlet) which needs to be referenced by the test projects.")
+	cmd.Flags().BoolVar(&stepConfig.CreateBOM, "createBOM", false, "Creates the bill of materials (BOM) `bom-dotnet.xml` using [CycloneDX for .NET](https://github.com/CycloneDX/cyclonedx-dotnet).")
+	cmd.Flags().BoolVar(&stepConfig.Publish, "publish", false, "Configures the build to pack the projects and to push the packages to the NuGet feed `targetRepositoryURL`. Package versions which already exist in the feed are skipped.")
+	cmd.Flags().StringVar(&stepConfig.TargetRepositoryURL, "targetRepositoryURL", os.Getenv("PIPER_targetRepositoryURL"), "URL of the NuGet feed the packages are pushed to, e.g. `https://my.nexus.com/repository/nuget-hosted/index.json`.")
+	cmd.Flags().StringVar(&stepConfig.TargetRepositoryAPIKey, "targetRepositoryApiKey", os.Getenv("PIPER_targetRepositoryApiKey"), "API key for pushing to the NuGet feed.")
+	cmd.Flags().StringVar(&stepConfig.BuildCacheBackend, "buildCacheBackend", os.Getenv("PIPER_buildCacheBackend"), "Backend of the dependency cache. If set, the dependencies of the build tool are restored before the build from an archive whose key is computed from the lock files of the project and saved after a successful build. Caching is disabled by default.")
+	cmd.Flags().StringVar(&stepConfig.BuildCacheLocation, "buildCacheLocation", os.Getenv("PIPER_buildCacheLocation"), "Location of the dependency cache: a directory for the `local` backend, or a bucket optionally followed by a path prefix for the `s3` and `gcs` backends, e.g. `my-bucket/piper-cache`. The `gcs` backend uses the key file of `gcpJsonKeyFilePath`, the `s3` backend uses the AWS credentials of the environment.")
+	cmd.Flags().StringVar(&stepConfig.BuildCacheEndpoint, "buildCacheEndpoint", os.Getenv("PIPER_buildCacheEndpoint"), "Endpoint of an S3-compatible object store used by the `s3` backend instead of AWS S3, e.g. `https://minio.example.org`.")
+
+}
+
+// retrieve step metadata
+func dotnetBuildMetadata() config.StepData {
+	var theMetaData = config.StepData{
+		Metadata: config.StepMetadata{
+			Name:        "dotnetBuild",
+			Aliases:     []config.Alias{},
+			Description: "This step will execute a .NET build with the dotnet CLI.",
+		},
+		Spec: config.StepSpec{
+			Inputs: config.StepInputs{
+				Secrets: []config.StepSecrets{
+					{Name: "targetRepositoryApiKeyCredentialsId", Description: "Jenkins 'Secret text' credentials ID containing the API key for pushing to the NuGet feed.", Type: "jenkins"},
+				},
+				Parameters: []config.StepParameters{
+					{
+						Name:        "projects",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+					{
+						Name:        "configuration",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `Release`,
+					},
+					{
+						Name:        "buildOptions",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+					{
+						Name: "buildSettingsInfo",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "custom/buildSettingsInfo",
+							},
+						},
+						Scope:     []string{"STEPS", "STAGES", "PARAMETERS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_buildSettingsInfo"),
+					},
+					{
+						Name:        "runTests",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"STEPS", "STAGES", "PARAMETERS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     true,
+					},
+					{
+						Name:        "testOptions",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"STEPS", "STAGES", "PARAMETERS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+					{
+						Name:        "reportCoverage",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"STEPS", "STAGES", "PARAMETERS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     true,
+					},
+					{
+						Name:        "createBOM",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "STEPS", "STAGES", "PARAMETERS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name:        "publish",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"STEPS", "STAGES", "PARAMETERS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name:        "targetRepositoryURL",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_targetRepositoryURL"),
+					},
+					{
+						Name: "targetRepositoryApiKey",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "targetRepositoryApiKeyCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:    "nugetFeedVaultSecretName",
+								Type:    "vaultSecret",
+								Default: "nuget-feed",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_targetRepositoryApiKey"),
+					},
+					{
+						Name:        "buildCacheBackend",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "GENERAL", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_buildCacheBackend"),
+					},
+					{
+						Name:        "buildCacheLocation",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "GENERAL", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_buildCacheLocation"),
+					},
+					{
+						Name:        "buildCacheEndpoint",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "GENERAL", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_buildCacheEndpoint"),
+					},
+				},
+			},
+			Containers: []config.Container{
+				{Name: "dotnet", Image: "mcr.microsoft.com/dotnet/sdk:8.0", Options: []config.Option{{Name: "-u", Value: "0"}}},
+			},
+			Outputs: config.StepOutputs{
+				Resources: []config.StepResources{
+					{
+						Name: "commonPipelineEnvironment",
+						Type: "piperEnvironment",
+						Parameters: []map[string]interface{}{
+							{"name": "custom/buildSettingsInfo"},
+						},
+					},
+					{
+						Name: "reports",
+						Type: "reports",
+						Parameters: []map[string]interface{}{
+							{"filePattern": "**/bom-dotnet.xml", "type": "sbom"},
+							{"filePattern": "**/TEST-*.xml", "type": "junit"},
+							{"filePattern": "**/coverage.cobertura.xml", "type": "cobertura-coverage"},
+						},
+					},
+				},
+			},
+		},
+	}
+	return theMetaData
+}
@@ -0,0 +1,151 @@
+//go:build unit
+// +build unit
+
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/stretchr/testify/assert"
+)
+
+type dotnetBuildMockUtils struct {
+	*mock.ExecMockRunner
+	*mock.FilesMock
+}
+
+func (d *dotnetBuildMockUtils) getDockerImageValue(stepName string) (string, error) {
+	return "mcr.microsoft.com/dotnet/sdk:8.0", nil
+}
+
+func newDotnetBuildTestsUtils() *dotnetBuildMockUtils {
+	return &dotnetBuildMockUtils{
+		ExecMockRunner: &mock.ExecMockRunner{},
+		FilesMock:      &mock.FilesMock{},
+	}
+}
+
+const dotnetTrx = `<TestRun xmlns="http://microsoft.com/schemas/VisualStudio/TeamTest/2010">
+  <Results>
+    <UnitTestResult testId="t1" testName="Add" duration="00:00:00.1000000" outcome="Passed" />
+  </Results>
+  <TestDefinitions>
+    <UnitTest id="t1" name="Add">
+      <TestMethod className="App.Tests.CalculatorTests" name="Add" />
+    </UnitTest>
+  </TestDefinitions>
+</TestRun>`
+
+func TestRunDotnetBuild(t *testing.T) {
+	t.Parallel()
+	trxResult := filepath.Join("TestResults", "runner_build_2024-01-01_12_00_00.trx")
+
+	t.Run("success - build and tests with coverage", func(t *testing.T) {
+		t.Parallel()
+		config := dotnetBuildOptions{Configuration: "Release", RunTests: true, ReportCoverage: true}
+		utils := newDotnetBuildTestsUtils()
+		utils.AddFile(trxResult, []byte(dotnetTrx))
+		cpe := dotnetBuildCommonPipelineEnvironment{}
+
+		err := runDotnetBuild(&config, &telemetry.CustomData{}, utils, &cpe)
+
+		assert.NoError(t, err)
+		if assert.Len(t, utils.Calls, 3) {
+			assert.Equal(t, mock.ExecCall{Exec: "dotnet", Params: []string{"restore"}}, utils.Calls[0])
+			assert.Equal(t, mock.ExecCall{Exec: "dotnet", Params: []string{"build", "--configuration", "Release", "--no-restore"}}, utils.Calls[1])
+			assert.Equal(t, mock.ExecCall{Exec: "dotnet", Params: []string{"test", "--configuration", "Release", "--no-build", "--logger", "trx", "--results-directory", "TestResults", "--collect", "XPlat Code Coverage"}}, utils.Calls[2])
+		}
+		junit, err := utils.FileRead("TEST-dotnet-runner_build_2024-01-01_12_00_00.xml")
+		if assert.NoError(t, err) {
+			assert.Contains(t, string(junit), `<testcase name="Add" classname="App.Tests.CalculatorTests" time="0.100"></testcase>`)
+		}
+		assert.Contains(t, cpe.custom.buildSettingsInfo, `"dockerImage":"mcr.microsoft.com/dotnet/sdk:8.0"`)
+	})
+
+	t.Run("success - multiple projects with options", func(t *testing.T) {
+		t.Parallel()
+		config := dotnetBuildOptions{
+			Projects:      []string{"src/App/App.csproj", "test/App.Tests/App.Tests.csproj"},
+			Configuration: "Debug",
+			BuildOptions:  []string{"-p:TreatWarningsAsErrors=true"},
+			RunTests:      true,
+			TestOptions:   []string{"--filter", "Category=Unit"},
+		}
+		utils := newDotnetBuildTestsUtils()
+
+		err := runDotnetBuild(&config, &telemetry.CustomData{}, utils, &dotnetBuildCommonPipelineEnvironment{})
+
+		assert.NoError(t, err)
+		if assert.Len(t, utils.Calls, 6) {
+			assert.Equal(t, []string{"restore", "src/App/App.csproj"}, utils.Calls[0].Params)
+			assert.Equal(t, []string{"build", "src/App/App.csproj", "--configuration", "Debug", "--no-restore", "-p:TreatWarningsAsErrors=true"}, utils.Calls[1].Params)
+			assert.Equal(t, []string{"restore", "test/App.Tests/App.Tests.csproj"}, utils.Calls[2].Params)
+			assert.Equal(t, []string{"test", "test/App.Tests/App.Tests.csproj", "--configuration", "Debug", "--no-build", "--logger", "trx", "--results-directory", "TestResults", "--filter", "Category=Unit"}, utils.Calls[5].Params)
+		}
+	})
+
+	t.Run("success - BOM and publish", func(t *testing.T) {
+		t.Parallel()
+		config := dotnetBuildOptions{
+			Projects:               []string{"src/App/App.csproj"},
+			Configuration:          "Release",
+			CreateBOM:              true,
+			Publish:                true,
+			TargetRepositoryURL:    "https://my.nexus.com/repository/nuget-hosted/index.json",
+			TargetRepositoryAPIKey: "key",
+		}
+		utils := newDotnetBuildTestsUtils()
+		utils.AddFile(filepath.Join(".pipeline", "nupkgs", "App.0.9.0.nupkg"), []byte("stale"))
+		utils.AddFile(filepath.Join(".pipeline", "nupkgs", "App.0.9.0.snupkg"), []byte("stale"))
+
+		err := runDotnetBuild(&config, &telemetry.CustomData{}, utils, &dotnetBuildCommonPipelineEnvironment{})
+
+		assert.NoError(t, err)
+		assert.True(t, utils.HasRemovedFile(filepath.Join(".pipeline", "nupkgs", "App.0.9.0.nupkg")), "packages of previous runs must be removed")
+		assert.True(t, utils.HasRemovedFile(filepath.Join(".pipeline", "nupkgs", "App.0.9.0.snupkg")), "packages of previous runs must be removed")
+		if assert.Len(t, utils.Calls, 6) {
+			assert.Equal(t, mock.ExecCall{Exec: "dotnet", Params: []string{"tool", "update", "CycloneDX", "--tool-path", filepath.Join(".pipeline", "dotnet-tools")}}, utils.Calls[2])
+			assert.Equal(t, mock.ExecCall{Exec: filepath.Join(".pipeline", "dotnet-tools", "dotnet-CycloneDX"), Params: []string{"src/App/App.csproj", "-o", filepath.Join("src", "App"), "-fn", "bom-dotnet.xml"}}, utils.Calls[3])
+			assert.Equal(t, []string{"pack", "src/App/App.csproj", "--configuration", "Release", "--no-build", "--output", filepath.Join(".pipeline", "nupkgs")}, utils.Calls[4].Params)
+			assert.Equal(t, []string{"nuget", "push", filepath.Join(".pipeline", "nupkgs", "*.nupkg"), "--source", "https://my.nexus.com/repository/nuget-hosted/index.json", "--skip-duplicate", "--api-key", "key"}, utils.Calls[5].Params)
+		}
+	})
+
+	t.Run("failure - build fails", func(t *testing.T) {
+		t.Parallel()
+		utils := newDotnetBuildTestsUtils()
+		utils.ShouldFailOnCommand = map[string]error{"dotnet build": fmt.Errorf("compile error")}
+
+		err := runDotnetBuild(&dotnetBuildOptions{}, &telemetry.CustomData{}, utils, &dotnetBuildCommonPipelineEnvironment{})
+
+		assert.EqualError(t, err, "failed to run dotnet build: compile error")
+	})
+
+	t.Run("failure - tests fail, results are kept", func(t *testing.T) {
+		t.Parallel()
+		config := dotnetBuildOptions{Configuration: "Release", RunTests: true}
+		utils := newDotnetBuildTestsUtils()
+		utils.AddFile(trxResult, []byte(dotnetTrx))
+		utils.ShouldFailOnCommand = map[string]error{"dotnet test": fmt.Errorf("exit status 1")}
+
+		err := runDotnetBuild(&config, &telemetry.CustomData{}, utils, &dotnetBuildCommonPipelineEnvironment{})
+
+		assert.EqualError(t, err, "some tests failed: exit status 1")
+		assert.True(t, utils.HasWrittenFile("TEST-dotnet-runner_build_2024-01-01_12_00_00.xml"))
+	})
+
+	t.Run("failure - push fails", func(t *testing.T) {
+		t.Parallel()
+		config := dotnetBuildOptions{Publish: true, TargetRepositoryURL: "https://my.nexus.com/repository/nuget-hosted/index.json"}
+		utils := newDotnetBuildTestsUtils()
+		utils.ShouldFailOnCommand = map[string]error{"dotnet nuget push": fmt.Errorf("401 Unauthorized")}
+
+		err := runDotnetBuild(&config, &telemetry.CustomData{}, utils, &dotnetBuildCommonPipelineEnvironment{})
+
+		assert.EqualError(t, err, "failed to push packages to https://my.nexus.com/repository/nuget-hosted/index.json: 401 Unauthorized")
+	})
+}
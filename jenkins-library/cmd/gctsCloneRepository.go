@@ -6,6 +6,7 @@ import (
 	"net/http/cookiejar"
 
 	"github.com/Jeffail/gabs/v2"
+	"github.com/SAP/jenkins-library/pkg/gcts"
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
@@ -47,7 +48,7 @@ func cloneRepository(config *gctsCloneRepositoryOptions, telemetryData *telemetr
 		"/sap/bc/cts_abapvcs/repository/" + config.Repository +
 		"/clone?sap-client=" + config.Client
 
-	url, urlErr := addQueryToURL(url, config.QueryParameters)
+	url, urlErr := gcts.AddQueryToURL(url, config.QueryParameters)
 
 	if urlErr != nil {
 
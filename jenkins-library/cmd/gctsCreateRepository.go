@@ -9,6 +9,7 @@ import (
 
 	gabs "github.com/Jeffail/gabs/v2"
 	"github.com/SAP/jenkins-library/pkg/command"
+	"github.com/SAP/jenkins-library/pkg/gcts"
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
@@ -84,7 +85,7 @@ func createRepository(config *gctsCreateRepositoryOptions, telemetryData *teleme
 
 	url := config.Host + "/sap/bc/cts_abapvcs/repository?sap-client=" + config.Client
 
-	url, urlErr := addQueryToURL(url, config.QueryParameters)
+	url, urlErr := gcts.AddQueryToURL(url, config.QueryParameters)
 
 	if urlErr != nil {
 
@@ -14,6 +14,7 @@ import (
 	"strings"
 
 	"github.com/SAP/jenkins-library/pkg/command"
+	"github.com/SAP/jenkins-library/pkg/gcts"
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
@@ -379,7 +380,7 @@ func getRepositoryObjects(config *gctsExecuteABAPQualityChecksOptions, client pi
 		"/sap/bc/cts_abapvcs/repository/" + config.Repository +
 		"/objects?sap-client=" + config.Client
 
-	url, urlErr := addQueryToURL(url, config.QueryParameters)
+	url, urlErr := gcts.AddQueryToURL(url, config.QueryParameters)
 
 	if urlErr != nil {
 
@@ -435,7 +436,7 @@ func getPackages(config *gctsExecuteABAPQualityChecksOptions, client piperhttp.S
 		"/sap/bc/cts_abapvcs/repository/" + config.Repository +
 		"/objects?sap-client=" + config.Client
 
-	url, urlErr := addQueryToURL(url, config.QueryParameters)
+	url, urlErr := gcts.AddQueryToURL(url, config.QueryParameters)
 
 	if urlErr != nil {
 
@@ -478,7 +479,7 @@ func discoverServer(config *gctsExecuteABAPQualityChecksOptions, client piperhtt
 	url := config.Host +
 		"/sap/bc/adt/core/discovery?sap-client=" + config.Client
 
-	url, urlErr := addQueryToURL(url, config.QueryParameters)
+	url, urlErr := gcts.AddQueryToURL(url, config.QueryParameters)
 
 	if urlErr != nil {
 
@@ -576,7 +577,7 @@ func runAUnitTest(config *gctsExecuteABAPQualityChecksOptions, client piperhttp.
 	url := config.Host +
 		"/sap/bc/adt/abapunit/testruns?sap-client=" + config.Client
 
-	url, urlErr := addQueryToURL(url, config.QueryParameters)
+	url, urlErr := gcts.AddQueryToURL(url, config.QueryParameters)
 
 	if urlErr != nil {
 
@@ -847,7 +848,7 @@ func startATCRun(config *gctsExecuteABAPQualityChecksOptions, client piperhttp.S
 	url := config.Host +
 		"/sap/bc/adt/atc/runs?worklistId=" + worklistID + "&sap-client=" + config.Client
 
-	url, urlErr := addQueryToURL(url, config.QueryParameters)
+	url, urlErr := gcts.AddQueryToURL(url, config.QueryParameters)
 
 	if urlErr != nil {
 
@@ -883,7 +884,7 @@ func getATCRun(config *gctsExecuteABAPQualityChecksOptions, client piperhttp.Sen
 	url := config.Host +
 		"/sap/bc/adt/atc/worklists/" + worklistID + "?sap-client=" + config.Client
 
-	url, urlErr := addQueryToURL(url, config.QueryParameters)
+	url, urlErr := gcts.AddQueryToURL(url, config.QueryParameters)
 
 	if urlErr != nil {
 
@@ -910,7 +911,7 @@ func getWorklist(config *gctsExecuteABAPQualityChecksOptions, client piperhttp.S
 		"/sap/bc/adt/atc/worklists?checkVariant=" + config.AtcVariant + "&sap-client=" + config.Client
 	discHeader, discError := discoverServer(config, client)
 
-	url, urlErr := addQueryToURL(url, config.QueryParameters)
+	url, urlErr := gcts.AddQueryToURL(url, config.QueryParameters)
 
 	if urlErr != nil {
 
@@ -1469,7 +1470,7 @@ func getRepo(config *gctsExecuteABAPQualityChecksOptions, client piperhttp.Sende
 		"/sap/bc/cts_abapvcs/repository/" + config.Repository +
 		"?sap-client=" + config.Client
 
-	url, urlErr := addQueryToURL(url, config.QueryParameters)
+	url, urlErr := gcts.AddQueryToURL(url, config.QueryParameters)
 
 	if urlErr != nil {
 
@@ -1505,7 +1506,7 @@ func getRepositoryLayout(config *gctsExecuteABAPQualityChecksOptions, client pip
 		"/sap/bc/cts_abapvcs/repository/" + config.Repository +
 		"/layout?sap-client=" + config.Client
 
-	url, urlErr := addQueryToURL(url, config.QueryParameters)
+	url, urlErr := gcts.AddQueryToURL(url, config.QueryParameters)
 
 	if urlErr != nil {
 
@@ -1541,7 +1542,7 @@ func getCommitList(config *gctsExecuteABAPQualityChecksOptions, client piperhttp
 		"/sap/bc/cts_abapvcs/repository/" + config.Repository +
 		"/getCommit?sap-client=" + config.Client
 
-	url, urlErr := addQueryToURL(url, config.QueryParameters)
+	url, urlErr := gcts.AddQueryToURL(url, config.QueryParameters)
 	if urlErr != nil {
 
 		return commitResp, urlErr
@@ -1576,7 +1577,7 @@ func getObjectDifference(config *gctsExecuteABAPQualityChecksOptions, fromCommit
 		"/sap/bc/cts_abapvcs/repository/" + config.Repository +
 		"/compareCommits?fromCommit=" + fromCommit + "&toCommit=" + toCommit + "&sap-client=" + config.Client
 
-	url, urlErr := addQueryToURL(url, config.QueryParameters)
+	url, urlErr := gcts.AddQueryToURL(url, config.QueryParameters)
 
 	if urlErr != nil {
 
@@ -1612,7 +1613,7 @@ func getObjectInfo(config *gctsExecuteABAPQualityChecksOptions, client piperhttp
 		"/sap/bc/cts_abapvcs/objects/" + objectType + "/" + objectName +
 		"?sap-client=" + config.Client
 
-	url, urlErr := addQueryToURL(url, config.QueryParameters)
+	url, urlErr := gcts.AddQueryToURL(url, config.QueryParameters)
 
 	if urlErr != nil {
 
@@ -1647,7 +1648,7 @@ func getHistory(config *gctsExecuteABAPQualityChecksOptions, client piperhttp.Se
 	url := config.Host +
 		"/sap/bc/cts_abapvcs/repository/" + config.Repository + "/getHistory?sap-client=" + config.Client
 
-	url, urlErr := addQueryToURL(url, config.QueryParameters)
+	url, urlErr := gcts.AddQueryToURL(url, config.QueryParameters)
 
 	if urlErr != nil {
 
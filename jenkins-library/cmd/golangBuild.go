@@ -8,10 +8,14 @@ import (
 	"path"
 	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/SAP/jenkins-library/pkg/buildcache"
 	"github.com/SAP/jenkins-library/pkg/buildsettings"
 	"github.com/SAP/jenkins-library/pkg/certutils"
+	"github.com/SAP/jenkins-library/pkg/changeimpact"
 	"github.com/SAP/jenkins-library/pkg/command"
+	"github.com/SAP/jenkins-library/pkg/docker"
 	"github.com/SAP/jenkins-library/pkg/goget"
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/log"
@@ -22,6 +26,9 @@ import (
 	"github.com/SAP/jenkins-library/pkg/multiarch"
 	"github.com/SAP/jenkins-library/pkg/versioning"
 
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"golang.org/x/mod/modfile"
 )
 
@@ -116,13 +123,21 @@ func golangBuild(config golangBuildOptions, telemetryData *telemetry.CustomData,
 
 	// Error situations will be bubbled up until they reach the line below which will then stop execution
 	// through the log.Entry().Fatal() call leading to an os.Exit(1) in the end.
+	cache := restoreBuildCache(buildcache.Golang(), config.BuildCacheBackend, config.BuildCacheLocation, config.BuildCacheEndpoint, telemetryData)
 	err := runGolangBuild(&config, telemetryData, utils, commonPipelineEnvironment)
 	if err != nil {
 		log.Entry().WithError(err).Fatal("execution of golang build failed")
 	}
+	saveBuildCache(cache, telemetryData)
 }
 
 func runGolangBuild(config *golangBuildOptions, telemetryData *telemetry.CustomData, utils golangBuildUtils, commonPipelineEnvironment *golangBuildCommonPipelineEnvironment) error {
+	startedOn := time.Now()
+	if config.CreateImage && len(config.TargetArchitectures) > 1 && len(config.Output) == 0 {
+		// without output the binaries of all architectures are written to the same file
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return fmt.Errorf("parameter output is required to create an image for more than one target architecture")
+	}
 	goModFile, err := readGoModFile(utils) // returns nil if go.mod doesnt exist
 	if err != nil {
 		return err
@@ -212,6 +227,7 @@ func runGolangBuild(config *golangBuildOptions, telemetryData *telemetry.CustomD
 	}
 
 	var binaries []string
+	var imageBinaries []docker.PlatformBinaries
 	platforms, err := multiarch.ParsePlatformStrings(config.TargetArchitectures)
 	if err != nil {
 		return err
@@ -226,6 +242,7 @@ func runGolangBuild(config *golangBuildOptions, telemetryData *telemetry.CustomD
 		if len(binaryNames) > 0 {
 			binaries = append(binaries, binaryNames...)
 		}
+		imageBinaries = append(imageBinaries, golangImageBinaries(platform, binaryNames))
 	}
 
 	log.Entry().Debugf("creating build settings information...")
@@ -247,6 +264,35 @@ func runGolangBuild(config *golangBuildOptions, telemetryData *telemetry.CustomD
 	}
 	commonPipelineEnvironment.custom.buildSettingsInfo = buildSettingsInfo
 
+	var images []string
+	if config.CreateImage {
+		if images, err = createGolangImage(config, imageBinaries, utils, commonPipelineEnvironment); err != nil {
+			return err
+		}
+	}
+
+	if config.CreateProvenance {
+		settings := provenanceSettings{
+			stepName:          stepName,
+			buildTool:         "golang",
+			signingKey:        config.ProvenanceSigningKey,
+			dockerConfigJSON:  config.DockerConfigJSON,
+			buildSettingsInfo: buildSettingsInfo,
+			externalParameters: map[string]interface{}{
+				"packages":            config.Packages,
+				"targetArchitectures": config.TargetArchitectures,
+				"buildFlags":          config.BuildFlags,
+				"ldflagsTemplate":     config.LdflagsTemplate,
+				"cgoEnabled":          config.CgoEnabled,
+			},
+			startedOn:   startedOn,
+			bomPatterns: []string{sbomFilename},
+		}
+		if err := createProvenance(settings, binaries, images, utils); err != nil {
+			return fmt.Errorf("failed to create provenance: %w", err)
+		}
+	}
+
 	if config.Publish {
 		if len(config.TargetRepositoryURL) == 0 {
 			return fmt.Errorf("there's no target repository for binary publishing configured")
@@ -345,7 +391,16 @@ func prepareGolangEnvironment(config *golangBuildOptions, goModFile *modfile.Fil
 
 func runGolangTests(config *golangBuildOptions, utils golangBuildUtils) (bool, error) {
 	// execute gotestsum in order to have more output options
-	testOptions := []string{"--junitfile", golangUnitTestOutput, "--jsonfile", unitJsonReport, "--", fmt.Sprintf("-coverprofile=%v", coverageFile), "-tags=unit", "./..."}
+	packages, err := golangTestPackages(config, utils)
+	if err != nil {
+		return false, err
+	}
+	if len(packages) == 0 {
+		log.Entry().Info("no package is affected by the changes, skipping the tests")
+		return true, nil
+	}
+	testOptions := []string{"--junitfile", golangUnitTestOutput, "--jsonfile", unitJsonReport, "--", fmt.Sprintf("-coverprofile=%v", coverageFile), "-tags=unit"}
+	testOptions = append(testOptions, packages...)
 	testOptions = append(testOptions, config.TestOptions...)
 	if err := utils.RunExecutable("gotestsum", testOptions...); err != nil {
 		exists, fileErr := utils.FileExists(golangUnitTestOutput)
@@ -366,7 +421,16 @@ func runGolangTests(config *golangBuildOptions, utils golangBuildUtils) (bool, e
 func runGolangIntegrationTests(config *golangBuildOptions, utils golangBuildUtils) (bool, error) {
 	// execute gotestsum in order to have more output options
 	// for integration tests coverage data is not meaningful and thus not being created
-	if err := utils.RunExecutable("gotestsum", "--junitfile", golangIntegrationTestOutput, "--jsonfile", integrationJsonReport, "--", "-tags=integration", "./..."); err != nil {
+	packages, err := golangTestPackages(config, utils)
+	if err != nil {
+		return false, err
+	}
+	if len(packages) == 0 {
+		log.Entry().Info("no package is affected by the changes, skipping the integration tests")
+		return true, nil
+	}
+	testOptions := append([]string{"--junitfile", golangIntegrationTestOutput, "--jsonfile", integrationJsonReport, "--", "-tags=integration"}, packages...)
+	if err := utils.RunExecutable("gotestsum", testOptions...); err != nil {
 		exists, fileErr := utils.FileExists(golangIntegrationTestOutput)
 		if !exists || fileErr != nil {
 			log.SetErrorCategory(log.ErrorBuild)
@@ -377,6 +441,29 @@ func runGolangIntegrationTests(config *golangBuildOptions, utils golangBuildUtil
 	return true, nil
 }
 
+// golangGlobalFiles affect all packages of the module if they change
+var golangGlobalFiles = []string{"go.mod", "go.sum", "vendor/**"}
+
+// golangTestPackages returns the packages to be tested, which are all packages unless the tests are restricted to the affected ones
+func golangTestPackages(config *golangBuildOptions, utils golangBuildUtils) ([]string, error) {
+	if !config.OnlyAffectedModules {
+		return []string{"./..."}, nil
+	}
+	packages, err := changeimpact.GoPackages(utils, "go.mod")
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect the go packages: %w", err)
+	}
+	affected, ok := affectedModules(packages, config.ChangeDetectionBaseRef, golangGlobalFiles)
+	if !ok || len(affected) == len(packages) {
+		return []string{"./..."}, nil
+	}
+	testPackages := []string{}
+	for _, directory := range modulePaths(affected) {
+		testPackages = append(testPackages, "./"+directory)
+	}
+	return testPackages, nil
+}
+
 func reportGolangTestCoverage(config *golangBuildOptions, utils golangBuildUtils) error {
 	if config.CoverageFormat == "cobertura" {
 		// execute gocover-cobertura in order to create cobertura report
@@ -518,6 +605,69 @@ func runGolangBuildPerArchitecture(config *golangBuildOptions, goModFile *modfil
 	return binaryNames, nil
 }
 
+// golangImageBinaries names the binaries of a platform in the image without the platform suffix of their file name
+func golangImageBinaries(platform multiarch.Platform, binaries []string) docker.PlatformBinaries {
+	imageBinaries := docker.PlatformBinaries{Platform: v1.Platform{OS: platform.OS, Architecture: platform.Arch, Variant: platform.Variant}}
+	suffix := fmt.Sprintf("-%s.%s", platform.OS, platform.Arch)
+	for _, binary := range binaries {
+		imageBinaries.Binaries = append(imageBinaries.Binaries, docker.Binary{Path: binary, Name: strings.TrimSuffix(filepath.Base(binary), suffix)})
+	}
+	return imageBinaries
+}
+
+// createGolangImage pushes the image containing the binaries of all architectures and returns it by digest
+func createGolangImage(config *golangBuildOptions, imageBinaries []docker.PlatformBinaries, utils golangBuildUtils, commonPipelineEnvironment *golangBuildCommonPipelineEnvironment) ([]string, error) {
+	containerRegistry, err := docker.ContainerRegistryFromURL(config.ContainerRegistryURL)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, fmt.Errorf("failed to read registry url %v: %w", config.ContainerRegistryURL, err)
+	}
+	var dockerConfig []byte
+	if len(config.DockerConfigJSON) > 0 {
+		if dockerConfig, err = utils.FileRead(config.DockerConfigJSON); err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return nil, fmt.Errorf("failed to read %v: %w", config.DockerConfigJSON, err)
+		}
+	}
+	keychain, err := docker.NewKeychain(dockerConfig)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, err
+	}
+	options := []remote.Option{remote.WithAuthFromKeychain(keychain)}
+
+	index, err := docker.BinaryImageIndex(config.BaseImage, imageBinaries, utils, options...)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorBuild)
+		return nil, fmt.Errorf("failed to create image: %w", err)
+	}
+	digest, err := index.Digest()
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute image digest: %w", err)
+	}
+
+	// Docker image tags don't allow plus signs in tags, thus replacing with dash
+	containerImageNameTag := fmt.Sprintf("%v:%v", config.ContainerImageName, strings.ReplaceAll(config.ContainerImageTag, "+", "-"))
+	tag, err := name.NewTag(fmt.Sprintf("%v/%v", containerRegistry, containerImageNameTag))
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, fmt.Errorf("invalid image name: %w", err)
+	}
+	if err := remote.WriteIndex(tag, index, options...); err != nil {
+		log.SetErrorCategory(log.ErrorInfrastructure)
+		return nil, fmt.Errorf("failed to push image %v: %w", tag, err)
+	}
+	log.Entry().Infof("image %v pushed with digest %v", tag, digest)
+
+	commonPipelineEnvironment.container.registryURL = config.ContainerRegistryURL
+	commonPipelineEnvironment.container.imageNameTag = containerImageNameTag
+	commonPipelineEnvironment.container.imageDigest = digest.String()
+	commonPipelineEnvironment.container.imageNames = append(commonPipelineEnvironment.container.imageNames, config.ContainerImageName)
+	commonPipelineEnvironment.container.imageNameTags = append(commonPipelineEnvironment.container.imageNameTags, containerImageNameTag)
+	commonPipelineEnvironment.container.imageDigests = append(commonPipelineEnvironment.container.imageDigests, digest.String())
+	return []string{tag.Context().Name() + "@" + digest.String()}, nil
+}
+
 func runBOMCreation(utils golangBuildUtils, outputFilename string) error {
 	if err := utils.RunExecutable("cyclonedx-gomod", "mod", "-licenses", fmt.Sprintf("-verbose=%t", GeneralConfig.Verbose), "-test", "-output", outputFilename, "-output-version", "1.4"); err != nil {
 		return fmt.Errorf("BOM creation failed: %w", err)
@@ -49,6 +49,19 @@ type golangBuildOptions struct {
 	PrivateModulesGitToken       string   `json:"privateModulesGitToken,omitempty"`
 	ArtifactVersion              string   `json:"artifactVersion,omitempty"`
 	GolangciLintURL              string   `json:"golangciLintUrl,omitempty"`
+	BuildCacheBackend            string   `json:"buildCacheBackend,omitempty" validate:"possible-values=local s3 gcs"`
+	BuildCacheLocation           string   `json:"buildCacheLocation,omitempty"`
+	BuildCacheEndpoint           string   `json:"buildCacheEndpoint,omitempty"`
+	OnlyAffectedModules          bool     `json:"onlyAffectedModules,omitempty"`
+	ChangeDetectionBaseRef       string   `json:"changeDetectionBaseRef,omitempty"`
+	CreateProvenance             bool     `json:"createProvenance,omitempty"`
+	ProvenanceSigningKey         string   `json:"provenanceSigningKey,omitempty"`
+	CreateImage                  bool     `json:"createImage,omitempty"`
+	BaseImage                    string   `json:"baseImage,omitempty"`
+	ContainerRegistryURL         string   `json:"containerRegistryUrl,omitempty" validate:"required_if=CreateImage true"`
+	ContainerImageName           string   `json:"containerImageName,omitempty" validate:"required_if=CreateImage true"`
+	ContainerImageTag            string   `json:"containerImageTag,omitempty" validate:"required_if=CreateImage true"`
+	DockerConfigJSON             string   `json:"dockerConfigJSON,omitempty"`
 }
 
 type golangBuildCommonPipelineEnvironment struct {
@@ -56,6 +69,14 @@ type golangBuildCommonPipelineEnvironment struct {
 		buildSettingsInfo string
 		artifacts         piperenv.Artifacts
 	}
+	container struct {
+		registryURL   string
+		imageNameTag  string
+		imageDigest   string
+		imageNames    []string
+		imageNameTags []string
+		imageDigests  []string
+	}
 }
 
 func (p *golangBuildCommonPipelineEnvironment) persist(path, resourceName string) {
@@ -66,6 +87,12 @@ func (p *golangBuildCommonPipelineEnvironment) persist(path, resourceName string
 	}{
 		{category: "custom", name: "buildSettingsInfo", value: p.custom.buildSettingsInfo},
 		{category: "custom", name: "artifacts", value: p.custom.artifacts},
+		{category: "container", name: "registryUrl", value: p.container.registryURL},
+		{category: "container", name: "imageNameTag", value: p.container.imageNameTag},
+		{category: "container", name: "imageDigest", value: p.container.imageDigest},
+		{category: "container", name: "imageNames", value: p.container.imageNames},
+		{category: "container", name: "imageNameTags", value: p.container.imageNameTags},
+		{category: "container", name: "imageDigests", value: p.container.imageDigests},
 	}
 
 	errCount := 0
@@ -94,6 +121,7 @@ func (p *golangBuildReports) persist(stepConfig golangBuildOptions, gcpJsonKeyFi
 		{FilePattern: "**/bom-golang.xml", ParamRef: "", StepResultType: "sbom"},
 		{FilePattern: "**/TEST-*.xml", ParamRef: "", StepResultType: "junit"},
 		{FilePattern: "**/cobertura-coverage.xml", ParamRef: "", StepResultType: "cobertura-coverage"},
+		{FilePattern: "**/provenance-*.intoto.jsonl", ParamRef: "", StepResultType: "provenance"},
 	}
 
 	gcsClient, err := gcs.NewClient(gcpJsonKeyFilePath, "")
@@ -172,6 +200,8 @@ If the build is successful the resulting artifact can be uploaded to e.g. a bina
 			log.RegisterSecret(stepConfig.TargetRepositoryPassword)
 			log.RegisterSecret(stepConfig.TargetRepositoryUser)
 			log.RegisterSecret(stepConfig.PrivateModulesGitToken)
+			log.RegisterSecret(stepConfig.ProvenanceSigningKey)
+			log.RegisterSecret(stepConfig.DockerConfigJSON)
 
 			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
 				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
@@ -286,6 +316,19 @@ func addGolangBuildFlags(cmd *cobra.Command, stepConfig *golangBuildOptions) {
 	cmd.Flags().StringVar(&stepConfig.PrivateModulesGitToken, "privateModulesGitToken", os.Getenv("PIPER_privateModulesGitToken"), "GitHub personal access token as per https://help.github.com/en/github/authenticating-to-github/creating-a-personal-access-token-for-the-command-line.")
 	cmd.Flags().StringVar(&stepConfig.ArtifactVersion, "artifactVersion", os.Getenv("PIPER_artifactVersion"), "Version of the artifact to be built.")
 	cmd.Flags().StringVar(&stepConfig.GolangciLintURL, "golangciLintUrl", `https://github.com/golangci/golangci-lint/releases/download/v1.51.2/golangci-lint-1.51.2-linux-amd64.tar.gz`, "Specifies the download url of the Golangci-Lint Linux amd64 tar binary file. This can be found at https://github.com/golangci/golangci-lint/releases.")
+	cmd.Flags().StringVar(&stepConfig.BuildCacheBackend, "buildCacheBackend", os.Getenv("PIPER_buildCacheBackend"), "Backend of the dependency cache. If set, the dependencies of the build tool are restored before the build from an archive whose key is computed from the lock files of the project and saved after a successful build. Caching is disabled by default.")
+	cmd.Flags().StringVar(&stepConfig.BuildCacheLocation, "buildCacheLocation", os.Getenv("PIPER_buildCacheLocation"), "Location of the dependency cache: a directory for the `local` backend, or a bucket optionally followed by a path prefix for the `s3` and `gcs` backends, e.g. `my-bucket/piper-cache`. The `gcs` backend uses the key file of `gcpJsonKeyFilePath`, the `s3` backend uses the AWS credentials of the environment.")
+	cmd.Flags().StringVar(&stepConfig.BuildCacheEndpoint, "buildCacheEndpoint", os.Getenv("PIPER_buildCacheEndpoint"), "Endpoint of an S3-compatible object store used by the `s3` backend instead of AWS S3, e.g. `https://minio.example.org`.")
+	cmd.Flags().BoolVar(&stepConfig.OnlyAffectedModules, "onlyAffectedModules", false, "Restricts the step to the modules of a monorepo affected by the changes of a pull request, including the modules which depend on them. Changes outside of all modules or to shared lock files affect all modules. Without pull request or base reference all modules are processed.")
+	cmd.Flags().StringVar(&stepConfig.ChangeDetectionBaseRef, "changeDetectionBaseRef", os.Getenv("PIPER_changeDetectionBaseRef"), "Branch or commit the changes are detected against if `onlyAffectedModules` is active. Defaults to the target branch of the pull request provided by the orchestrator.")
+	cmd.Flags().BoolVar(&stepConfig.CreateProvenance, "createProvenance", false, "Creates a [SLSA v1 provenance](https://slsa.dev/spec/v1.0/provenance) of the build outputs as in-toto statement in a DSSE envelope, which is published as step report `provenance-<stepName>.intoto.jsonl`.")
+	cmd.Flags().StringVar(&stepConfig.ProvenanceSigningKey, "provenanceSigningKey", os.Getenv("PIPER_provenanceSigningKey"), "Path to the PEM encoded ECDSA, Ed25519 or RSA private key the provenance is signed with. Without key the provenance is not signed.")
+	cmd.Flags().BoolVar(&stepConfig.CreateImage, "createImage", false, "Creates a container image without Docker daemon and Dockerfile: the binaries built for each of the [targetArchitectures](#targetarchitectures) are added to [baseImage](#baseimage) in directory `/ko-app`, similar to [ko](https://ko.build).\nThe images are pushed as multi-arch index to `<containerRegistryUrl>/<containerImageName>:<containerImageTag>`, the first binary is the entrypoint. Only `linux` architectures are supported.\nFor more than one architecture [output](#output) is required, so that the binaries of the architectures don't overwrite each other.\n")
+	cmd.Flags().StringVar(&stepConfig.BaseImage, "baseImage", `gcr.io/distroless/static:nonroot`, "Image the binaries are added to if `createImage` is active. It has to provide all target architectures, e.g. as multi-arch index.")
+	cmd.Flags().StringVar(&stepConfig.ContainerRegistryURL, "containerRegistryUrl", os.Getenv("PIPER_containerRegistryUrl"), "http(s) url of the Container registry the image created with `createImage` is pushed to.")
+	cmd.Flags().StringVar(&stepConfig.ContainerImageName, "containerImageName", os.Getenv("PIPER_containerImageName"), "Name of the image created with `createImage`.")
+	cmd.Flags().StringVar(&stepConfig.ContainerImageTag, "containerImageTag", os.Getenv("PIPER_containerImageTag"), "Tag of the image created with `createImage`.")
+	cmd.Flags().StringVar(&stepConfig.DockerConfigJSON, "dockerConfigJSON", os.Getenv("PIPER_dockerConfigJSON"), "Path to the file `.docker/config.json` containing the credentials to push the image created with `createImage` and to pull the base image.")
 
 	cmd.MarkFlagRequired("targetArchitectures")
 }
@@ -302,6 +345,8 @@ func golangBuildMetadata() config.StepData {
 			Inputs: config.StepInputs{
 				Secrets: []config.StepSecrets{
 					{Name: "golangPrivateModulesGitTokenCredentialsId", Description: "Jenkins 'Username with password' credentials ID containing username/password for http access to your git repos where your go private modules are stored.", Type: "jenkins"},
+					{Name: "provenanceSigningKeyCredentialsId", Description: "Jenkins 'Secret file' credentials ID containing the private key the provenance is signed with.", Type: "jenkins"},
+					{Name: "dockerConfigJsonCredentialsId", Description: "Jenkins 'Secret file' credentials ID containing Docker config.json (with registry credential(s)) to push the image created with `createImage`.", Type: "jenkins"},
 				},
 				Parameters: []config.StepParameters{
 					{
@@ -590,6 +635,155 @@ func golangBuildMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     `https://github.com/golangci/golangci-lint/releases/download/v1.51.2/golangci-lint-1.51.2-linux-amd64.tar.gz`,
 					},
+					{
+						Name:        "buildCacheBackend",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "GENERAL", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_buildCacheBackend"),
+					},
+					{
+						Name:        "buildCacheLocation",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "GENERAL", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_buildCacheLocation"),
+					},
+					{
+						Name:        "buildCacheEndpoint",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "GENERAL", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_buildCacheEndpoint"),
+					},
+					{
+						Name:        "onlyAffectedModules",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "GENERAL", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name:        "changeDetectionBaseRef",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "GENERAL", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_changeDetectionBaseRef"),
+					},
+					{
+						Name:        "createProvenance",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "GENERAL", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name: "provenanceSigningKey",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "provenanceSigningKeyCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:    "provenanceSigningKeyVaultSecretName",
+								Type:    "vaultSecretFile",
+								Default: "provenance-signing-key",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_provenanceSigningKey"),
+					},
+					{
+						Name:        "createImage",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name:        "baseImage",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `gcr.io/distroless/static:nonroot`,
+					},
+					{
+						Name: "containerRegistryUrl",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "container/registryUrl",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{{Name: "dockerRegistryUrl"}},
+						Default:   os.Getenv("PIPER_containerRegistryUrl"),
+					},
+					{
+						Name:        "containerImageName",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{{Name: "dockerImageName"}},
+						Default:     os.Getenv("PIPER_containerImageName"),
+					},
+					{
+						Name: "containerImageTag",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "artifactVersion",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_containerImageTag"),
+					},
+					{
+						Name: "dockerConfigJSON",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "dockerConfigJsonCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:    "dockerConfigFileVaultSecretName",
+								Type:    "vaultSecretFile",
+								Default: "docker-config",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_dockerConfigJSON"),
+					},
 				},
 			},
 			Containers: []config.Container{
@@ -603,6 +797,12 @@ func golangBuildMetadata() config.StepData {
 						Parameters: []map[string]interface{}{
 							{"name": "custom/buildSettingsInfo"},
 							{"name": "custom/artifacts", "type": "piperenv.Artifacts"},
+							{"name": "container/registryUrl"},
+							{"name": "container/imageNameTag"},
+							{"name": "container/imageDigest"},
+							{"name": "container/imageNames", "type": "[]string"},
+							{"name": "container/imageNameTags", "type": "[]string"},
+							{"name": "container/imageDigests", "type": "[]string"},
 						},
 					},
 					{
@@ -612,6 +812,7 @@ func golangBuildMetadata() config.StepData {
 							{"filePattern": "**/bom-golang.xml", "type": "sbom"},
 							{"filePattern": "**/TEST-*.xml", "type": "junit"},
 							{"filePattern": "**/cobertura-coverage.xml", "type": "cobertura-coverage"},
+							{"filePattern": "**/provenance-*.intoto.jsonl", "type": "provenance"},
 						},
 					},
 				},
@@ -8,16 +8,25 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/SAP/jenkins-library/pkg/docker"
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/mock"
 	"github.com/SAP/jenkins-library/pkg/multiarch"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"golang.org/x/mod/modfile"
 	"golang.org/x/mod/module"
@@ -126,6 +135,20 @@ go 1.17`
 		assert.Equal(t, []string{"build", "-trimpath"}, utils.ExecMockRunner.Calls[0].Params)
 	})
 
+	t.Run("error - image of two architectures without output", func(t *testing.T) {
+		config := golangBuildOptions{
+			CreateImage:         true,
+			TargetArchitectures: []string{"linux,amd64", "linux,arm64"},
+		}
+		utils := newGolangBuildTestsUtils()
+		utils.FilesMock.AddFile("go.mod", []byte(modTestFile))
+
+		err := runGolangBuild(&config, &telemetry.CustomData{}, utils, &cpe)
+
+		assert.EqualError(t, err, "parameter output is required to create an image for more than one target architecture")
+		assert.Empty(t, utils.ExecMockRunner.Calls)
+	})
+
 	t.Run("success - tests & ldflags", func(t *testing.T) {
 		config := golangBuildOptions{
 			RunTests:            true,
@@ -872,6 +895,89 @@ func TestRunGolangBuildPerArchitecture(t *testing.T) {
 	})
 }
 
+func TestCreateGolangImage(t *testing.T) {
+	server := httptest.NewServer(registry.New())
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+	base, err := random.Image(64, 1)
+	require.NoError(t, err)
+	baseConfig, err := base.ConfigFile()
+	require.NoError(t, err)
+	baseConfig.OS, baseConfig.Architecture = "linux", "amd64"
+	base, err = mutate.ConfigFile(base, baseConfig)
+	require.NoError(t, err)
+	baseTag, _ := name.NewTag(host + "/distroless/static:nonroot")
+	require.NoError(t, remote.Write(baseTag, base))
+
+	t.Run("success - image is pushed and written to the cpe", func(t *testing.T) {
+		config := golangBuildOptions{
+			BaseImage:            baseTag.String(),
+			ContainerRegistryURL: server.URL,
+			ContainerImageName:   "acme/app",
+			ContainerImageTag:    "1.0.0+build",
+		}
+		utils := newGolangBuildTestsUtils()
+		utils.AddFile("app-linux.amd64", []byte("binary"))
+		platform, _ := multiarch.ParsePlatformString("linux,amd64")
+		cpe := golangBuildCommonPipelineEnvironment{}
+
+		images, err := createGolangImage(&config, []docker.PlatformBinaries{golangImageBinaries(platform, []string{"app-linux.amd64"})}, utils, &cpe)
+
+		require.NoError(t, err)
+		tag, _ := name.NewTag(host + "/acme/app:1.0.0-build")
+		index, err := remote.Index(tag)
+		require.NoError(t, err)
+		digest, _ := index.Digest()
+		assert.Equal(t, []string{host + "/acme/app@" + digest.String()}, images)
+		assert.Equal(t, "acme/app:1.0.0-build", cpe.container.imageNameTag)
+		assert.Equal(t, []string{"acme/app:1.0.0-build"}, cpe.container.imageNameTags)
+		assert.Equal(t, []string{digest.String()}, cpe.container.imageDigests)
+		assert.Equal(t, server.URL, cpe.container.registryURL)
+		manifest, err := index.IndexManifest()
+		require.NoError(t, err)
+		require.Len(t, manifest.Manifests, 1)
+		image, err := index.Image(manifest.Manifests[0].Digest)
+		require.NoError(t, err)
+		imageConfig, err := image.ConfigFile()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"/ko-app/app"}, imageConfig.Config.Entrypoint)
+	})
+
+	t.Run("error - architecture not provided by base image", func(t *testing.T) {
+		config := golangBuildOptions{
+			BaseImage:            baseTag.String(),
+			ContainerRegistryURL: server.URL,
+			ContainerImageName:   "acme/app",
+			ContainerImageTag:    "1.0.0",
+		}
+		utils := newGolangBuildTestsUtils()
+		utils.AddFile("app-linux.arm64", []byte("binary"))
+		platform, _ := multiarch.ParsePlatformString("linux,arm64")
+
+		_, err := createGolangImage(&config, []docker.PlatformBinaries{golangImageBinaries(platform, []string{"app-linux.arm64"})}, utils, &golangBuildCommonPipelineEnvironment{})
+
+		assert.EqualError(t, err, "failed to create image: base image '"+baseTag.String()+"': is built for linux/amd64, not for linux/arm64")
+	})
+
+	t.Run("error - invalid registry url", func(t *testing.T) {
+		config := golangBuildOptions{ContainerRegistryURL: "registry", ContainerImageName: "acme/app", ContainerImageTag: "1.0.0"}
+
+		_, err := createGolangImage(&config, nil, newGolangBuildTestsUtils(), &golangBuildCommonPipelineEnvironment{})
+
+		assert.ErrorContains(t, err, "failed to read registry url registry")
+	})
+}
+
+func TestGolangImageBinaries(t *testing.T) {
+	platform, _ := multiarch.ParsePlatformString("linux,arm64")
+
+	binaries := golangImageBinaries(platform, []string{"out-linux.arm64", filepath.Join("out-linux-arm64", "server")})
+
+	assert.Equal(t, "linux", binaries.Platform.OS)
+	assert.Equal(t, "arm64", binaries.Platform.Architecture)
+	assert.Equal(t, []docker.Binary{{Path: "out-linux.arm64", Name: "out"}, {Path: filepath.Join("out-linux-arm64", "server"), Name: "server"}}, binaries.Binaries)
+}
+
 func TestIsMainPackageError(t *testing.T) {
 	utils := newGolangBuildTestsUtils()
 	utils.ShouldFailOnCommand = map[string]error{
@@ -9,6 +9,7 @@ import (
 	"strings"
 	"text/template"
 
+	"github.com/SAP/jenkins-library/pkg/buildcache"
 	"github.com/SAP/jenkins-library/pkg/buildsettings"
 	"github.com/SAP/jenkins-library/pkg/command"
 	"github.com/SAP/jenkins-library/pkg/gradle"
@@ -163,10 +164,12 @@ func newGradleExecuteBuildUtils() gradleExecuteBuildUtils {
 
 func gradleExecuteBuild(config gradleExecuteBuildOptions, telemetryData *telemetry.CustomData, pipelineEnv *gradleExecuteBuildCommonPipelineEnvironment) {
 	utils := newGradleExecuteBuildUtils()
+	cache := restoreBuildCache(buildcache.Gradle(), config.BuildCacheBackend, config.BuildCacheLocation, config.BuildCacheEndpoint, telemetryData)
 	err := runGradleExecuteBuild(&config, telemetryData, utils, pipelineEnv)
 	if err != nil {
 		log.Entry().WithError(err).Fatalf("step execution failed: %v", err)
 	}
+	saveBuildCache(cache, telemetryData)
 }
 
 func runGradleExecuteBuild(config *gradleExecuteBuildOptions, telemetryData *telemetry.CustomData, utils gradleExecuteBuildUtils, pipelineEnv *gradleExecuteBuildCommonPipelineEnvironment) error {
@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"crypto"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/pkg/errors"
+
+	"github.com/SAP/jenkins-library/pkg/cosign"
+	"github.com/SAP/jenkins-library/pkg/docker"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+)
+
+type imageSignatureUtils interface {
+	FileRead(path string) ([]byte, error)
+}
+
+type imageSignatureUtilsBundle struct {
+	*piperutils.Files
+}
+
+func newImageSignatureUtils() imageSignatureUtils {
+	return &imageSignatureUtilsBundle{
+		Files: &piperutils.Files{},
+	}
+}
+
+func imageSignature(config imageSignatureOptions, telemetryData *telemetry.CustomData) {
+	utils := newImageSignatureUtils()
+
+	err := runImageSignature(&config, telemetryData, utils)
+	if err != nil {
+		log.Entry().WithError(err).Fatal("step execution failed")
+	}
+}
+
+func runImageSignature(config *imageSignatureOptions, telemetryData *telemetry.CustomData, utils imageSignatureUtils) error {
+	var dockerConfig []byte
+	if len(config.DockerConfigJSON) > 0 {
+		var err error
+		if dockerConfig, err = utils.FileRead(config.DockerConfigJSON); err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return errors.Wrapf(err, "failed to read %v", config.DockerConfigJSON)
+		}
+	}
+	keychain, err := docker.NewKeychain(dockerConfig)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return err
+	}
+	options := []remote.Option{remote.WithAuthFromKeychain(keychain)}
+
+	images, err := imagesToSign(config, options)
+	if err != nil {
+		return err
+	}
+	if len(images) == 0 {
+		if config.Mode == "verify" {
+			// a verification without images must not pass a deployment unnoticed
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return fmt.Errorf("no images to verify found, neither in the common pipeline environment nor in the parameter images")
+		}
+		log.Entry().Warn("no images found, neither in the common pipeline environment nor in the parameter images")
+		return nil
+	}
+
+	if config.Mode == "verify" {
+		return verifyImageSignatures(config, images, utils, options)
+	}
+	return signImages(config, images, utils, options)
+}
+
+// imagesToSign returns the images built by the previous steps and the additionally configured images by digest.
+// Built images without digests in the common pipeline environment are resolved by their tags.
+func imagesToSign(config *imageSignatureOptions, options []remote.Option) ([]name.Digest, error) {
+	images := []name.Digest{}
+	built := builtImages(config.ContainerRegistryURL, config.ImageNameTags, config.ImageDigests)
+	for _, image := range built {
+		digest, err := name.NewDigest(image)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return nil, errors.Wrapf(err, "invalid image reference '%v'", image)
+		}
+		images = append(images, digest)
+	}
+	unresolved := []string{}
+	if len(built) == 0 {
+		for _, imageNameTag := range config.ImageNameTags {
+			unresolved = append(unresolved, registryImage(config.ContainerRegistryURL, imageNameTag))
+		}
+	}
+	for _, image := range append(unresolved, config.Images...) {
+		digest, err := cosign.ResolveDigest(strings.TrimSpace(image), options...)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return nil, err
+		}
+		images = append(images, digest)
+	}
+	return images, nil
+}
+
+func signImages(config *imageSignatureOptions, images []name.Digest, utils imageSignatureUtils, options []remote.Option) error {
+	key, err := utils.FileRead(config.SigningKey)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return errors.Wrap(err, "failed to read the signing key")
+	}
+	signer, err := cosign.LoadPrivateKey(key, config.SigningKeyPassword)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return err
+	}
+	for _, image := range images {
+		tag, err := cosign.Sign(image, signer, config.Annotations, options...)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorInfrastructure)
+			return err
+		}
+		log.Entry().Infof("signature of %v pushed to %v", image, tag)
+	}
+	return nil
+}
+
+func verifyImageSignatures(config *imageSignatureOptions, images []name.Digest, utils imageSignatureUtils, options []remote.Option) error {
+	trustedKeys := []crypto.PublicKey{}
+	for _, file := range config.VerificationKeys {
+		content, err := utils.FileRead(file)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return errors.Wrapf(err, "failed to read verification key %v", file)
+		}
+		keys, err := cosign.LoadPublicKeys(content)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return errors.Wrapf(err, "invalid verification key %v", file)
+		}
+		trustedKeys = append(trustedKeys, keys...)
+	}
+	if len(trustedKeys) == 0 {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return fmt.Errorf("no verification keys found")
+	}
+
+	unsigned := 0
+	for _, image := range images {
+		if err := cosign.Verify(image, trustedKeys, options...); err != nil {
+			log.Entry().WithError(err).Error("image signature verification failed")
+			unsigned++
+			continue
+		}
+		log.Entry().Infof("signature of %v verified", image)
+	}
+	if unsigned > 0 {
+		log.SetErrorCategory(log.ErrorCompliance)
+		return fmt.Errorf("%v of %v images lack a valid signature of a trusted key", unsigned, len(images))
+	}
+	return nil
+}
@@ -0,0 +1,350 @@
+// Code generated by piper's step-generator. DO NOT EDIT.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/gcp"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/splunk"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/SAP/jenkins-library/pkg/validation"
+	"github.com/spf13/cobra"
+)
+
+type imageSignatureOptions struct {
+	Mode                 string                 `json:"mode,omitempty" validate:"possible-values=sign verify"`
+	Images               []string               `json:"images,omitempty"`
+	ContainerRegistryURL string                 `json:"containerRegistryUrl,omitempty"`
+	ImageNameTags        []string               `json:"imageNameTags,omitempty"`
+	ImageDigests         []string               `json:"imageDigests,omitempty"`
+	SigningKey           string                 `json:"signingKey,omitempty" validate:"required_if=Mode sign"`
+	SigningKeyPassword   string                 `json:"signingKeyPassword,omitempty"`
+	Annotations          map[string]interface{} `json:"annotations,omitempty"`
+	VerificationKeys     []string               `json:"verificationKeys,omitempty" validate:"required_if=Mode verify"`
+	DockerConfigJSON     string                 `json:"dockerConfigJSON,omitempty"`
+}
+
+// ImageSignatureCommand Signs container images or verifies their signatures in the format of cosign.
+func ImageSignatureCommand() *cobra.Command {
+	const STEP_NAME = "imageSignature"
+
+	metadata := imageSignatureMetadata()
+	var stepConfig imageSignatureOptions
+	var startTime time.Time
+	var logCollector *log.CollectorHook
+	var splunkClient *splunk.Splunk
+	telemetryClient := &telemetry.Telemetry{}
+
+	var createImageSignatureCmd = &cobra.Command{
+		Use:   STEP_NAME,
+		Short: "Signs container images or verifies their signatures in the format of cosign.",
+		Long: `Signs container images or verifies their signatures without the need of external tools.
+The signatures are stored in the format of [cosign](https://github.com/sigstore/cosign), i.e. as ` + "`" + `sha256-<digest>.sig` + "`" + ` image next to the signed image,
+and can be verified with ` + "`" + `cosign verify --key cosign.pub --insecure-ignore-tlog <image>` + "`" + `.
+
+In mode ` + "`" + `sign` + "`" + ` the images built by a previous step, e.g. kanikoExecute or cnbBuild, are signed with the configured private key.
+Key pairs created with ` + "`" + `cosign generate-key-pair` + "`" + ` as well as unencrypted PEM encoded ECDSA, Ed25519 and RSA keys are supported.
+Keyless signing is not supported.
+
+In mode ` + "`" + `verify` + "`" + ` the step fails if an image lacks a valid signature of one of the trusted public keys.
+Use it before the deployment to ensure that only signed images are deployed.
+
+The images are taken from the common pipeline environment, i.e. ` + "`" + `container/imageNameTags` + "`" + ` and ` + "`" + `container/imageDigests` + "`" + ` as provided by the build steps,
+and can be extended by the parameter ` + "`" + `images` + "`" + `. Images without digests are resolved by their tags.
+In mode ` + "`" + `verify` + "`" + ` the step fails if no images are found.`,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			startTime = time.Now()
+			log.SetStepName(STEP_NAME)
+			log.SetVerbose(GeneralConfig.Verbose)
+
+			GeneralConfig.GitHubAccessTokens = ResolveAccessTokens(GeneralConfig.GitHubTokens)
+
+			path, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+			fatalHook := &log.FatalHook{CorrelationID: GeneralConfig.CorrelationID, Path: path}
+			log.RegisterHook(fatalHook)
+
+			err = PrepareConfig(cmd, &metadata, STEP_NAME, &stepConfig, config.OpenPiperFile)
+			if err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+
+			// Set step error patterns for improved error detection
+			stepErrors := make([]log.StepError, len(metadata.Metadata.Errors))
+			for i, err := range metadata.Metadata.Errors {
+				stepErrors[i] = log.StepError{
+					Pattern:  err.Pattern,
+					Message:  err.Message,
+					Category: err.Category,
+				}
+			}
+			log.SetStepErrors(stepErrors)
+			log.RegisterSecret(stepConfig.SigningKey)
+			log.RegisterSecret(stepConfig.SigningKeyPassword)
+			log.RegisterSecret(stepConfig.DockerConfigJSON)
+
+			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
+				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
+				log.RegisterHook(&sentryHook)
+			}
+
+			if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 || len(GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint) > 0 {
+				splunkClient = &splunk.Splunk{}
+				logCollector = &log.CollectorHook{CorrelationID: GeneralConfig.CorrelationID}
+				log.RegisterHook(logCollector)
+			}
+
+			if err = log.RegisterANSHookIfConfigured(GeneralConfig.CorrelationID); err != nil {
+				log.Entry().WithError(err).Warn("failed to set up SAP Alert Notification Service log hook")
+			}
+
+			validation, err := validation.New(validation.WithJSONNamesForStructFields(), validation.WithPredefinedErrorMessages())
+			if err != nil {
+				return err
+			}
+			if err = validation.ValidateStruct(stepConfig); err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+
+			return nil
+		},
+		Run: func(_ *cobra.Command, _ []string) {
+			vaultClient := config.GlobalVaultClient()
+			if vaultClient != nil {
+				defer vaultClient.MustRevokeToken()
+			}
+
+			stepTelemetryData := telemetry.CustomData{}
+			stepTelemetryData.ErrorCode = "1"
+			handler := func() {
+				config.RemoveVaultSecretFiles()
+				stepTelemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
+				stepTelemetryData.ErrorCategory = log.GetErrorCategory().String()
+				stepTelemetryData.PiperCommitHash = GitCommit
+				telemetryClient.SetData(&stepTelemetryData)
+				telemetryClient.LogStepTelemetryData()
+				if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+					splunkClient.Initialize(GeneralConfig.CorrelationID,
+						GeneralConfig.HookConfig.SplunkConfig.Dsn,
+						GeneralConfig.HookConfig.SplunkConfig.Token,
+						GeneralConfig.HookConfig.SplunkConfig.Index,
+						GeneralConfig.HookConfig.SplunkConfig.SendLogs)
+					splunkClient.Send(telemetryClient.GetData(), logCollector)
+				}
+				if len(GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint) > 0 {
+					splunkClient.Initialize(GeneralConfig.CorrelationID,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblToken,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblIndex,
+						GeneralConfig.HookConfig.SplunkConfig.SendLogs)
+					splunkClient.Send(telemetryClient.GetData(), logCollector)
+				}
+				if GeneralConfig.HookConfig.GCPPubSubConfig.Enabled {
+					err := gcp.NewGcpPubsubClient(
+						vaultClient,
+						GeneralConfig.HookConfig.GCPPubSubConfig.ProjectNumber,
+						GeneralConfig.HookConfig.GCPPubSubConfig.IdentityPool,
+						GeneralConfig.HookConfig.GCPPubSubConfig.IdentityProvider,
+						GeneralConfig.CorrelationID,
+						GeneralConfig.HookConfig.OIDCConfig.RoleID,
+					).Publish(GeneralConfig.HookConfig.GCPPubSubConfig.Topic, telemetryClient.GetDataBytes())
+					if err != nil {
+						log.Entry().WithError(err).Warn("event publish failed")
+					}
+				}
+			}
+			log.DeferExitHandler(handler)
+			defer handler()
+			telemetryClient.Initialize(STEP_NAME)
+			imageSignature(stepConfig, &stepTelemetryData)
+			stepTelemetryData.ErrorCode = "0"
+			log.Entry().Info("SUCCESS")
+		},
+	}
+
+	addImageSignatureFlags(createImageSignatureCmd, &stepConfig)
+	return createImageSignatureCmd
+}
+
+func addImageSignatureFlags(cmd *cobra.Command, stepConfig *imageSignatureOptions) {
+	cmd.Flags().StringVar(&stepConfig.Mode, "mode", `sign`, "Defines whether the images are signed (`sign`) or whether their signatures are verified (`verify`).")
+	cmd.Flags().StringSliceVar(&stepConfig.Images, "images", []string{}, "List of additional images referenced by tag or digest, e.g. `my.registry.com/acme/app:1.0.0`.")
+	cmd.Flags().StringVar(&stepConfig.ContainerRegistryURL, "containerRegistryUrl", os.Getenv("PIPER_containerRegistryUrl"), "http(s) url of the Container registry the images have been pushed to.")
+	cmd.Flags().StringSliceVar(&stepConfig.ImageNameTags, "imageNameTags", []string{}, "List of the images (name and tag, without registry) which have been built, typically provided by the build step.")
+	cmd.Flags().StringSliceVar(&stepConfig.ImageDigests, "imageDigests", []string{}, "List of the digests of the built images, typically provided by the build step.")
+	cmd.Flags().StringVar(&stepConfig.SigningKey, "signingKey", os.Getenv("PIPER_signingKey"), "Path to the private key the images are signed with, either created with `cosign generate-key-pair` or a PEM encoded ECDSA, Ed25519 or RSA key.")
+	cmd.Flags().StringVar(&stepConfig.SigningKeyPassword, "signingKeyPassword", os.Getenv("PIPER_signingKeyPassword"), "Password of the private key created with `cosign generate-key-pair`.")
+
+	cmd.Flags().StringSliceVar(&stepConfig.VerificationKeys, "verificationKeys", []string{}, "List of paths to the PEM encoded public keys, e.g. `cosign.pub`, of the trusted signers. An image is accepted if it is signed by one of the keys.")
+	cmd.Flags().StringVar(&stepConfig.DockerConfigJSON, "dockerConfigJSON", os.Getenv("PIPER_dockerConfigJSON"), "Path to the file `.docker/config.json` containing the credentials of the registries - this is typically provided by your CI/CD system.")
+
+}
+
+// retrieve step metadata
+func imageSignatureMetadata() config.StepData {
+	var theMetaData = config.StepData{
+		Metadata: config.StepMetadata{
+			Name:        "imageSignature",
+			Aliases:     []config.Alias{},
+			Description: "Signs container images or verifies their signatures in the format of cosign.",
+		},
+		Spec: config.StepSpec{
+			Inputs: config.StepInputs{
+				Secrets: []config.StepSecrets{
+					{Name: "dockerConfigJsonCredentialsId", Description: "Jenkins 'Secret file' credentials ID containing Docker config.json (with registry credential(s)).", Type: "jenkins"},
+					{Name: "signingKeyCredentialsId", Description: "Jenkins 'Secret file' credentials ID containing the private key the images are signed with.", Type: "jenkins"},
+					{Name: "signingKeyPasswordCredentialsId", Description: "Jenkins 'Secret text' credentials ID containing the password of the private key.", Type: "jenkins"},
+				},
+				Parameters: []config.StepParameters{
+					{
+						Name:        "mode",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `sign`,
+					},
+					{
+						Name:        "images",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+					{
+						Name: "containerRegistryUrl",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "container/registryUrl",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{{Name: "dockerRegistryUrl"}},
+						Default:   os.Getenv("PIPER_containerRegistryUrl"),
+					},
+					{
+						Name: "imageNameTags",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "container/imageNameTags",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "[]string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   []string{},
+					},
+					{
+						Name: "imageDigests",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "container/imageDigests",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "[]string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   []string{},
+					},
+					{
+						Name: "signingKey",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "signingKeyCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:    "signingKeyVaultSecretName",
+								Type:    "vaultSecretFile",
+								Default: "cosign-signing-key",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_signingKey"),
+					},
+					{
+						Name: "signingKeyPassword",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "signingKeyPasswordCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:    "signingKeyPasswordVaultSecretName",
+								Type:    "vaultSecret",
+								Default: "cosign-signing-key-password",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_signingKeyPassword"),
+					},
+					{
+						Name:        "annotations",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "map[string]interface{}",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "verificationKeys",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+					{
+						Name: "dockerConfigJSON",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "dockerConfigJsonCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:    "dockerConfigFileVaultSecretName",
+								Type:    "vaultSecretFile",
+								Default: "docker-config",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_dockerConfigJSON"),
+					},
+				},
+			},
+		},
+	}
+	return theMetaData
+}
@@ -0,0 +1,139 @@
+//go:build unit
+// +build unit
+
+package cmd
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunImageSignature(t *testing.T) {
+	server := httptest.NewServer(registry.New())
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+	pushImage := func(t *testing.T, repository string) string {
+		image, err := random.Image(64, 1)
+		require.NoError(t, err)
+		tag, _ := name.NewTag(host + "/" + repository + ":1.0.0")
+		require.NoError(t, remote.Write(tag, image))
+		digest, err := image.Digest()
+		require.NoError(t, err)
+		return digest.String()
+	}
+
+	trusted, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	other, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	newUtils := func() *mock.FilesMock {
+		utils := &mock.FilesMock{}
+		for file, key := range map[string]*ecdsa.PrivateKey{"trusted": trusted, "other": other} {
+			der, _ := x509.MarshalECPrivateKey(key)
+			utils.AddFile(file+".key", pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}))
+			pub, _ := x509.MarshalPKIXPublicKey(key.Public())
+			utils.AddFile(file+".pub", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pub}))
+		}
+		return utils
+	}
+
+	t.Run("images of the common pipeline environment are signed and verified", func(t *testing.T) {
+		digest := pushImage(t, "acme/app")
+		utils := newUtils()
+		config := imageSignatureOptions{
+			Mode:                 "sign",
+			ContainerRegistryURL: server.URL,
+			ImageNameTags:        []string{"acme/app:1.0.0"},
+			ImageDigests:         []string{digest},
+			SigningKey:           "trusted.key",
+		}
+
+		require.NoError(t, runImageSignature(&config, nil, utils))
+
+		config.Mode = "verify"
+		config.VerificationKeys = []string{"other.pub", "trusted.pub"}
+		assert.NoError(t, runImageSignature(&config, nil, utils))
+	})
+
+	t.Run("verification fails for images without trusted signature", func(t *testing.T) {
+		signedDigest := pushImage(t, "acme/signed")
+		pushImage(t, "acme/unsigned")
+		utils := newUtils()
+		sign := imageSignatureOptions{
+			Mode:                 "sign",
+			ContainerRegistryURL: server.URL,
+			ImageNameTags:        []string{"acme/signed:1.0.0"},
+			ImageDigests:         []string{signedDigest},
+			SigningKey:           "other.key",
+		}
+		require.NoError(t, runImageSignature(&sign, nil, utils))
+		verify := imageSignatureOptions{
+			Mode:             "verify",
+			Images:           []string{host + "/acme/signed:1.0.0", host + "/acme/unsigned:1.0.0"},
+			VerificationKeys: []string{"trusted.pub"},
+		}
+
+		err := runImageSignature(&verify, nil, utils)
+
+		assert.EqualError(t, err, "2 of 2 images lack a valid signature of a trusted key")
+	})
+
+	t.Run("without images nothing is done", func(t *testing.T) {
+		config := imageSignatureOptions{Mode: "sign", SigningKey: "missing.key"}
+
+		assert.NoError(t, runImageSignature(&config, nil, newUtils()))
+	})
+
+	t.Run("verification without images fails", func(t *testing.T) {
+		config := imageSignatureOptions{Mode: "verify", VerificationKeys: []string{"trusted.pub"}}
+
+		err := runImageSignature(&config, nil, newUtils())
+
+		assert.EqualError(t, err, "no images to verify found, neither in the common pipeline environment nor in the parameter images")
+	})
+
+	t.Run("built images without digests are resolved by their tags", func(t *testing.T) {
+		pushImage(t, "acme/tagonly")
+		utils := newUtils()
+		config := imageSignatureOptions{
+			Mode:                 "sign",
+			ContainerRegistryURL: server.URL,
+			ImageNameTags:        []string{"acme/tagonly:1.0.0"},
+			SigningKey:           "trusted.key",
+		}
+
+		require.NoError(t, runImageSignature(&config, nil, utils))
+
+		verify := imageSignatureOptions{Mode: "verify", Images: []string{host + "/acme/tagonly:1.0.0"}, VerificationKeys: []string{"trusted.pub"}}
+		assert.NoError(t, runImageSignature(&verify, nil, utils))
+	})
+
+	t.Run("missing signing key", func(t *testing.T) {
+		pushImage(t, "acme/nokey")
+		config := imageSignatureOptions{Mode: "sign", Images: []string{host + "/acme/nokey:1.0.0"}, SigningKey: "missing.key"}
+
+		err := runImageSignature(&config, nil, newUtils())
+
+		assert.ErrorContains(t, err, "failed to read the signing key")
+	})
+
+	t.Run("unknown image", func(t *testing.T) {
+		config := imageSignatureOptions{Mode: "verify", Images: []string{host + "/acme/unknown:1.0.0"}, VerificationKeys: []string{"trusted.pub"}}
+
+		err := runImageSignature(&config, nil, newUtils())
+
+		assert.ErrorContains(t, err, "failed to resolve image")
+	})
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/cpi"
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/pkg/errors"
+)
+
+const integrationArtifactDeployMultiTenantReport = "integrationArtifactDeployMultiTenant_results.json"
+
+type integrationArtifactDeployMultiTenantUtils interface {
+	FileWrite(path string, content []byte, perm os.FileMode) error
+	WriteFile(path string, content []byte, perm os.FileMode) error
+}
+
+func integrationArtifactDeployMultiTenant(config integrationArtifactDeployMultiTenantOptions, telemetryData *telemetry.CustomData) {
+	fileUtils := &piperutils.Files{}
+	newHTTPClient := func() piperhttp.Sender { return &piperhttp.Client{} }
+
+	err := runIntegrationArtifactDeployMultiTenant(&config, newHTTPClient, fileUtils)
+	if err != nil {
+		log.Entry().WithError(err).Fatal("step execution failed")
+	}
+}
+
+func runIntegrationArtifactDeployMultiTenant(config *integrationArtifactDeployMultiTenantOptions, newHTTPClient func() piperhttp.Sender, utils integrationArtifactDeployMultiTenantUtils) error {
+	tenants, err := cpi.ParseTenants(config.Tenants)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return err
+	}
+	parameters := map[string]string{}
+	for key, value := range config.Parameters {
+		parameters[key] = fmt.Sprint(value)
+	}
+
+	deployment := cpi.MultiTenantDeployment{
+		IntegrationFlowID:      config.IntegrationFlowID,
+		IntegrationFlowVersion: config.IntegrationFlowVersion,
+		Parameters:             parameters,
+		MaxConcurrency:         config.MaxConcurrency,
+		PollInterval:           time.Duration(config.PollIntervalInSeconds) * time.Second,
+		Timeout:                time.Duration(config.DeploymentTimeoutInMinutes) * time.Minute,
+		NewHTTPClient:          newHTTPClient,
+	}
+	log.Entry().Infof("Deploying integration flow %v to %v tenants", config.IntegrationFlowID, len(tenants))
+	results := deployment.Run(tenants)
+	for _, result := range results {
+		log.Entry().Info(result.String())
+	}
+
+	if err := writeIntegrationArtifactDeployMultiTenantReport(utils, results); err != nil {
+		return err
+	}
+
+	if failed := cpi.FailedTenants(results); len(failed) > 0 {
+		log.SetErrorCategory(log.ErrorService)
+		return errors.Errorf("deployment of integration flow %v failed on %v of %v tenants: %v", config.IntegrationFlowID, len(failed), len(results), strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+func writeIntegrationArtifactDeployMultiTenantReport(utils integrationArtifactDeployMultiTenantUtils, results []cpi.TenantResult) error {
+	report, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to create deployment report")
+	}
+	if err := utils.FileWrite(integrationArtifactDeployMultiTenantReport, report, 0666); err != nil {
+		return errors.Wrapf(err, "failed to write %v", integrationArtifactDeployMultiTenantReport)
+	}
+	piperutils.PersistReportsAndLinks("integrationArtifactDeployMultiTenant", "", utils, []piperutils.Path{{Name: "Integration Flow Deployment Results", Target: integrationArtifactDeployMultiTenantReport}}, nil)
+	return nil
+}
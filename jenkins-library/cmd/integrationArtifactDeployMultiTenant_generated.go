@@ -0,0 +1,322 @@
+// Code generated by piper's step-generator. DO NOT EDIT.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/gcp"
+	"github.com/SAP/jenkins-library/pkg/gcs"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/splunk"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/SAP/jenkins-library/pkg/validation"
+	"github.com/bmatcuk/doublestar"
+	"github.com/spf13/cobra"
+)
+
+type integrationArtifactDeployMultiTenantOptions struct {
+	Tenants                    string                 `json:"tenants,omitempty"`
+	IntegrationFlowID          string                 `json:"integrationFlowId,omitempty"`
+	IntegrationFlowVersion     string                 `json:"integrationFlowVersion,omitempty"`
+	Parameters                 map[string]interface{} `json:"parameters,omitempty"`
+	MaxConcurrency             int                    `json:"maxConcurrency,omitempty"`
+	PollIntervalInSeconds      int                    `json:"pollIntervalInSeconds,omitempty"`
+	DeploymentTimeoutInMinutes int                    `json:"deploymentTimeoutInMinutes,omitempty"`
+}
+
+type integrationArtifactDeployMultiTenantReports struct {
+}
+
+func (p *integrationArtifactDeployMultiTenantReports) persist(stepConfig integrationArtifactDeployMultiTenantOptions, gcpJsonKeyFilePath string, gcsBucketId string, gcsFolderPath string, gcsSubFolder string) {
+	if gcsBucketId == "" {
+		log.Entry().Info("persisting reports to GCS is disabled, because gcsBucketId is empty")
+		return
+	}
+	log.Entry().Info("Uploading reports to Google Cloud Storage...")
+	content := []gcs.ReportOutputParam{
+		{FilePattern: "integrationArtifactDeployMultiTenant_results.json", ParamRef: "", StepResultType: "integration-deployment-results"},
+	}
+
+	gcsClient, err := gcs.NewClient(gcpJsonKeyFilePath, "")
+	if err != nil {
+		log.Entry().Errorf("creation of GCS client failed: %v", err)
+		return
+	}
+	defer gcsClient.Close()
+	structVal := reflect.ValueOf(&stepConfig).Elem()
+	inputParameters := map[string]string{}
+	for i := 0; i < structVal.NumField(); i++ {
+		field := structVal.Type().Field(i)
+		if field.Type.String() == "string" {
+			paramName := strings.Split(field.Tag.Get("json"), ",")
+			paramValue, _ := structVal.Field(i).Interface().(string)
+			inputParameters[paramName[0]] = paramValue
+		}
+	}
+	if err := gcs.PersistReportsToGCS(gcsClient, content, inputParameters, gcsFolderPath, gcsBucketId, gcsSubFolder, doublestar.Glob, os.Stat); err != nil {
+		log.Entry().Errorf("failed to persist reports: %v", err)
+	}
+}
+
+// IntegrationArtifactDeployMultiTenantCommand Configure and deploy a CPI integration flow on several tenants in parallel
+func IntegrationArtifactDeployMultiTenantCommand() *cobra.Command {
+	const STEP_NAME = "integrationArtifactDeployMultiTenant"
+
+	metadata := integrationArtifactDeployMultiTenantMetadata()
+	var stepConfig integrationArtifactDeployMultiTenantOptions
+	var startTime time.Time
+	var reports integrationArtifactDeployMultiTenantReports
+	var logCollector *log.CollectorHook
+	var splunkClient *splunk.Splunk
+	telemetryClient := &telemetry.Telemetry{}
+
+	var createIntegrationArtifactDeployMultiTenantCmd = &cobra.Command{
+		Use:   STEP_NAME,
+		Short: "Configure and deploy a CPI integration flow on several tenants in parallel",
+		Long: `With this step you can deploy the same integration flow to several SAP Cloud Integration tenants, e.g. to development, test and customer tenants, in one step instead of calling [integrationArtifactUpdateConfiguration](integrationArtifactUpdateConfiguration.md) and [integrationArtifactDeploy](integrationArtifactDeploy.md) for every tenant.
+
+For every tenant the step updates the externalized parameters of the integration flow, deploys it and polls the deployment status until the deployment is finished. The externalized parameters in ` + "`" + `parameters` + "`" + ` are set on all tenants, tenant specific values in the list of tenants take precedence. Up to ` + "`" + `maxConcurrency` + "`" + ` tenants are processed in parallel. A failing tenant does not stop the deployment to the other tenants, but the step fails at the end if the deployment to any tenant failed.
+
+The results of all tenants including the error details of failed deployments are written to ` + "`" + `integrationArtifactDeployMultiTenant_results.json` + "`" + ` which is archived as report of the step.
+Learn more about the SAP Cloud Integration remote API for deploying an integration artifact [here](https://help.sap.com/viewer/368c481cd6954bdfa5d0435479fd4eaf/Cloud/en-US/d1679a80543f46509a7329243b595bdb.html).`,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			startTime = time.Now()
+			log.SetStepName(STEP_NAME)
+			log.SetVerbose(GeneralConfig.Verbose)
+
+			GeneralConfig.GitHubAccessTokens = ResolveAccessTokens(GeneralConfig.GitHubTokens)
+
+			path, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+			fatalHook := &log.FatalHook{CorrelationID: GeneralConfig.CorrelationID, Path: path}
+			log.RegisterHook(fatalHook)
+
+			err = PrepareConfig(cmd, &metadata, STEP_NAME, &stepConfig, config.OpenPiperFile)
+			if err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+
+			// Set step error patterns for improved error detection
+			stepErrors := make([]log.StepError, len(metadata.Metadata.Errors))
+			for i, err := range metadata.Metadata.Errors {
+				stepErrors[i] = log.StepError{
+					Pattern:  err.Pattern,
+					Message:  err.Message,
+					Category: err.Category,
+				}
+			}
+			log.SetStepErrors(stepErrors)
+			log.RegisterSecret(stepConfig.Tenants)
+
+			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
+				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
+				log.RegisterHook(&sentryHook)
+			}
+
+			if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 || len(GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint) > 0 {
+				splunkClient = &splunk.Splunk{}
+				logCollector = &log.CollectorHook{CorrelationID: GeneralConfig.CorrelationID}
+				log.RegisterHook(logCollector)
+			}
+
+			if err = log.RegisterANSHookIfConfigured(GeneralConfig.CorrelationID); err != nil {
+				log.Entry().WithError(err).Warn("failed to set up SAP Alert Notification Service log hook")
+			}
+
+			validation, err := validation.New(validation.WithJSONNamesForStructFields(), validation.WithPredefinedErrorMessages())
+			if err != nil {
+				return err
+			}
+			if err = validation.ValidateStruct(stepConfig); err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+
+			return nil
+		},
+		Run: func(_ *cobra.Command, _ []string) {
+			vaultClient := config.GlobalVaultClient()
+			if vaultClient != nil {
+				defer vaultClient.MustRevokeToken()
+			}
+
+			stepTelemetryData := telemetry.CustomData{}
+			stepTelemetryData.ErrorCode = "1"
+			handler := func() {
+				reports.persist(stepConfig, GeneralConfig.GCPJsonKeyFilePath, GeneralConfig.GCSBucketId, GeneralConfig.GCSFolderPath, GeneralConfig.GCSSubFolder)
+				config.RemoveVaultSecretFiles()
+				stepTelemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
+				stepTelemetryData.ErrorCategory = log.GetErrorCategory().String()
+				stepTelemetryData.PiperCommitHash = GitCommit
+				telemetryClient.SetData(&stepTelemetryData)
+				telemetryClient.LogStepTelemetryData()
+				if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+					splunkClient.Initialize(GeneralConfig.CorrelationID,
+						GeneralConfig.HookConfig.SplunkConfig.Dsn,
+						GeneralConfig.HookConfig.SplunkConfig.Token,
+						GeneralConfig.HookConfig.SplunkConfig.Index,
+						GeneralConfig.HookConfig.SplunkConfig.SendLogs)
+					splunkClient.Send(telemetryClient.GetData(), logCollector)
+				}
+				if len(GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint) > 0 {
+					splunkClient.Initialize(GeneralConfig.CorrelationID,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblToken,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblIndex,
+						GeneralConfig.HookConfig.SplunkConfig.SendLogs)
+					splunkClient.Send(telemetryClient.GetData(), logCollector)
+				}
+				if GeneralConfig.HookConfig.GCPPubSubConfig.Enabled {
+					err := gcp.NewGcpPubsubClient(
+						vaultClient,
+						GeneralConfig.HookConfig.GCPPubSubConfig.ProjectNumber,
+						GeneralConfig.HookConfig.GCPPubSubConfig.IdentityPool,
+						GeneralConfig.HookConfig.GCPPubSubConfig.IdentityProvider,
+						GeneralConfig.CorrelationID,
+						GeneralConfig.HookConfig.OIDCConfig.RoleID,
+					).Publish(GeneralConfig.HookConfig.GCPPubSubConfig.Topic, telemetryClient.GetDataBytes())
+					if err != nil {
+						log.Entry().WithError(err).Warn("event publish failed")
+					}
+				}
+			}
+			log.DeferExitHandler(handler)
+			defer handler()
+			telemetryClient.Initialize(STEP_NAME)
+			integrationArtifactDeployMultiTenant(stepConfig, &stepTelemetryData)
+			stepTelemetryData.ErrorCode = "0"
+			log.Entry().Info("SUCCESS")
+		},
+	}
+
+	addIntegrationArtifactDeployMultiTenantFlags(createIntegrationArtifactDeployMultiTenantCmd, &stepConfig)
+	return createIntegrationArtifactDeployMultiTenantCmd
+}
+
+func addIntegrationArtifactDeployMultiTenantFlags(cmd *cobra.Command, stepConfig *integrationArtifactDeployMultiTenantOptions) {
+	cmd.Flags().StringVar(&stepConfig.Tenants, "tenants", os.Getenv("PIPER_tenants"), "JSON list of the tenants. Every tenant has a unique `name`, the `apiServiceKey` of the Process Integration Runtime service instance of plan 'api' as JSON object or string and optional tenant specific externalized `parameters`.\nExample: `[{\"name\": \"dev\", \"apiServiceKey\": {...}}, {\"name\": \"customer1\", \"apiServiceKey\": {...}, \"parameters\": {\"Endpoint\": \"https://customer1.example.com\"}}]`\n")
+	cmd.Flags().StringVar(&stepConfig.IntegrationFlowID, "integrationFlowId", os.Getenv("PIPER_integrationFlowId"), "Specifies the ID of the Integration Flow artifact")
+	cmd.Flags().StringVar(&stepConfig.IntegrationFlowVersion, "integrationFlowVersion", `active`, "Specifies the version of the Integration Flow artifact whose configuration is updated")
+
+	cmd.Flags().IntVar(&stepConfig.MaxConcurrency, "maxConcurrency", 4, "Maximum number of tenants which are configured and deployed in parallel")
+	cmd.Flags().IntVar(&stepConfig.PollIntervalInSeconds, "pollIntervalInSeconds", 10, "Interval in seconds between two requests of the deployment status")
+	cmd.Flags().IntVar(&stepConfig.DeploymentTimeoutInMinutes, "deploymentTimeoutInMinutes", 10, "Maximum time in minutes to wait for the deployment on a tenant to finish")
+
+	cmd.MarkFlagRequired("tenants")
+	cmd.MarkFlagRequired("integrationFlowId")
+}
+
+// retrieve step metadata
+func integrationArtifactDeployMultiTenantMetadata() config.StepData {
+	var theMetaData = config.StepData{
+		Metadata: config.StepMetadata{
+			Name:        "integrationArtifactDeployMultiTenant",
+			Aliases:     []config.Alias{},
+			Description: "Configure and deploy a CPI integration flow on several tenants in parallel",
+		},
+		Spec: config.StepSpec{
+			Inputs: config.StepInputs{
+				Secrets: []config.StepSecrets{
+					{Name: "cpiTenantsCredentialsId", Description: "Jenkins secret text credential ID containing the list of tenants with their service keys, see parameter `tenants`", Type: "jenkins"},
+				},
+				Parameters: []config.StepParameters{
+					{
+						Name: "tenants",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "cpiTenantsCredentialsId",
+								Param: "tenants",
+								Type:  "secret",
+							},
+
+							{
+								Name:    "cpiTenantsVaultSecretName",
+								Type:    "vaultSecret",
+								Default: "cpi-tenants",
+							},
+						},
+						Scope:     []string{"PARAMETERS"},
+						Type:      "string",
+						Mandatory: true,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_tenants"),
+					},
+					{
+						Name:        "integrationFlowId",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "GENERAL", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   true,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_integrationFlowId"),
+					},
+					{
+						Name:        "integrationFlowVersion",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "GENERAL", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `active`,
+					},
+					{
+						Name:        "parameters",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "map[string]interface{}",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "maxConcurrency",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     4,
+					},
+					{
+						Name:        "pollIntervalInSeconds",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     10,
+					},
+					{
+						Name:        "deploymentTimeoutInMinutes",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     10,
+					},
+				},
+			},
+			Outputs: config.StepOutputs{
+				Resources: []config.StepResources{
+					{
+						Name: "reports",
+						Type: "reports",
+						Parameters: []map[string]interface{}{
+							{"filePattern": "integrationArtifactDeployMultiTenant_results.json", "type": "integration-deployment-results"},
+						},
+					},
+				},
+			},
+		},
+	}
+	return theMetaData
+}
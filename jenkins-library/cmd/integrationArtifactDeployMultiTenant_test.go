@@ -0,0 +1,94 @@
+//go:build unit
+// +build unit
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/cpi"
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunIntegrationArtifactDeployMultiTenant(t *testing.T) {
+	t.Parallel()
+
+	newHTTPClient := func() piperhttp.Sender { return &piperhttp.Client{} }
+	newConfig := func(tenants string) *integrationArtifactDeployMultiTenantOptions {
+		return &integrationArtifactDeployMultiTenantOptions{
+			Tenants:                    tenants,
+			IntegrationFlowID:          "Flow1",
+			IntegrationFlowVersion:     "active",
+			Parameters:                 map[string]interface{}{"Endpoint": "https://default.example.com", "Retries": 3},
+			MaxConcurrency:             2,
+			DeploymentTimeoutInMinutes: 1,
+		}
+	}
+	readReport := func(t *testing.T, utils *mock.FilesMock) []cpi.TenantResult {
+		content, err := utils.FileRead(integrationArtifactDeployMultiTenantReport)
+		require.NoError(t, err)
+		var results []cpi.TenantResult
+		require.NoError(t, json.Unmarshal(content, &results))
+		return results
+	}
+
+	t.Run("deploys to all tenants", func(t *testing.T) {
+		t.Parallel()
+		dev := newIntegrationPackageServer(t)
+		customer := newIntegrationPackageServer(t)
+		customer.DeployingPolls = 1
+		tenants := fmt.Sprintf(`[{"name": "dev", "apiServiceKey": %v}, {"name": "customer", "apiServiceKey": %v, "parameters": {"Endpoint": "https://customer.example.com"}}]`, dev.ServiceKey(), customer.ServiceKey())
+		utils := &mock.FilesMock{}
+
+		err := runIntegrationArtifactDeployMultiTenant(newConfig(tenants), newHTTPClient, utils)
+
+		require.NoError(t, err)
+		value, _ := dev.Configuration("Flow1", "Endpoint")
+		assert.Equal(t, "https://default.example.com", value)
+		value, _ = dev.Configuration("Flow1", "Retries")
+		assert.Equal(t, "3", value)
+		value, _ = customer.Configuration("Flow1", "Endpoint")
+		assert.Equal(t, "https://customer.example.com", value)
+		assert.Equal(t, []string{"IntegrationFlow/Flow1"}, customer.Deployed)
+
+		results := readReport(t, utils)
+		require.Len(t, results, 2)
+		assert.Equal(t, "dev", results[0].Tenant)
+		assert.Equal(t, cpi.TenantSuccess, results[0].Status)
+		assert.Equal(t, "customer", results[1].Tenant)
+		assert.Equal(t, "SUCCESS", results[1].DeploymentStatus)
+	})
+
+	t.Run("failed deployment on one tenant", func(t *testing.T) {
+		t.Parallel()
+		dev := newIntegrationPackageServer(t)
+		customer := newIntegrationPackageServer(t)
+		customer.DeployErrors["Flow1"] = "Receiver not reachable"
+		tenants := fmt.Sprintf(`[{"name": "dev", "apiServiceKey": %v}, {"name": "customer", "apiServiceKey": %v}]`, dev.ServiceKey(), customer.ServiceKey())
+		utils := &mock.FilesMock{}
+
+		err := runIntegrationArtifactDeployMultiTenant(newConfig(tenants), newHTTPClient, utils)
+
+		assert.EqualError(t, err, "deployment of integration flow Flow1 failed on 1 of 2 tenants: customer")
+		assert.Equal(t, []string{"IntegrationFlow/Flow1"}, dev.Deployed)
+		results := readReport(t, utils)
+		assert.Equal(t, cpi.TenantSuccess, results[0].Status)
+		assert.Equal(t, cpi.TenantFailure, results[1].Status)
+		assert.Equal(t, "Receiver not reachable", results[1].ErrorDetails)
+	})
+
+	t.Run("invalid tenants", func(t *testing.T) {
+		t.Parallel()
+		utils := &mock.FilesMock{}
+
+		err := runIntegrationArtifactDeployMultiTenant(newConfig(`[{"name": "dev"}]`), newHTTPClient, utils)
+
+		assert.EqualError(t, err, "apiServiceKey of tenant 'dev' is missing")
+		assert.False(t, utils.HasWrittenFile(integrationArtifactDeployMultiTenantReport))
+	})
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/SAP/jenkins-library/pkg/cpi"
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/pkg/errors"
+)
+
+const integrationPackageDriftReport = "integrationPackageSync_drift.json"
+
+type integrationPackageSyncUtils interface {
+	FileWrite(path string, content []byte, perm os.FileMode) error
+	WriteFile(path string, content []byte, perm os.FileMode) error
+}
+
+func integrationPackageSync(config integrationPackageSyncOptions, telemetryData *telemetry.CustomData) {
+	httpClient := &piperhttp.Client{}
+	fileUtils := &piperutils.Files{}
+
+	err := runIntegrationPackageSync(&config, httpClient, fileUtils)
+	if err != nil {
+		log.Entry().WithError(err).Fatal("step execution failed")
+	}
+}
+
+func runIntegrationPackageSync(config *integrationPackageSyncOptions, httpClient piperhttp.Sender, utils integrationPackageSyncUtils) error {
+	artifactTypes := []cpi.ArtifactType{}
+	for _, name := range config.ArtifactTypes {
+		artifactType, err := cpi.GetArtifactType(name)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return err
+		}
+		artifactTypes = append(artifactTypes, artifactType)
+	}
+
+	serviceKey, err := cpi.ReadCpiServiceKey(config.APIServiceKey)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return err
+	}
+	client, err := cpi.NewDesigntimeClient(serviceKey, httpClient)
+	if err != nil {
+		return err
+	}
+
+	if config.Mode == "download" {
+		descriptor, err := cpi.DownloadPackage(client, config.PackageID, artifactTypes, config.SourceDirectory)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorService)
+			return errors.Wrapf(err, "failed to download integration package %v", config.PackageID)
+		}
+		log.Entry().Infof("Downloaded %v artifacts of integration package %v into %v", len(descriptor.Artifacts), config.PackageID, config.SourceDirectory)
+		return nil
+	}
+
+	drifts, err := cpi.ComparePackage(client, config.PackageID, artifactTypes, config.SourceDirectory)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorService)
+		return errors.Wrapf(err, "failed to compare integration package %v", config.PackageID)
+	}
+	driftDetected := logIntegrationPackageDrift(drifts)
+	if err := writeIntegrationPackageDriftReport(utils, drifts); err != nil {
+		return err
+	}
+
+	switch config.Mode {
+	case "upload":
+		uploaded, err := cpi.UploadPackage(client, config.PackageID, drifts, config.SourceDirectory, config.Deploy)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorService)
+			return errors.Wrapf(err, "failed to upload integration package %v", config.PackageID)
+		}
+		log.Entry().Infof("Uploaded %v changed artifacts of integration package %v", len(uploaded), config.PackageID)
+	case "detectDrift":
+		if driftDetected && config.FailOnDrift {
+			log.SetErrorCategory(log.ErrorCompliance)
+			return errors.Errorf("integration package %v on the tenant differs from %v", config.PackageID, config.SourceDirectory)
+		}
+	default:
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return errors.Errorf("unsupported mode '%v'", config.Mode)
+	}
+	return nil
+}
+
+// logIntegrationPackageDrift logs the artifacts which differ and returns whether any artifact differs
+func logIntegrationPackageDrift(drifts []cpi.ArtifactDrift) bool {
+	driftDetected := false
+	for _, drift := range drifts {
+		if drift.State == cpi.DriftUnchanged {
+			log.Entry().Debugf("%v artifact %v is unchanged", drift.Type, drift.ID)
+			continue
+		}
+		driftDetected = true
+		log.Entry().WithField("state", drift.State).Infof("%v artifact %v differs from the tenant", drift.Type, drift.ID)
+	}
+	if !driftDetected {
+		log.Entry().Info("No drift between source directory and tenant detected")
+	}
+	return driftDetected
+}
+
+func writeIntegrationPackageDriftReport(utils integrationPackageSyncUtils, drifts []cpi.ArtifactDrift) error {
+	report, err := json.MarshalIndent(drifts, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to create drift report")
+	}
+	if err := utils.FileWrite(integrationPackageDriftReport, report, 0666); err != nil {
+		return errors.Wrapf(err, "failed to write %v", integrationPackageDriftReport)
+	}
+	piperutils.PersistReportsAndLinks("integrationPackageSync", "", utils, []piperutils.Path{{Name: "Integration Package Drift", Target: integrationPackageDriftReport}}, nil)
+	return nil
+}
@@ -0,0 +1,319 @@
+// Code generated by piper's step-generator. DO NOT EDIT.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/gcp"
+	"github.com/SAP/jenkins-library/pkg/gcs"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/splunk"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/SAP/jenkins-library/pkg/validation"
+	"github.com/bmatcuk/doublestar"
+	"github.com/spf13/cobra"
+)
+
+type integrationPackageSyncOptions struct {
+	APIServiceKey   string   `json:"apiServiceKey,omitempty"`
+	PackageID       string   `json:"packageId,omitempty"`
+	SourceDirectory string   `json:"sourceDirectory,omitempty"`
+	Mode            string   `json:"mode,omitempty" validate:"possible-values=download upload detectDrift"`
+	ArtifactTypes   []string `json:"artifactTypes,omitempty" validate:"possible-values=IntegrationFlow ValueMapping ScriptCollection MessageMapping"`
+	Deploy          bool     `json:"deploy,omitempty"`
+	FailOnDrift     bool     `json:"failOnDrift,omitempty"`
+}
+
+type integrationPackageSyncReports struct {
+}
+
+func (p *integrationPackageSyncReports) persist(stepConfig integrationPackageSyncOptions, gcpJsonKeyFilePath string, gcsBucketId string, gcsFolderPath string, gcsSubFolder string) {
+	if gcsBucketId == "" {
+		log.Entry().Info("persisting reports to GCS is disabled, because gcsBucketId is empty")
+		return
+	}
+	log.Entry().Info("Uploading reports to Google Cloud Storage...")
+	content := []gcs.ReportOutputParam{
+		{FilePattern: "integrationPackageSync_drift.json", ParamRef: "", StepResultType: "integration-package-drift"},
+	}
+
+	gcsClient, err := gcs.NewClient(gcpJsonKeyFilePath, "")
+	if err != nil {
+		log.Entry().Errorf("creation of GCS client failed: %v", err)
+		return
+	}
+	defer gcsClient.Close()
+	structVal := reflect.ValueOf(&stepConfig).Elem()
+	inputParameters := map[string]string{}
+	for i := 0; i < structVal.NumField(); i++ {
+		field := structVal.Type().Field(i)
+		if field.Type.String() == "string" {
+			paramName := strings.Split(field.Tag.Get("json"), ",")
+			paramValue, _ := structVal.Field(i).Interface().(string)
+			inputParameters[paramName[0]] = paramValue
+		}
+	}
+	if err := gcs.PersistReportsToGCS(gcsClient, content, inputParameters, gcsFolderPath, gcsBucketId, gcsSubFolder, doublestar.Glob, os.Stat); err != nil {
+		log.Entry().Errorf("failed to persist reports: %v", err)
+	}
+}
+
+// IntegrationPackageSyncCommand Synchronise an integration package between a source directory and an SAP Cloud Integration tenant
+func IntegrationPackageSyncCommand() *cobra.Command {
+	const STEP_NAME = "integrationPackageSync"
+
+	metadata := integrationPackageSyncMetadata()
+	var stepConfig integrationPackageSyncOptions
+	var startTime time.Time
+	var reports integrationPackageSyncReports
+	var logCollector *log.CollectorHook
+	var splunkClient *splunk.Splunk
+	telemetryClient := &telemetry.Telemetry{}
+
+	var createIntegrationPackageSyncCmd = &cobra.Command{
+		Use:   STEP_NAME,
+		Short: "Synchronise an integration package between a source directory and an SAP Cloud Integration tenant",
+		Long: `With this step you can treat a whole SAP Cloud Integration package as code. The step supports the following modes:
+
+* ` + "`" + `download` + "`" + ` - downloads all artifacts of the package and unpacks them into ` + "`" + `sourceDirectory` + "`" + `. Each artifact is stored in the directory ` + "`" + `<artifact type>/<artifact id>` + "`" + `, the artifacts of the package are listed in the file ` + "`" + `package.json` + "`" + `. Directories of artifacts which no longer exist on the tenant are removed.
+* ` + "`" + `detectDrift` + "`" + ` - compares the artifacts in ` + "`" + `sourceDirectory` + "`" + ` with the artifacts on the tenant and reports the differences.
+* ` + "`" + `upload` + "`" + ` - compares the artifacts like ` + "`" + `detectDrift` + "`" + ` and uploads only artifacts which have been modified or do not exist on the tenant. The uploaded artifacts are deployed unless ` + "`" + `deploy` + "`" + ` is disabled. Artifacts which only exist on the tenant are reported, but not deleted.
+
+Artifacts are compared by a digest of their unpacked content, line endings are ignored. The drift is written to ` + "`" + `integrationPackageSync_drift.json` + "`" + ` which is archived as report of the step.
+Supported artifact types are integration flows, value mappings, script collections and message mappings. Learn more about the SAP Cloud Integration remote API for integration content [here](https://help.sap.com/docs/cloud-integration/sap-cloud-integration/integration-content).`,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			startTime = time.Now()
+			log.SetStepName(STEP_NAME)
+			log.SetVerbose(GeneralConfig.Verbose)
+
+			GeneralConfig.GitHubAccessTokens = ResolveAccessTokens(GeneralConfig.GitHubTokens)
+
+			path, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+			fatalHook := &log.FatalHook{CorrelationID: GeneralConfig.CorrelationID, Path: path}
+			log.RegisterHook(fatalHook)
+
+			err = PrepareConfig(cmd, &metadata, STEP_NAME, &stepConfig, config.OpenPiperFile)
+			if err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+
+			// Set step error patterns for improved error detection
+			stepErrors := make([]log.StepError, len(metadata.Metadata.Errors))
+			for i, err := range metadata.Metadata.Errors {
+				stepErrors[i] = log.StepError{
+					Pattern:  err.Pattern,
+					Message:  err.Message,
+					Category: err.Category,
+				}
+			}
+			log.SetStepErrors(stepErrors)
+			log.RegisterSecret(stepConfig.APIServiceKey)
+
+			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
+				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
+				log.RegisterHook(&sentryHook)
+			}
+
+			if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 || len(GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint) > 0 {
+				splunkClient = &splunk.Splunk{}
+				logCollector = &log.CollectorHook{CorrelationID: GeneralConfig.CorrelationID}
+				log.RegisterHook(logCollector)
+			}
+
+			if err = log.RegisterANSHookIfConfigured(GeneralConfig.CorrelationID); err != nil {
+				log.Entry().WithError(err).Warn("failed to set up SAP Alert Notification Service log hook")
+			}
+
+			validation, err := validation.New(validation.WithJSONNamesForStructFields(), validation.WithPredefinedErrorMessages())
+			if err != nil {
+				return err
+			}
+			if err = validation.ValidateStruct(stepConfig); err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+
+			return nil
+		},
+		Run: func(_ *cobra.Command, _ []string) {
+			vaultClient := config.GlobalVaultClient()
+			if vaultClient != nil {
+				defer vaultClient.MustRevokeToken()
+			}
+
+			stepTelemetryData := telemetry.CustomData{}
+			stepTelemetryData.ErrorCode = "1"
+			handler := func() {
+				reports.persist(stepConfig, GeneralConfig.GCPJsonKeyFilePath, GeneralConfig.GCSBucketId, GeneralConfig.GCSFolderPath, GeneralConfig.GCSSubFolder)
+				config.RemoveVaultSecretFiles()
+				stepTelemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
+				stepTelemetryData.ErrorCategory = log.GetErrorCategory().String()
+				stepTelemetryData.PiperCommitHash = GitCommit
+				telemetryClient.SetData(&stepTelemetryData)
+				telemetryClient.LogStepTelemetryData()
+				if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+					splunkClient.Initialize(GeneralConfig.CorrelationID,
+						GeneralConfig.HookConfig.SplunkConfig.Dsn,
+						GeneralConfig.HookConfig.SplunkConfig.Token,
+						GeneralConfig.HookConfig.SplunkConfig.Index,
+						GeneralConfig.HookConfig.SplunkConfig.SendLogs)
+					splunkClient.Send(telemetryClient.GetData(), logCollector)
+				}
+				if len(GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint) > 0 {
+					splunkClient.Initialize(GeneralConfig.CorrelationID,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblToken,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblIndex,
+						GeneralConfig.HookConfig.SplunkConfig.SendLogs)
+					splunkClient.Send(telemetryClient.GetData(), logCollector)
+				}
+				if GeneralConfig.HookConfig.GCPPubSubConfig.Enabled {
+					err := gcp.NewGcpPubsubClient(
+						vaultClient,
+						GeneralConfig.HookConfig.GCPPubSubConfig.ProjectNumber,
+						GeneralConfig.HookConfig.GCPPubSubConfig.IdentityPool,
+						GeneralConfig.HookConfig.GCPPubSubConfig.IdentityProvider,
+						GeneralConfig.CorrelationID,
+						GeneralConfig.HookConfig.OIDCConfig.RoleID,
+					).Publish(GeneralConfig.HookConfig.GCPPubSubConfig.Topic, telemetryClient.GetDataBytes())
+					if err != nil {
+						log.Entry().WithError(err).Warn("event publish failed")
+					}
+				}
+			}
+			log.DeferExitHandler(handler)
+			defer handler()
+			telemetryClient.Initialize(STEP_NAME)
+			integrationPackageSync(stepConfig, &stepTelemetryData)
+			stepTelemetryData.ErrorCode = "0"
+			log.Entry().Info("SUCCESS")
+		},
+	}
+
+	addIntegrationPackageSyncFlags(createIntegrationPackageSyncCmd, &stepConfig)
+	return createIntegrationPackageSyncCmd
+}
+
+func addIntegrationPackageSyncFlags(cmd *cobra.Command, stepConfig *integrationPackageSyncOptions) {
+	cmd.Flags().StringVar(&stepConfig.APIServiceKey, "apiServiceKey", os.Getenv("PIPER_apiServiceKey"), "Service key JSON string to access the Process Integration Runtime service instance of plan 'api'")
+	cmd.Flags().StringVar(&stepConfig.PackageID, "packageId", os.Getenv("PIPER_packageId"), "Specifies the ID of the Integration Package")
+	cmd.Flags().StringVar(&stepConfig.SourceDirectory, "sourceDirectory", `integrationPackage`, "Directory which contains the unpacked artifacts of the integration package")
+	cmd.Flags().StringVar(&stepConfig.Mode, "mode", `detectDrift`, "Specifies whether the package is downloaded into the source directory, uploaded to the tenant or only compared, see the step description for details.")
+	cmd.Flags().StringSliceVar(&stepConfig.ArtifactTypes, "artifactTypes", []string{`IntegrationFlow`, `ValueMapping`, `ScriptCollection`, `MessageMapping`}, "Types of artifacts which are synchronised")
+	cmd.Flags().BoolVar(&stepConfig.Deploy, "deploy", true, "Deploys the uploaded artifacts in mode `upload`")
+	cmd.Flags().BoolVar(&stepConfig.FailOnDrift, "failOnDrift", false, "Fails the step in mode `detectDrift` if the source directory and the tenant differ")
+
+	cmd.MarkFlagRequired("apiServiceKey")
+	cmd.MarkFlagRequired("packageId")
+}
+
+// retrieve step metadata
+func integrationPackageSyncMetadata() config.StepData {
+	var theMetaData = config.StepData{
+		Metadata: config.StepMetadata{
+			Name:        "integrationPackageSync",
+			Aliases:     []config.Alias{},
+			Description: "Synchronise an integration package between a source directory and an SAP Cloud Integration tenant",
+		},
+		Spec: config.StepSpec{
+			Inputs: config.StepInputs{
+				Secrets: []config.StepSecrets{
+					{Name: "cpiApiServiceKeyCredentialsId", Description: "Jenkins secret text credential ID containing the service key to the Process Integration Runtime service instance of plan 'api'", Type: "jenkins"},
+				},
+				Parameters: []config.StepParameters{
+					{
+						Name: "apiServiceKey",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "cpiApiServiceKeyCredentialsId",
+								Param: "apiServiceKey",
+								Type:  "secret",
+							},
+						},
+						Scope:     []string{"PARAMETERS"},
+						Type:      "string",
+						Mandatory: true,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_apiServiceKey"),
+					},
+					{
+						Name:        "packageId",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "GENERAL", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   true,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_packageId"),
+					},
+					{
+						Name:        "sourceDirectory",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `integrationPackage`,
+					},
+					{
+						Name:        "mode",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `detectDrift`,
+					},
+					{
+						Name:        "artifactTypes",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{`IntegrationFlow`, `ValueMapping`, `ScriptCollection`, `MessageMapping`},
+					},
+					{
+						Name:        "deploy",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     true,
+					},
+					{
+						Name:        "failOnDrift",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+				},
+			},
+			Outputs: config.StepOutputs{
+				Resources: []config.StepResources{
+					{
+						Name: "reports",
+						Type: "reports",
+						Parameters: []map[string]interface{}{
+							{"filePattern": "integrationPackageSync_drift.json", "type": "integration-package-drift"},
+						},
+					},
+				},
+			},
+		},
+	}
+	return theMetaData
+}
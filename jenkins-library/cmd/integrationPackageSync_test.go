@@ -0,0 +1,107 @@
+//go:build unit
+// +build unit
+
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/cpi"
+	cpimocks "github.com/SAP/jenkins-library/pkg/cpi/mocks"
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newIntegrationPackageServer(t *testing.T) *cpimocks.Server {
+	server := cpimocks.NewServer()
+	t.Cleanup(server.Close)
+	content, err := cpi.CreateArtifactArchive(cpi.ArtifactFiles{"src/main/resources/scenarioflows/integrationflow/flow.iflw": []byte("<flow/>")})
+	require.NoError(t, err)
+	server.AddArtifact(cpi.Artifact{ID: "Flow1", Name: "Flow 1", PackageID: "pkg", Type: "IntegrationFlow"}, content)
+	return server
+}
+
+func TestRunIntegrationPackageSync(t *testing.T) {
+	t.Parallel()
+
+	newConfig := func(server *cpimocks.Server, dir, mode string) *integrationPackageSyncOptions {
+		return &integrationPackageSyncOptions{
+			APIServiceKey:   server.ServiceKey(),
+			PackageID:       "pkg",
+			SourceDirectory: dir,
+			Mode:            mode,
+			ArtifactTypes:   []string{"IntegrationFlow", "ValueMapping", "ScriptCollection", "MessageMapping"},
+			Deploy:          true,
+		}
+	}
+
+	t.Run("download and detect drift", func(t *testing.T) {
+		t.Parallel()
+		server := newIntegrationPackageServer(t)
+		dir := t.TempDir()
+		utils := &mock.FilesMock{}
+
+		require.NoError(t, runIntegrationPackageSync(newConfig(server, dir, "download"), &piperhttp.Client{}, utils))
+		assert.FileExists(t, filepath.Join(dir, "IntegrationFlow", "Flow1", "src", "main", "resources", "scenarioflows", "integrationflow", "flow.iflw"))
+		assert.FileExists(t, filepath.Join(dir, cpi.PackageDescriptorFile))
+
+		config := newConfig(server, dir, "detectDrift")
+		config.FailOnDrift = true
+		require.NoError(t, runIntegrationPackageSync(config, &piperhttp.Client{}, utils))
+
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "IntegrationFlow", "Flow1", "src", "main", "resources", "scenarioflows", "integrationflow", "flow.iflw"), []byte("<changed/>"), 0644))
+		err := runIntegrationPackageSync(config, &piperhttp.Client{}, utils)
+		assert.EqualError(t, err, "integration package pkg on the tenant differs from "+dir)
+
+		report, err := utils.FileRead(integrationPackageDriftReport)
+		require.NoError(t, err)
+		var drifts []cpi.ArtifactDrift
+		require.NoError(t, json.Unmarshal(report, &drifts))
+		if assert.Len(t, drifts, 1) {
+			assert.Equal(t, cpi.DriftModified, drifts[0].State)
+		}
+		assert.Empty(t, server.Deployed)
+	})
+
+	t.Run("upload changed artifacts", func(t *testing.T) {
+		t.Parallel()
+		server := newIntegrationPackageServer(t)
+		dir := t.TempDir()
+		utils := &mock.FilesMock{}
+		require.NoError(t, cpi.WriteArtifactDirectory(filepath.Join(dir, "IntegrationFlow", "Flow1"), cpi.ArtifactFiles{"src/main/resources/scenarioflows/integrationflow/flow.iflw": []byte("<flow/>")}))
+		require.NoError(t, cpi.WriteArtifactDirectory(filepath.Join(dir, "ScriptCollection", "Scripts"), cpi.ArtifactFiles{"src/main/resources/script/script.groovy": []byte("println 1")}))
+		require.NoError(t, cpi.WritePackageDescriptor(dir, cpi.PackageDescriptor{PackageID: "pkg", Artifacts: []cpi.DescriptorArtifact{{ID: "Scripts", Name: "Common Scripts", Type: "ScriptCollection"}}}))
+
+		require.NoError(t, runIntegrationPackageSync(newConfig(server, dir, "upload"), &piperhttp.Client{}, utils))
+
+		created, ok := server.Artifact("ScriptCollection", "Scripts")
+		require.True(t, ok)
+		assert.Equal(t, "Common Scripts", created.Name)
+		assert.Equal(t, []string{"ScriptCollection/Scripts"}, server.Deployed)
+		assert.NotContains(t, server.Requests, "PUT /api/v1/IntegrationDesigntimeArtifacts(Id='Flow1',Version='active')")
+	})
+
+	t.Run("unknown artifact type", func(t *testing.T) {
+		t.Parallel()
+		server := newIntegrationPackageServer(t)
+		config := newConfig(server, t.TempDir(), "detectDrift")
+		config.ArtifactTypes = []string{"Unknown"}
+
+		err := runIntegrationPackageSync(config, &piperhttp.Client{}, &mock.FilesMock{})
+
+		assert.EqualError(t, err, "unknown artifact type 'Unknown'")
+		assert.Empty(t, server.Requests)
+	})
+
+	t.Run("invalid service key", func(t *testing.T) {
+		t.Parallel()
+		err := runIntegrationPackageSync(&integrationPackageSyncOptions{APIServiceKey: "invalid", Mode: "download"}, &piperhttp.Client{}, &mock.FilesMock{})
+
+		assert.ErrorContains(t, err, "error unmarshalling serviceKey")
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/mitchellh/mapstructure"
 	"github.com/pkg/errors"
@@ -38,10 +39,36 @@ func kanikoExecute(config kanikoExecuteOptions, telemetryData *telemetry.CustomD
 
 	fileUtils := &piperutils.Files{}
 
+	startedOn := time.Now()
 	err := runKanikoExecute(&config, telemetryData, commonPipelineEnvironment, &c, client, fileUtils)
 	if err != nil {
 		log.Entry().WithError(err).Fatal("Kaniko execution failed")
 	}
+	if config.CreateProvenance {
+		if err := createKanikoProvenance(&config, startedOn, commonPipelineEnvironment, fileUtils); err != nil {
+			log.Entry().WithError(err).Fatal("failed to create provenance")
+		}
+	}
+}
+
+// createKanikoProvenance creates the provenance of the pushed images and pushes it as referrer of the images
+func createKanikoProvenance(config *kanikoExecuteOptions, startedOn time.Time, commonPipelineEnvironment *kanikoExecuteCommonPipelineEnvironment, utils provenanceUtils) error {
+	settings := provenanceSettings{
+		stepName:          "kanikoExecute",
+		buildTool:         "kaniko",
+		signingKey:        config.ProvenanceSigningKey,
+		dockerConfigJSON:  config.DockerConfigJSON,
+		buildSettingsInfo: commonPipelineEnvironment.custom.buildSettingsInfo,
+		externalParameters: map[string]interface{}{
+			"dockerfilePath":      config.DockerfilePath,
+			"buildOptions":        config.BuildOptions,
+			"targetArchitectures": config.TargetArchitectures,
+		},
+		startedOn:   startedOn,
+		bomPatterns: []string{"bom-*.xml"},
+	}
+	images := builtImages(commonPipelineEnvironment.container.registryURL, commonPipelineEnvironment.container.imageNameTags, commonPipelineEnvironment.container.imageDigests)
+	return createProvenance(settings, nil, images, utils)
 }
 
 func runKanikoExecute(config *kanikoExecuteOptions, telemetryData *telemetry.CustomData, commonPipelineEnvironment *kanikoExecuteCommonPipelineEnvironment, execRunner command.ExecRunner, httpClient piperhttp.Sender, fileUtils piperutils.FileUtils) error {
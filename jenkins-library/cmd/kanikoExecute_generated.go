@@ -44,6 +44,8 @@ type kanikoExecuteOptions struct {
 	ReadImageDigest                  bool                     `json:"readImageDigest,omitempty"`
 	CreateBOM                        bool                     `json:"createBOM,omitempty"`
 	SyftDownloadURL                  string                   `json:"syftDownloadUrl,omitempty"`
+	CreateProvenance                 bool                     `json:"createProvenance,omitempty"`
+	ProvenanceSigningKey             string                   `json:"provenanceSigningKey,omitempty"`
 }
 
 type kanikoExecuteCommonPipelineEnvironment struct {
@@ -99,6 +101,7 @@ func (p *kanikoExecuteReports) persist(stepConfig kanikoExecuteOptions, gcpJsonK
 	log.Entry().Info("Uploading reports to Google Cloud Storage...")
 	content := []gcs.ReportOutputParam{
 		{FilePattern: "**/bom-*.xml", ParamRef: "", StepResultType: "sbom"},
+		{FilePattern: "**/provenance-*.intoto.jsonl", ParamRef: "", StepResultType: "provenance"},
 	}
 
 	gcsClient, err := gcs.NewClient(gcpJsonKeyFilePath, "")
@@ -237,6 +240,7 @@ Following final image names will be built:
 			}
 			log.SetStepErrors(stepErrors)
 			log.RegisterSecret(stepConfig.DockerConfigJSON)
+			log.RegisterSecret(stepConfig.ProvenanceSigningKey)
 
 			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
 				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
@@ -346,6 +350,8 @@ func addKanikoExecuteFlags(cmd *cobra.Command, stepConfig *kanikoExecuteOptions)
 	cmd.Flags().BoolVar(&stepConfig.ReadImageDigest, "readImageDigest", false, "")
 	cmd.Flags().BoolVar(&stepConfig.CreateBOM, "createBOM", false, "Creates the bill of materials (BOM) using Syft and stores it in a file in CycloneDX 1.4 format.")
 	cmd.Flags().StringVar(&stepConfig.SyftDownloadURL, "syftDownloadUrl", `https://github.com/anchore/syft/releases/download/v1.22.0/syft_1.22.0_linux_amd64.tar.gz`, "Specifies the download url of the Syft Linux amd64 tar binary file. This can be found at https://github.com/anchore/syft/releases/.")
+	cmd.Flags().BoolVar(&stepConfig.CreateProvenance, "createProvenance", false, "Creates a [SLSA v1 provenance](https://slsa.dev/spec/v1.0/provenance) of the build outputs as in-toto statement in a DSSE envelope, which is published as step report `provenance-<stepName>.intoto.jsonl`. For the built images the provenance is additionally pushed to the registry as OCI artifact referring to the image.")
+	cmd.Flags().StringVar(&stepConfig.ProvenanceSigningKey, "provenanceSigningKey", os.Getenv("PIPER_provenanceSigningKey"), "Path to the PEM encoded ECDSA, Ed25519 or RSA private key the provenance is signed with. Without key the provenance is not signed.")
 
 }
 
@@ -361,6 +367,7 @@ func kanikoExecuteMetadata() config.StepData {
 			Inputs: config.StepInputs{
 				Secrets: []config.StepSecrets{
 					{Name: "dockerConfigJsonCredentialsId", Description: "Jenkins 'Secret file' credentials ID containing Docker config.json (with registry credential(s)). You can create it like explained in the [protocodeExecuteScan Prerequisites section](https://www.project-piper.io/steps/protecodeExecuteScan/#prerequisites).", Type: "jenkins"},
+					{Name: "provenanceSigningKeyCredentialsId", Description: "Jenkins 'Secret file' credentials ID containing the private key the provenance is signed with.", Type: "jenkins"},
 				},
 				Parameters: []config.StepParameters{
 					{
@@ -587,6 +594,35 @@ func kanikoExecuteMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     `https://github.com/anchore/syft/releases/download/v1.22.0/syft_1.22.0_linux_amd64.tar.gz`,
 					},
+					{
+						Name:        "createProvenance",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "GENERAL", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name: "provenanceSigningKey",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "provenanceSigningKeyCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:    "provenanceSigningKeyVaultSecretName",
+								Type:    "vaultSecretFile",
+								Default: "provenance-signing-key",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_provenanceSigningKey"),
+					},
 				},
 			},
 			Containers: []config.Container{
@@ -612,6 +648,7 @@ func kanikoExecuteMetadata() config.StepData {
 						Type: "reports",
 						Parameters: []map[string]interface{}{
 							{"filePattern": "**/bom-*.xml", "type": "sbom"},
+							{"filePattern": "**/provenance-*.intoto.jsonl", "type": "provenance"},
 						},
 					},
 				},
@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/command"
+	"github.com/SAP/jenkins-library/pkg/kubernetes"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/pkg/errors"
+)
+
+const (
+	manifestSarifFile  = "kubernetes-manifests.sarif"
+	manifestReportFile = "kubernetes-manifests-report.html"
+)
+
+type kubernetesValidateManifestsUtils interface {
+	command.ExecRunner
+
+	FileExists(filename string) (bool, error)
+	FileRead(path string) ([]byte, error)
+	FileWrite(path string, content []byte, perm os.FileMode) error
+	WriteFile(path string, content []byte, perm os.FileMode) error
+	MkdirAll(path string, perm os.FileMode) error
+	Glob(pattern string) (matches []string, err error)
+}
+
+type kubernetesValidateManifestsUtilsBundle struct {
+	*command.Command
+	*piperutils.Files
+}
+
+func newKubernetesValidateManifestsUtils() kubernetesValidateManifestsUtils {
+	utils := kubernetesValidateManifestsUtilsBundle{
+		Command: &command.Command{},
+		Files:   &piperutils.Files{},
+	}
+	// Reroute command output to logging framework
+	utils.Stdout(log.Writer())
+	utils.Stderr(log.Writer())
+	return &utils
+}
+
+func kubernetesValidateManifests(config kubernetesValidateManifestsOptions, telemetryData *telemetry.CustomData) {
+	utils := newKubernetesValidateManifestsUtils()
+
+	err := runKubernetesValidateManifests(&config, utils)
+	if err != nil {
+		log.Entry().WithError(err).Fatal("step execution failed")
+	}
+}
+
+func runKubernetesValidateManifests(config *kubernetesValidateManifestsOptions, utils kubernetesValidateManifestsUtils) error {
+	sources, err := renderKubernetesManifests(config, utils)
+	if err != nil {
+		return err
+	}
+	if len(sources) == 0 {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return fmt.Errorf("no manifests found, please configure chartPath, kustomizeOverlays or manifestFiles")
+	}
+
+	manifests := []kubernetes.Manifest{}
+	findings := []kubernetes.ManifestFinding{}
+	for _, source := range sources {
+		m, f := kubernetes.SplitManifests(source.name, source.content)
+		manifests = append(manifests, m...)
+		findings = append(findings, f...)
+	}
+
+	checkFindings, err := kubernetes.CheckManifests(manifests, kubernetes.ManifestCheckOptions{
+		KubernetesVersion: config.KubernetesVersion,
+		DisabledRules:     config.DisabledChecks,
+	})
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return err
+	}
+	findings = append(findings, checkFindings...)
+	log.Entry().Infof("checked %d Kubernetes resources, found %d issue(s)", len(manifests), len(findings))
+	for _, f := range findings {
+		log.Entry().Infof("[%s] %s %s (%s:%d): %s", f.Level, f.RuleID, f.Resource, f.Source, f.Line, f.Message)
+	}
+
+	if err := writeKubernetesManifestReports(findings, len(manifests), config.KubernetesVersion, utils); err != nil {
+		return err
+	}
+
+	failing := 0
+	for _, f := range findings {
+		if f.Level == kubernetes.LevelError || (f.Level == kubernetes.LevelWarning && config.FailOn == "warning") {
+			failing++
+		}
+	}
+	if config.FailOn != "none" && failing > 0 {
+		log.SetErrorCategory(log.ErrorCompliance)
+		return fmt.Errorf("%d manifest finding(s) with severity '%s' or higher found", failing, config.FailOn)
+	}
+	return nil
+}
+
+type manifestSource struct {
+	name    string
+	content []byte
+}
+
+func renderKubernetesManifests(config *kubernetesValidateManifestsOptions, utils kubernetesValidateManifestsUtils) ([]manifestSource, error) {
+	sources := []manifestSource{}
+
+	if len(config.ChartPath) > 0 {
+		params := []string{"template", config.DeploymentName, config.ChartPath, "--namespace", config.Namespace, "--kube-version", config.KubernetesVersion}
+		for _, v := range config.HelmValues {
+			params = append(params, "--values", v)
+		}
+		content, err := runWithCapturedStdout(utils, "helm", params...)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return nil, errors.Wrapf(err, "failed to render helm chart '%s'", config.ChartPath)
+		}
+		sources = append(sources, manifestSource{name: config.ChartPath, content: content})
+	}
+
+	for _, overlay := range config.KustomizeOverlays {
+		content, err := runWithCapturedStdout(utils, "kubectl", "kustomize", overlay)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return nil, errors.Wrapf(err, "failed to render kustomize overlay '%s'", overlay)
+		}
+		sources = append(sources, manifestSource{name: overlay, content: content})
+	}
+
+	for _, pattern := range config.ManifestFiles {
+		files, err := utils.Glob(pattern)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return nil, errors.Wrapf(err, "invalid manifest file pattern '%s'", pattern)
+		}
+		if len(files) == 0 {
+			log.Entry().Warnf("no manifest files found for pattern '%s'", pattern)
+		}
+		for _, file := range files {
+			content, err := utils.FileRead(file)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to read manifest file '%s'", file)
+			}
+			sources = append(sources, manifestSource{name: file, content: content})
+		}
+	}
+	return sources, nil
+}
+
+func runWithCapturedStdout(utils kubernetesValidateManifestsUtils, executable string, params ...string) ([]byte, error) {
+	var stdout bytes.Buffer
+	utils.Stdout(&stdout)
+	defer utils.Stdout(log.Writer())
+	if err := utils.RunExecutable(executable, params...); err != nil {
+		return nil, err
+	}
+	return stdout.Bytes(), nil
+}
+
+func writeKubernetesManifestReports(findings []kubernetes.ManifestFinding, manifestCount int, kubernetesVersion string, utils kubernetesValidateManifestsUtils) error {
+	sarif, err := json.MarshalIndent(kubernetes.CreateManifestSarif(findings), "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal SARIF report")
+	}
+	if err := utils.FileWrite(manifestSarifFile, sarif, 0o666); err != nil {
+		return errors.Wrap(err, "failed to write SARIF report")
+	}
+
+	scanReport := kubernetes.CreateManifestScanReport(findings, manifestCount, kubernetesVersion)
+	scanReport.StepName = "kubernetesValidateManifests"
+	scanReport.ReportTime = time.Now()
+
+	htmlReport, _ := scanReport.ToHTML()
+	if err := utils.FileWrite(manifestReportFile, htmlReport, 0o666); err != nil {
+		return errors.Wrap(err, "failed to write html report")
+	}
+
+	jsonReport, _ := scanReport.ToJSON()
+	if err := utils.MkdirAll(reporting.StepReportDirectory, 0o777); err != nil {
+		return errors.Wrap(err, "failed to create reporting directory")
+	}
+	if err := utils.FileWrite(filepath.Join(reporting.StepReportDirectory, "kubernetesValidateManifests.json"), jsonReport, 0o666); err != nil {
+		return errors.Wrap(err, "failed to write json report")
+	}
+
+	reportPaths := []piperutils.Path{
+		{Name: "Kubernetes Manifest Check SARIF", Target: manifestSarifFile},
+		{Name: "Kubernetes Manifest Check Report", Target: manifestReportFile},
+	}
+	piperutils.PersistReportsAndLinks("kubernetesValidateManifests", "", utils, reportPaths, nil)
+	return nil
+}
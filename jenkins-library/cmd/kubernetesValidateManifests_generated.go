@@ -0,0 +1,343 @@
+// Code generated by piper's step-generator. DO NOT EDIT.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/gcp"
+	"github.com/SAP/jenkins-library/pkg/gcs"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/splunk"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/SAP/jenkins-library/pkg/validation"
+	"github.com/bmatcuk/doublestar"
+	"github.com/spf13/cobra"
+)
+
+type kubernetesValidateManifestsOptions struct {
+	ChartPath         string   `json:"chartPath,omitempty"`
+	HelmValues        []string `json:"helmValues,omitempty"`
+	DeploymentName    string   `json:"deploymentName,omitempty"`
+	Namespace         string   `json:"namespace,omitempty"`
+	KustomizeOverlays []string `json:"kustomizeOverlays,omitempty"`
+	ManifestFiles     []string `json:"manifestFiles,omitempty"`
+	KubernetesVersion string   `json:"kubernetesVersion,omitempty"`
+	DisabledChecks    []string `json:"disabledChecks,omitempty"`
+	FailOn            string   `json:"failOn,omitempty" validate:"possible-values=error warning none"`
+}
+
+type kubernetesValidateManifestsReports struct {
+}
+
+func (p *kubernetesValidateManifestsReports) persist(stepConfig kubernetesValidateManifestsOptions, gcpJsonKeyFilePath string, gcsBucketId string, gcsFolderPath string, gcsSubFolder string) {
+	if gcsBucketId == "" {
+		log.Entry().Info("persisting reports to GCS is disabled, because gcsBucketId is empty")
+		return
+	}
+	log.Entry().Info("Uploading reports to Google Cloud Storage...")
+	content := []gcs.ReportOutputParam{
+		{FilePattern: "**/kubernetes-manifests.sarif", ParamRef: "", StepResultType: "kubernetes-manifests"},
+		{FilePattern: "**/kubernetes-manifests-report.html", ParamRef: "", StepResultType: "kubernetes-manifests"},
+	}
+
+	gcsClient, err := gcs.NewClient(gcpJsonKeyFilePath, "")
+	if err != nil {
+		log.Entry().Errorf("creation of GCS client failed: %v", err)
+		return
+	}
+	defer gcsClient.Close()
+	structVal := reflect.ValueOf(&stepConfig).Elem()
+	inputParameters := map[string]string{}
+	for i := 0; i < structVal.NumField(); i++ {
+		field := structVal.Type().Field(i)
+		if field.Type.String() == "string" {
+			paramName := strings.Split(field.Tag.Get("json"), ",")
+			paramValue, _ := structVal.Field(i).Interface().(string)
+			inputParameters[paramName[0]] = paramValue
+		}
+	}
+	if err := gcs.PersistReportsToGCS(gcsClient, content, inputParameters, gcsFolderPath, gcsBucketId, gcsSubFolder, doublestar.Glob, os.Stat); err != nil {
+		log.Entry().Errorf("failed to persist reports: %v", err)
+	}
+}
+
+// KubernetesValidateManifestsCommand Validates Kubernetes manifests, Helm charts and kustomize overlays before they are deployed.
+func KubernetesValidateManifestsCommand() *cobra.Command {
+	const STEP_NAME = "kubernetesValidateManifests"
+
+	metadata := kubernetesValidateManifestsMetadata()
+	var stepConfig kubernetesValidateManifestsOptions
+	var startTime time.Time
+	var reports kubernetesValidateManifestsReports
+	var logCollector *log.CollectorHook
+	var splunkClient *splunk.Splunk
+	telemetryClient := &telemetry.Telemetry{}
+
+	var createKubernetesValidateManifestsCmd = &cobra.Command{
+		Use:   STEP_NAME,
+		Short: "Validates Kubernetes manifests, Helm charts and kustomize overlays before they are deployed.",
+		Long: `Renders Helm charts and kustomize overlays as used by the steps ` + "`" + `kubernetesDeploy` + "`" + `, ` + "`" + `helmExecute` + "`" + ` and ` + "`" + `gitopsUpdateDeployment` + "`" + ` and validates the resulting manifests without access to a cluster.
+
+The following checks are performed:
+
+* ` + "`" + `K8S001` + "`" + ` - manifests are valid YAML
+* ` + "`" + `K8S002` + "`" + ` - resources match the Kubernetes API schema bundled with piper for the configured ` + "`" + `kubernetesVersion` + "`" + ` (unknown or mistyped fields)
+* ` + "`" + `K8S003` + "`" + ` - resources do not use API versions which were removed in the configured ` + "`" + `kubernetesVersion` + "`" + `
+* ` + "`" + `K8S004` + "`" + ` - resources of unknown kinds (e.g. custom resources) are reported as note since they cannot be validated
+* ` + "`" + `K8S101` + "`" + ` - containers define CPU and memory limits
+* ` + "`" + `K8S102` + "`" + ` - containers do not run as root
+* ` + "`" + `K8S103` + "`" + ` - container images use a fixed tag or digest instead of ` + "`" + `latest` + "`" + `
+* ` + "`" + `K8S104` + "`" + ` - long running containers define liveness and readiness probes
+
+Policy checks can be disabled via ` + "`" + `disabledChecks` + "`" + `. The findings are provided as SARIF file and as HTML/JSON report.`,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			startTime = time.Now()
+			log.SetStepName(STEP_NAME)
+			log.SetVerbose(GeneralConfig.Verbose)
+
+			GeneralConfig.GitHubAccessTokens = ResolveAccessTokens(GeneralConfig.GitHubTokens)
+
+			path, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+			fatalHook := &log.FatalHook{CorrelationID: GeneralConfig.CorrelationID, Path: path}
+			log.RegisterHook(fatalHook)
+
+			err = PrepareConfig(cmd, &metadata, STEP_NAME, &stepConfig, config.OpenPiperFile)
+			if err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+
+			// Set step error patterns for improved error detection
+			stepErrors := make([]log.StepError, len(metadata.Metadata.Errors))
+			for i, err := range metadata.Metadata.Errors {
+				stepErrors[i] = log.StepError{
+					Pattern:  err.Pattern,
+					Message:  err.Message,
+					Category: err.Category,
+				}
+			}
+			log.SetStepErrors(stepErrors)
+
+			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
+				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
+				log.RegisterHook(&sentryHook)
+			}
+
+			if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 || len(GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint) > 0 {
+				splunkClient = &splunk.Splunk{}
+				logCollector = &log.CollectorHook{CorrelationID: GeneralConfig.CorrelationID}
+				log.RegisterHook(logCollector)
+			}
+
+			if err = log.RegisterANSHookIfConfigured(GeneralConfig.CorrelationID); err != nil {
+				log.Entry().WithError(err).Warn("failed to set up SAP Alert Notification Service log hook")
+			}
+
+			validation, err := validation.New(validation.WithJSONNamesForStructFields(), validation.WithPredefinedErrorMessages())
+			if err != nil {
+				return err
+			}
+			if err = validation.ValidateStruct(stepConfig); err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+
+			return nil
+		},
+		Run: func(_ *cobra.Command, _ []string) {
+			vaultClient := config.GlobalVaultClient()
+			if vaultClient != nil {
+				defer vaultClient.MustRevokeToken()
+			}
+
+			stepTelemetryData := telemetry.CustomData{}
+			stepTelemetryData.ErrorCode = "1"
+			handler := func() {
+				reports.persist(stepConfig, GeneralConfig.GCPJsonKeyFilePath, GeneralConfig.GCSBucketId, GeneralConfig.GCSFolderPath, GeneralConfig.GCSSubFolder)
+				config.RemoveVaultSecretFiles()
+				stepTelemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
+				stepTelemetryData.ErrorCategory = log.GetErrorCategory().String()
+				stepTelemetryData.PiperCommitHash = GitCommit
+				telemetryClient.SetData(&stepTelemetryData)
+				telemetryClient.LogStepTelemetryData()
+				if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+					splunkClient.Initialize(GeneralConfig.CorrelationID,
+						GeneralConfig.HookConfig.SplunkConfig.Dsn,
+						GeneralConfig.HookConfig.SplunkConfig.Token,
+						GeneralConfig.HookConfig.SplunkConfig.Index,
+						GeneralConfig.HookConfig.SplunkConfig.SendLogs)
+					splunkClient.Send(telemetryClient.GetData(), logCollector)
+				}
+				if len(GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint) > 0 {
+					splunkClient.Initialize(GeneralConfig.CorrelationID,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblToken,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblIndex,
+						GeneralConfig.HookConfig.SplunkConfig.SendLogs)
+					splunkClient.Send(telemetryClient.GetData(), logCollector)
+				}
+				if GeneralConfig.HookConfig.GCPPubSubConfig.Enabled {
+					err := gcp.NewGcpPubsubClient(
+						vaultClient,
+						GeneralConfig.HookConfig.GCPPubSubConfig.ProjectNumber,
+						GeneralConfig.HookConfig.GCPPubSubConfig.IdentityPool,
+						GeneralConfig.HookConfig.GCPPubSubConfig.IdentityProvider,
+						GeneralConfig.CorrelationID,
+						GeneralConfig.HookConfig.OIDCConfig.RoleID,
+					).Publish(GeneralConfig.HookConfig.GCPPubSubConfig.Topic, telemetryClient.GetDataBytes())
+					if err != nil {
+						log.Entry().WithError(err).Warn("event publish failed")
+					}
+				}
+			}
+			log.DeferExitHandler(handler)
+			defer handler()
+			telemetryClient.Initialize(STEP_NAME)
+			kubernetesValidateManifests(stepConfig, &stepTelemetryData)
+			stepTelemetryData.ErrorCode = "0"
+			log.Entry().Info("SUCCESS")
+		},
+	}
+
+	addKubernetesValidateManifestsFlags(createKubernetesValidateManifestsCmd, &stepConfig)
+	return createKubernetesValidateManifestsCmd
+}
+
+func addKubernetesValidateManifestsFlags(cmd *cobra.Command, stepConfig *kubernetesValidateManifestsOptions) {
+	cmd.Flags().StringVar(&stepConfig.ChartPath, "chartPath", os.Getenv("PIPER_chartPath"), "Path to a Helm chart which is rendered via `helm template` and validated.")
+	cmd.Flags().StringSliceVar(&stepConfig.HelmValues, "helmValues", []string{}, "List of helm values as YAML file reference (as per helm parameter description for `-f` / `--values`) used to render the chart.")
+	cmd.Flags().StringVar(&stepConfig.DeploymentName, "deploymentName", `release`, "Release name used to render the Helm chart.")
+	cmd.Flags().StringVar(&stepConfig.Namespace, "namespace", `default`, "Namespace used to render the Helm chart.")
+	cmd.Flags().StringSliceVar(&stepConfig.KustomizeOverlays, "kustomizeOverlays", []string{}, "List of directories containing a `kustomization.yaml` which are rendered via `kubectl kustomize` and validated.")
+	cmd.Flags().StringSliceVar(&stepConfig.ManifestFiles, "manifestFiles", []string{}, "List of plain manifest files to be validated. Supports globbing, e.g. `k8s/**/*.yaml`.")
+	cmd.Flags().StringVar(&stepConfig.KubernetesVersion, "kubernetesVersion", `1.32`, "Kubernetes version of the target cluster in the format `1.<minor>`. It selects the bundled API schema, is used to detect removed API versions and is passed to `helm template --kube-version`. The step fails for versions without a bundled schema, currently only `1.32` is supported.")
+	cmd.Flags().StringSliceVar(&stepConfig.DisabledChecks, "disabledChecks", []string{}, "List of check IDs which shall not be performed, e.g. `K8S104` to skip the probe check.")
+	cmd.Flags().StringVar(&stepConfig.FailOn, "failOn", `error`, "Minimum severity of findings which lets the step fail.")
+
+}
+
+// retrieve step metadata
+func kubernetesValidateManifestsMetadata() config.StepData {
+	var theMetaData = config.StepData{
+		Metadata: config.StepMetadata{
+			Name:        "kubernetesValidateManifests",
+			Aliases:     []config.Alias{},
+			Description: "Validates Kubernetes manifests, Helm charts and kustomize overlays before they are deployed.",
+		},
+		Spec: config.StepSpec{
+			Inputs: config.StepInputs{
+				Resources: []config.StepResources{
+					{Name: "deployDescriptor", Type: "stash"},
+				},
+				Parameters: []config.StepParameters{
+					{
+						Name:        "chartPath",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{{Name: "helmChartPath"}},
+						Default:     os.Getenv("PIPER_chartPath"),
+					},
+					{
+						Name:        "helmValues",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+					{
+						Name:        "deploymentName",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{{Name: "helmDeploymentName"}},
+						Default:     `release`,
+					},
+					{
+						Name:        "namespace",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{{Name: "helmDeploymentNamespace"}, {Name: "k8sDeploymentNamespace"}},
+						Default:     `default`,
+					},
+					{
+						Name:        "kustomizeOverlays",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+					{
+						Name:        "manifestFiles",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+					{
+						Name:        "kubernetesVersion",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `1.32`,
+					},
+					{
+						Name:        "disabledChecks",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+					{
+						Name:        "failOn",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `error`,
+					},
+				},
+			},
+			Containers: []config.Container{
+				{Image: "dtzar/helm-kubectl:3", WorkingDir: "/config", Options: []config.Option{{Name: "-u", Value: "0"}}},
+			},
+			Outputs: config.StepOutputs{
+				Resources: []config.StepResources{
+					{
+						Name: "reports",
+						Type: "reports",
+						Parameters: []map[string]interface{}{
+							{"filePattern": "**/kubernetes-manifests.sarif", "type": "kubernetes-manifests"},
+							{"filePattern": "**/kubernetes-manifests-report.html", "type": "kubernetes-manifests"},
+						},
+					},
+				},
+			},
+		},
+	}
+	return theMetaData
+}
@@ -0,0 +1,146 @@
+//go:build unit
+// +build unit
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+type kubernetesValidateManifestsMockUtils struct {
+	*mock.ExecMockRunner
+	*mock.FilesMock
+}
+
+func newKubernetesValidateManifestsTestsUtils() kubernetesValidateManifestsMockUtils {
+	utils := kubernetesValidateManifestsMockUtils{
+		ExecMockRunner: &mock.ExecMockRunner{},
+		FilesMock:      &mock.FilesMock{},
+	}
+	return utils
+}
+
+const validManifest = `apiVersion: v1
+kind: Pod
+metadata:
+  name: app
+spec:
+  securityContext:
+    runAsNonRoot: true
+  containers:
+  - name: app
+    image: app:1.0
+    resources:
+      limits: {cpu: 100m, memory: 64Mi}
+    livenessProbe:
+      tcpSocket: {port: 8080}
+    readinessProbe:
+      tcpSocket: {port: 8080}
+`
+
+func TestRunKubernetesValidateManifests(t *testing.T) {
+	t.Parallel()
+
+	t.Run("helm chart and kustomize overlay", func(t *testing.T) {
+		t.Parallel()
+		config := kubernetesValidateManifestsOptions{
+			ChartPath:         "helm/app",
+			HelmValues:        []string{"values.yaml"},
+			DeploymentName:    "app",
+			Namespace:         "dev",
+			KustomizeOverlays: []string{"overlays/prod"},
+			KubernetesVersion: "1.32",
+			FailOn:            "error",
+		}
+		utils := newKubernetesValidateManifestsTestsUtils()
+		utils.StdoutReturn = map[string]string{
+			"helm template app helm/app --namespace dev --kube-version 1.32 --values values.yaml": validManifest,
+			"kubectl kustomize overlays/prod": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config\n",
+		}
+
+		err := runKubernetesValidateManifests(&config, utils)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []mock.ExecCall{
+			{Exec: "helm", Params: []string{"template", "app", "helm/app", "--namespace", "dev", "--kube-version", "1.32", "--values", "values.yaml"}},
+			{Exec: "kubectl", Params: []string{"kustomize", "overlays/prod"}},
+		}, utils.Calls)
+		assert.True(t, utils.HasWrittenFile("kubernetes-manifests.sarif"))
+		assert.True(t, utils.HasWrittenFile("kubernetes-manifests-report.html"))
+		assert.True(t, utils.HasWrittenFile(".pipeline/stepReports/kubernetesValidateManifests.json"))
+	})
+
+	t.Run("manifest files with findings", func(t *testing.T) {
+		t.Parallel()
+		config := kubernetesValidateManifestsOptions{ManifestFiles: []string{"k8s/*.yaml"}, KubernetesVersion: "1.32", FailOn: "error"}
+		utils := newKubernetesValidateManifestsTestsUtils()
+		utils.AddFile("k8s/pod.yaml", []byte(validManifest))
+		utils.AddFile("k8s/ingress.yaml", []byte("apiVersion: networking.k8s.io/v1beta1\nkind: Ingress\nmetadata:\n  name: old\n"))
+
+		err := runKubernetesValidateManifests(&config, utils)
+
+		assert.EqualError(t, err, "1 manifest finding(s) with severity 'error' or higher found")
+		content, _ := utils.FileRead("kubernetes-manifests.sarif")
+		sarif := format.SARIF{}
+		assert.NoError(t, json.Unmarshal(content, &sarif))
+		if assert.Len(t, sarif.Runs[0].Results, 1) {
+			assert.Equal(t, "K8S003", sarif.Runs[0].Results[0].RuleID)
+			assert.Equal(t, "k8s/ingress.yaml", sarif.Runs[0].Results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+		}
+	})
+
+	t.Run("fail on warnings", func(t *testing.T) {
+		t.Parallel()
+		manifest := "apiVersion: v1\nkind: Pod\nmetadata:\n  name: app\nspec:\n  containers:\n  - name: app\n    image: app:latest\n"
+		utils := newKubernetesValidateManifestsTestsUtils()
+		utils.AddFile("pod.yaml", []byte(manifest))
+
+		config := kubernetesValidateManifestsOptions{ManifestFiles: []string{"pod.yaml"}, KubernetesVersion: "1.32", FailOn: "error"}
+		assert.NoError(t, runKubernetesValidateManifests(&config, utils))
+
+		config.FailOn = "warning"
+		assert.EqualError(t, runKubernetesValidateManifests(&config, utils), "4 manifest finding(s) with severity 'warning' or higher found")
+
+		config.DisabledChecks = []string{"K8S101", "K8S102", "K8S103", "K8S104"}
+		assert.NoError(t, runKubernetesValidateManifests(&config, utils))
+	})
+
+	t.Run("helm rendering fails", func(t *testing.T) {
+		t.Parallel()
+		config := kubernetesValidateManifestsOptions{ChartPath: "helm/app", DeploymentName: "app", Namespace: "default", KubernetesVersion: "1.32"}
+		utils := newKubernetesValidateManifestsTestsUtils()
+		utils.ShouldFailOnCommand = map[string]error{"helm template": fmt.Errorf("chart not found")}
+
+		err := runKubernetesValidateManifests(&config, utils)
+
+		assert.EqualError(t, err, "failed to render helm chart 'helm/app': chart not found")
+	})
+
+	t.Run("no manifests", func(t *testing.T) {
+		t.Parallel()
+		config := kubernetesValidateManifestsOptions{KubernetesVersion: "1.32"}
+		utils := newKubernetesValidateManifestsTestsUtils()
+
+		err := runKubernetesValidateManifests(&config, utils)
+
+		assert.EqualError(t, err, "no manifests found, please configure chartPath, kustomizeOverlays or manifestFiles")
+	})
+
+	t.Run("unsupported kubernetes version", func(t *testing.T) {
+		t.Parallel()
+		config := kubernetesValidateManifestsOptions{ManifestFiles: []string{"pod.yaml"}, KubernetesVersion: "1.29"}
+		utils := newKubernetesValidateManifestsTestsUtils()
+		utils.AddFile("pod.yaml", []byte(validManifest))
+
+		err := runKubernetesValidateManifests(&config, utils)
+
+		assert.EqualError(t, err, "unsupported Kubernetes version '1.29', supported versions are 1.32")
+		assert.False(t, utils.HasWrittenFile("kubernetes-manifests.sarif"))
+	})
+}
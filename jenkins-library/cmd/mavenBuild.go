@@ -7,9 +7,12 @@ import (
 	"path/filepath"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/SAP/jenkins-library/pkg/build"
+	"github.com/SAP/jenkins-library/pkg/buildcache"
 	"github.com/SAP/jenkins-library/pkg/buildsettings"
+	"github.com/SAP/jenkins-library/pkg/changeimpact"
 	"github.com/SAP/jenkins-library/pkg/command"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/maven"
@@ -35,10 +38,18 @@ func mavenBuild(config mavenBuildOptions, telemetryData *telemetry.CustomData, c
 		reflect.Indirect(cmd).FieldByName("StepName").SetString("mavenBuild")
 	}
 
+	startedOn := time.Now()
+	cache := restoreBuildCache(buildcache.Maven(config.M2Path), config.BuildCacheBackend, config.BuildCacheLocation, config.BuildCacheEndpoint, telemetryData)
 	err := runMavenBuild(&config, telemetryData, utils, commonPipelineEnvironment)
 	if err != nil {
 		log.Entry().WithError(err).Fatal("step execution failed")
 	}
+	if config.CreateProvenance {
+		if err := createMavenProvenance(&config, startedOn, commonPipelineEnvironment, &piperutils.Files{}); err != nil {
+			log.Entry().WithError(err).Fatal("failed to create provenance")
+		}
+	}
+	saveBuildCache(cache, telemetryData)
 }
 
 func runMakeBOMGoal(config *mavenBuildOptions, utils maven.Utils) error {
@@ -96,9 +107,30 @@ func runMavenBuild(config *mavenBuildOptions, _ *telemetry.CustomData, utils mav
 		flags = append(flags, "--activate-profiles", strings.Join(config.Profiles, ","))
 	}
 
+	var projects []string
+	restricted := false
+	if config.OnlyAffectedModules {
+		var err error
+		projects, restricted, err = affectedMavenProjects(utils, config.PomPath, config.ChangeDetectionBaseRef)
+		if err != nil {
+			return err
+		}
+		if restricted && len(projects) == 0 {
+			log.Entry().Info("no module is affected by the changes, skipping the maven build")
+			return nil
+		}
+	}
+
 	exists, _ := utils.FileExists("integration-tests/pom.xml")
 	if exists {
-		flags = append(flags, "-pl", "!integration-tests")
+		projects = append(projects, "!integration-tests")
+	}
+	if len(projects) > 0 {
+		flags = append(flags, "-pl", strings.Join(projects, ","))
+	}
+	if restricted {
+		// the modules the affected ones depend on have to be part of the reactor
+		flags = append(flags, "--also-make")
 	}
 
 	var defines []string
@@ -373,3 +405,59 @@ func getTempDirForCertFile() string {
 	}
 	return tmpFolder
 }
+
+// mavenGlobalFiles affect all modules of the reactor if they change
+var mavenGlobalFiles = []string{".mvn/**"}
+
+// affectedMavenProjects returns the directories of the affected modules relative to the reactor of the pom file.
+// The second return value is false if the build must not be restricted, since all modules are affected.
+func affectedMavenProjects(utils maven.Utils, pomPath, baseRef string) ([]string, bool, error) {
+	if len(pomPath) == 0 {
+		pomPath = "pom.xml"
+	}
+	modules, err := changeimpact.MavenModules(utils, pomPath)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to detect the maven modules")
+	}
+	affected, ok := affectedModules(modules, baseRef, mavenGlobalFiles)
+	if !ok || len(affected) == len(modules) {
+		return nil, false, nil
+	}
+	projects := []string{}
+	for _, directory := range modulePaths(affected) {
+		project, err := filepath.Rel(filepath.Dir(pomPath), filepath.FromSlash(directory))
+		if err != nil {
+			return nil, false, err
+		}
+		projects = append(projects, filepath.ToSlash(project))
+	}
+	return projects, true, nil
+}
+
+// createMavenProvenance creates the provenance of the artifacts in the target directories of all modules
+func createMavenProvenance(config *mavenBuildOptions, startedOn time.Time, commonPipelineEnvironment *mavenBuildCommonPipelineEnvironment, utils provenanceUtils) error {
+	artifacts := []string{}
+	for _, pattern := range []string{"**/target/*.jar", "**/target/*.war", "**/target/*.ear"} {
+		matches, err := utils.Glob(pattern)
+		if err != nil {
+			return err
+		}
+		artifacts = append(artifacts, matches...)
+	}
+	settings := provenanceSettings{
+		stepName:          "mavenBuild",
+		buildTool:         "maven",
+		signingKey:        config.ProvenanceSigningKey,
+		buildSettingsInfo: commonPipelineEnvironment.custom.buildSettingsInfo,
+		externalParameters: map[string]interface{}{
+			"pomPath":  config.PomPath,
+			"profiles": config.Profiles,
+			"flatten":  config.Flatten,
+			"verify":   config.Verify,
+			"publish":  config.Publish,
+		},
+		startedOn:   startedOn,
+		bomPatterns: []string{"**/" + mvnBomFilename + ".xml"},
+	}
+	return createProvenance(settings, artifacts, nil, utils)
+}
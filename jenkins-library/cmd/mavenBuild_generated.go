@@ -42,6 +42,13 @@ type mavenBuildOptions struct {
 	BuildSettingsInfo               string   `json:"buildSettingsInfo,omitempty"`
 	DeployFlags                     []string `json:"deployFlags,omitempty"`
 	CreateBuildArtifactsMetadata    bool     `json:"createBuildArtifactsMetadata,omitempty"`
+	BuildCacheBackend               string   `json:"buildCacheBackend,omitempty" validate:"possible-values=local s3 gcs"`
+	BuildCacheLocation              string   `json:"buildCacheLocation,omitempty"`
+	BuildCacheEndpoint              string   `json:"buildCacheEndpoint,omitempty"`
+	OnlyAffectedModules             bool     `json:"onlyAffectedModules,omitempty"`
+	ChangeDetectionBaseRef          string   `json:"changeDetectionBaseRef,omitempty"`
+	CreateProvenance                bool     `json:"createProvenance,omitempty"`
+	ProvenanceSigningKey            string   `json:"provenanceSigningKey,omitempty"`
 }
 
 type mavenBuildCommonPipelineEnvironment struct {
@@ -87,6 +94,7 @@ func (p *mavenBuildReports) persist(stepConfig mavenBuildOptions, gcpJsonKeyFile
 		{FilePattern: "**/bom-maven.xml", ParamRef: "", StepResultType: "sbom"},
 		{FilePattern: "**/TEST-*.xml", ParamRef: "", StepResultType: "junit"},
 		{FilePattern: "**/jacoco.xml", ParamRef: "", StepResultType: "jacoco-coverage"},
+		{FilePattern: "**/provenance-*.intoto.jsonl", ParamRef: "", StepResultType: "provenance"},
 	}
 
 	gcsClient, err := gcs.NewClient(gcpJsonKeyFilePath, "")
@@ -198,6 +206,7 @@ general:
 			}
 			log.SetStepErrors(stepErrors)
 			log.RegisterSecret(stepConfig.AltDeploymentRepositoryPassword)
+			log.RegisterSecret(stepConfig.ProvenanceSigningKey)
 
 			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
 				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
@@ -305,6 +314,13 @@ func addMavenBuildFlags(cmd *cobra.Command, stepConfig *mavenBuildOptions) {
 	cmd.Flags().StringVar(&stepConfig.BuildSettingsInfo, "buildSettingsInfo", os.Getenv("PIPER_buildSettingsInfo"), "build settings info is typically filled by the step automatically to create information about the build settings that were used during the maven build . This information is typically used for compliance related processes.")
 	cmd.Flags().StringSliceVar(&stepConfig.DeployFlags, "deployFlags", []string{`-Dmaven.main.skip=true`, `-Dmaven.test.skip=true`, `-Dmaven.install.skip=true`}, "maven deploy flags that will be used when publish is detected.")
 	cmd.Flags().BoolVar(&stepConfig.CreateBuildArtifactsMetadata, "createBuildArtifactsMetadata", false, "metadata about the artifacts that are build and published , this metadata is generally used by steps downstream in the pipeline")
+	cmd.Flags().StringVar(&stepConfig.BuildCacheBackend, "buildCacheBackend", os.Getenv("PIPER_buildCacheBackend"), "Backend of the dependency cache. If set, the dependencies of the build tool are restored before the build from an archive whose key is computed from the lock files of the project and saved after a successful build. Caching is disabled by default.")
+	cmd.Flags().StringVar(&stepConfig.BuildCacheLocation, "buildCacheLocation", os.Getenv("PIPER_buildCacheLocation"), "Location of the dependency cache: a directory for the `local` backend, or a bucket optionally followed by a path prefix for the `s3` and `gcs` backends, e.g. `my-bucket/piper-cache`. The `gcs` backend uses the key file of `gcpJsonKeyFilePath`, the `s3` backend uses the AWS credentials of the environment.")
+	cmd.Flags().StringVar(&stepConfig.BuildCacheEndpoint, "buildCacheEndpoint", os.Getenv("PIPER_buildCacheEndpoint"), "Endpoint of an S3-compatible object store used by the `s3` backend instead of AWS S3, e.g. `https://minio.example.org`.")
+	cmd.Flags().BoolVar(&stepConfig.OnlyAffectedModules, "onlyAffectedModules", false, "Restricts the step to the modules of a monorepo affected by the changes of a pull request, including the modules which depend on them. Changes outside of all modules or to shared lock files affect all modules. Without pull request or base reference all modules are processed.")
+	cmd.Flags().StringVar(&stepConfig.ChangeDetectionBaseRef, "changeDetectionBaseRef", os.Getenv("PIPER_changeDetectionBaseRef"), "Branch or commit the changes are detected against if `onlyAffectedModules` is active. Defaults to the target branch of the pull request provided by the orchestrator.")
+	cmd.Flags().BoolVar(&stepConfig.CreateProvenance, "createProvenance", false, "Creates a [SLSA v1 provenance](https://slsa.dev/spec/v1.0/provenance) of the build outputs as in-toto statement in a DSSE envelope, which is published as step report `provenance-<stepName>.intoto.jsonl`.")
+	cmd.Flags().StringVar(&stepConfig.ProvenanceSigningKey, "provenanceSigningKey", os.Getenv("PIPER_provenanceSigningKey"), "Path to the PEM encoded ECDSA, Ed25519 or RSA private key the provenance is signed with. Without key the provenance is not signed.")
 
 }
 
@@ -332,6 +348,7 @@ func mavenBuildMetadata() config.StepData {
 			Inputs: config.StepInputs{
 				Secrets: []config.StepSecrets{
 					{Name: "altDeploymentRepositoryPasswordId", Description: "Jenkins credentials ID containing the artifact deployment repository password.", Type: "jenkins"},
+					{Name: "provenanceSigningKeyCredentialsId", Description: "Jenkins 'Secret file' credentials ID containing the private key the provenance is signed with.", Type: "jenkins"},
 				},
 				Resources: []config.StepResources{
 					{Type: "stash"},
@@ -564,6 +581,80 @@ func mavenBuildMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     false,
 					},
+					{
+						Name:        "buildCacheBackend",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "GENERAL", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_buildCacheBackend"),
+					},
+					{
+						Name:        "buildCacheLocation",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "GENERAL", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_buildCacheLocation"),
+					},
+					{
+						Name:        "buildCacheEndpoint",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "GENERAL", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_buildCacheEndpoint"),
+					},
+					{
+						Name:        "onlyAffectedModules",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "GENERAL", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name:        "changeDetectionBaseRef",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "GENERAL", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_changeDetectionBaseRef"),
+					},
+					{
+						Name:        "createProvenance",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "GENERAL", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name: "provenanceSigningKey",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "provenanceSigningKeyCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:    "provenanceSigningKeyVaultSecretName",
+								Type:    "vaultSecretFile",
+								Default: "provenance-signing-key",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_provenanceSigningKey"),
+					},
 				},
 			},
 			Containers: []config.Container{
@@ -586,6 +677,7 @@ func mavenBuildMetadata() config.StepData {
 							{"filePattern": "**/bom-maven.xml", "type": "sbom"},
 							{"filePattern": "**/TEST-*.xml", "type": "junit"},
 							{"filePattern": "**/jacoco.xml", "type": "jacoco-coverage"},
+							{"filePattern": "**/provenance-*.intoto.jsonl", "type": "provenance"},
 						},
 					},
 				},
@@ -30,6 +30,7 @@ func GetAllStepMetadata() map[string]config.StepData {
 		"ansSendEvent":                              ansSendEventMetadata(),
 		"apiKeyValueMapDownload":                    apiKeyValueMapDownloadMetadata(),
 		"apiKeyValueMapUpload":                      apiKeyValueMapUploadMetadata(),
+		"apiManagementSync":                         apiManagementSyncMetadata(),
 		"apiProviderDownload":                       apiProviderDownloadMetadata(),
 		"apiProviderList":                           apiProviderListMetadata(),
 		"apiProviderUpload":                         apiProviderUploadMetadata(),
@@ -41,6 +42,7 @@ func GetAllStepMetadata() map[string]config.StepData {
 		"awsS3Upload":                               awsS3UploadMetadata(),
 		"azureBlobUpload":                           azureBlobUploadMetadata(),
 		"batsExecuteTests":                          batsExecuteTestsMetadata(),
+		"cargoBuild":                                cargoBuildMetadata(),
 		"checkmarxExecuteScan":                      checkmarxExecuteScanMetadata(),
 		"checkmarxOneExecuteScan":                   checkmarxOneExecuteScanMetadata(),
 		"cloudFoundryCreateService":                 cloudFoundryCreateServiceMetadata(),
@@ -56,6 +58,7 @@ func GetAllStepMetadata() map[string]config.StepData {
 		"contrastExecuteScan":                       contrastExecuteScanMetadata(),
 		"credentialdiggerScan":                      credentialdiggerScanMetadata(),
 		"detectExecuteScan":                         detectExecuteScanMetadata(),
+		"dotnetBuild":                               dotnetBuildMetadata(),
 		"fortifyExecuteScan":                        fortifyExecuteScanMetadata(),
 		"gaugeExecuteTests":                         gaugeExecuteTestsMetadata(),
 		"gcpPublishEvent":                           gcpPublishEventMetadata(),
@@ -77,8 +80,10 @@ func GetAllStepMetadata() map[string]config.StepData {
 		"hadolintExecute":                           hadolintExecuteMetadata(),
 		"helmExecute":                               helmExecuteMetadata(),
 		"imagePushToRegistry":                       imagePushToRegistryMetadata(),
+		"imageSignature":                            imageSignatureMetadata(),
 		"influxWriteData":                           influxWriteDataMetadata(),
 		"integrationArtifactDeploy":                 integrationArtifactDeployMetadata(),
+		"integrationArtifactDeployMultiTenant":      integrationArtifactDeployMultiTenantMetadata(),
 		"integrationArtifactDownload":               integrationArtifactDownloadMetadata(),
 		"integrationArtifactGetMplStatus":           integrationArtifactGetMplStatusMetadata(),
 		"integrationArtifactGetServiceEndpoint":     integrationArtifactGetServiceEndpointMetadata(),
@@ -88,11 +93,13 @@ func GetAllStepMetadata() map[string]config.StepData {
 		"integrationArtifactUnDeploy":               integrationArtifactUnDeployMetadata(),
 		"integrationArtifactUpdateConfiguration":    integrationArtifactUpdateConfigurationMetadata(),
 		"integrationArtifactUpload":                 integrationArtifactUploadMetadata(),
+		"integrationPackageSync":                    integrationPackageSyncMetadata(),
 		"isChangeInDevelopment":                     isChangeInDevelopmentMetadata(),
 		"jsonApplyPatch":                            jsonApplyPatchMetadata(),
 		"kanikoExecute":                             kanikoExecuteMetadata(),
 		"karmaExecuteTests":                         karmaExecuteTestsMetadata(),
 		"kubernetesDeploy":                          kubernetesDeployMetadata(),
+		"kubernetesValidateManifests":               kubernetesValidateManifestsMetadata(),
 		"malwareExecuteScan":                        malwareExecuteScanMetadata(),
 		"mavenBuild":                                mavenBuildMetadata(),
 		"mavenExecute":                              mavenExecuteMetadata(),
@@ -105,14 +112,20 @@ func GetAllStepMetadata() map[string]config.StepData {
 		"npmExecuteScripts":                         npmExecuteScriptsMetadata(),
 		"npmExecuteTests":                           npmExecuteTestsMetadata(),
 		"pipelineCreateScanSummary":                 pipelineCreateScanSummaryMetadata(),
+		"pipelineNotify":                            pipelineNotifyMetadata(),
 		"protecodeExecuteScan":                      protecodeExecuteScanMetadata(),
 		"pythonBuild":                               pythonBuildMetadata(),
 		"shellExecute":                              shellExecuteMetadata(),
+		"smokeTestExecute":                          smokeTestExecuteMetadata(),
 		"sonarExecuteScan":                          sonarExecuteScanMetadata(),
 		"terraformExecute":                          terraformExecuteMetadata(),
+		"testResultsAggregate":                      testResultsAggregateMetadata(),
 		"tmsExport":                                 tmsExportMetadata(),
 		"tmsUpload":                                 tmsUploadMetadata(),
 		"transportRequestDocIDFromGit":              transportRequestDocIDFromGitMetadata(),
+		"transportRequestLifecycleCTS":              transportRequestLifecycleCTSMetadata(),
+		"transportRequestLifecycleRFC":              transportRequestLifecycleRFCMetadata(),
+		"transportRequestLifecycleSOLMAN":           transportRequestLifecycleSOLMANMetadata(),
 		"transportRequestReqIDFromGit":              transportRequestReqIDFromGitMetadata(),
 		"transportRequestUploadCTS":                 transportRequestUploadCTSMetadata(),
 		"transportRequestUploadRFC":                 transportRequestUploadRFCMetadata(),
@@ -17,6 +17,7 @@ import (
 
 	"github.com/SAP/jenkins-library/pkg/build"
 	"github.com/SAP/jenkins-library/pkg/buildsettings"
+	"github.com/SAP/jenkins-library/pkg/changeimpact"
 	"github.com/SAP/jenkins-library/pkg/npm"
 	"github.com/SAP/jenkins-library/pkg/versioning"
 
@@ -193,19 +194,42 @@ func runMtaBuild(config mtaBuildOptions, commonPipelineEnvironment *mtaBuildComm
 		return err
 	}
 
+	var modules []string
+	restricted := false
+	if config.OnlyAffectedModules {
+		if config.Publish {
+			log.Entry().Info("the mtar archive is published, building all modules")
+		} else {
+			modules, restricted, err = affectedMtaModules(utils, mtaYamlFile, config.ChangeDetectionBaseRef)
+			if err != nil {
+				return err
+			}
+			if restricted && len(modules) == 0 {
+				log.Entry().Info("no module is affected by the changes, skipping the mta build")
+				return nil
+			}
+		}
+	}
+
 	call := []string{"mbt", "build", "--mtar", mtarName, "--platform", platform.String()}
+	if restricted {
+		// the modules the affected ones depend on are built as well, the mtar archive is not assembled
+		call = []string{"mbt", "module-build", "--modules=" + strings.Join(modules, ","), "--with-all-dependencies"}
+	}
 	if len(config.Extensions) != 0 {
 		call = append(call, fmt.Sprintf("--extensions=%s", config.Extensions))
 	}
 
 	call = append(call, "--source", getSourcePath(config))
-	call = append(call, "--target", getAbsPath(getMtarFileRoot(config)))
+	if !restricted {
+		call = append(call, "--target", getAbsPath(getMtarFileRoot(config)))
+	}
 
-	if config.CreateBOM {
+	if config.CreateBOM && !restricted {
 		call = append(call, "--sbom-file-path", filepath.FromSlash("sbom-gen/bom-mta.xml"))
 	}
 
-	if config.Jobs > 0 {
+	if config.Jobs > 0 && !restricted {
 		call = append(call, "--mode=verbose")
 		call = append(call, "--jobs="+strconv.Itoa(config.Jobs))
 	}
@@ -228,6 +252,10 @@ func runMtaBuild(config mtaBuildOptions, commonPipelineEnvironment *mtaBuildComm
 		log.SetErrorCategory(log.ErrorBuild)
 		return err
 	}
+	if restricted {
+		log.Entry().Info("only the affected modules were built, skipping the assembly of the mtar archive")
+		return nil
+	}
 
 	log.Entry().Debugf("creating build settings information...")
 	stepName := "mtaBuild"
@@ -273,6 +301,23 @@ func runMtaBuild(config mtaBuildOptions, commonPipelineEnvironment *mtaBuildComm
 	return nil
 }
 
+// mtaGlobalFiles affect all modules of the mta.yaml if they change
+var mtaGlobalFiles = []string{"**/mta.yaml", "**/*.mtaext"}
+
+// affectedMtaModules returns the names of the affected modules of the mta.yaml file.
+// The second return value is false if the build must not be restricted, since all modules are affected.
+func affectedMtaModules(utils mtaBuildUtils, mtaYamlFile, baseRef string) ([]string, bool, error) {
+	modules, err := changeimpact.MtaModules(utils, mtaYamlFile)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to detect the mta modules")
+	}
+	affected, ok := affectedModules(modules, baseRef, mtaGlobalFiles)
+	if !ok || len(affected) == len(modules) {
+		return nil, false, nil
+	}
+	return changeimpact.Names(affected), true, nil
+}
+
 func handlePublish(config mtaBuildOptions, commonPipelineEnvironment *mtaBuildCommonPipelineEnvironment, utils mtaBuildUtils, mtarName string, isMtarNativelySuffixed bool) error {
 	log.Entry().Infof("publish detected")
 
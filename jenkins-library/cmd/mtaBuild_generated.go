@@ -46,6 +46,8 @@ type mtaBuildOptions struct {
 	CreateBOM                       bool     `json:"createBOM,omitempty"`
 	EnableSetTimestamp              bool     `json:"enableSetTimestamp,omitempty"`
 	CreateBuildArtifactsMetadata    bool     `json:"createBuildArtifactsMetadata,omitempty"`
+	OnlyAffectedModules             bool     `json:"onlyAffectedModules,omitempty"`
+	ChangeDetectionBaseRef          string   `json:"changeDetectionBaseRef,omitempty"`
 }
 
 type mtaBuildCommonPipelineEnvironment struct {
@@ -284,6 +286,8 @@ func addMtaBuildFlags(cmd *cobra.Command, stepConfig *mtaBuildOptions) {
 	cmd.Flags().BoolVar(&stepConfig.CreateBOM, "createBOM", false, "Creates the bill of materials (BOM) using CycloneDX plugin.")
 	cmd.Flags().BoolVar(&stepConfig.EnableSetTimestamp, "enableSetTimestamp", true, "Enables setting the timestamp in the `mta.yaml` when it contains `${timestamp}`. Disable this when you want the MTA Deploy Service to do this instead.")
 	cmd.Flags().BoolVar(&stepConfig.CreateBuildArtifactsMetadata, "createBuildArtifactsMetadata", false, "metadata about the artifacts that are build and published, this metadata is generally used by steps downstream in the pipeline")
+	cmd.Flags().BoolVar(&stepConfig.OnlyAffectedModules, "onlyAffectedModules", false, "Restricts the build to the modules of the `mta.yaml` affected by the changes of a pull request, including the modules which require them. The affected modules are built with `mbt module-build` together with the modules they depend on and the mtar archive is not assembled. The build is not restricted if `publish` is active. Without pull request or base reference all modules are built.")
+	cmd.Flags().StringVar(&stepConfig.ChangeDetectionBaseRef, "changeDetectionBaseRef", os.Getenv("PIPER_changeDetectionBaseRef"), "Branch or commit the changes are detected against if `onlyAffectedModules` is active. Defaults to the target branch of the pull request provided by the orchestrator.")
 
 }
 
@@ -563,6 +567,24 @@ func mtaBuildMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     false,
 					},
+					{
+						Name:        "onlyAffectedModules",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "GENERAL", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name:        "changeDetectionBaseRef",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "GENERAL", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_changeDetectionBaseRef"),
+					},
 				},
 			},
 			Containers: []config.Container{
@@ -3,11 +3,18 @@ package cmd
 import (
 	"encoding/json"
 	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/SAP/jenkins-library/pkg/build"
+	"github.com/SAP/jenkins-library/pkg/buildcache"
 	"github.com/SAP/jenkins-library/pkg/buildsettings"
+	"github.com/SAP/jenkins-library/pkg/changeimpact"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/npm"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 	"github.com/SAP/jenkins-library/pkg/versioning"
 )
@@ -19,11 +26,19 @@ func npmExecuteScripts(config npmExecuteScriptsOptions, telemetryData *telemetry
 	}
 	npmExecutor := npm.NewExecutor(npmExecutorOptions)
 
+	startedOn := time.Now()
+	cache := restoreBuildCache(buildcache.Npm(), config.BuildCacheBackend, config.BuildCacheLocation, config.BuildCacheEndpoint, telemetryData)
 	err := runNpmExecuteScripts(npmExecutor, &config, commonPipelineEnvironment)
 	if err != nil {
 		log.SetErrorCategory(log.ErrorBuild)
 		log.Entry().WithError(err).Fatal("step execution failed")
 	}
+	if config.CreateProvenance {
+		if err := createNpmProvenance(&config, startedOn, commonPipelineEnvironment, &piperutils.Files{}); err != nil {
+			log.Entry().WithError(err).Fatal("failed to create provenance")
+		}
+	}
+	saveBuildCache(cache, telemetryData)
 }
 
 func runNpmExecuteScripts(npmExecutor npm.Executor, config *npmExecuteScriptsOptions, commonPipelineEnvironment *npmExecuteScriptsCommonPipelineEnvironment) error {
@@ -32,6 +47,26 @@ func runNpmExecuteScripts(npmExecutor npm.Executor, config *npmExecuteScriptsOpt
 		os.Setenv("NODE_ENV", "production")
 	}
 
+	if config.OnlyAffectedModules {
+		packageJSONFiles := config.BuildDescriptorList
+		if len(packageJSONFiles) == 0 {
+			var err error
+			packageJSONFiles, err = npmExecutor.FindPackageJSONFilesWithExcludes(config.BuildDescriptorExcludeList)
+			if err != nil {
+				return err
+			}
+		}
+		affected, err := affectedPackageJSONFiles(&piperutils.Files{}, packageJSONFiles, config.ChangeDetectionBaseRef)
+		if err != nil {
+			return err
+		}
+		if len(affected) == 0 {
+			log.Entry().Info("no package is affected by the changes, skipping the execution")
+			return nil
+		}
+		config.BuildDescriptorList = affected
+	}
+
 	if config.Install {
 		if len(config.BuildDescriptorList) > 0 {
 			if err := npmExecutor.InstallAllDependencies(config.BuildDescriptorList); err != nil {
@@ -127,3 +162,53 @@ func runNpmExecuteScripts(npmExecutor npm.Executor, config *npmExecuteScriptsOpt
 
 	return nil
 }
+
+// npmGlobalFiles affect all packages of the repository if they change
+var npmGlobalFiles = []string{"package-lock.json", "npm-shrinkwrap.json", "yarn.lock", "pnpm-lock.yaml", "pnpm-workspace.yaml", ".npmrc"}
+
+// affectedPackageJSONFiles returns the package.json files of the packages affected by the changes
+func affectedPackageJSONFiles(utils changeimpact.Utils, packageJSONFiles []string, baseRef string) ([]string, error) {
+	modules, err := changeimpact.NpmModules(utils, packageJSONFiles)
+	if err != nil {
+		return nil, err
+	}
+	affected, _ := affectedModules(modules, baseRef, npmGlobalFiles)
+	files := []string{}
+	for _, module := range affected {
+		for _, file := range packageJSONFiles {
+			if path.Dir(filepath.ToSlash(file)) == module.Path {
+				files = append(files, file)
+			}
+		}
+	}
+	return files, nil
+}
+
+// createNpmProvenance creates the provenance of the package tarballs, e.g. created by npm pack before publishing
+func createNpmProvenance(config *npmExecuteScriptsOptions, startedOn time.Time, commonPipelineEnvironment *npmExecuteScriptsCommonPipelineEnvironment, utils provenanceUtils) error {
+	matches, err := utils.Glob("**/*.tgz")
+	if err != nil {
+		return err
+	}
+	tarballs := []string{}
+	for _, match := range matches {
+		if !strings.Contains(match, "node_modules") {
+			tarballs = append(tarballs, match)
+		}
+	}
+	settings := provenanceSettings{
+		stepName:          "npmExecuteScripts",
+		buildTool:         "npm",
+		signingKey:        config.ProvenanceSigningKey,
+		buildSettingsInfo: commonPipelineEnvironment.custom.buildSettingsInfo,
+		externalParameters: map[string]interface{}{
+			"runScripts":          config.RunScripts,
+			"buildDescriptorList": config.BuildDescriptorList,
+			"production":          config.Production,
+			"publish":             config.Publish,
+		},
+		startedOn:   startedOn,
+		bomPatterns: []string{"**/bom-npm.xml"},
+	}
+	return createProvenance(settings, tarballs, nil, utils)
+}
@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/ans"
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/notify"
+	"github.com/SAP/jenkins-library/pkg/orchestrator"
+	"github.com/SAP/jenkins-library/pkg/piperenv"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/pkg/errors"
+)
+
+const pipelineNotifyResultsReport = "pipelineNotify_results.json"
+
+type pipelineNotifyUtils interface {
+	FileExists(filename string) (bool, error)
+	FileRead(path string) ([]byte, error)
+	FileWrite(path string, content []byte, perm os.FileMode) error
+	WriteFile(path string, content []byte, perm os.FileMode) error
+	MkdirAll(path string, perm os.FileMode) error
+	Glob(pattern string) (matches []string, err error)
+	GetConfigProvider() (orchestrator.ConfigProvider, error)
+}
+
+type pipelineNotifyUtilsBundle struct {
+	*piperutils.Files
+}
+
+func (p *pipelineNotifyUtilsBundle) GetConfigProvider() (orchestrator.ConfigProvider, error) {
+	return orchestrator.GetOrchestratorConfigProvider(nil)
+}
+
+func pipelineNotify(config pipelineNotifyOptions, telemetryData *telemetry.CustomData) {
+	utils := &pipelineNotifyUtilsBundle{Files: &piperutils.Files{}}
+
+	var ansClient ans.Client
+	if len(config.AnsServiceKey) > 0 {
+		ansClient = &ans.ANS{}
+	}
+
+	err := runPipelineNotify(&config, utils, &piperhttp.Client{}, ansClient)
+	if err != nil {
+		log.Entry().WithError(err).Fatal("step execution failed")
+	}
+}
+
+func runPipelineNotify(config *pipelineNotifyOptions, utils pipelineNotifyUtils, httpClient piperhttp.Sender, ansClient ans.Client) error {
+	rules, channels, err := notify.ParseConfiguration(config.Rules, config.Channels)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return err
+	}
+	if ansClient != nil {
+		serviceKey, err := ans.UnmarshallServiceKeyJSON(config.AnsServiceKey)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return err
+		}
+		ansClient.SetServiceKey(serviceKey)
+	}
+
+	events, err := collectPipelineEvents(utils)
+	if err != nil {
+		return err
+	}
+
+	cpe := piperenv.CPEMap{}
+	if err := cpe.LoadFromDisk(path.Join(GeneralConfig.EnvRootPath, "commonPipelineEnvironment")); err != nil {
+		return errors.Wrap(err, "failed to load values from commonPipelineEnvironment")
+	}
+
+	window := time.Duration(config.DeduplicationWindow) * time.Minute
+	dispatcher := notify.Dispatcher{
+		Rules:      rules,
+		Channels:   channels,
+		HTTPClient: httpClient,
+		ANSClient:  ansClient,
+		CPE:        cpe,
+		Window:     window,
+	}
+	if window > 0 {
+		if dispatcher.State, err = readNotificationState(config.DeduplicationStateFile, utils); err != nil {
+			return err
+		}
+	}
+
+	results := []notify.Result{}
+	var dispatchErr error
+	for _, event := range events {
+		eventResults, err := dispatcher.Dispatch(event)
+		results = append(results, eventResults...)
+		if err != nil {
+			log.Entry().WithError(err).Warnf("Notification about %v event of step '%v' failed", event.Status, event.StepName)
+			dispatchErr = err
+		}
+	}
+	for _, result := range results {
+		switch {
+		case result.Suppressed:
+			log.Entry().Infof("Notification of channel '%v' about step '%v' suppressed, the failure was already reported", result.Channel, result.StepName)
+		case result.Sent:
+			log.Entry().Infof("Channel '%v' notified about %v event", result.Channel, result.Status)
+		}
+	}
+
+	if dispatcher.State != nil {
+		dispatcher.State.Prune(time.Now(), window)
+		if err := writeNotificationState(config.DeduplicationStateFile, dispatcher.State, utils); err != nil {
+			return err
+		}
+	}
+
+	report, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to create notification report")
+	}
+	if err := utils.FileWrite(pipelineNotifyResultsReport, report, 0666); err != nil {
+		return errors.Wrapf(err, "failed to write %v", pipelineNotifyResultsReport)
+	}
+	piperutils.PersistReportsAndLinks("pipelineNotify", "", utils, []piperutils.Path{{Name: "Notification Results", Target: pipelineNotifyResultsReport}}, nil)
+
+	if dispatchErr != nil {
+		log.SetErrorCategory(log.ErrorService)
+		return dispatchErr
+	}
+	return nil
+}
+
+// collectPipelineEvents creates failure events from the error details of failed steps or a success event for the stage
+func collectPipelineEvents(utils pipelineNotifyUtils) ([]notify.Event, error) {
+	files, err := utils.Glob("*_errorDetails.json")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to search for error details")
+	}
+	sort.Strings(files)
+
+	events := []notify.Event{}
+	for _, file := range files {
+		if filepath.Base(file) == "pipelineNotify_errorDetails.json" {
+			continue
+		}
+		content, err := utils.FileRead(file)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read %v", file)
+		}
+		event, err := notify.EventFromErrorDetails(content)
+		if err != nil {
+			log.Entry().WithError(err).Warnf("Ignoring invalid error details in %v", file)
+			continue
+		}
+		events = append(events, event)
+	}
+	if len(events) == 0 {
+		events = append(events, notify.Event{Status: notify.StatusSuccess})
+	}
+
+	provider, err := utils.GetConfigProvider()
+	if err != nil {
+		log.Entry().WithError(err).Warn("Could not determine the orchestrator, events are not enriched with pipeline details")
+		provider = &orchestrator.UnknownOrchestratorConfigProvider{}
+	}
+	for i := range events {
+		events[i].StageName = orchestratorValue(provider.StageName())
+		if len(events[i].StageName) == 0 {
+			events[i].StageName = GeneralConfig.StageName
+		}
+		events[i].Branch = orchestratorValue(provider.Branch())
+		events[i].BuildReason = orchestratorValue(provider.BuildReason())
+		events[i].BuildURL = orchestratorValue(provider.BuildURL())
+		events[i].JobURL = orchestratorValue(provider.JobURL())
+		events[i].CommitID = orchestratorValue(provider.CommitSHA())
+	}
+	return events, nil
+}
+
+// orchestratorValue removes the placeholder of values which are not available
+func orchestratorValue(value string) string {
+	if value == "n/a" {
+		return ""
+	}
+	return value
+}
+
+func readNotificationState(stateFile string, utils pipelineNotifyUtils) (*notify.DeduplicationState, error) {
+	exists, err := utils.FileExists(stateFile)
+	if err != nil || !exists {
+		return notify.NewDeduplicationState(), nil
+	}
+	content, err := utils.FileRead(stateFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %v", stateFile)
+	}
+	state, err := notify.ParseDeduplicationState(content)
+	if err != nil {
+		log.Entry().WithError(err).Warnf("Ignoring invalid notification state in %v", stateFile)
+		return notify.NewDeduplicationState(), nil
+	}
+	return state, nil
+}
+
+func writeNotificationState(stateFile string, state *notify.DeduplicationState, utils pipelineNotifyUtils) error {
+	content, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to create notification state")
+	}
+	if err := utils.MkdirAll(filepath.Dir(stateFile), 0777); err != nil {
+		return errors.Wrapf(err, "failed to create directory of %v", stateFile)
+	}
+	if err := utils.FileWrite(stateFile, content, 0666); err != nil {
+		return errors.Wrapf(err, "failed to write %v", stateFile)
+	}
+	return nil
+}
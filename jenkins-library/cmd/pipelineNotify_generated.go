@@ -0,0 +1,311 @@
+// Code generated by piper's step-generator. DO NOT EDIT.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/gcp"
+	"github.com/SAP/jenkins-library/pkg/gcs"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/splunk"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/SAP/jenkins-library/pkg/validation"
+	"github.com/bmatcuk/doublestar"
+	"github.com/spf13/cobra"
+)
+
+type pipelineNotifyOptions struct {
+	Rules                  []map[string]interface{} `json:"rules,omitempty"`
+	Channels               []map[string]interface{} `json:"channels,omitempty"`
+	AnsServiceKey          string                   `json:"ansServiceKey,omitempty"`
+	DeduplicationStateFile string                   `json:"deduplicationStateFile,omitempty"`
+	DeduplicationWindow    int                      `json:"deduplicationWindow,omitempty"`
+}
+
+type pipelineNotifyReports struct {
+}
+
+func (p *pipelineNotifyReports) persist(stepConfig pipelineNotifyOptions, gcpJsonKeyFilePath string, gcsBucketId string, gcsFolderPath string, gcsSubFolder string) {
+	if gcsBucketId == "" {
+		log.Entry().Info("persisting reports to GCS is disabled, because gcsBucketId is empty")
+		return
+	}
+	log.Entry().Info("Uploading reports to Google Cloud Storage...")
+	content := []gcs.ReportOutputParam{
+		{FilePattern: "pipelineNotify_results.json", ParamRef: "", StepResultType: "notification-results"},
+	}
+
+	gcsClient, err := gcs.NewClient(gcpJsonKeyFilePath, "")
+	if err != nil {
+		log.Entry().Errorf("creation of GCS client failed: %v", err)
+		return
+	}
+	defer gcsClient.Close()
+	structVal := reflect.ValueOf(&stepConfig).Elem()
+	inputParameters := map[string]string{}
+	for i := 0; i < structVal.NumField(); i++ {
+		field := structVal.Type().Field(i)
+		if field.Type.String() == "string" {
+			paramName := strings.Split(field.Tag.Get("json"), ",")
+			paramValue, _ := structVal.Field(i).Interface().(string)
+			inputParameters[paramName[0]] = paramValue
+		}
+	}
+	if err := gcs.PersistReportsToGCS(gcsClient, content, inputParameters, gcsFolderPath, gcsBucketId, gcsSubFolder, doublestar.Glob, os.Stat); err != nil {
+		log.Entry().Errorf("failed to persist reports: %v", err)
+	}
+}
+
+// PipelineNotifyCommand Route pipeline events to the SAP Alert Notification Service, webhooks, Slack and Microsoft Teams based on rules
+func PipelineNotifyCommand() *cobra.Command {
+	const STEP_NAME = "pipelineNotify"
+
+	metadata := pipelineNotifyMetadata()
+	var stepConfig pipelineNotifyOptions
+	var startTime time.Time
+	var reports pipelineNotifyReports
+	var logCollector *log.CollectorHook
+	var splunkClient *splunk.Splunk
+	telemetryClient := &telemetry.Telemetry{}
+
+	var createPipelineNotifyCmd = &cobra.Command{
+		Use:   STEP_NAME,
+		Short: "Route pipeline events to the SAP Alert Notification Service, webhooks, Slack and Microsoft Teams based on rules",
+		Long: `With this step you can notify different channels about failed steps and successful stages of the pipeline.
+
+The step creates a failure event for every ` + "`" + `<stepName>_errorDetails.json` + "`" + ` file in the workspace, which is written when a step fails.
+If no step failed, a success event for the current stage is created. The events are enriched with the stage, branch, build reason and build URL of the orchestrator.
+
+Each rule in ` + "`" + `rules` + "`" + ` selects events by the following properties, all properties are optional and all defined properties have to match:
+
+* ` + "`" + `steps` + "`" + `, ` + "`" + `stages` + "`" + `, ` + "`" + `branches` + "`" + ` - lists of glob patterns, e.g. ` + "`" + `release/*` + "`" + `
+* ` + "`" + `errorCategories` + "`" + ` - list of error categories, e.g. ` + "`" + `build` + "`" + `, ` + "`" + `compliance` + "`" + `, ` + "`" + `config` + "`" + `, ` + "`" + `infrastructure` + "`" + `, ` + "`" + `service` + "`" + ` or ` + "`" + `test` + "`" + `
+* ` + "`" + `buildReasons` + "`" + ` - list of build reasons, e.g. ` + "`" + `Manual` + "`" + `, ` + "`" + `Schedule` + "`" + ` or ` + "`" + `PullRequest` + "`" + `
+* ` + "`" + `status` + "`" + ` - list of event states ` + "`" + `failure` + "`" + ` and ` + "`" + `success` + "`" + `, by default only failures are routed
+
+The events are sent to the ` + "`" + `channels` + "`" + ` of all matching rules, every channel is notified once per event. Each channel in ` + "`" + `channels` + "`" + ` has a ` + "`" + `name` + "`" + ` and a ` + "`" + `type` + "`" + `:
+
+* ` + "`" + `ans` + "`" + ` - SAP Alert Notification Service, requires ` + "`" + `ansServiceKey` + "`" + `
+* ` + "`" + `slack` + "`" + ` - Slack incoming webhook
+* ` + "`" + `teams` + "`" + ` - Microsoft Teams incoming webhook
+* ` + "`" + `webhook` + "`" + ` - generic webhook, by default the event is posted as JSON
+
+Webhook channels require the ` + "`" + `url` + "`" + ` or ` + "`" + `urlEnv` + "`" + `, the name of an environment variable containing the URL. Optional ` + "`" + `headers` + "`" + ` are added to the request.
+The optional ` + "`" + `template` + "`" + ` defines the message of the channel, for generic webhooks it is the complete request body. Templates use the Go template syntax,
+the event is available as ` + "`" + `.Event` + "`" + `, e.g. ` + "`" + `{{ .Event.StepName }}` + "`" + `, and values of the common pipeline environment are available via ` + "`" + `{{ cpe "artifactVersion" }}` + "`" + `.
+
+Repeated identical failures are sent to a channel only once within ` + "`" + `deduplicationWindow` + "`" + `. The state is persisted in ` + "`" + `deduplicationStateFile` + "`" + `,
+a success of the stage resets the state so that a recurring failure is reported again.`,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			startTime = time.Now()
+			log.SetStepName(STEP_NAME)
+			log.SetVerbose(GeneralConfig.Verbose)
+
+			GeneralConfig.GitHubAccessTokens = ResolveAccessTokens(GeneralConfig.GitHubTokens)
+
+			path, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+			fatalHook := &log.FatalHook{CorrelationID: GeneralConfig.CorrelationID, Path: path}
+			log.RegisterHook(fatalHook)
+
+			err = PrepareConfig(cmd, &metadata, STEP_NAME, &stepConfig, config.OpenPiperFile)
+			if err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+
+			// Set step error patterns for improved error detection
+			stepErrors := make([]log.StepError, len(metadata.Metadata.Errors))
+			for i, err := range metadata.Metadata.Errors {
+				stepErrors[i] = log.StepError{
+					Pattern:  err.Pattern,
+					Message:  err.Message,
+					Category: err.Category,
+				}
+			}
+			log.SetStepErrors(stepErrors)
+			log.RegisterSecret(stepConfig.AnsServiceKey)
+
+			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
+				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
+				log.RegisterHook(&sentryHook)
+			}
+
+			if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 || len(GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint) > 0 {
+				splunkClient = &splunk.Splunk{}
+				logCollector = &log.CollectorHook{CorrelationID: GeneralConfig.CorrelationID}
+				log.RegisterHook(logCollector)
+			}
+
+			if err = log.RegisterANSHookIfConfigured(GeneralConfig.CorrelationID); err != nil {
+				log.Entry().WithError(err).Warn("failed to set up SAP Alert Notification Service log hook")
+			}
+
+			validation, err := validation.New(validation.WithJSONNamesForStructFields(), validation.WithPredefinedErrorMessages())
+			if err != nil {
+				return err
+			}
+			if err = validation.ValidateStruct(stepConfig); err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+
+			return nil
+		},
+		Run: func(_ *cobra.Command, _ []string) {
+			vaultClient := config.GlobalVaultClient()
+			if vaultClient != nil {
+				defer vaultClient.MustRevokeToken()
+			}
+
+			stepTelemetryData := telemetry.CustomData{}
+			stepTelemetryData.ErrorCode = "1"
+			handler := func() {
+				reports.persist(stepConfig, GeneralConfig.GCPJsonKeyFilePath, GeneralConfig.GCSBucketId, GeneralConfig.GCSFolderPath, GeneralConfig.GCSSubFolder)
+				config.RemoveVaultSecretFiles()
+				stepTelemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
+				stepTelemetryData.ErrorCategory = log.GetErrorCategory().String()
+				stepTelemetryData.PiperCommitHash = GitCommit
+				telemetryClient.SetData(&stepTelemetryData)
+				telemetryClient.LogStepTelemetryData()
+				if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+					splunkClient.Initialize(GeneralConfig.CorrelationID,
+						GeneralConfig.HookConfig.SplunkConfig.Dsn,
+						GeneralConfig.HookConfig.SplunkConfig.Token,
+						GeneralConfig.HookConfig.SplunkConfig.Index,
+						GeneralConfig.HookConfig.SplunkConfig.SendLogs)
+					splunkClient.Send(telemetryClient.GetData(), logCollector)
+				}
+				if len(GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint) > 0 {
+					splunkClient.Initialize(GeneralConfig.CorrelationID,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblToken,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblIndex,
+						GeneralConfig.HookConfig.SplunkConfig.SendLogs)
+					splunkClient.Send(telemetryClient.GetData(), logCollector)
+				}
+				if GeneralConfig.HookConfig.GCPPubSubConfig.Enabled {
+					err := gcp.NewGcpPubsubClient(
+						vaultClient,
+						GeneralConfig.HookConfig.GCPPubSubConfig.ProjectNumber,
+						GeneralConfig.HookConfig.GCPPubSubConfig.IdentityPool,
+						GeneralConfig.HookConfig.GCPPubSubConfig.IdentityProvider,
+						GeneralConfig.CorrelationID,
+						GeneralConfig.HookConfig.OIDCConfig.RoleID,
+					).Publish(GeneralConfig.HookConfig.GCPPubSubConfig.Topic, telemetryClient.GetDataBytes())
+					if err != nil {
+						log.Entry().WithError(err).Warn("event publish failed")
+					}
+				}
+			}
+			log.DeferExitHandler(handler)
+			defer handler()
+			telemetryClient.Initialize(STEP_NAME)
+			pipelineNotify(stepConfig, &stepTelemetryData)
+			stepTelemetryData.ErrorCode = "0"
+			log.Entry().Info("SUCCESS")
+		},
+	}
+
+	addPipelineNotifyFlags(createPipelineNotifyCmd, &stepConfig)
+	return createPipelineNotifyCmd
+}
+
+func addPipelineNotifyFlags(cmd *cobra.Command, stepConfig *pipelineNotifyOptions) {
+
+	cmd.Flags().StringVar(&stepConfig.AnsServiceKey, "ansServiceKey", os.Getenv("PIPER_ansServiceKey"), "Service key JSON string to access the SAP Alert Notification Service, required for channels of type `ans`")
+	cmd.Flags().StringVar(&stepConfig.DeduplicationStateFile, "deduplicationStateFile", `.pipeline/notificationState.json`, "Path of the file which stores the notifications sent, the file has to be preserved between pipeline runs for deduplication across runs")
+	cmd.Flags().IntVar(&stepConfig.DeduplicationWindow, "deduplicationWindow", 60, "Time in minutes in which an identical failure is sent to a channel only once, `0` disables the deduplication")
+
+	cmd.MarkFlagRequired("rules")
+	cmd.MarkFlagRequired("channels")
+}
+
+// retrieve step metadata
+func pipelineNotifyMetadata() config.StepData {
+	var theMetaData = config.StepData{
+		Metadata: config.StepMetadata{
+			Name:        "pipelineNotify",
+			Aliases:     []config.Alias{},
+			Description: "Route pipeline events to the SAP Alert Notification Service, webhooks, Slack and Microsoft Teams based on rules",
+		},
+		Spec: config.StepSpec{
+			Inputs: config.StepInputs{
+				Secrets: []config.StepSecrets{
+					{Name: "ansServiceKeyCredentialsId", Description: "Jenkins secret text credential ID containing the service key to access the SAP Alert Notification Service", Type: "jenkins"},
+				},
+				Parameters: []config.StepParameters{
+					{
+						Name:        "rules",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS", "GENERAL"},
+						Type:        "[]map[string]interface{}",
+						Mandatory:   true,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "channels",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS", "GENERAL"},
+						Type:        "[]map[string]interface{}",
+						Mandatory:   true,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name: "ansServiceKey",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "ansServiceKeyCredentialsId",
+								Param: "ansServiceKey",
+								Type:  "secret",
+							},
+						},
+						Scope:     []string{"PARAMETERS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_ansServiceKey"),
+					},
+					{
+						Name:        "deduplicationStateFile",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS", "GENERAL"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `.pipeline/notificationState.json`,
+					},
+					{
+						Name:        "deduplicationWindow",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS", "GENERAL"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     60,
+					},
+				},
+			},
+			Outputs: config.StepOutputs{
+				Resources: []config.StepResources{
+					{
+						Name: "reports",
+						Type: "reports",
+						Parameters: []map[string]interface{}{
+							{"filePattern": "pipelineNotify_results.json", "type": "notification-results"},
+						},
+					},
+				},
+			},
+		},
+	}
+	return theMetaData
+}
@@ -0,0 +1,157 @@
+//go:build unit
+// +build unit
+
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/SAP/jenkins-library/pkg/notify"
+	"github.com/SAP/jenkins-library/pkg/orchestrator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type pipelineNotifyMockUtils struct {
+	*mock.FilesMock
+}
+
+type pipelineNotifyOrchestratorMock struct {
+	orchestrator.UnknownOrchestratorConfigProvider
+}
+
+func (o *pipelineNotifyOrchestratorMock) StageName() string   { return "Build" }
+func (o *pipelineNotifyOrchestratorMock) Branch() string      { return "main" }
+func (o *pipelineNotifyOrchestratorMock) BuildReason() string { return "Schedule" }
+func (o *pipelineNotifyOrchestratorMock) BuildURL() string    { return "https://ci/job/1" }
+
+func (p *pipelineNotifyMockUtils) GetConfigProvider() (orchestrator.ConfigProvider, error) {
+	return &pipelineNotifyOrchestratorMock{}, nil
+}
+
+func newPipelineNotifyTestsUtils() *pipelineNotifyMockUtils {
+	return &pipelineNotifyMockUtils{FilesMock: &mock.FilesMock{}}
+}
+
+type slackReceiver struct {
+	*httptest.Server
+	mu       sync.Mutex
+	messages []string
+}
+
+func newSlackReceiver(t *testing.T) *slackReceiver {
+	receiver := &slackReceiver{}
+	receiver.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]string
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &payload)
+		receiver.mu.Lock()
+		defer receiver.mu.Unlock()
+		receiver.messages = append(receiver.messages, payload["text"])
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(receiver.Close)
+	return receiver
+}
+
+func (r *slackReceiver) received() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string{}, r.messages...)
+}
+
+func TestRunPipelineNotify(t *testing.T) {
+	t.Parallel()
+
+	newConfig := func(url string) pipelineNotifyOptions {
+		return pipelineNotifyOptions{
+			Rules: []map[string]interface{}{
+				{"name": "build failures on main", "branches": []interface{}{"main"}, "errorCategories": []interface{}{"build"}, "channels": []interface{}{"team"}},
+				{"name": "successes", "status": []interface{}{"success"}, "channels": []interface{}{"team", "alerts"}},
+			},
+			Channels: []map[string]interface{}{
+				{"name": "team", "type": "slack", "url": url},
+				{"name": "alerts", "type": "ans"},
+			},
+			AnsServiceKey:          `{"url": "https://my.test.backend", "client_id": "id", "client_secret": "secret", "oauth_url": "https://my.test.oauth.provider"}`,
+			DeduplicationStateFile: ".pipeline/notificationState.json",
+			DeduplicationWindow:    60,
+		}
+	}
+
+	t.Run("failure is routed and deduplicated", func(t *testing.T) {
+		t.Parallel()
+		receiver := newSlackReceiver(t)
+		config := newConfig(receiver.URL)
+		utils := newPipelineNotifyTestsUtils()
+		utils.AddFile("mavenBuild_errorDetails.json", []byte(`{"stepName": "mavenBuild", "category": "build", "message": "compilation failed", "error": "exit status 1"}`))
+		utils.AddFile("npmExecuteLint_errorDetails.json", []byte(`{"stepName": "npmExecuteLint", "category": "config", "message": "invalid config"}`))
+		ansClient := &ansMock{}
+
+		err := runPipelineNotify(&config, utils, &piperhttp.Client{}, ansClient)
+		require.NoError(t, err)
+		err = runPipelineNotify(&config, utils, &piperhttp.Client{}, ansClient)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"Step 'mavenBuild' failed in stage 'Build' on branch 'main': compilation failed: exit status 1 (https://ci/job/1)"}, receiver.received())
+		assert.Empty(t, ansClient.testEvent.Subject)
+
+		content, err := utils.FileRead(".pipeline/notificationState.json")
+		require.NoError(t, err)
+		assert.Contains(t, string(content), `"stepName": "mavenBuild"`)
+
+		report, err := utils.FileRead(pipelineNotifyResultsReport)
+		require.NoError(t, err)
+		var results []notify.Result
+		require.NoError(t, json.Unmarshal(report, &results))
+		assert.Equal(t, []notify.Result{{Channel: "team", StepName: "mavenBuild", Status: "failure", Suppressed: true}}, results)
+	})
+
+	t.Run("success of the stage", func(t *testing.T) {
+		t.Parallel()
+		receiver := newSlackReceiver(t)
+		config := newConfig(receiver.URL)
+		utils := newPipelineNotifyTestsUtils()
+		utils.AddFile(".pipeline/notificationState.json", []byte(`{"notifications": {"team/abc": {"channel": "team", "stepName": "mavenBuild", "stageName": "Build", "branch": "main", "time": "2100-01-01T00:00:00Z"}}}`))
+		ansClient := &ansMock{}
+
+		err := runPipelineNotify(&config, utils, &piperhttp.Client{}, ansClient)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Stage 'Build' succeeded on branch 'main' (https://ci/job/1)"}, receiver.received())
+		assert.Equal(t, "Pipeline stage Build succeeded", ansClient.testEvent.Subject)
+		assert.Equal(t, "https://my.test.backend", ansClient.testANS.URL)
+		content, err := utils.FileRead(".pipeline/notificationState.json")
+		require.NoError(t, err)
+		assert.NotContains(t, string(content), "mavenBuild")
+	})
+
+	t.Run("invalid rules", func(t *testing.T) {
+		t.Parallel()
+		config := newConfig("https://hooks.slack.example")
+		config.Rules = []map[string]interface{}{{"name": "r", "channels": []interface{}{"unknown"}}}
+
+		err := runPipelineNotify(&config, newPipelineNotifyTestsUtils(), &piperhttp.Client{}, &ansMock{})
+
+		assert.EqualError(t, err, "notification rule 'r' refers to unknown channel 'unknown'")
+	})
+
+	t.Run("failing channel", func(t *testing.T) {
+		t.Parallel()
+		config := newConfig("https://hooks.slack.example")
+		utils := newPipelineNotifyTestsUtils()
+
+		err := runPipelineNotify(&config, utils, &piperhttp.Client{}, &ansMock{failToSend: true})
+
+		assert.EqualError(t, err, "failed to notify channels: team: did not retrieve a HTTP response from the webhook; alerts: failed to send")
+		exists, _ := utils.FileExists(pipelineNotifyResultsReport)
+		assert.True(t, exists)
+	})
+}
@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	"github.com/SAP/jenkins-library/pkg/config"
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/orchestrator"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
@@ -124,6 +125,7 @@ func Execute() {
 	rootCmd.AddCommand(SonarExecuteScanCommand())
 	rootCmd.AddCommand(KubernetesDeployCommand())
 	rootCmd.AddCommand(HelmExecuteCommand())
+	rootCmd.AddCommand(KubernetesValidateManifestsCommand())
 	rootCmd.AddCommand(XsDeployCommand())
 	rootCmd.AddCommand(GithubCheckBranchProtectionCommand())
 	rootCmd.AddCommand(GithubCommentIssueCommand())
@@ -188,13 +190,18 @@ func Execute() {
 	rootCmd.AddCommand(TransportRequestUploadRFCCommand())
 	rootCmd.AddCommand(NewmanExecuteCommand())
 	rootCmd.AddCommand(IntegrationArtifactDeployCommand())
+	rootCmd.AddCommand(IntegrationArtifactDeployMultiTenantCommand())
 	rootCmd.AddCommand(TransportRequestUploadSOLMANCommand())
+	rootCmd.AddCommand(TransportRequestLifecycleCTSCommand())
+	rootCmd.AddCommand(TransportRequestLifecycleRFCCommand())
+	rootCmd.AddCommand(TransportRequestLifecycleSOLMANCommand())
 	rootCmd.AddCommand(IntegrationArtifactUpdateConfigurationCommand())
 	rootCmd.AddCommand(IntegrationArtifactGetMplStatusCommand())
 	rootCmd.AddCommand(IntegrationArtifactGetServiceEndpointCommand())
 	rootCmd.AddCommand(IntegrationArtifactDownloadCommand())
 	rootCmd.AddCommand(AbapEnvironmentAssembleConfirmCommand())
 	rootCmd.AddCommand(IntegrationArtifactUploadCommand())
+	rootCmd.AddCommand(IntegrationPackageSyncCommand())
 	rootCmd.AddCommand(IntegrationArtifactTriggerIntegrationTestCommand())
 	rootCmd.AddCommand(IntegrationArtifactUnDeployCommand())
 	rootCmd.AddCommand(IntegrationArtifactResourceCommand())
@@ -211,11 +218,16 @@ func Execute() {
 	rootCmd.AddCommand(AbapEnvironmentRunAUnitTestCommand())
 	rootCmd.AddCommand(CheckStepActiveCommand())
 	rootCmd.AddCommand(GolangBuildCommand())
+	rootCmd.AddCommand(CargoBuildCommand())
+	rootCmd.AddCommand(DotnetBuildCommand())
+	rootCmd.AddCommand(TestResultsAggregateCommand())
 	rootCmd.AddCommand(ShellExecuteCommand())
+	rootCmd.AddCommand(SmokeTestExecuteCommand())
 	rootCmd.AddCommand(ApiProxyDownloadCommand())
 	rootCmd.AddCommand(ApiKeyValueMapDownloadCommand())
 	rootCmd.AddCommand(ApiProviderDownloadCommand())
 	rootCmd.AddCommand(ApiProxyUploadCommand())
+	rootCmd.AddCommand(ApiManagementSyncCommand())
 	rootCmd.AddCommand(GradleExecuteBuildCommand())
 	rootCmd.AddCommand(ApiKeyValueMapUploadCommand())
 	rootCmd.AddCommand(PythonBuildCommand())
@@ -223,6 +235,7 @@ func Execute() {
 	rootCmd.AddCommand(AwsS3UploadCommand())
 	rootCmd.AddCommand(ApiProxyListCommand())
 	rootCmd.AddCommand(AnsSendEventCommand())
+	rootCmd.AddCommand(PipelineNotifyCommand())
 	rootCmd.AddCommand(ApiProviderListCommand())
 	rootCmd.AddCommand(TmsUploadCommand())
 	rootCmd.AddCommand(TmsExportCommand())
@@ -230,6 +243,7 @@ func Execute() {
 	rootCmd.AddCommand(AscAppUploadCommand())
 	rootCmd.AddCommand(AbapLandscapePortalUpdateAddOnProductCommand())
 	rootCmd.AddCommand(ImagePushToRegistryCommand())
+	rootCmd.AddCommand(ImageSignatureCommand())
 
 	addRootFlags(rootCmd)
 
@@ -352,6 +366,9 @@ func PrepareConfig(cmd *cobra.Command, metadata *config.StepData, stepName strin
 
 	initStageName(true)
 
+	// share OAuth2 access tokens with the following steps of the pipeline run
+	piperhttp.SetTokenCacheDirectory(filepath.Join(GeneralConfig.EnvRootPath, "oauthTokens"))
+
 	filters := metadata.GetParameterFilters()
 
 	// add telemetry parameter "collectTelemetryData" to ALL, GENERAL and PARAMETER filters
@@ -0,0 +1,226 @@
+package cmd
+
+import (
+	"crypto"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/docker"
+	"github.com/SAP/jenkins-library/pkg/git"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/orchestrator"
+	"github.com/SAP/jenkins-library/pkg/provenance"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/pkg/errors"
+)
+
+// provenanceBuilderID identifies piper as builder if the orchestrator does not provide the URL of the job
+const provenanceBuilderID = "https://github.com/SAP/jenkins-library"
+
+// provenanceSettings describes the build a step creates a provenance for
+type provenanceSettings struct {
+	stepName  string
+	buildTool string
+	// signingKey is the path of the private key, the provenance is not signed without key
+	signingKey string
+	// dockerConfigJSON provides the credentials to push the provenance of images
+	dockerConfigJSON   string
+	buildSettingsInfo  string
+	externalParameters map[string]interface{}
+	startedOn          time.Time
+	// bomPatterns locate the CycloneDX BOMs listing the resolved dependencies
+	bomPatterns []string
+}
+
+type provenanceUtils interface {
+	FileRead(path string) ([]byte, error)
+	FileWrite(path string, content []byte, perm os.FileMode) error
+	Glob(pattern string) (matches []string, err error)
+}
+
+// publishProvenanceReferrer pushes the provenance of an image to its registry, it is a variable to be replaced in tests
+var publishProvenanceReferrer = func(envelope provenance.Envelope, image string, keychain authn.Keychain) (name.Digest, error) {
+	return provenance.PublishReferrer(envelope, image, remote.WithAuthFromKeychain(keychain))
+}
+
+// createProvenance creates the provenance of the files and images built by the step and writes it as step report.
+// Images are given by digest, e.g. registry/image@sha256:..., their provenance is also pushed as OCI referrer.
+func createProvenance(settings provenanceSettings, files, images []string, utils provenanceUtils) error {
+	subjects := []provenance.ResourceDescriptor{}
+	for _, file := range files {
+		subject, err := provenance.FileSubject(utils, file)
+		if err != nil {
+			return err
+		}
+		subjects = append(subjects, subject)
+	}
+	for _, image := range images {
+		imageName, digest, _ := strings.Cut(image, "@")
+		subject, err := provenance.ImageSubject(imageName, digest)
+		if err != nil {
+			return err
+		}
+		subjects = append(subjects, subject)
+	}
+	if len(subjects) == 0 {
+		log.Entry().Warn("no build outputs found, skipping the creation of the provenance")
+		return nil
+	}
+
+	dependencies, err := provenanceDependencies(settings.bomPatterns, utils)
+	if err != nil {
+		return err
+	}
+	options := provenance.Options{
+		BuildTool:          settings.buildTool,
+		StepName:           settings.stepName,
+		BuilderID:          provenanceBuilderID,
+		StartedOn:          settings.startedOn,
+		FinishedOn:         time.Now(),
+		ExternalParameters: settings.externalParameters,
+		BuildSettingsInfo:  settings.buildSettingsInfo,
+		Dependencies:       dependencies,
+		Subjects:           subjects,
+	}
+	repositoryURL, commitID := "", ""
+	if provider, err := orchestrator.GetOrchestratorConfigProvider(nil); err == nil {
+		if jobURL := orchestratorValue(provider.JobURL()); len(jobURL) > 0 {
+			options.BuilderID = jobURL
+		}
+		options.InvocationID = orchestratorValue(provider.BuildURL())
+		repositoryURL = orchestratorValue(provider.RepoURL())
+		commitID = orchestratorValue(provider.CommitSHA())
+	}
+	if len(commitID) == 0 {
+		repositoryURL, commitID = localGitSource()
+	}
+	options.Source = provenance.GitSource(repositoryURL, commitID)
+
+	var signer crypto.Signer
+	if len(settings.signingKey) > 0 {
+		key, err := utils.FileRead(settings.signingKey)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return errors.Wrap(err, "failed to read the provenance signing key")
+		}
+		if signer, err = provenance.LoadSigner(key); err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return err
+		}
+	} else {
+		log.Entry().Warn("no signing key configured, the provenance is not signed")
+	}
+	envelope, err := provenance.Sign(provenance.New(options), signer)
+	if err != nil {
+		return err
+	}
+
+	content, err := json.Marshal(envelope)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal provenance")
+	}
+	reportFile := fmt.Sprintf("provenance-%v.intoto.jsonl", settings.stepName)
+	if err := utils.FileWrite(reportFile, append(content, '\n'), 0o644); err != nil {
+		return errors.Wrapf(err, "failed to write %v", reportFile)
+	}
+	log.Entry().Infof("provenance of %v build outputs written to %v", len(subjects), reportFile)
+
+	if len(images) == 0 {
+		return nil
+	}
+	var dockerConfig []byte
+	if len(settings.dockerConfigJSON) > 0 {
+		if dockerConfig, err = utils.FileRead(settings.dockerConfigJSON); err != nil {
+			return errors.Wrapf(err, "failed to read %v", settings.dockerConfigJSON)
+		}
+	}
+	keychain, err := docker.NewKeychain(dockerConfig)
+	if err != nil {
+		return err
+	}
+	for _, image := range images {
+		artifact, err := publishProvenanceReferrer(envelope, image, keychain)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorInfrastructure)
+			return err
+		}
+		log.Entry().Infof("provenance of %v pushed as %v", image, artifact)
+	}
+	return nil
+}
+
+func provenanceDependencies(bomPatterns []string, utils provenanceUtils) ([]provenance.ResourceDescriptor, error) {
+	bomFiles := []string{}
+	for _, pattern := range bomPatterns {
+		matches, err := utils.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range matches {
+			if !strings.Contains(match, "node_modules") {
+				bomFiles = append(bomFiles, match)
+			}
+		}
+	}
+	return provenance.BomDependencies(bomFiles)
+}
+
+func localGitSource() (string, string) {
+	repo, err := git.PlainOpen(".")
+	if err != nil {
+		return "", ""
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return "", ""
+	}
+	repositoryURL := ""
+	if remote, err := repo.Remote("origin"); err == nil && len(remote.Config().URLs) > 0 {
+		repositoryURL = remote.Config().URLs[0]
+	}
+	return repositoryURL, head.Hash().String()
+}
+
+// builtImages returns the images built by a step by digest, the tags and digests are taken from the common pipeline environment
+func builtImages(registryURL string, imageNameTags, imageDigests []string) []string {
+	if len(imageDigests) == 0 {
+		return nil
+	}
+	if len(imageDigests) != 1 && len(imageDigests) != len(imageNameTags) {
+		log.Entry().Warnf("cannot assign the %v image digests to the %v images", len(imageDigests), len(imageNameTags))
+		return nil
+	}
+	images := []string{}
+	known := map[string]bool{}
+	for i, imageNameTag := range imageNameTags {
+		digest := imageDigests[0]
+		if len(imageDigests) > 1 {
+			digest = imageDigests[i]
+		}
+		tag, err := name.NewTag(registryImage(registryURL, imageNameTag))
+		if err != nil {
+			log.Entry().WithError(err).Warnf("invalid image '%v'", imageNameTag)
+			continue
+		}
+		image := tag.Context().Name() + "@" + strings.TrimSpace(digest)
+		if !known[image] {
+			known[image] = true
+			images = append(images, image)
+		}
+	}
+	return images
+}
+
+// registryImage prefixes the image name and tag with the host of the registry URL
+func registryImage(registryURL, imageNameTag string) string {
+	registry := strings.TrimPrefix(strings.TrimPrefix(registryURL, "https://"), "http://")
+	if len(registry) == 0 {
+		return imageNameTag
+	}
+	return strings.TrimSuffix(registry, "/") + "/" + imageNameTag
+}
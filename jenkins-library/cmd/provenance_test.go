@@ -0,0 +1,132 @@
+//go:build unit
+// +build unit
+
+package cmd
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/SAP/jenkins-library/pkg/provenance"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateProvenance(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	der, _ := x509.MarshalECPrivateKey(key)
+	settings := provenanceSettings{
+		stepName:           "kanikoExecute",
+		buildTool:          "kaniko",
+		signingKey:         "signing.key",
+		dockerConfigJSON:   "config.json",
+		buildSettingsInfo:  `{"kanikoExecute":[{"dockerImage":"kaniko"}]}`,
+		externalParameters: map[string]interface{}{"dockerfilePath": "Dockerfile"},
+		startedOn:          time.Now(),
+		bomPatterns:        []string{"bom-*.xml"},
+	}
+	newUtils := func() *mock.FilesMock {
+		utils := &mock.FilesMock{}
+		utils.AddFile("signing.key", pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}))
+		utils.AddFile("config.json", []byte(`{"auths": {"registry.example.org": {"auth": "dXNlcjpwYXNzd29yZA=="}}}`))
+		utils.AddFile("app.tar", []byte("image"))
+		return utils
+	}
+	readEnvelope := func(t *testing.T, utils *mock.FilesMock, file string) provenance.Statement {
+		content, err := utils.FileRead(file)
+		require.NoError(t, err)
+		var envelope provenance.Envelope
+		require.NoError(t, json.Unmarshal(content, &envelope))
+		statement, err := provenance.Verify(envelope, key.Public())
+		require.NoError(t, err)
+		return statement
+	}
+
+	t.Run("signed provenance of images is written and pushed", func(t *testing.T) {
+		utils := newUtils()
+		pushed := []string{}
+		original := publishProvenanceReferrer
+		publishProvenanceReferrer = func(envelope provenance.Envelope, image string, keychain authn.Keychain) (name.Digest, error) {
+			ref, _ := name.NewDigest(image)
+			authenticator, _ := keychain.Resolve(ref.Context())
+			config, _ := authenticator.Authorization()
+			assert.Equal(t, "dXNlcjpwYXNzd29yZA==", config.Auth)
+			pushed = append(pushed, image)
+			return ref, nil
+		}
+		defer func() { publishProvenanceReferrer = original }()
+		images := []string{"registry.example.org/acme/app@sha256:0123456789012345678901234567890123456789012345678901234567890123"}
+
+		err := createProvenance(settings, []string{"app.tar"}, images, utils)
+
+		require.NoError(t, err)
+		assert.Equal(t, images, pushed)
+		statement := readEnvelope(t, utils, "provenance-kanikoExecute.intoto.jsonl")
+		assert.Equal(t, "https://github.com/SAP/jenkins-library/buildTypes/kaniko/kanikoExecute/v1", statement.Predicate.BuildDefinition.BuildType)
+		assert.Equal(t, map[string]interface{}{"dockerfilePath": "Dockerfile"}, statement.Predicate.BuildDefinition.ExternalParameters)
+		if assert.Len(t, statement.Subject, 2) {
+			assert.Equal(t, "app.tar", statement.Subject[0].Name)
+			assert.Equal(t, "registry.example.org/acme/app", statement.Subject[1].Name)
+			assert.Equal(t, "0123456789012345678901234567890123456789012345678901234567890123", statement.Subject[1].Digest["sha256"])
+		}
+	})
+
+	t.Run("failing push fails", func(t *testing.T) {
+		original := publishProvenanceReferrer
+		publishProvenanceReferrer = func(provenance.Envelope, string, authn.Keychain) (name.Digest, error) {
+			return name.Digest{}, fmt.Errorf("denied")
+		}
+		defer func() { publishProvenanceReferrer = original }()
+
+		err := createProvenance(settings, nil, []string{"registry.example.org/acme/app@sha256:0123"}, newUtils())
+
+		assert.EqualError(t, err, "denied")
+	})
+
+	t.Run("without build outputs no provenance is created", func(t *testing.T) {
+		utils := newUtils()
+
+		err := createProvenance(settings, nil, nil, utils)
+
+		require.NoError(t, err)
+		assert.False(t, utils.HasWrittenFile("provenance-kanikoExecute.intoto.jsonl"))
+	})
+
+	t.Run("invalid signing key", func(t *testing.T) {
+		utils := newUtils()
+		utils.AddFile("signing.key", []byte("no key"))
+
+		err := createProvenance(settings, []string{"app.tar"}, nil, utils)
+
+		assert.EqualError(t, err, "signing key is not PEM encoded")
+	})
+}
+
+func TestBuiltImages(t *testing.T) {
+	t.Run("single digest for all tags", func(t *testing.T) {
+		images := builtImages("https://registry.example.org", []string{"acme/app:1.0.0", "acme/app:latest"}, []string{"sha256:0123"})
+
+		assert.Equal(t, []string{"registry.example.org/acme/app@sha256:0123"}, images)
+	})
+
+	t.Run("digest per image", func(t *testing.T) {
+		images := builtImages("https://registry.example.org", []string{"acme/app:1.0.0", "acme/worker:1.0.0"}, []string{"sha256:0123", "sha256:4567"})
+
+		assert.Equal(t, []string{"registry.example.org/acme/app@sha256:0123", "registry.example.org/acme/worker@sha256:4567"}, images)
+	})
+
+	t.Run("digests cannot be assigned", func(t *testing.T) {
+		assert.Empty(t, builtImages("https://registry.example.org", []string{"acme/app:1.0.0", "acme/worker:1.0.0", "acme/ui:1.0.0"}, []string{"sha256:0123", "sha256:4567"}))
+		assert.Empty(t, builtImages("https://registry.example.org", []string{"acme/app:1.0.0"}, nil))
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/SAP/jenkins-library/pkg/buildcache"
 	"github.com/SAP/jenkins-library/pkg/buildsettings"
 	"github.com/SAP/jenkins-library/pkg/command"
 	"github.com/SAP/jenkins-library/pkg/log"
@@ -47,10 +48,12 @@ func newPythonBuildUtils() pythonBuildUtils {
 func pythonBuild(config pythonBuildOptions, telemetryData *telemetry.CustomData, commonPipelineEnvironment *pythonBuildCommonPipelineEnvironment) {
 	utils := newPythonBuildUtils()
 
+	cache := restoreBuildCache(buildcache.Python(), config.BuildCacheBackend, config.BuildCacheLocation, config.BuildCacheEndpoint, telemetryData)
 	err := runPythonBuild(&config, telemetryData, utils, commonPipelineEnvironment)
 	if err != nil {
 		log.Entry().WithError(err).Fatal("step execution failed")
 	}
+	saveBuildCache(cache, telemetryData)
 }
 
 func runPythonBuild(config *pythonBuildOptions, telemetryData *telemetry.CustomData, utils pythonBuildUtils, commonPipelineEnvironment *pythonBuildCommonPipelineEnvironment) error {
@@ -67,6 +70,7 @@ func runPythonBuild(config *pythonBuildOptions, telemetryData *telemetry.CustomD
 		return fmt.Errorf("failed to determine build descriptor file: %w", err)
 	}
 
+	projectManager := python.ProjectManagerPip
 	if strings.HasSuffix(buildDescriptorFilePath, "pyproject.toml") {
 		// handle pyproject.toml file
 		workDir, err := os.Getwd()
@@ -76,7 +80,17 @@ func runPythonBuild(config *pythonBuildOptions, telemetryData *telemetry.CustomD
 		utils.AppendEnv([]string{
 			fmt.Sprintf("VIRTUAL_ENV=%s", filepath.Join(workDir, config.VirtualEnvironmentName)),
 		})
-		if err := python.BuildWithPyProjectToml(utils.RunExecutable, config.VirtualEnvironmentName, config.BuildFlags, config.SetupFlags); err != nil {
+		projectManager = python.DetectProjectManager(utils.FileExists, utils.ReadFile, buildDescriptorFilePath)
+		log.Entry().Infof("building project with %s", projectManager)
+		switch projectManager {
+		case python.ProjectManagerPoetry:
+			err = python.BuildWithPoetry(utils.RunExecutable, config.VirtualEnvironmentName, config.SetupFlags)
+		case python.ProjectManagerUv:
+			err = python.BuildWithUv(utils.RunExecutable, config.VirtualEnvironmentName, config.SetupFlags)
+		default:
+			err = python.BuildWithPyProjectToml(utils.RunExecutable, config.VirtualEnvironmentName, config.BuildFlags, config.SetupFlags)
+		}
+		if err != nil {
 			return fmt.Errorf("failed to build python project: %w", err)
 		}
 	} else {
@@ -87,7 +101,11 @@ func runPythonBuild(config *pythonBuildOptions, telemetryData *telemetry.CustomD
 	}
 
 	if config.CreateBOM {
-		if err := python.CreateBOM(utils.RunExecutable, utils.FileExists, utils.ReadFile, config.VirtualEnvironmentName, config.RequirementsFilePath, cycloneDxVersion, cycloneDxSchemaVersion); err != nil {
+		if projectManager == python.ProjectManagerPip {
+			if err := python.CreateBOM(utils.RunExecutable, utils.FileExists, utils.ReadFile, config.VirtualEnvironmentName, config.RequirementsFilePath, cycloneDxVersion, cycloneDxSchemaVersion); err != nil {
+				return fmt.Errorf("failed to create BOM: %w", err)
+			}
+		} else if err := python.CreateBOMFromLockFile(utils.RunExecutable, utils.ReadFile, config.VirtualEnvironmentName, projectManager, cycloneDxVersion, cycloneDxSchemaVersion); err != nil {
 			return fmt.Errorf("failed to create BOM: %w", err)
 		}
 	}
@@ -99,7 +117,14 @@ func runPythonBuild(config *pythonBuildOptions, telemetryData *telemetry.CustomD
 	}
 
 	if config.Publish {
-		if err := python.PublishPackage(
+		publish := python.PublishPackage
+		switch projectManager {
+		case python.ProjectManagerPoetry:
+			publish = python.PublishWithPoetry
+		case python.ProjectManagerUv:
+			publish = python.PublishWithUv
+		}
+		if err := publish(
 			utils.RunExecutable,
 			config.VirtualEnvironmentName,
 			config.TargetRepositoryURL,
@@ -29,6 +29,9 @@ type pythonBuildOptions struct {
 	BuildSettingsInfo        string   `json:"buildSettingsInfo,omitempty"`
 	VirtualEnvironmentName   string   `json:"virtualEnvironmentName,omitempty"`
 	RequirementsFilePath     string   `json:"requirementsFilePath,omitempty"`
+	BuildCacheBackend        string   `json:"buildCacheBackend,omitempty" validate:"possible-values=local s3 gcs"`
+	BuildCacheLocation       string   `json:"buildCacheLocation,omitempty"`
+	BuildCacheEndpoint       string   `json:"buildCacheEndpoint,omitempty"`
 }
 
 type pythonBuildCommonPipelineEnvironment struct {
@@ -77,6 +80,13 @@ func PythonBuildCommand() *cobra.Command {
 		Long: `This step will build a python project.
 It will prioritize ` + "`" + `pyproject.toml` + "`" + ` file but can also be used with a ` + "`" + `setup.py` + "`" + ` manifest and builds a wheel and tarball artifact.
 
+### Poetry and uv projects
+
+Projects with a ` + "`" + `pyproject.toml` + "`" + ` which are managed by [Poetry](https://python-poetry.org) or [uv](https://docs.astral.sh/uv) are detected by their lock file ` + "`" + `poetry.lock` + "`" + ` or ` + "`" + `uv.lock` + "`" + `, or by their configuration ` + "`" + `[tool.poetry]` + "`" + ` or ` + "`" + `[tool.uv]` + "`" + ` in ` + "`" + `pyproject.toml` + "`" + `.
+The locked dependencies are installed into the virtual environment with ` + "`" + `poetry install` + "`" + ` or ` + "`" + `uv sync --locked` + "`" + `, the build fails if the lock file is not up to date with ` + "`" + `pyproject.toml` + "`" + `.
+The wheel and tarball are built with ` + "`" + `poetry build` + "`" + ` or ` + "`" + `uv build` + "`" + ` and published with ` + "`" + `poetry publish` + "`" + ` or ` + "`" + `uv publish` + "`" + `.
+The BOM is created from the lock file and contains the runtime dependencies of the project only.
+
 ### Build with depedencies from a private repository
 
 If your build has dependencies from a private repository you can include the standard ` + "`" + `requirements.txt` + "`" + ` into the source code with ` + "`" + `--extra-index-url` + "`" + ` as the first line
@@ -206,7 +216,7 @@ The variables ` + "`" + `PIPER_VAULTCREDENTIAL_USERNAME` + "`" + ` and ` + "`" +
 
 func addPythonBuildFlags(cmd *cobra.Command, stepConfig *pythonBuildOptions) {
 	cmd.Flags().StringSliceVar(&stepConfig.BuildFlags, "buildFlags", []string{}, "Defines list of build flags passed to python binary.")
-	cmd.Flags().StringSliceVar(&stepConfig.SetupFlags, "setupFlags", []string{}, "Defines list of flags passed to setup.py / build module.")
+	cmd.Flags().StringSliceVar(&stepConfig.SetupFlags, "setupFlags", []string{}, "Defines list of flags passed to setup.py / build module, or to `poetry build` / `uv build` for Poetry and uv projects.")
 	cmd.Flags().BoolVar(&stepConfig.CreateBOM, "createBOM", false, "Creates the bill of materials (BOM) using CycloneDX plugin.")
 	cmd.Flags().BoolVar(&stepConfig.Publish, "publish", false, "Configures the build to publish artifacts to a repository.")
 	cmd.Flags().StringVar(&stepConfig.TargetRepositoryPassword, "targetRepositoryPassword", os.Getenv("PIPER_targetRepositoryPassword"), "Password for the target repository where the compiled binaries shall be uploaded - typically provided by the CI/CD environment.")
@@ -215,6 +225,9 @@ func addPythonBuildFlags(cmd *cobra.Command, stepConfig *pythonBuildOptions) {
 	cmd.Flags().StringVar(&stepConfig.BuildSettingsInfo, "buildSettingsInfo", os.Getenv("PIPER_buildSettingsInfo"), "build settings info is typically filled by the step automatically to create information about the build settings that were used during the build. This information is typically used for compliance related processes.")
 	cmd.Flags().StringVar(&stepConfig.VirtualEnvironmentName, "virtualEnvironmentName", `piperBuild-env`, "name of the virtual environment that will be used for the build")
 	cmd.Flags().StringVar(&stepConfig.RequirementsFilePath, "requirementsFilePath", `requirements.txt`, "file path to the requirements.txt file needed for the sbom cycloneDx file creation.")
+	cmd.Flags().StringVar(&stepConfig.BuildCacheBackend, "buildCacheBackend", os.Getenv("PIPER_buildCacheBackend"), "Backend of the dependency cache. If set, the dependencies of the build tool are restored before the build from an archive whose key is computed from the lock files of the project and saved after a successful build. Caching is disabled by default.")
+	cmd.Flags().StringVar(&stepConfig.BuildCacheLocation, "buildCacheLocation", os.Getenv("PIPER_buildCacheLocation"), "Location of the dependency cache: a directory for the `local` backend, or a bucket optionally followed by a path prefix for the `s3` and `gcs` backends, e.g. `my-bucket/piper-cache`. The `gcs` backend uses the key file of `gcpJsonKeyFilePath`, the `s3` backend uses the AWS credentials of the environment.")
+	cmd.Flags().StringVar(&stepConfig.BuildCacheEndpoint, "buildCacheEndpoint", os.Getenv("PIPER_buildCacheEndpoint"), "Endpoint of an S3-compatible object store used by the `s3` backend instead of AWS S3, e.g. `https://minio.example.org`.")
 
 }
 
@@ -339,6 +352,33 @@ func pythonBuildMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     `requirements.txt`,
 					},
+					{
+						Name:        "buildCacheBackend",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "GENERAL", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_buildCacheBackend"),
+					},
+					{
+						Name:        "buildCacheLocation",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "GENERAL", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_buildCacheLocation"),
+					},
+					{
+						Name:        "buildCacheEndpoint",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "GENERAL", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_buildCacheEndpoint"),
+					},
 				},
 			},
 			Containers: []config.Container{
@@ -237,3 +237,82 @@ func TestRunPythonBuildWithToml(t *testing.T) {
 		assert.Equal(t, filepath.Join("dummy", "bin", "cyclonedx-py"), utils.ExecMockRunner.Calls[10].Exec)
 	})
 }
+
+func TestRunPythonBuildWithLockFile(t *testing.T) {
+	cpe := pythonBuildCommonPipelineEnvironment{}
+
+	SetConfigOptions(ConfigCommandOptions{
+		OpenFile: config.OpenPiperFile,
+	})
+
+	t.Run("success - poetry build, BOM and publish", func(t *testing.T) {
+		config := pythonBuildOptions{
+			CreateBOM:                true,
+			Publish:                  true,
+			TargetRepositoryURL:      "https://my.target.repository.local",
+			TargetRepositoryUser:     "user",
+			TargetRepositoryPassword: "password",
+			VirtualEnvironmentName:   "dummy",
+		}
+		utils := newPythonBuildTestsUtils()
+		utils.AddFile("pyproject.toml", []byte("[tool.poetry]\nname = \"app\"\nversion = \"1.0.0\"\n"))
+		utils.AddFile("poetry.lock", []byte(""))
+		utils.AddDir("dummy")
+		telemetryData := telemetry.CustomData{}
+
+		err := runPythonBuild(&config, &telemetryData, utils, &cpe)
+		assert.NoError(t, err)
+		if assert.Len(t, utils.ExecMockRunner.Calls, 10) {
+			assert.Equal(t, []string{"install", "--upgrade", "--root-user-action=ignore", "poetry"}, utils.ExecMockRunner.Calls[3].Params)
+			assert.Equal(t, filepath.Join("dummy", "bin", "poetry"), utils.ExecMockRunner.Calls[4].Exec)
+			assert.Equal(t, []string{"install", "--no-interaction"}, utils.ExecMockRunner.Calls[4].Params)
+			assert.Equal(t, []string{"build", "--no-interaction"}, utils.ExecMockRunner.Calls[5].Params)
+			assert.Equal(t, []string{"install", "--upgrade", "--root-user-action=ignore", "cyclonedx-bom==6.1.1"}, utils.ExecMockRunner.Calls[6].Params)
+			assert.Equal(t, filepath.Join("dummy", "bin", "cyclonedx-py"), utils.ExecMockRunner.Calls[7].Exec)
+			assert.Equal(t, []string{"poetry", "--no-dev", "--output-file", "bom-pip.xml", "--output-format", "XML", "--spec-version", "1.4"}, utils.ExecMockRunner.Calls[7].Params)
+			assert.Equal(t, []string{"config", "repositories.piper", config.TargetRepositoryURL}, utils.ExecMockRunner.Calls[8].Params)
+			assert.Equal(t, []string{"publish", "--repository", "piper", "--username", config.TargetRepositoryUser,
+				"--password", config.TargetRepositoryPassword, "--no-interaction"}, utils.ExecMockRunner.Calls[9].Params)
+		}
+	})
+
+	t.Run("success - uv build and publish", func(t *testing.T) {
+		config := pythonBuildOptions{
+			Publish:                  true,
+			SetupFlags:               []string{"--wheel"},
+			TargetRepositoryURL:      "https://my.target.repository.local",
+			TargetRepositoryUser:     "user",
+			TargetRepositoryPassword: "password",
+			VirtualEnvironmentName:   "dummy",
+		}
+		utils := newPythonBuildTestsUtils()
+		utils.AddFile("pyproject.toml", []byte("[project]\nname = \"app\"\nversion = \"1.0.0\"\n"))
+		utils.AddFile("uv.lock", []byte(""))
+		utils.AddDir("dummy")
+		telemetryData := telemetry.CustomData{}
+
+		err := runPythonBuild(&config, &telemetryData, utils, &cpe)
+		assert.NoError(t, err)
+		if assert.Len(t, utils.ExecMockRunner.Calls, 7) {
+			assert.Equal(t, []string{"install", "--upgrade", "--root-user-action=ignore", "uv"}, utils.ExecMockRunner.Calls[3].Params)
+			assert.Equal(t, filepath.Join("dummy", "bin", "uv"), utils.ExecMockRunner.Calls[4].Exec)
+			assert.Equal(t, []string{"sync", "--locked", "--inexact", "--active"}, utils.ExecMockRunner.Calls[4].Params)
+			assert.Equal(t, []string{"build", "--wheel"}, utils.ExecMockRunner.Calls[5].Params)
+			assert.Equal(t, []string{"publish", "--publish-url", config.TargetRepositoryURL, "--username", config.TargetRepositoryUser,
+				"--password", config.TargetRepositoryPassword, "dist/*"}, utils.ExecMockRunner.Calls[6].Params)
+		}
+	})
+
+	t.Run("failure - lock file out of date", func(t *testing.T) {
+		config := pythonBuildOptions{VirtualEnvironmentName: "dummy"}
+		utils := newPythonBuildTestsUtils()
+		utils.AddFile("pyproject.toml", []byte("[project]\nname = \"app\"\nversion = \"1.0.0\"\n"))
+		utils.AddFile("uv.lock", []byte(""))
+		utils.AddDir("dummy")
+		utils.ShouldFailOnCommand = map[string]error{filepath.Join("dummy", "bin", "uv") + " sync": fmt.Errorf("the lockfile needs to be updated")}
+		telemetryData := telemetry.CustomData{}
+
+		err := runPythonBuild(&config, &telemetryData, utils, &cpe)
+		assert.EqualError(t, err, "failed to build python project: failed to install project dependencies: the lockfile needs to be updated")
+	})
+}
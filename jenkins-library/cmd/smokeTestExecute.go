@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/smoketest"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/SAP/jenkins-library/pkg/xsuaa"
+	"github.com/pkg/errors"
+)
+
+type smokeTestExecuteUtils interface {
+	piperhttp.Sender
+
+	FileWrite(path string, content []byte, perm os.FileMode) error
+	WriteFile(path string, content []byte, perm os.FileMode) error
+	Sleep(d time.Duration)
+}
+
+type smokeTestExecuteUtilsBundle struct {
+	*piperhttp.Client
+	*piperutils.Files
+}
+
+func (s *smokeTestExecuteUtilsBundle) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
+
+func newSmokeTestExecuteUtils(config *smokeTestExecuteOptions) smokeTestExecuteUtils {
+	utils := smokeTestExecuteUtilsBundle{
+		Client: &piperhttp.Client{},
+		Files:  &piperutils.Files{},
+	}
+	// retries are handled by the smoke test runner to apply the configured backoff
+	utils.SetOptions(piperhttp.ClientOptions{MaxRetries: -1, TransportTimeout: time.Duration(config.RequestTimeout) * time.Second})
+	return &utils
+}
+
+func smokeTestExecute(config smokeTestExecuteOptions, telemetryData *telemetry.CustomData) {
+	utils := newSmokeTestExecuteUtils(&config)
+
+	err := runSmokeTestExecute(&config, utils)
+	if err != nil {
+		log.Entry().WithError(err).Fatal("step execution failed")
+	}
+}
+
+func runSmokeTestExecute(config *smokeTestExecuteOptions, utils smokeTestExecuteUtils) error {
+	checks, err := smoketest.ParseChecks(config.Checks)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return err
+	}
+	checks, err = smoketest.ExpandChecks(checks, config.BaseURLs)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return err
+	}
+
+	runner := smoketest.Runner{
+		Client:           utils,
+		Retries:          config.Retries,
+		RetryInterval:    time.Duration(config.RetryInterval) * time.Second,
+		MaxRetryInterval: time.Duration(config.MaxRetryInterval) * time.Second,
+		Sleep:            utils.Sleep,
+	}
+	if len(config.OauthURL) > 0 {
+		runner.Auth = &xsuaa.XSUAA{
+			OAuthURL:     config.OauthURL,
+			ClientID:     config.OauthClientID,
+			ClientSecret: config.OauthClientSecret,
+		}
+	}
+
+	start := time.Now()
+	results := runner.Run(checks)
+
+	report, err := smoketest.JUnitReport("smokeTestExecute", results, start)
+	if err != nil {
+		return errors.Wrap(err, "failed to create JUnit report")
+	}
+	if err := utils.FileWrite(config.ReportPath, report, 0o666); err != nil {
+		return errors.Wrap(err, "failed to write JUnit report")
+	}
+	piperutils.PersistReportsAndLinks("smokeTestExecute", "", utils, []piperutils.Path{{Name: "Smoke Test Results", Target: config.ReportPath}}, nil)
+
+	failed := 0
+	for _, r := range results {
+		if len(r.Failure) > 0 {
+			log.Entry().Errorf("check '%s' failed: %s", r.Name, r.Failure)
+			failed++
+		}
+	}
+	if failed > 0 {
+		log.SetErrorCategory(log.ErrorTest)
+		return fmt.Errorf("%d of %d smoke test(s) failed", failed, len(results))
+	}
+	log.Entry().Infof("all %d smoke test(s) passed", len(results))
+	return nil
+}
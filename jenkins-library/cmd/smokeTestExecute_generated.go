@@ -0,0 +1,370 @@
+// Code generated by piper's step-generator. DO NOT EDIT.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/gcp"
+	"github.com/SAP/jenkins-library/pkg/gcs"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/splunk"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/SAP/jenkins-library/pkg/validation"
+	"github.com/bmatcuk/doublestar"
+	"github.com/spf13/cobra"
+)
+
+type smokeTestExecuteOptions struct {
+	Checks            []map[string]interface{} `json:"checks,omitempty"`
+	BaseURLs          []string                 `json:"baseUrls,omitempty"`
+	Retries           int                      `json:"retries,omitempty"`
+	RetryInterval     int                      `json:"retryInterval,omitempty"`
+	MaxRetryInterval  int                      `json:"maxRetryInterval,omitempty"`
+	RequestTimeout    int                      `json:"requestTimeout,omitempty"`
+	OauthURL          string                   `json:"oauthUrl,omitempty"`
+	OauthClientID     string                   `json:"oauthClientId,omitempty"`
+	OauthClientSecret string                   `json:"oauthClientSecret,omitempty"`
+	ReportPath        string                   `json:"reportPath,omitempty"`
+}
+
+type smokeTestExecuteReports struct {
+}
+
+func (p *smokeTestExecuteReports) persist(stepConfig smokeTestExecuteOptions, gcpJsonKeyFilePath string, gcsBucketId string, gcsFolderPath string, gcsSubFolder string) {
+	if gcsBucketId == "" {
+		log.Entry().Info("persisting reports to GCS is disabled, because gcsBucketId is empty")
+		return
+	}
+	log.Entry().Info("Uploading reports to Google Cloud Storage...")
+	content := []gcs.ReportOutputParam{
+		{FilePattern: "**/TEST-smokeTestExecute.xml", ParamRef: "", StepResultType: "smoke-test"},
+	}
+
+	gcsClient, err := gcs.NewClient(gcpJsonKeyFilePath, "")
+	if err != nil {
+		log.Entry().Errorf("creation of GCS client failed: %v", err)
+		return
+	}
+	defer gcsClient.Close()
+	structVal := reflect.ValueOf(&stepConfig).Elem()
+	inputParameters := map[string]string{}
+	for i := 0; i < structVal.NumField(); i++ {
+		field := structVal.Type().Field(i)
+		if field.Type.String() == "string" {
+			paramName := strings.Split(field.Tag.Get("json"), ",")
+			paramValue, _ := structVal.Field(i).Interface().(string)
+			inputParameters[paramName[0]] = paramValue
+		}
+	}
+	if err := gcs.PersistReportsToGCS(gcsClient, content, inputParameters, gcsFolderPath, gcsBucketId, gcsSubFolder, doublestar.Glob, os.Stat); err != nil {
+		log.Entry().Errorf("failed to persist reports: %v", err)
+	}
+}
+
+// SmokeTestExecuteCommand Executes HTTP smoke and health checks against a deployed application.
+func SmokeTestExecuteCommand() *cobra.Command {
+	const STEP_NAME = "smokeTestExecute"
+
+	metadata := smokeTestExecuteMetadata()
+	var stepConfig smokeTestExecuteOptions
+	var startTime time.Time
+	var reports smokeTestExecuteReports
+	var logCollector *log.CollectorHook
+	var splunkClient *splunk.Splunk
+	telemetryClient := &telemetry.Telemetry{}
+
+	var createSmokeTestExecuteCmd = &cobra.Command{
+		Use:   STEP_NAME,
+		Short: "Executes HTTP smoke and health checks against a deployed application.",
+		Long: `Executes a declarative list of HTTP checks against an application after it has been deployed, e.g. via ` + "`" + `cloudFoundryDeploy` + "`" + `, ` + "`" + `kubernetesDeploy` + "`" + ` or ` + "`" + `integrationArtifactDeploy` + "`" + `.
+
+Each check supports the following properties:
+
+* ` + "`" + `name` + "`" + ` - name of the check as shown in the test results
+* ` + "`" + `url` + "`" + ` - absolute URL or path which is resolved against every entry of ` + "`" + `baseUrls` + "`" + `
+* ` + "`" + `method` + "`" + ` - HTTP method, defaults to ` + "`" + `GET` + "`" + `
+* ` + "`" + `headers` + "`" + ` - map of request headers
+* ` + "`" + `body` + "`" + ` - request body
+* ` + "`" + `expectedStatus` + "`" + ` - list of accepted status codes, defaults to ` + "`" + `[200]` + "`" + `
+* ` + "`" + `jsonAssertions` + "`" + ` - list of assertions on the JSON response body, each with a ` + "`" + `path` + "`" + ` (e.g. ` + "`" + `$.status` + "`" + ` or ` + "`" + `$.items[0].name` + "`" + `) and one of ` + "`" + `equals` + "`" + `, ` + "`" + `contains` + "`" + ` or ` + "`" + `exists` + "`" + `
+* ` + "`" + `maxLatency` + "`" + ` - latency budget of the request in milliseconds
+* ` + "`" + `retries` + "`" + ` - number of retries, overrides the step parameter ` + "`" + `retries` + "`" + `
+* ` + "`" + `skipAuth` + "`" + ` - do not send an OAuth token even if OAuth credentials are configured
+
+Failing checks are retried with exponential backoff. The results are written as JUnit XML so that they are shown in the test view of the orchestrator.`,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			startTime = time.Now()
+			log.SetStepName(STEP_NAME)
+			log.SetVerbose(GeneralConfig.Verbose)
+
+			GeneralConfig.GitHubAccessTokens = ResolveAccessTokens(GeneralConfig.GitHubTokens)
+
+			path, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+			fatalHook := &log.FatalHook{CorrelationID: GeneralConfig.CorrelationID, Path: path}
+			log.RegisterHook(fatalHook)
+
+			err = PrepareConfig(cmd, &metadata, STEP_NAME, &stepConfig, config.OpenPiperFile)
+			if err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+
+			// Set step error patterns for improved error detection
+			stepErrors := make([]log.StepError, len(metadata.Metadata.Errors))
+			for i, err := range metadata.Metadata.Errors {
+				stepErrors[i] = log.StepError{
+					Pattern:  err.Pattern,
+					Message:  err.Message,
+					Category: err.Category,
+				}
+			}
+			log.SetStepErrors(stepErrors)
+			log.RegisterSecret(stepConfig.OauthClientID)
+			log.RegisterSecret(stepConfig.OauthClientSecret)
+
+			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
+				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
+				log.RegisterHook(&sentryHook)
+			}
+
+			if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 || len(GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint) > 0 {
+				splunkClient = &splunk.Splunk{}
+				logCollector = &log.CollectorHook{CorrelationID: GeneralConfig.CorrelationID}
+				log.RegisterHook(logCollector)
+			}
+
+			if err = log.RegisterANSHookIfConfigured(GeneralConfig.CorrelationID); err != nil {
+				log.Entry().WithError(err).Warn("failed to set up SAP Alert Notification Service log hook")
+			}
+
+			validation, err := validation.New(validation.WithJSONNamesForStructFields(), validation.WithPredefinedErrorMessages())
+			if err != nil {
+				return err
+			}
+			if err = validation.ValidateStruct(stepConfig); err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+
+			return nil
+		},
+		Run: func(_ *cobra.Command, _ []string) {
+			vaultClient := config.GlobalVaultClient()
+			if vaultClient != nil {
+				defer vaultClient.MustRevokeToken()
+			}
+
+			stepTelemetryData := telemetry.CustomData{}
+			stepTelemetryData.ErrorCode = "1"
+			handler := func() {
+				reports.persist(stepConfig, GeneralConfig.GCPJsonKeyFilePath, GeneralConfig.GCSBucketId, GeneralConfig.GCSFolderPath, GeneralConfig.GCSSubFolder)
+				config.RemoveVaultSecretFiles()
+				stepTelemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
+				stepTelemetryData.ErrorCategory = log.GetErrorCategory().String()
+				stepTelemetryData.PiperCommitHash = GitCommit
+				telemetryClient.SetData(&stepTelemetryData)
+				telemetryClient.LogStepTelemetryData()
+				if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+					splunkClient.Initialize(GeneralConfig.CorrelationID,
+						GeneralConfig.HookConfig.SplunkConfig.Dsn,
+						GeneralConfig.HookConfig.SplunkConfig.Token,
+						GeneralConfig.HookConfig.SplunkConfig.Index,
+						GeneralConfig.HookConfig.SplunkConfig.SendLogs)
+					splunkClient.Send(telemetryClient.GetData(), logCollector)
+				}
+				if len(GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint) > 0 {
+					splunkClient.Initialize(GeneralConfig.CorrelationID,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblToken,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblIndex,
+						GeneralConfig.HookConfig.SplunkConfig.SendLogs)
+					splunkClient.Send(telemetryClient.GetData(), logCollector)
+				}
+				if GeneralConfig.HookConfig.GCPPubSubConfig.Enabled {
+					err := gcp.NewGcpPubsubClient(
+						vaultClient,
+						GeneralConfig.HookConfig.GCPPubSubConfig.ProjectNumber,
+						GeneralConfig.HookConfig.GCPPubSubConfig.IdentityPool,
+						GeneralConfig.HookConfig.GCPPubSubConfig.IdentityProvider,
+						GeneralConfig.CorrelationID,
+						GeneralConfig.HookConfig.OIDCConfig.RoleID,
+					).Publish(GeneralConfig.HookConfig.GCPPubSubConfig.Topic, telemetryClient.GetDataBytes())
+					if err != nil {
+						log.Entry().WithError(err).Warn("event publish failed")
+					}
+				}
+			}
+			log.DeferExitHandler(handler)
+			defer handler()
+			telemetryClient.Initialize(STEP_NAME)
+			smokeTestExecute(stepConfig, &stepTelemetryData)
+			stepTelemetryData.ErrorCode = "0"
+			log.Entry().Info("SUCCESS")
+		},
+	}
+
+	addSmokeTestExecuteFlags(createSmokeTestExecuteCmd, &stepConfig)
+	return createSmokeTestExecuteCmd
+}
+
+func addSmokeTestExecuteFlags(cmd *cobra.Command, stepConfig *smokeTestExecuteOptions) {
+
+	cmd.Flags().StringSliceVar(&stepConfig.BaseURLs, "baseUrls", []string{}, "Base URLs against which relative check URLs are resolved. URLs without scheme use `https`. By default the routes of an application deployed via `cloudFoundryDeploy` are used.")
+	cmd.Flags().IntVar(&stepConfig.Retries, "retries", 5, "Number of retries of a failing check.")
+	cmd.Flags().IntVar(&stepConfig.RetryInterval, "retryInterval", 5, "Initial wait time in seconds between two attempts. The wait time is doubled after every attempt up to `maxRetryInterval`.")
+	cmd.Flags().IntVar(&stepConfig.MaxRetryInterval, "maxRetryInterval", 60, "Maximum wait time in seconds between two attempts.")
+	cmd.Flags().IntVar(&stepConfig.RequestTimeout, "requestTimeout", 30, "Timeout of a single request in seconds.")
+	cmd.Flags().StringVar(&stepConfig.OauthURL, "oauthUrl", os.Getenv("PIPER_oauthUrl"), "URL of the XSUAA instance used to retrieve an access token via client credentials grant. If not set, the checks are executed without authentication.")
+	cmd.Flags().StringVar(&stepConfig.OauthClientID, "oauthClientId", os.Getenv("PIPER_oauthClientId"), "OAuth client id used to retrieve an access token.")
+	cmd.Flags().StringVar(&stepConfig.OauthClientSecret, "oauthClientSecret", os.Getenv("PIPER_oauthClientSecret"), "OAuth client secret used to retrieve an access token.")
+	cmd.Flags().StringVar(&stepConfig.ReportPath, "reportPath", `TEST-smokeTestExecute.xml`, "Path of the JUnit XML report.")
+
+	cmd.MarkFlagRequired("checks")
+}
+
+// retrieve step metadata
+func smokeTestExecuteMetadata() config.StepData {
+	var theMetaData = config.StepData{
+		Metadata: config.StepMetadata{
+			Name:        "smokeTestExecute",
+			Aliases:     []config.Alias{},
+			Description: "Executes HTTP smoke and health checks against a deployed application.",
+		},
+		Spec: config.StepSpec{
+			Inputs: config.StepInputs{
+				Secrets: []config.StepSecrets{
+					{Name: "oauthCredentialsId", Description: "Jenkins 'Username Password' credentials ID containing the OAuth client id and client secret used to retrieve an access token via `oauthUrl`.", Type: "jenkins"},
+				},
+				Parameters: []config.StepParameters{
+					{
+						Name:        "checks",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]map[string]interface{}",
+						Mandatory:   true,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name: "baseUrls",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "custom/cfAppRoutes",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "[]string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   []string{},
+					},
+					{
+						Name:        "retries",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     5,
+					},
+					{
+						Name:        "retryInterval",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     5,
+					},
+					{
+						Name:        "maxRetryInterval",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     60,
+					},
+					{
+						Name:        "requestTimeout",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     30,
+					},
+					{
+						Name:        "oauthUrl",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_oauthUrl"),
+					},
+					{
+						Name: "oauthClientId",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "oauthCredentialsId",
+								Param: "username",
+								Type:  "secret",
+							},
+						},
+						Scope:     []string{"PARAMETERS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_oauthClientId"),
+					},
+					{
+						Name: "oauthClientSecret",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "oauthCredentialsId",
+								Param: "password",
+								Type:  "secret",
+							},
+						},
+						Scope:     []string{"PARAMETERS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_oauthClientSecret"),
+					},
+					{
+						Name:        "reportPath",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `TEST-smokeTestExecute.xml`,
+					},
+				},
+			},
+			Outputs: config.StepOutputs{
+				Resources: []config.StepResources{
+					{
+						Name: "reports",
+						Type: "reports",
+						Parameters: []map[string]interface{}{
+							{"filePattern": "**/TEST-smokeTestExecute.xml", "type": "smoke-test"},
+						},
+					},
+				},
+			},
+		},
+	}
+	return theMetaData
+}
@@ -0,0 +1,112 @@
+//go:build unit
+// +build unit
+
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+type smokeTestExecuteMockUtils struct {
+	*piperhttp.Client
+	*mock.FilesMock
+	waits []time.Duration
+}
+
+func (m *smokeTestExecuteMockUtils) Sleep(d time.Duration) {
+	m.waits = append(m.waits, d)
+}
+
+func newSmokeTestExecuteTestsUtils() *smokeTestExecuteMockUtils {
+	client := &piperhttp.Client{}
+	client.SetOptions(piperhttp.ClientOptions{MaxRetries: -1, UseDefaultTransport: true})
+	return &smokeTestExecuteMockUtils{
+		Client:    client,
+		FilesMock: &mock.FilesMock{},
+	}
+}
+
+func TestRunSmokeTestExecute(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/oauth/token":
+			fmt.Fprint(w, `{"access_token":"token","token_type":"bearer","expires_in":3600}`)
+		case "/health":
+			if r.Header.Get("Authorization") != "bearer token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprint(w, `{"status":"UP"}`)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	t.Run("all checks pass", func(t *testing.T) {
+		t.Parallel()
+		config := smokeTestExecuteOptions{
+			Checks: []map[string]interface{}{
+				{"name": "health", "url": "/health", "jsonAssertions": []interface{}{map[string]interface{}{"path": "$.status", "equals": "UP"}}},
+			},
+			BaseURLs:          []string{server.URL},
+			Retries:           2,
+			RetryInterval:     1,
+			OauthURL:          server.URL,
+			OauthClientID:     "client",
+			OauthClientSecret: "secret",
+			ReportPath:        "TEST-smokeTestExecute.xml",
+		}
+		utils := newSmokeTestExecuteTestsUtils()
+
+		err := runSmokeTestExecute(&config, utils)
+
+		assert.NoError(t, err)
+		report, _ := utils.FileRead("TEST-smokeTestExecute.xml")
+		assert.Contains(t, string(report), `<testsuite name="smokeTestExecute" tests="1" failures="0"`)
+		assert.Empty(t, utils.waits)
+	})
+
+	t.Run("failing check", func(t *testing.T) {
+		t.Parallel()
+		config := smokeTestExecuteOptions{
+			Checks: []map[string]interface{}{
+				{"name": "health", "url": server.URL + "/health", "skipAuth": true},
+				{"name": "error", "url": server.URL + "/error", "expectedStatus": []interface{}{500}},
+			},
+			Retries:          3,
+			RetryInterval:    5,
+			MaxRetryInterval: 15,
+			ReportPath:       "TEST-smokeTestExecute.xml",
+		}
+		utils := newSmokeTestExecuteTestsUtils()
+
+		err := runSmokeTestExecute(&config, utils)
+
+		assert.EqualError(t, err, "1 of 2 smoke test(s) failed")
+		assert.Equal(t, []time.Duration{5 * time.Second, 10 * time.Second, 15 * time.Second}, utils.waits)
+		report, _ := utils.FileRead("TEST-smokeTestExecute.xml")
+		assert.Contains(t, string(report), `<failure message="unexpected status code 401, expected [200]"`)
+	})
+
+	t.Run("relative url without base url", func(t *testing.T) {
+		t.Parallel()
+		config := smokeTestExecuteOptions{Checks: []map[string]interface{}{{"url": "/health"}}}
+		utils := newSmokeTestExecuteTestsUtils()
+
+		err := runSmokeTestExecute(&config, utils)
+
+		assert.EqualError(t, err, "check '' uses the relative url '/health' but no base url is available")
+		assert.False(t, utils.HasWrittenFile("TEST-smokeTestExecute.xml"))
+	})
+}
@@ -2,20 +2,37 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"slices"
+	"strings"
 
 	"github.com/SAP/jenkins-library/pkg/command"
+	piperGithub "github.com/SAP/jenkins-library/pkg/github"
 	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/orchestrator"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 	"github.com/SAP/jenkins-library/pkg/terraform"
+	"github.com/pkg/errors"
+)
+
+const (
+	terraformPlanJSONFile    = "terraformPlan.json"
+	terraformPlanSummaryFile = "terraformPlanSummary.md"
 )
 
 type terraformExecuteUtils interface {
 	command.ExecRunner
 
 	FileExists(filename string) (bool, error)
+	WriteFile(filename string, data []byte, perm os.FileMode) error
+
+	// PullRequestNumber returns the number of the pull request the pipeline runs for, or 0 if it is no pull request build
+	PullRequestNumber() (int, error)
+	CreatePullRequestComment(config *terraformExecuteOptions, number int, body string) error
 }
 
 type terraformExecuteUtilsBundle struct {
@@ -34,6 +51,14 @@ func newTerraformExecuteUtils() terraformExecuteUtils {
 	return &utils
 }
 
+func (u *terraformExecuteUtilsBundle) PullRequestNumber() (int, error) {
+	return orchestrator.PullRequestNumber()
+}
+
+func (u *terraformExecuteUtilsBundle) CreatePullRequestComment(config *terraformExecuteOptions, number int, body string) error {
+	return piperGithub.CreatePullRequestComment(config.GithubToken, config.GithubAPIURL, config.Owner, config.Repository, number, body)
+}
+
 func terraformExecute(config terraformExecuteOptions, telemetryData *telemetry.CustomData, commonPipelineEnvironment *terraformExecuteCommonPipelineEnvironment) {
 	utils := newTerraformExecuteUtils()
 
@@ -58,10 +83,16 @@ func runTerraformExecute(config *terraformExecuteOptions, telemetryData *telemet
 		args = append(args, "-auto-approve")
 	}
 
-	if slices.Contains([]string{"apply", "plan"}, config.Command) && config.TerraformSecrets != "" {
+	// variables are part of a saved plan and must not be passed again when applying it
+	applySavedPlan := config.Command == "apply" && len(config.PlanFile) > 0
+	if slices.Contains([]string{"apply", "plan"}, config.Command) && config.TerraformSecrets != "" && !applySavedPlan {
 		args = append(args, fmt.Sprintf("-var-file=%s", config.TerraformSecrets))
 	}
 
+	if config.Command == "plan" && len(config.PlanFile) > 0 {
+		args = append(args, fmt.Sprintf("-out=%s", config.PlanFile))
+	}
+
 	if slices.Contains([]string{"init", "validate", "plan", "apply", "destroy"}, config.Command) {
 		args = append(args, "-no-color")
 	}
@@ -70,6 +101,18 @@ func runTerraformExecute(config *terraformExecuteOptions, telemetryData *telemet
 		args = append(args, config.AdditionalArgs...)
 	}
 
+	if applySavedPlan {
+		exists, err := utils.FileExists(terraformPath(config.GlobalOptions, config.PlanFile))
+		if err != nil {
+			return errors.Wrapf(err, "failed to check for plan file '%s'", config.PlanFile)
+		}
+		if !exists {
+			return fmt.Errorf("plan file '%s' not found, run the plan command with the same planFile first", config.PlanFile)
+		}
+		log.Entry().Infof("Applying saved plan '%s'", config.PlanFile)
+		args = append(args, config.PlanFile)
+	}
+
 	if config.Init {
 		err := runTerraform(utils, "init", []string{"-no-color"}, config.GlobalOptions)
 
@@ -84,6 +127,12 @@ func runTerraformExecute(config *terraformExecuteOptions, telemetryData *telemet
 		return err
 	}
 
+	if config.Command == "plan" && len(config.PlanFile) > 0 {
+		if err := analyzeTerraformPlan(config, utils, commonPipelineEnvironment); err != nil {
+			return err
+		}
+	}
+
 	var outputBuffer bytes.Buffer
 	utils.Stdout(&outputBuffer)
 
@@ -98,6 +147,89 @@ func runTerraformExecute(config *terraformExecuteOptions, telemetryData *telemet
 	return err
 }
 
+// terraformPath resolves a path passed to terraform against the directory of the global option -chdir,
+// since terraform reads and writes the files relative to this directory
+func terraformPath(globalOptions []string, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	for _, option := range globalOptions {
+		if dir, ok := strings.CutPrefix(option, "-chdir="); ok {
+			return filepath.Join(dir, path)
+		}
+	}
+	return path
+}
+
+func analyzeTerraformPlan(config *terraformExecuteOptions, utils terraformExecuteUtils, commonPipelineEnvironment *terraformExecuteCommonPipelineEnvironment) error {
+	var planBuffer bytes.Buffer
+	utils.Stdout(&planBuffer)
+	err := runTerraform(utils, "show", []string{"-json", "-no-color", config.PlanFile}, config.GlobalOptions)
+	utils.Stdout(log.Writer())
+	if err != nil {
+		return errors.Wrapf(err, "failed to convert plan '%s' to JSON", config.PlanFile)
+	}
+
+	plan, err := terraform.ReadPlan(planBuffer.String())
+	if err != nil {
+		return err
+	}
+	summary := plan.Summarize()
+
+	workspace := config.Workspace
+	if len(workspace) == 0 {
+		workspace = "default"
+	}
+	markdown := summary.Markdown(workspace)
+	log.Entry().Infof("Plan: %d to create, %d to update, %d to replace, %d to destroy", summary.Total.Create, summary.Total.Update, summary.Total.Replace, summary.Total.Destroy)
+
+	if err := utils.WriteFile(terraformPlanJSONFile, planBuffer.Bytes(), 0o644); err != nil {
+		return errors.Wrap(err, "failed to write plan JSON")
+	}
+	if err := utils.WriteFile(terraformPlanSummaryFile, []byte(markdown), 0o644); err != nil {
+		return errors.Wrap(err, "failed to write plan summary")
+	}
+	reports := []piperutils.Path{
+		{Target: terraformPath(config.GlobalOptions, config.PlanFile)},
+		{Target: terraformPlanJSONFile},
+		{Target: terraformPlanSummaryFile},
+	}
+	if err := piperutils.PersistReportsAndLinks("terraformExecute", "", utils, reports, nil); err != nil {
+		log.Entry().WithError(err).Warn("failed to persist reports")
+	}
+
+	commonPipelineEnvironment.custom.terraformPlanFile = config.PlanFile
+	summaryJSON, _ := json.Marshal(summary)
+	if err := json.Unmarshal(summaryJSON, &commonPipelineEnvironment.custom.terraformPlanSummary); err != nil {
+		return errors.Wrap(err, "failed to convert plan summary")
+	}
+
+	if config.CreatePullRequestComment {
+		number, err := utils.PullRequestNumber()
+		if err != nil {
+			log.Entry().WithError(err).Warn("failed to determine pull request, skipping plan summary comment")
+		} else if number == 0 {
+			log.Entry().Info("Not running for a pull request, skipping plan summary comment")
+		} else if err := utils.CreatePullRequestComment(config, number, markdown); err != nil {
+			return errors.Wrapf(err, "failed to comment plan summary on pull request #%d", number)
+		}
+	}
+
+	policy := terraform.PlanPolicy{
+		DenyDestroyInWorkspaces: config.DenyDestroyInWorkspaces,
+		ForbiddenResourceTypes:  config.ForbiddenResourceTypes,
+	}
+	violations, err := policy.Check(summary, workspace)
+	if err != nil {
+		return err
+	}
+	if len(violations) > 0 {
+		log.SetErrorCategory(log.ErrorCompliance)
+		return fmt.Errorf("terraform plan violates policies: %s", strings.Join(violations, "; "))
+	}
+	return nil
+}
+
 func runTerraform(utils terraformExecuteUtils, command string, additionalArgs []string, globalOptions []string) error {
 	args := []string{}
 
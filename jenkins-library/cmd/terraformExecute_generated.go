@@ -6,31 +6,45 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"time"
 
 	"github.com/SAP/jenkins-library/pkg/config"
 	"github.com/SAP/jenkins-library/pkg/gcp"
+	"github.com/SAP/jenkins-library/pkg/gcs"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/piperenv"
 	"github.com/SAP/jenkins-library/pkg/splunk"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 	"github.com/SAP/jenkins-library/pkg/validation"
+	"github.com/bmatcuk/doublestar"
 	"github.com/spf13/cobra"
 )
 
 type terraformExecuteOptions struct {
-	Command          string   `json:"command,omitempty"`
-	TerraformSecrets string   `json:"terraformSecrets,omitempty"`
-	GlobalOptions    []string `json:"globalOptions,omitempty"`
-	AdditionalArgs   []string `json:"additionalArgs,omitempty"`
-	Init             bool     `json:"init,omitempty"`
-	CliConfigFile    string   `json:"cliConfigFile,omitempty"`
-	Workspace        string   `json:"workspace,omitempty"`
+	Command                  string   `json:"command,omitempty"`
+	TerraformSecrets         string   `json:"terraformSecrets,omitempty"`
+	GlobalOptions            []string `json:"globalOptions,omitempty"`
+	AdditionalArgs           []string `json:"additionalArgs,omitempty"`
+	Init                     bool     `json:"init,omitempty"`
+	CliConfigFile            string   `json:"cliConfigFile,omitempty"`
+	Workspace                string   `json:"workspace,omitempty"`
+	PlanFile                 string   `json:"planFile,omitempty"`
+	DenyDestroyInWorkspaces  []string `json:"denyDestroyInWorkspaces,omitempty"`
+	ForbiddenResourceTypes   []string `json:"forbiddenResourceTypes,omitempty"`
+	CreatePullRequestComment bool     `json:"createPullRequestComment,omitempty"`
+	GithubAPIURL             string   `json:"githubApiUrl,omitempty"`
+	Owner                    string   `json:"owner,omitempty"`
+	Repository               string   `json:"repository,omitempty"`
+	GithubToken              string   `json:"githubToken,omitempty"`
 }
 
 type terraformExecuteCommonPipelineEnvironment struct {
 	custom struct {
-		terraformOutputs map[string]interface{}
+		terraformOutputs     map[string]interface{}
+		terraformPlanFile    string
+		terraformPlanSummary map[string]interface{}
 	}
 }
 
@@ -41,6 +55,8 @@ func (p *terraformExecuteCommonPipelineEnvironment) persist(path, resourceName s
 		value    interface{}
 	}{
 		{category: "custom", name: "terraformOutputs", value: p.custom.terraformOutputs},
+		{category: "custom", name: "terraformPlanFile", value: p.custom.terraformPlanFile},
+		{category: "custom", name: "terraformPlanSummary", value: p.custom.terraformPlanSummary},
 	}
 
 	errCount := 0
@@ -56,6 +72,41 @@ func (p *terraformExecuteCommonPipelineEnvironment) persist(path, resourceName s
 	}
 }
 
+type terraformExecuteReports struct {
+}
+
+func (p *terraformExecuteReports) persist(stepConfig terraformExecuteOptions, gcpJsonKeyFilePath string, gcsBucketId string, gcsFolderPath string, gcsSubFolder string) {
+	if gcsBucketId == "" {
+		log.Entry().Info("persisting reports to GCS is disabled, because gcsBucketId is empty")
+		return
+	}
+	log.Entry().Info("Uploading reports to Google Cloud Storage...")
+	content := []gcs.ReportOutputParam{
+		{FilePattern: "**/terraformPlan.json", ParamRef: "", StepResultType: "terraform"},
+		{FilePattern: "**/terraformPlanSummary.md", ParamRef: "", StepResultType: "terraform"},
+	}
+
+	gcsClient, err := gcs.NewClient(gcpJsonKeyFilePath, "")
+	if err != nil {
+		log.Entry().Errorf("creation of GCS client failed: %v", err)
+		return
+	}
+	defer gcsClient.Close()
+	structVal := reflect.ValueOf(&stepConfig).Elem()
+	inputParameters := map[string]string{}
+	for i := 0; i < structVal.NumField(); i++ {
+		field := structVal.Type().Field(i)
+		if field.Type.String() == "string" {
+			paramName := strings.Split(field.Tag.Get("json"), ",")
+			paramValue, _ := structVal.Field(i).Interface().(string)
+			inputParameters[paramName[0]] = paramValue
+		}
+	}
+	if err := gcs.PersistReportsToGCS(gcsClient, content, inputParameters, gcsFolderPath, gcsBucketId, gcsSubFolder, doublestar.Glob, os.Stat); err != nil {
+		log.Entry().Errorf("failed to persist reports: %v", err)
+	}
+}
+
 // TerraformExecuteCommand Executes Terraform
 func TerraformExecuteCommand() *cobra.Command {
 	const STEP_NAME = "terraformExecute"
@@ -64,6 +115,7 @@ func TerraformExecuteCommand() *cobra.Command {
 	var stepConfig terraformExecuteOptions
 	var startTime time.Time
 	var commonPipelineEnvironment terraformExecuteCommonPipelineEnvironment
+	var reports terraformExecuteReports
 	var logCollector *log.CollectorHook
 	var splunkClient *splunk.Splunk
 	telemetryClient := &telemetry.Telemetry{}
@@ -71,7 +123,11 @@ func TerraformExecuteCommand() *cobra.Command {
 	var createTerraformExecuteCmd = &cobra.Command{
 		Use:   STEP_NAME,
 		Short: "Executes Terraform",
-		Long:  `This step executes the terraform binary with the given command, and is able to fetch additional variables from vault.`,
+		Long: `This step executes the terraform binary with the given command, and is able to fetch additional variables from vault.
+
+If ` + "`" + `planFile` + "`" + ` is set, the ` + "`" + `plan` + "`" + ` command saves the binary plan to this file and analyses it: the planned changes are summarised
+per resource type, checked against the configured policies (` + "`" + `denyDestroyInWorkspaces` + "`" + `, ` + "`" + `forbiddenResourceTypes` + "`" + `) and optionally
+posted as comment to the pull request. A subsequent ` + "`" + `apply` + "`" + ` with the same ` + "`" + `planFile` + "`" + ` applies exactly the saved plan.`,
 		PreRunE: func(cmd *cobra.Command, _ []string) error {
 			startTime = time.Now()
 			log.SetStepName(STEP_NAME)
@@ -103,6 +159,7 @@ func TerraformExecuteCommand() *cobra.Command {
 			}
 			log.SetStepErrors(stepErrors)
 			log.RegisterSecret(stepConfig.CliConfigFile)
+			log.RegisterSecret(stepConfig.GithubToken)
 
 			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
 				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
@@ -140,6 +197,7 @@ func TerraformExecuteCommand() *cobra.Command {
 			stepTelemetryData.ErrorCode = "1"
 			handler := func() {
 				commonPipelineEnvironment.persist(GeneralConfig.EnvRootPath, "commonPipelineEnvironment")
+				reports.persist(stepConfig, GeneralConfig.GCPJsonKeyFilePath, GeneralConfig.GCSBucketId, GeneralConfig.GCSFolderPath, GeneralConfig.GCSSubFolder)
 				config.RemoveVaultSecretFiles()
 				stepTelemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
 				stepTelemetryData.ErrorCategory = log.GetErrorCategory().String()
@@ -197,6 +255,14 @@ func addTerraformExecuteFlags(cmd *cobra.Command, stepConfig *terraformExecuteOp
 	cmd.Flags().BoolVar(&stepConfig.Init, "init", false, "")
 	cmd.Flags().StringVar(&stepConfig.CliConfigFile, "cliConfigFile", os.Getenv("PIPER_cliConfigFile"), "Path to the terraform CLI configuration file (https://www.terraform.io/docs/cli/config/config-file.html#credentials).")
 	cmd.Flags().StringVar(&stepConfig.Workspace, "workspace", os.Getenv("PIPER_workspace"), "")
+	cmd.Flags().StringVar(&stepConfig.PlanFile, "planFile", os.Getenv("PIPER_planFile"), "Path of the binary plan file. The `plan` command saves the plan to this file and analyses it, the `apply` command applies the saved plan. A relative path is relative to the directory given by `-chdir` in `globalOptions`, like terraform resolves it. The plan file is also published as step report so that it can be archived.")
+	cmd.Flags().StringSliceVar(&stepConfig.DenyDestroyInWorkspaces, "denyDestroyInWorkspaces", []string{}, "List of workspace name patterns (e.g. `prod*`) in which a plan must not destroy or replace any resource. Requires `planFile`. If no `workspace` is configured, the workspace `default` is assumed.")
+	cmd.Flags().StringSliceVar(&stepConfig.ForbiddenResourceTypes, "forbiddenResourceTypes", []string{}, "List of resource type patterns (e.g. `aws_iam_*`) which a plan must not create, update or replace. Requires `planFile`.")
+	cmd.Flags().BoolVar(&stepConfig.CreatePullRequestComment, "createPullRequestComment", false, "Posts the plan summary as comment to the GitHub pull request which triggered the pipeline. Requires `planFile`.")
+	cmd.Flags().StringVar(&stepConfig.GithubAPIURL, "githubApiUrl", `https://api.github.com`, "Set the GitHub API URL.")
+	cmd.Flags().StringVar(&stepConfig.Owner, "owner", os.Getenv("PIPER_owner"), "Set the GitHub organization.")
+	cmd.Flags().StringVar(&stepConfig.Repository, "repository", os.Getenv("PIPER_repository"), "Set the GitHub repository.")
+	cmd.Flags().StringVar(&stepConfig.GithubToken, "githubToken", os.Getenv("PIPER_githubToken"), "GitHub personal access token as per https://help.github.com/en/github/authenticating-to-github/creating-a-personal-access-token-for-the-command-line")
 
 }
 
@@ -211,6 +277,7 @@ func terraformExecuteMetadata() config.StepData {
 		Spec: config.StepSpec{
 			Inputs: config.StepInputs{
 				Secrets: []config.StepSecrets{
+					{Name: "githubTokenCredentialsId", Description: "Jenkins 'Secret text' credentials ID containing token to authenticate to GitHub.", Type: "jenkins"},
 					{Name: "cliConfigFileCredentialsId", Description: "Jenkins 'Secret file' credentials ID containing terraform CLI configuration. You can find more details about it in the [Terraform documentation](https://www.terraform.io/docs/cli/config/config-file.html#credentials).", Type: "jenkins"},
 				},
 				Parameters: []config.StepParameters{
@@ -294,6 +361,104 @@ func terraformExecuteMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     os.Getenv("PIPER_workspace"),
 					},
+					{
+						Name: "planFile",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "custom/terraformPlanFile",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_planFile"),
+					},
+					{
+						Name:        "denyDestroyInWorkspaces",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+					{
+						Name:        "forbiddenResourceTypes",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+					{
+						Name:        "createPullRequestComment",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name:        "githubApiUrl",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `https://api.github.com`,
+					},
+					{
+						Name: "owner",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "github/owner",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{{Name: "githubOrg"}},
+						Default:   os.Getenv("PIPER_owner"),
+					},
+					{
+						Name: "repository",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "github/repository",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{{Name: "githubRepo"}},
+						Default:   os.Getenv("PIPER_repository"),
+					},
+					{
+						Name: "githubToken",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "githubTokenCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:    "githubVaultSecretName",
+								Type:    "vaultSecret",
+								Default: "github",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{{Name: "access_token"}},
+						Default:   os.Getenv("PIPER_githubToken"),
+					},
 				},
 			},
 			Containers: []config.Container{
@@ -306,6 +471,16 @@ func terraformExecuteMetadata() config.StepData {
 						Type: "piperEnvironment",
 						Parameters: []map[string]interface{}{
 							{"name": "custom/terraformOutputs", "type": "map[string]interface{}"},
+							{"name": "custom/terraformPlanFile"},
+							{"name": "custom/terraformPlanSummary", "type": "map[string]interface{}"},
+						},
+					},
+					{
+						Name: "reports",
+						Type: "reports",
+						Parameters: []map[string]interface{}{
+							{"filePattern": "**/terraformPlan.json", "type": "terraform"},
+							{"filePattern": "**/terraformPlanSummary.md", "type": "terraform"},
 						},
 					},
 				},
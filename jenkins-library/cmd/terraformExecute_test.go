@@ -5,24 +5,38 @@ package cmd
 
 import (
 	"fmt"
+	"path/filepath"
+	"testing"
+
 	"github.com/SAP/jenkins-library/pkg/mock"
 	"github.com/stretchr/testify/assert"
-	"testing"
 )
 
 type terraformExecuteMockUtils struct {
 	*mock.ExecMockRunner
 	*mock.FilesMock
+	pullRequestNumber int
+	comments          map[int]string
 }
 
 func newTerraformExecuteTestsUtils() terraformExecuteMockUtils {
 	utils := terraformExecuteMockUtils{
 		ExecMockRunner: &mock.ExecMockRunner{},
 		FilesMock:      &mock.FilesMock{},
+		comments:       map[int]string{},
 	}
 	return utils
 }
 
+func (u terraformExecuteMockUtils) PullRequestNumber() (int, error) {
+	return u.pullRequestNumber, nil
+}
+
+func (u terraformExecuteMockUtils) CreatePullRequestComment(config *terraformExecuteOptions, number int, body string) error {
+	u.comments[number] = body
+	return nil
+}
+
 func TestRunTerraformExecute(t *testing.T) {
 	t.Parallel()
 
@@ -158,4 +172,145 @@ func TestRunTerraformExecute(t *testing.T) {
 		assert.Equal(t, 1, len(cpe.custom.terraformOutputs))
 		assert.Equal(t, "a secret value", cpe.custom.terraformOutputs["sample_var"])
 	})
+
+	t.Run("Plan is saved and analysed", func(t *testing.T) {
+		t.Parallel()
+
+		cpe := terraformExecuteCommonPipelineEnvironment{}
+		config := terraformExecuteOptions{
+			Command:                  "plan",
+			TerraformSecrets:         "/tmp/test",
+			PlanFile:                 "tfplan",
+			Workspace:                "dev",
+			DenyDestroyInWorkspaces:  []string{"prod*"},
+			CreatePullRequestComment: true,
+		}
+		utils := newTerraformExecuteTestsUtils()
+		utils.pullRequestNumber = 42
+		utils.StdoutReturn = map[string]string{
+			"terraform output -json":                "{}",
+			"terraform show -json -no-color tfplan": terraformTestPlan,
+		}
+
+		err := runTerraformExecute(&config, nil, utils, &cpe)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, mock.ExecCall{Exec: "terraform", Params: []string{"plan", "-var-file=/tmp/test", "-out=tfplan", "-no-color"}}, utils.Calls[0])
+			assert.Equal(t, mock.ExecCall{Exec: "terraform", Params: []string{"show", "-json", "-no-color", "tfplan"}}, utils.Calls[1])
+			assert.Equal(t, "tfplan", cpe.custom.terraformPlanFile)
+			assert.Equal(t, map[string]interface{}{"create": float64(1), "update": float64(0), "replace": float64(0), "destroy": float64(1)}, cpe.custom.terraformPlanSummary["total"])
+			assert.True(t, utils.HasWrittenFile("terraformPlan.json"))
+			assert.True(t, utils.HasWrittenFile("terraformPlanSummary.md"))
+			assert.Contains(t, utils.comments[42], "**1 to create, 0 to update, 0 to replace, 1 to destroy**")
+		}
+	})
+
+	t.Run("Plan violating policies fails", func(t *testing.T) {
+		t.Parallel()
+
+		config := terraformExecuteOptions{
+			Command:                 "plan",
+			PlanFile:                "tfplan",
+			Workspace:               "production",
+			DenyDestroyInWorkspaces: []string{"prod*"},
+		}
+		utils := newTerraformExecuteTestsUtils()
+		utils.StdoutReturn = map[string]string{
+			"terraform show -json -no-color tfplan": terraformTestPlan,
+		}
+
+		err := runTerraformExecute(&config, nil, utils, &terraformExecuteCommonPipelineEnvironment{})
+
+		assert.EqualError(t, err, "terraform plan violates policies: plan destroys 1 resource(s) in workspace 'production' (aws_instance.old)")
+		assert.Empty(t, utils.comments)
+	})
+
+	t.Run("Saved plan is applied", func(t *testing.T) {
+		t.Parallel()
+
+		config := terraformExecuteOptions{
+			Command:          "apply",
+			TerraformSecrets: "/tmp/test",
+			PlanFile:         "tfplan",
+		}
+		utils := newTerraformExecuteTestsUtils()
+		utils.AddFile("tfplan", []byte("binary plan"))
+		utils.StdoutReturn = map[string]string{"terraform output -json": "{}"}
+
+		err := runTerraformExecute(&config, nil, utils, &terraformExecuteCommonPipelineEnvironment{})
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, mock.ExecCall{Exec: "terraform", Params: []string{"apply", "-auto-approve", "-no-color", "tfplan"}}, utils.Calls[0])
+		}
+	})
+
+	t.Run("Plan file is relative to the chdir directory", func(t *testing.T) {
+		t.Parallel()
+
+		cpe := terraformExecuteCommonPipelineEnvironment{}
+		config := terraformExecuteOptions{
+			Command:       "plan",
+			PlanFile:      "tfplan",
+			GlobalOptions: []string{"-chdir=infra"},
+		}
+		utils := newTerraformExecuteTestsUtils()
+		utils.StdoutReturn = map[string]string{
+			"terraform -chdir=infra output -json":                "{}",
+			"terraform -chdir=infra show -json -no-color tfplan": terraformTestPlan,
+		}
+
+		err := runTerraformExecute(&config, nil, utils, &cpe)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, mock.ExecCall{Exec: "terraform", Params: []string{"-chdir=infra", "plan", "-out=tfplan", "-no-color"}}, utils.Calls[0])
+			assert.Equal(t, "tfplan", cpe.custom.terraformPlanFile)
+			reports, err := utils.FileRead("terraformExecute_reports.json")
+			if assert.NoError(t, err) {
+				assert.Contains(t, string(reports), `"target":"infra/tfplan"`)
+			}
+		}
+	})
+
+	t.Run("Saved plan is applied in the chdir directory", func(t *testing.T) {
+		t.Parallel()
+
+		config := terraformExecuteOptions{
+			Command:       "apply",
+			PlanFile:      "tfplan",
+			GlobalOptions: []string{"-chdir=infra"},
+		}
+		utils := newTerraformExecuteTestsUtils()
+		utils.AddFile(filepath.Join("infra", "tfplan"), []byte("binary plan"))
+		utils.StdoutReturn = map[string]string{"terraform -chdir=infra output -json": "{}"}
+
+		err := runTerraformExecute(&config, nil, utils, &terraformExecuteCommonPipelineEnvironment{})
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, mock.ExecCall{Exec: "terraform", Params: []string{"-chdir=infra", "apply", "-auto-approve", "-no-color", "tfplan"}}, utils.Calls[0])
+		}
+	})
+
+	t.Run("Missing saved plan", func(t *testing.T) {
+		t.Parallel()
+
+		config := terraformExecuteOptions{
+			Command:  "apply",
+			PlanFile: "tfplan",
+		}
+		utils := newTerraformExecuteTestsUtils()
+
+		err := runTerraformExecute(&config, nil, utils, &terraformExecuteCommonPipelineEnvironment{})
+
+		assert.EqualError(t, err, "plan file 'tfplan' not found, run the plan command with the same planFile first")
+		assert.Empty(t, utils.Calls)
+	})
 }
+
+const terraformTestPlan = `{
+	"format_version": "1.2",
+	"resource_changes": [
+		{"address": "aws_instance.new", "type": "aws_instance", "change": {"actions": ["create"]}},
+		{"address": "aws_instance.old", "type": "aws_instance", "change": {"actions": ["delete"]}},
+		{"address": "aws_s3_bucket.logs", "type": "aws_s3_bucket", "change": {"actions": ["no-op"]}}
+	]
+}`
@@ -0,0 +1,270 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/SAP/jenkins-library/pkg/testreport"
+	"golang.org/x/mod/modfile"
+)
+
+const (
+	aggregatedTestResultsReport = "test-results-aggregated.json"
+	aggregatedJUnitReport       = "aggregated-junit.xml"
+	aggregatedCoverageReport    = "aggregated-cobertura-coverage.xml"
+)
+
+type testResultsAggregateUtils interface {
+	piperutils.FileUtils
+}
+
+type testResultsAggregateUtilsBundle struct {
+	*piperutils.Files
+}
+
+func newTestResultsAggregateUtils() testResultsAggregateUtils {
+	utils := testResultsAggregateUtilsBundle{
+		Files: &piperutils.Files{},
+	}
+	return &utils
+}
+
+// aggregatedTestResults is the content of the report test-results-aggregated.json
+type aggregatedTestResults struct {
+	Tests                testreport.TestSummary           `json:"tests"`
+	FailedTests          []testreport.TestCase            `json:"failedTests,omitempty"`
+	FlakyTests           []testreport.TestCase            `json:"flakyTests,omitempty"`
+	Coverage             *testreport.CoverageSummary      `json:"coverage,omitempty"`
+	ChangeDetectionBase  string                           `json:"changeDetectionBaseRef,omitempty"`
+	ChangedLinesCoverage *testreport.ChangedLinesCoverage `json:"changedLinesCoverage,omitempty"`
+	TestResults          []string                         `json:"testResults"`
+	CoverageReports      []string                         `json:"coverageReports"`
+}
+
+func testResultsAggregate(config testResultsAggregateOptions, telemetryData *telemetry.CustomData) {
+	utils := newTestResultsAggregateUtils()
+
+	err := runTestResultsAggregate(&config, utils)
+	if err != nil {
+		log.Entry().WithError(err).Fatal("aggregation of test results failed")
+	}
+}
+
+func runTestResultsAggregate(config *testResultsAggregateOptions, utils testResultsAggregateUtils) error {
+	// the outputs of previous executions of the step must not be aggregated again
+	excludes := append([]string{aggregatedJUnitReport, aggregatedCoverageReport}, config.ExcludePatterns...)
+
+	result := aggregatedTestResults{}
+	testResults, err := findReports(config.TestResultPatterns, excludes, utils)
+	if err != nil {
+		return err
+	}
+	result.TestResults = testResults
+	tests := []testreport.TestCase{}
+	for _, file := range testResults {
+		content, err := utils.FileRead(file)
+		if err != nil {
+			return fmt.Errorf("failed to read test results %v: %w", file, err)
+		}
+		fileTests, err := testreport.ParseJUnit(content)
+		if err != nil {
+			log.Entry().WithError(err).Warnf("skipping test results %v", file)
+			continue
+		}
+		tests = append(tests, fileTests...)
+	}
+	tests = testreport.MergeTests(tests)
+	result.Tests = testreport.Summarize(tests)
+	for _, test := range tests {
+		if test.Status == testreport.StatusFailed || test.Status == testreport.StatusError {
+			result.FailedTests = append(result.FailedTests, test)
+		}
+		if test.Flaky {
+			result.FlakyTests = append(result.FlakyTests, test)
+		}
+	}
+	log.Entry().Infof("%v tests in %v test results: %v passed, %v failed, %v errors, %v skipped, %v flaky",
+		result.Tests.Total, len(testResults), result.Tests.Passed, result.Tests.Failed, result.Tests.Errors, result.Tests.Skipped, result.Tests.Flaky)
+	for _, test := range result.FlakyTests {
+		log.Entry().Warnf("flaky test %v passed after %v attempts: %v", test.ID(), test.Attempts, test.Message)
+	}
+
+	coverageReports, err := findReports(config.CoverageReportPatterns, excludes, utils)
+	if err != nil {
+		return err
+	}
+	result.CoverageReports = coverageReports
+	coverage := testreport.Coverage{}
+	if len(coverageReports) > 0 {
+		resolver, err := coveragePathResolver(excludes, utils)
+		if err != nil {
+			return err
+		}
+		for _, file := range coverageReports {
+			content, err := utils.FileRead(file)
+			if err != nil {
+				return fmt.Errorf("failed to read coverage report %v: %w", file, err)
+			}
+			fileCoverage, format, err := testreport.ParseCoverage(content, file, resolver)
+			if err != nil {
+				log.Entry().WithError(err).Warnf("skipping coverage report %v", file)
+				continue
+			}
+			log.Entry().Debugf("read coverage of %v files from %v report %v", len(fileCoverage), format, file)
+			coverage.Merge(fileCoverage)
+		}
+	}
+	if len(coverage) > 0 {
+		summary := coverage.Summary()
+		result.Coverage = &summary
+		log.Entry().Infof("line coverage of %v files: %v%% (%v of %v lines)", len(coverage), summary.Percentage, summary.CoveredLines, summary.Lines)
+
+		if baseRef, _ := pullRequestBaseRef(config.ChangeDetectionBaseRef); len(baseRef) > 0 {
+			changed, err := changedLines(baseRef)
+			if err != nil {
+				log.Entry().WithError(err).Warn("failed to detect the changed lines, coverage of changed lines is not calculated")
+			} else {
+				changedCoverage := coverage.ChangedLines(changed)
+				result.ChangeDetectionBase = baseRef
+				result.ChangedLinesCoverage = &changedCoverage
+				log.Entry().Infof("line coverage of the changes since '%v': %v%% (%v of %v lines)", baseRef, changedCoverage.Percentage, changedCoverage.CoveredLines, changedCoverage.Lines)
+			}
+		}
+	}
+
+	if err := writeAggregatedReports(result, tests, coverage, utils); err != nil {
+		return err
+	}
+	return checkAggregatedTestResults(config, result)
+}
+
+// findReports returns the files matching the patterns, excluding the files matching any of the excludes
+func findReports(patterns, excludes []string, utils testResultsAggregateUtils) ([]string, error) {
+	found := map[string]bool{}
+	for _, pattern := range patterns {
+		matches, err := utils.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search for %v: %w", pattern, err)
+		}
+		matches, err = piperutils.ExcludeFiles(matches, excludes)
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range matches {
+			found[match] = true
+		}
+	}
+	files := make([]string, 0, len(found))
+	for file := range found {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// coveragePathResolver resolves the paths of source files relative to the current directory, the Go modules of the
+// repository are required to resolve the package paths of Go cover profiles
+func coveragePathResolver(excludes []string, utils testResultsAggregateUtils) (testreport.PathResolver, error) {
+	workDir, err := utils.Getwd()
+	if err != nil {
+		return testreport.PathResolver{}, fmt.Errorf("failed to get current working directory: %w", err)
+	}
+	resolver := testreport.PathResolver{WorkDir: workDir, GoModules: map[string]string{}}
+	descriptors, err := findReports([]string{"**/go.mod"}, excludes, utils)
+	if err != nil {
+		return resolver, err
+	}
+	for _, descriptor := range descriptors {
+		content, err := utils.FileRead(descriptor)
+		if err != nil {
+			return resolver, fmt.Errorf("failed to read %v: %w", descriptor, err)
+		}
+		if module := modfile.ModulePath(content); len(module) > 0 {
+			resolver.GoModules[module] = filepath.ToSlash(filepath.Dir(descriptor))
+		}
+	}
+	return resolver, nil
+}
+
+func writeAggregatedReports(result aggregatedTestResults, tests []testreport.TestCase, coverage testreport.Coverage, utils testResultsAggregateUtils) error {
+	content, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to serialize aggregated test results: %w", err)
+	}
+	if err := utils.FileWrite(aggregatedTestResultsReport, content, 0o644); err != nil {
+		return fmt.Errorf("failed to write %v: %w", aggregatedTestResultsReport, err)
+	}
+
+	if len(tests) > 0 {
+		junit, err := testreport.WriteJUnit(tests)
+		if err != nil {
+			return fmt.Errorf("failed to create aggregated test results: %w", err)
+		}
+		if err := utils.FileWrite(aggregatedJUnitReport, junit, 0o644); err != nil {
+			return fmt.Errorf("failed to write %v: %w", aggregatedJUnitReport, err)
+		}
+	}
+
+	if len(coverage) > 0 {
+		cobertura, err := testreport.WriteCobertura(coverage, ".", time.Now())
+		if err != nil {
+			return fmt.Errorf("failed to create aggregated coverage report: %w", err)
+		}
+		if err := utils.FileWrite(aggregatedCoverageReport, cobertura, 0o644); err != nil {
+			return fmt.Errorf("failed to write %v: %w", aggregatedCoverageReport, err)
+		}
+	}
+	return nil
+}
+
+// checkAggregatedTestResults returns an error listing all violated conditions
+func checkAggregatedTestResults(config *testResultsAggregateOptions, result aggregatedTestResults) error {
+	violations := []string{}
+	category := log.ErrorTest
+	if config.FailOnTestFailures && len(result.FailedTests) > 0 {
+		violations = append(violations, fmt.Sprintf("%v tests failed", len(result.FailedTests)))
+	}
+	if config.FailOnFlakyTests && len(result.FlakyTests) > 0 {
+		violations = append(violations, fmt.Sprintf("%v tests are flaky", len(result.FlakyTests)))
+	}
+	if config.CoverageThreshold > 0 {
+		percentage := 0.0
+		if result.Coverage != nil {
+			percentage = result.Coverage.Percentage
+		}
+		if percentage < float64(config.CoverageThreshold) {
+			violations = append(violations, fmt.Sprintf("line coverage %v%% is below the threshold of %v%%", percentage, config.CoverageThreshold))
+			category = log.ErrorCompliance
+		}
+	}
+	if config.ChangedLinesCoverageThreshold > 0 && result.ChangedLinesCoverage != nil && result.ChangedLinesCoverage.Lines > 0 {
+		if result.ChangedLinesCoverage.Percentage < float64(config.ChangedLinesCoverageThreshold) {
+			violations = append(violations, fmt.Sprintf("line coverage of the changes %v%% is below the threshold of %v%%", result.ChangedLinesCoverage.Percentage, config.ChangedLinesCoverageThreshold))
+			category = log.ErrorCompliance
+			for _, file := range sortedKeys(result.ChangedLinesCoverage.Uncovered) {
+				log.Entry().Infof("changed lines of %v not covered by tests: %v", file, result.ChangedLinesCoverage.Uncovered[file])
+			}
+		}
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	log.SetErrorCategory(category)
+	return fmt.Errorf("test results do not meet the requirements: %v", strings.Join(violations, ", "))
+}
+
+func sortedKeys(m map[string][]int) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,330 @@
+// Code generated by piper's step-generator. DO NOT EDIT.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/gcp"
+	"github.com/SAP/jenkins-library/pkg/gcs"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/splunk"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/SAP/jenkins-library/pkg/validation"
+	"github.com/bmatcuk/doublestar"
+	"github.com/spf13/cobra"
+)
+
+type testResultsAggregateOptions struct {
+	TestResultPatterns            []string `json:"testResultPatterns,omitempty"`
+	CoverageReportPatterns        []string `json:"coverageReportPatterns,omitempty"`
+	ExcludePatterns               []string `json:"excludePatterns,omitempty"`
+	CoverageThreshold             int      `json:"coverageThreshold,omitempty"`
+	ChangedLinesCoverageThreshold int      `json:"changedLinesCoverageThreshold,omitempty"`
+	ChangeDetectionBaseRef        string   `json:"changeDetectionBaseRef,omitempty"`
+	FailOnTestFailures            bool     `json:"failOnTestFailures,omitempty"`
+	FailOnFlakyTests              bool     `json:"failOnFlakyTests,omitempty"`
+}
+
+type testResultsAggregateReports struct {
+}
+
+func (p *testResultsAggregateReports) persist(stepConfig testResultsAggregateOptions, gcpJsonKeyFilePath string, gcsBucketId string, gcsFolderPath string, gcsSubFolder string) {
+	if gcsBucketId == "" {
+		log.Entry().Info("persisting reports to GCS is disabled, because gcsBucketId is empty")
+		return
+	}
+	log.Entry().Info("Uploading reports to Google Cloud Storage...")
+	content := []gcs.ReportOutputParam{
+		{FilePattern: "test-results-aggregated.json", ParamRef: "", StepResultType: "test-results"},
+		{FilePattern: "aggregated-junit.xml", ParamRef: "", StepResultType: "junit"},
+		{FilePattern: "aggregated-cobertura-coverage.xml", ParamRef: "", StepResultType: "cobertura-coverage"},
+	}
+
+	gcsClient, err := gcs.NewClient(gcpJsonKeyFilePath, "")
+	if err != nil {
+		log.Entry().Errorf("creation of GCS client failed: %v", err)
+		return
+	}
+	defer gcsClient.Close()
+	structVal := reflect.ValueOf(&stepConfig).Elem()
+	inputParameters := map[string]string{}
+	for i := 0; i < structVal.NumField(); i++ {
+		field := structVal.Type().Field(i)
+		if field.Type.String() == "string" {
+			paramName := strings.Split(field.Tag.Get("json"), ",")
+			paramValue, _ := structVal.Field(i).Interface().(string)
+			inputParameters[paramName[0]] = paramValue
+		}
+	}
+	if err := gcs.PersistReportsToGCS(gcsClient, content, inputParameters, gcsFolderPath, gcsBucketId, gcsSubFolder, doublestar.Glob, os.Stat); err != nil {
+		log.Entry().Errorf("failed to persist reports: %v", err)
+	}
+}
+
+// TestResultsAggregateCommand Aggregates the test results and coverage reports of all build and test steps and enforces coverage thresholds.
+func TestResultsAggregateCommand() *cobra.Command {
+	const STEP_NAME = "testResultsAggregate"
+
+	metadata := testResultsAggregateMetadata()
+	var stepConfig testResultsAggregateOptions
+	var startTime time.Time
+	var reports testResultsAggregateReports
+	var logCollector *log.CollectorHook
+	var splunkClient *splunk.Splunk
+	telemetryClient := &telemetry.Telemetry{}
+
+	var createTestResultsAggregateCmd = &cobra.Command{
+		Use:   STEP_NAME,
+		Short: "Aggregates the test results and coverage reports of all build and test steps and enforces coverage thresholds.",
+		Long: `This step collects the test results and coverage reports written by other steps like ` + "`" + `mavenBuild` + "`" + `, ` + "`" + `golangBuild` + "`" + `, ` + "`" + `npmExecuteTests` + "`" + `,
+` + "`" + `karmaExecuteTests` + "`" + `, ` + "`" + `gaugeExecuteTests` + "`" + ` or ` + "`" + `batsExecuteTests` + "`" + ` and combines them into one consolidated view.
+
+Test results are read in JUnit XML format. Executions of the same test case of a suite in different reports, e.g. from reruns of failed tests,
+are merged. Test cases with the same class and name in other suites are kept apart. A test case which failed in one execution and passed in another is considered flaky.
+The reruns reported by the Maven Surefire plugin (` + "`" + `rerunFailingTestsCount` + "`" + `) are detected as well.
+
+Coverage reports are read in the formats Cobertura, JaCoCo, lcov and Go cover profiles.
+The coverage of all reports is merged per source file, relative to the root of the repository.
+In pull requests the coverage of the lines changed compared to the target branch is calculated in addition.
+
+The step writes the following reports:
+
+* ` + "`" + `test-results-aggregated.json` + "`" + `: summary of tests and coverage including the flaky tests and the uncovered changed lines
+* ` + "`" + `aggregated-junit.xml` + "`" + `: merged test results in JUnit format, flaky tests are reported as ` + "`" + `flakyFailure` + "`" + ` like the Maven Surefire plugin does
+* ` + "`" + `aggregated-cobertura-coverage.xml` + "`" + `: merged coverage in Cobertura format`,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			startTime = time.Now()
+			log.SetStepName(STEP_NAME)
+			log.SetVerbose(GeneralConfig.Verbose)
+
+			GeneralConfig.GitHubAccessTokens = ResolveAccessTokens(GeneralConfig.GitHubTokens)
+
+			path, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+			fatalHook := &log.FatalHook{CorrelationID: GeneralConfig.CorrelationID, Path: path}
+			log.RegisterHook(fatalHook)
+
+			err = PrepareConfig(cmd, &metadata, STEP_NAME, &stepConfig, config.OpenPiperFile)
+			if err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+
+			// Set step error patterns for improved error detection
+			stepErrors := make([]log.StepError, len(metadata.Metadata.Errors))
+			for i, err := range metadata.Metadata.Errors {
+				stepErrors[i] = log.StepError{
+					Pattern:  err.Pattern,
+					Message:  err.Message,
+					Category: err.Category,
+				}
+			}
+			log.SetStepErrors(stepErrors)
+
+			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
+				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
+				log.RegisterHook(&sentryHook)
+			}
+
+			if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 || len(GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint) > 0 {
+				splunkClient = &splunk.Splunk{}
+				logCollector = &log.CollectorHook{CorrelationID: GeneralConfig.CorrelationID}
+				log.RegisterHook(logCollector)
+			}
+
+			if err = log.RegisterANSHookIfConfigured(GeneralConfig.CorrelationID); err != nil {
+				log.Entry().WithError(err).Warn("failed to set up SAP Alert Notification Service log hook")
+			}
+
+			validation, err := validation.New(validation.WithJSONNamesForStructFields(), validation.WithPredefinedErrorMessages())
+			if err != nil {
+				return err
+			}
+			if err = validation.ValidateStruct(stepConfig); err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+
+			return nil
+		},
+		Run: func(_ *cobra.Command, _ []string) {
+			vaultClient := config.GlobalVaultClient()
+			if vaultClient != nil {
+				defer vaultClient.MustRevokeToken()
+			}
+
+			stepTelemetryData := telemetry.CustomData{}
+			stepTelemetryData.ErrorCode = "1"
+			handler := func() {
+				reports.persist(stepConfig, GeneralConfig.GCPJsonKeyFilePath, GeneralConfig.GCSBucketId, GeneralConfig.GCSFolderPath, GeneralConfig.GCSSubFolder)
+				config.RemoveVaultSecretFiles()
+				stepTelemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
+				stepTelemetryData.ErrorCategory = log.GetErrorCategory().String()
+				stepTelemetryData.PiperCommitHash = GitCommit
+				telemetryClient.SetData(&stepTelemetryData)
+				telemetryClient.LogStepTelemetryData()
+				if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+					splunkClient.Initialize(GeneralConfig.CorrelationID,
+						GeneralConfig.HookConfig.SplunkConfig.Dsn,
+						GeneralConfig.HookConfig.SplunkConfig.Token,
+						GeneralConfig.HookConfig.SplunkConfig.Index,
+						GeneralConfig.HookConfig.SplunkConfig.SendLogs)
+					splunkClient.Send(telemetryClient.GetData(), logCollector)
+				}
+				if len(GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint) > 0 {
+					splunkClient.Initialize(GeneralConfig.CorrelationID,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblToken,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblIndex,
+						GeneralConfig.HookConfig.SplunkConfig.SendLogs)
+					splunkClient.Send(telemetryClient.GetData(), logCollector)
+				}
+				if GeneralConfig.HookConfig.GCPPubSubConfig.Enabled {
+					err := gcp.NewGcpPubsubClient(
+						vaultClient,
+						GeneralConfig.HookConfig.GCPPubSubConfig.ProjectNumber,
+						GeneralConfig.HookConfig.GCPPubSubConfig.IdentityPool,
+						GeneralConfig.HookConfig.GCPPubSubConfig.IdentityProvider,
+						GeneralConfig.CorrelationID,
+						GeneralConfig.HookConfig.OIDCConfig.RoleID,
+					).Publish(GeneralConfig.HookConfig.GCPPubSubConfig.Topic, telemetryClient.GetDataBytes())
+					if err != nil {
+						log.Entry().WithError(err).Warn("event publish failed")
+					}
+				}
+			}
+			log.DeferExitHandler(handler)
+			defer handler()
+			telemetryClient.Initialize(STEP_NAME)
+			testResultsAggregate(stepConfig, &stepTelemetryData)
+			stepTelemetryData.ErrorCode = "0"
+			log.Entry().Info("SUCCESS")
+		},
+	}
+
+	addTestResultsAggregateFlags(createTestResultsAggregateCmd, &stepConfig)
+	return createTestResultsAggregateCmd
+}
+
+func addTestResultsAggregateFlags(cmd *cobra.Command, stepConfig *testResultsAggregateOptions) {
+	cmd.Flags().StringSliceVar(&stepConfig.TestResultPatterns, "testResultPatterns", []string{`**/TEST-*.xml`, `**/junit*.xml`}, "Glob patterns of the test results in JUnit XML format.")
+	cmd.Flags().StringSliceVar(&stepConfig.CoverageReportPatterns, "coverageReportPatterns", []string{`**/cobertura-coverage.xml`, `**/coverage.cobertura.xml`, `**/jacoco.xml`, `**/lcov.info`}, "Glob patterns of the coverage reports. Supported formats are Cobertura, JaCoCo, lcov and Go cover profiles, the format is detected from the content.")
+	cmd.Flags().StringSliceVar(&stepConfig.ExcludePatterns, "excludePatterns", []string{`**/node_modules/**`}, "Glob patterns of files which are ignored even if they match the patterns of test results or coverage reports.")
+	cmd.Flags().IntVar(&stepConfig.CoverageThreshold, "coverageThreshold", 0, "Minimum line coverage in percent of all source files. The step fails if the coverage is lower, a value of `0` disables the check.")
+	cmd.Flags().IntVar(&stepConfig.ChangedLinesCoverageThreshold, "changedLinesCoverageThreshold", 0, "Minimum line coverage in percent of the lines changed compared to `changeDetectionBaseRef`. The step fails if the coverage is lower, a value of `0` disables the check. Changed lines which are not executable, e.g. comments, are ignored.")
+	cmd.Flags().StringVar(&stepConfig.ChangeDetectionBaseRef, "changeDetectionBaseRef", os.Getenv("PIPER_changeDetectionBaseRef"), "Branch or commit the changed lines are detected against. Defaults to the target branch of the pull request provided by the orchestrator, outside of pull requests no changed lines are detected.")
+	cmd.Flags().BoolVar(&stepConfig.FailOnTestFailures, "failOnTestFailures", false, "Defines if the step fails in case of failed tests, after reruns are taken into account.")
+	cmd.Flags().BoolVar(&stepConfig.FailOnFlakyTests, "failOnFlakyTests", false, "Defines if the step fails in case of flaky tests, i.e. tests which passed only in a rerun.")
+
+}
+
+// retrieve step metadata
+func testResultsAggregateMetadata() config.StepData {
+	var theMetaData = config.StepData{
+		Metadata: config.StepMetadata{
+			Name:        "testResultsAggregate",
+			Aliases:     []config.Alias{},
+			Description: "Aggregates the test results and coverage reports of all build and test steps and enforces coverage thresholds.",
+		},
+		Spec: config.StepSpec{
+			Inputs: config.StepInputs{
+				Parameters: []config.StepParameters{
+					{
+						Name:        "testResultPatterns",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{`**/TEST-*.xml`, `**/junit*.xml`},
+					},
+					{
+						Name:        "coverageReportPatterns",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{`**/cobertura-coverage.xml`, `**/coverage.cobertura.xml`, `**/jacoco.xml`, `**/lcov.info`},
+					},
+					{
+						Name:        "excludePatterns",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{`**/node_modules/**`},
+					},
+					{
+						Name:        "coverageThreshold",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     0,
+					},
+					{
+						Name:        "changedLinesCoverageThreshold",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     0,
+					},
+					{
+						Name:        "changeDetectionBaseRef",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "GENERAL", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_changeDetectionBaseRef"),
+					},
+					{
+						Name:        "failOnTestFailures",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name:        "failOnFlakyTests",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+				},
+			},
+			Outputs: config.StepOutputs{
+				Resources: []config.StepResources{
+					{
+						Name: "reports",
+						Type: "reports",
+						Parameters: []map[string]interface{}{
+							{"filePattern": "test-results-aggregated.json", "type": "test-results"},
+							{"filePattern": "aggregated-junit.xml", "type": "junit"},
+							{"filePattern": "aggregated-cobertura-coverage.xml", "type": "cobertura-coverage"},
+						},
+					},
+				},
+			},
+		},
+	}
+	return theMetaData
+}
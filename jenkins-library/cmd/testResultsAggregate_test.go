@@ -0,0 +1,184 @@
+//go:build unit
+// +build unit
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testResultsAggregateMockUtils struct {
+	*mock.FilesMock
+}
+
+func newTestResultsAggregateTestsUtils() testResultsAggregateMockUtils {
+	utils := testResultsAggregateMockUtils{
+		FilesMock: &mock.FilesMock{},
+	}
+	utils.AddFile("api/target/surefire-reports/TEST-com.example.CalculatorTest.xml", []byte(`<testsuite name="com.example.CalculatorTest">
+  <testcase name="add" classname="com.example.CalculatorTest" time="0.1"/>
+  <testcase name="remote" classname="com.example.CalculatorTest" time="0.2">
+    <flakyFailure message="connection refused"/>
+  </testcase>
+</testsuite>`))
+	utils.AddFile("ui/TEST-ui.xml", []byte(`<testsuites>
+  <testsuite name="ui">
+    <testcase classname="App" name="renders" time="0.01"><failure message="timeout"/></testcase>
+  </testsuite>
+</testsuites>`))
+	utils.AddFile("ui/TEST-ui-rerun.xml", []byte(`<testsuite name="ui">
+  <testcase classname="App" name="renders" time="0.01"/>
+</testsuite>`))
+	utils.AddFile("api/target/site/jacoco/jacoco.xml", []byte(`<report name="api">
+  <package name="com/example">
+    <sourcefile name="Calculator.java">
+      <line nr="3" mi="0" ci="3" mb="0" cb="0"/>
+      <line nr="5" mi="2" ci="0" mb="0" cb="0"/>
+    </sourcefile>
+  </package>
+</report>`))
+	utils.AddFile("ui/coverage/lcov.info", []byte("SF:src/app.ts\nDA:1,1\nDA:2,1\nend_of_record\n"))
+	utils.AddFile("ui/node_modules/lib/coverage/lcov.info", []byte("SF:index.js\nDA:1,0\nend_of_record\n"))
+	return utils
+}
+
+func mockChangedLines(t *testing.T, lines map[string][]int, err error) {
+	original := changedLines
+	changedLines = func(baseRef string) (map[string][]int, error) {
+		assert.Equal(t, "main", baseRef)
+		return lines, err
+	}
+	t.Cleanup(func() { changedLines = original })
+}
+
+func defaultTestResultsAggregateOptions() testResultsAggregateOptions {
+	return testResultsAggregateOptions{
+		TestResultPatterns:     []string{"**/TEST-*.xml", "**/junit*.xml"},
+		CoverageReportPatterns: []string{"**/cobertura-coverage.xml", "**/jacoco.xml", "**/lcov.info"},
+		ExcludePatterns:        []string{"**/node_modules/**"},
+		ChangeDetectionBaseRef: "main",
+	}
+}
+
+func readAggregatedTestResults(t *testing.T, utils testResultsAggregateMockUtils) aggregatedTestResults {
+	content, err := utils.FileRead(aggregatedTestResultsReport)
+	require.NoError(t, err)
+	result := aggregatedTestResults{}
+	require.NoError(t, json.Unmarshal(content, &result))
+	return result
+}
+
+func TestRunTestResultsAggregate(t *testing.T) {
+	t.Run("aggregates test results and coverage", func(t *testing.T) {
+		mockChangedLines(t, map[string][]int{"com/example/Calculator.java": {3, 4, 5}, "ui/src/app.ts": {2}}, nil)
+		config := defaultTestResultsAggregateOptions()
+		utils := newTestResultsAggregateTestsUtils()
+
+		err := runTestResultsAggregate(&config, utils)
+
+		require.NoError(t, err)
+		result := readAggregatedTestResults(t, utils)
+		assert.Equal(t, 3, result.Tests.Total)
+		assert.Equal(t, 3, result.Tests.Passed)
+		assert.Equal(t, 2, result.Tests.Flaky)
+		assert.Empty(t, result.FailedTests)
+		assert.Equal(t, []string{"api/target/site/jacoco/jacoco.xml", "ui/coverage/lcov.info"}, result.CoverageReports)
+		assert.Equal(t, 75.0, result.Coverage.Percentage)
+		assert.Equal(t, "main", result.ChangeDetectionBase)
+		assert.Equal(t, 66.66, result.ChangedLinesCoverage.Percentage)
+		assert.Equal(t, map[string][]int{"com/example/Calculator.java": {5}}, result.ChangedLinesCoverage.Uncovered)
+		assert.True(t, utils.HasWrittenFile(aggregatedJUnitReport))
+		assert.True(t, utils.HasWrittenFile(aggregatedCoverageReport))
+	})
+
+	t.Run("coverage below thresholds", func(t *testing.T) {
+		mockChangedLines(t, map[string][]int{"com/example/Calculator.java": {5}}, nil)
+		config := defaultTestResultsAggregateOptions()
+		config.CoverageThreshold = 80
+		config.ChangedLinesCoverageThreshold = 50
+		utils := newTestResultsAggregateTestsUtils()
+
+		err := runTestResultsAggregate(&config, utils)
+
+		assert.EqualError(t, err, "test results do not meet the requirements: line coverage 75% is below the threshold of 80%, line coverage of the changes 0% is below the threshold of 50%")
+		// the reports are written nevertheless
+		assert.True(t, utils.HasWrittenFile(aggregatedTestResultsReport))
+	})
+
+	t.Run("flaky and failed tests", func(t *testing.T) {
+		mockChangedLines(t, nil, nil)
+		config := defaultTestResultsAggregateOptions()
+		config.FailOnTestFailures = true
+		config.FailOnFlakyTests = true
+		utils := newTestResultsAggregateTestsUtils()
+		utils.AddFile("ui/junit-e2e.xml", []byte(`<testsuite name="e2e"><testcase classname="Login" name="works"><error message="crashed"/></testcase></testsuite>`))
+
+		err := runTestResultsAggregate(&config, utils)
+
+		assert.EqualError(t, err, "test results do not meet the requirements: 1 tests failed, 2 tests are flaky")
+		result := readAggregatedTestResults(t, utils)
+		assert.Equal(t, "Login#works", result.FailedTests[0].ID())
+	})
+
+	t.Run("test cases of other suites are no reruns", func(t *testing.T) {
+		mockChangedLines(t, nil, nil)
+		config := defaultTestResultsAggregateOptions()
+		config.FailOnTestFailures = true
+		utils := newTestResultsAggregateTestsUtils()
+		utils.AddFile("admin/TEST-admin.xml", []byte(`<testsuite name="admin"><testcase classname="App" name="renders"><failure message="missing title"/></testcase></testsuite>`))
+
+		err := runTestResultsAggregate(&config, utils)
+
+		assert.EqualError(t, err, "test results do not meet the requirements: 1 tests failed")
+		result := readAggregatedTestResults(t, utils)
+		assert.Equal(t, 4, result.Tests.Total)
+		assert.Equal(t, 3, result.Tests.Passed)
+		assert.Equal(t, 1, result.Tests.Failed)
+		assert.Equal(t, "admin", result.FailedTests[0].Suite)
+	})
+
+	t.Run("failing change detection skips the coverage of changed lines", func(t *testing.T) {
+		mockChangedLines(t, nil, fmt.Errorf("base not found"))
+		config := defaultTestResultsAggregateOptions()
+		config.ChangedLinesCoverageThreshold = 100
+		utils := newTestResultsAggregateTestsUtils()
+
+		err := runTestResultsAggregate(&config, utils)
+
+		require.NoError(t, err)
+		assert.Nil(t, readAggregatedTestResults(t, utils).ChangedLinesCoverage)
+	})
+
+	t.Run("invalid reports are skipped", func(t *testing.T) {
+		mockChangedLines(t, nil, nil)
+		config := defaultTestResultsAggregateOptions()
+		utils := &mock.FilesMock{}
+		utils.AddFile("TEST-broken.xml", []byte(`<html></html>`))
+		utils.AddFile("coverage/lcov.info", []byte(`no coverage`))
+
+		err := runTestResultsAggregate(&config, testResultsAggregateMockUtils{utils})
+
+		require.NoError(t, err)
+		assert.True(t, utils.HasWrittenFile(aggregatedTestResultsReport))
+		assert.False(t, utils.HasWrittenFile(aggregatedJUnitReport))
+		assert.False(t, utils.HasWrittenFile(aggregatedCoverageReport))
+	})
+}
+
+func TestCoveragePathResolver(t *testing.T) {
+	utils := &mock.FilesMock{}
+	utils.AddFile("go.mod", []byte("module github.com/example/app\n\ngo 1.22\n"))
+	utils.AddFile("tools/go.mod", []byte("module github.com/example/app/tools\n"))
+	utils.AddFile("vendor/github.com/lib/go.mod", []byte("module github.com/lib\n"))
+
+	resolver, err := coveragePathResolver([]string{"vendor/**"}, testResultsAggregateMockUtils{utils})
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"github.com/example/app": ".", "github.com/example/app/tools": "tools"}, resolver.GoModules)
+}
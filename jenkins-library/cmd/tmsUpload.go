@@ -1,25 +1,32 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"path/filepath"
+	"strconv"
+	"time"
 
 	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/reporting"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 	"github.com/SAP/jenkins-library/pkg/tms"
+	"github.com/pkg/errors"
 )
 
-func tmsUpload(uploadConfig tmsUploadOptions, telemetryData *telemetry.CustomData, influx *tmsUploadInflux) {
+func tmsUpload(uploadConfig tmsUploadOptions, telemetryData *telemetry.CustomData, commonPipelineEnvironment *tmsUploadCommonPipelineEnvironment, influx *tmsUploadInflux) {
 	utils := tms.NewTmsUtils()
 	config := convertUploadOptions(uploadConfig)
 	communicationInstance := tms.SetupCommunication(config)
 
-	err := runTmsUpload(uploadConfig, communicationInstance, utils)
+	err := runTmsUpload(uploadConfig, communicationInstance, utils, commonPipelineEnvironment, time.Sleep)
 	if err != nil {
 		log.Entry().WithError(err).Fatal("Failed to run tmsUpload step")
 	}
 }
 
-func runTmsUpload(uploadConfig tmsUploadOptions, communicationInstance tms.CommunicationInterface, utils tms.TmsUtils) error {
+func runTmsUpload(uploadConfig tmsUploadOptions, communicationInstance tms.CommunicationInterface, utils tms.TmsUtils, commonPipelineEnvironment *tmsUploadCommonPipelineEnvironment, sleep func(time.Duration)) error {
 	config := convertUploadOptions(uploadConfig)
 	fileInfo, errUploadFile := tms.UploadFile(config, communicationInstance, utils)
 	if errUploadFile != nil {
@@ -31,15 +38,71 @@ func runTmsUpload(uploadConfig tmsUploadOptions, communicationInstance tms.Commu
 		return errUploadDescriptors
 	}
 
-	_, errUploadFileToNode := communicationInstance.UploadFileToNode(fileInfo, config.NodeName, config.CustomDescription, config.NamedUser)
+	uploadResponse, errUploadFileToNode := communicationInstance.UploadFileToNode(fileInfo, config.NodeName, config.CustomDescription, config.NamedUser)
 	if errUploadFileToNode != nil {
 		log.SetErrorCategory(log.ErrorService)
 		return fmt.Errorf("failed to upload file to node: %w", errUploadFileToNode)
 	}
+	commonPipelineEnvironment.custom.tmsTransportRequestID = strconv.FormatInt(uploadResponse.TransportRequestId, 10)
 
+	if len(uploadConfig.ImportNodes) > 0 {
+		return promoteTransportRequest(uploadConfig, uploadResponse.TransportRequestId, communicationInstance, utils, commonPipelineEnvironment, sleep)
+	}
 	return nil
 }
 
+func promoteTransportRequest(uploadConfig tmsUploadOptions, transportRequestId int64, communicationInstance tms.CommunicationInterface, utils tms.TmsUtils, commonPipelineEnvironment *tmsUploadCommonPipelineEnvironment, sleep func(time.Duration)) error {
+	options := tms.PromotionOptions{
+		TransportRequestId: transportRequestId,
+		ImportNodes:        uploadConfig.ImportNodes,
+		NamedUser:          uploadConfig.NamedUser,
+		MtaVersion:         uploadConfig.MtaVersion,
+		PollInterval:       time.Duration(uploadConfig.ImportPollInterval) * time.Second,
+		Timeout:            time.Duration(uploadConfig.ImportTimeout) * time.Second,
+		Sleep:              sleep,
+	}
+	if len(uploadConfig.NodeExtDescriptorMapping) > 0 {
+		// the MTA ID is only required to report the MTA extension descriptors applied in the nodes
+		mtaYamlMap, errGetMtaYamlAsMap := tms.GetYamlAsMap(utils, "mta.yaml")
+		if errGetMtaYamlAsMap != nil {
+			log.Entry().WithError(errGetMtaYamlAsMap).Warn("failed to read the MTA ID from mta.yaml, the applied MTA extension descriptors are not reported")
+		} else {
+			options.MtaId = fmt.Sprintf("%v", mtaYamlMap["ID"])
+		}
+	}
+
+	results, errPromote := tms.PromoteTransportRequest(communicationInstance, options)
+
+	nodeResults := []map[string]interface{}{}
+	resultBytes, _ := json.Marshal(results)
+	json.Unmarshal(resultBytes, &nodeResults)
+	commonPipelineEnvironment.custom.tmsNodeResults = nodeResults
+
+	if errReport := writeTmsPromotionReport(results, utils); errReport != nil {
+		log.Entry().WithError(errReport).Warn("failed to write promotion report")
+	}
+	return errPromote
+}
+
+func writeTmsPromotionReport(results []tms.NodeResult, utils tms.TmsUtils) error {
+	report := tms.CreatePromotionReport(results)
+	report.StepName = "tmsUpload"
+	report.ReportTime = time.Now()
+
+	htmlReport, _ := report.ToHTML()
+	if err := utils.FileWrite("tmsPromotionReport.html", htmlReport, 0o666); err != nil {
+		return errors.Wrap(err, "failed to write html report")
+	}
+	jsonReport, _ := report.ToJSON()
+	if err := utils.MkdirAll(reporting.StepReportDirectory, 0o777); err != nil {
+		return errors.Wrap(err, "failed to create reporting directory")
+	}
+	if err := utils.FileWrite(filepath.Join(reporting.StepReportDirectory, "tmsUpload_promotion.json"), jsonReport, 0o666); err != nil {
+		return errors.Wrap(err, "failed to write json report")
+	}
+	return piperutils.PersistReportsAndLinks("tmsUpload", "", utils, []piperutils.Path{{Name: "TMS Promotion Report", Target: "tmsPromotionReport.html"}}, nil)
+}
+
 func convertUploadOptions(uploadConfig tmsUploadOptions) tms.Options {
 	var config tms.Options
 	config.ServiceKey = uploadConfig.ServiceKey
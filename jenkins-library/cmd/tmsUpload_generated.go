@@ -6,15 +6,19 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"time"
 
 	"github.com/SAP/jenkins-library/pkg/config"
 	"github.com/SAP/jenkins-library/pkg/gcp"
+	"github.com/SAP/jenkins-library/pkg/gcs"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/piperenv"
 	"github.com/SAP/jenkins-library/pkg/splunk"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 	"github.com/SAP/jenkins-library/pkg/validation"
+	"github.com/bmatcuk/doublestar"
 	"github.com/spf13/cobra"
 )
 
@@ -29,6 +33,73 @@ type tmsUploadOptions struct {
 	NodeExtDescriptorMapping map[string]interface{} `json:"nodeExtDescriptorMapping,omitempty"`
 	Proxy                    string                 `json:"proxy,omitempty"`
 	StashContent             []string               `json:"stashContent,omitempty"`
+	ImportNodes              []string               `json:"importNodes,omitempty"`
+	ImportPollInterval       int                    `json:"importPollInterval,omitempty"`
+	ImportTimeout            int                    `json:"importTimeout,omitempty"`
+}
+
+type tmsUploadCommonPipelineEnvironment struct {
+	custom struct {
+		tmsTransportRequestID string
+		tmsNodeResults        []map[string]interface{}
+	}
+}
+
+func (p *tmsUploadCommonPipelineEnvironment) persist(path, resourceName string) {
+	content := []struct {
+		category string
+		name     string
+		value    interface{}
+	}{
+		{category: "custom", name: "tmsTransportRequestId", value: p.custom.tmsTransportRequestID},
+		{category: "custom", name: "tmsNodeResults", value: p.custom.tmsNodeResults},
+	}
+
+	errCount := 0
+	for _, param := range content {
+		err := piperenv.SetResourceParameter(path, resourceName, filepath.Join(param.category, param.name), param.value)
+		if err != nil {
+			log.Entry().WithError(err).Error("Error persisting piper environment.")
+			errCount++
+		}
+	}
+	if errCount > 0 {
+		log.Entry().Error("failed to persist Piper environment")
+	}
+}
+
+type tmsUploadReports struct {
+}
+
+func (p *tmsUploadReports) persist(stepConfig tmsUploadOptions, gcpJsonKeyFilePath string, gcsBucketId string, gcsFolderPath string, gcsSubFolder string) {
+	if gcsBucketId == "" {
+		log.Entry().Info("persisting reports to GCS is disabled, because gcsBucketId is empty")
+		return
+	}
+	log.Entry().Info("Uploading reports to Google Cloud Storage...")
+	content := []gcs.ReportOutputParam{
+		{FilePattern: "**/tmsPromotionReport.html", ParamRef: "", StepResultType: "tms"},
+	}
+
+	gcsClient, err := gcs.NewClient(gcpJsonKeyFilePath, "")
+	if err != nil {
+		log.Entry().Errorf("creation of GCS client failed: %v", err)
+		return
+	}
+	defer gcsClient.Close()
+	structVal := reflect.ValueOf(&stepConfig).Elem()
+	inputParameters := map[string]string{}
+	for i := 0; i < structVal.NumField(); i++ {
+		field := structVal.Type().Field(i)
+		if field.Type.String() == "string" {
+			paramName := strings.Split(field.Tag.Get("json"), ",")
+			paramValue, _ := structVal.Field(i).Interface().(string)
+			inputParameters[paramName[0]] = paramValue
+		}
+	}
+	if err := gcs.PersistReportsToGCS(gcsClient, content, inputParameters, gcsFolderPath, gcsBucketId, gcsSubFolder, doublestar.Glob, os.Stat); err != nil {
+		log.Entry().Errorf("failed to persist reports: %v", err)
+	}
 }
 
 type tmsUploadInflux struct {
@@ -71,6 +142,8 @@ func TmsUploadCommand() *cobra.Command {
 	metadata := tmsUploadMetadata()
 	var stepConfig tmsUploadOptions
 	var startTime time.Time
+	var commonPipelineEnvironment tmsUploadCommonPipelineEnvironment
+	var reports tmsUploadReports
 	var influx tmsUploadInflux
 	var logCollector *log.CollectorHook
 	var splunkClient *splunk.Splunk
@@ -155,6 +228,8 @@ For more information, see [official documentation of SAP Cloud Transport Managem
 			stepTelemetryData := telemetry.CustomData{}
 			stepTelemetryData.ErrorCode = "1"
 			handler := func() {
+				commonPipelineEnvironment.persist(GeneralConfig.EnvRootPath, "commonPipelineEnvironment")
+				reports.persist(stepConfig, GeneralConfig.GCPJsonKeyFilePath, GeneralConfig.GCSBucketId, GeneralConfig.GCSFolderPath, GeneralConfig.GCSSubFolder)
 				influx.persist(GeneralConfig.EnvRootPath, "influx")
 				config.RemoveVaultSecretFiles()
 				stepTelemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
@@ -195,7 +270,7 @@ For more information, see [official documentation of SAP Cloud Transport Managem
 			log.DeferExitHandler(handler)
 			defer handler()
 			telemetryClient.Initialize(STEP_NAME)
-			tmsUpload(stepConfig, &stepTelemetryData, &influx)
+			tmsUpload(stepConfig, &stepTelemetryData, &commonPipelineEnvironment, &influx)
 			stepTelemetryData.ErrorCode = "0"
 			log.Entry().Info("SUCCESS")
 		},
@@ -216,6 +291,9 @@ func addTmsUploadFlags(cmd *cobra.Command, stepConfig *tmsUploadOptions) {
 
 	cmd.Flags().StringVar(&stepConfig.Proxy, "proxy", os.Getenv("PIPER_proxy"), "Proxy URL which should be used for communication with the SAP Cloud Transport Management service backend.")
 	cmd.Flags().StringSliceVar(&stepConfig.StashContent, "stashContent", []string{`buildResult`}, "If specific stashes should be considered during Jenkins execution, their names need to be passed as a list via this parameter, e.g. stashContent: [\"deployDescriptor\", \"buildResult\"]. By default, the build result is considered.")
+	cmd.Flags().StringSliceVar(&stepConfig.ImportNodes, "importNodes", []string{}, "Names of the transport nodes in the order of the transport route into which the uploaded transport request is imported, e.g. importNodes: [\"QA\", \"PROD\"]. For each node the step waits until the transport request arrives in the import queue, triggers the import and waits until the import is finished before it continues with the next node. If not set, the step finishes after the upload.")
+	cmd.Flags().IntVar(&stepConfig.ImportPollInterval, "importPollInterval", 30, "Interval in seconds in which the import queue and the import status are polled.")
+	cmd.Flags().IntVar(&stepConfig.ImportTimeout, "importTimeout", 3600, "Maximum time in seconds to wait for the transport request to arrive in the import queue of a node and for the import into a node to finish.")
 
 	cmd.MarkFlagRequired("serviceKey")
 	cmd.MarkFlagRequired("nodeName")
@@ -343,10 +421,52 @@ func tmsUploadMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     []string{`buildResult`},
 					},
+					{
+						Name:        "importNodes",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STEPS", "STAGES"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+					{
+						Name:        "importPollInterval",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STEPS", "STAGES"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     30,
+					},
+					{
+						Name:        "importTimeout",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STEPS", "STAGES"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     3600,
+					},
 				},
 			},
 			Outputs: config.StepOutputs{
 				Resources: []config.StepResources{
+					{
+						Name: "commonPipelineEnvironment",
+						Type: "piperEnvironment",
+						Parameters: []map[string]interface{}{
+							{"name": "custom/tmsTransportRequestId"},
+							{"name": "custom/tmsNodeResults", "type": "[]map[string]interface{}"},
+						},
+					},
+					{
+						Name: "reports",
+						Type: "reports",
+						Parameters: []map[string]interface{}{
+							{"filePattern": "**/tmsPromotionReport.html", "type": "tms"},
+						},
+					},
 					{
 						Name: "influx",
 						Type: "influx",
@@ -9,6 +9,7 @@ import (
 	"os"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/SAP/jenkins-library/pkg/mock"
 	"github.com/SAP/jenkins-library/pkg/tms"
@@ -71,6 +72,9 @@ type communicationInstanceMock struct {
 	isErrorOnUploadFile                   bool
 	isErrorOnUploadFileToNode             bool
 	isErrorOnExportFileToNode             bool
+	transportRequestsResponse             []tms.TransportRequest
+	actionStatus                          string
+	importedNodeIds                       []int64
 }
 
 func (cim *communicationInstanceMock) GetNodes() ([]tms.Node, error) {
@@ -148,6 +152,19 @@ func (cim *communicationInstanceMock) UploadFileToNode(fileInfo tms.FileInfo, no
 	}
 }
 
+func (cim *communicationInstanceMock) GetTransportRequests(nodeId int64, status string) ([]tms.TransportRequest, error) {
+	return cim.transportRequestsResponse, nil
+}
+
+func (cim *communicationInstanceMock) ImportTransportRequests(nodeId int64, transportRequestIds []int64, namedUser string) (tms.ImportResponseEntity, error) {
+	cim.importedNodeIds = append(cim.importedNodeIds, nodeId)
+	return tms.ImportResponseEntity{ActionId: nodeId * 10}, nil
+}
+
+func (cim *communicationInstanceMock) GetAction(actionId int64) (tms.Action, error) {
+	return tms.Action{Id: actionId, Status: cim.actionStatus}, nil
+}
+
 func mapToJson(m map[string]interface{}) (string, error) {
 	b, err := json.Marshal(m)
 	if err != nil {
@@ -180,7 +197,7 @@ func TestRunTmsUpload(t *testing.T) {
 		config := tmsUploadOptions{MtaPath: MTA_PATH_LOCAL, CustomDescription: CUSTOM_DESCRIPTION, NamedUser: NAMED_USER, NodeName: NODE_NAME, MtaVersion: MTA_VERSION, NodeExtDescriptorMapping: nodeNameExtDescriptorMapping}
 
 		// test
-		err := runTmsUpload(config, &communicationInstance, utils)
+		err := runTmsUpload(config, &communicationInstance, utils, &tmsUploadCommonPipelineEnvironment{}, nil)
 
 		// assert
 		assert.NoError(t, err)
@@ -199,7 +216,7 @@ func TestRunTmsUpload(t *testing.T) {
 		config := tmsUploadOptions{MtaPath: MTA_PATH_LOCAL, CustomDescription: CUSTOM_DESCRIPTION, NamedUser: NAMED_USER, NodeName: NODE_NAME, MtaVersion: MTA_VERSION}
 
 		// test
-		err := runTmsUpload(config, &communicationInstance, utils)
+		err := runTmsUpload(config, &communicationInstance, utils, &tmsUploadCommonPipelineEnvironment{}, nil)
 
 		// assert
 		assert.NoError(t, err)
@@ -227,7 +244,7 @@ func TestRunTmsUpload(t *testing.T) {
 		config := tmsUploadOptions{MtaPath: MTA_PATH_LOCAL, CustomDescription: CUSTOM_DESCRIPTION, NamedUser: NAMED_USER, NodeName: NODE_NAME, MtaVersion: MTA_VERSION, NodeExtDescriptorMapping: nodeNameExtDescriptorMapping}
 
 		// test
-		err := runTmsUpload(config, &communicationInstance, utils)
+		err := runTmsUpload(config, &communicationInstance, utils, &tmsUploadCommonPipelineEnvironment{}, nil)
 
 		// assert
 		assert.NoError(t, err)
@@ -244,7 +261,7 @@ func TestRunTmsUpload(t *testing.T) {
 		config := tmsUploadOptions{MtaPath: MTA_PATH_LOCAL, CustomDescription: CUSTOM_DESCRIPTION, NamedUser: NAMED_USER, NodeName: NODE_NAME, MtaVersion: MTA_VERSION, NodeExtDescriptorMapping: nodeNameExtDescriptorMapping}
 
 		// test
-		err := runTmsUpload(config, &communicationInstance, utils)
+		err := runTmsUpload(config, &communicationInstance, utils, &tmsUploadCommonPipelineEnvironment{}, nil)
 
 		// assert
 		assert.EqualError(t, err, fmt.Sprintf("mta file %s not found", MTA_PATH_LOCAL))
@@ -262,7 +279,7 @@ func TestRunTmsUpload(t *testing.T) {
 		config := tmsUploadOptions{MtaPath: MTA_PATH_LOCAL, CustomDescription: CUSTOM_DESCRIPTION, NamedUser: NAMED_USER, NodeName: NODE_NAME, MtaVersion: MTA_VERSION, NodeExtDescriptorMapping: nodeNameExtDescriptorMapping}
 
 		// test
-		err := runTmsUpload(config, &communicationInstance, utils)
+		err := runTmsUpload(config, &communicationInstance, utils, &tmsUploadCommonPipelineEnvironment{}, nil)
 
 		// assert
 		assert.EqualError(t, err, "failed to get nodes: Something went wrong on getting nodes")
@@ -281,7 +298,7 @@ func TestRunTmsUpload(t *testing.T) {
 		config := tmsUploadOptions{MtaPath: MTA_PATH_LOCAL, CustomDescription: CUSTOM_DESCRIPTION, NamedUser: NAMED_USER, NodeName: NODE_NAME, MtaVersion: MTA_VERSION, NodeExtDescriptorMapping: nodeNameExtDescriptorMapping}
 
 		// test
-		err := runTmsUpload(config, &communicationInstance, utils)
+		err := runTmsUpload(config, &communicationInstance, utils, &tmsUploadCommonPipelineEnvironment{}, nil)
 
 		// assert
 		assert.EqualError(t, err, "failed to get mta.yaml as map: could not read 'mta.yaml'")
@@ -303,7 +320,7 @@ func TestRunTmsUpload(t *testing.T) {
 		config := tmsUploadOptions{MtaPath: MTA_PATH_LOCAL, CustomDescription: CUSTOM_DESCRIPTION, NamedUser: NAMED_USER, NodeName: NODE_NAME, MtaVersion: MTA_VERSION, NodeExtDescriptorMapping: nodeNameExtDescriptorMapping}
 
 		// test
-		err := runTmsUpload(config, &communicationInstance, utils)
+		err := runTmsUpload(config, &communicationInstance, utils, &tmsUploadCommonPipelineEnvironment{}, nil)
 
 		// assert
 		assert.EqualError(t, err, "failed to get mta.yaml as map: error unmarshaling JSON: while decoding JSON: json: cannot unmarshal string into Go value of type map[string]interface {}")
@@ -325,7 +342,7 @@ func TestRunTmsUpload(t *testing.T) {
 		config := tmsUploadOptions{MtaPath: MTA_PATH_LOCAL, CustomDescription: CUSTOM_DESCRIPTION, NamedUser: NAMED_USER, NodeName: NODE_NAME, MtaVersion: MTA_VERSION, NodeExtDescriptorMapping: nodeNameExtDescriptorMapping}
 
 		// test
-		err := runTmsUpload(config, &communicationInstance, utils)
+		err := runTmsUpload(config, &communicationInstance, utils, &tmsUploadCommonPipelineEnvironment{}, nil)
 
 		// assert
 		var expectedErrorMessage string
@@ -363,7 +380,7 @@ func TestRunTmsUpload(t *testing.T) {
 		config := tmsUploadOptions{MtaPath: MTA_PATH_LOCAL, CustomDescription: CUSTOM_DESCRIPTION, NamedUser: NAMED_USER, NodeName: NODE_NAME, MtaVersion: WRONG_MTA_VERSION, NodeExtDescriptorMapping: nodeNameExtDescriptorMapping}
 
 		// test
-		err := runTmsUpload(config, &communicationInstance, utils)
+		err := runTmsUpload(config, &communicationInstance, utils, &tmsUploadCommonPipelineEnvironment{}, nil)
 
 		// assert
 		var expectedErrorMessage string
@@ -394,7 +411,7 @@ func TestRunTmsUpload(t *testing.T) {
 		config := tmsUploadOptions{MtaPath: MTA_PATH_LOCAL, CustomDescription: CUSTOM_DESCRIPTION, NamedUser: NAMED_USER, NodeName: NODE_NAME, MtaVersion: MTA_VERSION, NodeExtDescriptorMapping: nodeNameExtDescriptorMapping}
 
 		// test
-		err := runTmsUpload(config, &communicationInstance, utils)
+		err := runTmsUpload(config, &communicationInstance, utils, &tmsUploadCommonPipelineEnvironment{}, nil)
 
 		// assert
 		assert.EqualError(t, err, "failed to get MTA extension descriptor: Something went wrong on getting MTA extension descriptor")
@@ -421,7 +438,7 @@ func TestRunTmsUpload(t *testing.T) {
 		config := tmsUploadOptions{MtaPath: MTA_PATH_LOCAL, CustomDescription: CUSTOM_DESCRIPTION, NamedUser: NAMED_USER, NodeName: NODE_NAME, MtaVersion: MTA_VERSION, NodeExtDescriptorMapping: nodeNameExtDescriptorMapping}
 
 		// test
-		err := runTmsUpload(config, &communicationInstance, utils)
+		err := runTmsUpload(config, &communicationInstance, utils, &tmsUploadCommonPipelineEnvironment{}, nil)
 
 		// assert
 		assert.EqualError(t, err, "failed to update MTA extension descriptor: Something went wrong on updating MTA extension descriptor")
@@ -447,7 +464,7 @@ func TestRunTmsUpload(t *testing.T) {
 		config := tmsUploadOptions{MtaPath: MTA_PATH_LOCAL, CustomDescription: CUSTOM_DESCRIPTION, NamedUser: NAMED_USER, NodeName: NODE_NAME, MtaVersion: MTA_VERSION, NodeExtDescriptorMapping: nodeNameExtDescriptorMapping}
 
 		// test
-		err := runTmsUpload(config, &communicationInstance, utils)
+		err := runTmsUpload(config, &communicationInstance, utils, &tmsUploadCommonPipelineEnvironment{}, nil)
 
 		// assert
 		assert.EqualError(t, err, "failed to upload MTA extension descriptor to node: Something went wrong on uploading MTA extension descriptor to node")
@@ -473,7 +490,7 @@ func TestRunTmsUpload(t *testing.T) {
 		config := tmsUploadOptions{MtaPath: MTA_PATH_LOCAL, CustomDescription: CUSTOM_DESCRIPTION, NamedUser: NAMED_USER, NodeName: NODE_NAME, MtaVersion: MTA_VERSION, NodeExtDescriptorMapping: nodeNameExtDescriptorMapping}
 
 		// test
-		err := runTmsUpload(config, &communicationInstance, utils)
+		err := runTmsUpload(config, &communicationInstance, utils, &tmsUploadCommonPipelineEnvironment{}, nil)
 
 		// assert
 		assert.EqualError(t, err, "failed to upload file: Something went wrong on uploading file")
@@ -500,13 +517,71 @@ func TestRunTmsUpload(t *testing.T) {
 		config := tmsUploadOptions{MtaPath: MTA_PATH_LOCAL, CustomDescription: CUSTOM_DESCRIPTION, NamedUser: NAMED_USER, NodeName: NODE_NAME, MtaVersion: MTA_VERSION, NodeExtDescriptorMapping: nodeNameExtDescriptorMapping}
 
 		// test
-		err := runTmsUpload(config, &communicationInstance, utils)
+		err := runTmsUpload(config, &communicationInstance, utils, &tmsUploadCommonPipelineEnvironment{}, nil)
 
 		// assert
 		assert.EqualError(t, err, "failed to upload file to node: Something went wrong on uploading file to node")
 	})
 }
 
+func TestRunTmsUploadWithPromotion(t *testing.T) {
+	t.Parallel()
+
+	t.Run("happy path: transport request is imported into all nodes of the route", func(t *testing.T) {
+		t.Parallel()
+
+		// init
+		nodes := []tms.Node{{Id: NODE_ID, Name: NODE_NAME}, {Id: 1, Name: "QA"}, {Id: 2, Name: "PROD"}}
+		fileInfo := tms.FileInfo{Id: FILE_ID, Name: MTA_NAME}
+		uploadResponse := tms.NodeUploadResponseEntity{TransportRequestId: 42}
+		communicationInstance := communicationInstanceMock{getNodesResponse: nodes, uploadFileResponse: fileInfo, uploadFileToNodeResponse: uploadResponse, transportRequestsResponse: []tms.TransportRequest{{Id: 42}}, actionStatus: tms.ActionStatusSucceeded}
+
+		utils := newTmsTestsUtils()
+		utils.AddFile(MTA_PATH_LOCAL, []byte("dummy content"))
+
+		config := tmsUploadOptions{MtaPath: MTA_PATH_LOCAL, CustomDescription: CUSTOM_DESCRIPTION, NamedUser: NAMED_USER, NodeName: NODE_NAME, MtaVersion: MTA_VERSION, ImportNodes: []string{"QA", "PROD"}, ImportPollInterval: 1, ImportTimeout: 10}
+		cpe := tmsUploadCommonPipelineEnvironment{}
+
+		// test
+		err := runTmsUpload(config, &communicationInstance, utils, &cpe, func(time.Duration) {})
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{1, 2}, communicationInstance.importedNodeIds)
+		assert.Equal(t, "42", cpe.custom.tmsTransportRequestID)
+		if assert.Len(t, cpe.custom.tmsNodeResults, 2) {
+			assert.Equal(t, "PROD", cpe.custom.tmsNodeResults[1]["nodeName"])
+			assert.Equal(t, tms.ActionStatusSucceeded, cpe.custom.tmsNodeResults[1]["status"])
+		}
+		assert.True(t, utils.HasWrittenFile("tmsPromotionReport.html"))
+		assert.True(t, utils.HasWrittenFile(".pipeline/stepReports/tmsUpload_promotion.json"))
+	})
+
+	t.Run("error path: import fails in first node", func(t *testing.T) {
+		t.Parallel()
+
+		// init
+		nodes := []tms.Node{{Id: 1, Name: "QA"}, {Id: 2, Name: "PROD"}}
+		fileInfo := tms.FileInfo{Id: FILE_ID, Name: MTA_NAME}
+		uploadResponse := tms.NodeUploadResponseEntity{TransportRequestId: 42}
+		communicationInstance := communicationInstanceMock{getNodesResponse: nodes, uploadFileResponse: fileInfo, uploadFileToNodeResponse: uploadResponse, transportRequestsResponse: []tms.TransportRequest{{Id: 42}}, actionStatus: tms.ActionStatusError}
+
+		utils := newTmsTestsUtils()
+		utils.AddFile(MTA_PATH_LOCAL, []byte("dummy content"))
+
+		config := tmsUploadOptions{MtaPath: MTA_PATH_LOCAL, CustomDescription: CUSTOM_DESCRIPTION, NamedUser: NAMED_USER, NodeName: NODE_NAME, MtaVersion: MTA_VERSION, ImportNodes: []string{"QA", "PROD"}, ImportPollInterval: 1, ImportTimeout: 10}
+		cpe := tmsUploadCommonPipelineEnvironment{}
+
+		// test
+		err := runTmsUpload(config, &communicationInstance, utils, &cpe, func(time.Duration) {})
+
+		// assert
+		assert.EqualError(t, err, "import of transport request 42 into node QA failed with status ERROR")
+		assert.Equal(t, []int64{1}, communicationInstance.importedNodeIds)
+		assert.Equal(t, tms.NodeStatusSkipped, cpe.custom.tmsNodeResults[1]["status"])
+	})
+}
+
 func Test_convertUploadOptions(t *testing.T) {
 	t.Parallel()
 	mockServiceKey := `no real serviceKey json necessary for these tests`
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/SAP/jenkins-library/pkg/command"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/SAP/jenkins-library/pkg/transportrequest/cmclient"
+	"github.com/SAP/jenkins-library/pkg/transportrequest/cts"
+)
+
+type transportRequestLifecycleCTSUtils interface {
+	cts.Exec
+}
+
+type transportRequestLifecycleCTSUtilsBundle struct {
+	*command.Command
+}
+
+func newTransportRequestLifecycleCTSUtils() transportRequestLifecycleCTSUtils {
+	utils := transportRequestLifecycleCTSUtilsBundle{
+		Command: &command.Command{},
+	}
+	// Reroute command output to logging framework
+	utils.Stdout(log.Writer())
+	utils.Stderr(log.Writer())
+	return &utils
+}
+
+func transportRequestLifecycleCTS(config transportRequestLifecycleCTSOptions,
+	telemetryData *telemetry.CustomData,
+	commonPipelineEnvironment *transportRequestLifecycleCTSCommonPipelineEnvironment) {
+	utils := newTransportRequestLifecycleCTSUtils()
+
+	err := runTransportRequestLifecycleCTS(&config, utils, commonPipelineEnvironment)
+	if err != nil {
+		log.Entry().WithError(err).Fatal("step execution failed")
+	}
+}
+
+func runTransportRequestLifecycleCTS(config *transportRequestLifecycleCTSOptions,
+	utils transportRequestLifecycleCTSUtils,
+	commonPipelineEnvironment *transportRequestLifecycleCTSCommonPipelineEnvironment) error {
+
+	connection := cts.Connection{
+		Endpoint: config.Endpoint,
+		User:     config.Username,
+		Password: config.Password,
+	}
+
+	switch config.Action {
+	case "create":
+		create := cts.CreateAction{
+			Connection:     connection,
+			TransportType:  config.TransportType,
+			TargetSystemID: config.TargetSystem,
+			Description:    config.Description,
+			CMOpts:         config.CmClientOpts,
+		}
+		transportRequestID, err := create.Perform(utils)
+		if err != nil {
+			return err
+		}
+		commonPipelineEnvironment.custom.transportRequestID = transportRequestID
+		commonPipelineEnvironment.custom.transportRequestStatus = cmclient.StatusModifiable
+	case "release":
+		release := cts.ReleaseAction{
+			Connection:         connection,
+			TransportRequestID: config.TransportRequestID,
+			CMOpts:             config.CmClientOpts,
+		}
+		if err := release.Perform(utils); err != nil {
+			return err
+		}
+		commonPipelineEnvironment.custom.transportRequestID = config.TransportRequestID
+		commonPipelineEnvironment.custom.transportRequestStatus = cmclient.StatusReleased
+	case "status":
+		status := cts.StatusAction{
+			Connection:         connection,
+			TransportRequestID: config.TransportRequestID,
+			CMOpts:             config.CmClientOpts,
+		}
+		transportRequestStatus, err := status.Perform(utils)
+		if err != nil {
+			return err
+		}
+		commonPipelineEnvironment.custom.transportRequestID = config.TransportRequestID
+		commonPipelineEnvironment.custom.transportRequestStatus = transportRequestStatus
+		return checkTransportRequestStatus(config.TransportRequestID, transportRequestStatus, config.ExpectedStatus)
+	default:
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return fmt.Errorf("unsupported action '%s'", config.Action)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+//go:build unit
+// +build unit
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunTransportRequestLifecycleCTS(t *testing.T) {
+	t.Parallel()
+
+	newConfig := func(action string) transportRequestLifecycleCTSOptions {
+		return transportRequestLifecycleCTSOptions{
+			Action:             action,
+			Endpoint:           "https://example.org:8000",
+			Username:           "me",
+			Password:           "******",
+			TransportType:      "W",
+			TargetSystem:       "QAS",
+			Description:        "Created by Piper",
+			TransportRequestID: "DEVK900123",
+		}
+	}
+
+	t.Run("create", func(t *testing.T) {
+		t.Parallel()
+		utils := newTransportRequestLifecycleTestUtils()
+		utils.StdoutReturn = map[string]string{"^cmclient.*-t CTS create-transport -tt W -ts QAS -d Created by Piper$": "DEVK900456"}
+		config := newConfig("create")
+		cpe := transportRequestLifecycleCTSCommonPipelineEnvironment{}
+
+		err := runTransportRequestLifecycleCTS(&config, utils, &cpe)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, "DEVK900456", cpe.custom.transportRequestID)
+			assert.Equal(t, "modifiable", cpe.custom.transportRequestStatus)
+		}
+	})
+
+	t.Run("release", func(t *testing.T) {
+		t.Parallel()
+		utils := newTransportRequestLifecycleTestUtils()
+		config := newConfig("release")
+		cpe := transportRequestLifecycleCTSCommonPipelineEnvironment{}
+
+		err := runTransportRequestLifecycleCTS(&config, utils, &cpe)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, []string{"export-transport", "-tID", "DEVK900123"}, utils.Calls[0].Params[8:])
+			assert.Equal(t, "released", cpe.custom.transportRequestStatus)
+		}
+	})
+
+	t.Run("status", func(t *testing.T) {
+		t.Parallel()
+		utils := newTransportRequestLifecycleTestUtils()
+		utils.StdoutReturn = map[string]string{"^cmclient.*get-transport-modifiable -tID DEVK900123$": "false"}
+		config := newConfig("status")
+		config.ExpectedStatus = "released"
+		cpe := transportRequestLifecycleCTSCommonPipelineEnvironment{}
+
+		err := runTransportRequestLifecycleCTS(&config, utils, &cpe)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, "DEVK900123", cpe.custom.transportRequestID)
+			assert.Equal(t, "released", cpe.custom.transportRequestStatus)
+		}
+	})
+
+	t.Run("create without target system", func(t *testing.T) {
+		t.Parallel()
+		utils := newTransportRequestLifecycleTestUtils()
+		config := newConfig("create")
+		config.TargetSystem = ""
+		cpe := transportRequestLifecycleCTSCommonPipelineEnvironment{}
+
+		err := runTransportRequestLifecycleCTS(&config, utils, &cpe)
+
+		assert.EqualError(t, err, "cannot create transport request: the following parameters are not available [TargetSystemID]")
+	})
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/SAP/jenkins-library/pkg/command"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/SAP/jenkins-library/pkg/transportrequest/cmclient"
+	"github.com/SAP/jenkins-library/pkg/transportrequest/rfc"
+)
+
+type transportRequestLifecycleRFCUtils interface {
+	rfc.Exec
+}
+
+type transportRequestLifecycleRFCUtilsBundle struct {
+	*command.Command
+}
+
+func newTransportRequestLifecycleRFCUtils() transportRequestLifecycleRFCUtils {
+	utils := transportRequestLifecycleRFCUtilsBundle{
+		Command: &command.Command{},
+	}
+	// Reroute command output to logging framework
+	utils.Stdout(log.Writer())
+	utils.Stderr(log.Writer())
+	return &utils
+}
+
+func transportRequestLifecycleRFC(config transportRequestLifecycleRFCOptions,
+	telemetryData *telemetry.CustomData,
+	commonPipelineEnvironment *transportRequestLifecycleRFCCommonPipelineEnvironment) {
+	utils := newTransportRequestLifecycleRFCUtils()
+
+	err := runTransportRequestLifecycleRFC(&config, utils, commonPipelineEnvironment)
+	if err != nil {
+		log.Entry().WithError(err).Fatal("step execution failed")
+	}
+}
+
+func runTransportRequestLifecycleRFC(config *transportRequestLifecycleRFCOptions,
+	utils transportRequestLifecycleRFCUtils,
+	commonPipelineEnvironment *transportRequestLifecycleRFCCommonPipelineEnvironment) error {
+
+	connection := rfc.Connection{
+		Endpoint: config.Endpoint,
+		Client:   config.Client,
+		Instance: config.Instance,
+		User:     config.Username,
+		Password: config.Password,
+	}
+
+	switch config.Action {
+	case "create":
+		create := rfc.CreateAction{
+			Connection:  connection,
+			Description: config.Description,
+			Verbose:     config.Verbose,
+		}
+		transportRequestID, err := create.Perform(utils)
+		if err != nil {
+			return err
+		}
+		commonPipelineEnvironment.custom.transportRequestID = transportRequestID
+		commonPipelineEnvironment.custom.transportRequestStatus = cmclient.StatusModifiable
+	case "release":
+		release := rfc.ReleaseAction{
+			Connection:         connection,
+			TransportRequestID: config.TransportRequestID,
+			Verbose:            config.Verbose,
+		}
+		if err := release.Perform(utils); err != nil {
+			return err
+		}
+		commonPipelineEnvironment.custom.transportRequestID = config.TransportRequestID
+		commonPipelineEnvironment.custom.transportRequestStatus = cmclient.StatusReleased
+	default:
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return fmt.Errorf("unsupported action '%s'", config.Action)
+	}
+	return nil
+}
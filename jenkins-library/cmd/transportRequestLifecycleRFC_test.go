@@ -0,0 +1,72 @@
+//go:build unit
+// +build unit
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunTransportRequestLifecycleRFC(t *testing.T) {
+	t.Parallel()
+
+	newConfig := func(action string) transportRequestLifecycleRFCOptions {
+		return transportRequestLifecycleRFCOptions{
+			Action:             action,
+			Endpoint:           "https://example.org/rfc",
+			Instance:           "00",
+			Client:             "001",
+			Username:           "me",
+			Password:           "******",
+			Description:        "Created by Piper",
+			TransportRequestID: "DEVK900123",
+		}
+	}
+
+	t.Run("create", func(t *testing.T) {
+		t.Parallel()
+		utils := newTransportRequestLifecycleTestUtils()
+		utils.StdoutReturn = map[string]string{"cts createTransportRequest": `{"REQUESTID": "DEVK900456"}`}
+		config := newConfig("create")
+		cpe := transportRequestLifecycleRFCCommonPipelineEnvironment{}
+
+		err := runTransportRequestLifecycleRFC(&config, utils, &cpe)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, "DEVK900456", cpe.custom.transportRequestID)
+			assert.Equal(t, "modifiable", cpe.custom.transportRequestStatus)
+			assert.Contains(t, utils.Env, "TRANSPORT_DESCRIPTION=Created by Piper")
+		}
+	})
+
+	t.Run("release", func(t *testing.T) {
+		t.Parallel()
+		utils := newTransportRequestLifecycleTestUtils()
+		config := newConfig("release")
+		cpe := transportRequestLifecycleRFCCommonPipelineEnvironment{}
+
+		err := runTransportRequestLifecycleRFC(&config, utils, &cpe)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, []mock.ExecCall{{Exec: "cts", Params: []string{"releaseTransport:DEVK900123"}}}, utils.Calls)
+			assert.Equal(t, "DEVK900123", cpe.custom.transportRequestID)
+			assert.Equal(t, "released", cpe.custom.transportRequestStatus)
+		}
+	})
+
+	t.Run("release without transport request", func(t *testing.T) {
+		t.Parallel()
+		utils := newTransportRequestLifecycleTestUtils()
+		config := newConfig("release")
+		config.TransportRequestID = ""
+		cpe := transportRequestLifecycleRFCCommonPipelineEnvironment{}
+
+		err := runTransportRequestLifecycleRFC(&config, utils, &cpe)
+
+		assert.EqualError(t, err, "cannot release transport request: the following parameters are not available [TransportRequestID]")
+		assert.Empty(t, utils.Calls)
+	})
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/SAP/jenkins-library/pkg/command"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/SAP/jenkins-library/pkg/transportrequest/cmclient"
+	"github.com/SAP/jenkins-library/pkg/transportrequest/solman"
+	"github.com/pkg/errors"
+)
+
+type transportRequestLifecycleSOLMANUtils interface {
+	solman.Exec
+}
+
+type transportRequestLifecycleSOLMANUtilsBundle struct {
+	*command.Command
+}
+
+func newTransportRequestLifecycleSOLMANUtils() transportRequestLifecycleSOLMANUtils {
+	utils := transportRequestLifecycleSOLMANUtilsBundle{
+		Command: &command.Command{},
+	}
+	// Reroute command output to logging framework
+	utils.Stdout(log.Writer())
+	utils.Stderr(log.Writer())
+	return &utils
+}
+
+func transportRequestLifecycleSOLMAN(config transportRequestLifecycleSOLMANOptions,
+	telemetryData *telemetry.CustomData,
+	commonPipelineEnvironment *transportRequestLifecycleSOLMANCommonPipelineEnvironment) {
+	utils := newTransportRequestLifecycleSOLMANUtils()
+
+	err := runTransportRequestLifecycleSOLMAN(&config, utils, commonPipelineEnvironment)
+	if err != nil {
+		log.Entry().WithError(err).Fatal("step execution failed")
+	}
+}
+
+func runTransportRequestLifecycleSOLMAN(config *transportRequestLifecycleSOLMANOptions,
+	utils transportRequestLifecycleSOLMANUtils,
+	commonPipelineEnvironment *transportRequestLifecycleSOLMANCommonPipelineEnvironment) error {
+
+	connection := solman.Connection{
+		Endpoint: config.Endpoint,
+		User:     config.Username,
+		Password: config.Password,
+	}
+	commonPipelineEnvironment.custom.changeDocumentID = config.ChangeDocumentID
+
+	switch config.Action {
+	case "create":
+		create := solman.CreateAction{
+			Connection:          connection,
+			ChangeDocumentID:    config.ChangeDocumentID,
+			DevelopmentSystemID: config.DevelopmentSystemID,
+			CMOpts:              config.CmClientOpts,
+		}
+		transportRequestID, err := create.Perform(utils)
+		if err != nil {
+			return err
+		}
+		commonPipelineEnvironment.custom.transportRequestID = transportRequestID
+		commonPipelineEnvironment.custom.transportRequestStatus = cmclient.StatusModifiable
+	case "release":
+		release := solman.ReleaseAction{
+			Connection:         connection,
+			ChangeDocumentID:   config.ChangeDocumentID,
+			TransportRequestID: config.TransportRequestID,
+			CMOpts:             config.CmClientOpts,
+		}
+		if err := release.Perform(utils); err != nil {
+			return err
+		}
+		commonPipelineEnvironment.custom.transportRequestID = config.TransportRequestID
+		commonPipelineEnvironment.custom.transportRequestStatus = cmclient.StatusReleased
+	case "status":
+		status := solman.StatusAction{
+			Connection:         connection,
+			ChangeDocumentID:   config.ChangeDocumentID,
+			TransportRequestID: config.TransportRequestID,
+			CMOpts:             config.CmClientOpts,
+		}
+		transportRequestStatus, err := status.Perform(utils)
+		if err != nil {
+			return err
+		}
+		commonPipelineEnvironment.custom.transportRequestID = config.TransportRequestID
+		commonPipelineEnvironment.custom.transportRequestStatus = transportRequestStatus
+		return checkTransportRequestStatus(config.TransportRequestID, transportRequestStatus, config.ExpectedStatus)
+	default:
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return fmt.Errorf("unsupported action '%s'", config.Action)
+	}
+	return nil
+}
+
+// checkTransportRequestStatus fails if an expected status is given and the transport request has a different status
+func checkTransportRequestStatus(transportRequestID, status, expectedStatus string) error {
+	if len(expectedStatus) == 0 || status == expectedStatus {
+		return nil
+	}
+	log.SetErrorCategory(log.ErrorCompliance)
+	return errors.Errorf("transport request '%s' is %s, expected status is %s", transportRequestID, status, expectedStatus)
+}
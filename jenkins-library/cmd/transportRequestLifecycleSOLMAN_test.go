@@ -0,0 +1,107 @@
+//go:build unit
+// +build unit
+
+package cmd
+
+import (
+	"io"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTransportRequestLifecycleTestUtils() *mock.ExecMockRunner {
+	utils := &mock.ExecMockRunner{}
+	utils.Stdout(io.Discard)
+	return utils
+}
+
+func TestRunTransportRequestLifecycleSOLMAN(t *testing.T) {
+	t.Parallel()
+
+	newConfig := func(action string) transportRequestLifecycleSOLMANOptions {
+		return transportRequestLifecycleSOLMANOptions{
+			Action:              action,
+			Endpoint:            "https://example.org/solman",
+			Username:            "me",
+			Password:            "******",
+			ChangeDocumentID:    "123",
+			DevelopmentSystemID: "DEV~ABAP/100",
+			TransportRequestID:  "DEVK900123",
+		}
+	}
+
+	t.Run("create", func(t *testing.T) {
+		t.Parallel()
+		utils := newTransportRequestLifecycleTestUtils()
+		utils.StdoutReturn = map[string]string{"^cmclient.*create-transport -cID 123 -dID DEV~ABAP/100$": "DEVK900456\n"}
+		config := newConfig("create")
+		cpe := transportRequestLifecycleSOLMANCommonPipelineEnvironment{}
+
+		err := runTransportRequestLifecycleSOLMAN(&config, utils, &cpe)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, "DEVK900456", cpe.custom.transportRequestID)
+			assert.Equal(t, "123", cpe.custom.changeDocumentID)
+			assert.Equal(t, "modifiable", cpe.custom.transportRequestStatus)
+		}
+	})
+
+	t.Run("release", func(t *testing.T) {
+		t.Parallel()
+		utils := newTransportRequestLifecycleTestUtils()
+		config := newConfig("release")
+		cpe := transportRequestLifecycleSOLMANCommonPipelineEnvironment{}
+
+		err := runTransportRequestLifecycleSOLMAN(&config, utils, &cpe)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, []string{"--endpoint", "https://example.org/solman", "--user", "me", "--password", "******", "release-transport", "-cID", "123", "-tID", "DEVK900123"}, utils.Calls[0].Params)
+			assert.Equal(t, "DEVK900123", cpe.custom.transportRequestID)
+			assert.Equal(t, "released", cpe.custom.transportRequestStatus)
+		}
+	})
+
+	t.Run("status", func(t *testing.T) {
+		t.Parallel()
+		utils := newTransportRequestLifecycleTestUtils()
+		utils.StdoutReturn = map[string]string{"^cmclient.*get-transport-modifiable.*": "true"}
+		config := newConfig("status")
+		config.ExpectedStatus = "modifiable"
+		cpe := transportRequestLifecycleSOLMANCommonPipelineEnvironment{}
+
+		err := runTransportRequestLifecycleSOLMAN(&config, utils, &cpe)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, "modifiable", cpe.custom.transportRequestStatus)
+		}
+	})
+
+	t.Run("status differs from expected status", func(t *testing.T) {
+		t.Parallel()
+		utils := newTransportRequestLifecycleTestUtils()
+		utils.StdoutReturn = map[string]string{"^cmclient.*get-transport-modifiable.*": "false"}
+		config := newConfig("status")
+		config.ExpectedStatus = "modifiable"
+		cpe := transportRequestLifecycleSOLMANCommonPipelineEnvironment{}
+
+		err := runTransportRequestLifecycleSOLMAN(&config, utils, &cpe)
+
+		assert.EqualError(t, err, "transport request 'DEVK900123' is released, expected status is modifiable")
+		assert.Equal(t, "released", cpe.custom.transportRequestStatus)
+	})
+
+	t.Run("create fails", func(t *testing.T) {
+		t.Parallel()
+		utils := newTransportRequestLifecycleTestUtils()
+		utils.ExitCode = 1
+		config := newConfig("create")
+		cpe := transportRequestLifecycleSOLMANCommonPipelineEnvironment{}
+
+		err := runTransportRequestLifecycleSOLMAN(&config, utils, &cpe)
+
+		assert.EqualError(t, err, "cannot create transport request: create transport request command returned with exit code '1'")
+		assert.Empty(t, cpe.custom.transportRequestID)
+	})
+}
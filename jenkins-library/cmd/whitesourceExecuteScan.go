@@ -91,6 +91,17 @@ func validationBuildDescriptorFile(buildTool, buildDescriptorFile string) error
 		return nil
 	}
 	switch buildTool {
+	case "cargo":
+		if !strings.HasSuffix(buildDescriptorFile, "Cargo.toml") {
+			return errors.New("buildDescriptorFile must be \"Cargo.toml\"")
+		}
+	case "dotnet":
+		if !strings.HasSuffix(buildDescriptorFile, "Directory.Build.props") &&
+			filepath.Ext(buildDescriptorFile) != ".csproj" &&
+			filepath.Ext(buildDescriptorFile) != ".fsproj" &&
+			filepath.Ext(buildDescriptorFile) != ".vbproj" {
+			return errors.New("buildDescriptorFile must be a project file or \"Directory.Build.props\"")
+		}
 	case "dub":
 		if filepath.Ext(buildDescriptorFile) != ".json" {
 			return errors.New("extension of buildDescriptorFile must be in '*.json'")
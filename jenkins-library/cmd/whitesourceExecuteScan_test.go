@@ -883,6 +883,20 @@ func TestPersistScannedProjects(t *testing.T) {
 
 func TestBuildToolFiles(t *testing.T) {
 	t.Parallel()
+	t.Run("buildTool = cargo", func(t *testing.T) {
+		err := validationBuildDescriptorFile("cargo", "/home/Cargo.lock")
+		assert.ErrorContains(t, err, "buildDescriptorFile must be \"Cargo.toml\"")
+		err = validationBuildDescriptorFile("cargo", "/home/Cargo.toml")
+		assert.NoError(t, err)
+	})
+	t.Run("buildTool = dotnet", func(t *testing.T) {
+		err := validationBuildDescriptorFile("dotnet", "/home/App.sln")
+		assert.ErrorContains(t, err, "buildDescriptorFile must be a project file or \"Directory.Build.props\"")
+		err = validationBuildDescriptorFile("dotnet", "/home/App.csproj")
+		assert.NoError(t, err)
+		err = validationBuildDescriptorFile("dotnet", "/home/Directory.Build.props")
+		assert.NoError(t, err)
+	})
 	t.Run("buildTool = dub", func(t *testing.T) {
 		err := validationBuildDescriptorFile("dub", "/home/mta.yaml")
 		assert.ErrorContains(t, err, "extension of buildDescriptorFile must be in '*.json'")
@@ -0,0 +1,156 @@
+//go:build integration
+// +build integration
+
+// can be executed with
+// go test -v -tags integration -run TestSAPServicesIntegration ./integration/...
+// the steps run against local fakes of the SAP services, no network access or credentials are required
+
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abapmocks "github.com/SAP/jenkins-library/pkg/abaputils/mocks"
+	apimmocks "github.com/SAP/jenkins-library/pkg/apim/mocks"
+	"github.com/SAP/jenkins-library/pkg/command"
+	cpimocks "github.com/SAP/jenkins-library/pkg/cpi/mocks"
+	tmsmocks "github.com/SAP/jenkins-library/pkg/tms/mocks"
+)
+
+// runPiperStep runs a step of the piper binary in the given directory and returns its output
+func runPiperStep(t *testing.T, dir string, env []string, step string, args ...string) (string, error) {
+	t.Helper()
+	cmd := command.Command{}
+	var output bytes.Buffer
+	cmd.Stdout(&output)
+	cmd.Stderr(&output)
+	cmd.SetDir(dir)
+	if len(env) > 0 {
+		cmd.SetEnv(append(os.Environ(), env...))
+	}
+	err := cmd.RunExecutable(getPiperExecutable(), append([]string{step, "--noTelemetry"}, args...)...)
+	return output.String(), err
+}
+
+func writeZip(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+	var content bytes.Buffer
+	writer := zip.NewWriter(&content)
+	for name, data := range files {
+		file, err := writer.Create(name)
+		require.NoError(t, err)
+		_, err = file.Write([]byte(data))
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+	require.NoError(t, os.WriteFile(path, content.Bytes(), 0o644))
+}
+
+func readCommonPipelineEnvironment(t *testing.T, dir, name string) string {
+	t.Helper()
+	content, err := os.ReadFile(filepath.Join(dir, ".pipeline", "commonPipelineEnvironment", name))
+	require.NoError(t, err)
+	return string(content)
+}
+
+func TestSAPServicesIntegrationCloudIntegration(t *testing.T) {
+	server := cpimocks.NewServer()
+	defer server.Close()
+	dir := t.TempDir()
+	writeZip(t, filepath.Join(dir, "flow.zip"), map[string]string{
+		"META-INF/MANIFEST.MF":                      "Bundle-SymbolicName: PiperFlow\nBundle-Version: 1.0.0\n",
+		"src/main/resources/parameters.prop":        "endpoint=https://example.org\n",
+		"src/main/resources/scenarioflows/flow.xml": "<flow/>",
+	})
+	serviceKey := "--apiServiceKey=" + server.ServiceKey()
+
+	output, err := runPiperStep(t, dir, nil, "integrationArtifactUpload", serviceKey,
+		"--integrationFlowId=PiperFlow", "--integrationFlowName=Piper Flow", "--packageId=PiperPackage", "--filePath=flow.zip")
+	require.NoError(t, err, output)
+	_, uploaded := server.Artifact("IntegrationFlow", "PiperFlow")
+	assert.True(t, uploaded)
+
+	output, err = runPiperStep(t, dir, nil, "integrationArtifactDeploy", serviceKey, "--integrationFlowId=PiperFlow")
+	require.NoError(t, err, output)
+
+	server.AddMessageProcessingLog("PiperFlow", "FAILED", "connection refused")
+	// the step fails for a failed message and reports the error of the message
+	_, err = runPiperStep(t, dir, nil, "integrationArtifactGetMplStatus", serviceKey, "--integrationFlowId=PiperFlow")
+	assert.Error(t, err)
+	assert.Equal(t, "FAILED", readCommonPipelineEnvironment(t, dir, "custom/integrationFlowMplStatus"))
+	assert.Equal(t, "connection refused", readCommonPipelineEnvironment(t, dir, "custom/integrationFlowMplError"))
+}
+
+func TestSAPServicesIntegrationAPIManagement(t *testing.T) {
+	server := apimmocks.NewServer()
+	defer server.Close()
+	dir := t.TempDir()
+	writeZip(t, filepath.Join(dir, "proxy.zip"), map[string]string{
+		"PiperProxy/APIProxy/PiperProxy.xml": "<APIProxy><name>PiperProxy</name></APIProxy>",
+	})
+	serviceKey := "--apiServiceKey=" + server.ServiceKey()
+
+	output, err := runPiperStep(t, dir, nil, "apiProxyUpload", serviceKey, "--filePath=proxy.zip")
+	require.NoError(t, err, output)
+	_, uploaded := server.Entity("APIProxies", "PiperProxy")
+	assert.True(t, uploaded)
+
+	output, err = runPiperStep(t, dir, nil, "apiProxyDownload", serviceKey, "--apiProxyName=PiperProxy", "--downloadPath=download")
+	require.NoError(t, err, output)
+	original, _ := os.ReadFile(filepath.Join(dir, "proxy.zip"))
+	downloaded, err := os.ReadFile(filepath.Join(dir, "download", "PiperProxy.zip"))
+	require.NoError(t, err)
+	assert.Equal(t, original, downloaded)
+}
+
+func TestSAPServicesIntegrationTransportManagement(t *testing.T) {
+	server := tmsmocks.NewServer()
+	defer server.Close()
+	server.AddNode("DEV", "QA")
+	server.AddNode("QA")
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "piper.mtar"), []byte("mtar"), 0o644))
+
+	output, err := runPiperStep(t, dir, nil, "tmsUpload", "--serviceKey="+server.ServiceKey(),
+		"--mtaPath=piper.mtar", "--nodeName=DEV", "--mtaVersion=1.0.0", "--importNodes=DEV", "--importNodes=QA", "--importPollInterval=1")
+	require.NoError(t, err, output)
+
+	transportRequestID, err := strconv.ParseInt(readCommonPipelineEnvironment(t, dir, "custom/tmsTransportRequestId"), 10, 64)
+	require.NoError(t, err)
+	for _, node := range []string{"DEV", "QA"} {
+		status, _ := server.TransportRequestStatus(node, transportRequestID)
+		assert.Equal(t, tmsmocks.StatusSucceeded, status, node)
+	}
+}
+
+func TestSAPServicesIntegrationABAPEnvironment(t *testing.T) {
+	server := abapmocks.NewServer()
+	defer server.Close()
+	server.AddSoftwareComponent("/DMO/PIPER")
+	dir := t.TempDir()
+	// the fake system uses a self-signed certificate, which the step trusts via SSL_CERT_FILE
+	certFile := filepath.Join(dir, "server.pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0o644))
+	env := []string{"SSL_CERT_FILE=" + certFile}
+	credentials := []string{"--host=" + server.URL, "--username=" + abapmocks.Username, "--password=" + abapmocks.Password, "--repositoryName=/DMO/PIPER"}
+
+	output, err := runPiperStep(t, dir, env, "abapEnvironmentCloneGitRepo", append(credentials, "--branchName=main")...)
+	require.NoError(t, err, output)
+
+	output, err = runPiperStep(t, dir, env, "abapEnvironmentPullGitRepo", credentials...)
+	require.NoError(t, err, output)
+
+	component, _ := server.SoftwareComponent("/DMO/PIPER")
+	assert.True(t, component.Cloned)
+	assert.Equal(t, "main", component.ActiveBranch)
+	assert.Contains(t, server.Requests, "POST "+abapmocks.APIPath+"/SoftwareComponents/%2FDMO%2FPIPER/SAP__self.pull")
+}
@@ -0,0 +1,113 @@
+package abaputils
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// AddonStatusReport : consolidated state of all software component versions and delivery packages of an addon product version
+type AddonStatusReport struct {
+	AddonProduct              string            `json:"addonProduct"`
+	AddonVersion              string            `json:"addonVersion"`
+	TargetVectorID            string            `json:"targetVectorID,omitempty"`
+	ErrorText                 string            `json:"errorText,omitempty"`
+	SoftwareComponentVersions []ComponentStatus `json:"softwareComponentVersions"`
+}
+
+// ComponentStatus : state of a software component version, its delivery package and the build of the package
+type ComponentStatus struct {
+	Name             string `json:"name"`
+	Version          string `json:"version"`
+	SpLevel          string `json:"spLevel"`
+	PatchLevel       string `json:"patchLevel"`
+	CommitID         string `json:"commitID,omitempty"`
+	PackageName      string `json:"packageName,omitempty"`
+	PackageType      string `json:"packageType,omitempty"`
+	PackageStatus    string `json:"packageStatus,omitempty"`
+	InBuildScope     bool   `json:"inBuildScope"`
+	BuildID          string `json:"buildID,omitempty"`
+	BuildRunState    string `json:"buildRunState,omitempty"`
+	BuildResultState string `json:"buildResultState,omitempty"`
+	ErrorText        string `json:"errorText,omitempty"`
+}
+
+// packageStatusTexts : descriptions of the package status values of AAKaaS
+var packageStatusTexts = map[string]string{
+	"P": "planned",
+	"L": "locked",
+	"R": "released",
+	"C": "creation triggered",
+}
+
+// StatusReport : creates the consolidated report of the addon product version
+func (me *AddonDescriptor) StatusReport() AddonStatusReport {
+	addonVersion := me.AddonVersionYAML
+	if addonVersion == "" {
+		addonVersion = me.AddonVersion
+	}
+	report := AddonStatusReport{
+		AddonProduct:              me.AddonProduct,
+		AddonVersion:              addonVersion,
+		TargetVectorID:            me.TargetVectorID,
+		ErrorText:                 me.ErrorText,
+		SoftwareComponentVersions: []ComponentStatus{},
+	}
+	for _, repo := range me.Repositories {
+		version := repo.VersionYAML
+		if version == "" {
+			version = repo.Version
+		}
+		report.SoftwareComponentVersions = append(report.SoftwareComponentVersions, ComponentStatus{
+			Name:             repo.Name,
+			Version:          version,
+			SpLevel:          repo.SpLevel,
+			PatchLevel:       repo.PatchLevel,
+			CommitID:         repo.CommitID,
+			PackageName:      repo.PackageName,
+			PackageType:      repo.PackageType,
+			PackageStatus:    packageStatusText(repo.Status),
+			InBuildScope:     repo.InBuildScope,
+			BuildID:          repo.BuildID,
+			BuildRunState:    repo.BuildRunState,
+			BuildResultState: repo.BuildResultState,
+			ErrorText:        repo.ErrorText,
+		})
+	}
+	return report
+}
+
+func packageStatusText(status string) string {
+	if text, ok := packageStatusTexts[status]; ok {
+		return text
+	}
+	return status
+}
+
+// ToJSON : dito
+func (r *AddonStatusReport) ToJSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+// ToMarkdown : renders the report as markdown table
+func (r *AddonStatusReport) ToMarkdown() string {
+	var md strings.Builder
+	fmt.Fprintf(&md, "# Addon Product Version %s %s\n\n", r.AddonProduct, r.AddonVersion)
+	if r.TargetVectorID != "" {
+		fmt.Fprintf(&md, "Target Vector: %s\n\n", r.TargetVectorID)
+	}
+	if r.ErrorText != "" {
+		fmt.Fprintf(&md, "Error: %s\n\n", r.ErrorText)
+	}
+	md.WriteString("| Software Component | Version | SP Level | Patch Level | Package | Package Status | Build | Build State | Error |\n")
+	md.WriteString("| --- | --- | --- | --- | --- | --- | --- | --- | --- |\n")
+	for _, c := range r.SoftwareComponentVersions {
+		buildState := c.BuildRunState
+		if c.BuildResultState != "" {
+			buildState += " / " + c.BuildResultState
+		}
+		fmt.Fprintf(&md, "| %s | %s | %s | %s | %s | %s | %s | %s | %s |\n",
+			c.Name, c.Version, c.SpLevel, c.PatchLevel, c.PackageName, c.PackageStatus, c.BuildID, buildState, strings.ReplaceAll(c.ErrorText, "|", "\\|"))
+	}
+	return md.String()
+}
@@ -0,0 +1,91 @@
+//go:build unit
+// +build unit
+
+package abaputils
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddonStatusReport(t *testing.T) {
+	addonDescriptor := AddonDescriptor{
+		AddonProduct:     "/DMO/PRODUCT1",
+		AddonVersionYAML: "1.2.0",
+		AddonVersion:     "0001",
+		TargetVectorID:   "W7Q00207512600000353",
+		Repositories: []Repository{
+			{
+				Name:             "/DMO/REPO_A",
+				VersionYAML:      "1.2.0",
+				Version:          "0001",
+				SpLevel:          "0002",
+				PatchLevel:       "0000",
+				PackageName:      "SAPK-002AAINDMO",
+				PackageType:      "CSP",
+				Status:           "P",
+				InBuildScope:     true,
+				BuildID:          "AKO22FYOFYPOXHOBVKXUTX3A3Q",
+				BuildRunState:    "FINISHED",
+				BuildResultState: "ERRONEOUS",
+				ErrorText:        "Object A|B is inconsistent",
+			},
+			{
+				Name:        "/DMO/REPO_B",
+				Version:     "0003",
+				SpLevel:     "0000",
+				PatchLevel:  "0000",
+				PackageName: "SAPK-003AAINDMO",
+				Status:      "R",
+			},
+		},
+	}
+
+	t.Run("report contains all software component versions", func(t *testing.T) {
+		report := addonDescriptor.StatusReport()
+
+		assert.Equal(t, "/DMO/PRODUCT1", report.AddonProduct)
+		assert.Equal(t, "1.2.0", report.AddonVersion)
+		assert.Equal(t, "W7Q00207512600000353", report.TargetVectorID)
+		if assert.Len(t, report.SoftwareComponentVersions, 2) {
+			assert.Equal(t, ComponentStatus{
+				Name:             "/DMO/REPO_A",
+				Version:          "1.2.0",
+				SpLevel:          "0002",
+				PatchLevel:       "0000",
+				PackageName:      "SAPK-002AAINDMO",
+				PackageType:      "CSP",
+				PackageStatus:    "planned",
+				InBuildScope:     true,
+				BuildID:          "AKO22FYOFYPOXHOBVKXUTX3A3Q",
+				BuildRunState:    "FINISHED",
+				BuildResultState: "ERRONEOUS",
+				ErrorText:        "Object A|B is inconsistent",
+			}, report.SoftwareComponentVersions[0])
+			assert.Equal(t, "0003", report.SoftwareComponentVersions[1].Version)
+			assert.Equal(t, "released", report.SoftwareComponentVersions[1].PackageStatus)
+		}
+	})
+
+	t.Run("report as JSON", func(t *testing.T) {
+		report := addonDescriptor.StatusReport()
+		content, err := report.ToJSON()
+		assert.NoError(t, err)
+
+		var parsed AddonStatusReport
+		assert.NoError(t, json.Unmarshal(content, &parsed))
+		assert.Equal(t, report, parsed)
+	})
+
+	t.Run("report as markdown", func(t *testing.T) {
+		report := addonDescriptor.StatusReport()
+		md := report.ToMarkdown()
+
+		assert.Contains(t, md, "# Addon Product Version /DMO/PRODUCT1 1.2.0")
+		assert.Contains(t, md, "Target Vector: W7Q00207512600000353")
+		assert.Contains(t, md, "| /DMO/REPO_A | 1.2.0 | 0002 | 0000 | SAPK-002AAINDMO | planned | AKO22FYOFYPOXHOBVKXUTX3A3Q | FINISHED / ERRONEOUS | Object A\\|B is inconsistent |")
+		assert.Contains(t, md, "| /DMO/REPO_B | 0003 | 0000 | 0000 | SAPK-003AAINDMO | released |  |  |  |")
+	})
+}
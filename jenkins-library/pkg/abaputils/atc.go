@@ -0,0 +1,221 @@
+package abaputils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+)
+
+const atcToolName = "ABAP Test Cockpit"
+
+// ATCFinding is a finding of an ATC check run
+type ATCFinding struct {
+	// File is the object or include the finding belongs to, usually an ADT URI
+	File     string
+	Line     int
+	Severity string
+	Message  string
+	// Source is the check which reported the finding
+	Source string
+}
+
+// Fingerprint identifies the finding independently of its line, so that it can be recognised in other check runs
+func (f ATCFinding) Fingerprint() string {
+	hash := sha256.Sum256([]byte(strings.Join([]string{strings.ToLower(f.File), f.Source, f.Message}, "\x00")))
+	return hex.EncodeToString(hash[:])
+}
+
+// SarifLevel maps the ATC severity (priority 1 to 3) to the level of a SARIF result
+func (f ATCFinding) SarifLevel() string {
+	switch strings.ToLower(f.Severity) {
+	case "error", "1":
+		return "error"
+	case "warning", "2":
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+type adtFileMapping struct {
+	pattern *regexp.Regexp
+	// fileName creates the file name of the abapGit repository layout from the submatches of the pattern
+	fileName func(match []string) string
+}
+
+// adtFileMappings are evaluated in order, more specific includes have to precede the main objects
+var adtFileMappings = []adtFileMapping{
+	{regexp.MustCompile(`/oo/classes/([^/#?]+)/includes/testclasses`), func(m []string) string { return m[1] + ".clas.testclasses.abap" }},
+	{regexp.MustCompile(`/oo/classes/([^/#?]+)/includes/definitions`), func(m []string) string { return m[1] + ".clas.locals_def.abap" }},
+	{regexp.MustCompile(`/oo/classes/([^/#?]+)/includes/implementations`), func(m []string) string { return m[1] + ".clas.locals_imp.abap" }},
+	{regexp.MustCompile(`/oo/classes/([^/#?]+)/includes/macros`), func(m []string) string { return m[1] + ".clas.macros.abap" }},
+	{regexp.MustCompile(`/oo/classes/([^/#?]+)`), func(m []string) string { return m[1] + ".clas.abap" }},
+	{regexp.MustCompile(`/oo/interfaces/([^/#?]+)`), func(m []string) string { return m[1] + ".intf.abap" }},
+	{regexp.MustCompile(`/functions/groups/([^/#?]+)/fmodules/([^/#?]+)`), func(m []string) string { return m[1] + ".fugr." + m[2] + ".abap" }},
+	{regexp.MustCompile(`/functions/groups/([^/#?]+)/includes/([^/#?]+)`), func(m []string) string { return m[1] + ".fugr." + m[2] + ".abap" }},
+	{regexp.MustCompile(`/functions/groups/([^/#?]+)`), func(m []string) string { return m[1] + ".fugr.sapl" + m[1] + ".abap" }},
+	{regexp.MustCompile(`/programs/(?:programs|includes)/([^/#?]+)`), func(m []string) string { return m[1] + ".prog.abap" }},
+	{regexp.MustCompile(`/ddic/ddl/sources/([^/#?]+)`), func(m []string) string { return m[1] + ".ddls.asddls" }},
+	{regexp.MustCompile(`/acm/dcl/sources/([^/#?]+)`), func(m []string) string { return m[1] + ".dcls.asdcls" }},
+	{regexp.MustCompile(`/bo/behaviordefinitions/([^/#?]+)`), func(m []string) string { return m[1] + ".bdef.asbdef" }},
+	{regexp.MustCompile(`/ddic/tables/([^/#?]+)`), func(m []string) string { return m[1] + ".tabl.xml" }},
+	{regexp.MustCompile(`/ddic/structures/([^/#?]+)`), func(m []string) string { return m[1] + ".tabl.xml" }},
+	{regexp.MustCompile(`/ddic/dataelements/([^/#?]+)`), func(m []string) string { return m[1] + ".dtel.xml" }},
+	{regexp.MustCompile(`/ddic/domains/([^/#?]+)`), func(m []string) string { return m[1] + ".doma.xml" }},
+}
+
+var adtStartLine = regexp.MustCompile(`#.*start=(\d+)`)
+
+// ATCArtifactURI maps the object or include of an ATC finding to the file in the abapGit repository layout below the source directory.
+// Namespaces are written in the abapGit notation, e.g. /ABC/CL_FOO becomes #abc#cl_foo. Unknown files are returned unchanged.
+func ATCArtifactURI(file, sourceDirectory string) string {
+	unescaped, err := url.PathUnescape(file)
+	if err != nil {
+		unescaped = file
+	}
+	for _, mapping := range adtFileMappings {
+		match := mapping.pattern.FindStringSubmatch(strings.ToLower(file))
+		if match == nil {
+			continue
+		}
+		// the names are matched on the escaped file, so that namespaces are not split into path segments
+		for i := range match {
+			name, err := url.PathUnescape(match[i])
+			if err == nil {
+				match[i] = strings.ReplaceAll(name, "/", "#")
+			}
+		}
+		return path.Join(sourceDirectory, mapping.fileName(match))
+	}
+	return strings.TrimPrefix(unescaped, "/")
+}
+
+// ATCStartLine returns the line of the finding, falling back to the start position of the ADT URI
+func ATCStartLine(line, file string) int {
+	if value, err := strconv.Atoi(line); err == nil {
+		return value
+	}
+	if match := adtStartLine.FindStringSubmatch(file); match != nil {
+		value, _ := strconv.Atoi(match[1])
+		return value
+	}
+	return 0
+}
+
+// NewATCFindings returns the findings which are not contained in the baseline. Findings are compared by their fingerprint,
+// a finding which occurs more often than in the baseline is new.
+func NewATCFindings(findings, baseline []ATCFinding) []ATCFinding {
+	known := map[string]int{}
+	for _, finding := range baseline {
+		known[finding.Fingerprint()]++
+	}
+	newFindings := []ATCFinding{}
+	for _, finding := range findings {
+		fingerprint := finding.Fingerprint()
+		if known[fingerprint] > 0 {
+			known[fingerprint]--
+			continue
+		}
+		newFindings = append(newFindings, finding)
+	}
+	return newFindings
+}
+
+// CreateATCSarif converts ATC findings into SARIF format with artifact locations in the abapGit repository layout
+func CreateATCSarif(findings []ATCFinding, sourceDirectory string) format.SARIF {
+	ruleIndex := map[string]int{}
+	rules := []format.SarifRule{}
+	sources := []string{}
+	for _, finding := range findings {
+		if _, ok := ruleIndex[finding.Source]; !ok {
+			ruleIndex[finding.Source] = 0
+			sources = append(sources, finding.Source)
+		}
+	}
+	sort.Strings(sources)
+	for i, source := range sources {
+		ruleIndex[source] = i
+		rules = append(rules, format.SarifRule{
+			ID:               source,
+			Name:             source,
+			ShortDescription: &format.Message{Text: source},
+		})
+	}
+
+	results := []format.Results{}
+	for _, finding := range findings {
+		results = append(results, format.Results{
+			RuleID:    finding.Source,
+			RuleIndex: ruleIndex[finding.Source],
+			Level:     finding.SarifLevel(),
+			Message:   &format.Message{Text: finding.Message},
+			Locations: []format.Location{{
+				PhysicalLocation: format.PhysicalLocation{
+					ArtifactLocation: format.ArtifactLocation{URI: ATCArtifactURI(finding.File, sourceDirectory)},
+					Region:           format.Region{StartLine: finding.Line},
+				},
+			}},
+		})
+	}
+
+	return format.SARIF{
+		Schema:  "https://docs.oasis-open.org/sarif/sarif/v2.1.0/cos02/schemas/sarif-schema-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []format.Runs{{
+			Results: results,
+			Tool: format.Tool{Driver: format.Driver{
+				Name:           atcToolName,
+				InformationUri: "https://help.sap.com/docs/btp/sap-business-technology-platform/checking-quality-of-abap-code-with-atc",
+				Rules:          rules,
+			}},
+		}},
+	}
+}
+
+// ATCSummaryMarkdown creates a markdown summary of the findings, e.g. to be posted as comment on a pull request.
+// If a baseline was used, newFindings lists the findings not contained in the baseline, otherwise it is nil.
+func ATCSummaryMarkdown(findings, newFindings []ATCFinding, sourceDirectory string) string {
+	var summary strings.Builder
+	summary.WriteString("### ATC Results\n\n")
+	counts := map[string]int{}
+	for _, finding := range findings {
+		counts[finding.SarifLevel()]++
+	}
+	summary.WriteString("| Priority | Findings |\n|---|---|\n")
+	for _, level := range []string{"error", "warning", "note"} {
+		fmt.Fprintf(&summary, "| %s | %d |\n", level, counts[level])
+	}
+
+	listed := findings
+	if newFindings != nil {
+		fmt.Fprintf(&summary, "\n%d of %d findings are new compared to the baseline.\n", len(newFindings), len(findings))
+		listed = newFindings
+	}
+	if len(listed) == 0 {
+		return summary.String()
+	}
+
+	const maxListed = 50
+	summary.WriteString("\n| Priority | File | Line | Check | Message |\n|---|---|---|---|---|\n")
+	for i, finding := range listed {
+		if i == maxListed {
+			fmt.Fprintf(&summary, "\n... and %d more findings\n", len(listed)-maxListed)
+			break
+		}
+		fmt.Fprintf(&summary, "| %s | %s | %d | %s | %s |\n", finding.SarifLevel(), ATCArtifactURI(finding.File, sourceDirectory), finding.Line,
+			escapeMarkdownTableCell(finding.Source), escapeMarkdownTableCell(finding.Message))
+	}
+	return summary.String()
+}
+
+func escapeMarkdownTableCell(text string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(text, "|", "\\|"), "\n", " ")
+}
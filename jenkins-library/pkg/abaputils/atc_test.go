@@ -0,0 +1,100 @@
+//go:build unit
+// +build unit
+
+package abaputils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestATCArtifactURI(t *testing.T) {
+	tests := []struct {
+		file     string
+		expected string
+	}{
+		{"/sap/bc/adt/oo/classes/zcl_example/source/main#start=12,0", "src/zcl_example.clas.abap"},
+		{"/sap/bc/adt/oo/classes/ZCL_EXAMPLE/includes/testclasses#start=3,2", "src/zcl_example.clas.testclasses.abap"},
+		{"/sap/bc/adt/oo/classes/zcl_example/includes/implementations", "src/zcl_example.clas.locals_imp.abap"},
+		{"/sap/bc/adt/oo/classes/%2fabc%2fcl_example/source/main", "src/#abc#cl_example.clas.abap"},
+		{"/sap/bc/adt/oo/interfaces/zif_example/source/main", "src/zif_example.intf.abap"},
+		{"/sap/bc/adt/functions/groups/zfg/fmodules/z_function/source/main", "src/zfg.fugr.z_function.abap"},
+		{"/sap/bc/adt/functions/groups/zfg/source/main", "src/zfg.fugr.saplzfg.abap"},
+		{"/sap/bc/adt/programs/programs/zreport/source/main", "src/zreport.prog.abap"},
+		{"/sap/bc/adt/ddic/ddl/sources/zi_view/source/main", "src/zi_view.ddls.asddls"},
+		{"/sap/bc/adt/bo/behaviordefinitions/zi_view/source/main", "src/zi_view.bdef.asbdef"},
+		{"ZCL_UNKNOWN", "ZCL_UNKNOWN"},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, ATCArtifactURI(test.file, "src"), test.file)
+	}
+}
+
+func TestATCStartLine(t *testing.T) {
+	assert.Equal(t, 7, ATCStartLine("7", "/sap/bc/adt/oo/classes/zcl_example/source/main#start=12,0"))
+	assert.Equal(t, 12, ATCStartLine("", "/sap/bc/adt/oo/classes/zcl_example/source/main#start=12,0"))
+	assert.Equal(t, 0, ATCStartLine("", "ZCL_EXAMPLE"))
+}
+
+func TestNewATCFindings(t *testing.T) {
+	finding := ATCFinding{File: "/sap/bc/adt/oo/classes/zcl_a/source/main", Line: 10, Severity: "error", Message: "Syntax error", Source: "Syntax Check"}
+	moved := finding
+	moved.Line = 12
+	other := ATCFinding{File: "/sap/bc/adt/oo/classes/zcl_b/source/main", Line: 1, Severity: "warning", Message: "Unused variable", Source: "Extended Check"}
+
+	t.Run("moved findings are not new", func(t *testing.T) {
+		assert.Equal(t, []ATCFinding{other}, NewATCFindings([]ATCFinding{moved, other}, []ATCFinding{finding}))
+	})
+
+	t.Run("additional occurrences are new", func(t *testing.T) {
+		assert.Equal(t, []ATCFinding{finding}, NewATCFindings([]ATCFinding{finding, finding}, []ATCFinding{finding}))
+	})
+
+	t.Run("empty baseline", func(t *testing.T) {
+		assert.Equal(t, []ATCFinding{finding, other}, NewATCFindings([]ATCFinding{finding, other}, nil))
+	})
+}
+
+func TestCreateATCSarif(t *testing.T) {
+	sarif := CreateATCSarif([]ATCFinding{
+		{File: "/sap/bc/adt/oo/classes/zcl_a/source/main", Line: 10, Severity: "error", Message: "Syntax error", Source: "Syntax Check"},
+		{File: "/sap/bc/adt/oo/interfaces/zif_b/source/main", Line: 3, Severity: "info", Message: "Missing comment", Source: "Documentation"},
+	}, "src")
+
+	run := sarif.Runs[0]
+	assert.Equal(t, "2.1.0", sarif.Version)
+	assert.Equal(t, "ABAP Test Cockpit", run.Tool.Driver.Name)
+	if assert.Len(t, run.Tool.Driver.Rules, 2) {
+		assert.Equal(t, "Documentation", run.Tool.Driver.Rules[0].ID)
+		assert.Equal(t, "Syntax Check", run.Tool.Driver.Rules[1].ID)
+	}
+	if assert.Len(t, run.Results, 2) {
+		assert.Equal(t, "error", run.Results[0].Level)
+		assert.Equal(t, 1, run.Results[0].RuleIndex)
+		assert.Equal(t, "src/zcl_a.clas.abap", run.Results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+		assert.Equal(t, 10, run.Results[0].Locations[0].PhysicalLocation.Region.StartLine)
+		assert.Equal(t, "note", run.Results[1].Level)
+	}
+}
+
+func TestATCSummaryMarkdown(t *testing.T) {
+	findings := []ATCFinding{
+		{File: "/sap/bc/adt/oo/classes/zcl_a/source/main", Line: 10, Severity: "error", Message: "Syntax | error", Source: "Syntax Check"},
+		{File: "/sap/bc/adt/oo/classes/zcl_a/source/main", Line: 12, Severity: "warning", Message: "Unused variable", Source: "Extended Check"},
+	}
+
+	t.Run("without baseline", func(t *testing.T) {
+		summary := ATCSummaryMarkdown(findings, nil, "src")
+		assert.Contains(t, summary, "| error | 1 |\n| warning | 1 |\n| note | 0 |")
+		assert.Contains(t, summary, "| error | src/zcl_a.clas.abap | 10 | Syntax Check | Syntax \\| error |")
+		assert.NotContains(t, summary, "baseline")
+	})
+
+	t.Run("with baseline", func(t *testing.T) {
+		summary := ATCSummaryMarkdown(findings, findings[1:], "src")
+		assert.Contains(t, summary, "1 of 2 findings are new compared to the baseline.")
+		assert.Contains(t, summary, "Unused variable")
+		assert.NotContains(t, summary, "Syntax \\| error")
+	})
+}
@@ -60,6 +60,9 @@ type Repository struct {
 	Languages           []string `json:"languages,omitempty"`
 	InBuildScope        bool     `json:",omitempty"`
 	ErrorText           string   `json:",omitempty"`
+	BuildID             string   `json:",omitempty"`
+	BuildRunState       string   `json:",omitempty"`
+	BuildResultState    string   `json:",omitempty"`
 }
 
 // ReadAddonDescriptorType is the type for ReadAddonDescriptor for mocking
@@ -0,0 +1,391 @@
+//go:build !release
+// +build !release
+
+package mocks
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/abaputils"
+)
+
+const (
+	// APIPath is the path of the service of communication scenario SAP_COM_0948 to manage software components
+	APIPath = "/sap/opu/odata4/sap/a4c_mswc_api/srvd_a2x/sap/manage_software_components/0001"
+	// Username and Password are the credentials of the communication user of the fake system
+	Username = "user"
+	Password = "password"
+
+	csrfToken = "csrf-token"
+)
+
+// SoftwareComponent is a software component of the fake system
+type SoftwareComponent struct {
+	Name string
+	// Branches are the branches of the remote repository of the software component
+	Branches []string
+	// Tags are the commit IDs of the tags of the software component
+	Tags map[string]string
+	// Cloned is true once the software component has been cloned to the system
+	Cloned       bool
+	ActiveBranch string
+	CommitID     string
+}
+
+// Action is an action on a software component triggered on the fake system
+type Action struct {
+	UUID          string
+	Type          string
+	ComponentName string
+	BranchName    string
+	CommitID      string
+	// Status is the final status of the action, S for success and E for error
+	Status    string
+	Message   string
+	StartTime string
+	polls     int
+}
+
+// Server is a local fake of the ABAP environment API to manage software components (SAP_COM_0948).
+// The server uses TLS, since the ABAP steps require a host with https scheme.
+type Server struct {
+	*httptest.Server
+
+	mu sync.Mutex
+	// SoftwareComponents are the software components of the system by name
+	SoftwareComponents map[string]*SoftwareComponent
+	// Actions are the triggered actions by UUID
+	Actions map[string]*Action
+	// FailingComponents are the error messages of software components whose actions fail, by name
+	FailingComponents map[string]string
+	// RunningPolls is the number of status requests per action which report the action as still running
+	RunningPolls int
+	// Requests are the requests in the format 'METHOD path' received by the server
+	Requests []string
+}
+
+// NewServer starts a fake ABAP environment system. The server is closed when Close is called.
+func NewServer() *Server {
+	s := &Server{
+		SoftwareComponents: map[string]*SoftwareComponent{},
+		Actions:            map[string]*Action{},
+		FailingComponents:  map[string]string{},
+	}
+	s.Server = httptest.NewTLSServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// AddSoftwareComponent adds a software component with the given branches, the first branch is the default branch
+func (s *Server) AddSoftwareComponent(name string, branches ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(branches) == 0 {
+		branches = []string{"main"}
+	}
+	s.SoftwareComponents[name] = &SoftwareComponent{Name: name, Branches: branches, Tags: map[string]string{}}
+}
+
+// SoftwareComponent returns the state of the software component
+func (s *Server) SoftwareComponent(name string) (SoftwareComponent, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	component, ok := s.SoftwareComponents[name]
+	if !ok {
+		return SoftwareComponent{}, false
+	}
+	return *component, true
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Requests = append(s.Requests, r.Method+" "+r.URL.EscapedPath())
+
+	if user, password, ok := r.BasicAuth(); !ok || user != Username || password != Password {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if !strings.HasPrefix(r.URL.EscapedPath(), APIPath+"/") {
+		writeError(w, http.StatusNotFound, "/IWCOR/CX_OD_NOT_FOUND", "resource not found")
+		return
+	}
+	if strings.EqualFold(r.Header.Get("X-Csrf-Token"), "fetch") {
+		w.Header().Set("X-Csrf-Token", csrfToken)
+	}
+	if r.Method != http.MethodGet && r.Header.Get("X-Csrf-Token") != csrfToken {
+		w.Header().Set("X-Csrf-Token", "Required")
+		writeError(w, http.StatusForbidden, "/IWFND/CM_CONSUMER/008", "CSRF token validation failed")
+		return
+	}
+
+	segments := strings.Split(strings.TrimPrefix(r.URL.EscapedPath(), APIPath+"/"), "/")
+	for i, segment := range segments {
+		segments[i], _ = url.PathUnescape(segment)
+	}
+
+	switch {
+	case segments[0] == "Actions" && r.Method == http.MethodGet:
+		s.handleActions(w, segments[1:])
+	case segments[0] == "SoftwareComponents" && len(segments) == 2 && r.Method == http.MethodGet:
+		component, ok := s.component(w, segments[1])
+		if ok {
+			writeJSON(w, http.StatusOK, abaputils.RepositoryEntity{ScName: component.Name, ActiveBranch: component.ActiveBranch, AvailOnInst: component.Cloned})
+		}
+	case segments[0] == "SoftwareComponents" && len(segments) == 3 && r.Method == http.MethodPost:
+		s.handleComponentAction(w, r, segments[1], segments[2])
+	case segments[0] == "Branches" && len(segments) == 4 && segments[3] == "SAP__self.checkout_branch" && r.Method == http.MethodPost:
+		s.handleCheckout(w, segments[1], segments[2])
+	case segments[0] == "Tags" && len(segments) == 1 && r.Method == http.MethodPost:
+		s.handleTag(w, r)
+	case segments[0] == "LogArchive" && len(segments) == 3 && r.Method == http.MethodGet:
+		s.handleLogArchive(w, segments[1])
+	default:
+		writeError(w, http.StatusNotFound, "/IWCOR/CX_OD_NOT_FOUND", "resource not found")
+	}
+}
+
+func (s *Server) component(w http.ResponseWriter, name string) (*SoftwareComponent, bool) {
+	component, ok := s.SoftwareComponents[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, "A4C_A2G/311", fmt.Sprintf("Software component %v does not exist", name))
+	}
+	return component, ok
+}
+
+// handleComponentAction clones or pulls a software component
+func (s *Server) handleComponentAction(w http.ResponseWriter, r *http.Request, name, operation string) {
+	component, ok := s.component(w, name)
+	if !ok {
+		return
+	}
+	var body struct {
+		BranchName string `json:"branch_name"`
+		CommitID   string `json:"commit_id"`
+		TagName    string `json:"tag_name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "A4C_A2G/000", err.Error())
+		return
+	}
+
+	switch operation {
+	case "SAP__self.clone":
+		if component.Cloned {
+			writeError(w, http.StatusBadRequest, "A4C_A2G/257", fmt.Sprintf("Software component %v has already been cloned", name))
+			return
+		}
+		branch := body.BranchName
+		if len(branch) == 0 {
+			branch = component.Branches[0]
+		}
+		if !contains(component.Branches, branch) {
+			writeError(w, http.StatusBadRequest, "A4C_A2G/222", fmt.Sprintf("Branch %v does not exist", branch))
+			return
+		}
+		action := s.newAction("Clone", component, branch, commitID(component, branch, body.CommitID, body.TagName))
+		if action.Status == "S" {
+			component.Cloned = true
+			component.ActiveBranch = branch
+			component.CommitID = action.CommitID
+		}
+		writeJSON(w, http.StatusOK, action.entity(action.Status))
+	case "SAP__self.pull":
+		if !component.Cloned {
+			writeError(w, http.StatusBadRequest, "A4C_A2G/224", fmt.Sprintf("Software component %v has not been cloned yet", name))
+			return
+		}
+		action := s.newAction("Pull", component, component.ActiveBranch, commitID(component, component.ActiveBranch, body.CommitID, body.TagName))
+		if action.Status == "S" {
+			component.CommitID = action.CommitID
+		}
+		writeJSON(w, http.StatusOK, action.entity(action.Status))
+	default:
+		writeError(w, http.StatusNotFound, "/IWCOR/CX_OD_NOT_FOUND", "resource not found")
+	}
+}
+
+func (s *Server) handleCheckout(w http.ResponseWriter, name, branch string) {
+	component, ok := s.component(w, name)
+	if !ok {
+		return
+	}
+	if !component.Cloned || !contains(component.Branches, branch) {
+		writeError(w, http.StatusBadRequest, "A4C_A2G/222", fmt.Sprintf("Branch %v of software component %v cannot be checked out", branch, name))
+		return
+	}
+	action := s.newAction("Checkout", component, branch, commitID(component, branch, "", ""))
+	if action.Status == "S" {
+		component.ActiveBranch = branch
+		component.CommitID = action.CommitID
+	}
+	writeJSON(w, http.StatusOK, action.entity(action.Status))
+}
+
+func (s *Server) handleTag(w http.ResponseWriter, r *http.Request) {
+	var body abaputils.CreateTagBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "A4C_A2G/000", err.Error())
+		return
+	}
+	component, ok := s.component(w, body.RepositoryName)
+	if !ok {
+		return
+	}
+	if _, exists := component.Tags[body.Tag]; exists {
+		writeError(w, http.StatusBadRequest, "A4C_A2G/230", fmt.Sprintf("Tag %v already exists", body.Tag))
+		return
+	}
+	commit := body.CommitID
+	if len(commit) == 0 {
+		commit = component.CommitID
+	}
+	action := s.newAction("Tag", component, component.ActiveBranch, commit)
+	if action.Status == "S" {
+		component.Tags[body.Tag] = commit
+	}
+	writeJSON(w, http.StatusOK, action.entity(action.Status))
+}
+
+// handleActions returns the status and the logs of actions
+func (s *Server) handleActions(w http.ResponseWriter, segments []string) {
+	if len(segments) == 0 {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"value": []interface{}{}})
+		return
+	}
+	action, ok := s.Actions[segments[0]]
+	if !ok {
+		writeError(w, http.StatusNotFound, "A4C_A2G/502", fmt.Sprintf("Action %v does not exist", segments[0]))
+		return
+	}
+	running := action.polls < s.RunningPolls
+	logType := "Success"
+	if action.Status == "E" {
+		logType = "Error"
+	}
+
+	switch {
+	case len(segments) == 1:
+		status := action.Status
+		if running {
+			status = "R"
+		}
+		action.polls++
+		writeJSON(w, http.StatusOK, action.entity(status))
+	case len(segments) == 2 && segments[1] == "_Execution_log":
+		writeJSON(w, http.StatusOK, abaputils.ExecutionLog{Value: []abaputils.ExecutionLogValue{
+			{IndexNo: 1, Type: "Info", Descr: fmt.Sprintf("%v of software component %v started", action.Type, action.ComponentName), Timestamp: action.StartTime},
+			{IndexNo: 2, Type: logType, Descr: action.Message, Timestamp: action.StartTime},
+		}})
+	case len(segments) == 2 && segments[1] == "_Log_Overview":
+		writeJSON(w, http.StatusOK, map[string]interface{}{"value": []abaputils.LogResultsV2{
+			{Index: 1, Name: "Main Import", Status: logType, Timestamp: action.StartTime},
+		}})
+	case len(segments) == 4 && segments[1] == "_Log_Overview" && segments[3] == "_Log_Protocol":
+		protocol := []abaputils.LogProtocol{}
+		if segments[2] == "1" {
+			protocol = append(protocol, abaputils.LogProtocol{OverviewIndex: 1, ProtocolLine: 1, Type: logType, Description: action.Message, Timestamp: action.StartTime})
+		}
+		writeJSON(w, http.StatusOK, abaputils.LogProtocolResultsV4{Results: protocol, Count: len(protocol)})
+	default:
+		writeError(w, http.StatusNotFound, "/IWCOR/CX_OD_NOT_FOUND", "resource not found")
+	}
+}
+
+func (s *Server) handleLogArchive(w http.ResponseWriter, uuid string) {
+	action, ok := s.Actions[uuid]
+	if !ok {
+		writeError(w, http.StatusNotFound, "A4C_A2G/502", fmt.Sprintf("Action %v does not exist", uuid))
+		return
+	}
+	var archive bytes.Buffer
+	writer := zip.NewWriter(&archive)
+	file, err := writer.Create(action.UUID + ".log")
+	if err == nil {
+		_, err = file.Write([]byte(action.Message))
+	}
+	if err == nil {
+		err = writer.Close()
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "A4C_A2G/000", err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/zip")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(archive.Bytes())
+}
+
+// newAction records an action, which fails if the software component is configured to fail
+func (s *Server) newAction(actionType string, component *SoftwareComponent, branch, commit string) *Action {
+	action := &Action{
+		UUID:          fmt.Sprintf("00000000-0000-0000-0000-%012d", len(s.Actions)+1),
+		Type:          actionType,
+		ComponentName: component.Name,
+		BranchName:    branch,
+		CommitID:      commit,
+		Status:        "S",
+		Message:       fmt.Sprintf("%v of software component %v finished successfully", actionType, component.Name),
+		StartTime:     time.Now().UTC().Format(time.RFC3339),
+	}
+	if message, fails := s.FailingComponents[component.Name]; fails {
+		action.Status = "E"
+		action.Message = message
+	}
+	s.Actions[action.UUID] = action
+	return action
+}
+
+func (a *Action) entity(status string) abaputils.ActionEntity {
+	descriptions := map[string]string{"R": "Running", "S": "Success", "E": "Error"}
+	return abaputils.ActionEntity{
+		UUID:              a.UUID,
+		ScName:            a.ComponentName,
+		ImportType:        a.Type,
+		BranchName:        a.BranchName,
+		Status:            status,
+		StatusDescription: descriptions[status],
+		CommitID:          a.CommitID,
+		StartTime:         a.StartTime,
+		StartedByUser:     Username,
+	}
+}
+
+// commitID returns the commit of a clone or pull, which is the given commit, the commit of the tag or the head of the branch
+func commitID(component *SoftwareComponent, branch, commit, tag string) string {
+	if len(commit) > 0 {
+		return commit
+	}
+	if tagCommit, ok := component.Tags[tag]; ok && len(tag) > 0 {
+		return tagCommit
+	}
+	return "head-of-" + branch + "-" + strconv.Itoa(len(component.Tags))
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, map[string]interface{}{"error": abaputils.AbapErrorODataV4{Code: code, Message: message}})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
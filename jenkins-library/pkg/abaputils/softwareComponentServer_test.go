@@ -0,0 +1,83 @@
+//go:build unit
+// +build unit
+
+package abaputils_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/abaputils"
+	"github.com/SAP/jenkins-library/pkg/abaputils/mocks"
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// insecureClient trusts the self-signed certificate of the fake system
+type insecureClient struct {
+	piperhttp.Client
+}
+
+func (c *insecureClient) SetOptions(options piperhttp.ClientOptions) {
+	options.TransportSkipVerification = true
+	c.Client.SetOptions(options)
+}
+
+func TestSoftwareComponentsOnServer(t *testing.T) {
+	server := mocks.NewServer()
+	defer server.Close()
+	server.AddSoftwareComponent("/DMO/REPO", "main", "feature")
+	server.AddSoftwareComponent("/DMO/BROKEN")
+	server.FailingComponents["/DMO/BROKEN"] = "Import of /DMO/BROKEN failed"
+	server.RunningPolls = 1
+	manager := abaputils.SoftwareComponentApiManager{Client: &insecureClient{}, PollIntervall: time.Millisecond}
+	con := abaputils.ConnectionDetailsHTTP{URL: server.URL, User: mocks.Username, Password: mocks.Password}
+
+	api, err := manager.GetAPI(con, abaputils.Repository{Name: "/DMO/REPO", Branch: "main"})
+	require.NoError(t, err)
+	cloned, _, err, _ := api.GetRepository()
+	require.NoError(t, err)
+	assert.False(t, cloned)
+
+	require.NoError(t, api.Clone())
+	status, err := abaputils.PollEntity(api, manager.GetPollIntervall(), &abaputils.LogOutputManager{})
+	require.NoError(t, err)
+	assert.Equal(t, "S", status)
+
+	require.NoError(t, api.Pull())
+	status, err = abaputils.PollEntity(api, manager.GetPollIntervall(), &abaputils.LogOutputManager{})
+	require.NoError(t, err)
+	assert.Equal(t, "S", status)
+
+	require.NoError(t, api.CreateTag(abaputils.Tag{TagName: "v1.0.0", TagDescription: "release"}))
+
+	api, err = manager.GetAPI(con, abaputils.Repository{Name: "/DMO/REPO", Branch: "feature"})
+	require.NoError(t, err)
+	require.NoError(t, api.CheckoutBranch())
+	status, err = abaputils.PollEntity(api, manager.GetPollIntervall(), &abaputils.LogOutputManager{})
+	require.NoError(t, err)
+	assert.Equal(t, "S", status)
+
+	component, _ := server.SoftwareComponent("/DMO/REPO")
+	assert.True(t, component.Cloned)
+	assert.Equal(t, "feature", component.ActiveBranch)
+	assert.Contains(t, component.Tags, "v1.0.0")
+
+	t.Run("failing action", func(t *testing.T) {
+		api, err := manager.GetAPI(con, abaputils.Repository{Name: "/DMO/BROKEN"})
+		require.NoError(t, err)
+		require.NoError(t, api.Clone())
+		status, err := abaputils.PollEntity(api, manager.GetPollIntervall(), &abaputils.LogOutputManager{})
+		require.NoError(t, err)
+		assert.Equal(t, "E", status)
+		component, _ := server.SoftwareComponent("/DMO/BROKEN")
+		assert.False(t, component.Cloned)
+	})
+
+	t.Run("unknown software component", func(t *testing.T) {
+		api, err := manager.GetAPI(con, abaputils.Repository{Name: "/DMO/UNKNOWN"})
+		require.NoError(t, err)
+		assert.Error(t, api.Pull())
+	})
+}
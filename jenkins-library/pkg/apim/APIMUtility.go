@@ -2,12 +2,10 @@ package apim
 
 import (
 	"encoding/json"
-	"fmt"
 	"strings"
 
 	"github.com/SAP/jenkins-library/pkg/cpi"
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
-	"github.com/SAP/jenkins-library/pkg/xsuaa"
 	"github.com/pasztorpisti/qs"
 	"github.com/pkg/errors"
 )
@@ -36,27 +34,21 @@ type Bundle struct {
 	Client                       piperhttp.Sender
 }
 
-// InitAPIM() fumnction initialize APIM bearer token for API access
+// InitAPIM() function initializes the OAuth2 authentication of the client for API access
 func (apim *Bundle) InitAPIM() error {
 	serviceKey, err := cpi.ReadCpiServiceKey(apim.APIServiceKey)
 	if err != nil {
 		return err
 	}
 	apim.Host = serviceKey.OAuth.Host
-	httpClient := apim.Client
-	clientOptions := piperhttp.ClientOptions{}
-	x := xsuaa.XSUAA{
-		OAuthURL:     serviceKey.OAuth.OAuthTokenProviderURL,
+	if len(serviceKey.OAuth.OAuthTokenProviderURL) == 0 {
+		return errors.New("the token URL of the service key is missing")
+	}
+	apim.Client.SetOptions(piperhttp.ClientOptions{OAuth2: &piperhttp.OAuth2Options{
+		TokenURL:     serviceKey.OAuth.OAuthTokenProviderURL,
 		ClientID:     serviceKey.OAuth.ClientID,
 		ClientSecret: serviceKey.OAuth.ClientSecret,
-	}
-	token, tokenErr := x.GetBearerToken()
-
-	if tokenErr != nil {
-		return errors.Wrap(tokenErr, "failed to fetch Bearer Token")
-	}
-	clientOptions.Token = fmt.Sprintf("Bearer %s", token.AccessToken)
-	httpClient.SetOptions(clientOptions)
+	}})
 	return nil
 }
 
@@ -73,7 +65,7 @@ func (odataFilters *OdataParameters) MakeOdataQuery() (string, error) {
 	})
 	values, encodeErr := customMarshaler.Marshal(odataFilters)
 	if encodeErr == nil && len(values) > 0 {
-		values = "?" + strings.ReplaceAll(values, "&", "&$")
+		values = "?$" + strings.ReplaceAll(values, "&", "&$")
 	}
 	return values, encodeErr
 }
@@ -16,7 +16,7 @@ func TestOdataQueryInitExisting(t *testing.T) {
 			Select: "", Expand: ""}
 		odataFilters, err := OdataUtils.MakeOdataQuery(&odataFilterInputs)
 		assert.NoError(t, err)
-		assert.Equal(t, "?filter=isCopy+eq+false&$orderby=name&$skip=1&$top=4", odataFilters)
+		assert.Equal(t, "?$filter=isCopy+eq+false&$orderby=name&$skip=1&$top=4", odataFilters)
 	})
 
 	t.Run("MakeOdataQuery- empty odata filters Test", func(t *testing.T) {
@@ -0,0 +1,175 @@
+package apim
+
+import (
+	"bytes"
+	b64 "encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// KindAPIProvider is the kind of API providers
+	KindAPIProvider = "APIProvider"
+	// KindKeyValueMap is the kind of key value maps
+	KindKeyValueMap = "KeyValueMap"
+	// KindAPIProxy is the kind of API proxies
+	KindAPIProxy = "APIProxy"
+)
+
+// entitySets are the entity sets of the management API by kind
+var entitySets = map[string]string{
+	KindAPIProvider: "APIProviders",
+	KindKeyValueMap: "KeyMapEntries",
+	KindAPIProxy:    "APIProxies",
+}
+
+// listQueries are the OData queries used to list the entities of a kind
+var listQueries = map[string]string{
+	KindAPIProvider: "",
+	KindKeyValueMap: "?$expand=keyMapEntryValues",
+	KindAPIProxy:    "?$filter=isCopy%20eq%20false&$select=name",
+}
+
+// ListEntities returns the entities of the given kind on the tenant by their name. Server-side paging is followed
+// via the next link of the responses.
+func (apim *Bundle) ListEntities(kind string) (map[string]map[string]interface{}, error) {
+	entities := map[string]map[string]interface{}{}
+	requestURL := apim.managementURL(kind, "") + listQueries[kind]
+	for len(requestURL) > 0 {
+		body, err := apim.send(http.MethodGet, requestURL, nil, "application/json", http.StatusOK)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list entities of kind %v", kind)
+		}
+		var response struct {
+			D struct {
+				Results []map[string]interface{} `json:"results"`
+				Next    string                   `json:"__next"`
+			} `json:"d"`
+		}
+		if err := json.Unmarshal(body, &response); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse entities of kind %v", kind)
+		}
+		for _, entity := range response.D.Results {
+			name, _ := entity["name"].(string)
+			entities[name] = entity
+		}
+		if requestURL, err = nextPageURL(requestURL, response.D.Next); err != nil {
+			return nil, errors.Wrapf(err, "failed to list entities of kind %v", kind)
+		}
+	}
+	return entities, nil
+}
+
+// nextPageURL resolves the next link of an OData response, which may be relative to the current request
+func nextPageURL(requestURL, next string) (string, error) {
+	if len(next) == 0 {
+		return "", nil
+	}
+	current, err := url.Parse(requestURL)
+	if err != nil {
+		return "", err
+	}
+	nextURL, err := url.Parse(next)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid next link '%v'", next)
+	}
+	return current.ResolveReference(nextURL).String(), nil
+}
+
+// CreateEntity creates an API provider or key value map with the given definition
+func (apim *Bundle) CreateEntity(kind string, definition map[string]interface{}) error {
+	if _, err := apim.send(http.MethodPost, apim.managementURL(kind, ""), definition, "application/json", http.StatusCreated); err != nil {
+		return errors.Wrapf(err, "failed to create %v %v", kind, definition["name"])
+	}
+	return nil
+}
+
+// UpdateEntity replaces the API provider or key value map with the given definition
+func (apim *Bundle) UpdateEntity(kind string, definition map[string]interface{}) error {
+	name, _ := definition["name"].(string)
+	if _, err := apim.send(http.MethodPut, apim.managementURL(kind, name), definition, "application/json", http.StatusOK, http.StatusNoContent); err != nil {
+		return errors.Wrapf(err, "failed to update %v %v", kind, name)
+	}
+	return nil
+}
+
+// DeleteEntity deletes the entity of the given kind and name
+func (apim *Bundle) DeleteEntity(kind, name string) error {
+	if _, err := apim.send(http.MethodDelete, apim.managementURL(kind, name), nil, "application/json", http.StatusOK, http.StatusNoContent); err != nil {
+		return errors.Wrapf(err, "failed to delete %v %v", kind, name)
+	}
+	return nil
+}
+
+// DownloadAPIProxy returns the zip archive of the API proxy
+func (apim *Bundle) DownloadAPIProxy(name string) ([]byte, error) {
+	requestURL := fmt.Sprintf("%s/apiportal/api/1.0/Transport.svc/APIProxies?name=%s", apim.Host, url.QueryEscape(name))
+	content, err := apim.send(http.MethodGet, requestURL, nil, "application/zip", http.StatusOK)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to download %v %v", KindAPIProxy, name)
+	}
+	return content, nil
+}
+
+// UploadAPIProxy imports the zip archive of an API proxy. An existing API proxy with the same name is replaced.
+func (apim *Bundle) UploadAPIProxy(name string, content []byte) error {
+	requestURL := fmt.Sprintf("%s/apiportal/api/1.0/Transport.svc/APIProxies", apim.Host)
+	if _, err := apim.send(http.MethodPost, requestURL, b64.StdEncoding.EncodeToString(content), "application/zip", http.StatusOK, http.StatusCreated); err != nil {
+		return errors.Wrapf(err, "failed to upload %v %v", KindAPIProxy, name)
+	}
+	return nil
+}
+
+func (apim *Bundle) managementURL(kind, name string) string {
+	requestURL := fmt.Sprintf("%s/apiportal/api/1.0/Management.svc/%s", apim.Host, entitySets[kind])
+	if len(name) > 0 {
+		requestURL += fmt.Sprintf("('%s')", url.PathEscape(strings.ReplaceAll(name, "'", "''")))
+	}
+	return requestURL
+}
+
+// send sends the request and returns the response body. String payloads are sent as they are, other payloads are sent as JSON.
+func (apim *Bundle) send(method, requestURL string, payload interface{}, accept string, expectedStatus ...int) ([]byte, error) {
+	header := make(http.Header)
+	header.Add("Accept", accept)
+	var body io.Reader
+	switch content := payload.(type) {
+	case nil:
+	case string:
+		body = bytes.NewBufferString(content)
+	default:
+		jsonPayload, err := json.Marshal(content)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create request payload")
+		}
+		header.Add("Content-Type", "application/json")
+		body = bytes.NewBuffer(jsonPayload)
+	}
+
+	response, httpErr := apim.Client.SendRequest(method, requestURL, body, header, nil)
+	if response == nil {
+		return nil, errors.Errorf("did not retrieve a HTTP response: %v", httpErr)
+	}
+	if response.Body != nil {
+		defer response.Body.Close()
+	}
+	responseBody, readErr := io.ReadAll(response.Body)
+	if readErr != nil {
+		return nil, errors.Wrapf(readErr, "HTTP response body could not be read, response status code: %v", response.StatusCode)
+	}
+	for _, status := range expectedStatus {
+		if response.StatusCode == status {
+			return responseBody, nil
+		}
+	}
+	if httpErr != nil {
+		return nil, errors.Wrapf(httpErr, "HTTP %v request to %v failed with error: %v", method, requestURL, string(responseBody))
+	}
+	return nil, errors.Errorf("HTTP %v request to %v returned unexpected response status code %v: %v", method, requestURL, response.StatusCode, string(responseBody))
+}
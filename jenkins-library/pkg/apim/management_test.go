@@ -0,0 +1,48 @@
+//go:build unit
+// +build unit
+
+package apim_test
+
+import (
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/apim"
+	"github.com/SAP/jenkins-library/pkg/apim/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListEntitiesPaging(t *testing.T) {
+	server := mocks.NewServer()
+	t.Cleanup(server.Close)
+	server.PageSize = 2
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		server.AddEntity("APIProviders", map[string]interface{}{"name": name})
+	}
+	bundle := newBundle(t, server)
+
+	entities, err := bundle.ListEntities(apim.KindAPIProvider)
+
+	require.NoError(t, err)
+	assert.Len(t, entities, 5)
+	assert.Contains(t, entities, "e")
+	// one request for the token and three pages
+	assert.Equal(t, []string{
+		"GET /apiportal/api/1.0/Management.svc/APIProviders",
+		"GET /apiportal/api/1.0/Management.svc/APIProviders",
+		"GET /apiportal/api/1.0/Management.svc/APIProviders",
+	}, server.Requests[1:])
+}
+
+func TestListEntitiesQueries(t *testing.T) {
+	server := mocks.NewServer()
+	t.Cleanup(server.Close)
+	bundle := newBundle(t, server)
+
+	for _, kind := range []string{apim.KindAPIProvider, apim.KindKeyValueMap, apim.KindAPIProxy} {
+		_, err := bundle.ListEntities(kind)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, []string{"", "$expand=keyMapEntryValues", "$filter=isCopy%20eq%20false&$select=name"}, server.Queries[1:])
+}
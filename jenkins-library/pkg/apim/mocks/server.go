@@ -0,0 +1,240 @@
+//go:build !release
+// +build !release
+
+package mocks
+
+import (
+	b64 "encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/SAP/jenkins-library/pkg/cpi"
+)
+
+const (
+	managementPath = "/apiportal/api/1.0/Management.svc/"
+	transportPath  = "/apiportal/api/1.0/Transport.svc/APIProxies"
+)
+
+var entityPath = regexp.MustCompile(`^(\w+)(?:\('(.+)'\))?$`)
+
+// Server is a local fake of the management API of an API Management tenant
+type Server struct {
+	*httptest.Server
+
+	mu sync.Mutex
+	// Entities are the API providers, key value maps and API proxies by entity set and name
+	Entities map[string]map[string]map[string]interface{}
+	// Proxies are the zip archives of the API proxies by name
+	Proxies map[string][]byte
+	// Requests are the requests in the format 'METHOD path' received by the server
+	Requests []string
+	// Queries are the raw queries of the requests received by the server
+	Queries []string
+	// PageSize limits the number of entities returned per list request, further pages are linked via __next
+	PageSize int
+}
+
+// NewServer starts a fake API Management tenant. The server is closed when Close is called.
+func NewServer() *Server {
+	s := &Server{
+		Entities: map[string]map[string]map[string]interface{}{"APIProviders": {}, "KeyMapEntries": {}, "APIProxies": {}},
+		Proxies:  map[string][]byte{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// ServiceKey returns a service key of the fake tenant
+func (s *Server) ServiceKey() string {
+	return fmt.Sprintf(`{"oauth": {"url": "%[1]v", "tokenurl": "%[1]v/oauth/token", "clientid": "client", "clientsecret": "secret"}}`, s.URL)
+}
+
+// AddEntity stores an entity in the given entity set, e.g. APIProviders
+func (s *Server) AddEntity(entitySet string, entity map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	name, _ := entity["name"].(string)
+	s.Entities[entitySet][name] = entity
+}
+
+// AddProxy stores an API proxy with the given zip archive, the files of the archive are located in a directory with the name of the API proxy
+func (s *Server) AddProxy(name string, content []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Entities["APIProxies"][name] = map[string]interface{}{"name": name, "isCopy": false}
+	s.Proxies[name] = content
+}
+
+// Entity returns the entity of the entity set with the given name
+func (s *Server) Entity(entitySet, name string) (map[string]interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entity, ok := s.Entities[entitySet][name]
+	return entity, ok
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Requests = append(s.Requests, r.Method+" "+r.URL.Path)
+	s.Queries = append(s.Queries, r.URL.RawQuery)
+
+	if r.URL.Path == "/oauth/token" {
+		writeJSON(w, http.StatusOK, map[string]string{"access_token": "token", "token_type": "bearer"})
+		return
+	}
+	if r.Header.Get("Authorization") != "Bearer token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if r.URL.Path == transportPath {
+		s.handleTransport(w, r)
+		return
+	}
+
+	match := entityPath.FindStringSubmatch(strings.TrimPrefix(r.URL.Path, managementPath))
+	if !strings.HasPrefix(r.URL.Path, managementPath) || match == nil || s.Entities[match[1]] == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	entities := s.Entities[match[1]]
+	name, _ := url.PathUnescape(strings.ReplaceAll(match[2], "''", "'"))
+
+	switch {
+	case r.Method == http.MethodGet && len(name) == 0:
+		names := make([]string, 0, len(entities))
+		for entityName := range entities {
+			names = append(names, entityName)
+		}
+		sort.Strings(names)
+		skip, _ := strconv.Atoi(r.URL.Query().Get("$skiptoken"))
+		page := map[string]interface{}{}
+		if s.PageSize > 0 && len(names) > skip+s.PageSize {
+			page["__next"] = fmt.Sprintf("%v?$skiptoken=%v", match[1], skip+s.PageSize)
+			names = names[skip : skip+s.PageSize]
+		} else {
+			names = names[min(skip, len(names)):]
+		}
+		results := []map[string]interface{}{}
+		for _, entityName := range names {
+			results = append(results, withMetadata(entities[entityName]))
+		}
+		page["results"] = results
+		writeJSON(w, http.StatusOK, map[string]interface{}{"d": page})
+	case r.Method == http.MethodPost && len(name) == 0:
+		entity := map[string]interface{}{}
+		if err := json.NewDecoder(r.Body).Decode(&entity); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		entityName, _ := entity["name"].(string)
+		if _, exists := entities[entityName]; exists {
+			writeJSON(w, http.StatusConflict, map[string]string{"error": "entity already exists"})
+			return
+		}
+		entities[entityName] = entity
+		writeJSON(w, http.StatusCreated, map[string]interface{}{"d": entity})
+	case r.Method == http.MethodPut && len(name) > 0:
+		if _, exists := entities[name]; !exists {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		entity := map[string]interface{}{}
+		if err := json.NewDecoder(r.Body).Decode(&entity); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		entities[name] = entity
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodDelete && len(name) > 0:
+		if _, exists := entities[name]; !exists {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		delete(entities, name)
+		delete(s.Proxies, name)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) handleTransport(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		name := r.URL.Query().Get("name")
+		content, ok := s.Proxies[name]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/zip")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%v.zip", name))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(content)
+	case http.MethodPost:
+		payload, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		content, err := b64.StdEncoding.DecodeString(string(payload))
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		name, err := proxyName(content)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		s.Entities["APIProxies"][name] = map[string]interface{}{"name": name, "isCopy": false}
+		s.Proxies[name] = content
+		w.WriteHeader(http.StatusOK)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// proxyName returns the name of the API proxy of the archive, which is the name of its top level directory
+func proxyName(content []byte) (string, error) {
+	files, err := cpi.ReadArtifactArchive(content)
+	if err != nil {
+		return "", err
+	}
+	for file := range files {
+		if parts := strings.SplitN(file, "/", 2); len(parts) == 2 {
+			return parts[0], nil
+		}
+	}
+	return "", fmt.Errorf("archive does not contain an API proxy")
+}
+
+// withMetadata adds the administrative data which is returned by the tenant for every entity
+func withMetadata(entity map[string]interface{}) map[string]interface{} {
+	result := map[string]interface{}{"__metadata": map[string]interface{}{"type": "apiportal.Entity"}, "life_cycle": map[string]interface{}{"created_by": "admin"}}
+	for key, value := range entity {
+		if list, ok := value.([]interface{}); ok {
+			value = map[string]interface{}{"results": list}
+		}
+		result[key] = value
+	}
+	return result
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
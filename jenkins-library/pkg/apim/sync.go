@@ -0,0 +1,300 @@
+package apim
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/cpi"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/pkg/errors"
+)
+
+// Action is the change which is required to converge an entity to the desired state
+type Action string
+
+const (
+	// ActionCreate creates an entity which does not exist on the tenant
+	ActionCreate Action = "create"
+	// ActionUpdate updates an entity which differs from the desired state
+	ActionUpdate Action = "update"
+	// ActionDelete deletes a managed entity which is not part of the desired state
+	ActionDelete Action = "delete"
+	// ActionNone means the entity is in the desired state
+	ActionNone Action = "none"
+	// ActionKeep means the entity is not part of the desired state, but is kept since it is not managed
+	ActionKeep Action = "keep"
+)
+
+// stateDirectories are the directories of the desired state by kind
+var stateDirectories = map[string]string{
+	KindAPIProvider: "apiProviders",
+	KindKeyValueMap: "keyValueMaps",
+	KindAPIProxy:    "apiProxies",
+}
+
+// Kinds are the kinds of entities in the order in which they are applied. API proxies refer to API providers and key value maps.
+var Kinds = []string{KindAPIProvider, KindKeyValueMap, KindAPIProxy}
+
+// DesiredEntity is an entity of the desired state
+type DesiredEntity struct {
+	Kind string
+	Name string
+	// Definition is the JSON definition of API providers and key value maps
+	Definition map[string]interface{}
+	// Files are the files of the unpacked API proxy bundle
+	Files cpi.ArtifactFiles
+}
+
+// PlannedChange is a change of the plan to converge the tenant to the desired state
+type PlannedChange struct {
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	Action Action `json:"action"`
+
+	desired *DesiredEntity
+}
+
+// Plan is the list of changes to converge the tenant to the desired state
+type Plan struct {
+	Changes []PlannedChange `json:"changes"`
+}
+
+// Count returns the number of changes with the given action
+func (p Plan) Count(action Action) int {
+	count := 0
+	for _, change := range p.Changes {
+		if change.Action == action {
+			count++
+		}
+	}
+	return count
+}
+
+// HasChanges returns whether the plan contains changes of the tenant
+func (p Plan) HasChanges() bool {
+	return p.Count(ActionCreate)+p.Count(ActionUpdate)+p.Count(ActionDelete) > 0
+}
+
+// Summary describes the number of changes of the plan
+func (p Plan) Summary() string {
+	return fmt.Sprintf("%d to create, %d to update, %d to delete, %d unchanged, %d unmanaged",
+		p.Count(ActionCreate), p.Count(ActionUpdate), p.Count(ActionDelete), p.Count(ActionNone), p.Count(ActionKeep))
+}
+
+// ReadDesiredState reads the API providers and key value maps from JSON files in the directories apiProviders and keyValueMaps
+// and the unpacked API proxy bundles from the directory apiProxies below the source directory. The files of an API proxy are
+// stored in the directory apiProxies/<name> without the top level directory of the proxy archive.
+func ReadDesiredState(sourceDirectory string) ([]DesiredEntity, error) {
+	entities := []DesiredEntity{}
+	for _, kind := range []string{KindAPIProvider, KindKeyValueMap} {
+		files, err := filepath.Glob(filepath.Join(sourceDirectory, stateDirectories[kind], "*.json"))
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(files)
+		for _, file := range files {
+			content, err := os.ReadFile(file)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to read %v", file)
+			}
+			definition := map[string]interface{}{}
+			if err := json.Unmarshal(content, &definition); err != nil {
+				return nil, errors.Wrapf(err, "invalid JSON content in %v", file)
+			}
+			name, _ := definition["name"].(string)
+			if len(name) == 0 {
+				name = strings.TrimSuffix(filepath.Base(file), ".json")
+				definition["name"] = name
+			}
+			entities = append(entities, DesiredEntity{Kind: kind, Name: name, Definition: definition})
+		}
+	}
+
+	proxyDirectory := filepath.Join(sourceDirectory, stateDirectories[KindAPIProxy])
+	proxies, err := os.ReadDir(proxyDirectory)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, errors.Wrapf(err, "failed to read %v", proxyDirectory)
+	}
+	for _, proxy := range proxies {
+		if !proxy.IsDir() {
+			continue
+		}
+		files, err := cpi.ReadArtifactDirectory(filepath.Join(proxyDirectory, proxy.Name()))
+		if err != nil {
+			return nil, err
+		}
+		entities = append(entities, DesiredEntity{Kind: KindAPIProxy, Name: proxy.Name(), Files: files})
+	}
+
+	seen := map[string]bool{}
+	for _, entity := range entities {
+		key := entity.Kind + "/" + entity.Name
+		if seen[key] {
+			return nil, errors.Errorf("%v %v is defined more than once", entity.Kind, entity.Name)
+		}
+		seen[key] = true
+	}
+	return entities, nil
+}
+
+// IsManaged returns whether the name matches one of the patterns of managed entities
+func IsManaged(name string, managedPatterns []string) bool {
+	for _, pattern := range managedPatterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// CreatePlan compares the desired state with the tenant. Entities which are not part of the desired state
+// are only deleted if their name matches one of the managed patterns.
+func (apim *Bundle) CreatePlan(desired []DesiredEntity, managedPatterns []string) (Plan, error) {
+	plan := Plan{Changes: []PlannedChange{}}
+	for _, kind := range Kinds {
+		actual, err := apim.ListEntities(kind)
+		if err != nil {
+			return plan, err
+		}
+		for i := range desired {
+			entity := &desired[i]
+			if entity.Kind != kind {
+				continue
+			}
+			change := PlannedChange{Kind: kind, Name: entity.Name, Action: ActionCreate, desired: entity}
+			if current, ok := actual[entity.Name]; ok {
+				delete(actual, entity.Name)
+				inSync, err := apim.inDesiredState(*entity, current)
+				if err != nil {
+					return plan, err
+				}
+				change.Action = ActionUpdate
+				if inSync {
+					change.Action = ActionNone
+				}
+			}
+			plan.Changes = append(plan.Changes, change)
+		}
+
+		names := make([]string, 0, len(actual))
+		for name := range actual {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			action := ActionKeep
+			if IsManaged(name, managedPatterns) {
+				action = ActionDelete
+			}
+			plan.Changes = append(plan.Changes, PlannedChange{Kind: kind, Name: name, Action: action})
+		}
+	}
+	return plan, nil
+}
+
+// ApplyPlan executes the changes of the plan. Deletions are executed in reverse order of the kinds,
+// so that API proxies are deleted before the API providers they refer to.
+func (apim *Bundle) ApplyPlan(plan Plan) error {
+	for _, change := range plan.Changes {
+		if change.Action != ActionCreate && change.Action != ActionUpdate {
+			continue
+		}
+		if err := apim.apply(change); err != nil {
+			return err
+		}
+		log.Entry().Infof("%v %v: %v", change.Kind, change.Name, change.Action)
+	}
+	for i := len(plan.Changes) - 1; i >= 0; i-- {
+		change := plan.Changes[i]
+		if change.Action != ActionDelete {
+			continue
+		}
+		if err := apim.DeleteEntity(change.Kind, change.Name); err != nil {
+			return err
+		}
+		log.Entry().Infof("%v %v: deleted", change.Kind, change.Name)
+	}
+	return nil
+}
+
+func (apim *Bundle) apply(change PlannedChange) error {
+	if change.desired == nil {
+		return errors.Errorf("no desired state for %v %v", change.Kind, change.Name)
+	}
+	if change.Kind == KindAPIProxy {
+		files := cpi.ArtifactFiles{}
+		for file, content := range change.desired.Files {
+			files[change.Name+"/"+file] = content
+		}
+		content, err := cpi.CreateArtifactArchive(files)
+		if err != nil {
+			return errors.Wrapf(err, "failed to pack %v %v", change.Kind, change.Name)
+		}
+		return apim.UploadAPIProxy(change.Name, content)
+	}
+	if change.Action == ActionCreate {
+		return apim.CreateEntity(change.Kind, change.desired.Definition)
+	}
+	return apim.UpdateEntity(change.Kind, change.desired.Definition)
+}
+
+func (apim *Bundle) inDesiredState(desired DesiredEntity, current map[string]interface{}) (bool, error) {
+	if desired.Kind != KindAPIProxy {
+		return ContainsJSON(current, desired.Definition), nil
+	}
+	content, err := apim.DownloadAPIProxy(desired.Name)
+	if err != nil {
+		return false, err
+	}
+	files, err := cpi.ReadArtifactArchive(content)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to unpack %v %v", desired.Kind, desired.Name)
+	}
+	// the files of the archive are located in a directory with the name of the API proxy
+	proxyFiles := cpi.ArtifactFiles{}
+	for file, content := range files {
+		proxyFiles[strings.TrimPrefix(file, desired.Name+"/")] = content
+	}
+	return proxyFiles.Digest() == desired.Files.Digest(), nil
+}
+
+// ContainsJSON returns whether all values of the expected JSON are contained in the actual JSON. Properties which are only
+// contained in the actual JSON, e.g. administrative data of the tenant, are ignored. Expanded OData navigation properties
+// in the format {"results": [...]} are compared with plain arrays.
+func ContainsJSON(actual, expected interface{}) bool {
+	switch expectedValue := expected.(type) {
+	case map[string]interface{}:
+		actualValue, ok := actual.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for key, value := range expectedValue {
+			if !ContainsJSON(actualValue[key], value) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		if results, ok := actual.(map[string]interface{}); ok {
+			actual = results["results"]
+		}
+		actualValue, ok := actual.([]interface{})
+		if !ok || len(actualValue) != len(expectedValue) {
+			return false
+		}
+		for i := range expectedValue {
+			if !ContainsJSON(actualValue[i], expectedValue[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(actual, expected)
+	}
+}
@@ -0,0 +1,155 @@
+//go:build unit
+// +build unit
+
+package apim_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/apim"
+	"github.com/SAP/jenkins-library/pkg/apim/mocks"
+	"github.com/SAP/jenkins-library/pkg/cpi"
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeState(t *testing.T, dir string, files map[string]string) {
+	for file, content := range files {
+		filePath := filepath.Join(dir, filepath.FromSlash(file))
+		require.NoError(t, os.MkdirAll(filepath.Dir(filePath), 0755))
+		require.NoError(t, os.WriteFile(filePath, []byte(content), 0644))
+	}
+}
+
+func newBundle(t *testing.T, server *mocks.Server) *apim.Bundle {
+	bundle := &apim.Bundle{APIServiceKey: server.ServiceKey(), Client: &piperhttp.Client{}}
+	require.NoError(t, bundle.InitAPIM())
+	return bundle
+}
+
+func proxyArchive(t *testing.T, name, definition string) []byte {
+	content, err := cpi.CreateArtifactArchive(cpi.ArtifactFiles{name + "/APIProxy/" + name + ".xml": []byte(definition)})
+	require.NoError(t, err)
+	return content
+}
+
+func TestReadDesiredState(t *testing.T) {
+	t.Run("entities of all kinds", func(t *testing.T) {
+		dir := t.TempDir()
+		writeState(t, dir, map[string]string{
+			"apiProviders/backend.json":      `{"name": "Backend", "destinationType": "Internet"}`,
+			"keyValueMaps/settings.json":     `{"scope": "ENV"}`,
+			"apiProxies/Orders/APIProxy/a.x": "<proxy/>",
+		})
+
+		entities, err := apim.ReadDesiredState(dir)
+
+		require.NoError(t, err)
+		if assert.Len(t, entities, 3) {
+			assert.Equal(t, "Backend", entities[0].Name)
+			assert.Equal(t, apim.KindAPIProvider, entities[0].Kind)
+			assert.Equal(t, "settings", entities[1].Name)
+			assert.Equal(t, "settings", entities[1].Definition["name"])
+			assert.Equal(t, apim.KindAPIProxy, entities[2].Kind)
+			assert.Equal(t, "<proxy/>", string(entities[2].Files["APIProxy/a.x"]))
+		}
+	})
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		dir := t.TempDir()
+		writeState(t, dir, map[string]string{"apiProviders/backend.json": `{`})
+
+		_, err := apim.ReadDesiredState(dir)
+
+		assert.ErrorContains(t, err, "invalid JSON content in")
+	})
+
+	t.Run("duplicate entity", func(t *testing.T) {
+		dir := t.TempDir()
+		writeState(t, dir, map[string]string{
+			"apiProviders/a.json": `{"name": "Backend"}`,
+			"apiProviders/b.json": `{"name": "Backend"}`,
+		})
+
+		_, err := apim.ReadDesiredState(dir)
+
+		assert.EqualError(t, err, "APIProvider Backend is defined more than once")
+	})
+}
+
+func TestPlanAndApply(t *testing.T) {
+	server := mocks.NewServer()
+	t.Cleanup(server.Close)
+	server.AddEntity("APIProviders", map[string]interface{}{"name": "Backend", "destinationType": "Internet", "host": "old.example.com"})
+	server.AddEntity("APIProviders", map[string]interface{}{"name": "team-a-legacy", "host": "legacy.example.com"})
+	server.AddEntity("APIProviders", map[string]interface{}{"name": "Foreign", "host": "foreign.example.com"})
+	server.AddEntity("KeyMapEntries", map[string]interface{}{"name": "settings", "scope": "ENV", "keyMapEntryValues": []interface{}{map[string]interface{}{"name": "timeout", "value": "30"}}})
+	server.AddProxy("Orders", proxyArchive(t, "Orders", "<orders/>"))
+
+	dir := t.TempDir()
+	writeState(t, dir, map[string]string{
+		"apiProviders/backend.json":                 `{"name": "Backend", "destinationType": "Internet", "host": "new.example.com"}`,
+		"apiProviders/payments.json":                `{"name": "team-a-payments", "host": "payments.example.com"}`,
+		"keyValueMaps/settings.json":                `{"name": "settings", "scope": "ENV", "keyMapEntryValues": [{"name": "timeout", "value": "30"}]}`,
+		"apiProxies/Orders/APIProxy/Orders.xml":     "<orders/>",
+		"apiProxies/Invoices/APIProxy/Invoices.xml": "<invoices/>",
+	})
+	desired, err := apim.ReadDesiredState(dir)
+	require.NoError(t, err)
+	bundle := newBundle(t, server)
+
+	plan, err := bundle.CreatePlan(desired, []string{"team-a-*"})
+
+	require.NoError(t, err)
+	actions := map[string]apim.Action{}
+	for _, change := range plan.Changes {
+		actions[change.Kind+"/"+change.Name] = change.Action
+	}
+	assert.Equal(t, map[string]apim.Action{
+		"APIProvider/Backend":         apim.ActionUpdate,
+		"APIProvider/team-a-payments": apim.ActionCreate,
+		"APIProvider/team-a-legacy":   apim.ActionDelete,
+		"APIProvider/Foreign":         apim.ActionKeep,
+		"KeyValueMap/settings":        apim.ActionNone,
+		"APIProxy/Orders":             apim.ActionNone,
+		"APIProxy/Invoices":           apim.ActionCreate,
+	}, actions)
+	assert.Equal(t, "2 to create, 1 to update, 1 to delete, 2 unchanged, 1 unmanaged", plan.Summary())
+	assert.True(t, plan.HasChanges())
+
+	require.NoError(t, bundle.ApplyPlan(plan))
+
+	backend, _ := server.Entity("APIProviders", "Backend")
+	assert.Equal(t, "new.example.com", backend["host"])
+	_, ok := server.Entity("APIProviders", "team-a-payments")
+	assert.True(t, ok)
+	_, ok = server.Entity("APIProviders", "team-a-legacy")
+	assert.False(t, ok)
+	_, ok = server.Entity("APIProviders", "Foreign")
+	assert.True(t, ok, "unmanaged entities must not be deleted")
+	_, ok = server.Entity("APIProxies", "Invoices")
+	assert.True(t, ok)
+
+	plan, err = bundle.CreatePlan(desired, []string{"team-a-*"})
+	require.NoError(t, err)
+	assert.False(t, plan.HasChanges())
+}
+
+func TestContainsJSON(t *testing.T) {
+	actual := map[string]interface{}{
+		"name":       "settings",
+		"__metadata": map[string]interface{}{"uri": "x"},
+		"keyMapEntryValues": map[string]interface{}{"results": []interface{}{
+			map[string]interface{}{"name": "a", "value": "1", "map_name": "settings"},
+		}},
+	}
+
+	assert.True(t, apim.ContainsJSON(actual, map[string]interface{}{"name": "settings"}))
+	assert.True(t, apim.ContainsJSON(actual, map[string]interface{}{"keyMapEntryValues": []interface{}{map[string]interface{}{"name": "a", "value": "1"}}}))
+	assert.False(t, apim.ContainsJSON(actual, map[string]interface{}{"keyMapEntryValues": []interface{}{map[string]interface{}{"name": "a", "value": "2"}}}))
+	assert.False(t, apim.ContainsJSON(actual, map[string]interface{}{"keyMapEntryValues": []interface{}{}}))
+	assert.False(t, apim.ContainsJSON(actual, map[string]interface{}{"scope": "ENV"}))
+}
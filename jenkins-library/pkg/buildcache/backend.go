@@ -0,0 +1,193 @@
+package buildcache
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"cloud.google.com/go/storage"
+	"github.com/SAP/jenkins-library/pkg/gcs"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/pkg/errors"
+)
+
+// Backend stores the cache archives by key
+type Backend interface {
+	// Download writes the archive of the key to the target file, found is false if there is no archive for the key
+	Download(key, targetPath string) (found bool, err error)
+	Upload(sourcePath, key string) error
+}
+
+// Options configure the backend of the cache
+type Options struct {
+	// Backend is one of local, s3 and gcs
+	Backend string
+	// Location is the directory of the local backend or the bucket with an optional path prefix, e.g. my-bucket/piper-cache
+	Location string
+	// Endpoint is the URL of an S3-compatible object store
+	Endpoint string
+	// GCPJsonKeyFilePath is the key file used by the gcs backend
+	GCPJsonKeyFilePath string
+}
+
+// NewBackend creates the backend of the options
+func NewBackend(options Options) (Backend, error) {
+	if len(options.Location) == 0 {
+		return nil, fmt.Errorf("the location of the %v dependency cache is missing", options.Backend)
+	}
+	bucket, prefix, _ := strings.Cut(options.Location, "/")
+	switch options.Backend {
+	case "local":
+		return &LocalBackend{Directory: options.Location}, nil
+	case "s3":
+		cfg, err := config.LoadDefaultConfig(context.Background())
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load the AWS configuration")
+		}
+		client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+			if len(options.Endpoint) > 0 {
+				o.BaseEndpoint = &options.Endpoint
+				// S3-compatible stores usually don't support virtual hosted buckets
+				o.UsePathStyle = true
+			}
+		})
+		return &S3Backend{Client: client, Bucket: bucket, Prefix: prefix}, nil
+	case "gcs":
+		client, err := gcs.NewClient(options.GCPJsonKeyFilePath, "")
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create the GCS client")
+		}
+		return &GCSBackend{Client: client, Bucket: bucket, Prefix: prefix}, nil
+	default:
+		return nil, fmt.Errorf("unsupported dependency cache backend '%v'", options.Backend)
+	}
+}
+
+func archiveName(key string) string {
+	return key + ".tar.gz"
+}
+
+// LocalBackend stores the archives in a directory, e.g. on a volume shared by the build agents
+type LocalBackend struct {
+	Directory string
+}
+
+// Download copies the archive of the key from the directory
+func (b *LocalBackend) Download(key, targetPath string) (bool, error) {
+	source := filepath.Join(b.Directory, archiveName(key))
+	if _, err := os.Stat(source); os.IsNotExist(err) {
+		return false, nil
+	}
+	if _, err := copyFile(source, targetPath); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Upload copies the archive into the directory, the archive is renamed after copying so that
+// concurrent builds never read a partially written archive
+func (b *LocalBackend) Upload(sourcePath, key string) error {
+	if err := os.MkdirAll(b.Directory, 0o755); err != nil {
+		return err
+	}
+	target := filepath.Join(b.Directory, archiveName(key))
+	temporaryTarget := fmt.Sprintf("%v.%v.tmp", target, os.Getpid())
+	if _, err := copyFile(sourcePath, temporaryTarget); err != nil {
+		os.Remove(temporaryTarget)
+		return err
+	}
+	return os.Rename(temporaryTarget, target)
+}
+
+func copyFile(source, target string) (int64, error) {
+	sourceFile, err := os.Open(source)
+	if err != nil {
+		return 0, err
+	}
+	defer sourceFile.Close()
+	targetFile, err := os.Create(target)
+	if err != nil {
+		return 0, err
+	}
+	written, err := io.Copy(targetFile, sourceFile)
+	if closeErr := targetFile.Close(); err == nil {
+		err = closeErr
+	}
+	return written, err
+}
+
+// S3API defines the functions of the S3 client used by the backend
+type S3API interface {
+	GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error)
+	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+}
+
+// S3Backend stores the archives in an AWS S3 or S3-compatible bucket
+type S3Backend struct {
+	Client S3API
+	Bucket string
+	Prefix string
+}
+
+// Download fetches the object of the key
+func (b *S3Backend) Download(key, targetPath string) (bool, error) {
+	objectKey := path.Join(b.Prefix, archiveName(key))
+	output, err := b.Client.GetObject(context.Background(), &s3.GetObjectInput{Bucket: &b.Bucket, Key: &objectKey})
+	var noSuchKey *types.NoSuchKey
+	var notFound *types.NotFound
+	if errors.As(err, &noSuchKey) || errors.As(err, &notFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer output.Body.Close()
+	target, err := os.Create(targetPath)
+	if err != nil {
+		return false, err
+	}
+	_, err = io.Copy(target, output.Body)
+	if closeErr := target.Close(); err == nil {
+		err = closeErr
+	}
+	return err == nil, err
+}
+
+// Upload puts the archive as object of the key
+func (b *S3Backend) Upload(sourcePath, key string) error {
+	source, err := os.Open(sourcePath)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+	objectKey := path.Join(b.Prefix, archiveName(key))
+	_, err = b.Client.PutObject(context.Background(), &s3.PutObjectInput{Bucket: &b.Bucket, Key: &objectKey, Body: source})
+	return err
+}
+
+// GCSBackend stores the archives in a Google Cloud Storage bucket
+type GCSBackend struct {
+	Client gcs.Client
+	Bucket string
+	Prefix string
+}
+
+// Download fetches the object of the key
+func (b *GCSBackend) Download(key, targetPath string) (bool, error) {
+	err := b.Client.DownloadFile(context.Background(), b.Bucket, path.Join(b.Prefix, archiveName(key)), targetPath)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Upload stores the archive as object of the key
+func (b *GCSBackend) Upload(sourcePath, key string) error {
+	return b.Client.UploadFile(context.Background(), b.Bucket, sourcePath, path.Join(b.Prefix, archiveName(key)))
+}
@@ -0,0 +1,106 @@
+//go:build unit
+// +build unit
+
+package buildcache
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"cloud.google.com/go/storage"
+	"github.com/SAP/jenkins-library/pkg/gcs/mocks"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type s3Mock struct {
+	objects map[string][]byte
+}
+
+func (m *s3Mock) GetObject(_ context.Context, params *s3.GetObjectInput, _ ...func(*s3.Options)) (*s3.GetObjectOutput, error) {
+	content, ok := m.objects[*params.Bucket+"/"+*params.Key]
+	if !ok {
+		return nil, &types.NoSuchKey{}
+	}
+	return &s3.GetObjectOutput{Body: io.NopCloser(bytes.NewReader(content))}, nil
+}
+
+func (m *s3Mock) PutObject(_ context.Context, params *s3.PutObjectInput, _ ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	content, err := io.ReadAll(params.Body)
+	if err != nil {
+		return nil, err
+	}
+	m.objects[*params.Bucket+"/"+*params.Key] = content
+	return &s3.PutObjectOutput{}, nil
+}
+
+func TestBackends(t *testing.T) {
+	source := filepath.Join(t.TempDir(), "archive.tar.gz")
+	require.NoError(t, os.WriteFile(source, []byte("archive"), 0o644))
+
+	t.Run("local", func(t *testing.T) {
+		backend, err := NewBackend(Options{Backend: "local", Location: filepath.Join(t.TempDir(), "cache")})
+		require.NoError(t, err)
+		target := filepath.Join(t.TempDir(), "download.tar.gz")
+
+		found, err := backend.Download("npm-123", target)
+		require.NoError(t, err)
+		assert.False(t, found)
+
+		require.NoError(t, backend.Upload(source, "npm-123"))
+		found, err = backend.Download("npm-123", target)
+		require.NoError(t, err)
+		assert.True(t, found)
+		content, _ := os.ReadFile(target)
+		assert.Equal(t, "archive", string(content))
+	})
+
+	t.Run("s3", func(t *testing.T) {
+		client := &s3Mock{objects: map[string][]byte{}}
+		backend := &S3Backend{Client: client, Bucket: "bucket", Prefix: "piper/cache"}
+		target := filepath.Join(t.TempDir(), "download.tar.gz")
+
+		found, err := backend.Download("npm-123", target)
+		require.NoError(t, err)
+		assert.False(t, found)
+
+		require.NoError(t, backend.Upload(source, "npm-123"))
+		assert.Contains(t, client.objects, "bucket/piper/cache/npm-123.tar.gz")
+		found, err = backend.Download("npm-123", target)
+		require.NoError(t, err)
+		assert.True(t, found)
+		content, _ := os.ReadFile(target)
+		assert.Equal(t, "archive", string(content))
+	})
+
+	t.Run("gcs", func(t *testing.T) {
+		client := &mocks.Client{}
+		client.On("DownloadFile", "bucket", "cache/npm-123.tar.gz", "missing.tar.gz").Return(fmt.Errorf("failed to open source file: %w", storage.ErrObjectNotExist))
+		client.On("DownloadFile", "bucket", "cache/npm-123.tar.gz", "download.tar.gz").Return(nil)
+		client.On("UploadFile", "bucket", source, "cache/npm-123.tar.gz").Return(nil)
+		backend := &GCSBackend{Client: client, Bucket: "bucket", Prefix: "cache"}
+
+		found, err := backend.Download("npm-123", "missing.tar.gz")
+		require.NoError(t, err)
+		assert.False(t, found)
+		found, err = backend.Download("npm-123", "download.tar.gz")
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.NoError(t, backend.Upload(source, "npm-123"))
+		client.AssertExpectations(t)
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		_, err := NewBackend(Options{Backend: "local"})
+		assert.EqualError(t, err, "the location of the local dependency cache is missing")
+		_, err = NewBackend(Options{Backend: "ftp", Location: "server"})
+		assert.EqualError(t, err, "unsupported dependency cache backend 'ftp'")
+	})
+}
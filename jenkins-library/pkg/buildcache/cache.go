@@ -0,0 +1,359 @@
+package buildcache
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/pkg/errors"
+)
+
+const (
+	// ResultHit means that the dependencies have been restored from the cache
+	ResultHit = "hit"
+	// ResultMiss means that the cache did not contain the dependencies of the lock files
+	ResultMiss = "miss"
+	// ResultNoLockFiles means that the project does not contain lock files of the tool, therefore nothing is cached
+	ResultNoLockFiles = "noLockFiles"
+)
+
+// Tool describes the lock files and the dependency directories of a build tool
+type Tool struct {
+	Name string
+	// LockFiles are glob patterns of the files which determine the dependencies, e.g. **/go.sum
+	LockFiles []string
+	// Excludes are glob patterns of files which are never considered as lock files, e.g. **/node_modules/**
+	Excludes []string
+	// Directories are the directories which contain the downloaded dependencies
+	Directories []string
+}
+
+// Utils provides the file access needed to compute the cache key
+type Utils interface {
+	Glob(pattern string) (matches []string, err error)
+	FileRead(path string) ([]byte, error)
+}
+
+// Cache restores the dependency directories of a tool before the build and saves them afterwards.
+// The archives are content-addressed: the key is the hash of the lock files of the project.
+type Cache struct {
+	backend Backend
+	tool    Tool
+	utils   Utils
+	key     string
+	result  string
+	size    int64
+}
+
+// New creates a dependency cache of the tool which is stored in the backend
+func New(backend Backend, tool Tool, utils Utils) *Cache {
+	return &Cache{backend: backend, tool: tool, utils: utils}
+}
+
+// Key returns the key of the cache archive, it is empty if the project has no lock files of the tool
+func (c *Cache) Key() string {
+	return c.key
+}
+
+// Result returns whether the cache has been hit, missed or not been used
+func (c *Cache) Result() string {
+	return c.result
+}
+
+// Size returns the size in bytes of the archive which has been restored or saved
+func (c *Cache) Size() int64 {
+	return c.size
+}
+
+// Restore computes the key and extracts the cached archive of the key into the dependency directories
+func (c *Cache) Restore() error {
+	key, err := computeKey(c.tool, c.utils)
+	if err != nil {
+		return errors.Wrap(err, "failed to compute the cache key")
+	}
+	c.key = key
+	if len(key) == 0 {
+		log.Entry().Infof("no lock files of %v found, dependencies are not cached", c.tool.Name)
+		c.result = ResultNoLockFiles
+		return nil
+	}
+
+	archive, err := tempArchivePath()
+	if err != nil {
+		return err
+	}
+	defer os.Remove(archive)
+	found, err := c.backend.Download(key, archive)
+	if err != nil {
+		return errors.Wrapf(err, "failed to download the dependency cache %v", key)
+	}
+	if !found {
+		log.Entry().Infof("dependency cache %v not found", key)
+		c.result = ResultMiss
+		return nil
+	}
+	if err := extract(archive, c.tool.Directories); err != nil {
+		return errors.Wrapf(err, "failed to extract the dependency cache %v", key)
+	}
+	c.result = ResultHit
+	c.size = fileSize(archive)
+	log.Entry().Infof("restored dependency cache %v (%v bytes)", key, c.size)
+	return nil
+}
+
+// Save archives the dependency directories and uploads them, unless the dependencies of the key have already been cached
+func (c *Cache) Save() error {
+	if len(c.key) == 0 || c.result != ResultMiss {
+		return nil
+	}
+	archive, err := tempArchivePath()
+	if err != nil {
+		return err
+	}
+	defer os.Remove(archive)
+	if err := create(archive, c.tool.Directories); err != nil {
+		return errors.Wrapf(err, "failed to archive the dependencies of %v", c.tool.Name)
+	}
+	if err := c.backend.Upload(archive, c.key); err != nil {
+		return errors.Wrapf(err, "failed to upload the dependency cache %v", c.key)
+	}
+	c.size = fileSize(archive)
+	log.Entry().Infof("saved dependency cache %v (%v bytes)", c.key, c.size)
+	return nil
+}
+
+// computeKey hashes the paths and contents of the lock files of the tool together with the platform,
+// since downloaded dependencies may contain platform specific binaries
+func computeKey(tool Tool, utils Utils) (string, error) {
+	files := []string{}
+	for _, pattern := range tool.LockFiles {
+		matches, err := utils.Glob(pattern)
+		if err != nil {
+			return "", errors.Wrapf(err, "invalid lock file pattern %v", pattern)
+		}
+		files = append(files, matches...)
+	}
+	files, err := piperutils.ExcludeFiles(files, tool.Excludes)
+	if err != nil {
+		return "", err
+	}
+	if len(files) == 0 {
+		return "", nil
+	}
+	sort.Strings(files)
+
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%v\n%v/%v\n", tool.Name, runtime.GOOS, runtime.GOARCH)
+	for i, file := range files {
+		if i > 0 && files[i-1] == file {
+			continue
+		}
+		content, err := utils.FileRead(file)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to read lock file %v", file)
+		}
+		fmt.Fprintf(hash, "%v\n%x\n", filepath.ToSlash(file), sha256.Sum256(content))
+	}
+	return fmt.Sprintf("%v-%x", tool.Name, hash.Sum(nil)), nil
+}
+
+func tempArchivePath() (string, error) {
+	file, err := os.CreateTemp("", "buildcache-*.tar.gz")
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create a temporary file for the dependency cache")
+	}
+	file.Close()
+	return file.Name(), nil
+}
+
+func fileSize(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
+
+// create writes a gzipped tar archive of the directories, the entries of a directory are prefixed with its index
+func create(archive string, directories []string) error {
+	file, err := os.Create(archive)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	gzipWriter := gzip.NewWriter(file)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	for i, directory := range directories {
+		if _, err := os.Stat(directory); os.IsNotExist(err) {
+			continue
+		}
+		err := filepath.WalkDir(directory, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			relativePath, err := filepath.Rel(directory, path)
+			if err != nil {
+				return err
+			}
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			link := ""
+			if info.Mode()&fs.ModeSymlink != 0 {
+				if link, err = os.Readlink(path); err != nil {
+					return err
+				}
+			} else if !info.Mode().IsRegular() && !info.IsDir() {
+				return nil
+			}
+			header, err := tar.FileInfoHeader(info, link)
+			if err != nil {
+				return err
+			}
+			header.Name = strconv.Itoa(i) + "/" + filepath.ToSlash(relativePath)
+			if err := tarWriter.WriteHeader(header); err != nil {
+				return err
+			}
+			if !info.Mode().IsRegular() {
+				return nil
+			}
+			content, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer content.Close()
+			_, err = io.Copy(tarWriter, content)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if err := tarWriter.Close(); err != nil {
+		return err
+	}
+	return gzipWriter.Close()
+}
+
+// extract restores the entries of an archive written by create into the directories
+func extract(archive string, directories []string) error {
+	file, err := os.Open(archive)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	gzipReader, err := gzip.NewReader(file)
+	if err != nil {
+		return err
+	}
+	tarReader := tar.NewReader(gzipReader)
+
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		index, relativePath, _ := strings.Cut(header.Name, "/")
+		i, err := strconv.Atoi(index)
+		if err != nil || i < 0 || i >= len(directories) {
+			// the archive has been created for other directories
+			continue
+		}
+		if !filepath.IsLocal(relativePath) {
+			return fmt.Errorf("archive contains invalid path %v", header.Name)
+		}
+		target := filepath.Join(directories[i], relativePath)
+		if err := checkTarget(directories[i], target); err != nil {
+			return err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			// links must not point outside of the restored directory, otherwise later entries could be written through them
+			link := filepath.FromSlash(header.Linkname)
+			if filepath.IsAbs(link) || !isBelow(directories[i], filepath.Join(filepath.Dir(target), link)) {
+				return fmt.Errorf("archive contains link %v with invalid target %v", header.Name, header.Linkname)
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+			if err := os.Symlink(header.Linkname, target); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+			content, err := os.OpenFile(target, os.O_RDWR|os.O_CREATE|os.O_EXCL, header.FileInfo().Mode().Perm())
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(content, tarReader)
+			if closeErr := content.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// checkTarget fails if writing the target would follow a symbolic link below the root and removes an existing link or file at the target itself
+func checkTarget(root, target string) error {
+	relative, err := filepath.Rel(root, filepath.Dir(target))
+	if err != nil {
+		return err
+	}
+	parent := root
+	for _, element := range strings.Split(relative, string(filepath.Separator)) {
+		if element == "." {
+			continue
+		}
+		parent = filepath.Join(parent, element)
+		info, err := os.Lstat(parent)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if info.Mode()&fs.ModeSymlink != 0 {
+			return fmt.Errorf("path %v cannot be restored through the symbolic link %v", target, parent)
+		}
+	}
+	info, err := os.Lstat(target)
+	if os.IsNotExist(err) || (err == nil && info.IsDir()) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return os.Remove(target)
+}
+
+// isBelow checks whether the path is the root or one of its descendants
+func isBelow(root, path string) bool {
+	relative, err := filepath.Rel(root, path)
+	return err == nil && filepath.IsLocal(relative)
+}
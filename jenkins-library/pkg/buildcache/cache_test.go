@@ -0,0 +1,194 @@
+//go:build unit
+// +build unit
+
+package buildcache
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testTool(directories ...string) Tool {
+	return Tool{Name: "npm", LockFiles: []string{"**/package-lock.json"}, Excludes: []string{"**/node_modules/**"}, Directories: directories}
+}
+
+func TestCache(t *testing.T) {
+	backend := &LocalBackend{Directory: filepath.Join(t.TempDir(), "cache")}
+	utils := &mock.FilesMock{}
+	utils.AddFile("package-lock.json", []byte(`{"lockfileVersion": 3}`))
+	dependencies := filepath.Join(t.TempDir(), ".npm")
+	require.NoError(t, os.MkdirAll(filepath.Join(dependencies, "_cacache", "index"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dependencies, "_cacache", "index", "entry"), []byte("cached"), 0o644))
+	require.NoError(t, os.Symlink("index/entry", filepath.Join(dependencies, "_cacache", "link")))
+
+	t.Run("first build misses and saves the dependencies", func(t *testing.T) {
+		cache := New(backend, testTool(dependencies), utils)
+
+		require.NoError(t, cache.Restore())
+		require.NoError(t, cache.Save())
+
+		assert.Equal(t, ResultMiss, cache.Result())
+		assert.Regexp(t, "^npm-[0-9a-f]{64}$", cache.Key())
+		assert.FileExists(t, filepath.Join(backend.Directory, cache.Key()+".tar.gz"))
+		assert.Greater(t, cache.Size(), int64(0))
+	})
+
+	t.Run("following build restores the dependencies", func(t *testing.T) {
+		restored := filepath.Join(t.TempDir(), ".npm")
+		cache := New(backend, testTool(restored), utils)
+
+		require.NoError(t, cache.Restore())
+		require.NoError(t, cache.Save())
+
+		assert.Equal(t, ResultHit, cache.Result())
+		content, err := os.ReadFile(filepath.Join(restored, "_cacache", "index", "entry"))
+		require.NoError(t, err)
+		assert.Equal(t, "cached", string(content))
+		link, err := os.Readlink(filepath.Join(restored, "_cacache", "link"))
+		require.NoError(t, err)
+		assert.Equal(t, "index/entry", link)
+		entries, _ := os.ReadDir(backend.Directory)
+		assert.Len(t, entries, 1)
+	})
+
+	t.Run("changed lock file misses", func(t *testing.T) {
+		changed := &mock.FilesMock{}
+		changed.AddFile("package-lock.json", []byte(`{"lockfileVersion": 3, "packages": {}}`))
+		cache := New(backend, testTool(t.TempDir()), changed)
+
+		require.NoError(t, cache.Restore())
+
+		assert.Equal(t, ResultMiss, cache.Result())
+	})
+
+	t.Run("project without lock files is not cached", func(t *testing.T) {
+		withoutLockFiles := &mock.FilesMock{}
+		withoutLockFiles.AddFile("node_modules/dependency/package-lock.json", []byte(`{}`))
+		cache := New(backend, testTool(dependencies), withoutLockFiles)
+
+		require.NoError(t, cache.Restore())
+		require.NoError(t, cache.Save())
+
+		assert.Equal(t, ResultNoLockFiles, cache.Result())
+		assert.Empty(t, cache.Key())
+	})
+}
+
+func TestComputeKey(t *testing.T) {
+	t.Run("key depends on the paths and contents of the lock files", func(t *testing.T) {
+		utils := &mock.FilesMock{}
+		utils.AddFile("package-lock.json", []byte(`a`))
+		utils.AddFile("packages/ui/package-lock.json", []byte(`b`))
+		key, err := computeKey(testTool(), utils)
+		require.NoError(t, err)
+
+		moved := &mock.FilesMock{}
+		moved.AddFile("package-lock.json", []byte(`a`))
+		moved.AddFile("packages/web/package-lock.json", []byte(`b`))
+		movedKey, err := computeKey(testTool(), moved)
+		require.NoError(t, err)
+
+		assert.NotEqual(t, key, movedKey)
+	})
+
+	t.Run("dependencies are excluded", func(t *testing.T) {
+		utils := &mock.FilesMock{}
+		utils.AddFile("package-lock.json", []byte(`a`))
+		key, err := computeKey(testTool(), utils)
+		require.NoError(t, err)
+
+		utils.AddFile("node_modules/dependency/package-lock.json", []byte(`b`))
+		keyWithDependencies, err := computeKey(testTool(), utils)
+		require.NoError(t, err)
+
+		assert.Equal(t, key, keyWithDependencies)
+	})
+}
+
+func TestExtract(t *testing.T) {
+	t.Run("entries of unknown directories are ignored", func(t *testing.T) {
+		first := t.TempDir()
+		second := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(first, "a"), []byte("a"), 0o644))
+		require.NoError(t, os.WriteFile(filepath.Join(second, "b"), []byte("b"), 0o644))
+		archive := filepath.Join(t.TempDir(), "cache.tar.gz")
+		require.NoError(t, create(archive, []string{first, second}))
+
+		target := t.TempDir()
+		require.NoError(t, extract(archive, []string{target}))
+
+		assert.FileExists(t, filepath.Join(target, "a"))
+		assert.NoFileExists(t, filepath.Join(target, "b"))
+	})
+
+	t.Run("missing directories are skipped", func(t *testing.T) {
+		archive := filepath.Join(t.TempDir(), "cache.tar.gz")
+
+		require.NoError(t, create(archive, []string{filepath.Join(t.TempDir(), "missing")}))
+
+		assert.NoError(t, extract(archive, []string{t.TempDir()}))
+	})
+
+	t.Run("links outside of the directory are rejected", func(t *testing.T) {
+		for _, link := range []string{"/etc/passwd", "../outside", "sub/../../outside"} {
+			archive := writeArchive(t, &tar.Header{Name: "0/link", Typeflag: tar.TypeSymlink, Linkname: link})
+			target := t.TempDir()
+
+			err := extract(archive, []string{target})
+
+			assert.EqualError(t, err, "archive contains link 0/link with invalid target "+link)
+			assert.NoFileExists(t, filepath.Join(target, "link"))
+		}
+	})
+
+	t.Run("files are not written through existing links", func(t *testing.T) {
+		outside := t.TempDir()
+		target := t.TempDir()
+		require.NoError(t, os.Symlink(outside, filepath.Join(target, "deps")))
+		archive := writeArchive(t, &tar.Header{Name: "0/deps/file", Typeflag: tar.TypeReg, Mode: 0o644})
+
+		err := extract(archive, []string{target})
+
+		assert.ErrorContains(t, err, "cannot be restored through the symbolic link")
+		assert.NoFileExists(t, filepath.Join(outside, "file"))
+	})
+
+	t.Run("existing link is replaced by the file", func(t *testing.T) {
+		outside := filepath.Join(t.TempDir(), "file")
+		require.NoError(t, os.WriteFile(outside, []byte("outside"), 0o644))
+		target := t.TempDir()
+		require.NoError(t, os.Symlink(outside, filepath.Join(target, "file")))
+		archive := writeArchive(t, &tar.Header{Name: "0/file", Typeflag: tar.TypeReg, Mode: 0o644})
+
+		require.NoError(t, extract(archive, []string{target}))
+
+		info, err := os.Lstat(filepath.Join(target, "file"))
+		require.NoError(t, err)
+		assert.True(t, info.Mode().IsRegular())
+		content, _ := os.ReadFile(outside)
+		assert.Equal(t, "outside", string(content))
+	})
+}
+
+// writeArchive writes an archive with the entries, regular files are empty
+func writeArchive(t *testing.T, headers ...*tar.Header) string {
+	archive := filepath.Join(t.TempDir(), "cache.tar.gz")
+	file, err := os.Create(archive)
+	require.NoError(t, err)
+	defer file.Close()
+	gzipWriter := gzip.NewWriter(file)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for _, header := range headers {
+		require.NoError(t, tarWriter.WriteHeader(header))
+	}
+	require.NoError(t, tarWriter.Close())
+	require.NoError(t, gzipWriter.Close())
+	return archive
+}
@@ -0,0 +1,116 @@
+package buildcache
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Maven caches the local repository, which is located in m2Path if set
+func Maven(m2Path string) Tool {
+	repository := filepath.Join(homeDirectory(), ".m2", "repository")
+	if len(m2Path) > 0 {
+		repository, _ = filepath.Abs(m2Path)
+	}
+	return Tool{
+		Name:        "maven",
+		LockFiles:   []string{"**/pom.xml"},
+		Excludes:    []string{"**/target/**", "**/node_modules/**"},
+		Directories: []string{repository},
+	}
+}
+
+// Npm caches the package caches of npm, pnpm and yarn
+func Npm() Tool {
+	return Tool{
+		Name:      "npm",
+		LockFiles: []string{"**/package-lock.json", "**/npm-shrinkwrap.json", "**/pnpm-lock.yaml", "**/yarn.lock"},
+		Excludes:  []string{"**/node_modules/**"},
+		Directories: []string{
+			fromEnvironment("npm_config_cache", ".npm"),
+			filepath.Join(homeDirectory(), ".local", "share", "pnpm", "store"),
+			filepath.Join(homeDirectory(), ".cache", "yarn"),
+		},
+	}
+}
+
+// Golang caches the module cache
+func Golang() Tool {
+	moduleCache := os.Getenv("GOMODCACHE")
+	if len(moduleCache) == 0 {
+		goPath := filepath.Join(homeDirectory(), "go")
+		if paths := filepath.SplitList(os.Getenv("GOPATH")); len(paths) > 0 && len(paths[0]) > 0 {
+			goPath = paths[0]
+		}
+		moduleCache = filepath.Join(goPath, "pkg", "mod")
+	}
+	return Tool{
+		Name:        "golang",
+		LockFiles:   []string{"**/go.sum"},
+		Excludes:    []string{"**/vendor/**"},
+		Directories: []string{moduleCache},
+	}
+}
+
+// Python caches the download caches of pip, Poetry and uv
+func Python() Tool {
+	return Tool{
+		Name:      "python",
+		LockFiles: []string{"**/requirements*.txt", "**/poetry.lock", "**/Pipfile.lock", "**/uv.lock"},
+		Excludes:  []string{"**/.venv/**", "**/node_modules/**"},
+		Directories: []string{
+			fromEnvironment("PIP_CACHE_DIR", filepath.Join(".cache", "pip")),
+			fromEnvironment("POETRY_CACHE_DIR", filepath.Join(".cache", "pypoetry")),
+			fromEnvironment("UV_CACHE_DIR", filepath.Join(".cache", "uv")),
+		},
+	}
+}
+
+// Gradle caches the downloaded modules and wrapper distributions. Since dependency locking is optional,
+// the build scripts are part of the key as well.
+func Gradle() Tool {
+	gradleHome := fromEnvironment("GRADLE_USER_HOME", ".gradle")
+	return Tool{
+		Name:        "gradle",
+		LockFiles:   []string{"**/gradle.lockfile", "**/*.gradle", "**/*.gradle.kts", "**/gradle/libs.versions.toml", "**/gradle-wrapper.properties"},
+		Excludes:    []string{"**/build/**", "**/node_modules/**"},
+		Directories: []string{filepath.Join(gradleHome, "caches", "modules-2"), filepath.Join(gradleHome, "wrapper", "dists")},
+	}
+}
+
+// Cargo caches the registry index, the downloaded crates and the git dependencies
+func Cargo() Tool {
+	cargoHome := fromEnvironment("CARGO_HOME", ".cargo")
+	return Tool{
+		Name:        "cargo",
+		LockFiles:   []string{"**/Cargo.lock"},
+		Excludes:    []string{"**/target/**"},
+		Directories: []string{filepath.Join(cargoHome, "registry"), filepath.Join(cargoHome, "git")},
+	}
+}
+
+// Nuget caches the global packages folder of NuGet
+func Nuget() Tool {
+	return Tool{
+		Name:        "nuget",
+		LockFiles:   []string{"**/packages.lock.json", "**/*.csproj", "**/*.fsproj", "**/*.vbproj", "**/Directory.Packages.props"},
+		Excludes:    []string{"**/bin/**", "**/obj/**"},
+		Directories: []string{fromEnvironment("NUGET_PACKAGES", filepath.Join(".nuget", "packages"))},
+	}
+}
+
+// fromEnvironment returns the directory of the environment variable, or the directory relative to the home directory
+func fromEnvironment(variable, homeRelativePath string) string {
+	if directory := strings.TrimSpace(os.Getenv(variable)); len(directory) > 0 {
+		return directory
+	}
+	return filepath.Join(homeDirectory(), homeRelativePath)
+}
+
+func homeDirectory() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "."
+	}
+	return home
+}
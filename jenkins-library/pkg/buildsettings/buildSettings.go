@@ -10,6 +10,8 @@ import (
 )
 
 type BuildSettings struct {
+	CargoBuild         []BuildOptions `json:"cargoBuild,omitempty"`
+	DotnetBuild        []BuildOptions `json:"dotnetBuild,omitempty"`
 	GolangBuild        []BuildOptions `json:"golangBuild,omitempty"`
 	GradleExecuteBuild []BuildOptions `json:"gradleExecuteBuild,omitempty"`
 	HelmExecute        []BuildOptions `json:"helmExecute,omitempty"`
@@ -78,6 +80,14 @@ func CreateBuildSettingsInfo(config *BuildOptions, buildTool string) (string, er
 		settings = append(settings, currentBuildSettingsInfo)
 		var err error
 		switch buildTool {
+		case "cargoBuild":
+			jsonResult, err = json.Marshal(BuildSettings{
+				CargoBuild: settings,
+			})
+		case "dotnetBuild":
+			jsonResult, err = json.Marshal(BuildSettings{
+				DotnetBuild: settings,
+			})
 		case "golangBuild":
 			jsonResult, err = json.Marshal(BuildSettings{
 				GolangBuild: settings,
@@ -14,6 +14,16 @@ func TestCreateBuildSettingsInfo(t *testing.T) {
 			buildTool string
 			expected  string
 		}{
+			{
+				config:    BuildOptions{CreateBOM: true, DockerImage: "rust:1"},
+				buildTool: "cargoBuild",
+				expected:  "{\"cargoBuild\":[{\"createBOM\":true,\"dockerImage\":\"rust:1\"}]}",
+			},
+			{
+				config:    BuildOptions{Publish: true, DockerImage: "mcr.microsoft.com/dotnet/sdk:8.0"},
+				buildTool: "dotnetBuild",
+				expected:  "{\"dotnetBuild\":[{\"publish\":true,\"dockerImage\":\"mcr.microsoft.com/dotnet/sdk:8.0\"}]}",
+			},
 			{
 				config:    BuildOptions{CreateBOM: true},
 				buildTool: "golangBuild",
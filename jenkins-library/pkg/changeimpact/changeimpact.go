@@ -0,0 +1,132 @@
+package changeimpact
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/bmatcuk/doublestar"
+)
+
+// Module is a unit of a monorepo which is built, tested or scanned on its own
+type Module struct {
+	// Name identifies the module within its build tool, e.g. the npm package name or groupId:artifactId of a Maven module
+	Name string
+	// Path is the directory of the module relative to the repository root, using forward slashes
+	Path string
+	// Dependencies are the names of the modules of the repository the module depends on
+	Dependencies []string
+}
+
+// Utils provides the file access needed to detect the modules
+type Utils interface {
+	FileExists(path string) (bool, error)
+	FileRead(path string) ([]byte, error)
+	Glob(pattern string) (matches []string, err error)
+}
+
+// Affected returns the modules which contain a changed file and all modules which directly or transitively depend on them.
+// A changed file which belongs to no module or matches one of the global patterns, e.g. a shared lock file, affects all modules.
+func Affected(modules []Module, changedFiles []string, globalPatterns []string) []Module {
+	affected := map[string]bool{}
+	for _, file := range changedFiles {
+		file = filepath.ToSlash(file)
+		if matchesAny(file, globalPatterns) {
+			return modules
+		}
+		owner := owningModule(modules, file)
+		if owner == nil {
+			return modules
+		}
+		affected[owner.Name] = true
+	}
+
+	dependents := map[string][]string{}
+	for _, module := range modules {
+		for _, dependency := range module.Dependencies {
+			dependents[dependency] = append(dependents[dependency], module.Name)
+		}
+	}
+	queue := make([]string, 0, len(affected))
+	for name := range affected {
+		queue = append(queue, name)
+	}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		for _, dependent := range dependents[name] {
+			if !affected[dependent] {
+				affected[dependent] = true
+				queue = append(queue, dependent)
+			}
+		}
+	}
+
+	result := []Module{}
+	for _, module := range modules {
+		if affected[module.Name] {
+			result = append(result, module)
+		}
+	}
+	return result
+}
+
+// owningModule returns the module with the most specific directory containing the file
+func owningModule(modules []Module, file string) *Module {
+	var owner *Module
+	for i, module := range modules {
+		if !containsFile(module.Path, file) {
+			continue
+		}
+		if owner == nil || len(module.Path) > len(owner.Path) {
+			owner = &modules[i]
+		}
+	}
+	return owner
+}
+
+func containsFile(directory, file string) bool {
+	directory = path.Clean(directory)
+	return directory == "." || strings.HasPrefix(file, directory+"/")
+}
+
+func matchesAny(file string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := doublestar.Match(pattern, file); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// Names returns the names of the modules
+func Names(modules []Module) []string {
+	names := make([]string, 0, len(modules))
+	for _, module := range modules {
+		names = append(names, module.Name)
+	}
+	return names
+}
+
+// modulePath returns the slash separated directory of a descriptor file
+func modulePath(descriptor string) string {
+	return path.Dir(filepath.ToSlash(descriptor))
+}
+
+// withinRepository removes the dependencies on modules which are not part of the repository
+func withinRepository(modules []Module) []Module {
+	names := map[string]bool{}
+	for _, module := range modules {
+		names[module.Name] = true
+	}
+	for i, module := range modules {
+		dependencies := []string{}
+		for _, dependency := range module.Dependencies {
+			if names[dependency] && dependency != module.Name {
+				dependencies = append(dependencies, dependency)
+			}
+		}
+		modules[i].Dependencies = dependencies
+	}
+	return modules
+}
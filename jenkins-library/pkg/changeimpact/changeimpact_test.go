@@ -0,0 +1,158 @@
+//go:build unit
+// +build unit
+
+package changeimpact
+
+import (
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAffected(t *testing.T) {
+	modules := []Module{
+		{Name: "root", Path: "."},
+		{Name: "core", Path: "packages/core"},
+		{Name: "ui", Path: "packages/ui", Dependencies: []string{"core"}},
+		{Name: "app", Path: "packages/app", Dependencies: []string{"ui"}},
+		{Name: "docs", Path: "packages/docs"},
+	}
+	globalPatterns := []string{"package-lock.json"}
+
+	t.Run("dependents are affected transitively", func(t *testing.T) {
+		affected := Affected(modules, []string{"packages/core/src/index.js"}, globalPatterns)
+
+		assert.Equal(t, []string{"core", "ui", "app"}, Names(affected))
+	})
+
+	t.Run("most specific module owns the file", func(t *testing.T) {
+		affected := Affected(modules, []string{"packages/docs/README.md", "README.md"}, globalPatterns)
+
+		assert.Equal(t, []string{"root", "docs"}, Names(affected))
+	})
+
+	t.Run("global file affects all modules", func(t *testing.T) {
+		affected := Affected(modules, []string{"packages/docs/README.md", "package-lock.json"}, globalPatterns)
+
+		assert.Equal(t, modules, affected)
+	})
+
+	t.Run("file outside of all modules affects all modules", func(t *testing.T) {
+		affected := Affected(modules[1:], []string{"Jenkinsfile"}, globalPatterns)
+
+		assert.Equal(t, modules[1:], affected)
+	})
+
+	t.Run("no changes affect no module", func(t *testing.T) {
+		assert.Empty(t, Affected(modules, []string{}, globalPatterns))
+	})
+
+	t.Run("module path is not a prefix of a sibling", func(t *testing.T) {
+		siblings := []Module{{Name: "ui", Path: "packages/ui"}, {Name: "ui-kit", Path: "packages/ui-kit"}}
+
+		affected := Affected(siblings, []string{"packages/ui-kit/index.js"}, nil)
+
+		assert.Equal(t, []string{"ui-kit"}, Names(affected))
+	})
+}
+
+func TestNpmModules(t *testing.T) {
+	utils := &mock.FilesMock{}
+	utils.AddFile("packages/core/package.json", []byte(`{"name": "@acme/core", "dependencies": {"lodash": "^4.0.0"}}`))
+	utils.AddFile("packages/ui/package.json", []byte(`{"name": "@acme/ui", "peerDependencies": {"@acme/core": "*"}, "devDependencies": {"@acme/ui": "*"}}`))
+
+	modules, err := NpmModules(utils, []string{"packages/core/package.json", "packages/ui/package.json"})
+
+	require.NoError(t, err)
+	assert.Equal(t, []Module{
+		{Name: "@acme/core", Path: "packages/core", Dependencies: []string{}},
+		{Name: "@acme/ui", Path: "packages/ui", Dependencies: []string{"@acme/core"}},
+	}, modules)
+}
+
+func TestMavenModules(t *testing.T) {
+	utils := &mock.FilesMock{}
+	utils.AddFile("pom.xml", []byte(`<project><groupId>com.acme</groupId><artifactId>parent</artifactId>
+		<modules><module>core</module><module>app</module></modules></project>`))
+	utils.AddFile("core/pom.xml", []byte(`<project><parent><groupId>com.acme</groupId><artifactId>parent</artifactId></parent>
+		<artifactId>core</artifactId></project>`))
+	utils.AddFile("app/pom.xml", []byte(`<project><parent><groupId>com.acme</groupId><artifactId>parent</artifactId></parent>
+		<artifactId>app</artifactId><dependencies><dependency><groupId>com.acme</groupId><artifactId>core</artifactId></dependency>
+		<dependency><groupId>org.junit</groupId><artifactId>junit</artifactId></dependency></dependencies></project>`))
+
+	modules, err := MavenModules(utils, "pom.xml")
+
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []Module{
+		{Name: "com.acme:parent", Path: ".", Dependencies: []string{}},
+		{Name: "com.acme:core", Path: "core", Dependencies: []string{"com.acme:parent"}},
+		{Name: "com.acme:app", Path: "app", Dependencies: []string{"com.acme:parent", "com.acme:core"}},
+	}, modules)
+}
+
+func TestGoPackages(t *testing.T) {
+	utils := &mock.FilesMock{}
+	utils.AddFile("go.mod", []byte("module github.com/acme/tool\n\ngo 1.22\n"))
+	utils.AddFile("main.go", []byte("package main\n\nimport \"github.com/acme/tool/pkg/api\"\n"))
+	utils.AddFile("pkg/api/api.go", []byte("package api\n\nimport (\n\t\"fmt\"\n\t\"github.com/acme/tool/pkg/util\"\n)\n"))
+	utils.AddFile("pkg/util/util.go", []byte("package util\n"))
+	utils.AddFile("pkg/util/util_test.go", []byte("package util\n\nimport \"github.com/acme/tool/pkg/testing/fixtures\"\n"))
+	utils.AddFile("pkg/testing/fixtures/fixtures.go", []byte("package fixtures\n"))
+	utils.AddFile("vendor/github.com/other/lib/lib.go", []byte("package lib\n"))
+	utils.AddFile("pkg/api/testdata/sample.go", []byte("not go code"))
+
+	modules, err := GoPackages(utils, "go.mod")
+
+	require.NoError(t, err)
+	assert.Equal(t, []Module{
+		{Name: "github.com/acme/tool", Path: ".", Dependencies: []string{"github.com/acme/tool/pkg/api"}},
+		{Name: "github.com/acme/tool/pkg/api", Path: "pkg/api", Dependencies: []string{"github.com/acme/tool/pkg/util"}},
+		{Name: "github.com/acme/tool/pkg/testing/fixtures", Path: "pkg/testing/fixtures", Dependencies: []string{}},
+		{Name: "github.com/acme/tool/pkg/util", Path: "pkg/util", Dependencies: []string{"github.com/acme/tool/pkg/testing/fixtures"}},
+	}, modules)
+
+	t.Run("missing module path", func(t *testing.T) {
+		utils := &mock.FilesMock{}
+		utils.AddFile("go.mod", []byte("go 1.22\n"))
+
+		_, err := GoPackages(utils, "go.mod")
+
+		assert.EqualError(t, err, "go.mod does not declare a module path")
+	})
+}
+
+func TestMtaModules(t *testing.T) {
+	utils := &mock.FilesMock{}
+	utils.AddFile("mta.yaml", []byte(`
+ID: acme
+modules:
+  - name: srv
+    type: nodejs
+    path: srv
+    provides:
+      - name: srv-api
+    requires:
+      - name: acme-db
+  - name: db
+    type: hdb
+    path: db
+  - name: app
+    type: approuter.nodejs
+    requires:
+      - name: srv-api
+      - name: db
+resources:
+  - name: acme-db
+`))
+
+	modules, err := MtaModules(utils, "mta.yaml")
+
+	require.NoError(t, err)
+	assert.Equal(t, []Module{
+		{Name: "srv", Path: "srv", Dependencies: []string{}},
+		{Name: "db", Path: "db", Dependencies: []string{}},
+		{Name: "app", Path: "app", Dependencies: []string{"srv", "db"}},
+	}, modules)
+}
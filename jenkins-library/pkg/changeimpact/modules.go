@@ -0,0 +1,203 @@
+package changeimpact
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/maven"
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"golang.org/x/mod/modfile"
+)
+
+// NpmModules returns the packages of the package.json files, a package depends on the packages of the repository
+// listed in any of its dependency sections
+func NpmModules(utils Utils, packageJSONFiles []string) ([]Module, error) {
+	modules := []Module{}
+	for _, file := range packageJSONFiles {
+		content, err := utils.FileRead(file)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read %v", file)
+		}
+		var descriptor struct {
+			Name                 string            `json:"name"`
+			Dependencies         map[string]string `json:"dependencies"`
+			DevDependencies      map[string]string `json:"devDependencies"`
+			PeerDependencies     map[string]string `json:"peerDependencies"`
+			OptionalDependencies map[string]string `json:"optionalDependencies"`
+		}
+		if err := json.Unmarshal(content, &descriptor); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse %v", file)
+		}
+		module := Module{Name: descriptor.Name, Path: modulePath(file)}
+		if len(module.Name) == 0 {
+			module.Name = module.Path
+		}
+		for _, dependencies := range []map[string]string{descriptor.Dependencies, descriptor.DevDependencies, descriptor.PeerDependencies, descriptor.OptionalDependencies} {
+			module.Dependencies = append(module.Dependencies, sortedKeys(dependencies)...)
+		}
+		modules = append(modules, module)
+	}
+	return withinRepository(modules), nil
+}
+
+// MavenModules returns the modules of the reactor of the pom file. A module depends on its parent and its dependencies.
+func MavenModules(utils Utils, pomFile string) ([]Module, error) {
+	modules := []Module{}
+	err := maven.VisitAllMavenModules(filepath.Dir(pomFile), utils, nil, func(info maven.ModuleInfo) error {
+		project := info.Project
+		groupID := project.GroupID
+		if len(groupID) == 0 {
+			groupID = project.Parent.GroupID
+		}
+		module := Module{Name: groupID + ":" + project.ArtifactID, Path: modulePath(info.PomXMLPath)}
+		if len(project.Parent.ArtifactID) > 0 {
+			module.Dependencies = append(module.Dependencies, project.Parent.GroupID+":"+project.Parent.ArtifactID)
+		}
+		for _, dependency := range project.Dependencies {
+			module.Dependencies = append(module.Dependencies, dependency.GroupID+":"+dependency.ArtifactID)
+		}
+		modules = append(modules, module)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return withinRepository(modules), nil
+}
+
+// GoPackages returns the packages of the Go module of the go.mod file, a package depends on the packages of the module it imports.
+// The imports of the tests are considered as well, since the tests of a package have to run if an imported package changes.
+func GoPackages(utils Utils, goModFile string) ([]Module, error) {
+	content, err := utils.FileRead(goModFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %v", goModFile)
+	}
+	goModulePath := modfile.ModulePath(content)
+	if len(goModulePath) == 0 {
+		return nil, fmt.Errorf("%v does not declare a module path", goModFile)
+	}
+	root := filepath.Dir(goModFile)
+	files, err := utils.Glob(filepath.Join(root, "**", "*.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	packages := map[string]*Module{}
+	for _, file := range files {
+		relativePath, err := filepath.Rel(root, file)
+		if err != nil {
+			return nil, err
+		}
+		relativeDirectory := path.Dir(filepath.ToSlash(relativePath))
+		if excludedGoDirectory(relativeDirectory) {
+			continue
+		}
+		source, err := utils.FileRead(file)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read %v", file)
+		}
+		parsed, err := parser.ParseFile(token.NewFileSet(), file, source, parser.ImportsOnly)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse %v", file)
+		}
+		importPath := goModulePath
+		if relativeDirectory != "." {
+			importPath = goModulePath + "/" + relativeDirectory
+		}
+		pkg, ok := packages[importPath]
+		if !ok {
+			pkg = &Module{Name: importPath, Path: modulePathOf(root, relativeDirectory)}
+			packages[importPath] = pkg
+		}
+		for _, spec := range parsed.Imports {
+			imported, _ := strconv.Unquote(spec.Path.Value)
+			if imported == goModulePath || strings.HasPrefix(imported, goModulePath+"/") {
+				pkg.Dependencies = append(pkg.Dependencies, imported)
+			}
+		}
+	}
+
+	modules := []Module{}
+	for _, name := range sortedKeys(packages) {
+		modules = append(modules, *packages[name])
+	}
+	return withinRepository(modules), nil
+}
+
+func excludedGoDirectory(directory string) bool {
+	for _, segment := range strings.Split(directory, "/") {
+		if segment == "vendor" || segment == "testdata" || (strings.HasPrefix(segment, ".") && segment != ".") || strings.HasPrefix(segment, "_") {
+			return true
+		}
+	}
+	return false
+}
+
+func modulePathOf(root, relativeDirectory string) string {
+	return path.Clean(path.Join(filepath.ToSlash(root), relativeDirectory))
+}
+
+// MtaModules returns the modules of the mta.yaml file, a module depends on the modules providing what it requires
+func MtaModules(utils Utils, mtaYamlFile string) ([]Module, error) {
+	content, err := utils.FileRead(mtaYamlFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %v", mtaYamlFile)
+	}
+	var descriptor struct {
+		Modules []struct {
+			Name     string `json:"name"`
+			Path     string `json:"path"`
+			Requires []struct {
+				Name string `json:"name"`
+			} `json:"requires"`
+			Provides []struct {
+				Name string `json:"name"`
+			} `json:"provides"`
+		} `json:"modules"`
+	}
+	if err := yaml.Unmarshal(content, &descriptor); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %v", mtaYamlFile)
+	}
+
+	providers := map[string]string{}
+	for _, module := range descriptor.Modules {
+		providers[module.Name] = module.Name
+		for _, provided := range module.Provides {
+			providers[provided.Name] = module.Name
+		}
+	}
+	root := modulePath(mtaYamlFile)
+	modules := []Module{}
+	for _, module := range descriptor.Modules {
+		directory := module.Path
+		if len(directory) == 0 {
+			directory = module.Name
+		}
+		result := Module{Name: module.Name, Path: path.Clean(path.Join(root, filepath.ToSlash(directory)))}
+		for _, required := range module.Requires {
+			// requirements of resources have no provider within the repository
+			if provider, ok := providers[required.Name]; ok {
+				result.Dependencies = append(result.Dependencies, provider)
+			}
+		}
+		modules = append(modules, result)
+	}
+	return withinRepository(modules), nil
+}
+
+func sortedKeys[T any](values map[string]T) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,661 @@
+package cloudfoundry
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/pkg/errors"
+)
+
+// Deployment strategies supported by the Cloud Foundry v3 deployments API
+const (
+	StrategyRolling = "rolling"
+	StrategyCanary  = "canary"
+)
+
+// Deployment states as reported by the Cloud Foundry v3 deployments API
+const (
+	deploymentStatusFinalized = "FINALIZED"
+	deploymentReasonDeployed  = "DEPLOYED"
+	deploymentReasonPaused    = "PAUSED"
+)
+
+// V3Client talks to the Cloud Foundry v3 API in-process instead of wrapping the cf CLI.
+type V3Client struct {
+	APIEndpoint  string
+	PollInterval time.Duration
+	client       piperhttp.Sender
+	token        string
+}
+
+// V3Deployment is the subset of a v3 deployment resource evaluated by piper
+type V3Deployment struct {
+	GUID     string `json:"guid"`
+	Strategy string `json:"strategy"`
+	Status   struct {
+		Value  string `json:"value"`
+		Reason string `json:"reason"`
+	} `json:"status"`
+	Droplet struct {
+		GUID string `json:"guid"`
+	} `json:"droplet"`
+}
+
+// V3ProcessInstance describes the state of a single process instance
+type V3ProcessInstance struct {
+	Index int    `json:"index"`
+	State string `json:"state"`
+}
+
+// DeploymentResult contains the identifiers of a finished v3 deployment
+type DeploymentResult struct {
+	AppGUID        string
+	DropletGUID    string
+	DeploymentGUID string
+	Routes         []string
+}
+
+// V3DeploymentOptions configures a zero-downtime deployment via DeployV3
+type V3DeploymentOptions struct {
+	Org         string
+	Space       string
+	AppName     string
+	Strategy    string
+	AppPath     string
+	Manifest    []byte
+	DockerImage string
+	DockerUser  string
+	DockerPass  string
+	Timeout     time.Duration
+	// VerifyHealth is invoked once the new instances are running (for canary deployments while the deployment is paused).
+	// The routes of the app are provided in the result. A non-nil error cancels the deployment.
+	VerifyHealth func(result DeploymentResult) error
+}
+
+type v3Resource struct {
+	GUID string `json:"guid"`
+}
+
+type v3List struct {
+	Resources []json.RawMessage `json:"resources"`
+}
+
+// NewV3Client creates a client for the Cloud Foundry v3 API
+func NewV3Client(apiEndpoint string, client piperhttp.Sender) *V3Client {
+	return &V3Client{
+		APIEndpoint:  strings.TrimSuffix(apiEndpoint, "/"),
+		PollInterval: 5 * time.Second,
+		client:       client,
+	}
+}
+
+// Login fetches an access token from the UAA linked by the Cloud Foundry API using the password grant of the cf CLI client.
+func (c *V3Client) Login(username, password string) error {
+	var root struct {
+		Links struct {
+			Login struct {
+				Href string `json:"href"`
+			} `json:"login"`
+		} `json:"links"`
+	}
+	if err := c.request(http.MethodGet, c.APIEndpoint+"/", nil, "", &root); err != nil {
+		return errors.Wrap(err, "failed to retrieve Cloud Foundry API root")
+	}
+	if len(root.Links.Login.Href) == 0 {
+		return fmt.Errorf("Cloud Foundry API '%s' does not expose a login endpoint", c.APIEndpoint)
+	}
+
+	form := url.Values{
+		"grant_type": {"password"},
+		"username":   {username},
+		"password":   {password},
+	}
+	header := http.Header{}
+	header.Set("Content-Type", "application/x-www-form-urlencoded")
+	header.Set("Accept", "application/json")
+	// the cf CLI uses the public client 'cf' with an empty secret
+	header.Set("Authorization", "Basic Y2Y6")
+
+	response, err := c.client.SendRequest(http.MethodPost, strings.TrimSuffix(root.Links.Login.Href, "/")+"/oauth/token", strings.NewReader(form.Encode()), header, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to fetch Cloud Foundry access token")
+	}
+	defer response.Body.Close()
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&token); err != nil {
+		return errors.Wrap(err, "failed to parse Cloud Foundry access token")
+	}
+	if len(token.AccessToken) == 0 {
+		return errors.New("no access token returned by Cloud Foundry UAA")
+	}
+	log.RegisterSecret(token.AccessToken)
+	c.token = "bearer " + token.AccessToken
+	return nil
+}
+
+// GetSpaceGUID resolves the GUID of a space within an organization
+func (c *V3Client) GetSpaceGUID(org, space string) (string, error) {
+	orgGUID, err := c.findGUID(fmt.Sprintf("/v3/organizations?names=%s", url.QueryEscape(org)))
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to look up organization '%s'", org)
+	}
+	if len(orgGUID) == 0 {
+		return "", fmt.Errorf("organization '%s' not found", org)
+	}
+	spaceGUID, err := c.findGUID(fmt.Sprintf("/v3/spaces?names=%s&organization_guids=%s", url.QueryEscape(space), orgGUID))
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to look up space '%s'", space)
+	}
+	if len(spaceGUID) == 0 {
+		return "", fmt.Errorf("space '%s' not found in organization '%s'", space, org)
+	}
+	return spaceGUID, nil
+}
+
+// GetAppGUID resolves the GUID of an app within a space. An empty GUID is returned if the app does not exist.
+func (c *V3Client) GetAppGUID(spaceGUID, appName string) (string, error) {
+	return c.findGUID(fmt.Sprintf("/v3/apps?names=%s&space_guids=%s", url.QueryEscape(appName), spaceGUID))
+}
+
+// ApplyManifest applies a manifest to a space and waits until the resulting job has finished
+func (c *V3Client) ApplyManifest(spaceGUID string, manifest []byte, timeout time.Duration) error {
+	response, err := c.send(http.MethodPost, fmt.Sprintf("%s/v3/spaces/%s/actions/apply_manifest", c.APIEndpoint, spaceGUID), bytes.NewReader(manifest), "application/x-yaml")
+	if err != nil {
+		return errors.Wrap(err, "failed to apply manifest")
+	}
+	response.Body.Close()
+	jobURL := response.Header.Get("Location")
+	if len(jobURL) == 0 {
+		return nil
+	}
+	return c.poll(timeout, func() (bool, error) {
+		var job struct {
+			State  string `json:"state"`
+			Errors []struct {
+				Detail string `json:"detail"`
+			} `json:"errors"`
+		}
+		if err := c.request(http.MethodGet, jobURL, nil, "", &job); err != nil {
+			return false, err
+		}
+		switch job.State {
+		case "COMPLETE":
+			return true, nil
+		case "FAILED":
+			details := []string{}
+			for _, e := range job.Errors {
+				details = append(details, e.Detail)
+			}
+			return false, fmt.Errorf("applying manifest failed: %s", strings.Join(details, "; "))
+		}
+		return false, nil
+	})
+}
+
+// CreateApp creates an app. A docker lifecycle is used if docker is true.
+func (c *V3Client) CreateApp(spaceGUID, appName string, docker bool) (string, error) {
+	body := map[string]interface{}{
+		"name":          appName,
+		"relationships": map[string]interface{}{"space": map[string]interface{}{"data": map[string]string{"guid": spaceGUID}}},
+	}
+	if docker {
+		body["lifecycle"] = map[string]interface{}{"type": "docker", "data": map[string]interface{}{}}
+	}
+	var app v3Resource
+	if err := c.request(http.MethodPost, c.APIEndpoint+"/v3/apps", body, "", &app); err != nil {
+		return "", errors.Wrapf(err, "failed to create app '%s'", appName)
+	}
+	return app.GUID, nil
+}
+
+// CreatePackage creates a new bits package or, if dockerImage is set, a docker package for an app
+func (c *V3Client) CreatePackage(appGUID, dockerImage, dockerUser, dockerPass string) (string, error) {
+	body := map[string]interface{}{
+		"type":          "bits",
+		"relationships": map[string]interface{}{"app": map[string]interface{}{"data": map[string]string{"guid": appGUID}}},
+	}
+	if len(dockerImage) > 0 {
+		data := map[string]string{"image": dockerImage}
+		if len(dockerUser) > 0 {
+			data["username"] = dockerUser
+			data["password"] = dockerPass
+		}
+		body["type"] = "docker"
+		body["data"] = data
+	}
+	var pkg v3Resource
+	if err := c.request(http.MethodPost, c.APIEndpoint+"/v3/packages", body, "", &pkg); err != nil {
+		return "", errors.Wrap(err, "failed to create package")
+	}
+	return pkg.GUID, nil
+}
+
+// UploadBits uploads the zipped content of a directory into a bits package and waits until it is processed
+func (c *V3Client) UploadBits(packageGUID, appPath string, timeout time.Duration) error {
+	buf := &bytes.Buffer{}
+	writer := multipart.NewWriter(buf)
+	part, err := writer.CreateFormFile("bits", "app.zip")
+	if err != nil {
+		return err
+	}
+	if err := ZipDirectory(appPath, part); err != nil {
+		return errors.Wrapf(err, "failed to zip application directory '%s'", appPath)
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	response, err := c.send(http.MethodPost, fmt.Sprintf("%s/v3/packages/%s/upload", c.APIEndpoint, packageGUID), buf, writer.FormDataContentType())
+	if err != nil {
+		return errors.Wrap(err, "failed to upload application bits")
+	}
+	response.Body.Close()
+
+	return c.poll(timeout, func() (bool, error) {
+		var pkg struct {
+			State string `json:"state"`
+		}
+		if err := c.request(http.MethodGet, fmt.Sprintf("%s/v3/packages/%s", c.APIEndpoint, packageGUID), nil, "", &pkg); err != nil {
+			return false, err
+		}
+		switch pkg.State {
+		case "READY":
+			return true, nil
+		case "FAILED", "EXPIRED":
+			return false, fmt.Errorf("package '%s' is in state '%s'", packageGUID, pkg.State)
+		}
+		return false, nil
+	})
+}
+
+// StagePackage builds a droplet for a package and returns the droplet GUID once staging succeeded
+func (c *V3Client) StagePackage(packageGUID string, timeout time.Duration) (string, error) {
+	body := map[string]interface{}{"package": map[string]string{"guid": packageGUID}}
+	var build v3Resource
+	if err := c.request(http.MethodPost, c.APIEndpoint+"/v3/builds", body, "", &build); err != nil {
+		return "", errors.Wrap(err, "failed to create build")
+	}
+
+	var dropletGUID string
+	err := c.poll(timeout, func() (bool, error) {
+		var b struct {
+			State   string      `json:"state"`
+			Error   string      `json:"error"`
+			Droplet *v3Resource `json:"droplet"`
+		}
+		if err := c.request(http.MethodGet, fmt.Sprintf("%s/v3/builds/%s", c.APIEndpoint, build.GUID), nil, "", &b); err != nil {
+			return false, err
+		}
+		switch b.State {
+		case "STAGED":
+			if b.Droplet != nil {
+				dropletGUID = b.Droplet.GUID
+			}
+			return true, nil
+		case "FAILED":
+			return false, fmt.Errorf("staging failed: %s", b.Error)
+		}
+		return false, nil
+	})
+	return dropletGUID, err
+}
+
+// CreateDeployment starts a deployment of a droplet with the given strategy
+func (c *V3Client) CreateDeployment(appGUID, dropletGUID, strategy string) (V3Deployment, error) {
+	body := map[string]interface{}{
+		"droplet":       map[string]string{"guid": dropletGUID},
+		"strategy":      strategy,
+		"relationships": map[string]interface{}{"app": map[string]interface{}{"data": map[string]string{"guid": appGUID}}},
+	}
+	var deployment V3Deployment
+	if err := c.request(http.MethodPost, c.APIEndpoint+"/v3/deployments", body, "", &deployment); err != nil {
+		return deployment, errors.Wrap(err, "failed to create deployment")
+	}
+	return deployment, nil
+}
+
+// GetDeployment reads the current state of a deployment
+func (c *V3Client) GetDeployment(deploymentGUID string) (V3Deployment, error) {
+	var deployment V3Deployment
+	err := c.request(http.MethodGet, fmt.Sprintf("%s/v3/deployments/%s", c.APIEndpoint, deploymentGUID), nil, "", &deployment)
+	return deployment, err
+}
+
+// ContinueDeployment continues a paused canary deployment
+func (c *V3Client) ContinueDeployment(deploymentGUID string) error {
+	return c.request(http.MethodPost, fmt.Sprintf("%s/v3/deployments/%s/actions/continue", c.APIEndpoint, deploymentGUID), nil, "", nil)
+}
+
+// CancelDeployment cancels an active deployment and rolls back to the previous droplet, finalized deployments cannot be cancelled
+func (c *V3Client) CancelDeployment(deploymentGUID string) error {
+	return c.request(http.MethodPost, fmt.Sprintf("%s/v3/deployments/%s/actions/cancel", c.APIEndpoint, deploymentGUID), nil, "", nil)
+}
+
+// GetCurrentDropletGUID returns the droplet an app is currently running
+func (c *V3Client) GetCurrentDropletGUID(appGUID string) (string, error) {
+	var droplet v3Resource
+	err := c.request(http.MethodGet, fmt.Sprintf("%s/v3/apps/%s/droplets/current", c.APIEndpoint, appGUID), nil, "", &droplet)
+	return droplet.GUID, err
+}
+
+// RestoreDroplet makes a previous droplet the current droplet of an app again. The droplet is rolled out with a rolling
+// deployment, so the app keeps serving requests while the instances are replaced.
+func (c *V3Client) RestoreDroplet(appGUID, dropletGUID string, timeout time.Duration) error {
+	deployment, err := c.CreateDeployment(appGUID, dropletGUID, StrategyRolling)
+	if err != nil {
+		return err
+	}
+	return c.awaitDeployment(deployment.GUID, DeploymentResult{AppGUID: appGUID}, V3DeploymentOptions{Timeout: timeout})
+}
+
+// GetProcessInstances returns the state of all instances of the web process of an app
+func (c *V3Client) GetProcessInstances(appGUID string) ([]V3ProcessInstance, error) {
+	var stats struct {
+		Resources []V3ProcessInstance `json:"resources"`
+	}
+	err := c.request(http.MethodGet, fmt.Sprintf("%s/v3/apps/%s/processes/web/stats", c.APIEndpoint, appGUID), nil, "", &stats)
+	return stats.Resources, err
+}
+
+// GetAppRoutes returns the URLs of all routes mapped to an app
+func (c *V3Client) GetAppRoutes(appGUID string) ([]string, error) {
+	var routes struct {
+		Resources []struct {
+			URL string `json:"url"`
+		} `json:"resources"`
+	}
+	if err := c.request(http.MethodGet, fmt.Sprintf("%s/v3/apps/%s/routes", c.APIEndpoint, appGUID), nil, "", &routes); err != nil {
+		return nil, err
+	}
+	urls := []string{}
+	for _, r := range routes.Resources {
+		urls = append(urls, r.URL)
+	}
+	return urls, nil
+}
+
+// DeployV3 performs a zero-downtime deployment of an app using the v3 deployments API.
+// The deployment is cancelled if it fails, times out or the health verification fails.
+func (c *V3Client) DeployV3(options V3DeploymentOptions) (DeploymentResult, error) {
+	result := DeploymentResult{}
+	if options.Strategy != StrategyRolling && options.Strategy != StrategyCanary {
+		return result, fmt.Errorf("unsupported deployment strategy '%s', supported values: '%s', '%s'", options.Strategy, StrategyRolling, StrategyCanary)
+	}
+
+	spaceGUID, err := c.GetSpaceGUID(options.Org, options.Space)
+	if err != nil {
+		return result, err
+	}
+
+	if len(options.Manifest) > 0 {
+		log.Entry().Info("Applying manifest")
+		if err := c.ApplyManifest(spaceGUID, options.Manifest, options.Timeout); err != nil {
+			return result, err
+		}
+	}
+
+	result.AppGUID, err = c.GetAppGUID(spaceGUID, options.AppName)
+	if err != nil {
+		return result, errors.Wrapf(err, "failed to look up app '%s'", options.AppName)
+	}
+	if len(result.AppGUID) == 0 {
+		log.Entry().Infof("App '%s' does not exist yet, creating it", options.AppName)
+		result.AppGUID, err = c.CreateApp(spaceGUID, options.AppName, len(options.DockerImage) > 0)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	packageGUID, err := c.CreatePackage(result.AppGUID, options.DockerImage, options.DockerUser, options.DockerPass)
+	if err != nil {
+		return result, err
+	}
+	if len(options.DockerImage) == 0 {
+		log.Entry().Infof("Uploading application bits from '%s'", options.AppPath)
+		if err := c.UploadBits(packageGUID, options.AppPath, options.Timeout); err != nil {
+			return result, err
+		}
+	}
+
+	log.Entry().Info("Staging droplet")
+	result.DropletGUID, err = c.StagePackage(packageGUID, options.Timeout)
+	if err != nil {
+		return result, err
+	}
+
+	// the droplet running before the deployment is restored if the new droplet turns out to be unhealthy
+	previousDropletGUID, err := c.GetCurrentDropletGUID(result.AppGUID)
+	if err != nil {
+		log.Entry().WithError(err).Debugf("No current droplet of app '%s' found", options.AppName)
+	}
+
+	log.Entry().Infof("Starting %s deployment of droplet '%s'", options.Strategy, result.DropletGUID)
+	deployment, err := c.CreateDeployment(result.AppGUID, result.DropletGUID, options.Strategy)
+	if err != nil {
+		return result, err
+	}
+	result.DeploymentGUID = deployment.GUID
+
+	if err := c.awaitDeployment(deployment.GUID, result, options); err != nil {
+		// an active deployment is rolled back by Cloud Foundry when it is cancelled
+		log.Entry().WithError(err).Warnf("Cancelling deployment '%s'", deployment.GUID)
+		if cancelErr := c.CancelDeployment(deployment.GUID); cancelErr != nil {
+			log.Entry().WithError(cancelErr).Errorf("Failed to cancel deployment '%s'", deployment.GUID)
+		}
+		return result, err
+	}
+
+	if err := c.verify(result, options); err != nil {
+		// the deployment is finalized already and cannot be cancelled anymore
+		if len(previousDropletGUID) == 0 {
+			log.Entry().WithError(err).Errorf("Health verification failed, app '%s' has no previous droplet to restore", options.AppName)
+			return result, err
+		}
+		log.Entry().WithError(err).Warnf("Health verification failed, restoring previous droplet '%s'", previousDropletGUID)
+		if restoreErr := c.RestoreDroplet(result.AppGUID, previousDropletGUID, options.Timeout); restoreErr != nil {
+			log.Entry().WithError(restoreErr).Errorf("Failed to restore previous droplet '%s'", previousDropletGUID)
+		}
+		return result, err
+	}
+
+	result.Routes, err = c.GetAppRoutes(result.AppGUID)
+	if err != nil {
+		return result, errors.Wrap(err, "failed to read app routes")
+	}
+	return result, nil
+}
+
+// awaitDeployment waits until the deployment is finalized, a paused canary deployment is continued once the canary is healthy
+func (c *V3Client) awaitDeployment(deploymentGUID string, result DeploymentResult, options V3DeploymentOptions) error {
+	canaryVerified := false
+	return c.poll(options.Timeout, func() (bool, error) {
+		deployment, err := c.GetDeployment(deploymentGUID)
+		if err != nil {
+			return false, err
+		}
+		log.Entry().Debugf("Deployment '%s' status: %s/%s", deploymentGUID, deployment.Status.Value, deployment.Status.Reason)
+
+		if deployment.Status.Reason == deploymentReasonPaused && !canaryVerified {
+			if err := c.verify(result, options); err != nil {
+				return false, errors.Wrap(err, "canary verification failed")
+			}
+			canaryVerified = true
+			log.Entry().Info("Canary instance is healthy, continuing deployment")
+			return false, c.ContinueDeployment(deploymentGUID)
+		}
+
+		if deployment.Status.Value != deploymentStatusFinalized {
+			return false, nil
+		}
+		if deployment.Status.Reason != deploymentReasonDeployed {
+			return false, fmt.Errorf("deployment '%s' finished with reason '%s'", deploymentGUID, deployment.Status.Reason)
+		}
+		return true, nil
+	})
+}
+
+func (c *V3Client) verify(result DeploymentResult, options V3DeploymentOptions) error {
+	instances, err := c.GetProcessInstances(result.AppGUID)
+	if err != nil {
+		return errors.Wrap(err, "failed to read process instances")
+	}
+	running := 0
+	for _, instance := range instances {
+		switch instance.State {
+		case "RUNNING":
+			running++
+		case "CRASHED", "DOWN":
+			return fmt.Errorf("instance %d of app '%s' is in state '%s'", instance.Index, options.AppName, instance.State)
+		}
+	}
+	if running == 0 {
+		return fmt.Errorf("no running instance of app '%s' found", options.AppName)
+	}
+	if options.VerifyHealth != nil {
+		result.Routes, err = c.GetAppRoutes(result.AppGUID)
+		if err != nil {
+			return errors.Wrap(err, "failed to read app routes")
+		}
+		return options.VerifyHealth(result)
+	}
+	return nil
+}
+
+func (c *V3Client) poll(timeout time.Duration, check func() (bool, error)) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		done, err := check()
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+		if timeout > 0 && time.Now().After(deadline) {
+			return fmt.Errorf("timed out after %v", timeout)
+		}
+		time.Sleep(c.PollInterval)
+	}
+}
+
+func (c *V3Client) findGUID(pathAndQuery string) (string, error) {
+	var list v3List
+	if err := c.request(http.MethodGet, c.APIEndpoint+pathAndQuery, nil, "", &list); err != nil {
+		return "", err
+	}
+	if len(list.Resources) == 0 {
+		return "", nil
+	}
+	var resource v3Resource
+	if err := json.Unmarshal(list.Resources[0], &resource); err != nil {
+		return "", err
+	}
+	return resource.GUID, nil
+}
+
+func (c *V3Client) request(method, url string, body interface{}, contentType string, result interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal request body")
+		}
+		reader = bytes.NewReader(b)
+		contentType = "application/json"
+	}
+	response, err := c.send(method, url, reader, contentType)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if result == nil {
+		return nil
+	}
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read response of %s %s", method, url)
+	}
+	if err := json.Unmarshal(data, result); err != nil {
+		return errors.Wrapf(err, "failed to parse response of %s %s", method, url)
+	}
+	return nil
+}
+
+func (c *V3Client) send(method, url string, body io.Reader, contentType string) (*http.Response, error) {
+	header := http.Header{}
+	header.Set("Accept", "application/json")
+	if len(contentType) > 0 {
+		header.Set("Content-Type", contentType)
+	}
+	if len(c.token) > 0 {
+		header.Set("Authorization", c.token)
+	}
+	response, err := c.client.SendRequest(method, url, body, header, nil)
+	if err != nil {
+		if response != nil && response.Body != nil {
+			data, _ := io.ReadAll(response.Body)
+			response.Body.Close()
+			return nil, errors.Wrapf(err, "%s %s failed: %s", method, url, string(data))
+		}
+		return nil, err
+	}
+	return response, nil
+}
+
+// ZipDirectory writes the content of a directory as zip archive. Paths in the archive are relative to the directory.
+func ZipDirectory(dir string, w io.Writer) error {
+	archive := zip.NewWriter(w)
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		if info.IsDir() && (info.Name() == ".git" || info.Name() == ".pipeline") {
+			return filepath.SkipDir
+		}
+		header, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(rel)
+		if info.IsDir() {
+			header.Name += "/"
+		} else {
+			header.Method = zip.Deflate
+		}
+		entry, err := archive.CreateHeader(header)
+		if err != nil || info.IsDir() {
+			return err
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(entry, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return archive.Close()
+}
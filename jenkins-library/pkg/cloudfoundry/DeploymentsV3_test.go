@@ -0,0 +1,254 @@
+//go:build unit
+// +build unit
+
+package cloudfoundry
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeCfAPI struct {
+	sync.Mutex
+	server            *httptest.Server
+	deploymentPolls   int
+	deploymentReasons []string
+	instanceState     string
+	currentDroplet    string
+	deployedDroplets  []string
+	calls             []string
+}
+
+func newFakeCfAPI(deploymentReasons []string) *fakeCfAPI {
+	api := &fakeCfAPI{deploymentReasons: deploymentReasons, instanceState: "RUNNING"}
+	api.server = httptest.NewServer(http.HandlerFunc(api.handle))
+	return api
+}
+
+func (api *fakeCfAPI) handle(w http.ResponseWriter, r *http.Request) {
+	api.Lock()
+	defer api.Unlock()
+	api.calls = append(api.calls, r.Method+" "+r.URL.Path)
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case r.URL.Path == "/":
+		fmt.Fprintf(w, `{"links":{"login":{"href":"%s/uaa"}}}`, api.server.URL)
+	case r.URL.Path == "/uaa/oauth/token":
+		fmt.Fprint(w, `{"access_token":"token","token_type":"bearer"}`)
+	case r.URL.Path == "/v3/organizations":
+		fmt.Fprint(w, `{"resources":[{"guid":"org-guid"}]}`)
+	case r.URL.Path == "/v3/spaces":
+		fmt.Fprint(w, `{"resources":[{"guid":"space-guid"}]}`)
+	case r.URL.Path == "/v3/apps":
+		fmt.Fprint(w, `{"resources":[{"guid":"app-guid"}]}`)
+	case r.URL.Path == "/v3/spaces/space-guid/actions/apply_manifest":
+		w.Header().Set("Location", api.server.URL+"/v3/jobs/job-guid")
+		w.WriteHeader(http.StatusAccepted)
+	case r.URL.Path == "/v3/jobs/job-guid":
+		fmt.Fprint(w, `{"state":"COMPLETE"}`)
+	case r.URL.Path == "/v3/packages":
+		fmt.Fprint(w, `{"guid":"package-guid"}`)
+	case r.URL.Path == "/v3/packages/package-guid/upload":
+		fmt.Fprint(w, `{"guid":"package-guid"}`)
+	case r.URL.Path == "/v3/packages/package-guid":
+		fmt.Fprint(w, `{"state":"READY"}`)
+	case r.URL.Path == "/v3/builds":
+		fmt.Fprint(w, `{"guid":"build-guid"}`)
+	case r.URL.Path == "/v3/builds/build-guid":
+		fmt.Fprint(w, `{"state":"STAGED","droplet":{"guid":"droplet-guid"}}`)
+	case r.URL.Path == "/v3/apps/app-guid/droplets/current" && len(api.currentDroplet) > 0:
+		fmt.Fprintf(w, `{"guid":"%s"}`, api.currentDroplet)
+	case r.URL.Path == "/v3/deployments":
+		body := struct {
+			Strategy string `json:"strategy"`
+			Droplet  struct {
+				GUID string `json:"guid"`
+			} `json:"droplet"`
+		}{}
+		json.NewDecoder(r.Body).Decode(&body)
+		api.deployedDroplets = append(api.deployedDroplets, body.Droplet.GUID)
+		guid := "deployment-guid"
+		if body.Droplet.GUID == api.currentDroplet {
+			guid = "rollback-guid"
+		}
+		fmt.Fprintf(w, `{"guid":"%s","strategy":"%s"}`, guid, body.Strategy)
+	case r.URL.Path == "/v3/deployments/rollback-guid":
+		fmt.Fprint(w, `{"guid":"rollback-guid","status":{"value":"FINALIZED","reason":"DEPLOYED"}}`)
+	case r.URL.Path == "/v3/deployments/deployment-guid":
+		reason := api.deploymentReasons[len(api.deploymentReasons)-1]
+		if api.deploymentPolls < len(api.deploymentReasons) {
+			reason = api.deploymentReasons[api.deploymentPolls]
+		}
+		api.deploymentPolls++
+		value := "ACTIVE"
+		if reason == "DEPLOYED" || reason == "CANCELED" {
+			value = "FINALIZED"
+		}
+		fmt.Fprintf(w, `{"guid":"deployment-guid","status":{"value":"%s","reason":"%s"}}`, value, reason)
+	case strings.HasPrefix(r.URL.Path, "/v3/deployments/deployment-guid/actions/"):
+		fmt.Fprint(w, `{}`)
+	case r.URL.Path == "/v3/apps/app-guid/processes/web/stats":
+		fmt.Fprintf(w, `{"resources":[{"index":0,"state":"%s"}]}`, api.instanceState)
+	case r.URL.Path == "/v3/apps/app-guid/routes":
+		fmt.Fprint(w, `{"resources":[{"url":"my-app.cfapps.example.com"}]}`)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (api *fakeCfAPI) called(call string) bool {
+	for _, c := range api.calls {
+		if c == call {
+			return true
+		}
+	}
+	return false
+}
+
+func newTestV3Client(t *testing.T, api *fakeCfAPI) *V3Client {
+	client := &piperhttp.Client{}
+	client.SetOptions(piperhttp.ClientOptions{MaxRetries: -1, UseDefaultTransport: true})
+	cf := NewV3Client(api.server.URL, client)
+	cf.PollInterval = time.Millisecond
+	assert.NoError(t, cf.Login("user", "password"))
+	return cf
+}
+
+func TestDeployV3(t *testing.T) {
+	appDir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(appDir, "index.js"), []byte("console.log('hello')"), 0o644))
+
+	options := V3DeploymentOptions{
+		Org:      "myOrg",
+		Space:    "mySpace",
+		AppName:  "my-app",
+		AppPath:  appDir,
+		Manifest: []byte("applications:\n- name: my-app\n"),
+		Timeout:  time.Minute,
+	}
+
+	t.Run("rolling deployment", func(t *testing.T) {
+		api := newFakeCfAPI([]string{"DEPLOYING", "DEPLOYED"})
+		defer api.server.Close()
+		cf := newTestV3Client(t, api)
+
+		opts := options
+		opts.Strategy = StrategyRolling
+		result, err := cf.DeployV3(opts)
+
+		assert.NoError(t, err)
+		assert.Equal(t, DeploymentResult{
+			AppGUID:        "app-guid",
+			DropletGUID:    "droplet-guid",
+			DeploymentGUID: "deployment-guid",
+			Routes:         []string{"my-app.cfapps.example.com"},
+		}, result)
+		assert.True(t, api.called("POST /v3/spaces/space-guid/actions/apply_manifest"))
+		assert.True(t, api.called("POST /v3/packages/package-guid/upload"))
+		assert.False(t, api.called("POST /v3/deployments/deployment-guid/actions/cancel"))
+	})
+
+	t.Run("canary deployment is continued after verification", func(t *testing.T) {
+		api := newFakeCfAPI([]string{"DEPLOYING", "PAUSED", "DEPLOYING", "DEPLOYED"})
+		defer api.server.Close()
+		cf := newTestV3Client(t, api)
+
+		verifications := 0
+		opts := options
+		opts.Strategy = StrategyCanary
+		opts.VerifyHealth = func(result DeploymentResult) error {
+			verifications++
+			assert.Equal(t, []string{"my-app.cfapps.example.com"}, result.Routes)
+			return nil
+		}
+		_, err := cf.DeployV3(opts)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, verifications)
+		assert.True(t, api.called("POST /v3/deployments/deployment-guid/actions/continue"))
+	})
+
+	t.Run("canary deployment is cancelled on failed verification", func(t *testing.T) {
+		api := newFakeCfAPI([]string{"PAUSED"})
+		defer api.server.Close()
+		cf := newTestV3Client(t, api)
+
+		opts := options
+		opts.Strategy = StrategyCanary
+		opts.VerifyHealth = func(result DeploymentResult) error {
+			return fmt.Errorf("unhealthy")
+		}
+		result, err := cf.DeployV3(opts)
+
+		assert.EqualError(t, err, "canary verification failed: unhealthy")
+		assert.Equal(t, "droplet-guid", result.DropletGUID)
+		assert.True(t, api.called("POST /v3/deployments/deployment-guid/actions/cancel"))
+		assert.False(t, api.called("POST /v3/deployments/deployment-guid/actions/continue"))
+	})
+
+	t.Run("previous droplet is restored on crashed instances after finalization", func(t *testing.T) {
+		api := newFakeCfAPI([]string{"DEPLOYING", "DEPLOYED"})
+		api.instanceState = "CRASHED"
+		api.currentDroplet = "previous-droplet-guid"
+		defer api.server.Close()
+		cf := newTestV3Client(t, api)
+
+		opts := options
+		opts.Strategy = StrategyRolling
+		_, err := cf.DeployV3(opts)
+
+		assert.EqualError(t, err, "instance 0 of app 'my-app' is in state 'CRASHED'")
+		assert.Equal(t, []string{"droplet-guid", "previous-droplet-guid"}, api.deployedDroplets)
+		assert.True(t, api.called("GET /v3/deployments/rollback-guid"))
+		assert.False(t, api.called("POST /v3/deployments/deployment-guid/actions/cancel"))
+	})
+
+	t.Run("failed health check of a new app without previous droplet", func(t *testing.T) {
+		api := newFakeCfAPI([]string{"DEPLOYED"})
+		defer api.server.Close()
+		cf := newTestV3Client(t, api)
+
+		opts := options
+		opts.Strategy = StrategyRolling
+		opts.VerifyHealth = func(result DeploymentResult) error {
+			return fmt.Errorf("unhealthy")
+		}
+		_, err := cf.DeployV3(opts)
+
+		assert.EqualError(t, err, "unhealthy")
+		assert.Equal(t, []string{"droplet-guid"}, api.deployedDroplets)
+	})
+
+	t.Run("docker image deployment skips upload", func(t *testing.T) {
+		api := newFakeCfAPI([]string{"DEPLOYED"})
+		defer api.server.Close()
+		cf := newTestV3Client(t, api)
+
+		opts := options
+		opts.Strategy = StrategyRolling
+		opts.DockerImage = "repo/image:1.0"
+		_, err := cf.DeployV3(opts)
+
+		assert.NoError(t, err)
+		assert.False(t, api.called("POST /v3/packages/package-guid/upload"))
+	})
+
+	t.Run("unsupported strategy", func(t *testing.T) {
+		cf := NewV3Client("https://api.example.com", &piperhttp.Client{})
+		opts := options
+		opts.Strategy = "blue-green"
+		_, err := cf.DeployV3(opts)
+		assert.EqualError(t, err, "unsupported deployment strategy 'blue-green', supported values: 'rolling', 'canary'")
+	})
+}
@@ -0,0 +1,115 @@
+package containerstructure
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+)
+
+// Config is a test configuration in the format of container-structure-test, see
+// https://github.com/GoogleContainerTools/container-structure-test#command-tests.
+// Command and license tests require a container runtime and are reported as skipped.
+type Config struct {
+	SchemaVersion      string              `json:"schemaVersion"`
+	CommandTests       []namedTest         `json:"commandTests,omitempty"`
+	LicenseTests       []interface{}       `json:"licenseTests,omitempty"`
+	FileExistenceTests []FileExistenceTest `json:"fileExistenceTests,omitempty"`
+	FileContentTests   []FileContentTest   `json:"fileContentTests,omitempty"`
+	MetadataTest       *MetadataTest       `json:"metadataTest,omitempty"`
+	// SizeTest is not part of the format of container-structure-test
+	SizeTest *SizeTest `json:"sizeTest,omitempty"`
+}
+
+type namedTest struct {
+	Name string `json:"name"`
+}
+
+// FileExistenceTest checks the existence and the attributes of a file
+type FileExistenceTest struct {
+	Name        string `json:"name"`
+	Path        string `json:"path"`
+	ShouldExist bool   `json:"shouldExist"`
+	// Permissions in the format of the Unix ls command, e.g. -rwxr-xr-x
+	Permissions string `json:"permissions,omitempty"`
+	UID         *int   `json:"uid,omitempty"`
+	GID         *int   `json:"gid,omitempty"`
+	// IsExecutableBy is one of owner, group, other or any
+	IsExecutableBy string `json:"isExecutableBy,omitempty"`
+}
+
+// FileContentTest checks the content of a file against regular expressions
+type FileContentTest struct {
+	Name             string   `json:"name"`
+	Path             string   `json:"path"`
+	ExpectedContents []string `json:"expectedContents,omitempty"`
+	ExcludedContents []string `json:"excludedContents,omitempty"`
+}
+
+// MetadataTest checks the configuration of the image, empty values are not checked
+type MetadataTest struct {
+	Env              []KeyValue `json:"envVars,omitempty"`
+	Labels           []KeyValue `json:"labels,omitempty"`
+	ExposedPorts     []string   `json:"exposedPorts,omitempty"`
+	UnexposedPorts   []string   `json:"unexposedPorts,omitempty"`
+	Volumes          []string   `json:"volumes,omitempty"`
+	UnmountedVolumes []string   `json:"unmountedVolumes,omitempty"`
+	// Entrypoint and Cmd are compared if defined, an empty list requires an empty value
+	Entrypoint *[]string `json:"entrypoint,omitempty"`
+	Cmd        *[]string `json:"cmd,omitempty"`
+	Workdir    string    `json:"workdir,omitempty"`
+	User       string    `json:"user,omitempty"`
+}
+
+// KeyValue is an environment variable or a label, the value is a regular expression if IsRegex is set
+type KeyValue struct {
+	Key     string `json:"key"`
+	Value   string `json:"value"`
+	IsRegex bool   `json:"isRegex,omitempty"`
+}
+
+// SizeTest limits the size of the image as stored in a registry, i.e. the compressed layers and the configuration.
+// Sizes are given in bytes or with a unit like 200MB or 1.5GiB.
+type SizeTest struct {
+	MaxSize string `json:"maxSize"`
+}
+
+// ParseConfig reads a test configuration in YAML or JSON format
+func ParseConfig(content []byte) (Config, error) {
+	config := Config{}
+	if err := yaml.Unmarshal(content, &config); err != nil {
+		return config, errors.Wrap(err, "failed to parse test configuration")
+	}
+	if len(config.SchemaVersion) > 0 && config.SchemaVersion != "2.0.0" {
+		return config, fmt.Errorf("unsupported schema version '%v' of test configuration, supported: 2.0.0", config.SchemaVersion)
+	}
+	return config, nil
+}
+
+var sizeUnits = []struct {
+	suffix string
+	factor float64
+}{
+	// longer suffixes first since they end with shorter ones
+	{"KiB", 1 << 10}, {"MiB", 1 << 20}, {"GiB", 1 << 30},
+	{"KB", 1e3}, {"MB", 1e6}, {"GB", 1e9},
+	{"B", 1},
+}
+
+// ParseSize returns the number of bytes of a size like 512, 200MB or 1.5GiB
+func ParseSize(size string) (int64, error) {
+	value, factor := strings.TrimSpace(size), 1.0
+	for _, unit := range sizeUnits {
+		if strings.HasSuffix(strings.ToUpper(value), strings.ToUpper(unit.suffix)) {
+			value, factor = strings.TrimSpace(value[:len(value)-len(unit.suffix)]), unit.factor
+			break
+		}
+	}
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil || number < 0 {
+		return 0, fmt.Errorf("invalid size '%v'", size)
+	}
+	return int64(number * factor), nil
+}
@@ -0,0 +1,209 @@
+package containerstructure
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/pkg/errors"
+)
+
+// maxSymlinks limits the number of symbolic links followed when resolving a path
+const maxSymlinks = 40
+
+// LoadImage loads an image without a container runtime. The image is either a tar file as written by
+// `docker save`, `crane pull` or kaniko, an OCI image layout directory or a reference of an image in a registry.
+// The platform selects the image of a multi-arch image.
+func LoadImage(image string, platform v1.Platform, options ...remote.Option) (v1.Image, error) {
+	if info, err := os.Stat(image); err == nil {
+		if info.IsDir() {
+			return imageFromLayout(image, platform)
+		}
+		img, err := tarball.ImageFromPath(image, nil)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to load image from tar file %v", image)
+		}
+		return img, nil
+	}
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return nil, errors.Wrapf(err, "'%v' is neither an existing file nor an image reference", image)
+	}
+	img, err := remote.Image(ref, append(options, remote.WithPlatform(platform))...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load image %v", ref)
+	}
+	return img, nil
+}
+
+func imageFromLayout(dir string, platform v1.Platform) (v1.Image, error) {
+	index, err := layout.ImageIndexFromPath(dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load OCI image layout %v", dir)
+	}
+	for {
+		manifest, err := index.IndexManifest()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read OCI image layout %v", dir)
+		}
+		var descriptor *v1.Descriptor
+		for i, candidate := range manifest.Manifests {
+			if candidate.Platform == nil || candidate.Platform.Satisfies(platform) || len(manifest.Manifests) == 1 {
+				descriptor = &manifest.Manifests[i]
+				break
+			}
+		}
+		if descriptor == nil {
+			return nil, fmt.Errorf("no image for platform %v found in OCI image layout %v", platform.String(), dir)
+		}
+		if descriptor.MediaType.IsImage() {
+			return index.Image(descriptor.Digest)
+		}
+		if !descriptor.MediaType.IsIndex() {
+			return nil, fmt.Errorf("unsupported media type %v in OCI image layout %v", descriptor.MediaType, dir)
+		}
+		// the layout of crane contains the index of a multi-arch image
+		if index, err = index.ImageIndex(descriptor.Digest); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// File is an entry of the file system of an image
+type File struct {
+	Mode     os.FileMode
+	UID      int
+	GID      int
+	Linkname string
+	Content  []byte
+}
+
+// FileSystem is the flattened file system of an image, i.e. the layers are applied including whiteouts
+type FileSystem struct {
+	files map[string]*File
+	// hardLinks maps hard links to their targets
+	hardLinks map[string]string
+}
+
+// ReadFileSystem reads the file system of the image. To keep the memory consumption low the content is only kept for
+// the files of the given paths.
+func ReadFileSystem(image v1.Image, contentPaths []string) (*FileSystem, error) {
+	fs, err := readFileSystem(image, map[string]bool{})
+	if err != nil {
+		return nil, err
+	}
+	if len(contentPaths) == 0 {
+		return fs, nil
+	}
+	// the content paths are resolved first since they may contain symbolic links
+	keepContent := map[string]bool{}
+	for _, p := range contentPaths {
+		if resolved, err := fs.resolve(p); err == nil {
+			keepContent[resolved] = true
+			// the content of a hard link is part of its target
+			if target, ok := fs.hardLinks[resolved]; ok {
+				keepContent[target] = true
+			}
+		}
+	}
+	return readFileSystem(image, keepContent)
+}
+
+func readFileSystem(image v1.Image, keepContent map[string]bool) (*FileSystem, error) {
+	reader := mutate.Extract(image)
+	defer reader.Close()
+
+	fs := &FileSystem{files: map[string]*File{"/": {Mode: os.ModeDir | 0o755}}, hardLinks: map[string]string{}}
+	archive := tar.NewReader(reader)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read file system of image")
+		}
+		p := cleanPath(header.Name)
+		file := &File{Mode: header.FileInfo().Mode(), UID: header.Uid, GID: header.Gid, Linkname: header.Linkname}
+		if header.Typeflag == tar.TypeLink {
+			// hard links share the attributes and the content of their target
+			if target, ok := fs.files[cleanPath(header.Linkname)]; ok {
+				file = target
+				fs.hardLinks[p] = cleanPath(header.Linkname)
+			}
+		} else if header.Typeflag == tar.TypeReg && keepContent[p] {
+			if file.Content, err = io.ReadAll(archive); err != nil {
+				return nil, errors.Wrapf(err, "failed to read %v", p)
+			}
+		}
+		fs.files[p] = file
+		// layers do not necessarily contain entries of all parent directories
+		for dir := path.Dir(p); dir != "/"; dir = path.Dir(dir) {
+			if _, ok := fs.files[dir]; ok {
+				break
+			}
+			fs.files[dir] = &File{Mode: os.ModeDir | 0o755}
+		}
+	}
+	return fs, nil
+}
+
+// Lookup returns the file of a path, symbolic links are followed
+func (fs *FileSystem) Lookup(p string) (*File, error) {
+	resolved, err := fs.resolve(p)
+	if err != nil {
+		return nil, err
+	}
+	return fs.files[resolved], nil
+}
+
+// resolve returns the path of a file with all symbolic links resolved
+func (fs *FileSystem) resolve(p string) (string, error) {
+	components := strings.Split(strings.TrimPrefix(cleanPath(p), "/"), "/")
+	current, links := "/", 0
+	for i := 0; i < len(components); i++ {
+		if len(components[i]) == 0 {
+			continue
+		}
+		next := path.Join(current, components[i])
+		file, ok := fs.files[next]
+		if !ok {
+			return "", os.ErrNotExist
+		}
+		if file.Mode&os.ModeSymlink != 0 {
+			if links++; links > maxSymlinks {
+				return "", fmt.Errorf("too many symbolic links resolving %v", p)
+			}
+			// continue with the target of the link followed by the remaining components
+			target := strings.Split(strings.TrimPrefix(linkTarget(next, file.Linkname), "/"), "/")
+			components = append(target, components[i+1:]...)
+			current, i = "/", -1
+			continue
+		}
+		if i < len(components)-1 && !file.Mode.IsDir() {
+			return "", os.ErrNotExist
+		}
+		current = next
+	}
+	return current, nil
+}
+
+func linkTarget(link, target string) string {
+	if path.IsAbs(target) {
+		return cleanPath(target)
+	}
+	return cleanPath(path.Join(path.Dir(link), target))
+}
+
+func cleanPath(p string) string {
+	return path.Clean("/" + strings.TrimPrefix(p, "./"))
+}
@@ -0,0 +1,264 @@
+package containerstructure
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/pkg/errors"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/testreport"
+)
+
+const (
+	commandTestClass       = "Command Test"
+	licenseTestClass       = "License Test"
+	fileExistenceTestClass = "File Existence Test"
+	fileContentTestClass   = "File Content Test"
+	metadataTestClass      = "Metadata Test"
+	sizeTestClass          = "Size Test"
+)
+
+// Run executes the tests of the configuration against the image, the suite names the test cases in the result
+func Run(image v1.Image, config Config, suite string) ([]testreport.TestCase, error) {
+	contentPaths := []string{}
+	for _, test := range config.FileContentTests {
+		contentPaths = append(contentPaths, test.Path)
+	}
+	var fs *FileSystem
+	if len(config.FileExistenceTests) > 0 || len(config.FileContentTests) > 0 {
+		var err error
+		if fs, err = ReadFileSystem(image, contentPaths); err != nil {
+			return nil, err
+		}
+	}
+
+	tests := []testreport.TestCase{}
+	result := func(class, name string, problems []string) {
+		test := testreport.TestCase{Suite: suite, ClassName: class, Name: name, Status: testreport.StatusPassed, Attempts: 1}
+		if len(problems) > 0 {
+			test.Status, test.Message = testreport.StatusFailed, strings.Join(problems, "; ")
+		}
+		tests = append(tests, test)
+	}
+
+	if len(config.CommandTests) > 0 {
+		log.Entry().Warnf("skipping %v command tests of %v, they require a container runtime", len(config.CommandTests), suite)
+	}
+	for _, test := range config.CommandTests {
+		tests = append(tests, skipped(suite, commandTestClass, test.Name))
+	}
+	if len(config.LicenseTests) > 0 {
+		log.Entry().Warnf("skipping %v license tests of %v, they require a container runtime", len(config.LicenseTests), suite)
+	}
+	for i := range config.LicenseTests {
+		tests = append(tests, skipped(suite, licenseTestClass, fmt.Sprintf("license test %v", i+1)))
+	}
+	for _, test := range config.FileExistenceTests {
+		result(fileExistenceTestClass, test.Name, checkFileExistence(fs, test))
+	}
+	for _, test := range config.FileContentTests {
+		result(fileContentTestClass, test.Name, checkFileContent(fs, test))
+	}
+	if config.MetadataTest != nil {
+		imageConfig, err := image.ConfigFile()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read configuration of image")
+		}
+		result(metadataTestClass, metadataTestClass, checkMetadata(imageConfig.Config, *config.MetadataTest))
+	}
+	if config.SizeTest != nil {
+		problems, err := checkSize(image, *config.SizeTest)
+		if err != nil {
+			return nil, err
+		}
+		result(sizeTestClass, sizeTestClass, problems)
+	}
+	if len(tests) > 0 && testreport.Summarize(tests).Skipped == len(tests) {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, fmt.Errorf("all %v tests of %v require a container runtime and were skipped, use the test driver docker", len(tests), suite)
+	}
+	return tests, nil
+}
+
+func skipped(suite, class, name string) testreport.TestCase {
+	return testreport.TestCase{
+		Suite: suite, ClassName: class, Name: name, Status: testreport.StatusSkipped,
+		Message: fmt.Sprintf("%v requires a container runtime", strings.ToLower(class)),
+	}
+}
+
+var executableBits = map[string]os.FileMode{"owner": 0o100, "group": 0o010, "other": 0o001, "any": 0o111}
+
+func checkFileExistence(fs *FileSystem, test FileExistenceTest) []string {
+	file, err := fs.Lookup(test.Path)
+	if err != nil {
+		if test.ShouldExist {
+			return []string{fmt.Sprintf("file %v does not exist", test.Path)}
+		}
+		return nil
+	}
+	if !test.ShouldExist {
+		return []string{fmt.Sprintf("file %v should not exist", test.Path)}
+	}
+	problems := []string{}
+	if len(test.Permissions) > 0 && file.Mode.String() != test.Permissions {
+		problems = append(problems, fmt.Sprintf("permissions of %v are %v instead of %v", test.Path, file.Mode.String(), test.Permissions))
+	}
+	if test.UID != nil && file.UID != *test.UID {
+		problems = append(problems, fmt.Sprintf("owner of %v is %v instead of %v", test.Path, file.UID, *test.UID))
+	}
+	if test.GID != nil && file.GID != *test.GID {
+		problems = append(problems, fmt.Sprintf("group of %v is %v instead of %v", test.Path, file.GID, *test.GID))
+	}
+	if len(test.IsExecutableBy) > 0 {
+		bits, ok := executableBits[test.IsExecutableBy]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("invalid value '%v' of isExecutableBy, supported: owner, group, other, any", test.IsExecutableBy))
+		} else if file.Mode.Perm()&bits == 0 {
+			problems = append(problems, fmt.Sprintf("%v is not executable by %v", test.Path, test.IsExecutableBy))
+		}
+	}
+	return problems
+}
+
+func checkFileContent(fs *FileSystem, test FileContentTest) []string {
+	file, err := fs.Lookup(test.Path)
+	if err != nil {
+		return []string{fmt.Sprintf("file %v does not exist", test.Path)}
+	}
+	if !file.Mode.IsRegular() {
+		return []string{fmt.Sprintf("%v is not a regular file", test.Path)}
+	}
+	problems := []string{}
+	for _, expected := range test.ExpectedContents {
+		match, err := matches(expected, file.Content)
+		if err != nil {
+			problems = append(problems, err.Error())
+		} else if !match {
+			problems = append(problems, fmt.Sprintf("content of %v does not match '%v'", test.Path, expected))
+		}
+	}
+	for _, excluded := range test.ExcludedContents {
+		match, err := matches(excluded, file.Content)
+		if err != nil {
+			problems = append(problems, err.Error())
+		} else if match {
+			problems = append(problems, fmt.Sprintf("content of %v matches excluded '%v'", test.Path, excluded))
+		}
+	}
+	return problems
+}
+
+func matches(expression string, content []byte) (bool, error) {
+	re, err := regexp.Compile(expression)
+	if err != nil {
+		return false, fmt.Errorf("invalid regular expression '%v': %v", expression, err)
+	}
+	return re.Match(content), nil
+}
+
+func checkMetadata(config v1.Config, test MetadataTest) []string {
+	problems := []string{}
+	env := map[string]string{}
+	for _, variable := range config.Env {
+		key, value, _ := strings.Cut(variable, "=")
+		env[key] = value
+	}
+	problems = append(problems, checkKeyValues("environment variable", env, test.Env)...)
+	problems = append(problems, checkKeyValues("label", config.Labels, test.Labels)...)
+
+	for _, port := range test.ExposedPorts {
+		if !hasPort(config.ExposedPorts, port) {
+			problems = append(problems, fmt.Sprintf("port %v is not exposed", port))
+		}
+	}
+	for _, port := range test.UnexposedPorts {
+		if hasPort(config.ExposedPorts, port) {
+			problems = append(problems, fmt.Sprintf("port %v should not be exposed", port))
+		}
+	}
+	for _, volume := range test.Volumes {
+		if _, ok := config.Volumes[volume]; !ok {
+			problems = append(problems, fmt.Sprintf("volume %v is not defined", volume))
+		}
+	}
+	for _, volume := range test.UnmountedVolumes {
+		if _, ok := config.Volumes[volume]; ok {
+			problems = append(problems, fmt.Sprintf("volume %v should not be defined", volume))
+		}
+	}
+
+	if test.Entrypoint != nil && !equalCommands(config.Entrypoint, *test.Entrypoint) {
+		problems = append(problems, fmt.Sprintf("entrypoint is %q instead of %q", config.Entrypoint, *test.Entrypoint))
+	}
+	if test.Cmd != nil && !equalCommands(config.Cmd, *test.Cmd) {
+		problems = append(problems, fmt.Sprintf("cmd is %q instead of %q", config.Cmd, *test.Cmd))
+	}
+	if len(test.Workdir) > 0 && config.WorkingDir != test.Workdir {
+		problems = append(problems, fmt.Sprintf("working directory is '%v' instead of '%v'", config.WorkingDir, test.Workdir))
+	}
+	if len(test.User) > 0 && config.User != test.User {
+		problems = append(problems, fmt.Sprintf("user is '%v' instead of '%v'", config.User, test.User))
+	}
+	return problems
+}
+
+func checkKeyValues(kind string, actual map[string]string, expected []KeyValue) []string {
+	problems := []string{}
+	for _, pair := range expected {
+		value, ok := actual[pair.Key]
+		switch {
+		case !ok:
+			problems = append(problems, fmt.Sprintf("%v %v is not defined", kind, pair.Key))
+		case pair.IsRegex:
+			if match, err := matches(pair.Value, []byte(value)); err != nil {
+				problems = append(problems, err.Error())
+			} else if !match {
+				problems = append(problems, fmt.Sprintf("%v %v is '%v' and does not match '%v'", kind, pair.Key, value, pair.Value))
+			}
+		case value != pair.Value:
+			problems = append(problems, fmt.Sprintf("%v %v is '%v' instead of '%v'", kind, pair.Key, value, pair.Value))
+		}
+	}
+	return problems
+}
+
+// hasPort checks if the port is exposed, the protocol defaults to tcp
+func hasPort(ports map[string]struct{}, port string) bool {
+	if !strings.Contains(port, "/") {
+		port += "/tcp"
+	}
+	_, ok := ports[port]
+	return ok
+}
+
+func equalCommands(actual, expected []string) bool {
+	if len(actual) == 0 && len(expected) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(actual, expected)
+}
+
+func checkSize(image v1.Image, test SizeTest) ([]string, error) {
+	maxSize, err := ParseSize(test.MaxSize)
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := image.Manifest()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read manifest of image")
+	}
+	size := manifest.Config.Size
+	for _, layer := range manifest.Layers {
+		size += layer.Size
+	}
+	if size > maxSize {
+		return []string{fmt.Sprintf("image size %v bytes exceeds the maximum of %v (%v bytes)", size, test.MaxSize, maxSize)}, nil
+	}
+	return nil, nil
+}
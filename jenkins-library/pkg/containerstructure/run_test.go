@@ -0,0 +1,285 @@
+//go:build unit
+// +build unit
+
+package containerstructure
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/testreport"
+)
+
+func testLayer(t *testing.T, headers ...tar.Header) v1.Layer {
+	var buf bytes.Buffer
+	writer := tar.NewWriter(&buf)
+	for _, header := range headers {
+		header := header
+		content := header.Linkname
+		if header.Typeflag == tar.TypeReg {
+			header.Size, header.Linkname = int64(len(content)), ""
+		} else {
+			content = ""
+		}
+		require.NoError(t, writer.WriteHeader(&header))
+		_, err := writer.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+	layer, err := tarball.LayerFromOpener(func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(buf.Bytes())), nil
+	})
+	require.NoError(t, err)
+	return layer
+}
+
+// regular returns the header of a regular file, the content is passed as link name to keep the test data compact
+func regular(name string, mode int64, content string) tar.Header {
+	return tar.Header{Typeflag: tar.TypeReg, Name: name, Mode: mode, Linkname: content, Uid: 1000, Gid: 1000}
+}
+
+func testImage(t *testing.T) v1.Image {
+	base := testLayer(t,
+		tar.Header{Typeflag: tar.TypeDir, Name: "etc/", Mode: 0o755},
+		regular("etc/os-release", 0o644, "ID=alpine\nVERSION_ID=3.20\n"),
+		regular("etc/secret", 0o600, "password"),
+		tar.Header{Typeflag: tar.TypeSymlink, Name: "usr/lib/os-release", Linkname: "../../etc/os-release"},
+	)
+	app := testLayer(t,
+		regular("app/server", 0o750, "binary"),
+		tar.Header{Typeflag: tar.TypeSymlink, Name: "bin", Linkname: "/app"},
+		// whiteout of a file of the base layer
+		regular("etc/.wh.secret", 0o644, ""),
+	)
+	image, err := mutate.AppendLayers(empty.Image, base, app)
+	require.NoError(t, err)
+	image, err = mutate.Config(image, v1.Config{
+		Entrypoint:   []string{"/app/server"},
+		Env:          []string{"PATH=/usr/bin:/bin", "APP_VERSION=1.2.3"},
+		ExposedPorts: map[string]struct{}{"8080/tcp": {}},
+		Labels:       map[string]string{"org.opencontainers.image.source": "https://github.com/example/app"},
+		User:         "1000",
+		WorkingDir:   "/app",
+	})
+	require.NoError(t, err)
+	return image
+}
+
+func intPointer(i int) *int {
+	return &i
+}
+
+func TestRun(t *testing.T) {
+	image := testImage(t)
+
+	t.Run("successful tests", func(t *testing.T) {
+		config, err := ParseConfig([]byte(`schemaVersion: 2.0.0
+fileExistenceTests:
+  - name: server
+    path: /bin/server
+    shouldExist: true
+    permissions: -rwxr-x---
+    uid: 1000
+    isExecutableBy: group
+  - name: no secret
+    path: /etc/secret
+    shouldExist: false
+fileContentTests:
+  - name: alpine
+    path: /usr/lib/os-release
+    expectedContents: ['ID=alpine']
+    excludedContents: ['ID=debian']
+metadataTest:
+  envVars:
+    - key: APP_VERSION
+      value: '^1\.\d+\.\d+$'
+      isRegex: true
+  labels:
+    - key: org.opencontainers.image.source
+      value: https://github.com/example/app
+  exposedPorts: ["8080"]
+  unexposedPorts: ["22"]
+  entrypoint: [/app/server]
+  cmd: []
+  workdir: /app
+  user: "1000"
+sizeTest:
+  maxSize: 1MB
+commandTests:
+  - name: version
+    command: server
+    args: [--version]
+`))
+		require.NoError(t, err)
+
+		tests, err := Run(image, config, "cst.yaml")
+
+		require.NoError(t, err)
+		summary := testreport.Summarize(tests)
+		assert.Equal(t, testreport.TestSummary{Total: 6, Passed: 5, Skipped: 1}, summary, tests)
+		assert.Equal(t, testreport.TestCase{Suite: "cst.yaml", ClassName: "Command Test", Name: "version", Status: testreport.StatusSkipped, Message: "command test requires a container runtime"}, tests[0])
+	})
+
+	t.Run("skipped tests are reported", func(t *testing.T) {
+		_, hook := test.NewNullLogger()
+		hooks := logrus.LevelHooks{}
+		for level, levelHooks := range logrus.StandardLogger().Hooks {
+			hooks[level] = append([]logrus.Hook{}, levelHooks...)
+		}
+		t.Cleanup(func() { logrus.StandardLogger().ReplaceHooks(hooks) })
+		log.RegisterHook(hook)
+		config := Config{
+			CommandTests: []namedTest{{Name: "version"}, {Name: "help"}},
+			LicenseTests: []interface{}{map[string]interface{}{"debian": true}},
+			MetadataTest: &MetadataTest{Workdir: "/app"},
+		}
+
+		tests, err := Run(image, config, "cst.yaml")
+
+		require.NoError(t, err)
+		assert.Equal(t, testreport.TestSummary{Total: 4, Passed: 1, Skipped: 3}, testreport.Summarize(tests))
+		warnings := []string{}
+		for _, entry := range hook.AllEntries() {
+			if entry.Level == logrus.WarnLevel {
+				warnings = append(warnings, entry.Message)
+			}
+		}
+		assert.Equal(t, []string{
+			"skipping 2 command tests of cst.yaml, they require a container runtime",
+			"skipping 1 license tests of cst.yaml, they require a container runtime",
+		}, warnings)
+	})
+
+	t.Run("all tests skipped", func(t *testing.T) {
+		config := Config{CommandTests: []namedTest{{Name: "version"}}}
+
+		_, err := Run(image, config, "cst.yaml")
+
+		assert.EqualError(t, err, "all 1 tests of cst.yaml require a container runtime and were skipped, use the test driver docker")
+	})
+
+	t.Run("failing tests", func(t *testing.T) {
+		entrypoint := []string{"/bin/sh"}
+		config := Config{
+			FileExistenceTests: []FileExistenceTest{
+				{Name: "missing", Path: "/etc/passwd", ShouldExist: true},
+				{Name: "attributes", Path: "/app/server", ShouldExist: true, Permissions: "-rwxr-xr-x", GID: intPointer(0), IsExecutableBy: "other"},
+			},
+			FileContentTests: []FileContentTest{
+				{Name: "content", Path: "/etc/os-release", ExpectedContents: []string{"ID=debian"}, ExcludedContents: []string{"alpine"}},
+				{Name: "directory", Path: "/etc"},
+			},
+			MetadataTest: &MetadataTest{
+				Env:            []KeyValue{{Key: "APP_VERSION", Value: "2.0.0"}, {Key: "HOME", Value: "/root"}},
+				UnexposedPorts: []string{"8080/tcp"},
+				Entrypoint:     &entrypoint,
+				User:           "root",
+			},
+			SizeTest: &SizeTest{MaxSize: "10B"},
+		}
+
+		tests, err := Run(image, config, "cst.yaml")
+
+		require.NoError(t, err)
+		messages := []string{}
+		for _, test := range tests {
+			assert.Equal(t, testreport.StatusFailed, test.Status, test.Name)
+			messages = append(messages, test.Message)
+		}
+		assert.Equal(t, []string{
+			"file /etc/passwd does not exist",
+			"permissions of /app/server are -rwxr-x--- instead of -rwxr-xr-x; group of /app/server is 1000 instead of 0; /app/server is not executable by other",
+			"content of /etc/os-release does not match 'ID=debian'; content of /etc/os-release matches excluded 'alpine'",
+			"/etc is not a regular file",
+			"environment variable APP_VERSION is '1.2.3' instead of '2.0.0'; environment variable HOME is not defined; port 8080/tcp should not be exposed; entrypoint is [\"/app/server\"] instead of [\"/bin/sh\"]; user is '1000' instead of 'root'",
+		}, messages[:5])
+		assert.Contains(t, messages[5], "exceeds the maximum of 10B (10 bytes)")
+	})
+}
+
+func TestParseConfig(t *testing.T) {
+	t.Run("JSON", func(t *testing.T) {
+		config, err := ParseConfig([]byte(`{"schemaVersion": "2.0.0", "metadataTest": {"workdir": "/app"}}`))
+
+		require.NoError(t, err)
+		assert.Equal(t, "/app", config.MetadataTest.Workdir)
+	})
+
+	t.Run("unsupported schema version", func(t *testing.T) {
+		_, err := ParseConfig([]byte(`schemaVersion: 1.0.0`))
+
+		assert.EqualError(t, err, "unsupported schema version '1.0.0' of test configuration, supported: 2.0.0")
+	})
+}
+
+func TestParseSize(t *testing.T) {
+	for size, expected := range map[string]int64{"512": 512, "200MB": 200000000, "1.5 GiB": 1610612736, "10kib": 10240, "3B": 3} {
+		actual, err := ParseSize(size)
+		require.NoError(t, err, size)
+		assert.Equal(t, expected, actual, size)
+	}
+	_, err := ParseSize("large")
+	assert.EqualError(t, err, "invalid size 'large'")
+}
+
+func TestLoadImage(t *testing.T) {
+	image := testImage(t)
+	digest, err := image.Digest()
+	require.NoError(t, err)
+	platform := v1.Platform{OS: "linux", Architecture: "amd64"}
+
+	t.Run("tar file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "image.tar")
+		require.NoError(t, tarball.WriteToFile(path, name.MustParseReference("example/app:1.0"), image))
+
+		loaded, err := LoadImage(path, platform)
+
+		require.NoError(t, err)
+		fs, err := ReadFileSystem(loaded, []string{"/usr/lib/os-release"})
+		require.NoError(t, err)
+		file, err := fs.Lookup("/usr/lib/os-release")
+		require.NoError(t, err)
+		assert.Equal(t, "ID=alpine\nVERSION_ID=3.20\n", string(file.Content))
+	})
+
+	t.Run("OCI image layout with multi-arch image", func(t *testing.T) {
+		arm, err := mutate.ConfigFile(empty.Image, &v1.ConfigFile{OS: "linux", Architecture: "arm64"})
+		require.NoError(t, err)
+		index := mutate.AppendManifests(empty.Index,
+			mutate.IndexAddendum{Add: arm, Descriptor: v1.Descriptor{Platform: &v1.Platform{OS: "linux", Architecture: "arm64"}}},
+			mutate.IndexAddendum{Add: image, Descriptor: v1.Descriptor{Platform: &platform}},
+		)
+		dir := t.TempDir()
+		imageLayout, err := layout.Write(dir, empty.Index)
+		require.NoError(t, err)
+		require.NoError(t, imageLayout.AppendIndex(index))
+
+		loaded, err := LoadImage(dir, platform)
+
+		require.NoError(t, err)
+		loadedDigest, err := loaded.Digest()
+		require.NoError(t, err)
+		assert.Equal(t, digest, loadedDigest)
+	})
+
+	t.Run("invalid reference", func(t *testing.T) {
+		_, err := LoadImage("Not An Image", platform)
+
+		assert.ErrorContains(t, err, "'Not An Image' is neither an existing file nor an image reference")
+	})
+}
@@ -0,0 +1,97 @@
+package cosign
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/SAP/jenkins-library/pkg/provenance"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+)
+
+// encryptedKey is the password protected private key created by `cosign generate-key-pair`
+type encryptedKey struct {
+	KDF struct {
+		Name   string `json:"name"`
+		Params struct {
+			N int `json:"N"`
+			R int `json:"r"`
+			P int `json:"p"`
+		} `json:"params"`
+		Salt []byte `json:"salt"`
+	} `json:"kdf"`
+	Cipher struct {
+		Name  string `json:"name"`
+		Nonce []byte `json:"nonce"`
+	} `json:"cipher"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// LoadPrivateKey parses a private key created by cosign, which is decrypted with the password,
+// or a PEM encoded, unencrypted ECDSA, Ed25519 or RSA private key.
+func LoadPrivateKey(keyPEM []byte, password string) (crypto.Signer, error) {
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, fmt.Errorf("signing key is not PEM encoded")
+	}
+	if block.Type != "ENCRYPTED SIGSTORE PRIVATE KEY" && block.Type != "ENCRYPTED COSIGN PRIVATE KEY" {
+		return provenance.LoadSigner(keyPEM)
+	}
+
+	var encrypted encryptedKey
+	if err := json.Unmarshal(block.Bytes, &encrypted); err != nil {
+		return nil, errors.Wrap(err, "failed to parse encrypted signing key")
+	}
+	if encrypted.KDF.Name != "scrypt" || encrypted.Cipher.Name != "nacl/secretbox" || len(encrypted.Cipher.Nonce) != 24 {
+		return nil, fmt.Errorf("unsupported encryption of signing key (kdf: '%v', cipher: '%v')", encrypted.KDF.Name, encrypted.Cipher.Name)
+	}
+	secret, err := scrypt.Key([]byte(password), encrypted.KDF.Salt, encrypted.KDF.Params.N, encrypted.KDF.Params.R, encrypted.KDF.Params.P, 32)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to derive the key encryption key")
+	}
+	var key [32]byte
+	var nonce [24]byte
+	copy(key[:], secret)
+	copy(nonce[:], encrypted.Cipher.Nonce)
+	der, ok := secretbox.Open(nil, encrypted.Ciphertext, &nonce, &key)
+	if !ok {
+		return nil, fmt.Errorf("failed to decrypt signing key, the password is wrong")
+	}
+	privateKey, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse signing key")
+	}
+	signer, ok := privateKey.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported signing key")
+	}
+	return signer, nil
+}
+
+// LoadPublicKeys parses all PEM encoded public keys, e.g. the cosign.pub files of the trusted signers
+func LoadPublicKeys(keysPEM []byte) ([]crypto.PublicKey, error) {
+	keys := []crypto.PublicKey{}
+	for {
+		var block *pem.Block
+		block, keysPEM = pem.Decode(keysPEM)
+		if block == nil {
+			break
+		}
+		if block.Type != "PUBLIC KEY" {
+			continue
+		}
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse public key")
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no PEM encoded public key found")
+	}
+	return keys, nil
+}
@@ -0,0 +1,87 @@
+//go:build unit
+// +build unit
+
+package cosign
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+)
+
+// encryptKey creates a private key in the format of `cosign generate-key-pair`
+func encryptKey(t *testing.T, key *ecdsa.PrivateKey, password string) []byte {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	var encrypted encryptedKey
+	encrypted.KDF.Name = "scrypt"
+	encrypted.KDF.Params.N, encrypted.KDF.Params.R, encrypted.KDF.Params.P = 1024, 8, 1
+	encrypted.KDF.Salt = []byte("0123456789abcdef0123456789abcdef")
+	encrypted.Cipher.Name = "nacl/secretbox"
+	encrypted.Cipher.Nonce = []byte("0123456789abcdef01234567")
+	secret, err := scrypt.Key([]byte(password), encrypted.KDF.Salt, 1024, 8, 1, 32)
+	require.NoError(t, err)
+	var secretKey [32]byte
+	var nonce [24]byte
+	copy(secretKey[:], secret)
+	copy(nonce[:], encrypted.Cipher.Nonce)
+	encrypted.Ciphertext = secretbox.Seal(nil, der, &nonce, &secretKey)
+	content, err := json.Marshal(encrypted)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "ENCRYPTED SIGSTORE PRIVATE KEY", Bytes: content})
+}
+
+func TestLoadPrivateKey(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	t.Run("cosign key", func(t *testing.T) {
+		signer, err := LoadPrivateKey(encryptKey(t, key, "secret"), "secret")
+
+		require.NoError(t, err)
+		assert.True(t, key.PublicKey.Equal(signer.Public()))
+	})
+
+	t.Run("wrong password", func(t *testing.T) {
+		_, err := LoadPrivateKey(encryptKey(t, key, "secret"), "guess")
+
+		assert.EqualError(t, err, "failed to decrypt signing key, the password is wrong")
+	})
+
+	t.Run("unencrypted key", func(t *testing.T) {
+		der, _ := x509.MarshalECPrivateKey(key)
+
+		signer, err := LoadPrivateKey(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), "")
+
+		require.NoError(t, err)
+		assert.True(t, key.PublicKey.Equal(signer.Public()))
+	})
+}
+
+func TestLoadPublicKeys(t *testing.T) {
+	first, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	second, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	encode := func(key *ecdsa.PrivateKey) []byte {
+		der, _ := x509.MarshalPKIXPublicKey(&key.PublicKey)
+		return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	}
+
+	keys, err := LoadPublicKeys(append(encode(first), encode(second)...))
+
+	require.NoError(t, err)
+	if assert.Len(t, keys, 2) {
+		assert.True(t, first.PublicKey.Equal(keys[0]))
+		assert.True(t, second.PublicKey.Equal(keys[1]))
+	}
+
+	_, err = LoadPublicKeys([]byte("no key"))
+	assert.EqualError(t, err, "no PEM encoded public key found")
+}
@@ -0,0 +1,192 @@
+package cosign
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/pkg/errors"
+)
+
+const (
+	// SimpleSigningMediaType is the media type of the layers of a cosign signature image
+	SimpleSigningMediaType = "application/vnd.dev.cosign.simplesigning.v1+json"
+	// SignatureAnnotation is the layer annotation holding the base64 encoded signature of the layer
+	SignatureAnnotation = "dev.cosignproject.cosign/signature"
+	signatureType       = "cosign container image signature"
+)
+
+// payload is the simple signing payload cosign signs, see https://github.com/containers/image/blob/main/docs/containers-signature.5.md
+type payload struct {
+	Critical struct {
+		Identity struct {
+			DockerReference string `json:"docker-reference"`
+		} `json:"identity"`
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+	Optional map[string]interface{} `json:"optional"`
+}
+
+// SignatureTag returns the tag cosign stores the signatures of the image at, i.e. sha256-<hex>.sig
+func SignatureTag(image name.Digest) name.Tag {
+	return image.Context().Tag(strings.Replace(image.DigestStr(), ":", "-", 1) + ".sig")
+}
+
+// ResolveDigest returns the reference by digest of an image given by tag or digest
+func ResolveDigest(image string, options ...remote.Option) (name.Digest, error) {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return name.Digest{}, errors.Wrapf(err, "invalid image reference '%v'", image)
+	}
+	if digest, ok := ref.(name.Digest); ok {
+		return digest, nil
+	}
+	descriptor, err := remote.Head(ref, options...)
+	if err != nil {
+		return name.Digest{}, errors.Wrapf(err, "failed to resolve image '%v'", image)
+	}
+	return ref.Context().Digest(descriptor.Digest.String()), nil
+}
+
+// Sign signs the image and pushes the signature in the format of cosign. Existing signatures of the image are kept.
+func Sign(image name.Digest, signer crypto.Signer, annotations map[string]interface{}, options ...remote.Option) (name.Tag, error) {
+	var content payload
+	content.Critical.Identity.DockerReference = image.Context().Name()
+	content.Critical.Image.DockerManifestDigest = image.DigestStr()
+	content.Critical.Type = signatureType
+	content.Optional = annotations
+	message, err := json.Marshal(content)
+	if err != nil {
+		return name.Tag{}, errors.Wrap(err, "failed to marshal signature payload")
+	}
+	signature, err := sign(signer, message)
+	if err != nil {
+		return name.Tag{}, errors.Wrapf(err, "failed to sign image '%v'", image)
+	}
+	encodedSignature := base64.StdEncoding.EncodeToString(signature)
+
+	tag := SignatureTag(image)
+	signatures, err := remote.Image(tag, options...)
+	if isNotFound(err) {
+		signatures = mutate.ConfigMediaType(mutate.MediaType(empty.Image, types.OCIManifestSchema1), types.OCIConfigJSON)
+	} else if err != nil {
+		return name.Tag{}, errors.Wrapf(err, "failed to read the signatures of image '%v'", image)
+	}
+	signatures, err = mutate.Append(signatures, mutate.Addendum{
+		Layer:       static.NewLayer(message, SimpleSigningMediaType),
+		Annotations: map[string]string{SignatureAnnotation: encodedSignature},
+	})
+	if err != nil {
+		return name.Tag{}, errors.Wrap(err, "failed to add signature")
+	}
+	if err := remote.Write(tag, signatures, options...); err != nil {
+		return name.Tag{}, errors.Wrapf(err, "failed to push the signature of image '%v'", image)
+	}
+	return tag, nil
+}
+
+// Verify checks that the image has a signature of one of the trusted keys
+func Verify(image name.Digest, trustedKeys []crypto.PublicKey, options ...remote.Option) error {
+	signatures, err := remote.Image(SignatureTag(image), options...)
+	if isNotFound(err) {
+		return fmt.Errorf("image '%v' is not signed", image)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to read the signatures of image '%v'", image)
+	}
+	manifest, err := signatures.Manifest()
+	if err != nil {
+		return errors.Wrapf(err, "failed to read the signatures of image '%v'", image)
+	}
+	for _, descriptor := range manifest.Layers {
+		encodedSignature, ok := descriptor.Annotations[SignatureAnnotation]
+		if !ok || descriptor.MediaType != SimpleSigningMediaType {
+			continue
+		}
+		message, err := layerContent(signatures, descriptor.Digest)
+		if err != nil {
+			return err
+		}
+		var content payload
+		if err := json.Unmarshal(message, &content); err != nil || content.Critical.Image.DockerManifestDigest != image.DigestStr() {
+			log.Entry().Debugf("skipping signature %v of another image", descriptor.Digest)
+			continue
+		}
+		signature, err := base64.StdEncoding.DecodeString(encodedSignature)
+		if err != nil {
+			continue
+		}
+		for _, key := range trustedKeys {
+			if verify(key, message, signature) {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("image '%v' has no valid signature of a trusted key", image)
+}
+
+func layerContent(image v1.Image, digest v1.Hash) ([]byte, error) {
+	layer, err := image.LayerByDigest(digest)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read signature %v", digest)
+	}
+	// cosign stores the payload as is, therefore the compressed content is the payload
+	reader, err := layer.Compressed()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read signature %v", digest)
+	}
+	defer reader.Close()
+	content := bytes.Buffer{}
+	if _, err := io.Copy(&content, reader); err != nil {
+		return nil, errors.Wrapf(err, "failed to read signature %v", digest)
+	}
+	return content.Bytes(), nil
+}
+
+func sign(signer crypto.Signer, message []byte) ([]byte, error) {
+	if _, ok := signer.Public().(ed25519.PublicKey); ok {
+		return signer.Sign(rand.Reader, message, crypto.Hash(0))
+	}
+	digest := sha256.Sum256(message)
+	return signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+}
+
+func verify(publicKey crypto.PublicKey, message, signature []byte) bool {
+	digest := sha256.Sum256(message)
+	switch key := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		return ecdsa.VerifyASN1(key, digest[:], signature)
+	case ed25519.PublicKey:
+		return ed25519.Verify(key, message, signature)
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature) == nil
+	}
+	return false
+}
+
+func isNotFound(err error) bool {
+	var transportError *transport.Error
+	return errors.As(err, &transportError) && transportError.StatusCode == http.StatusNotFound
+}
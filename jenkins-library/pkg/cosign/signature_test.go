@@ -0,0 +1,82 @@
+//go:build unit
+// +build unit
+
+package cosign
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	server := httptest.NewServer(registry.New())
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+	pushImage := func(t *testing.T, repository string) name.Digest {
+		image, err := random.Image(64, 1)
+		require.NoError(t, err)
+		tag, _ := name.NewTag(host + "/" + repository + ":1.0.0")
+		require.NoError(t, remote.Write(tag, image))
+		digest, err := ResolveDigest(tag.String())
+		require.NoError(t, err)
+		return digest
+	}
+	trusted, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	other, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	t.Run("signed image is verified", func(t *testing.T) {
+		image := pushImage(t, "acme/app")
+
+		tag, err := Sign(image, trusted, map[string]interface{}{"pipeline": "release"})
+		require.NoError(t, err)
+
+		assert.Equal(t, "sha256-"+strings.TrimPrefix(image.DigestStr(), "sha256:")+".sig", tag.TagStr())
+		assert.NoError(t, Verify(image, []crypto.PublicKey{other.Public(), trusted.Public()}))
+		assert.EqualError(t, Verify(image, []crypto.PublicKey{other.Public()}), "image '"+image.String()+"' has no valid signature of a trusted key")
+	})
+
+	t.Run("signatures are added", func(t *testing.T) {
+		image := pushImage(t, "acme/worker")
+
+		_, err := Sign(image, other, nil)
+		require.NoError(t, err)
+		_, err = Sign(image, trusted, nil)
+		require.NoError(t, err)
+
+		signatures, err := remote.Image(SignatureTag(image))
+		require.NoError(t, err)
+		manifest, _ := signatures.Manifest()
+		require.Len(t, manifest.Layers, 2)
+		assert.Equal(t, SimpleSigningMediaType, string(manifest.Layers[0].MediaType))
+		message, err := layerContent(signatures, manifest.Layers[0].Digest)
+		require.NoError(t, err)
+		var content payload
+		require.NoError(t, json.Unmarshal(message, &content))
+		assert.Equal(t, image.Context().Name(), content.Critical.Identity.DockerReference)
+		assert.Equal(t, image.DigestStr(), content.Critical.Image.DockerManifestDigest)
+		assert.Equal(t, "cosign container image signature", content.Critical.Type)
+		assert.NoError(t, Verify(image, []crypto.PublicKey{trusted.Public()}))
+		assert.NoError(t, Verify(image, []crypto.PublicKey{other.Public()}))
+	})
+
+	t.Run("unsigned image fails", func(t *testing.T) {
+		image := pushImage(t, "acme/unsigned")
+
+		err := Verify(image, []crypto.PublicKey{trusted.Public()})
+
+		assert.EqualError(t, err, "image '"+image.String()+"' is not signed")
+	})
+}
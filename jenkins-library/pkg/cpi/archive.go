@@ -0,0 +1,139 @@
+package cpi
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// archiveModTime is used for all entries of created archives, so that the same content always results in the same archive
+var archiveModTime = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// ArtifactFiles are the files of an artifact by their slash separated path relative to the artifact root
+type ArtifactFiles map[string][]byte
+
+// ReadArtifactArchive reads the files of a zip archive as downloaded from the tenant
+func ReadArtifactArchive(content []byte) (ArtifactFiles, error) {
+	reader, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open artifact archive")
+	}
+	files := ArtifactFiles{}
+	for _, entry := range reader.File {
+		if entry.FileInfo().IsDir() {
+			continue
+		}
+		name := path.Clean(strings.ReplaceAll(entry.Name, "\\", "/"))
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return nil, errors.Errorf("artifact archive contains illegal file path '%v'", entry.Name)
+		}
+		file, err := entry.Open()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to open file %v of artifact archive", entry.Name)
+		}
+		data, err := io.ReadAll(file)
+		file.Close()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read file %v of artifact archive", entry.Name)
+		}
+		files[name] = data
+	}
+	return files, nil
+}
+
+// CreateArtifactArchive creates a zip archive of the files. Entries are sorted and have a fixed modification time.
+func CreateArtifactArchive(files ArtifactFiles) ([]byte, error) {
+	var buffer bytes.Buffer
+	writer := zip.NewWriter(&buffer)
+	for _, name := range files.paths() {
+		header := &zip.FileHeader{Name: name, Method: zip.Deflate, Modified: archiveModTime}
+		entry, err := writer.CreateHeader(header)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to add %v to artifact archive", name)
+		}
+		if _, err := entry.Write(files[name]); err != nil {
+			return nil, errors.Wrapf(err, "failed to write %v to artifact archive", name)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, errors.Wrap(err, "failed to close artifact archive")
+	}
+	return buffer.Bytes(), nil
+}
+
+// ReadArtifactDirectory reads the files of an artifact which has been unpacked into a directory
+func ReadArtifactDirectory(directory string) (ArtifactFiles, error) {
+	files := ArtifactFiles{}
+	err := filepath.WalkDir(directory, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		relativePath, err := filepath.Rel(directory, filePath)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(filePath)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(relativePath)] = data
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read artifact directory %v", directory)
+	}
+	return files, nil
+}
+
+// WriteArtifactDirectory replaces the content of the directory with the files of the artifact
+func WriteArtifactDirectory(directory string, files ArtifactFiles) error {
+	if err := os.RemoveAll(directory); err != nil {
+		return errors.Wrapf(err, "failed to clean artifact directory %v", directory)
+	}
+	for _, name := range files.paths() {
+		filePath := filepath.Join(directory, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			return errors.Wrapf(err, "failed to create directory for %v", filePath)
+		}
+		if err := os.WriteFile(filePath, files[name], 0644); err != nil {
+			return errors.Wrapf(err, "failed to write %v", filePath)
+		}
+	}
+	return nil
+}
+
+// Digest is a hash of the paths and contents of the files. Line endings are normalised, so that
+// a checkout with Windows line endings has the same digest as the content on the tenant.
+func (files ArtifactFiles) Digest() string {
+	hash := sha256.New()
+	for _, name := range files.paths() {
+		hash.Write([]byte(name))
+		hash.Write([]byte{0})
+		hash.Write(bytes.ReplaceAll(files[name], []byte("\r\n"), []byte("\n")))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func (files ArtifactFiles) paths() []string {
+	paths := make([]string, 0, len(files))
+	for name := range files {
+		paths = append(paths, name)
+	}
+	sort.Strings(paths)
+	return paths
+}
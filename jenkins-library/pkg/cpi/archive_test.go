@@ -0,0 +1,69 @@
+//go:build unit
+// +build unit
+
+package cpi
+
+import (
+	"archive/zip"
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArtifactArchive(t *testing.T) {
+	files := ArtifactFiles{
+		"META-INF/MANIFEST.MF":                            []byte("Manifest-Version: 1.0\n"),
+		"src/main/resources/scenarioflows/flow.iflw":      []byte("<flow/>"),
+		"src/main/resources/script/script.groovy":         []byte("println 'hello'\n"),
+		"src/main/resources/parameters.prop":              []byte(""),
+		"src/main/resources/mapping/mapping.mmap":         []byte("<mapping/>"),
+		"src/main/resources/parameters.propdef":           []byte("<parameters/>"),
+		"src/main/resources/scenarioflows/sub/other.iflw": []byte("<other/>"),
+	}
+
+	t.Run("archives are deterministic", func(t *testing.T) {
+		first, err := CreateArtifactArchive(files)
+		require.NoError(t, err)
+		second, err := CreateArtifactArchive(files)
+		require.NoError(t, err)
+		assert.Equal(t, first, second)
+
+		read, err := ReadArtifactArchive(first)
+		require.NoError(t, err)
+		assert.Equal(t, files, read)
+	})
+
+	t.Run("directory round trip", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "artifact")
+		require.NoError(t, WriteArtifactDirectory(dir, ArtifactFiles{"stale.txt": []byte("stale")}))
+		require.NoError(t, WriteArtifactDirectory(dir, files))
+
+		read, err := ReadArtifactDirectory(dir)
+		require.NoError(t, err)
+		assert.Equal(t, files, read)
+	})
+
+	t.Run("illegal paths are rejected", func(t *testing.T) {
+		var buffer bytes.Buffer
+		writer := zip.NewWriter(&buffer)
+		_, err := writer.Create("../evil.sh")
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+
+		_, err = ReadArtifactArchive(buffer.Bytes())
+		assert.EqualError(t, err, "artifact archive contains illegal file path '../evil.sh'")
+	})
+}
+
+func TestArtifactFilesDigest(t *testing.T) {
+	unix := ArtifactFiles{"script.groovy": []byte("a\nb\n")}
+	windows := ArtifactFiles{"script.groovy": []byte("a\r\nb\r\n")}
+	renamed := ArtifactFiles{"other.groovy": []byte("a\nb\n")}
+
+	assert.Equal(t, unix.Digest(), windows.Digest())
+	assert.NotEqual(t, unix.Digest(), renamed.Digest())
+	assert.NotEqual(t, unix.Digest(), ArtifactFiles{"script.groovy": []byte("a\nc\n")}.Digest())
+}
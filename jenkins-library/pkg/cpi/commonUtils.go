@@ -2,7 +2,6 @@ package cpi
 
 import (
 	"encoding/json"
-	"fmt"
 	"io"
 	"mime"
 	"net/http"
@@ -11,7 +10,6 @@ import (
 
 	"github.com/SAP/jenkins-library/pkg/log"
 
-	"github.com/Jeffail/gabs/v2"
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/pkg/errors"
 )
@@ -78,46 +76,17 @@ func ReadCpiServiceKey(serviceKeyJSON string) (cpiServiceKey ServiceKey, err err
 }
 
 // GetBearerToken -Provides the bearer token for making CPI OData calls
+// The token is requested with the client of the token parameters and shared with other clients of the same credentials.
 func (tokenParameters TokenParameters) GetBearerToken() (string, error) {
-
-	httpClient := tokenParameters.Client
-
-	clientOptions := piperhttp.ClientOptions{
-		Username: tokenParameters.Username,
-		Password: tokenParameters.Password,
-	}
-	httpClient.SetOptions(clientOptions)
-
-	header := make(http.Header)
-	header.Add("Accept", "application/json")
-	tokenFinalURL := fmt.Sprintf("%s?grant_type=client_credentials", tokenParameters.TokenURL)
-	method := "POST"
-	resp, httpErr := httpClient.SendRequest(method, tokenFinalURL, nil, header, nil)
-	if httpErr != nil {
-		return "", errors.Wrapf(httpErr, "HTTP %v request to %v failed with error", method, tokenFinalURL)
-	}
-	if resp != nil && resp.Body != nil {
-		defer resp.Body.Close()
-	}
-
-	if resp == nil {
-		return "", errors.Errorf("did not retrieve a HTTP response")
-	}
-
-	if resp.StatusCode != 200 {
-		return "", errors.Errorf("did not retrieve a valid HTTP response code: %v", httpErr)
-	}
-
-	bodyText, readErr := io.ReadAll(resp.Body)
-	if readErr != nil {
-		return "", errors.Wrap(readErr, "HTTP response body could not be read")
-	}
-	jsonResponse, parsingErr := gabs.ParseJSON([]byte(bodyText))
-	if parsingErr != nil {
-		return "", errors.Wrapf(parsingErr, "HTTP response body could not be parsed as JSON: %v", string(bodyText))
+	token, err := piperhttp.FetchOAuth2Token(piperhttp.OAuth2Options{
+		TokenURL:     tokenParameters.TokenURL,
+		ClientID:     tokenParameters.Username,
+		ClientSecret: tokenParameters.Password,
+	}, tokenParameters.Client)
+	if err != nil {
+		return "", err
 	}
-	token := jsonResponse.Path("access_token").Data().(string)
-	return token, nil
+	return token.AccessToken, nil
 }
 
 // HandleHTTPFileDownloadResponse - Handle the file download response for http multipart response
@@ -0,0 +1,244 @@
+package cpi
+
+import (
+	"bytes"
+	b64 "encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/pkg/errors"
+)
+
+// ArtifactType describes a type of designtime artifact of an integration package and its OData entities
+type ArtifactType struct {
+	// Name is the name of the artifact type, e.g. IntegrationFlow
+	Name string
+	// EntitySet is the OData entity set of the designtime artifacts of the type
+	EntitySet string
+	// DeployFunction is the OData function which deploys an artifact of the type
+	DeployFunction string
+	// RecreateOnUpdate is set for types whose artifacts cannot be updated and have to be deleted and created again
+	RecreateOnUpdate bool
+}
+
+// ArtifactTypes are the artifact types of an integration package which can be synchronised
+var ArtifactTypes = []ArtifactType{
+	{Name: "IntegrationFlow", EntitySet: "IntegrationDesigntimeArtifacts", DeployFunction: "DeployIntegrationDesigntimeArtifact"},
+	{Name: "ValueMapping", EntitySet: "ValueMappingDesigntimeArtifacts", DeployFunction: "DeployValueMappingDesigntimeArtifact", RecreateOnUpdate: true},
+	{Name: "ScriptCollection", EntitySet: "ScriptCollectionDesigntimeArtifacts", DeployFunction: "DeployScriptCollectionDesigntimeArtifact"},
+	{Name: "MessageMapping", EntitySet: "MessageMappingDesigntimeArtifacts", DeployFunction: "DeployMessageMappingDesigntimeArtifact"},
+}
+
+// GetArtifactType returns the artifact type with the given name
+func GetArtifactType(name string) (ArtifactType, error) {
+	for _, artifactType := range ArtifactTypes {
+		if strings.EqualFold(artifactType.Name, name) {
+			return artifactType, nil
+		}
+	}
+	return ArtifactType{}, errors.Errorf("unknown artifact type '%v'", name)
+}
+
+// Artifact is a designtime artifact of an integration package
+type Artifact struct {
+	ID        string `json:"Id"`
+	Name      string `json:"Name"`
+	Version   string `json:"Version,omitempty"`
+	PackageID string `json:"PackageId,omitempty"`
+	Type      string `json:"-"`
+}
+
+// DesigntimeClient provides access to the designtime artifacts of the Cloud Integration OData API
+type DesigntimeClient struct {
+	host       string
+	httpClient piperhttp.Sender
+}
+
+// NewDesigntimeClient creates a client for the tenant of the service key, which authenticates with the OAuth2 client credentials of the service key
+func NewDesigntimeClient(serviceKey ServiceKey, httpClient piperhttp.Sender) (*DesigntimeClient, error) {
+	if len(serviceKey.OAuth.OAuthTokenProviderURL) == 0 {
+		return nil, errors.New("the token URL of the service key is missing")
+	}
+	httpClient.SetOptions(piperhttp.ClientOptions{OAuth2: &piperhttp.OAuth2Options{
+		TokenURL:     serviceKey.OAuth.OAuthTokenProviderURL,
+		ClientID:     serviceKey.OAuth.ClientID,
+		ClientSecret: serviceKey.OAuth.ClientSecret,
+	}})
+	return &DesigntimeClient{host: strings.TrimSuffix(serviceKey.OAuth.Host, "/"), httpClient: httpClient}, nil
+}
+
+// PackageArtifacts returns the artifacts of the given type in the integration package
+func (c *DesigntimeClient) PackageArtifacts(packageID string, artifactType ArtifactType) ([]Artifact, error) {
+	requestURL := fmt.Sprintf("%s/api/v1/IntegrationPackages('%s')/%s", c.host, escapeKey(packageID), artifactType.EntitySet)
+	body, err := c.send(http.MethodGet, requestURL, nil, "application/json", http.StatusOK)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list %v artifacts of package %v", artifactType.Name, packageID)
+	}
+	var response struct {
+		D struct {
+			Results []Artifact `json:"results"`
+		} `json:"d"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %v artifacts of package %v", artifactType.Name, packageID)
+	}
+	for i := range response.D.Results {
+		response.D.Results[i].Type = artifactType.Name
+	}
+	return response.D.Results, nil
+}
+
+// DownloadArtifact returns the content of the active version of the artifact as zip archive
+func (c *DesigntimeClient) DownloadArtifact(artifact Artifact) ([]byte, error) {
+	artifactType, err := GetArtifactType(artifact.Type)
+	if err != nil {
+		return nil, err
+	}
+	content, err := c.send(http.MethodGet, c.artifactURL(artifactType, artifact.ID)+"/$value", nil, "application/zip", http.StatusOK)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to download %v artifact %v", artifactType.Name, artifact.ID)
+	}
+	return content, nil
+}
+
+// CreateArtifact creates the artifact with the given zip archive as content in the integration package of the artifact
+func (c *DesigntimeClient) CreateArtifact(artifact Artifact, content []byte) error {
+	artifactType, err := GetArtifactType(artifact.Type)
+	if err != nil {
+		return err
+	}
+	payload := map[string]string{
+		"Id":              artifact.ID,
+		"Name":            artifact.Name,
+		"PackageId":       artifact.PackageID,
+		"ArtifactContent": b64.StdEncoding.EncodeToString(content),
+	}
+	requestURL := fmt.Sprintf("%s/api/v1/%s", c.host, artifactType.EntitySet)
+	if _, err := c.send(http.MethodPost, requestURL, payload, "application/json", http.StatusCreated); err != nil {
+		return errors.Wrapf(err, "failed to create %v artifact %v", artifactType.Name, artifact.ID)
+	}
+	return nil
+}
+
+// UpdateArtifact replaces the content of the active version of the artifact. Artifacts which do not support
+// updates are deleted and created again.
+func (c *DesigntimeClient) UpdateArtifact(artifact Artifact, content []byte) error {
+	artifactType, err := GetArtifactType(artifact.Type)
+	if err != nil {
+		return err
+	}
+	if artifactType.RecreateOnUpdate {
+		if _, err := c.send(http.MethodDelete, c.artifactURL(artifactType, artifact.ID), nil, "application/json", http.StatusOK, http.StatusAccepted, http.StatusNoContent); err != nil {
+			return errors.Wrapf(err, "failed to delete %v artifact %v", artifactType.Name, artifact.ID)
+		}
+		return c.CreateArtifact(artifact, content)
+	}
+	payload := map[string]string{
+		"Name":            artifact.Name,
+		"ArtifactContent": b64.StdEncoding.EncodeToString(content),
+	}
+	if _, err := c.send(http.MethodPut, c.artifactURL(artifactType, artifact.ID), payload, "application/json", http.StatusOK, http.StatusNoContent); err != nil {
+		return errors.Wrapf(err, "failed to update %v artifact %v", artifactType.Name, artifact.ID)
+	}
+	return nil
+}
+
+// DeployArtifact triggers the deployment of the active version of the artifact and returns the ID of the deployment task
+func (c *DesigntimeClient) DeployArtifact(artifact Artifact) (string, error) {
+	artifactType, err := GetArtifactType(artifact.Type)
+	if err != nil {
+		return "", err
+	}
+	requestURL := fmt.Sprintf("%s/api/v1/%s?Id='%s'&Version='active'", c.host, artifactType.DeployFunction, url.QueryEscape(escapeKey(artifact.ID)))
+	taskID, err := c.send(http.MethodPost, requestURL, nil, "application/json", http.StatusOK, http.StatusAccepted)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to deploy %v artifact %v", artifactType.Name, artifact.ID)
+	}
+	return string(taskID), nil
+}
+
+// UpdateConfiguration sets the value of an externalized parameter of the integration flow
+func (c *DesigntimeClient) UpdateConfiguration(integrationFlowID, version, key, value string) error {
+	requestURL := fmt.Sprintf("%s/api/v1/IntegrationDesigntimeArtifacts(Id='%s',Version='%s')/$links/Configurations('%s')", c.host, escapeKey(integrationFlowID), escapeKey(version), escapeKey(key))
+	if _, err := c.send(http.MethodPut, requestURL, map[string]string{"ParameterValue": value}, "application/json", http.StatusOK, http.StatusAccepted, http.StatusNoContent); err != nil {
+		return errors.Wrapf(err, "failed to update configuration parameter %v of integration flow %v", key, integrationFlowID)
+	}
+	return nil
+}
+
+// DeploymentStatus returns the status of the deployment task, e.g. DEPLOYING, SUCCESS or FAIL
+func (c *DesigntimeClient) DeploymentStatus(taskID string) (string, error) {
+	requestURL := fmt.Sprintf("%s/api/v1/BuildAndDeployStatus(TaskId='%s')", c.host, escapeKey(taskID))
+	body, err := c.send(http.MethodGet, requestURL, nil, "application/json", http.StatusOK)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get status of deployment task %v", taskID)
+	}
+	var response struct {
+		D struct {
+			Status string `json:"Status"`
+		} `json:"d"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return "", errors.Wrapf(err, "failed to parse status of deployment task %v", taskID)
+	}
+	return response.D.Status, nil
+}
+
+// DeploymentError returns the error information of the runtime artifact of a failed deployment
+func (c *DesigntimeClient) DeploymentError(artifactID string) (string, error) {
+	requestURL := fmt.Sprintf("%s/api/v1/IntegrationRuntimeArtifacts('%s')/ErrorInformation/$value", c.host, escapeKey(artifactID))
+	body, err := c.send(http.MethodGet, requestURL, nil, "application/json", http.StatusOK)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get deployment error of artifact %v", artifactID)
+	}
+	return string(body), nil
+}
+
+func (c *DesigntimeClient) artifactURL(artifactType ArtifactType, id string) string {
+	return fmt.Sprintf("%s/api/v1/%s(Id='%s',Version='active')", c.host, artifactType.EntitySet, escapeKey(id))
+}
+
+func (c *DesigntimeClient) send(method, requestURL string, payload interface{}, accept string, expectedStatus ...int) ([]byte, error) {
+	header := make(http.Header)
+	header.Add("Accept", accept)
+	var body io.Reader
+	if payload != nil {
+		jsonPayload, err := json.Marshal(payload)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create request payload")
+		}
+		header.Add("Content-Type", "application/json")
+		body = bytes.NewBuffer(jsonPayload)
+	}
+
+	response, httpErr := c.httpClient.SendRequest(method, requestURL, body, header, nil)
+	if response == nil {
+		return nil, errors.Errorf("did not retrieve a HTTP response: %v", httpErr)
+	}
+	if response.Body != nil {
+		defer response.Body.Close()
+	}
+	responseBody, readErr := io.ReadAll(response.Body)
+	if readErr != nil {
+		return nil, errors.Wrapf(readErr, "HTTP response body could not be read, response status code: %v", response.StatusCode)
+	}
+	for _, status := range expectedStatus {
+		if response.StatusCode == status {
+			return responseBody, nil
+		}
+	}
+	if httpErr != nil {
+		return nil, errors.Wrapf(httpErr, "HTTP %v request to %v failed with error: %v", method, requestURL, string(responseBody))
+	}
+	return nil, errors.Errorf("HTTP %v request to %v returned unexpected response status code %v: %v", method, requestURL, response.StatusCode, string(responseBody))
+}
+
+// escapeKey escapes single quotes in OData key values
+func escapeKey(key string) string {
+	return strings.ReplaceAll(key, "'", "''")
+}
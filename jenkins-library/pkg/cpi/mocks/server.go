@@ -0,0 +1,334 @@
+//go:build !release
+// +build !release
+
+package mocks
+
+import (
+	b64 "encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/SAP/jenkins-library/pkg/cpi"
+)
+
+var (
+	packageArtifactsPath = regexp.MustCompile(`^/api/v1/IntegrationPackages\('([^']+)'\)/(\w+)$`)
+	artifactPath         = regexp.MustCompile(`^/api/v1/(\w+)\(Id='([^']+)',Version='[^']+'\)(/\$value)?$`)
+	entitySetPath        = regexp.MustCompile(`^/api/v1/(\w+)$`)
+	configurationPath    = regexp.MustCompile(`^/api/v1/IntegrationDesigntimeArtifacts\(Id='([^']+)',Version='[^']+'\)/\$links/Configurations\('([^']+)'\)$`)
+	deployStatusPath     = regexp.MustCompile(`^/api/v1/BuildAndDeployStatus\(TaskId='([^']+)'\)$`)
+	errorInformationPath = regexp.MustCompile(`^/api/v1/IntegrationRuntimeArtifacts\('([^']+)'\)/ErrorInformation/\$value$`)
+	mplErrorPath         = regexp.MustCompile(`^/api/v1/MessageProcessingLogs\('([^']+)'\)/ErrorInformation/\$value$`)
+	mplFilter            = regexp.MustCompile(`IntegrationArtifact/Id eq '([^']+)'`)
+)
+
+// MessageProcessingLog is a message processing log of an integration flow on the fake tenant
+type MessageProcessingLog struct {
+	MessageGuid string
+	Status      string
+	// ErrorText is returned as error information of failed messages
+	ErrorText string
+}
+
+// StoredArtifact is a designtime artifact stored on the fake tenant
+type StoredArtifact struct {
+	cpi.Artifact
+	Content []byte
+}
+
+// Server is a local fake of the designtime artifact, deployment and message monitoring APIs of a Cloud Integration tenant
+type Server struct {
+	*httptest.Server
+
+	mu sync.Mutex
+	// Artifacts are the artifacts of the tenant by artifact type name and ID
+	Artifacts map[string]map[string]*StoredArtifact
+	// Deployed are the deployed artifacts in the format 'Type/ID'
+	Deployed []string
+	// Requests are the requests in the format 'METHOD path' received by the server
+	Requests []string
+	// Configurations are the values of the externalized parameters by integration flow ID and parameter key
+	Configurations map[string]map[string]string
+	// DeployErrors are the error information of integration flows whose deployment fails, by integration flow ID
+	DeployErrors map[string]string
+	// DeployingPolls is the number of status requests per deployment task which report the task as still running
+	DeployingPolls int
+	// MessageProcessingLogs are the message processing logs by integration flow ID, the last log is the most recent one
+	MessageProcessingLogs map[string][]MessageProcessingLog
+
+	tasks map[string]string
+	polls map[string]int
+}
+
+// NewServer starts a fake Cloud Integration tenant. The server is closed when Close is called.
+func NewServer() *Server {
+	s := &Server{
+		Artifacts:             map[string]map[string]*StoredArtifact{},
+		Configurations:        map[string]map[string]string{},
+		DeployErrors:          map[string]string{},
+		MessageProcessingLogs: map[string][]MessageProcessingLog{},
+		tasks:                 map[string]string{},
+		polls:                 map[string]int{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// ServiceKey returns a service key of the fake tenant
+func (s *Server) ServiceKey() string {
+	return fmt.Sprintf(`{"oauth": {"url": "%[1]v", "tokenurl": "%[1]v/oauth/token", "clientid": "client", "clientsecret": "secret"}}`, s.URL)
+}
+
+// AddArtifact stores an artifact on the tenant
+func (s *Server) AddArtifact(artifact cpi.Artifact, content []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.store(artifact, content)
+}
+
+// Configuration returns the value of an externalized parameter of the integration flow
+func (s *Server) Configuration(integrationFlowID, key string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	value, ok := s.Configurations[integrationFlowID][key]
+	return value, ok
+}
+
+// AddMessageProcessingLog records the processing of a message by the integration flow and returns the ID of the message
+func (s *Server) AddMessageProcessingLog(integrationFlowID, status, errorText string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	guid := fmt.Sprintf("mpl-%v-%v", integrationFlowID, len(s.MessageProcessingLogs[integrationFlowID])+1)
+	s.MessageProcessingLogs[integrationFlowID] = append(s.MessageProcessingLogs[integrationFlowID], MessageProcessingLog{MessageGuid: guid, Status: status, ErrorText: errorText})
+	return guid
+}
+
+// Artifact returns the stored artifact of the given type and ID
+func (s *Server) Artifact(artifactType, id string) (StoredArtifact, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	artifact, ok := s.Artifacts[artifactType][id]
+	if !ok {
+		return StoredArtifact{}, false
+	}
+	return *artifact, true
+}
+
+func (s *Server) store(artifact cpi.Artifact, content []byte) {
+	if s.Artifacts[artifact.Type] == nil {
+		s.Artifacts[artifact.Type] = map[string]*StoredArtifact{}
+	}
+	if len(artifact.Version) == 0 {
+		artifact.Version = "1.0.0"
+	}
+	s.Artifacts[artifact.Type][artifact.ID] = &StoredArtifact{Artifact: artifact, Content: content}
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Requests = append(s.Requests, r.Method+" "+r.URL.Path)
+
+	if r.URL.Path == "/oauth/token" {
+		writeJSON(w, http.StatusOK, map[string]string{"access_token": "token"})
+		return
+	}
+	if r.Header.Get("Authorization") != "Bearer token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if match := configurationPath.FindStringSubmatch(r.URL.Path); match != nil && r.Method == http.MethodPut {
+		if _, exists := s.Artifacts["IntegrationFlow"][match[1]]; !exists {
+			writeJSON(w, http.StatusNotFound, map[string]interface{}{"error": map[string]string{"message": "artifact not found"}})
+			return
+		}
+		var payload map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		if s.Configurations[match[1]] == nil {
+			s.Configurations[match[1]] = map[string]string{}
+		}
+		s.Configurations[match[1]][match[2]] = payload["ParameterValue"]
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	if match := deployStatusPath.FindStringSubmatch(r.URL.Path); match != nil && r.Method == http.MethodGet {
+		id, exists := s.tasks[match[1]]
+		if !exists {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		status := "SUCCESS"
+		if s.polls[match[1]] < s.DeployingPolls {
+			status = "DEPLOYING"
+		} else if _, fails := s.DeployErrors[id]; fails {
+			status = "FAIL"
+		}
+		s.polls[match[1]]++
+		writeJSON(w, http.StatusOK, map[string]interface{}{"d": map[string]string{"TaskId": match[1], "Status": status}})
+		return
+	}
+
+	if match := errorInformationPath.FindStringSubmatch(r.URL.Path); match != nil && r.Method == http.MethodGet {
+		message, exists := s.DeployErrors[match[1]]
+		if !exists {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(message))
+		return
+	}
+
+	if r.URL.Path == "/api/v1/MessageProcessingLogs" && r.Method == http.MethodGet {
+		results := []map[string]string{}
+		if match := mplFilter.FindStringSubmatch(r.URL.Query().Get("$filter")); match != nil {
+			logs := s.MessageProcessingLogs[match[1]]
+			for i := len(logs) - 1; i >= 0; i-- {
+				if logs[i].Status != "DISCARDED" {
+					results = append(results, map[string]string{"MessageGuid": logs[i].MessageGuid, "Status": logs[i].Status})
+					break
+				}
+			}
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"d": map[string]interface{}{"results": results}})
+		return
+	}
+
+	if match := mplErrorPath.FindStringSubmatch(r.URL.Path); match != nil && r.Method == http.MethodGet {
+		for _, logs := range s.MessageProcessingLogs {
+			for _, mpl := range logs {
+				if mpl.MessageGuid == match[1] {
+					w.WriteHeader(http.StatusOK)
+					_, _ = w.Write([]byte(mpl.ErrorText))
+					return
+				}
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	if match := packageArtifactsPath.FindStringSubmatch(r.URL.Path); match != nil && r.Method == http.MethodGet {
+		artifactType, ok := typeByEntitySet(match[2])
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		results := []cpi.Artifact{}
+		for _, artifact := range s.Artifacts[artifactType.Name] {
+			if artifact.PackageID == match[1] {
+				results = append(results, artifact.Artifact)
+			}
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"d": map[string]interface{}{"results": results}})
+		return
+	}
+
+	if match := artifactPath.FindStringSubmatch(r.URL.Path); match != nil {
+		artifactType, ok := typeByEntitySet(match[1])
+		artifact, exists := s.Artifacts[artifactType.Name][match[2]]
+		if !ok || !exists {
+			writeJSON(w, http.StatusNotFound, map[string]interface{}{"error": map[string]string{"message": "artifact not found"}})
+			return
+		}
+		switch {
+		case r.Method == http.MethodGet && len(match[3]) == 0:
+			writeJSON(w, http.StatusOK, map[string]interface{}{"d": artifact.Artifact})
+		case r.Method == http.MethodGet && len(match[3]) > 0:
+			w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%v.zip", artifact.ID))
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write(artifact.Content)
+		case r.Method == http.MethodPut && !artifactType.RecreateOnUpdate:
+			var payload map[string]string
+			content, err := decodePayload(r, &payload)
+			if err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+				return
+			}
+			artifact.Content = content
+			if name := payload["Name"]; len(name) > 0 {
+				artifact.Name = name
+			}
+			w.WriteHeader(http.StatusOK)
+		case r.Method == http.MethodDelete:
+			delete(s.Artifacts[artifactType.Name], artifact.ID)
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	if match := entitySetPath.FindStringSubmatch(r.URL.Path); match != nil && r.Method == http.MethodPost {
+		if artifactType, ok := typeByEntitySet(match[1]); ok {
+			var payload map[string]string
+			content, err := decodePayload(r, &payload)
+			if err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+				return
+			}
+			if _, exists := s.Artifacts[artifactType.Name][payload["Id"]]; exists {
+				writeJSON(w, http.StatusConflict, map[string]interface{}{"error": map[string]string{"message": "artifact already exists"}})
+				return
+			}
+			s.store(cpi.Artifact{ID: payload["Id"], Name: payload["Name"], PackageID: payload["PackageId"], Type: artifactType.Name}, content)
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		if artifactType, ok := typeByDeployFunction(match[1]); ok {
+			id := strings.Trim(r.URL.Query().Get("Id"), "'")
+			if _, exists := s.Artifacts[artifactType.Name][id]; !exists {
+				writeJSON(w, http.StatusNotFound, map[string]interface{}{"error": map[string]string{"message": "artifact not found"}})
+				return
+			}
+			s.Deployed = append(s.Deployed, artifactType.Name+"/"+id)
+			taskID := fmt.Sprintf("task-%v", len(s.Deployed))
+			s.tasks[taskID] = id
+			w.WriteHeader(http.StatusAccepted)
+			_, _ = w.Write([]byte(taskID))
+			return
+		}
+	}
+	w.WriteHeader(http.StatusNotFound)
+}
+
+func decodePayload(r *http.Request, payload *map[string]string) ([]byte, error) {
+	if err := json.NewDecoder(r.Body).Decode(payload); err != nil {
+		return nil, err
+	}
+	return b64.StdEncoding.DecodeString((*payload)["ArtifactContent"])
+}
+
+func typeByEntitySet(entitySet string) (cpi.ArtifactType, bool) {
+	for _, artifactType := range cpi.ArtifactTypes {
+		if artifactType.EntitySet == entitySet {
+			return artifactType, true
+		}
+	}
+	return cpi.ArtifactType{}, false
+}
+
+func typeByDeployFunction(function string) (cpi.ArtifactType, bool) {
+	for _, artifactType := range cpi.ArtifactTypes {
+		if artifactType.DeployFunction == function {
+			return artifactType, true
+		}
+	}
+	return cpi.ArtifactType{}, false
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,235 @@
+package cpi
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/pkg/errors"
+)
+
+const (
+	// TenantSuccess is the result of a tenant on which the integration flow has been deployed
+	TenantSuccess = "success"
+	// TenantFailure is the result of a tenant on which the configuration or the deployment failed
+	TenantFailure = "failure"
+
+	deployStatusDeploying = "DEPLOYING"
+	deployStatusSuccess   = "SUCCESS"
+)
+
+// Tenant is a Cloud Integration tenant of a multi-tenant deployment
+type Tenant struct {
+	// Name identifies the tenant in logs and reports
+	Name string `json:"name"`
+	// ServiceKey is the service key of the Process Integration Runtime service instance of plan 'api',
+	// either as JSON object or as JSON string
+	ServiceKey json.RawMessage `json:"apiServiceKey"`
+	// Parameters are tenant specific values of externalized parameters, they override the common values
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
+
+// TenantResult is the outcome of the deployment to a tenant
+type TenantResult struct {
+	Tenant            string   `json:"tenant"`
+	Host              string   `json:"host,omitempty"`
+	Status            string   `json:"status"`
+	UpdatedParameters []string `json:"updatedParameters,omitempty"`
+	DeploymentStatus  string   `json:"deploymentStatus,omitempty"`
+	Error             string   `json:"error,omitempty"`
+	ErrorDetails      string   `json:"errorDetails,omitempty"`
+}
+
+// MultiTenantDeployment updates the configuration of an integration flow and deploys it to several tenants in parallel
+type MultiTenantDeployment struct {
+	IntegrationFlowID      string
+	IntegrationFlowVersion string
+	// Parameters are the values of externalized parameters which are set on all tenants
+	Parameters map[string]string
+	// MaxConcurrency limits the number of tenants which are processed at the same time
+	MaxConcurrency int
+	PollInterval   time.Duration
+	Timeout        time.Duration
+	// NewHTTPClient creates the HTTP client of a tenant, every tenant needs its own client since the client carries the token of the tenant
+	NewHTTPClient func() piperhttp.Sender
+}
+
+// ParseTenants parses the list of tenants of a multi-tenant deployment
+func ParseTenants(tenantsJSON string) ([]Tenant, error) {
+	var tenants []Tenant
+	if err := json.Unmarshal([]byte(tenantsJSON), &tenants); err != nil {
+		// the error of the JSON decoder might contain parts of the service keys
+		return nil, errors.New("failed to parse tenants, a JSON list of tenants with name, apiServiceKey and optional parameters is expected")
+	}
+	if len(tenants) == 0 {
+		return nil, errors.New("no tenants provided")
+	}
+	names := map[string]bool{}
+	for i, tenant := range tenants {
+		if len(tenant.Name) == 0 {
+			return nil, errors.Errorf("name of tenant %v is missing", i+1)
+		}
+		if names[tenant.Name] {
+			return nil, errors.Errorf("tenant name '%v' is not unique", tenant.Name)
+		}
+		names[tenant.Name] = true
+		if len(tenant.ServiceKey) == 0 || string(tenant.ServiceKey) == "null" {
+			return nil, errors.Errorf("apiServiceKey of tenant '%v' is missing", tenant.Name)
+		}
+	}
+	return tenants, nil
+}
+
+// serviceKey returns the service key of the tenant, which can be provided as JSON object or as string containing the JSON object
+func (t Tenant) serviceKey() (ServiceKey, error) {
+	serviceKeyJSON := string(t.ServiceKey)
+	var quoted string
+	if err := json.Unmarshal(t.ServiceKey, &quoted); err == nil {
+		serviceKeyJSON = quoted
+	}
+	serviceKey, err := ReadCpiServiceKey(serviceKeyJSON)
+	if err != nil {
+		return ServiceKey{}, errors.Errorf("invalid apiServiceKey of tenant '%v'", t.Name)
+	}
+	return serviceKey, nil
+}
+
+// Run deploys the integration flow to all tenants and returns the results in the order of the tenants.
+// A failing tenant does not stop the deployment to the other tenants.
+func (d *MultiTenantDeployment) Run(tenants []Tenant) []TenantResult {
+	maxConcurrency := d.MaxConcurrency
+	if maxConcurrency < 1 {
+		maxConcurrency = 1
+	}
+	results := make([]TenantResult, len(tenants))
+	semaphore := make(chan struct{}, maxConcurrency)
+	var wg sync.WaitGroup
+	for i := range tenants {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			results[i] = TenantResult{Tenant: tenants[i].Name, Status: TenantFailure}
+			d.deploy(tenants[i], &results[i])
+		}(i)
+	}
+	wg.Wait()
+	return results
+}
+
+func (d *MultiTenantDeployment) deploy(tenant Tenant, result *TenantResult) {
+	logger := log.Entry().WithField("tenant", tenant.Name)
+
+	serviceKey, err := tenant.serviceKey()
+	if err != nil {
+		result.Error = err.Error()
+		return
+	}
+	result.Host = serviceKey.OAuth.Host
+	client, err := NewDesigntimeClient(serviceKey, d.NewHTTPClient())
+	if err != nil {
+		result.Error = err.Error()
+		return
+	}
+
+	parameters := mergeParameters(d.Parameters, tenant.Parameters)
+	for _, key := range sortedKeys(parameters) {
+		if err := client.UpdateConfiguration(d.IntegrationFlowID, d.IntegrationFlowVersion, key, parameters[key]); err != nil {
+			result.Error = err.Error()
+			return
+		}
+		result.UpdatedParameters = append(result.UpdatedParameters, key)
+	}
+	if len(parameters) > 0 {
+		logger.Infof("Updated %v configuration parameters of integration flow %v", len(parameters), d.IntegrationFlowID)
+	}
+
+	taskID, err := client.DeployArtifact(Artifact{ID: d.IntegrationFlowID, Type: "IntegrationFlow"})
+	if err != nil {
+		result.Error = err.Error()
+		return
+	}
+	logger.Infof("Triggered deployment of integration flow %v", d.IntegrationFlowID)
+
+	status, err := d.waitForDeployment(client, taskID)
+	result.DeploymentStatus = status
+	if err != nil {
+		result.Error = err.Error()
+		return
+	}
+	if status != deployStatusSuccess {
+		result.Error = fmt.Sprintf("deployment of integration flow %v ended with status %v", d.IntegrationFlowID, status)
+		if details, err := client.DeploymentError(d.IntegrationFlowID); err == nil {
+			result.ErrorDetails = details
+		} else {
+			logger.WithError(err).Warn("Failed to retrieve the deployment error details")
+		}
+		return
+	}
+	logger.Infof("Deployed integration flow %v", d.IntegrationFlowID)
+	result.Status = TenantSuccess
+}
+
+// waitForDeployment polls the status of the deployment task until the deployment is no longer running
+func (d *MultiTenantDeployment) waitForDeployment(client *DesigntimeClient, taskID string) (string, error) {
+	deadline := time.Now().Add(d.Timeout)
+	for {
+		status, err := client.DeploymentStatus(taskID)
+		if err != nil {
+			return "", err
+		}
+		if status != deployStatusDeploying {
+			return status, nil
+		}
+		if time.Now().After(deadline) {
+			return status, errors.Errorf("deployment of integration flow %v did not finish within %v", d.IntegrationFlowID, d.Timeout)
+		}
+		time.Sleep(d.PollInterval)
+	}
+}
+
+// mergeParameters combines the common parameters with the parameters of a tenant, tenant parameters take precedence
+func mergeParameters(common, tenant map[string]string) map[string]string {
+	merged := map[string]string{}
+	for key, value := range common {
+		merged[key] = value
+	}
+	for key, value := range tenant {
+		merged[key] = value
+	}
+	return merged
+}
+
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// FailedTenants returns the names of the tenants whose deployment failed
+func FailedTenants(results []TenantResult) []string {
+	failed := []string{}
+	for _, result := range results {
+		if result.Status != TenantSuccess {
+			failed = append(failed, result.Tenant)
+		}
+	}
+	return failed
+}
+
+// String returns a short summary of the result
+func (r TenantResult) String() string {
+	if r.Status == TenantSuccess {
+		return fmt.Sprintf("%v: %v", r.Tenant, r.Status)
+	}
+	return fmt.Sprintf("%v: %v (%v)", r.Tenant, r.Status, strings.TrimSpace(r.Error))
+}
@@ -0,0 +1,147 @@
+//go:build unit
+// +build unit
+
+package cpi_test
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/cpi"
+	"github.com/SAP/jenkins-library/pkg/cpi/mocks"
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTenantServer(t *testing.T) *mocks.Server {
+	server := mocks.NewServer()
+	t.Cleanup(server.Close)
+	server.AddArtifact(cpi.Artifact{ID: "Flow1", Name: "Flow 1", PackageID: "pkg", Type: "IntegrationFlow"}, []byte("content"))
+	return server
+}
+
+func newMultiTenantDeployment() *cpi.MultiTenantDeployment {
+	return &cpi.MultiTenantDeployment{
+		IntegrationFlowID:      "Flow1",
+		IntegrationFlowVersion: "active",
+		Parameters:             map[string]string{"Endpoint": "https://default.example.com", "Timeout": "60"},
+		MaxConcurrency:         2,
+		PollInterval:           time.Millisecond,
+		Timeout:                time.Second,
+		NewHTTPClient:          func() piperhttp.Sender { return &piperhttp.Client{} },
+	}
+}
+
+func TestParseTenants(t *testing.T) {
+	t.Run("service key as object and as string", func(t *testing.T) {
+		tenants, err := cpi.ParseTenants(`[
+			{"name": "dev", "apiServiceKey": {"oauth": {"url": "https://dev"}}},
+			{"name": "test", "apiServiceKey": "{\"oauth\": {\"url\": \"https://test\"}}", "parameters": {"Endpoint": "https://test.example.com"}}
+		]`)
+
+		require.NoError(t, err)
+		require.Len(t, tenants, 2)
+		assert.Equal(t, "dev", tenants[0].Name)
+		assert.Equal(t, map[string]string{"Endpoint": "https://test.example.com"}, tenants[1].Parameters)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := cpi.ParseTenants(`{"name": "dev", "apiServiceKey": {"oauth": {"clientsecret": "secret"}}}`)
+		assert.EqualError(t, err, "failed to parse tenants, a JSON list of tenants with name, apiServiceKey and optional parameters is expected")
+		_, err = cpi.ParseTenants(`[]`)
+		assert.EqualError(t, err, "no tenants provided")
+		_, err = cpi.ParseTenants(`[{"apiServiceKey": {}}]`)
+		assert.EqualError(t, err, "name of tenant 1 is missing")
+		_, err = cpi.ParseTenants(`[{"name": "dev", "apiServiceKey": {}}, {"name": "dev", "apiServiceKey": {}}]`)
+		assert.EqualError(t, err, "tenant name 'dev' is not unique")
+		_, err = cpi.ParseTenants(`[{"name": "dev"}]`)
+		assert.EqualError(t, err, "apiServiceKey of tenant 'dev' is missing")
+	})
+}
+
+func TestMultiTenantDeployment(t *testing.T) {
+	t.Run("deploys to all tenants with tenant specific parameters", func(t *testing.T) {
+		dev := newTenantServer(t)
+		test := newTenantServer(t)
+		test.DeployingPolls = 2
+		customer := newTenantServer(t)
+		customer.DeployErrors["Flow1"] = "Certificate for alias 'customer' not found"
+		tenants := []cpi.Tenant{
+			{Name: "dev", ServiceKey: []byte(dev.ServiceKey())},
+			{Name: "test", ServiceKey: []byte(strconv.Quote(test.ServiceKey())), Parameters: map[string]string{"Endpoint": "https://test.example.com"}},
+			{Name: "customer", ServiceKey: []byte(customer.ServiceKey())},
+		}
+
+		results := newMultiTenantDeployment().Run(tenants)
+
+		require.Len(t, results, 3)
+		assert.Equal(t, cpi.TenantResult{
+			Tenant:            "dev",
+			Host:              dev.URL,
+			Status:            cpi.TenantSuccess,
+			UpdatedParameters: []string{"Endpoint", "Timeout"},
+			DeploymentStatus:  "SUCCESS",
+		}, results[0])
+		assert.Equal(t, cpi.TenantSuccess, results[1].Status)
+		assert.Equal(t, cpi.TenantFailure, results[2].Status)
+		assert.Equal(t, "FAIL", results[2].DeploymentStatus)
+		assert.Equal(t, "deployment of integration flow Flow1 ended with status FAIL", results[2].Error)
+		assert.Equal(t, "Certificate for alias 'customer' not found", results[2].ErrorDetails)
+		assert.Equal(t, []string{"customer"}, cpi.FailedTenants(results))
+
+		value, _ := dev.Configuration("Flow1", "Endpoint")
+		assert.Equal(t, "https://default.example.com", value)
+		value, _ = test.Configuration("Flow1", "Endpoint")
+		assert.Equal(t, "https://test.example.com", value)
+		value, _ = test.Configuration("Flow1", "Timeout")
+		assert.Equal(t, "60", value)
+		assert.Equal(t, []string{"IntegrationFlow/Flow1"}, test.Deployed)
+	})
+
+	t.Run("failing tenant does not stop other tenants", func(t *testing.T) {
+		servers := []*mocks.Server{}
+		tenants := []cpi.Tenant{{Name: "broken", ServiceKey: []byte(`{"oauth": "invalid"}`)}}
+		for i := 0; i < 4; i++ {
+			server := newTenantServer(t)
+			servers = append(servers, server)
+			tenants = append(tenants, cpi.Tenant{Name: fmt.Sprintf("tenant%v", i), ServiceKey: []byte(server.ServiceKey())})
+		}
+		deployment := newMultiTenantDeployment()
+		deployment.Parameters = nil
+
+		results := deployment.Run(tenants)
+
+		assert.Equal(t, []string{"broken"}, cpi.FailedTenants(results))
+		assert.Equal(t, "invalid apiServiceKey of tenant 'broken'", results[0].Error)
+		for _, server := range servers {
+			assert.Equal(t, []string{"IntegrationFlow/Flow1"}, server.Deployed)
+		}
+	})
+
+	t.Run("unknown integration flow", func(t *testing.T) {
+		server := newTenantServer(t)
+		deployment := newMultiTenantDeployment()
+		deployment.IntegrationFlowID = "Unknown"
+
+		results := deployment.Run([]cpi.Tenant{{Name: "dev", ServiceKey: []byte(server.ServiceKey())}})
+
+		assert.Equal(t, cpi.TenantFailure, results[0].Status)
+		assert.Contains(t, results[0].Error, "failed to update configuration parameter Endpoint of integration flow Unknown")
+		assert.Empty(t, server.Deployed)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		server := newTenantServer(t)
+		server.DeployingPolls = 1000
+		deployment := newMultiTenantDeployment()
+		deployment.Timeout = 5 * time.Millisecond
+
+		results := deployment.Run([]cpi.Tenant{{Name: "dev", ServiceKey: []byte(server.ServiceKey())}})
+
+		assert.Equal(t, "DEPLOYING", results[0].DeploymentStatus)
+		assert.Equal(t, "deployment of integration flow Flow1 did not finish within 5ms", results[0].Error)
+	})
+}
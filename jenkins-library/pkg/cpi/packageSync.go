@@ -0,0 +1,270 @@
+package cpi
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/pkg/errors"
+)
+
+// PackageDescriptorFile is the file in the source directory which lists the artifacts of the integration package
+const PackageDescriptorFile = "package.json"
+
+// DriftState is the result of the comparison of an artifact in the source directory with the tenant
+type DriftState string
+
+const (
+	// DriftUnchanged means the content in the source directory and on the tenant is equal
+	DriftUnchanged DriftState = "unchanged"
+	// DriftModified means the content in the source directory differs from the content on the tenant
+	DriftModified DriftState = "modified"
+	// DriftOnlyInRepository means the artifact does not exist on the tenant
+	DriftOnlyInRepository DriftState = "onlyInRepository"
+	// DriftOnlyInTenant means the artifact exists on the tenant, but not in the source directory
+	DriftOnlyInTenant DriftState = "onlyInTenant"
+)
+
+// PackageDescriptor describes the artifacts of an integration package stored in a source directory
+type PackageDescriptor struct {
+	PackageID string               `json:"packageId"`
+	Artifacts []DescriptorArtifact `json:"artifacts"`
+}
+
+// DescriptorArtifact is an artifact of the package descriptor
+type DescriptorArtifact struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// ArtifactDrift is the drift of a single artifact between the source directory and the tenant
+type ArtifactDrift struct {
+	ID           string     `json:"id"`
+	Name         string     `json:"name"`
+	Type         string     `json:"type"`
+	State        DriftState `json:"state"`
+	LocalDigest  string     `json:"localDigest,omitempty"`
+	TenantDigest string     `json:"tenantDigest,omitempty"`
+}
+
+// PackageClient is the subset of the designtime API required to synchronise integration packages
+type PackageClient interface {
+	PackageArtifacts(packageID string, artifactType ArtifactType) ([]Artifact, error)
+	DownloadArtifact(artifact Artifact) ([]byte, error)
+	CreateArtifact(artifact Artifact, content []byte) error
+	UpdateArtifact(artifact Artifact, content []byte) error
+	DeployArtifact(artifact Artifact) (string, error)
+}
+
+// ArtifactDirectory returns the directory of the artifact below the source directory
+func ArtifactDirectory(sourceDirectory, artifactType, id string) string {
+	return filepath.Join(sourceDirectory, artifactType, id)
+}
+
+// DownloadPackage downloads all artifacts of the given types in the integration package and unpacks them into the source directory.
+// Directories of artifacts which no longer exist on the tenant are removed.
+func DownloadPackage(client PackageClient, packageID string, artifactTypes []ArtifactType, sourceDirectory string) (PackageDescriptor, error) {
+	descriptor := PackageDescriptor{PackageID: packageID, Artifacts: []DescriptorArtifact{}}
+	for _, artifactType := range artifactTypes {
+		artifacts, err := client.PackageArtifacts(packageID, artifactType)
+		if err != nil {
+			return descriptor, err
+		}
+		current := map[string]bool{}
+		for _, artifact := range artifacts {
+			content, err := client.DownloadArtifact(artifact)
+			if err != nil {
+				return descriptor, err
+			}
+			files, err := ReadArtifactArchive(content)
+			if err != nil {
+				return descriptor, errors.Wrapf(err, "failed to unpack %v artifact %v", artifactType.Name, artifact.ID)
+			}
+			if err := WriteArtifactDirectory(ArtifactDirectory(sourceDirectory, artifactType.Name, artifact.ID), files); err != nil {
+				return descriptor, err
+			}
+			current[artifact.ID] = true
+			descriptor.Artifacts = append(descriptor.Artifacts, DescriptorArtifact{ID: artifact.ID, Name: artifact.Name, Type: artifactType.Name})
+			log.Entry().Infof("Downloaded %v artifact %v", artifactType.Name, artifact.ID)
+		}
+
+		localIDs, err := localArtifactIDs(sourceDirectory, artifactType.Name)
+		if err != nil {
+			return descriptor, err
+		}
+		for _, id := range localIDs {
+			if !current[id] {
+				log.Entry().Infof("Removing %v artifact %v which does not exist on the tenant", artifactType.Name, id)
+				if err := os.RemoveAll(ArtifactDirectory(sourceDirectory, artifactType.Name, id)); err != nil {
+					return descriptor, errors.Wrapf(err, "failed to remove %v artifact %v", artifactType.Name, id)
+				}
+			}
+		}
+	}
+	sortDescriptorArtifacts(descriptor.Artifacts)
+	return descriptor, WritePackageDescriptor(sourceDirectory, descriptor)
+}
+
+// ComparePackage compares the artifacts of the given types in the source directory with the artifacts in the integration package on the tenant
+func ComparePackage(client PackageClient, packageID string, artifactTypes []ArtifactType, sourceDirectory string) ([]ArtifactDrift, error) {
+	descriptor, err := ReadPackageDescriptor(sourceDirectory)
+	if err != nil {
+		return nil, err
+	}
+	names := map[string]string{}
+	for _, artifact := range descriptor.Artifacts {
+		names[artifact.Type+"/"+artifact.ID] = artifact.Name
+	}
+
+	drifts := []ArtifactDrift{}
+	for _, artifactType := range artifactTypes {
+		artifacts, err := client.PackageArtifacts(packageID, artifactType)
+		if err != nil {
+			return nil, err
+		}
+		remote := map[string]Artifact{}
+		for _, artifact := range artifacts {
+			remote[artifact.ID] = artifact
+		}
+		localIDs, err := localArtifactIDs(sourceDirectory, artifactType.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, id := range localIDs {
+			files, err := ReadArtifactDirectory(ArtifactDirectory(sourceDirectory, artifactType.Name, id))
+			if err != nil {
+				return nil, err
+			}
+			drift := ArtifactDrift{ID: id, Name: names[artifactType.Name+"/"+id], Type: artifactType.Name, State: DriftOnlyInRepository, LocalDigest: files.Digest()}
+			if len(drift.Name) == 0 {
+				drift.Name = id
+			}
+			if artifact, ok := remote[id]; ok {
+				delete(remote, id)
+				content, err := client.DownloadArtifact(artifact)
+				if err != nil {
+					return nil, err
+				}
+				tenantFiles, err := ReadArtifactArchive(content)
+				if err != nil {
+					return nil, errors.Wrapf(err, "failed to unpack %v artifact %v", artifactType.Name, id)
+				}
+				drift.TenantDigest = tenantFiles.Digest()
+				drift.State = DriftModified
+				if drift.TenantDigest == drift.LocalDigest {
+					drift.State = DriftUnchanged
+				}
+			}
+			drifts = append(drifts, drift)
+		}
+		for _, artifact := range remote {
+			drifts = append(drifts, ArtifactDrift{ID: artifact.ID, Name: artifact.Name, Type: artifactType.Name, State: DriftOnlyInTenant})
+		}
+	}
+	sort.SliceStable(drifts, func(i, j int) bool {
+		if drifts[i].Type != drifts[j].Type {
+			return drifts[i].Type < drifts[j].Type
+		}
+		return drifts[i].ID < drifts[j].ID
+	})
+	return drifts, nil
+}
+
+// UploadPackage uploads the artifacts which are modified or only exist in the source directory and optionally deploys them.
+// Artifacts which only exist on the tenant are not deleted. The uploaded artifacts are returned.
+func UploadPackage(client PackageClient, packageID string, drifts []ArtifactDrift, sourceDirectory string, deploy bool) ([]ArtifactDrift, error) {
+	uploaded := []ArtifactDrift{}
+	for _, drift := range drifts {
+		if drift.State != DriftModified && drift.State != DriftOnlyInRepository {
+			if drift.State == DriftOnlyInTenant {
+				log.Entry().Warnf("%v artifact %v exists on the tenant, but not in the source directory", drift.Type, drift.ID)
+			}
+			continue
+		}
+		files, err := ReadArtifactDirectory(ArtifactDirectory(sourceDirectory, drift.Type, drift.ID))
+		if err != nil {
+			return uploaded, err
+		}
+		content, err := CreateArtifactArchive(files)
+		if err != nil {
+			return uploaded, errors.Wrapf(err, "failed to pack %v artifact %v", drift.Type, drift.ID)
+		}
+		artifact := Artifact{ID: drift.ID, Name: drift.Name, PackageID: packageID, Type: drift.Type}
+		if drift.State == DriftModified {
+			err = client.UpdateArtifact(artifact, content)
+		} else {
+			err = client.CreateArtifact(artifact, content)
+		}
+		if err != nil {
+			return uploaded, err
+		}
+		log.Entry().Infof("Uploaded %v artifact %v", drift.Type, drift.ID)
+		if deploy {
+			taskID, err := client.DeployArtifact(artifact)
+			if err != nil {
+				return uploaded, err
+			}
+			log.Entry().WithField("taskId", taskID).Infof("Triggered deployment of %v artifact %v", drift.Type, drift.ID)
+		}
+		uploaded = append(uploaded, drift)
+	}
+	return uploaded, nil
+}
+
+// ReadPackageDescriptor reads the package descriptor of the source directory. A missing descriptor results in an empty descriptor.
+func ReadPackageDescriptor(sourceDirectory string) (PackageDescriptor, error) {
+	descriptor := PackageDescriptor{}
+	content, err := os.ReadFile(filepath.Join(sourceDirectory, PackageDescriptorFile))
+	if os.IsNotExist(err) {
+		return descriptor, nil
+	}
+	if err != nil {
+		return descriptor, errors.Wrap(err, "failed to read package descriptor")
+	}
+	if err := json.Unmarshal(content, &descriptor); err != nil {
+		return descriptor, errors.Wrapf(err, "failed to parse package descriptor %v", filepath.Join(sourceDirectory, PackageDescriptorFile))
+	}
+	return descriptor, nil
+}
+
+// WritePackageDescriptor writes the package descriptor into the source directory
+func WritePackageDescriptor(sourceDirectory string, descriptor PackageDescriptor) error {
+	content, err := json.MarshalIndent(descriptor, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to create package descriptor")
+	}
+	if err := os.MkdirAll(sourceDirectory, 0755); err != nil {
+		return errors.Wrapf(err, "failed to create source directory %v", sourceDirectory)
+	}
+	return os.WriteFile(filepath.Join(sourceDirectory, PackageDescriptorFile), append(content, '\n'), 0644)
+}
+
+func localArtifactIDs(sourceDirectory, artifactType string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(sourceDirectory, artifactType))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %v artifacts of source directory", artifactType)
+	}
+	ids := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			ids = append(ids, entry.Name())
+		}
+	}
+	return ids, nil
+}
+
+func sortDescriptorArtifacts(artifacts []DescriptorArtifact) {
+	sort.SliceStable(artifacts, func(i, j int) bool {
+		if artifacts[i].Type != artifacts[j].Type {
+			return artifacts[i].Type < artifacts[j].Type
+		}
+		return artifacts[i].ID < artifacts[j].ID
+	})
+}
@@ -0,0 +1,140 @@
+//go:build unit
+// +build unit
+
+package cpi_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/cpi"
+	"github.com/SAP/jenkins-library/pkg/cpi/mocks"
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func archive(t *testing.T, files cpi.ArtifactFiles) []byte {
+	content, err := cpi.CreateArtifactArchive(files)
+	require.NoError(t, err)
+	return content
+}
+
+func newPackageServer(t *testing.T) *mocks.Server {
+	server := mocks.NewServer()
+	t.Cleanup(server.Close)
+	server.AddArtifact(cpi.Artifact{ID: "Flow1", Name: "Flow 1", PackageID: "pkg", Type: "IntegrationFlow"}, archive(t, cpi.ArtifactFiles{"src/flow.iflw": []byte("<flow1/>")}))
+	server.AddArtifact(cpi.Artifact{ID: "Flow2", Name: "Flow 2", PackageID: "pkg", Type: "IntegrationFlow"}, archive(t, cpi.ArtifactFiles{"src/flow.iflw": []byte("<flow2/>")}))
+	server.AddArtifact(cpi.Artifact{ID: "Scripts", Name: "Scripts", PackageID: "pkg", Type: "ScriptCollection"}, archive(t, cpi.ArtifactFiles{"script.groovy": []byte("println 1\n")}))
+	server.AddArtifact(cpi.Artifact{ID: "Other", Name: "Other", PackageID: "other", Type: "IntegrationFlow"}, archive(t, cpi.ArtifactFiles{"src/flow.iflw": []byte("<other/>")}))
+	return server
+}
+
+func newPackageClient(t *testing.T, server *mocks.Server) *cpi.DesigntimeClient {
+	serviceKey, err := cpi.ReadCpiServiceKey(server.ServiceKey())
+	require.NoError(t, err)
+	client, err := cpi.NewDesigntimeClient(serviceKey, &piperhttp.Client{})
+	require.NoError(t, err)
+	return client
+}
+
+func TestDownloadPackage(t *testing.T) {
+	server := newPackageServer(t)
+	client := newPackageClient(t, server)
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "IntegrationFlow", "Removed"), 0755))
+
+	descriptor, err := cpi.DownloadPackage(client, "pkg", cpi.ArtifactTypes, dir)
+
+	require.NoError(t, err)
+	assert.Equal(t, []cpi.DescriptorArtifact{
+		{ID: "Flow1", Name: "Flow 1", Type: "IntegrationFlow"},
+		{ID: "Flow2", Name: "Flow 2", Type: "IntegrationFlow"},
+		{ID: "Scripts", Name: "Scripts", Type: "ScriptCollection"},
+	}, descriptor.Artifacts)
+	content, err := os.ReadFile(filepath.Join(dir, "IntegrationFlow", "Flow1", "src", "flow.iflw"))
+	require.NoError(t, err)
+	assert.Equal(t, "<flow1/>", string(content))
+	assert.NoDirExists(t, filepath.Join(dir, "IntegrationFlow", "Removed"))
+	assert.NoDirExists(t, filepath.Join(dir, "IntegrationFlow", "Other"))
+
+	stored, err := cpi.ReadPackageDescriptor(dir)
+	require.NoError(t, err)
+	assert.Equal(t, descriptor, stored)
+}
+
+func TestComparePackageAndUpload(t *testing.T) {
+	server := newPackageServer(t)
+	client := newPackageClient(t, server)
+	dir := t.TempDir()
+	_, err := cpi.DownloadPackage(client, "pkg", cpi.ArtifactTypes, dir)
+	require.NoError(t, err)
+
+	// modify one artifact, add a new one and remove one from the source directory
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "IntegrationFlow", "Flow1", "src", "flow.iflw"), []byte("<changed/>"), 0644))
+	require.NoError(t, cpi.WriteArtifactDirectory(filepath.Join(dir, "MessageMapping", "Mapping"), cpi.ArtifactFiles{"mapping.mmap": []byte("<mapping/>")}))
+	require.NoError(t, os.RemoveAll(filepath.Join(dir, "IntegrationFlow", "Flow2")))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ScriptCollection", "Scripts", "script.groovy"), []byte("println 1\r\n"), 0644))
+
+	drifts, err := cpi.ComparePackage(client, "pkg", cpi.ArtifactTypes, dir)
+	require.NoError(t, err)
+	states := map[string]cpi.DriftState{}
+	for _, drift := range drifts {
+		states[drift.Type+"/"+drift.ID] = drift.State
+	}
+	assert.Equal(t, map[string]cpi.DriftState{
+		"IntegrationFlow/Flow1":    cpi.DriftModified,
+		"IntegrationFlow/Flow2":    cpi.DriftOnlyInTenant,
+		"MessageMapping/Mapping":   cpi.DriftOnlyInRepository,
+		"ScriptCollection/Scripts": cpi.DriftUnchanged,
+	}, states)
+
+	uploaded, err := cpi.UploadPackage(client, "pkg", drifts, dir, true)
+	require.NoError(t, err)
+	assert.Len(t, uploaded, 2)
+	assert.Equal(t, []string{"IntegrationFlow/Flow1", "MessageMapping/Mapping"}, server.Deployed)
+	created, ok := server.Artifact("MessageMapping", "Mapping")
+	require.True(t, ok)
+	assert.Equal(t, "pkg", created.PackageID)
+	_, ok = server.Artifact("IntegrationFlow", "Flow2")
+	assert.True(t, ok, "artifacts only existing on the tenant must not be deleted")
+
+	drifts, err = cpi.ComparePackage(client, "pkg", cpi.ArtifactTypes, dir)
+	require.NoError(t, err)
+	for _, drift := range drifts {
+		if drift.ID != "Flow2" {
+			assert.Equal(t, cpi.DriftUnchanged, drift.State, drift.ID)
+		}
+	}
+}
+
+func TestUpdateArtifactRecreate(t *testing.T) {
+	server := newPackageServer(t)
+	server.AddArtifact(cpi.Artifact{ID: "VM", Name: "Value Mapping", PackageID: "pkg", Type: "ValueMapping"}, archive(t, cpi.ArtifactFiles{"value_mapping.xml": []byte("<a/>")}))
+	client := newPackageClient(t, server)
+
+	err := client.UpdateArtifact(cpi.Artifact{ID: "VM", Name: "Value Mapping", PackageID: "pkg", Type: "ValueMapping"}, archive(t, cpi.ArtifactFiles{"value_mapping.xml": []byte("<b/>")}))
+
+	require.NoError(t, err)
+	stored, ok := server.Artifact("ValueMapping", "VM")
+	require.True(t, ok)
+	files, err := cpi.ReadArtifactArchive(stored.Content)
+	require.NoError(t, err)
+	assert.Equal(t, "<b/>", string(files["value_mapping.xml"]))
+	assert.Contains(t, server.Requests, "DELETE /api/v1/ValueMappingDesigntimeArtifacts(Id='VM',Version='active')")
+}
+
+func TestDesigntimeClientErrors(t *testing.T) {
+	server := newPackageServer(t)
+	client := newPackageClient(t, server)
+
+	_, err := client.DownloadArtifact(cpi.Artifact{ID: "Unknown", Type: "IntegrationFlow"})
+	assert.ErrorContains(t, err, "failed to download IntegrationFlow artifact Unknown")
+
+	_, err = client.DownloadArtifact(cpi.Artifact{ID: "Flow1", Type: "Unknown"})
+	assert.EqualError(t, err, "unknown artifact type 'Unknown'")
+
+	err = client.CreateArtifact(cpi.Artifact{ID: "Flow1", Name: "Flow 1", PackageID: "pkg", Type: "IntegrationFlow"}, nil)
+	assert.ErrorContains(t, err, "failed to create IntegrationFlow artifact Flow1")
+}
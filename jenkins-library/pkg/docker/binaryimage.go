@@ -0,0 +1,185 @@
+package docker
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"io"
+	"path"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/pkg/errors"
+)
+
+// BinaryImageDir is the directory of the image the binaries are added to, like ko does
+const BinaryImageDir = "/ko-app"
+
+// Binary is an executable which is added to an image
+type Binary struct {
+	// Path is the local path of the binary
+	Path string
+	// Name is the file name of the binary in the image
+	Name string
+}
+
+// PlatformBinaries are the binaries built for one platform
+type PlatformBinaries struct {
+	Platform v1.Platform
+	Binaries []Binary
+}
+
+type binaryReader interface {
+	FileRead(path string) ([]byte, error)
+}
+
+// BinaryImageIndex appends a layer with the binaries of each platform onto the base image of the platform and returns
+// the multi-arch index of the resulting images. The first binary of a platform is the entrypoint of its image.
+func BinaryImageIndex(baseImage string, platforms []PlatformBinaries, utils binaryReader, options ...remote.Option) (v1.ImageIndex, error) {
+	baseRef, err := name.ParseReference(baseImage)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid base image '%v'", baseImage)
+	}
+	base, err := remote.Get(baseRef, options...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read base image '%v'", baseImage)
+	}
+
+	index := mutate.IndexMediaType(empty.Index, types.OCIImageIndex)
+	for _, platform := range platforms {
+		if platform.Platform.OS != "linux" {
+			return nil, fmt.Errorf("images can only be created for linux, not for %v", platform.Platform)
+		}
+		if len(platform.Binaries) == 0 {
+			return nil, fmt.Errorf("no binaries built for %v", platform.Platform)
+		}
+		image, err := platformImage(base, platform.Platform)
+		if err != nil {
+			return nil, errors.Wrapf(err, "base image '%v'", baseImage)
+		}
+		if image, err = appendBinaries(image, baseRef, platform, utils); err != nil {
+			return nil, err
+		}
+		platform := platform.Platform
+		index = mutate.AppendManifests(index, mutate.IndexAddendum{Add: image, Descriptor: v1.Descriptor{Platform: &platform}})
+	}
+	return index, nil
+}
+
+// platformImage returns the image of the platform from a multi-arch index or the image itself if it matches the platform
+func platformImage(base *remote.Descriptor, platform v1.Platform) (v1.Image, error) {
+	if base.MediaType.IsIndex() {
+		index, err := base.ImageIndex()
+		if err != nil {
+			return nil, err
+		}
+		manifest, err := index.IndexManifest()
+		if err != nil {
+			return nil, err
+		}
+		for _, descriptor := range manifest.Manifests {
+			if descriptor.Platform != nil && descriptor.Platform.Satisfies(platform) {
+				return index.Image(descriptor.Digest)
+			}
+		}
+		return nil, fmt.Errorf("does not provide platform %v", platform)
+	}
+	image, err := base.Image()
+	if err != nil {
+		return nil, err
+	}
+	config, err := image.ConfigFile()
+	if err != nil {
+		return nil, err
+	}
+	if imagePlatform := config.Platform(); imagePlatform != nil && !imagePlatform.Satisfies(platform) {
+		return nil, fmt.Errorf("is built for %v, not for %v", imagePlatform, platform)
+	}
+	return image, nil
+}
+
+func appendBinaries(image v1.Image, baseRef name.Reference, platform PlatformBinaries, utils binaryReader) (v1.Image, error) {
+	mediaType, err := image.MediaType()
+	if err != nil {
+		return nil, err
+	}
+	baseDigest, err := image.Digest()
+	if err != nil {
+		return nil, err
+	}
+	content, err := binaryLayerContent(platform.Binaries, utils)
+	if err != nil {
+		return nil, err
+	}
+	layerMediaType := types.DockerLayer
+	if mediaType == types.OCIManifestSchema1 {
+		layerMediaType = types.OCILayer
+	}
+	layer, err := tarball.LayerFromOpener(func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(content)), nil
+	}, tarball.WithMediaType(layerMediaType))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create binary layer")
+	}
+	image, err = mutate.Append(image, mutate.Addendum{
+		Layer:   layer,
+		History: v1.History{CreatedBy: "piper golangBuild", Comment: "binaries for " + platform.Platform.String()},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to append binary layer")
+	}
+
+	config, err := image.ConfigFile()
+	if err != nil {
+		return nil, err
+	}
+	config = config.DeepCopy()
+	config.OS = platform.Platform.OS
+	config.Architecture = platform.Platform.Architecture
+	config.Variant = platform.Platform.Variant
+	config.Config.Entrypoint = []string{path.Join(BinaryImageDir, platform.Binaries[0].Name)}
+	config.Config.Cmd = nil
+	if image, err = mutate.ConfigFile(image, config); err != nil {
+		return nil, errors.Wrap(err, "failed to update image config")
+	}
+	if mediaType == types.OCIManifestSchema1 {
+		image = mutate.Annotations(image, map[string]string{
+			"org.opencontainers.image.base.name":   baseRef.Name(),
+			"org.opencontainers.image.base.digest": baseDigest.String(),
+		}).(v1.Image)
+	}
+	return image, nil
+}
+
+// binaryLayerContent creates the tar containing the binaries, timestamps are fixed to get reproducible layers
+func binaryLayerContent(binaries []Binary, utils binaryReader) ([]byte, error) {
+	var buffer bytes.Buffer
+	writer := tar.NewWriter(&buffer)
+	dir := BinaryImageDir[1:]
+	if err := writer.WriteHeader(&tar.Header{Name: dir + "/", Typeflag: tar.TypeDir, Mode: 0o555, ModTime: time.Unix(0, 0)}); err != nil {
+		return nil, err
+	}
+	for _, binary := range binaries {
+		content, err := utils.FileRead(binary.Path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read binary %v", binary.Path)
+		}
+		header := &tar.Header{Name: path.Join(dir, binary.Name), Typeflag: tar.TypeReg, Mode: 0o555, Size: int64(len(content)), ModTime: time.Unix(0, 0)}
+		if err := writer.WriteHeader(header); err != nil {
+			return nil, err
+		}
+		if _, err := writer.Write(content); err != nil {
+			return nil, err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,131 @@
+//go:build unit
+// +build unit
+
+package docker
+
+import (
+	"archive/tar"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBinaryImageIndex(t *testing.T) {
+	server := httptest.NewServer(registry.New())
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	amd64 := v1.Platform{OS: "linux", Architecture: "amd64"}
+	arm64 := v1.Platform{OS: "linux", Architecture: "arm64"}
+	baseImage := func(t *testing.T, platform v1.Platform) v1.Image {
+		image, err := random.Image(64, 1)
+		require.NoError(t, err)
+		image = mutate.MediaType(image, types.OCIManifestSchema1)
+		config, err := image.ConfigFile()
+		require.NoError(t, err)
+		config.OS, config.Architecture = platform.OS, platform.Architecture
+		config.Config.Cmd = []string{"/bin/sh"}
+		image, err = mutate.ConfigFile(image, config)
+		require.NoError(t, err)
+		return image
+	}
+	base := mutate.IndexMediaType(empty.Index, types.OCIImageIndex)
+	for _, platform := range []v1.Platform{amd64, arm64} {
+		platform := platform
+		base = mutate.AppendManifests(base, mutate.IndexAddendum{Add: baseImage(t, platform), Descriptor: v1.Descriptor{Platform: &platform}})
+	}
+	baseTag, _ := name.NewTag(host + "/distroless/static:nonroot")
+	require.NoError(t, remote.WriteIndex(baseTag, base))
+
+	utils := &mock.FilesMock{}
+	utils.AddFile("app-linux.amd64", []byte("amd64 binary"))
+	utils.AddFile("app-linux.arm64", []byte("arm64 binary"))
+
+	t.Run("binaries are added to the base image of their platform", func(t *testing.T) {
+		index, err := BinaryImageIndex(baseTag.String(), []PlatformBinaries{
+			{Platform: amd64, Binaries: []Binary{{Path: "app-linux.amd64", Name: "app"}}},
+			{Platform: arm64, Binaries: []Binary{{Path: "app-linux.arm64", Name: "app"}}},
+		}, utils)
+		require.NoError(t, err)
+
+		manifest, err := index.IndexManifest()
+		require.NoError(t, err)
+		require.Len(t, manifest.Manifests, 2)
+		for i, platform := range []v1.Platform{amd64, arm64} {
+			descriptor := manifest.Manifests[i]
+			assert.Equal(t, platform, *descriptor.Platform)
+			image, err := index.Image(descriptor.Digest)
+			require.NoError(t, err)
+			config, err := image.ConfigFile()
+			require.NoError(t, err)
+			assert.Equal(t, []string{"/ko-app/app"}, config.Config.Entrypoint)
+			assert.Empty(t, config.Config.Cmd)
+			imageManifest, err := image.Manifest()
+			require.NoError(t, err)
+			assert.Equal(t, baseTag.Name(), imageManifest.Annotations["org.opencontainers.image.base.name"])
+			layers, err := image.Layers()
+			require.NoError(t, err)
+			require.Len(t, layers, 2)
+			mediaType, _ := layers[1].MediaType()
+			assert.Equal(t, types.OCILayer, mediaType)
+			files := layerFiles(t, layers[1])
+			assert.Equal(t, map[string]string{"ko-app/": "", "ko-app/app": platform.Architecture + " binary"}, files)
+		}
+	})
+
+	t.Run("platform not provided by the base image", func(t *testing.T) {
+		_, err := BinaryImageIndex(baseTag.String(), []PlatformBinaries{
+			{Platform: v1.Platform{OS: "linux", Architecture: "s390x"}, Binaries: []Binary{{Path: "app-linux.amd64", Name: "app"}}},
+		}, utils)
+
+		assert.EqualError(t, err, "base image '"+baseTag.String()+"': does not provide platform linux/s390x")
+	})
+
+	t.Run("windows is not supported", func(t *testing.T) {
+		_, err := BinaryImageIndex(baseTag.String(), []PlatformBinaries{
+			{Platform: v1.Platform{OS: "windows", Architecture: "amd64"}, Binaries: []Binary{{Path: "app.exe", Name: "app.exe"}}},
+		}, utils)
+
+		assert.EqualError(t, err, "images can only be created for linux, not for windows/amd64")
+	})
+
+	t.Run("missing binary", func(t *testing.T) {
+		_, err := BinaryImageIndex(baseTag.String(), []PlatformBinaries{
+			{Platform: amd64, Binaries: []Binary{{Path: "missing", Name: "app"}}},
+		}, utils)
+
+		assert.ErrorContains(t, err, "failed to read binary missing")
+	})
+}
+
+func layerFiles(t *testing.T, layer v1.Layer) map[string]string {
+	content, err := layer.Uncompressed()
+	require.NoError(t, err)
+	defer content.Close()
+	files := map[string]string{}
+	reader := tar.NewReader(content)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		data, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		files[header.Name] = string(data)
+	}
+	return files
+}
@@ -0,0 +1,45 @@
+package docker
+
+import (
+	"encoding/json"
+
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/docker/registry"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/pkg/errors"
+)
+
+type configFileKeychain struct {
+	config *configfile.ConfigFile
+}
+
+// NewKeychain returns a keychain providing the credentials of the Docker config.json content.
+// Registries without credentials in the config are accessed anonymously.
+func NewKeychain(configJSON []byte) (authn.Keychain, error) {
+	config := &configfile.ConfigFile{}
+	if len(configJSON) > 0 {
+		if err := json.Unmarshal(configJSON, config); err != nil {
+			return nil, errors.Wrap(err, "failed to parse docker config.json")
+		}
+	}
+	return &configFileKeychain{config: config}, nil
+}
+
+func (k *configFileKeychain) Resolve(target authn.Resource) (authn.Authenticator, error) {
+	for key, auth := range k.config.GetAuthConfigs() {
+		if registry.ConvertToHostname(key) != target.RegistryStr() {
+			continue
+		}
+		if auth.Username == "" && auth.Password == "" && auth.Auth == "" && auth.IdentityToken == "" && auth.RegistryToken == "" {
+			break
+		}
+		return authn.FromConfig(authn.AuthConfig{
+			Username:      auth.Username,
+			Password:      auth.Password,
+			Auth:          auth.Auth,
+			IdentityToken: auth.IdentityToken,
+			RegistryToken: auth.RegistryToken,
+		}), nil
+	}
+	return authn.Anonymous, nil
+}
@@ -0,0 +1,42 @@
+//go:build unit
+// +build unit
+
+package docker
+
+import (
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewKeychain(t *testing.T) {
+	keychain, err := NewKeychain([]byte(`{"auths": {
+		"https://registry.example.org": {"auth": "dXNlcjpwYXNzd29yZA=="},
+		"other.example.org": {"username": "other", "password": "secret"},
+		"empty.example.org": {}
+	}}`))
+	require.NoError(t, err)
+
+	resolve := func(image string) *authn.AuthConfig {
+		ref, err := name.ParseReference(image)
+		require.NoError(t, err)
+		authenticator, err := keychain.Resolve(ref.Context())
+		require.NoError(t, err)
+		config, err := authenticator.Authorization()
+		require.NoError(t, err)
+		return config
+	}
+
+	assert.Equal(t, &authn.AuthConfig{Auth: "dXNlcjpwYXNzd29yZA=="}, resolve("registry.example.org/app:1.0.0"))
+	assert.Equal(t, &authn.AuthConfig{Username: "other", Password: "secret"}, resolve("other.example.org/app:1.0.0"))
+	assert.Equal(t, &authn.AuthConfig{}, resolve("empty.example.org/app:1.0.0"))
+	assert.Equal(t, &authn.AuthConfig{}, resolve("unknown.example.org/app:1.0.0"))
+
+	t.Run("invalid config", func(t *testing.T) {
+		_, err := NewKeychain([]byte(`{`))
+		assert.Contains(t, err.Error(), "failed to parse docker config.json")
+	})
+}
@@ -0,0 +1,217 @@
+package dotnet
+
+import (
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// trxTestRun is the relevant part of a Visual Studio test results file (.trx) as written by the trx logger of dotnet test
+type trxTestRun struct {
+	XMLName xml.Name `xml:"TestRun"`
+	Times   struct {
+		Start string `xml:"start,attr"`
+	} `xml:"Times"`
+	Results         []trxResult         `xml:"Results>UnitTestResult"`
+	TestDefinitions []trxTestDefinition `xml:"TestDefinitions>UnitTest"`
+}
+
+type trxResult struct {
+	TestID       string `xml:"testId,attr"`
+	TestName     string `xml:"testName,attr"`
+	ComputerName string `xml:"computerName,attr"`
+	Duration     string `xml:"duration,attr"`
+	Outcome      string `xml:"outcome,attr"`
+	Output       struct {
+		StdOut    string `xml:"StdOut"`
+		StdErr    string `xml:"StdErr"`
+		ErrorInfo struct {
+			Message    string `xml:"Message"`
+			StackTrace string `xml:"StackTrace"`
+		} `xml:"ErrorInfo"`
+	} `xml:"Output"`
+	// InnerResults contains the results of data driven tests
+	InnerResults []trxResult `xml:"InnerResults>UnitTestResult"`
+}
+
+type trxTestDefinition struct {
+	ID         string `xml:"id,attr"`
+	Name       string `xml:"name,attr"`
+	Storage    string `xml:"storage,attr"`
+	TestMethod struct {
+		ClassName string `xml:"className,attr"`
+		Name      string `xml:"name,attr"`
+	} `xml:"TestMethod"`
+}
+
+type junitTestSuites struct {
+	XMLName   xml.Name         `xml:"testsuites"`
+	Tests     int              `xml:"tests,attr"`
+	Failures  int              `xml:"failures,attr"`
+	Errors    int              `xml:"errors,attr"`
+	Skipped   int              `xml:"skipped,attr"`
+	Time      string           `xml:"time,attr"`
+	TestSuite []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr,omitempty"`
+	Hostname  string          `xml:"hostname,attr,omitempty"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitProblem `xml:"failure,omitempty"`
+	Error     *junitProblem `xml:"error,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+	SystemErr string        `xml:"system-err,omitempty"`
+}
+
+type junitProblem struct {
+	Message string `xml:"message,attr"`
+	Content string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr,omitempty"`
+}
+
+// TrxToJUnit converts a Visual Studio test results file (.trx) into a JUnit XML report, the tests are grouped into test suites by their class
+func TrxToJUnit(trx []byte) ([]byte, error) {
+	var run trxTestRun
+	if err := xml.Unmarshal(trx, &run); err != nil {
+		return nil, errors.Wrap(err, "failed to parse test results")
+	}
+	definitions := map[string]trxTestDefinition{}
+	for _, definition := range run.TestDefinitions {
+		definitions[definition.ID] = definition
+	}
+
+	suites := map[string]*junitTestSuite{}
+	durations := map[string]time.Duration{}
+	report := junitTestSuites{}
+	var total time.Duration
+	for _, result := range flattenResults(run.Results) {
+		className := definitions[result.TestID].TestMethod.ClassName
+		if len(className) == 0 {
+			className = "dotnet"
+		}
+		suite, ok := suites[className]
+		if !ok {
+			suite = &junitTestSuite{Name: className, Timestamp: trxTimestamp(run.Times.Start), Hostname: result.ComputerName}
+			suites[className] = suite
+		}
+		duration := trxDuration(result.Duration)
+		durations[className] += duration
+		total += duration
+
+		testCase := junitTestCase{
+			Name:      trxTestName(result.TestName, className),
+			ClassName: className,
+			Time:      seconds(duration),
+			SystemOut: strings.TrimSpace(result.Output.StdOut),
+			SystemErr: strings.TrimSpace(result.Output.StdErr),
+		}
+		problem := &junitProblem{Message: strings.TrimSpace(result.Output.ErrorInfo.Message), Content: strings.TrimSpace(result.Output.ErrorInfo.StackTrace)}
+		switch result.Outcome {
+		case "Passed", "PassedButRunAborted", "Warning", "Completed":
+		case "Failed":
+			testCase.Failure = problem
+			suite.Failures++
+		case "NotExecuted", "NotRunnable", "Inconclusive", "Pending", "Disconnected":
+			testCase.Skipped = &junitSkipped{Message: problem.Message}
+			suite.Skipped++
+		default:
+			// Error, Timeout, Aborted
+			if len(problem.Message) == 0 {
+				problem.Message = result.Outcome
+			}
+			testCase.Error = problem
+			suite.Errors++
+		}
+		suite.Tests++
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+
+	classNames := make([]string, 0, len(suites))
+	for className := range suites {
+		classNames = append(classNames, className)
+	}
+	sort.Strings(classNames)
+	for _, className := range classNames {
+		suite := suites[className]
+		suite.Time = seconds(durations[className])
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Errors += suite.Errors
+		report.Skipped += suite.Skipped
+		report.TestSuite = append(report.TestSuite, *suite)
+	}
+	report.Time = seconds(total)
+
+	content, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), content...), nil
+}
+
+// flattenResults replaces the results of data driven tests by the results of the single runs
+func flattenResults(results []trxResult) []trxResult {
+	flat := []trxResult{}
+	for _, result := range results {
+		if len(result.InnerResults) > 0 {
+			flat = append(flat, flattenResults(result.InnerResults)...)
+			continue
+		}
+		flat = append(flat, result)
+	}
+	return flat
+}
+
+// trxTestName removes the class from the fully qualified test names written by some test frameworks
+func trxTestName(testName, className string) string {
+	if name := strings.TrimPrefix(testName, className+"."); len(name) > 0 {
+		return name
+	}
+	return testName
+}
+
+// trxDuration parses durations in the format hh:mm:ss.fffffff
+func trxDuration(value string) time.Duration {
+	parts := strings.Split(value, ":")
+	if len(parts) != 3 {
+		return 0
+	}
+	hours, _ := strconv.Atoi(parts[0])
+	minutes, _ := strconv.Atoi(parts[1])
+	secs, _ := strconv.ParseFloat(parts[2], 64)
+	return time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute + time.Duration(secs*float64(time.Second))
+}
+
+func trxTimestamp(value string) string {
+	start, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return ""
+	}
+	return start.UTC().Format("2006-01-02T15:04:05")
+}
+
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
@@ -0,0 +1,100 @@
+//go:build unit
+// +build unit
+
+package dotnet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const trxResults = `<?xml version="1.0" encoding="utf-8"?>
+<TestRun id="1f5d3c0e" name="runner@build 2024-01-01 12:00:00" xmlns="http://microsoft.com/schemas/VisualStudio/TeamTest/2010">
+  <Times creation="2024-01-01T12:00:00.0000000+01:00" start="2024-01-01T12:00:00.5000000+01:00" finish="2024-01-01T12:00:02.0000000+01:00" />
+  <Results>
+    <UnitTestResult executionId="e1" testId="t1" testName="Company.App.Tests.CalculatorTests.Add" computerName="build" duration="00:00:00.2500000" outcome="Passed">
+      <Output>
+        <StdOut>adding</StdOut>
+      </Output>
+    </UnitTestResult>
+    <UnitTestResult executionId="e2" testId="t2" testName="Divide" computerName="build" duration="00:00:01.0000000" outcome="Failed">
+      <Output>
+        <ErrorInfo>
+          <Message>Assert.Equal() Failure</Message>
+          <StackTrace>at Company.App.Tests.CalculatorTests.Divide()</StackTrace>
+        </ErrorInfo>
+      </Output>
+    </UnitTestResult>
+    <UnitTestResult executionId="e3" testId="t3" testName="Parse" computerName="build" duration="00:00:00.0000000" outcome="NotExecuted">
+      <Output>
+        <ErrorInfo>
+          <Message>not yet implemented</Message>
+        </ErrorInfo>
+      </Output>
+    </UnitTestResult>
+    <UnitTestResult executionId="e4" testId="t4" testName="Square" computerName="build" duration="00:00:00.2000000" outcome="Passed">
+      <InnerResults>
+        <UnitTestResult executionId="e5" testId="t4" testName="Square (2)" computerName="build" duration="00:00:00.1000000" outcome="Passed" />
+        <UnitTestResult executionId="e6" testId="t4" testName="Square (3)" computerName="build" duration="00:00:00.1000000" outcome="Timeout" />
+      </InnerResults>
+    </UnitTestResult>
+  </Results>
+  <TestDefinitions>
+    <UnitTest name="Add" storage="/src/tests/bin/release/tests.dll" id="t1">
+      <TestMethod codeBase="tests.dll" className="Company.App.Tests.CalculatorTests" name="Add" />
+    </UnitTest>
+    <UnitTest name="Divide" storage="/src/tests/bin/release/tests.dll" id="t2">
+      <TestMethod codeBase="tests.dll" className="Company.App.Tests.CalculatorTests" name="Divide" />
+    </UnitTest>
+    <UnitTest name="Parse" storage="/src/tests/bin/release/tests.dll" id="t3">
+      <TestMethod codeBase="tests.dll" className="Company.App.Tests.ParserTests" name="Parse" />
+    </UnitTest>
+    <UnitTest name="Square" storage="/src/tests/bin/release/tests.dll" id="t4">
+      <TestMethod codeBase="tests.dll" className="Company.App.Tests.CalculatorTests" name="Square" />
+    </UnitTest>
+  </TestDefinitions>
+</TestRun>
+`
+
+func TestTrxToJUnit(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		junit, err := TrxToJUnit([]byte(trxResults))
+
+		require.NoError(t, err)
+		assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites tests="5" failures="1" errors="1" skipped="1" time="1.450">
+  <testsuite name="Company.App.Tests.CalculatorTests" tests="4" failures="1" errors="1" skipped="0" time="1.450" timestamp="2024-01-01T11:00:00" hostname="build">
+    <testcase name="Add" classname="Company.App.Tests.CalculatorTests" time="0.250">
+      <system-out>adding</system-out>
+    </testcase>
+    <testcase name="Divide" classname="Company.App.Tests.CalculatorTests" time="1.000">
+      <failure message="Assert.Equal() Failure">at Company.App.Tests.CalculatorTests.Divide()</failure>
+    </testcase>
+    <testcase name="Square (2)" classname="Company.App.Tests.CalculatorTests" time="0.100"></testcase>
+    <testcase name="Square (3)" classname="Company.App.Tests.CalculatorTests" time="0.100">
+      <error message="Timeout"></error>
+    </testcase>
+  </testsuite>
+  <testsuite name="Company.App.Tests.ParserTests" tests="1" failures="0" errors="0" skipped="1" time="0.000" timestamp="2024-01-01T11:00:00" hostname="build">
+    <testcase name="Parse" classname="Company.App.Tests.ParserTests" time="0.000">
+      <skipped message="not yet implemented"></skipped>
+    </testcase>
+  </testsuite>
+</testsuites>`, string(junit))
+	})
+
+	t.Run("no results", func(t *testing.T) {
+		junit, err := TrxToJUnit([]byte(`<TestRun xmlns="http://microsoft.com/schemas/VisualStudio/TeamTest/2010"><Results /></TestRun>`))
+
+		require.NoError(t, err)
+		assert.Contains(t, string(junit), `<testsuites tests="0" failures="0" errors="0" skipped="0" time="0.000"></testsuites>`)
+	})
+
+	t.Run("invalid content", func(t *testing.T) {
+		_, err := TrxToJUnit([]byte("no xml"))
+
+		assert.ErrorContains(t, err, "failed to parse test results")
+	})
+}
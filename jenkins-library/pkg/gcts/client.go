@@ -0,0 +1,407 @@
+package gcts
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"sort"
+	"strings"
+
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/pkg/errors"
+)
+
+const apiPath = "/sap/bc/cts_abapvcs"
+
+// maxURLLength is the maximum length of request URLs accepted by the ABAP system
+const maxURLLength = 2000
+
+// ConnectionDetails contains the information required to connect to the gCTS API of an ABAP system
+type ConnectionDetails struct {
+	Host                string
+	Client              string
+	Username            string
+	Password            string
+	QueryParameters     map[string]interface{}
+	SkipSSLVerification bool
+}
+
+// Client is a client for the gCTS REST API of an ABAP system
+type Client struct {
+	details    ConnectionDetails
+	httpClient piperhttp.Sender
+}
+
+// NewClient creates a client for the gCTS API and configures the http client with the credentials of the connection details
+func NewClient(details ConnectionDetails, httpClient piperhttp.Sender) (*Client, error) {
+	cookieJar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating a cookie jar failed")
+	}
+	httpClient.SetOptions(piperhttp.ClientOptions{
+		CookieJar:                 cookieJar,
+		Username:                  details.Username,
+		Password:                  details.Password,
+		MaxRetries:                -1,
+		TransportSkipVerification: details.SkipSSLVerification,
+	})
+	return &Client{details: details, httpClient: httpClient}, nil
+}
+
+// Repository is a repository in the ABAP system
+type Repository struct {
+	Rid           string        `json:"rid"`
+	Name          string        `json:"name"`
+	Role          string        `json:"role"`
+	Type          string        `json:"type"`
+	Vsid          string        `json:"vsid"`
+	Status        string        `json:"status"`
+	Branch        string        `json:"branch"`
+	URL           string        `json:"url"`
+	Version       string        `json:"version,omitempty"`
+	CurrentCommit string        `json:"currentCommit"`
+	Connection    string        `json:"connection"`
+	Config        []ConfigEntry `json:"config"`
+}
+
+// ConfigValue returns the value of the configuration key of the repository
+func (r *Repository) ConfigValue(key string) (string, bool) {
+	for _, entry := range r.Config {
+		if entry.Key == key {
+			return entry.Value, true
+		}
+	}
+	return "", false
+}
+
+// ConfigEntry is a configuration key of a repository
+type ConfigEntry struct {
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	Category string `json:"category,omitempty"`
+}
+
+// ConfigMetadata describes a configuration key supported by the ABAP system
+type ConfigMetadata struct {
+	Ckey         string `json:"ckey"`
+	Ctype        string `json:"ctype"`
+	Cvisible     string `json:"cvisible"`
+	Datatype     string `json:"datatype"`
+	DefaultValue string `json:"defaultValue"`
+	Description  string `json:"description"`
+	Category     string `json:"category"`
+	UiElement    string `json:"uiElement"`
+	Example      string `json:"example"`
+}
+
+// Commit is a commit of the remote repository
+type Commit struct {
+	ID          string `json:"id"`
+	Author      string `json:"author"`
+	AuthorMail  string `json:"authorMail"`
+	Message     string `json:"message"`
+	Description string `json:"description"`
+	Date        string `json:"date"`
+}
+
+// HistoryEntry is an entry of the checkout history of a repository
+type HistoryEntry struct {
+	Rid          string `json:"rid"`
+	CheckoutTime int64  `json:"checkoutTime"`
+	FromCommit   string `json:"fromCommit"`
+	ToCommit     string `json:"toCommit"`
+	Caller       string `json:"caller"`
+	Request      string `json:"request"`
+	Type         string `json:"type"`
+}
+
+// CommitChange is the result of an operation which changes the active commit of a repository
+type CommitChange struct {
+	FromCommit string `json:"fromCommit"`
+	ToCommit   string `json:"toCommit"`
+	Trkorr     string `json:"trkorr,omitempty"`
+}
+
+// ErrorLogEntry is an entry of the error log returned by the gCTS API
+type ErrorLogEntry struct {
+	Time     int64           `json:"time"`
+	User     string          `json:"user"`
+	Section  string          `json:"section"`
+	Action   string          `json:"action"`
+	Severity string          `json:"severity"`
+	Message  string          `json:"message"`
+	Code     string          `json:"code"`
+	Protocol []ErrorProtocol `json:"protocol"`
+}
+
+// ErrorProtocol is the transport protocol attached to an error log entry
+type ErrorProtocol struct {
+	Type     string   `json:"type"`
+	Protocol []string `json:"protocol"`
+}
+
+// APIError is returned for failed requests to the gCTS API and contains the error details of the response
+type APIError struct {
+	StatusCode int             `json:"-"`
+	Exception  string          `json:"exception"`
+	ErrorLog   []ErrorLogEntry `json:"errorLog"`
+	err        error
+}
+
+func (e *APIError) Error() string {
+	return e.err.Error()
+}
+
+func (e *APIError) Unwrap() error {
+	return e.err
+}
+
+// HasCode returns true if the error log contains an entry with the given message code
+func (e *APIError) HasCode(code string) bool {
+	for _, entry := range e.ErrorLog {
+		if entry.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// GetRepository returns the repository with the given ID
+func (c *Client) GetRepository(rid string) (*Repository, error) {
+	var response struct {
+		Result Repository `json:"result"`
+	}
+	if err := c.send(http.MethodGet, "/repository/"+rid, nil, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response.Result, nil
+}
+
+// CreateRepository creates the repository in the ABAP system. It does not fail if the repository already exists.
+func (c *Client) CreateRepository(repository Repository) error {
+	type createRequestBody struct {
+		Repository string     `json:"repository"`
+		Data       Repository `json:"data"`
+	}
+	if len(repository.Name) == 0 {
+		repository.Name = repository.Rid
+	}
+	err := c.send(http.MethodPost, "/repository", nil, createRequestBody{Repository: repository.Rid, Data: repository}, nil)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusInternalServerError && apiErr.Exception == "Repository already exists" {
+		log.Entry().WithField("repository", repository.Rid).Infof("the repository already exists on the ABAP system %v", c.details.Host)
+		return nil
+	}
+	return err
+}
+
+// UpdateRepository updates the properties of an existing repository
+func (c *Client) UpdateRepository(repository Repository) error {
+	type updateRequestBody struct {
+		Rid  string     `json:"rid"`
+		Data Repository `json:"data"`
+	}
+	return c.send(http.MethodPost, "/repository/"+repository.Rid, nil, updateRequestBody{Rid: repository.Rid, Data: repository}, nil)
+}
+
+// CloneRepository clones the remote repository into the local repository. It does not fail if the repository has already been cloned.
+func (c *Client) CloneRepository(rid string) error {
+	err := c.send(http.MethodPost, "/repository/"+rid+"/clone", nil, nil, nil)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		if apiErr.HasCode("GCTS.CLIENT.1420") {
+			log.Entry().WithField("repository", rid).Info("the repository has already been cloned")
+			return nil
+		}
+		if apiErr.HasCode("GCTS.CLIENT.3302") {
+			log.Entry().Error("possible reason: the remote repository is set to 'private'. you need to provide the local ABAP server repository with authentication credentials to the remote Git repository in order to clone it.")
+		}
+	}
+	return err
+}
+
+// SwitchBranch switches the repository from the current branch to the target branch
+func (c *Client) SwitchBranch(rid, currentBranch, targetBranch string) (*CommitChange, error) {
+	var response struct {
+		Result struct {
+			FromCommit string `json:"fromCommit"`
+			ToCommit   string `json:"ToCommit"`
+		} `json:"result"`
+	}
+	err := c.send(http.MethodGet, "/repository/"+rid+"/branches/"+currentBranch+"/switch?branch="+targetBranch, nil, nil, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &CommitChange{FromCommit: response.Result.FromCommit, ToCommit: response.Result.ToCommit}, nil
+}
+
+// PullByCommit imports the given commit into the ABAP system. If no commit is given, the latest commit of the current branch is used.
+func (c *Client) PullByCommit(rid, commit string) (*CommitChange, error) {
+	var response CommitChange
+	if err := c.send(http.MethodGet, "/repository/"+rid+"/pullByCommit", []string{"request=" + commit}, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// Deploy imports the objects of the repository in the given scope, e.g. CRNTCOMMIT, into the ABAP system
+func (c *Client) Deploy(rid, scope string) error {
+	type deployRequestBody struct {
+		Repository string `json:"repository,omitempty"`
+		Scope      string `json:"scope"`
+	}
+	return c.send(http.MethodPost, "/repository/"+rid+"/deploy", nil, deployRequestBody{Scope: scope}, nil)
+}
+
+// SetConfig sets a configuration key of the repository
+func (c *Client) SetConfig(rid, key, value string) error {
+	return c.send(http.MethodPost, "/repository/"+rid+"/config", nil, ConfigEntry{Key: key, Value: value}, nil)
+}
+
+// DeleteConfig deletes a configuration key of the repository
+func (c *Client) DeleteConfig(rid, key string) error {
+	return c.send(http.MethodDelete, "/repository/"+rid+"/config/"+key, nil, nil, nil)
+}
+
+// GetConfigMetadata returns the configuration keys supported by the ABAP system
+func (c *Client) GetConfigMetadata() ([]ConfigMetadata, error) {
+	var response struct {
+		Config []ConfigMetadata `json:"config"`
+	}
+	if err := c.send(http.MethodGet, "/config", nil, nil, &response); err != nil {
+		return nil, err
+	}
+	return response.Config, nil
+}
+
+// GetCommits returns the commits of the current branch of the remote repository
+func (c *Client) GetCommits(rid string) ([]Commit, error) {
+	var response struct {
+		Commits []Commit `json:"commits"`
+	}
+	if err := c.send(http.MethodGet, "/repository/"+rid+"/getCommit", nil, nil, &response); err != nil {
+		return nil, err
+	}
+	return response.Commits, nil
+}
+
+// GetHistory returns the checkout history of the repository, latest entry first
+func (c *Client) GetHistory(rid string) ([]HistoryEntry, error) {
+	var response struct {
+		Result []HistoryEntry `json:"result"`
+	}
+	if err := c.send(http.MethodGet, "/repository/"+rid+"/getHistory", nil, nil, &response); err != nil {
+		return nil, err
+	}
+	return response.Result, nil
+}
+
+// requestURL builds the URL of an API path. The sap-client is appended to the query of the path, additional
+// query parameters follow the client in the given order since the values are passed unencoded for compatibility.
+func (c *Client) requestURL(path string, query []string) (string, error) {
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+	parameters := append([]string{"sap-client=" + c.details.Client}, query...)
+	return AddQueryToURL(c.details.Host+apiPath+path+separator+strings.Join(parameters, "&"), c.details.QueryParameters)
+}
+
+// AddQueryToURL appends the configured query parameters sorted by key to the URL and checks the maximum URL length
+// of the ABAP system. The values are passed unencoded for compatibility.
+func AddQueryToURL(requestURL string, queryParameters map[string]interface{}) (string, error) {
+	for _, key := range sortedKeys(queryParameters) {
+		separator := "&"
+		if !strings.Contains(requestURL, "?") {
+			separator = "?"
+		}
+		requestURL += separator + key + "=" + fmt.Sprint(queryParameters[key])
+	}
+	if len(requestURL) > maxURLLength {
+		return requestURL, errors.Errorf("Url endpoint is longer than %v characters!", maxURLLength)
+	}
+	return requestURL, nil
+}
+
+func (c *Client) send(method, path string, query []string, body interface{}, result interface{}) error {
+	requestURL, err := c.requestURL(path, query)
+	if err != nil {
+		return err
+	}
+
+	header := make(http.Header)
+	var requestBody io.Reader
+	if body != nil {
+		jsonBody, err := json.Marshal(body)
+		if err != nil {
+			return errors.Wrapf(err, "failed to marshal request body for %v", path)
+		}
+		requestBody = bytes.NewBuffer(jsonBody)
+		header.Set("Content-Type", "application/json")
+		header.Add("Accept", "application/json")
+	}
+
+	resp, httpErr := c.httpClient.SendRequest(method, requestURL, requestBody, header, nil)
+	defer func() {
+		if resp != nil && resp.Body != nil {
+			resp.Body.Close()
+		}
+	}()
+	if httpErr != nil {
+		return newAPIError(resp, httpErr)
+	}
+	if resp == nil {
+		return errors.New("did not retrieve a HTTP response")
+	}
+	if result == nil {
+		return nil
+	}
+	return piperhttp.ParseHTTPResponseBodyJSON(resp, result)
+}
+
+// newAPIError parses the error details of a failed response and writes the error log to the step log
+func newAPIError(resp *http.Response, httpErr error) error {
+	apiErr := &APIError{err: httpErr}
+	if resp == nil {
+		return apiErr
+	}
+	apiErr.StatusCode = resp.StatusCode
+	if resp.Body != nil {
+		content, _ := io.ReadAll(resp.Body)
+		if err := json.Unmarshal(content, apiErr); err != nil {
+			log.Entry().Debugf("failed to parse gCTS error response: %v", string(content))
+		}
+	}
+	if len(apiErr.Exception) > 0 {
+		log.Entry().Errorf("gCTS exception: %v", apiErr.Exception)
+	}
+	for _, entry := range apiErr.ErrorLog {
+		log.Entry().Errorf("Time: %v, User: %v, Section: %v, Action: %v, Severity: %v, Message: %v",
+			entry.Time, entry.User, entry.Section, entry.Action, entry.Severity, entry.Message)
+		for _, protocol := range entry.Protocol {
+			log.Entry().Errorf("Type: %v", protocol.Type)
+			for _, line := range protocol.Protocol {
+				if strings.Contains(line, "4 ETW000 ") {
+					line = strings.ReplaceAll(line, "4 ETW000 ", "")
+				} else if strings.Contains(line, "4EETW000 ") {
+					line = strings.ReplaceAll(line, "4EETW000 ", "ERROR: ")
+				}
+				log.Entry().Error(line)
+			}
+		}
+	}
+	return apiErr
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
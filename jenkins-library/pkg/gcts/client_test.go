@@ -0,0 +1,203 @@
+//go:build unit
+// +build unit
+
+package gcts_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/gcts"
+	"github.com/SAP/jenkins-library/pkg/gcts/mocks"
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const remoteURL = "https://github.com/example/repo.git"
+
+func newTestClient(t *testing.T, server *mocks.Server, queryParameters map[string]interface{}) *gcts.Client {
+	client, err := gcts.NewClient(gcts.ConnectionDetails{
+		Host:            server.URL,
+		Client:          "000",
+		Username:        "user",
+		Password:        "password",
+		QueryParameters: queryParameters,
+	}, &piperhttp.Client{})
+	require.NoError(t, err)
+	return client
+}
+
+func newTestServer(t *testing.T) *mocks.Server {
+	server := mocks.NewServer()
+	t.Cleanup(server.Close)
+	server.AddRemote(remoteURL, map[string][]string{
+		"main":    {"c1", "c2", "c3"},
+		"feature": {"f1", "f2"},
+	})
+	return server
+}
+
+func TestClient(t *testing.T) {
+	t.Run("repository lifecycle", func(t *testing.T) {
+		server := newTestServer(t)
+		client := newTestClient(t, server, nil)
+
+		require.NoError(t, client.CreateRepository(gcts.Repository{Rid: "repo", Role: "SOURCE", Type: "GIT", Vsid: "ABC", URL: remoteURL}))
+		require.NoError(t, client.CloneRepository("repo"))
+
+		repository, err := client.GetRepository("repo")
+		require.NoError(t, err)
+		assert.Equal(t, "repo", repository.Name)
+		assert.Equal(t, "c3", repository.CurrentCommit)
+		value, ok := repository.ConfigValue("CURRENT_COMMIT")
+		assert.True(t, ok)
+		assert.Equal(t, "c3", value)
+
+		change, err := client.SwitchBranch("repo", "main", "feature")
+		require.NoError(t, err)
+		assert.Equal(t, &gcts.CommitChange{FromCommit: "c3", ToCommit: "f2"}, change)
+
+		change, err = client.PullByCommit("repo", "f1")
+		require.NoError(t, err)
+		assert.Equal(t, &gcts.CommitChange{FromCommit: "f2", ToCommit: "f1"}, change)
+
+		commits, err := client.GetCommits("repo")
+		require.NoError(t, err)
+		assert.Equal(t, []gcts.Commit{{ID: "f2"}, {ID: "f1"}}, commits)
+
+		history, err := client.GetHistory("repo")
+		require.NoError(t, err)
+		assert.Equal(t, "f1", history[0].ToCommit)
+		assert.Equal(t, "f2", history[0].FromCommit)
+
+		require.NoError(t, client.SetConfig("repo", "VCS_AUTOMATIC_PULL", "X"))
+		require.NoError(t, client.DeleteConfig("repo", "VCS_AUTOMATIC_PULL"))
+		require.NoError(t, client.Deploy("repo", gcts.ScopeCurrentCommit))
+
+		assert.Equal(t, []string{
+			"POST /repository",
+			"POST /repository/repo/clone",
+			"GET /repository/repo",
+			"GET /repository/repo/branches/main/switch",
+			"GET /repository/repo/pullByCommit",
+			"GET /repository/repo/getCommit",
+			"GET /repository/repo/getHistory",
+			"POST /repository/repo/config",
+			"DELETE /repository/repo/config/VCS_AUTOMATIC_PULL",
+			"POST /repository/repo/deploy",
+		}, server.Requests)
+	})
+
+	t.Run("create existing repository", func(t *testing.T) {
+		server := newTestServer(t)
+		server.AddRepository(gcts.Repository{Rid: "repo"})
+		client := newTestClient(t, server, nil)
+
+		assert.NoError(t, client.CreateRepository(gcts.Repository{Rid: "repo", URL: remoteURL}))
+	})
+
+	t.Run("clone repository which has already been cloned", func(t *testing.T) {
+		server := newTestServer(t)
+		server.AddRepository(gcts.Repository{Rid: "repo"})
+		server.Failures["POST /repository/repo/clone"] = gcts.APIError{ErrorLog: []gcts.ErrorLogEntry{{Code: "GCTS.CLIENT.1420", Message: "already cloned"}}}
+		client := newTestClient(t, server, nil)
+
+		assert.NoError(t, client.CloneRepository("repo"))
+	})
+
+	t.Run("error details", func(t *testing.T) {
+		server := newTestServer(t)
+		server.AddRepository(gcts.Repository{Rid: "repo", URL: remoteURL, Branch: "main"})
+		server.Failures["GET /repository/repo/pullByCommit"] = gcts.APIError{
+			Exception: "Import failed",
+			ErrorLog: []gcts.ErrorLogEntry{{
+				Code:     "GCTS.API.500",
+				Severity: "ERROR",
+				Protocol: []gcts.ErrorProtocol{{Type: "Import", Protocol: []string{"4EETW000 object locked"}}},
+			}},
+		}
+		client := newTestClient(t, server, nil)
+
+		_, err := client.PullByCommit("repo", "c1")
+
+		var apiErr *gcts.APIError
+		require.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, 500, apiErr.StatusCode)
+		assert.Equal(t, "Import failed", apiErr.Exception)
+		assert.True(t, apiErr.HasCode("GCTS.API.500"))
+		assert.Contains(t, err.Error(), "500")
+	})
+
+	t.Run("config metadata", func(t *testing.T) {
+		server := newTestServer(t)
+		server.ConfigMetadata = []gcts.ConfigMetadata{{Ckey: "VCS_NO_IMPORT", Datatype: "BOOLEAN", Example: "X"}}
+		client := newTestClient(t, server, map[string]interface{}{"saml2": "disabled"})
+
+		metadata, err := client.GetConfigMetadata()
+
+		require.NoError(t, err)
+		assert.Equal(t, server.ConfigMetadata, metadata)
+	})
+
+	t.Run("url too long", func(t *testing.T) {
+		server := newTestServer(t)
+		long := make([]byte, 2000)
+		for i := range long {
+			long[i] = 'a'
+		}
+		client := newTestClient(t, server, map[string]interface{}{"long": string(long)})
+
+		_, err := client.GetRepository("repo")
+
+		assert.EqualError(t, err, "Url endpoint is longer than 2000 characters!")
+		assert.Empty(t, server.Requests)
+	})
+}
+
+func TestAddQueryToURL(t *testing.T) {
+	query := map[string]interface{}{"sap-language": "EN", "debug": true}
+
+	t.Run("without query", func(t *testing.T) {
+		requestURL, err := gcts.AddQueryToURL("http://host/repository", query)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "http://host/repository?debug=true&sap-language=EN", requestURL)
+	})
+
+	t.Run("with query", func(t *testing.T) {
+		requestURL, err := gcts.AddQueryToURL("http://host/repository?sap-client=000", query)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "http://host/repository?sap-client=000&debug=true&sap-language=EN", requestURL)
+	})
+
+	t.Run("too long", func(t *testing.T) {
+		_, err := gcts.AddQueryToURL("http://host/repository", map[string]interface{}{"long": strings.Repeat("a", 2000)})
+
+		assert.EqualError(t, err, "Url endpoint is longer than 2000 characters!")
+	})
+}
+
+func TestNormalizeConfiguration(t *testing.T) {
+	metadata := []gcts.ConfigMetadata{
+		{Ckey: "VCS_AUTOMATIC_PULL", Datatype: "BOOLEAN", Example: "X"},
+		{Ckey: "VCS_AUTOMATIC_NOTIFICATION", Datatype: "BOOLEAN", Example: "X"},
+		{Ckey: "CLIENT_VCS_LOGLVL", Datatype: "STRING"},
+	}
+
+	normalized := gcts.NormalizeConfiguration(map[string]interface{}{
+		"VCS_AUTOMATIC_PULL":         true,
+		"VCS_AUTOMATIC_NOTIFICATION": "false",
+		"CLIENT_VCS_LOGLVL":          "true",
+		"UNKNOWN":                    42,
+	}, metadata)
+
+	assert.Equal(t, map[string]string{
+		"VCS_AUTOMATIC_PULL":         "X",
+		"VCS_AUTOMATIC_NOTIFICATION": "",
+		"CLIENT_VCS_LOGLVL":          "true",
+		"UNKNOWN":                    "42",
+	}, normalized)
+}
@@ -0,0 +1,262 @@
+//go:build !release
+// +build !release
+
+package mocks
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/SAP/jenkins-library/pkg/gcts"
+)
+
+const apiPath = "/sap/bc/cts_abapvcs"
+
+// Server is a local fake of the gCTS API of an ABAP system which keeps the repositories in memory
+type Server struct {
+	*httptest.Server
+
+	mu sync.Mutex
+	// Repositories are the repositories of the ABAP system by their ID
+	Repositories map[string]*gcts.Repository
+	// RemoteBranches are the commits of the remote repositories by URL and branch, latest commit last
+	RemoteBranches map[string]map[string][]string
+	// Imported are the commits which have been imported into the ABAP system by repository ID
+	Imported map[string][]string
+	// Failures are the error responses by request in the format 'METHOD path', e.g. 'GET /repository/myRepo/pullByCommit'
+	Failures map[string]gcts.APIError
+	// Requests are the requests in the format 'METHOD path' received by the server
+	Requests []string
+	// ConfigMetadata is returned by the configuration metadata endpoint
+	ConfigMetadata []gcts.ConfigMetadata
+}
+
+// NewServer starts a fake gCTS server. The server is closed when Close is called.
+func NewServer() *Server {
+	s := &Server{
+		Repositories:   map[string]*gcts.Repository{},
+		RemoteBranches: map[string]map[string][]string{},
+		Imported:       map[string][]string{},
+		Failures:       map[string]gcts.APIError{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// AddRemote registers a remote repository with the commits of its branches
+func (s *Server) AddRemote(url string, branches map[string][]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.RemoteBranches[url] = branches
+}
+
+// AddRepository adds an existing repository to the ABAP system
+func (s *Server) AddRepository(repository gcts.Repository) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Repositories[repository.Rid] = &repository
+}
+
+// Repository returns a copy of the repository with the given ID
+func (s *Server) Repository(rid string) (gcts.Repository, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	repository, ok := s.Repositories[rid]
+	if !ok {
+		return gcts.Repository{}, false
+	}
+	return *repository, true
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, apiPath)
+	request := r.Method + " " + path
+	s.Requests = append(s.Requests, request)
+	if failure, ok := s.Failures[request]; ok {
+		status := failure.StatusCode
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+		writeJSON(w, status, failure)
+		return
+	}
+
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	switch {
+	case request == "GET /config":
+		writeJSON(w, http.StatusOK, map[string]interface{}{"config": s.ConfigMetadata})
+	case request == "POST /repository":
+		s.createRepository(w, r)
+	case segments[0] == "repository" && len(segments) >= 2:
+		repository, ok := s.Repositories[segments[1]]
+		if !ok {
+			writeException(w, "No relation between system and repository")
+			return
+		}
+		s.handleRepository(w, r, repository, segments[2:])
+	default:
+		writeJSON(w, http.StatusNotFound, map[string]string{"exception": "unknown endpoint " + request})
+	}
+}
+
+func (s *Server) handleRepository(w http.ResponseWriter, r *http.Request, repository *gcts.Repository, segments []string) {
+	action := r.Method + " " + strings.Join(segments, "/")
+	switch {
+	case action == "GET ":
+		writeJSON(w, http.StatusOK, map[string]interface{}{"result": repository})
+	case action == "POST ":
+		var body struct {
+			Data gcts.Repository `json:"data"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeException(w, err.Error())
+			return
+		}
+		repository.Vsid = body.Data.Vsid
+		writeJSON(w, http.StatusOK, map[string]interface{}{"result": repository})
+	case action == "POST clone":
+		commits := s.RemoteBranches[repository.URL][repository.Branch]
+		if len(commits) == 0 {
+			writeException(w, "remote repository "+repository.URL+" not found")
+			return
+		}
+		repository.Status = "READY"
+		s.checkout(repository, commits[len(commits)-1])
+		writeJSON(w, http.StatusOK, map[string]interface{}{"result": map[string]string{"rid": repository.Rid}})
+	case action == "GET pullByCommit":
+		commits := s.RemoteBranches[repository.URL][repository.Branch]
+		commit := r.URL.Query().Get("request")
+		if len(commit) == 0 && len(commits) > 0 {
+			commit = commits[len(commits)-1]
+		}
+		if !contains(commits, commit) {
+			writeException(w, fmt.Sprintf("commit %v does not exist in branch %v", commit, repository.Branch))
+			return
+		}
+		from := repository.CurrentCommit
+		s.checkout(repository, commit)
+		writeJSON(w, http.StatusOK, gcts.CommitChange{FromCommit: from, ToCommit: commit})
+	case action == "GET getCommit":
+		commits := []gcts.Commit{}
+		remote := s.RemoteBranches[repository.URL][repository.Branch]
+		for i := len(remote) - 1; i >= 0; i-- {
+			commits = append(commits, gcts.Commit{ID: remote[i]})
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"commits": commits})
+	case action == "GET getHistory":
+		history := []gcts.HistoryEntry{}
+		imported := s.Imported[repository.Rid]
+		for i := len(imported) - 1; i > 0; i-- {
+			history = append(history, gcts.HistoryEntry{Rid: repository.Rid, FromCommit: imported[i-1], ToCommit: imported[i], Type: "PULL"})
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"result": history})
+	case action == "POST config":
+		var entry gcts.ConfigEntry
+		if err := json.NewDecoder(r.Body).Decode(&entry); err != nil {
+			writeException(w, err.Error())
+			return
+		}
+		setConfig(repository, entry.Key, entry.Value)
+		writeJSON(w, http.StatusOK, map[string]interface{}{"result": entry})
+	case r.Method == http.MethodDelete && len(segments) == 2 && segments[0] == "config":
+		deleteConfig(repository, segments[1])
+		w.WriteHeader(http.StatusNoContent)
+	case action == "POST deploy":
+		s.Imported[repository.Rid] = append(s.Imported[repository.Rid], repository.CurrentCommit)
+		writeJSON(w, http.StatusOK, map[string]interface{}{"result": repository})
+	case r.Method == http.MethodGet && len(segments) == 3 && segments[0] == "branches" && segments[2] == "switch":
+		target := r.URL.Query().Get("branch")
+		if segments[1] != repository.Branch {
+			writeException(w, fmt.Sprintf("branch %v is not the current branch", segments[1]))
+			return
+		}
+		commits, ok := s.RemoteBranches[repository.URL][target]
+		if !ok || len(commits) == 0 {
+			writeException(w, fmt.Sprintf("branch %v does not exist", target))
+			return
+		}
+		from := repository.CurrentCommit
+		repository.Branch = target
+		s.checkout(repository, commits[len(commits)-1])
+		writeJSON(w, http.StatusOK, map[string]interface{}{"result": map[string]string{"fromCommit": from, "ToCommit": repository.CurrentCommit}})
+	default:
+		writeJSON(w, http.StatusNotFound, map[string]string{"exception": "unknown endpoint " + action})
+	}
+}
+
+func (s *Server) createRepository(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Repository string          `json:"repository"`
+		Data       gcts.Repository `json:"data"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeException(w, err.Error())
+		return
+	}
+	if _, ok := s.Repositories[body.Repository]; ok {
+		writeException(w, "Repository already exists")
+		return
+	}
+	repository := body.Data
+	repository.Status = "CREATED"
+	if len(repository.Branch) == 0 {
+		repository.Branch = "main"
+	}
+	s.Repositories[body.Repository] = &repository
+	writeJSON(w, http.StatusCreated, map[string]interface{}{"repository": repository})
+}
+
+// checkout changes the current commit and records the import unless the import is disabled for the repository
+func (s *Server) checkout(repository *gcts.Repository, commit string) {
+	repository.CurrentCommit = commit
+	setConfig(repository, "CURRENT_COMMIT", commit)
+	if value, _ := repository.ConfigValue(gcts.ConfigNoImport); value != "X" {
+		s.Imported[repository.Rid] = append(s.Imported[repository.Rid], commit)
+	}
+}
+
+func setConfig(repository *gcts.Repository, key, value string) {
+	for i := range repository.Config {
+		if repository.Config[i].Key == key {
+			repository.Config[i].Value = value
+			return
+		}
+	}
+	repository.Config = append(repository.Config, gcts.ConfigEntry{Key: key, Value: value})
+}
+
+func deleteConfig(repository *gcts.Repository, key string) {
+	config := []gcts.ConfigEntry{}
+	for _, entry := range repository.Config {
+		if entry.Key != key {
+			config = append(config, entry)
+		}
+	}
+	repository.Config = config
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+func writeException(w http.ResponseWriter, exception string) {
+	writeJSON(w, http.StatusInternalServerError, map[string]string{"exception": exception})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,236 @@
+package gcts
+
+import (
+	"fmt"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/pkg/errors"
+)
+
+const (
+	// ConfigNoImport prevents that pulled objects are imported into the ABAP system
+	ConfigNoImport = "VCS_NO_IMPORT"
+	// ScopeCurrentCommit deploys all objects of the current commit
+	ScopeCurrentCommit = "CRNTCOMMIT"
+)
+
+// RepositoryState is the desired state of a repository in the ABAP system
+type RepositoryState struct {
+	// RemoteURL, Role and Type are only used to create the repository if it does not exist
+	RemoteURL string
+	Role      string
+	Type      string
+	VSID      string
+	// Branch is the branch to be checked out, empty keeps the current branch
+	Branch string
+	// Commit is the commit to be imported, empty imports the latest commit of the branch
+	Commit string
+	Config map[string]string
+	// Rollback restores the branch and commit the repository had before if the reconciliation fails
+	Rollback bool
+}
+
+// ReconcileResult describes the changes done to reach the desired state of a repository
+type ReconcileResult struct {
+	Created    bool
+	Actions    []string
+	Branch     string
+	FromCommit string
+	ToCommit   string
+}
+
+func (r *ReconcileResult) addAction(format string, args ...interface{}) {
+	action := fmt.Sprintf(format, args...)
+	log.Entry().Infof("gCTS: %v", action)
+	r.Actions = append(r.Actions, action)
+}
+
+// LookupRepository returns the repository with the given ID and whether it exists in the ABAP system.
+// An error response of the ABAP system is interpreted as a repository which does not exist.
+func (c *Client) LookupRepository(rid string) (*Repository, bool, error) {
+	repository, err := c.GetRepository(rid)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return repository, len(repository.Rid) > 0, nil
+}
+
+// EnsureRepositoryState reconciles the repository with the desired state. A repository which does not exist is created and cloned.
+// For a new repository with a desired branch or commit the objects are only imported once the desired commit is checked out.
+func (c *Client) EnsureRepositoryState(rid string, desired RepositoryState) (*ReconcileResult, error) {
+	result := &ReconcileResult{}
+	repository, exists, err := c.LookupRepository(rid)
+	if err != nil {
+		return result, errors.Wrapf(err, "failed to read repository %v", rid)
+	}
+
+	if !exists {
+		if len(desired.RemoteURL) == 0 {
+			return result, errors.Errorf("repository %v does not exist and no remote repository URL is configured", rid)
+		}
+		repository, err = c.createAndClone(rid, desired, result)
+		if err != nil || repository == nil {
+			return result, err
+		}
+	} else if err := c.reconcileSettings(repository, desired, result); err != nil {
+		return result, err
+	}
+
+	result.Branch = repository.Branch
+	result.FromCommit = repository.CurrentCommit
+	result.ToCommit = repository.CurrentCommit
+	if err := c.reconcileCommit(repository, desired, result); err != nil {
+		if desired.Rollback {
+			c.rollback(repository, result)
+		}
+		return result, err
+	}
+
+	if result.Created {
+		if err := c.DeleteConfig(rid, ConfigNoImport); err != nil {
+			return result, errors.Wrapf(err, "failed to delete configuration key %v", ConfigNoImport)
+		}
+		if err := c.Deploy(rid, ScopeCurrentCommit); err != nil {
+			return result, errors.Wrap(err, "failed to deploy current commit")
+		}
+		result.addAction("deployed current commit %v", result.ToCommit)
+	}
+	return result, nil
+}
+
+// createAndClone creates and clones a repository. The returned repository is nil if there is nothing left to reconcile.
+func (c *Client) createAndClone(rid string, desired RepositoryState, result *ReconcileResult) (*Repository, error) {
+	repository := Repository{Rid: rid, Name: rid, Role: desired.Role, Type: desired.Type, Vsid: desired.VSID, URL: desired.RemoteURL}
+	for _, key := range sortedKeys(desired.Config) {
+		repository.Config = append(repository.Config, ConfigEntry{Key: key, Value: desired.Config[key]})
+	}
+	if err := c.CreateRepository(repository); err != nil {
+		return nil, errors.Wrapf(err, "creating repository on the ABAP system %v failed", c.details.Host)
+	}
+	result.Created = true
+	result.addAction("created repository %v", rid)
+
+	// without a desired branch or commit the clone imports the latest commit and the repository is in the desired state
+	checkoutRequired := len(desired.Branch) > 0 || len(desired.Commit) > 0
+	if checkoutRequired {
+		if err := c.SetConfig(rid, ConfigNoImport, "X"); err != nil {
+			return nil, errors.Wrapf(err, "failed to set configuration key %v", ConfigNoImport)
+		}
+	}
+	if err := c.CloneRepository(rid); err != nil {
+		return nil, errors.Wrap(err, "cloning the repository failed")
+	}
+	result.addAction("cloned repository %v", rid)
+	if !checkoutRequired {
+		return nil, nil
+	}
+	cloned, err := c.GetRepository(rid)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read repository %v after clone", rid)
+	}
+	return cloned, nil
+}
+
+func (c *Client) reconcileSettings(repository *Repository, desired RepositoryState, result *ReconcileResult) error {
+	if len(desired.VSID) > 0 && desired.VSID != repository.Vsid {
+		updated := *repository
+		updated.Vsid = desired.VSID
+		if err := c.UpdateRepository(updated); err != nil {
+			return errors.Wrap(err, "failed to update vSID")
+		}
+		result.addAction("changed vSID from %v to %v", repository.Vsid, desired.VSID)
+		repository.Vsid = desired.VSID
+	}
+	for _, key := range sortedKeys(desired.Config) {
+		if value, ok := repository.ConfigValue(key); ok && value == desired.Config[key] {
+			continue
+		}
+		if err := c.SetConfig(repository.Rid, key, desired.Config[key]); err != nil {
+			return errors.Wrapf(err, "failed to set configuration key %v", key)
+		}
+		result.addAction("set configuration key %v to '%v'", key, desired.Config[key])
+	}
+	return nil
+}
+
+func (c *Client) reconcileCommit(repository *Repository, desired RepositoryState, result *ReconcileResult) error {
+	if len(desired.Branch) > 0 && desired.Branch != result.Branch {
+		change, err := c.SwitchBranch(repository.Rid, result.Branch, desired.Branch)
+		if err != nil {
+			return errors.Wrapf(err, "failed to switch from branch %v to %v", result.Branch, desired.Branch)
+		}
+		result.addAction("switched branch from %v to %v", result.Branch, desired.Branch)
+		result.Branch = desired.Branch
+		if len(change.ToCommit) > 0 {
+			result.ToCommit = change.ToCommit
+		}
+	}
+
+	// a new repository already contains the latest commit of the branch after the clone and switch
+	pullRequired := len(desired.Commit) > 0 && desired.Commit != result.ToCommit
+	if len(desired.Commit) == 0 && !result.Created {
+		pullRequired = true
+	}
+	if !pullRequired {
+		return nil
+	}
+	change, err := c.PullByCommit(repository.Rid, desired.Commit)
+	if err != nil {
+		return errors.Wrapf(err, "failed to pull commit '%v'", desired.Commit)
+	}
+	if change.FromCommit != change.ToCommit {
+		result.addAction("pulled commit %v (previous commit was %v)", change.ToCommit, change.FromCommit)
+	}
+	result.ToCommit = change.ToCommit
+	return nil
+}
+
+// rollback restores the branch and commit of the repository before the reconciliation
+func (c *Client) rollback(initial *Repository, result *ReconcileResult) {
+	if result.Branch != initial.Branch {
+		log.Entry().Errorf("Rolling back from branch %v to %v", result.Branch, initial.Branch)
+		change, err := c.SwitchBranch(initial.Rid, result.Branch, initial.Branch)
+		if err != nil {
+			log.Entry().WithError(err).Error("rollback of branch failed")
+			return
+		}
+		result.addAction("rolled back branch to %v", initial.Branch)
+		result.Branch = initial.Branch
+		if len(change.ToCommit) > 0 {
+			result.ToCommit = change.ToCommit
+		}
+	}
+	if len(initial.CurrentCommit) > 0 && result.ToCommit != initial.CurrentCommit {
+		log.Entry().Errorf("Rolling back to commit %v", initial.CurrentCommit)
+		if _, err := c.PullByCommit(initial.Rid, initial.CurrentCommit); err != nil {
+			log.Entry().WithError(err).Error("rollback of commit failed")
+			return
+		}
+		result.addAction("rolled back to commit %v", initial.CurrentCommit)
+		result.ToCommit = initial.CurrentCommit
+	}
+}
+
+// NormalizeConfiguration converts configuration values to the format expected by the ABAP system,
+// e.g. boolean values are passed as 'X' or empty string
+func NormalizeConfiguration(configuration map[string]interface{}, metadata []ConfigMetadata) map[string]string {
+	normalized := map[string]string{}
+	for key, value := range configuration {
+		configValue := fmt.Sprint(value)
+		for _, meta := range metadata {
+			if meta.Ckey == key && meta.Datatype == "BOOLEAN" && meta.Example == "X" {
+				if configValue == "false" || configValue == "" {
+					configValue = ""
+				} else if configValue == "true" || configValue == "X" {
+					configValue = "X"
+				}
+			}
+		}
+		normalized[key] = configValue
+	}
+	return normalized
+}
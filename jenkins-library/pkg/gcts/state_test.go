@@ -0,0 +1,105 @@
+//go:build unit
+// +build unit
+
+package gcts_test
+
+import (
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/gcts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnsureRepositoryState(t *testing.T) {
+	t.Run("new repository is cloned", func(t *testing.T) {
+		server := newTestServer(t)
+		client := newTestClient(t, server, nil)
+
+		result, err := client.EnsureRepositoryState("repo", gcts.RepositoryState{RemoteURL: remoteURL, Role: "SOURCE", Type: "GIT", VSID: "ABC", Config: map[string]string{"VCS_AUTOMATIC_PULL": "X"}})
+
+		require.NoError(t, err)
+		assert.True(t, result.Created)
+		assert.Equal(t, []string{"created repository repo", "cloned repository repo"}, result.Actions)
+		repository, _ := server.Repository("repo")
+		assert.Equal(t, "ABC", repository.Vsid)
+		value, _ := repository.ConfigValue("VCS_AUTOMATIC_PULL")
+		assert.Equal(t, "X", value)
+		assert.Equal(t, []string{"c3"}, server.Imported["repo"])
+	})
+
+	t.Run("new repository is imported after checkout of branch and commit", func(t *testing.T) {
+		server := newTestServer(t)
+		client := newTestClient(t, server, nil)
+
+		result, err := client.EnsureRepositoryState("repo", gcts.RepositoryState{RemoteURL: remoteURL, Branch: "feature", Commit: "f1"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "feature", result.Branch)
+		assert.Equal(t, "f1", result.ToCommit)
+		repository, _ := server.Repository("repo")
+		_, noImport := repository.ConfigValue(gcts.ConfigNoImport)
+		assert.False(t, noImport)
+		// only the desired commit is imported into the ABAP system
+		assert.Equal(t, []string{"f1"}, server.Imported["repo"])
+	})
+
+	t.Run("existing repository is reconciled", func(t *testing.T) {
+		server := newTestServer(t)
+		server.AddRepository(gcts.Repository{Rid: "repo", URL: remoteURL, Branch: "main", CurrentCommit: "c1", Vsid: "OLD",
+			Config: []gcts.ConfigEntry{{Key: "VCS_AUTOMATIC_PULL", Value: "X"}}})
+		client := newTestClient(t, server, nil)
+
+		result, err := client.EnsureRepositoryState("repo", gcts.RepositoryState{
+			VSID:   "NEW",
+			Branch: "main",
+			Commit: "c2",
+			Config: map[string]string{"VCS_AUTOMATIC_PULL": "X", "CLIENT_VCS_LOGLVL": "debug"},
+		})
+
+		require.NoError(t, err)
+		assert.False(t, result.Created)
+		assert.Equal(t, []string{
+			"changed vSID from OLD to NEW",
+			"set configuration key CLIENT_VCS_LOGLVL to 'debug'",
+			"pulled commit c2 (previous commit was c1)",
+		}, result.Actions)
+		assert.Equal(t, "c1", result.FromCommit)
+		assert.Equal(t, "c2", result.ToCommit)
+	})
+
+	t.Run("repository in desired state", func(t *testing.T) {
+		server := newTestServer(t)
+		server.AddRepository(gcts.Repository{Rid: "repo", URL: remoteURL, Branch: "main", CurrentCommit: "c2"})
+		client := newTestClient(t, server, nil)
+
+		result, err := client.EnsureRepositoryState("repo", gcts.RepositoryState{Branch: "main", Commit: "c2"})
+
+		require.NoError(t, err)
+		assert.Empty(t, result.Actions)
+		assert.Equal(t, []string{"GET /repository/repo"}, server.Requests)
+	})
+
+	t.Run("rollback after failed pull", func(t *testing.T) {
+		server := newTestServer(t)
+		server.AddRepository(gcts.Repository{Rid: "repo", URL: remoteURL, Branch: "main", CurrentCommit: "c3"})
+		client := newTestClient(t, server, nil)
+
+		result, err := client.EnsureRepositoryState("repo", gcts.RepositoryState{Branch: "feature", Commit: "unknown", Rollback: true})
+
+		assert.EqualError(t, err, "failed to pull commit 'unknown': request to "+server.URL+"/sap/bc/cts_abapvcs/repository/repo/pullByCommit?sap-client=000&request=unknown returned with response 500 Internal Server Error")
+		assert.Equal(t, []string{"switched branch from main to feature", "rolled back branch to main"}, result.Actions)
+		repository, _ := server.Repository("repo")
+		assert.Equal(t, "main", repository.Branch)
+		assert.Equal(t, "c3", repository.CurrentCommit)
+	})
+
+	t.Run("missing repository without remote url", func(t *testing.T) {
+		server := newTestServer(t)
+		client := newTestClient(t, server, nil)
+
+		_, err := client.EnsureRepositoryState("repo", gcts.RepositoryState{Commit: "c1"})
+
+		assert.EqualError(t, err, "repository repo does not exist and no remote repository URL is configured")
+	})
+}
@@ -1,11 +1,13 @@
 package git
 
 import (
+	"strings"
 	"time"
 
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/format/diff"
 	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/go-git/go-git/v5/plumbing/transport/http"
 	"github.com/pkg/errors"
@@ -172,6 +174,105 @@ func LogRange(repo *git.Repository, from, to string) (object.CommitIter, error)
 	return object.NewCommitPreorderIter(cTo, map[plumbing.Hash]bool{}, ignore), nil
 }
 
+// ChangedFiles returns the paths of the files which have been changed on 'head' since it has been branched off 'base',
+// i.e. the changes of a pull request. A branch name as base is also resolved as remote branch of origin.
+func ChangedFiles(repo *git.Repository, base, head string) ([]string, error) {
+	baseTree, headTree, err := mergeBaseTrees(repo, base, head, "changed files")
+	if err != nil {
+		return nil, err
+	}
+	changes, err := object.DiffTree(baseTree, headTree)
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot provide changed files")
+	}
+
+	files := []string{}
+	for _, change := range changes {
+		// renamed files are changed at both locations
+		for _, name := range []string{change.From.Name, change.To.Name} {
+			if len(name) > 0 && (len(files) == 0 || files[len(files)-1] != name) {
+				files = append(files, name)
+			}
+		}
+	}
+	return files, nil
+}
+
+// ChangedLines returns the numbers of the lines which have been added or modified on 'head' since it has been branched off 'base',
+// per path of the changed file. Deleted files and binary files are not contained.
+func ChangedLines(repo *git.Repository, base, head string) (map[string][]int, error) {
+	baseTree, headTree, err := mergeBaseTrees(repo, base, head, "changed lines")
+	if err != nil {
+		return nil, err
+	}
+	patch, err := baseTree.Patch(headTree)
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot provide changed lines")
+	}
+
+	lines := map[string][]int{}
+	for _, filePatch := range patch.FilePatches() {
+		_, to := filePatch.Files()
+		if to == nil || filePatch.IsBinary() {
+			continue
+		}
+		line := 1
+		changed := []int{}
+		for _, chunk := range filePatch.Chunks() {
+			count := strings.Count(chunk.Content(), "\n")
+			if len(chunk.Content()) > 0 && !strings.HasSuffix(chunk.Content(), "\n") {
+				count++
+			}
+			switch chunk.Type() {
+			case diff.Equal:
+				line += count
+			case diff.Add:
+				for i := 0; i < count; i++ {
+					changed = append(changed, line+i)
+				}
+				line += count
+			}
+		}
+		if len(changed) > 0 {
+			lines[to.Path()] = changed
+		}
+	}
+	return lines, nil
+}
+
+// mergeBaseTrees returns the tree of the merge base of 'base' and 'head' and the tree of 'head'
+func mergeBaseTrees(repo *git.Repository, base, head, what string) (*object.Tree, *object.Tree, error) {
+	cHead, err := getCommitObject(head, repo)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "Cannot provide %s (head: '%s' not found)", what, head)
+	}
+	var cBase *object.Commit
+	for _, ref := range []string{base, "refs/remotes/origin/" + base} {
+		if cBase, err = getCommitObject(ref, repo); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "Cannot provide %s (base: '%s' not found)", what, base)
+	}
+	mergeBases, err := cBase.MergeBase(cHead)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "Cannot provide %s", what)
+	}
+	if len(mergeBases) == 0 {
+		return nil, nil, errors.Errorf("Cannot provide %s ('%s' and '%s' have no common ancestor)", what, base, head)
+	}
+	baseTree, err := mergeBases[0].Tree()
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "Cannot provide %s", what)
+	}
+	headTree, err := cHead.Tree()
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "Cannot provide %s", what)
+	}
+	return baseTree, headTree, nil
+}
+
 func getCommitObject(ref string, repo *git.Repository) (*object.Commit, error) {
 	if len(ref) == 0 {
 		// with go-git v5.1.0 we panic otherwise inside ResolveRevision
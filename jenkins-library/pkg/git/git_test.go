@@ -455,3 +455,90 @@ func (UtilsGitMockError) plainClone(string, bool, *git.CloneOptions) (*git.Repos
 func (UtilsGitMockError) plainOpen(path string) (*git.Repository, error) {
 	return nil, errors.New("error during git plain open")
 }
+
+func TestChangedFiles(t *testing.T) {
+	fs := memfs.New()
+	r, err := git.Init(memory.NewStorage(), fs)
+	assert.NoError(t, err)
+	w, err := r.Worktree()
+	assert.NoError(t, err)
+	commit := func(files map[string]string) {
+		for name, content := range files {
+			f, err := fs.Create(name)
+			assert.NoError(t, err)
+			_, err = f.Write([]byte(content))
+			assert.NoError(t, err)
+			f.Close()
+			_, err = w.Add(name)
+			assert.NoError(t, err)
+		}
+		_, err := w.Commit("commit", &git.CommitOptions{Author: &object.Signature{Name: "me", Email: "me@example.org"}})
+		assert.NoError(t, err)
+	}
+
+	commit(map[string]string{"ui/package.json": "{}", "api/pom.xml": "<project/>"})
+	assert.NoError(t, w.Checkout(&git.CheckoutOptions{Create: true, Branch: plumbing.ReferenceName("refs/heads/main")}))
+	assert.NoError(t, w.Checkout(&git.CheckoutOptions{Create: true, Branch: plumbing.ReferenceName("refs/heads/feature")}))
+	commit(map[string]string{"ui/src/index.js": "export {}"})
+	// changes on the base branch after branching off are not part of the pull request
+	assert.NoError(t, w.Checkout(&git.CheckoutOptions{Branch: plumbing.ReferenceName("refs/heads/main")}))
+	commit(map[string]string{"api/pom.xml": "<project><modules/></project>"})
+
+	t.Run("changes since branching off", func(t *testing.T) {
+		files, err := ChangedFiles(r, "main", "feature")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"ui/src/index.js"}, files)
+	})
+
+	t.Run("unknown base", func(t *testing.T) {
+		_, err := ChangedFiles(r, "develop", "feature")
+		assert.ErrorContains(t, err, "Cannot provide changed files (base: 'develop' not found)")
+	})
+}
+
+func TestChangedLines(t *testing.T) {
+	fs := memfs.New()
+	r, err := git.Init(memory.NewStorage(), fs)
+	assert.NoError(t, err)
+	w, err := r.Worktree()
+	assert.NoError(t, err)
+	commit := func(files map[string]string) {
+		for name, content := range files {
+			f, err := fs.Create(name)
+			assert.NoError(t, err)
+			_, err = f.Write([]byte(content))
+			assert.NoError(t, err)
+			f.Close()
+			_, err = w.Add(name)
+			assert.NoError(t, err)
+		}
+		_, err := w.Commit("commit", &git.CommitOptions{Author: &object.Signature{Name: "me", Email: "me@example.org"}})
+		assert.NoError(t, err)
+	}
+
+	commit(map[string]string{"main.go": "package main\n\nfunc main() {\n}\n", "README.md": "# app"})
+	assert.NoError(t, w.Checkout(&git.CheckoutOptions{Create: true, Branch: plumbing.ReferenceName("refs/heads/main")}))
+	assert.NoError(t, w.Checkout(&git.CheckoutOptions{Create: true, Branch: plumbing.ReferenceName("refs/heads/feature")}))
+	commit(map[string]string{
+		"main.go":  "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println()\n}\n",
+		"app/a.go": "package app\n\nvar A = 1",
+	})
+	_, err = w.Remove("README.md")
+	assert.NoError(t, err)
+	_, err = w.Commit("remove", &git.CommitOptions{Author: &object.Signature{Name: "me", Email: "me@example.org"}})
+	assert.NoError(t, err)
+
+	t.Run("added and modified lines", func(t *testing.T) {
+		lines, err := ChangedLines(r, "main", "feature")
+		assert.NoError(t, err)
+		assert.Equal(t, map[string][]int{
+			"main.go":  {3, 4, 6},
+			"app/a.go": {1, 2, 3},
+		}, lines)
+	})
+
+	t.Run("unknown base", func(t *testing.T) {
+		_, err := ChangedLines(r, "develop", "feature")
+		assert.ErrorContains(t, err, "Cannot provide changed lines (base: 'develop' not found)")
+	})
+}
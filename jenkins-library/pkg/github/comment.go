@@ -0,0 +1,25 @@
+package github
+
+import (
+	"context"
+
+	"github.com/google/go-github/v68/github"
+	"github.com/pkg/errors"
+)
+
+// CreatePullRequestComment adds a comment with the given body to a pull request
+func CreatePullRequestComment(token, apiURL, owner, repository string, number int, body string) error {
+	ctx, client, err := NewClientBuilder(token, apiURL).Build()
+	if err != nil {
+		return errors.Wrap(err, "failed to get GitHub client")
+	}
+	return createPullRequestComment(ctx, client.Issues, owner, repository, number, body)
+}
+
+func createPullRequestComment(ctx context.Context, service githubCreateCommentService, owner, repository string, number int, body string) error {
+	_, _, err := service.CreateComment(ctx, owner, repository, number, &github.IssueComment{Body: &body})
+	if err != nil {
+		return errors.Wrapf(err, "failed to comment on pull request #%v", number)
+	}
+	return nil
+}
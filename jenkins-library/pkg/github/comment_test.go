@@ -0,0 +1,32 @@
+//go:build unit
+// +build unit
+
+package github
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreatePullRequestComment(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		service := ghCreateCommentMock{}
+
+		err := createPullRequestComment(context.Background(), &service, "owner", "repo", 42, "summary")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 42, service.issueNumber)
+		assert.Equal(t, "summary", service.issueComment.GetBody())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		service := ghCreateCommentMock{issueCommentError: fmt.Errorf("forbidden")}
+
+		err := createPullRequestComment(context.Background(), &service, "owner", "repo", 42, "summary")
+
+		assert.EqualError(t, err, "failed to comment on pull request #42: forbidden")
+	})
+}
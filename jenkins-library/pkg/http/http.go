@@ -37,6 +37,7 @@ type Client struct {
 	username                  string
 	password                  string
 	token                     string
+	oauth2                    *OAuth2Options
 	logger                    *logrus.Entry
 	cookieJar                 http.CookieJar
 	doLogRequestBodyOnDebug   bool
@@ -71,6 +72,7 @@ type ClientOptions struct {
 	UseDefaultTransport       bool
 	TrustedCerts              []string          // defines the set of root certificate authorities that clients use when verifying server certificates
 	Certificates              []tls.Certificate // contains one or more certificate chains to present to the other side of the connection (client-authentication)
+	OAuth2                    *OAuth2Options    // enables authentication with OAuth2 client credentials, the access tokens are cached and shared between clients with the same credentials
 }
 
 // TransportWrapper is a wrapper for central round trip capabilities
@@ -81,6 +83,7 @@ type TransportWrapper struct {
 	username                 string
 	password                 string
 	token                    string
+	tokenSource              *oauth2TokenSource
 }
 
 // UploadRequestData encapsulates the parameters for calling uploader.Upload()
@@ -247,6 +250,7 @@ func (c *Client) SetOptions(options ClientOptions) {
 	c.username = options.Username
 	c.password = options.Password
 	c.token = options.Token
+	c.oauth2 = options.OAuth2
 	if options.MaxRetries < 0 {
 		c.maxRetries = 0
 	} else if options.MaxRetries == 0 {
@@ -313,6 +317,7 @@ func (c *Client) initializeHttpClient() *http.Client {
 	} else {
 		log.Entry().Debug("no trusted certs found / using default transport / insecure skip set to true / : continuing with existing tls config")
 	}
+	transport.tokenSource = c.newTokenSource(transport.Transport)
 
 	if c.maxRetries > 0 {
 		retryClient := retryablehttp.NewClient()
@@ -329,7 +334,8 @@ func (c *Client) initializeHttpClient() *http.Client {
 				doLogResponseBodyOnDebug: c.doLogResponseBodyOnDebug,
 				token:                    c.token,
 				username:                 c.username,
-				password:                 c.password}
+				password:                 c.password,
+				tokenSource:              transport.tokenSource}
 		}
 		retryClient.CheckRetry = func(ctx context.Context, resp *http.Response, err error) (bool, error) {
 			if err != nil && (strings.Contains(err.Error(), "timeout") || strings.Contains(err.Error(), "timed out") || strings.Contains(err.Error(), "connection refused") || strings.Contains(err.Error(), "connection reset")) {
@@ -346,6 +352,8 @@ func (c *Client) initializeHttpClient() *http.Client {
 		}
 		if !c.useDefaultTransport {
 			c.httpClient.Transport = transport
+		} else if transport.tokenSource != nil {
+			c.httpClient.Transport = &TransportWrapper{Transport: http.DefaultTransport, tokenSource: transport.tokenSource}
 		}
 	}
 
@@ -373,6 +381,10 @@ func (t *TransportWrapper) RoundTrip(req *http.Request) (*http.Response, error)
 
 	handleAuthentication(req, t.username, t.password, t.token)
 
+	if t.tokenSource != nil && len(req.Header.Get(authHeaderKey)) == 0 {
+		return t.roundTripWithAccessToken(req)
+	}
+
 	t.logRequest(req)
 
 	resp, err := t.Transport.RoundTrip(req)
@@ -382,6 +394,41 @@ func (t *TransportWrapper) RoundTrip(req *http.Request) (*http.Response, error)
 	return resp, err
 }
 
+// roundTripWithAccessToken authenticates the request with an OAuth2 access token.
+// A rejected token is dropped from the cache and the request is repeated once with a new token if its body can be replayed.
+func (t *TransportWrapper) roundTripWithAccessToken(req *http.Request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		token, err := t.tokenSource.token()
+		if err != nil {
+			return nil, err
+		}
+		authenticated := req.Clone(req.Context())
+		authenticated.Header.Set(authHeaderKey, token.Authorization())
+		log.Entry().Debug("Using OAuth2 Authentication ****")
+
+		t.logRequest(authenticated)
+		resp, err := t.Transport.RoundTrip(authenticated)
+		t.logResponse(resp)
+		if err != nil || resp.StatusCode != http.StatusUnauthorized {
+			return resp, err
+		}
+
+		t.tokenSource.invalidate(token)
+		if attempt > 1 || (req.Body != nil && req.GetBody == nil) {
+			return resp, nil
+		}
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return resp, nil
+			}
+			req.Body = body
+		}
+		resp.Body.Close()
+		log.Entry().Debug("Access token has been rejected, repeating the request with a new token")
+	}
+}
+
 func handleAuthentication(req *http.Request, username, password, token string) {
 	// Handle authentication if not done already
 	if (len(username) > 0 || len(password) > 0) && len(req.Header.Get(authHeaderKey)) == 0 {
@@ -687,3 +734,20 @@ func ParseHTTPResponseBodyJSON(resp *http.Response, response interface{}) error
 
 	return nil
 }
+
+// newTokenSource creates the source of the OAuth2 access tokens, which uses its own transport,
+// since mTLS clients present other certificates to the token endpoint than to the service
+func (c *Client) newTokenSource(transport http.RoundTripper) *oauth2TokenSource {
+	if c.oauth2 == nil {
+		return nil
+	}
+	base, ok := transport.(*http.Transport)
+	if !ok || c.useDefaultTransport {
+		base = http.DefaultTransport.(*http.Transport)
+	}
+	tokenTransport := base.Clone()
+	if tokenTransport.TLSClientConfig == nil {
+		tokenTransport.TLSClientConfig = &tls.Config{}
+	}
+	return newOAuth2TokenSource(*c.oauth2, tokenTransport)
+}
@@ -0,0 +1,286 @@
+package http
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/pkg/errors"
+)
+
+// tokenRefreshMargin is the remaining validity below which a cached access token is refreshed
+const tokenRefreshMargin = 2 * time.Minute
+
+// OAuth2Options configures the authentication with access tokens of the OAuth2 client credentials grant.
+// The token is fetched with the first request and refreshed shortly before it expires.
+type OAuth2Options struct {
+	// TokenURL is the token endpoint, e.g. https://<subdomain>.authentication.<region>.hana.ondemand.com/oauth/token
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+	// ClientCertificates are presented to the token endpoint by certificate based (mTLS) clients, which have no ClientSecret
+	ClientCertificates []tls.Certificate
+}
+
+// OAuth2Token is an access token of the OAuth2 client credentials grant
+type OAuth2Token struct {
+	AccessToken string    `json:"accessToken"`
+	TokenType   string    `json:"tokenType"`
+	ExpiresAt   time.Time `json:"expiresAt,omitempty"`
+}
+
+func (t OAuth2Token) valid(now time.Time) bool {
+	return len(t.AccessToken) > 0 && (t.ExpiresAt.IsZero() || now.Add(tokenRefreshMargin).Before(t.ExpiresAt))
+}
+
+// Authorization returns the value of the Authorization header for the token
+func (t OAuth2Token) Authorization() string {
+	if len(t.TokenType) == 0 || strings.EqualFold(t.TokenType, "bearer") {
+		return "Bearer " + t.AccessToken
+	}
+	return t.TokenType + " " + t.AccessToken
+}
+
+// tokenCache holds the access tokens of all clients of the process in memory. If a directory is set, it also
+// shares the tokens with the following steps of the pipeline run.
+var tokenCache = struct {
+	sync.Mutex
+	tokens    map[string]OAuth2Token
+	directory string
+}{tokens: map[string]OAuth2Token{}}
+
+// ResetTokenCache drops the access tokens cached in memory and stops sharing them via a directory, e.g. to isolate tests
+func ResetTokenCache() {
+	tokenCache.Lock()
+	defer tokenCache.Unlock()
+	tokenCache.tokens = map[string]OAuth2Token{}
+	tokenCache.directory = ""
+}
+
+// SetTokenCacheDirectory enables sharing access tokens between the steps of a pipeline run by storing them in the given directory.
+// The directory is only accessible by the owner. Tokens without expiry are not stored.
+func SetTokenCacheDirectory(directory string) {
+	tokenCache.Lock()
+	defer tokenCache.Unlock()
+	tokenCache.directory = directory
+}
+
+func cachedToken(key string, now time.Time) (OAuth2Token, bool) {
+	tokenCache.Lock()
+	defer tokenCache.Unlock()
+	if token, ok := tokenCache.tokens[key]; ok && token.valid(now) {
+		return token, true
+	}
+	if len(tokenCache.directory) == 0 {
+		return OAuth2Token{}, false
+	}
+	content, err := os.ReadFile(filepath.Join(tokenCache.directory, key+".json"))
+	if err != nil {
+		return OAuth2Token{}, false
+	}
+	var token OAuth2Token
+	if err := json.Unmarshal(content, &token); err != nil || !token.valid(now) {
+		return OAuth2Token{}, false
+	}
+	log.RegisterSecret(token.AccessToken)
+	tokenCache.tokens[key] = token
+	return token, true
+}
+
+func storeToken(key string, token OAuth2Token) {
+	tokenCache.Lock()
+	defer tokenCache.Unlock()
+	tokenCache.tokens[key] = token
+	if len(tokenCache.directory) == 0 || token.ExpiresAt.IsZero() {
+		return
+	}
+	if err := writeToken(filepath.Join(tokenCache.directory, key+".json"), token); err != nil {
+		log.Entry().WithError(err).Debug("failed to share access token with the following steps")
+	}
+}
+
+// writeToken replaces the token file with a file which is only readable by the owner
+func writeToken(path string, token OAuth2Token) error {
+	content, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	if err := os.Chmod(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	// a temporary file is created with mode 0600 and replaces an existing file or link instead of writing through it
+	file, err := os.CreateTemp(filepath.Dir(path), ".token-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	_, err = file.Write(content)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
+
+func removeToken(key, accessToken string) {
+	tokenCache.Lock()
+	defer tokenCache.Unlock()
+	if token, ok := tokenCache.tokens[key]; ok && token.AccessToken == accessToken {
+		delete(tokenCache.tokens, key)
+		if len(tokenCache.directory) > 0 {
+			_ = os.Remove(filepath.Join(tokenCache.directory, key+".json"))
+		}
+	}
+}
+
+// oauth2TokenSource fetches and caches the access tokens of a client
+type oauth2TokenSource struct {
+	options OAuth2Options
+	send    func(method, url string, body io.Reader, header http.Header) (*http.Response, error)
+	key     string
+	mutex   sync.Mutex
+}
+
+func newOAuth2TokenSource(options OAuth2Options, transport *http.Transport) *oauth2TokenSource {
+	transport.TLSClientConfig.Certificates = options.ClientCertificates
+	client := &http.Client{Transport: transport}
+	return &oauth2TokenSource{
+		options: options,
+		send: func(method, url string, body io.Reader, header http.Header) (*http.Response, error) {
+			request, err := http.NewRequest(method, url, body)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid token URL %v", url)
+			}
+			request.Header = header
+			return client.Do(request)
+		},
+		key: tokenKey(options, clientCredentialsGrant),
+	}
+}
+
+const clientCredentialsGrant = "client_credentials"
+
+func tokenKey(options OAuth2Options, grantType string) string {
+	scopes := append([]string{}, options.Scopes...)
+	sort.Strings(scopes)
+	// the secret is part of the key, so that a rotated secret does not reuse tokens of the previous one
+	hash := sha256.Sum256([]byte(strings.Join([]string{options.TokenURL, options.ClientID, options.ClientSecret, strings.Join(scopes, " "), grantType}, "\n")))
+	return hex.EncodeToString(hash[:])
+}
+
+// FetchOAuth2Token returns an access token for clients which do not send their requests with the OAuth2 option of the http client.
+// The token is taken from the shared token cache or requested from the token endpoint with the given sender,
+// which has to present the ClientCertificates of the options itself.
+func FetchOAuth2Token(options OAuth2Options, sender Sender) (OAuth2Token, error) {
+	source := &oauth2TokenSource{
+		options: options,
+		send: func(method, url string, body io.Reader, header http.Header) (*http.Response, error) {
+			return sender.SendRequest(method, url, body, header, nil)
+		},
+		key: tokenKey(options, clientCredentialsGrant),
+	}
+	return source.token()
+}
+
+// CachedOAuth2Token returns an access token of the given grant from the shared token cache. If no valid token is cached,
+// it is requested with fetch and cached afterwards. This allows clients which request their tokens themselves, e.g. with
+// another grant than client credentials, to share them like the clients using the OAuth2 option of the http client.
+func CachedOAuth2Token(options OAuth2Options, grantType string, fetch func() (OAuth2Token, error)) (OAuth2Token, error) {
+	key := tokenKey(options, grantType)
+	if token, ok := cachedToken(key, time.Now()); ok {
+		return token, nil
+	}
+	token, err := fetch()
+	if err != nil {
+		return OAuth2Token{}, err
+	}
+	storeToken(key, token)
+	return token, nil
+}
+
+// token returns a valid access token, either from the cache or newly fetched from the token endpoint
+func (s *oauth2TokenSource) token() (OAuth2Token, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if token, ok := cachedToken(s.key, time.Now()); ok {
+		return token, nil
+	}
+	token, err := s.fetch()
+	if err != nil {
+		return OAuth2Token{}, err
+	}
+	storeToken(s.key, token)
+	return token, nil
+}
+
+// invalidate removes a token which has been rejected from the cache
+func (s *oauth2TokenSource) invalidate(token OAuth2Token) {
+	removeToken(s.key, token.AccessToken)
+}
+
+func (s *oauth2TokenSource) fetch() (OAuth2Token, error) {
+	form := url.Values{"grant_type": {clientCredentialsGrant}, "client_id": {s.options.ClientID}}
+	if len(s.options.Scopes) > 0 {
+		form.Set("scope", strings.Join(s.options.Scopes, " "))
+	}
+	header := http.Header{}
+	header.Set("Content-Type", "application/x-www-form-urlencoded")
+	header.Set("Accept", "application/json")
+	if len(s.options.ClientSecret) > 0 {
+		credentials := base64.StdEncoding.EncodeToString([]byte(s.options.ClientID + ":" + s.options.ClientSecret))
+		log.RegisterSecret(s.options.ClientSecret)
+		log.RegisterSecret(credentials)
+		header.Set(authHeaderKey, "Basic "+credentials)
+	}
+
+	requestTime := time.Now()
+	response, err := s.send(http.MethodPost, s.options.TokenURL, strings.NewReader(form.Encode()), header)
+	// senders of the http package also return an error for unsuccessful status codes, which is reported below
+	if response == nil {
+		if err == nil {
+			err = errors.New("no response")
+		}
+		return OAuth2Token{}, errors.Wrapf(err, "fetching an access token from %v failed", s.options.TokenURL)
+	}
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return OAuth2Token{}, errors.Wrap(err, "failed to read the response of the token endpoint")
+	}
+	if response.StatusCode != http.StatusOK {
+		return OAuth2Token{}, errors.Errorf("fetching an access token from %v failed with HTTP status %v: %v", s.options.TokenURL, response.StatusCode, string(body))
+	}
+
+	var tokenResponse struct {
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &tokenResponse); err != nil || len(tokenResponse.AccessToken) == 0 {
+		return OAuth2Token{}, errors.Errorf("the response of the token endpoint %v does not contain an access token", s.options.TokenURL)
+	}
+	log.RegisterSecret(tokenResponse.AccessToken)
+	token := OAuth2Token{AccessToken: tokenResponse.AccessToken, TokenType: tokenResponse.TokenType}
+	if tokenResponse.ExpiresIn > 0 {
+		token.ExpiresAt = requestTime.Add(time.Duration(tokenResponse.ExpiresIn) * time.Second)
+	}
+	return token, nil
+}
@@ -0,0 +1,316 @@
+//go:build unit
+// +build unit
+
+package http
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type tokenServer struct {
+	*httptest.Server
+	mutex     sync.Mutex
+	requests  int
+	expiresIn int
+	forms     []map[string]string
+	basicAuth []string
+}
+
+func (s *tokenServer) handle(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.requests++
+	r.ParseForm()
+	s.forms = append(s.forms, map[string]string{"grant_type": r.PostForm.Get("grant_type"), "client_id": r.PostForm.Get("client_id"), "scope": r.PostForm.Get("scope")})
+	user, password, _ := r.BasicAuth()
+	s.basicAuth = append(s.basicAuth, user+":"+password)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"access_token": fmt.Sprintf("token%v", s.requests), "token_type": "bearer", "expires_in": s.expiresIn})
+}
+
+func (s *tokenServer) tokenRequests() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.requests
+}
+
+func newTokenServer(t *testing.T, expiresIn int) *tokenServer {
+	server := &tokenServer{expiresIn: expiresIn}
+	server.Server = httptest.NewServer(http.HandlerFunc(server.handle))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newServiceServer returns a server which records the Authorization headers and rejects the given tokens
+func newServiceServer(t *testing.T, rejected ...string) (*httptest.Server, *[]string) {
+	var mutex sync.Mutex
+	headers := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		header := r.Header.Get("Authorization")
+		headers = append(headers, header)
+		for _, token := range rejected {
+			if header == "Bearer "+token {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+		}
+		w.Write([]byte("OK"))
+	}))
+	t.Cleanup(server.Close)
+	return server, &headers
+}
+
+func resetTokenCache(t *testing.T) {
+	t.Cleanup(ResetTokenCache)
+}
+
+func sendWithOAuth2(t *testing.T, options OAuth2Options, requestURL string) {
+	client := Client{}
+	client.SetOptions(ClientOptions{MaxRetries: -1, OAuth2: &options})
+	response, err := client.SendRequest(http.MethodGet, requestURL, nil, nil, nil)
+	require.NoError(t, err)
+	response.Body.Close()
+}
+
+func TestOAuth2(t *testing.T) {
+	t.Run("token is shared between clients", func(t *testing.T) {
+		resetTokenCache(t)
+		tokens := newTokenServer(t, 3600)
+		service, headers := newServiceServer(t)
+		options := OAuth2Options{TokenURL: tokens.URL + "/oauth/token", ClientID: "client", ClientSecret: "secret", Scopes: []string{"read", "write"}}
+
+		sendWithOAuth2(t, options, service.URL)
+		sendWithOAuth2(t, options, service.URL)
+
+		assert.Equal(t, 1, tokens.tokenRequests())
+		assert.Equal(t, []map[string]string{{"grant_type": "client_credentials", "client_id": "client", "scope": "read write"}}, tokens.forms)
+		assert.Equal(t, []string{"client:secret"}, tokens.basicAuth)
+		assert.Equal(t, []string{"Bearer token1", "Bearer token1"}, *headers)
+	})
+
+	t.Run("other credentials use other tokens", func(t *testing.T) {
+		resetTokenCache(t)
+		tokens := newTokenServer(t, 3600)
+		service, headers := newServiceServer(t)
+
+		sendWithOAuth2(t, OAuth2Options{TokenURL: tokens.URL, ClientID: "client", ClientSecret: "secret"}, service.URL)
+		sendWithOAuth2(t, OAuth2Options{TokenURL: tokens.URL, ClientID: "client", ClientSecret: "rotated"}, service.URL)
+
+		assert.Equal(t, []string{"Bearer token1", "Bearer token2"}, *headers)
+	})
+
+	t.Run("token is refreshed before it expires", func(t *testing.T) {
+		resetTokenCache(t)
+		// the token expires within the refresh margin
+		tokens := newTokenServer(t, 60)
+		service, headers := newServiceServer(t)
+		options := OAuth2Options{TokenURL: tokens.URL, ClientID: "client", ClientSecret: "secret"}
+
+		sendWithOAuth2(t, options, service.URL)
+		sendWithOAuth2(t, options, service.URL)
+
+		assert.Equal(t, []string{"Bearer token1", "Bearer token2"}, *headers)
+	})
+
+	t.Run("rejected token is replaced", func(t *testing.T) {
+		resetTokenCache(t)
+		tokens := newTokenServer(t, 3600)
+		service, headers := newServiceServer(t, "token1")
+
+		sendWithOAuth2(t, OAuth2Options{TokenURL: tokens.URL, ClientID: "client", ClientSecret: "secret"}, service.URL)
+
+		assert.Equal(t, 2, tokens.tokenRequests())
+		assert.Equal(t, []string{"Bearer token1", "Bearer token2"}, *headers)
+	})
+
+	t.Run("explicit authorization header is kept", func(t *testing.T) {
+		resetTokenCache(t)
+		tokens := newTokenServer(t, 3600)
+		service, headers := newServiceServer(t)
+		client := Client{}
+		client.SetOptions(ClientOptions{MaxRetries: -1, OAuth2: &OAuth2Options{TokenURL: tokens.URL, ClientID: "client", ClientSecret: "secret"}})
+
+		response, err := client.SendRequest(http.MethodGet, service.URL, nil, http.Header{"Authorization": {"Basic abc"}}, nil)
+
+		require.NoError(t, err)
+		response.Body.Close()
+		assert.Equal(t, 0, tokens.tokenRequests())
+		assert.Equal(t, []string{"Basic abc"}, *headers)
+	})
+
+	t.Run("token endpoint fails", func(t *testing.T) {
+		resetTokenCache(t)
+		tokens := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error": "invalid_client"}`))
+		}))
+		defer tokens.Close()
+		client := Client{}
+		client.SetOptions(ClientOptions{MaxRetries: -1, OAuth2: &OAuth2Options{TokenURL: tokens.URL, ClientID: "client", ClientSecret: "secret"}})
+
+		_, err := client.SendRequest(http.MethodGet, "http://localhost/service", nil, nil, nil)
+
+		assert.ErrorContains(t, err, fmt.Sprintf(`fetching an access token from %v failed with HTTP status 401: {"error": "invalid_client"}`, tokens.URL))
+	})
+}
+
+func TestOAuth2TokenCacheDirectory(t *testing.T) {
+	resetTokenCache(t)
+	directory := filepath.Join(t.TempDir(), "oauthTokens")
+	require.NoError(t, os.MkdirAll(directory, 0o755))
+	SetTokenCacheDirectory(directory)
+	tokens := newTokenServer(t, 3600)
+	service, headers := newServiceServer(t, "token2")
+	options := OAuth2Options{TokenURL: tokens.URL, ClientID: "client", ClientSecret: "secret"}
+
+	sendWithOAuth2(t, options, service.URL)
+
+	files, err := os.ReadDir(directory)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	info, err := files[0].Info()
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	info, err = os.Stat(directory)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o700), info.Mode().Perm())
+
+	t.Run("following step reuses the token", func(t *testing.T) {
+		tokenCache.tokens = map[string]OAuth2Token{}
+
+		sendWithOAuth2(t, options, service.URL)
+
+		assert.Equal(t, 1, tokens.tokenRequests())
+		assert.Equal(t, []string{"Bearer token1", "Bearer token1"}, *headers)
+	})
+
+	t.Run("expired token is not reused", func(t *testing.T) {
+		tokenCache.tokens = map[string]OAuth2Token{}
+		content, _ := json.Marshal(OAuth2Token{AccessToken: "token1", TokenType: "bearer", ExpiresAt: time.Now().Add(time.Minute)})
+		require.NoError(t, os.WriteFile(filepath.Join(directory, files[0].Name()), content, 0o644))
+
+		// token2 is rejected by the service, the token of the third request is cached afterwards
+		sendWithOAuth2(t, options, service.URL)
+
+		assert.Equal(t, 3, tokens.tokenRequests())
+		content, err := os.ReadFile(filepath.Join(directory, files[0].Name()))
+		require.NoError(t, err)
+		assert.Contains(t, string(content), `"accessToken":"token3"`)
+		info, err := os.Stat(filepath.Join(directory, files[0].Name()))
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	})
+}
+
+func TestFetchOAuth2Token(t *testing.T) {
+	resetTokenCache(t)
+	tokens := newTokenServer(t, 3600)
+	options := OAuth2Options{TokenURL: tokens.URL, ClientID: "client", ClientSecret: "secret"}
+
+	token, err := FetchOAuth2Token(options, &Client{})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer token1", token.Authorization())
+	assert.Equal(t, []string{"client:secret"}, tokens.basicAuth)
+	assert.Equal(t, "client_credentials", tokens.forms[0]["grant_type"])
+
+	t.Run("token is shared with clients", func(t *testing.T) {
+		service, headers := newServiceServer(t)
+
+		sendWithOAuth2(t, options, service.URL)
+
+		assert.Equal(t, 1, tokens.tokenRequests())
+		assert.Equal(t, []string{"Bearer token1"}, *headers)
+	})
+
+	t.Run("token endpoint fails", func(t *testing.T) {
+		failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error": "invalid_client"}`))
+		}))
+		defer failing.Close()
+		client := &Client{}
+		client.SetOptions(ClientOptions{MaxRetries: -1})
+
+		_, err := FetchOAuth2Token(OAuth2Options{TokenURL: failing.URL, ClientID: "client", ClientSecret: "secret"}, client)
+
+		assert.ErrorContains(t, err, fmt.Sprintf(`fetching an access token from %v failed with HTTP status 401: {"error": "invalid_client"}`, failing.URL))
+	})
+}
+
+func TestCachedOAuth2Token(t *testing.T) {
+	resetTokenCache(t)
+	options := OAuth2Options{TokenURL: "https://uaa.example.com/oauth/token", ClientID: "client", ClientSecret: "secret"}
+	fetched := 0
+	fetch := func() (OAuth2Token, error) {
+		fetched++
+		return OAuth2Token{AccessToken: fmt.Sprintf("token%v", fetched), TokenType: "bearer", ExpiresAt: time.Now().Add(time.Hour)}, nil
+	}
+
+	first, err := CachedOAuth2Token(options, "password", fetch)
+	require.NoError(t, err)
+	second, err := CachedOAuth2Token(options, "password", fetch)
+	require.NoError(t, err)
+	other, err := CachedOAuth2Token(options, "client_credentials", fetch)
+	require.NoError(t, err)
+
+	assert.Equal(t, "token1", first.AccessToken)
+	assert.Equal(t, "token1", second.AccessToken)
+	// tokens of other grants are cached separately
+	assert.Equal(t, "token2", other.AccessToken)
+
+	t.Run("failing fetch is not cached", func(t *testing.T) {
+		ResetTokenCache()
+
+		_, err := CachedOAuth2Token(options, "password", func() (OAuth2Token, error) { return OAuth2Token{}, fmt.Errorf("unauthorized") })
+		assert.EqualError(t, err, "unauthorized")
+		token, err := CachedOAuth2Token(options, "password", fetch)
+
+		require.NoError(t, err)
+		assert.Equal(t, "token3", token.AccessToken)
+	})
+}
+
+func TestOAuth2CertificateClient(t *testing.T) {
+	resetTokenCache(t)
+	clientPemKey, clientPemCert := GenerateSelfSignedClientAuthCertificate()
+	clientCertPool := x509.NewCertPool()
+	clientCertPool.AppendCertsFromPEM(clientPemCert)
+	tokens := &tokenServer{expiresIn: 3600}
+	tokens.Server = httptest.NewUnstartedServer(http.HandlerFunc(tokens.handle))
+	tokens.TLS = &tls.Config{ClientCAs: clientCertPool, ClientAuth: tls.RequireAndVerifyClientCert}
+	tokens.StartTLS()
+	defer tokens.Close()
+	service, headers := newServiceServer(t)
+	keyPair, err := tls.X509KeyPair(clientPemCert, clientPemKey)
+	require.NoError(t, err)
+
+	client := Client{}
+	client.SetOptions(ClientOptions{
+		MaxRetries:                -1,
+		TransportSkipVerification: true,
+		OAuth2:                    &OAuth2Options{TokenURL: tokens.URL, ClientID: "client", ClientCertificates: []tls.Certificate{keyPair}},
+	})
+	response, err := client.SendRequest(http.MethodGet, service.URL, nil, nil, nil)
+
+	require.NoError(t, err)
+	response.Body.Close()
+	assert.Equal(t, []string{":"}, tokens.basicAuth)
+	assert.Equal(t, "client", tokens.forms[0]["client_id"])
+	assert.Equal(t, []string{"Bearer token1"}, *headers)
+}
@@ -0,0 +1,458 @@
+package kubernetes
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer/json"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// Severity levels of manifest findings, aligned with SARIF result levels
+const (
+	LevelError   = "error"
+	LevelWarning = "warning"
+	LevelNote    = "note"
+)
+
+// Rule IDs of the manifest checks
+const (
+	RuleInvalidYAML     = "K8S001"
+	RuleSchema          = "K8S002"
+	RuleRemovedAPI      = "K8S003"
+	RuleUnknownKind     = "K8S004"
+	RuleResourceLimits  = "K8S101"
+	RuleRunAsNonRoot    = "K8S102"
+	RuleImageTag        = "K8S103"
+	RuleProbes          = "K8S104"
+	manifestCheckToolID = "piper-kubernetes-manifest-check"
+)
+
+var manifestRules = map[string]string{
+	RuleInvalidYAML:    "Manifest is no valid YAML",
+	RuleSchema:         "Resource does not match the Kubernetes API schema",
+	RuleRemovedAPI:     "Resource uses an API version which is not served by the target cluster version",
+	RuleUnknownKind:    "Resource kind is unknown to the bundled Kubernetes schema (e.g. custom resource)",
+	RuleResourceLimits: "Containers should define CPU and memory limits",
+	RuleRunAsNonRoot:   "Containers should not run as root",
+	RuleImageTag:       "Container images should use a fixed tag or digest instead of 'latest'",
+	RuleProbes:         "Long running containers should define liveness and readiness probes",
+}
+
+// removedAPIs lists API versions removed from Kubernetes together with the minor version in which they were removed.
+// An empty kind list applies to all kinds of the API version.
+var removedAPIs = []struct {
+	apiVersion string
+	kinds      []string
+	removedIn  int
+}{
+	{"extensions/v1beta1", []string{"Deployment", "DaemonSet", "ReplicaSet", "NetworkPolicy", "PodSecurityPolicy"}, 16},
+	{"apps/v1beta1", nil, 16},
+	{"apps/v1beta2", nil, 16},
+	{"extensions/v1beta1", []string{"Ingress"}, 22},
+	{"networking.k8s.io/v1beta1", nil, 22},
+	{"rbac.authorization.k8s.io/v1beta1", nil, 22},
+	{"apiextensions.k8s.io/v1beta1", nil, 22},
+	{"admissionregistration.k8s.io/v1beta1", nil, 22},
+	{"apiregistration.k8s.io/v1beta1", nil, 22},
+	{"scheduling.k8s.io/v1beta1", nil, 22},
+	{"certificates.k8s.io/v1beta1", nil, 22},
+	{"coordination.k8s.io/v1beta1", nil, 22},
+	{"storage.k8s.io/v1beta1", []string{"CSIDriver", "CSINode", "StorageClass", "VolumeAttachment"}, 22},
+	{"batch/v1beta1", nil, 25},
+	{"policy/v1beta1", nil, 25},
+	{"discovery.k8s.io/v1beta1", nil, 25},
+	{"events.k8s.io/v1beta1", nil, 25},
+	{"autoscaling/v2beta1", nil, 25},
+	{"node.k8s.io/v1beta1", nil, 25},
+	{"autoscaling/v2beta2", nil, 26},
+	{"flowcontrol.apiserver.k8s.io/v1beta1", nil, 26},
+	{"storage.k8s.io/v1beta1", []string{"CSIStorageCapacity"}, 27},
+	{"flowcontrol.apiserver.k8s.io/v1beta2", nil, 29},
+	{"flowcontrol.apiserver.k8s.io/v1beta3", nil, 32},
+}
+
+// schemes are the API schemas bundled with piper by Kubernetes minor version. Manifests can only be checked for
+// Kubernetes versions with a bundled schema.
+var schemes = map[int]*runtime.Scheme{
+	32: scheme.Scheme,
+}
+
+// Manifest is a single Kubernetes resource of a (multi-document) manifest file
+type Manifest struct {
+	Source  string
+	Line    int
+	Content []byte
+	Object  map[string]interface{}
+}
+
+// ManifestFinding describes a schema or policy violation of a manifest
+type ManifestFinding struct {
+	RuleID   string
+	Level    string
+	Message  string
+	Source   string
+	Line     int
+	Resource string
+}
+
+// ManifestCheckOptions configures the manifest checks
+type ManifestCheckOptions struct {
+	// KubernetesVersion is the target cluster version, e.g. 1.30
+	KubernetesVersion string
+	// DisabledRules contains rule IDs which are not checked
+	DisabledRules []string
+}
+
+// SplitManifests splits a multi-document YAML stream into single manifests. Empty documents are skipped.
+func SplitManifests(source string, content []byte) ([]Manifest, []ManifestFinding) {
+	manifests := []Manifest{}
+	findings := []ManifestFinding{}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	for {
+		var node yaml.Node
+		err := decoder.Decode(&node)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			findings = append(findings, ManifestFinding{RuleID: RuleInvalidYAML, Level: LevelError, Message: err.Error(), Source: source})
+			break
+		}
+		if len(node.Content) == 0 || node.Content[0].Kind != yaml.MappingNode {
+			continue
+		}
+		object := map[string]interface{}{}
+		if err := node.Decode(&object); err != nil {
+			findings = append(findings, ManifestFinding{RuleID: RuleInvalidYAML, Level: LevelError, Message: err.Error(), Source: source, Line: node.Line})
+			continue
+		}
+		if len(object) == 0 {
+			continue
+		}
+		doc, err := yaml.Marshal(&node)
+		if err != nil {
+			findings = append(findings, ManifestFinding{RuleID: RuleInvalidYAML, Level: LevelError, Message: err.Error(), Source: source, Line: node.Line})
+			continue
+		}
+		manifests = append(manifests, Manifest{Source: source, Line: node.Content[0].Line, Content: doc, Object: object})
+	}
+	return manifests, findings
+}
+
+// CheckManifests validates the manifests against the bundled Kubernetes API schema and the best practice policies
+func CheckManifests(manifests []Manifest, options ManifestCheckOptions) ([]ManifestFinding, error) {
+	minor, err := parseMinorVersion(options.KubernetesVersion)
+	if err != nil {
+		return nil, err
+	}
+	apiScheme, ok := schemes[minor]
+	if !ok {
+		return nil, fmt.Errorf("unsupported Kubernetes version '%s', supported versions are %s", options.KubernetesVersion, strings.Join(SupportedKubernetesVersions(), ", "))
+	}
+	disabled := map[string]bool{}
+	for _, rule := range options.DisabledRules {
+		disabled[rule] = true
+	}
+
+	findings := []ManifestFinding{}
+	for _, m := range manifests {
+		for _, f := range append(checkSchema(m, minor, apiScheme), checkPolicies(m)...) {
+			if !disabled[f.RuleID] {
+				findings = append(findings, f)
+			}
+		}
+	}
+	return findings, nil
+}
+
+// SupportedKubernetesVersions returns the Kubernetes versions for which an API schema is bundled
+func SupportedKubernetesVersions() []string {
+	minors := []int{}
+	for minor := range schemes {
+		minors = append(minors, minor)
+	}
+	sort.Ints(minors)
+	versions := []string{}
+	for _, minor := range minors {
+		versions = append(versions, fmt.Sprintf("1.%d", minor))
+	}
+	return versions
+}
+
+func parseMinorVersion(version string) (int, error) {
+	parts := strings.Split(strings.TrimPrefix(version, "v"), ".")
+	if len(parts) < 2 || parts[0] != "1" {
+		return 0, fmt.Errorf("invalid Kubernetes version '%s', expected format '1.<minor>'", version)
+	}
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, fmt.Errorf("invalid Kubernetes version '%s', expected format '1.<minor>'", version)
+	}
+	return minor, nil
+}
+
+func (m Manifest) finding(rule, level, message string) ManifestFinding {
+	return ManifestFinding{RuleID: rule, Level: level, Message: message, Source: m.Source, Line: m.Line, Resource: m.resourceName()}
+}
+
+func (m Manifest) resourceName() string {
+	name := ""
+	if metadata, ok := m.Object["metadata"].(map[string]interface{}); ok {
+		name = fmt.Sprint(metadata["name"])
+	}
+	return fmt.Sprintf("%v/%s", m.Object["kind"], name)
+}
+
+// checkSchema strictly decodes the manifest into the API types of the schema bundled for the target version and
+// detects API versions which were removed in the target version.
+func checkSchema(m Manifest, minor int, apiScheme *runtime.Scheme) []ManifestFinding {
+	apiVersion, _ := m.Object["apiVersion"].(string)
+	kind, _ := m.Object["kind"].(string)
+	if len(apiVersion) == 0 || len(kind) == 0 {
+		return []ManifestFinding{m.finding(RuleSchema, LevelError, "apiVersion and kind must be set")}
+	}
+	if metadata, ok := m.Object["metadata"].(map[string]interface{}); !ok || (metadata["name"] == nil && metadata["generateName"] == nil) {
+		return []ManifestFinding{m.finding(RuleSchema, LevelError, "metadata.name must be set")}
+	}
+
+	for _, removed := range removedAPIs {
+		if removed.apiVersion != apiVersion || (len(removed.kinds) > 0 && !contains(removed.kinds, kind)) {
+			continue
+		}
+		if minor >= removed.removedIn {
+			return []ManifestFinding{m.finding(RuleRemovedAPI, LevelError,
+				fmt.Sprintf("%s %s was removed in Kubernetes 1.%d", apiVersion, kind, removed.removedIn))}
+		}
+	}
+
+	serializer := json.NewSerializerWithOptions(json.DefaultMetaFactory, apiScheme, apiScheme, json.SerializerOptions{Yaml: true, Strict: true})
+	_, _, err := serializer.Decode(m.Content, nil, nil)
+	if err == nil {
+		return nil
+	}
+	if runtime.IsNotRegisteredError(err) {
+		return []ManifestFinding{m.finding(RuleUnknownKind, LevelNote, fmt.Sprintf("%s %s is not part of the bundled Kubernetes schema and was not validated", apiVersion, kind))}
+	}
+	if strictErr, ok := runtime.AsStrictDecodingError(err); ok {
+		findings := []ManifestFinding{}
+		for _, e := range strictErr.Errors() {
+			findings = append(findings, m.finding(RuleSchema, LevelError, e.Error()))
+		}
+		return findings
+	}
+	return []ManifestFinding{m.finding(RuleSchema, LevelError, err.Error())}
+}
+
+type podSpec struct {
+	spec        map[string]interface{}
+	longRunning bool
+}
+
+func (m Manifest) podSpec() *podSpec {
+	kind, _ := m.Object["kind"].(string)
+	switch kind {
+	case "Pod":
+		return &podSpec{spec: getMap(m.Object, "spec"), longRunning: true}
+	case "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet", "ReplicationController":
+		return &podSpec{spec: getMap(m.Object, "spec", "template", "spec"), longRunning: true}
+	case "Job":
+		return &podSpec{spec: getMap(m.Object, "spec", "template", "spec")}
+	case "CronJob":
+		return &podSpec{spec: getMap(m.Object, "spec", "jobTemplate", "spec", "template", "spec")}
+	}
+	return nil
+}
+
+func checkPolicies(m Manifest) []ManifestFinding {
+	pod := m.podSpec()
+	if pod == nil || pod.spec == nil {
+		return nil
+	}
+	findings := []ManifestFinding{}
+	podNonRoot := getMap(pod.spec, "securityContext")["runAsNonRoot"] == true
+
+	containers := getList(pod.spec, "containers")
+	for _, c := range append(getList(pod.spec, "initContainers"), containers...) {
+		name := fmt.Sprint(c["name"])
+
+		limits := getMap(c, "resources", "limits")
+		if limits["cpu"] == nil || limits["memory"] == nil {
+			findings = append(findings, m.finding(RuleResourceLimits, LevelWarning, fmt.Sprintf("container '%s' does not define CPU and memory limits", name)))
+		}
+
+		securityContext := getMap(c, "securityContext")
+		nonRoot := podNonRoot
+		if v, ok := securityContext["runAsNonRoot"].(bool); ok {
+			nonRoot = v
+		}
+		if user, ok := securityContext["runAsUser"]; ok && fmt.Sprint(user) == "0" {
+			nonRoot = false
+		}
+		if !nonRoot {
+			findings = append(findings, m.finding(RuleRunAsNonRoot, LevelWarning, fmt.Sprintf("container '%s' may run as root, set securityContext.runAsNonRoot to true", name)))
+		}
+
+		image, _ := c["image"].(string)
+		if !hasFixedImageTag(image) {
+			findings = append(findings, m.finding(RuleImageTag, LevelWarning, fmt.Sprintf("container '%s' uses image '%s' without fixed tag", name, image)))
+		}
+	}
+
+	if pod.longRunning {
+		for _, c := range containers {
+			if c["livenessProbe"] == nil || c["readinessProbe"] == nil {
+				findings = append(findings, m.finding(RuleProbes, LevelWarning, fmt.Sprintf("container '%s' does not define liveness and readiness probes", c["name"])))
+			}
+		}
+	}
+	return findings
+}
+
+func hasFixedImageTag(image string) bool {
+	if strings.Contains(image, "@sha256:") {
+		return true
+	}
+	// the tag follows the last colon unless the colon belongs to a registry port
+	lastSlash := strings.LastIndex(image, "/")
+	lastColon := strings.LastIndex(image, ":")
+	if lastColon <= lastSlash {
+		return false
+	}
+	return image[lastColon+1:] != "latest"
+}
+
+func getMap(object map[string]interface{}, path ...string) map[string]interface{} {
+	current := object
+	for _, key := range path {
+		next, ok := current[key].(map[string]interface{})
+		if !ok {
+			return map[string]interface{}{}
+		}
+		current = next
+	}
+	return current
+}
+
+func getList(object map[string]interface{}, key string) []map[string]interface{} {
+	result := []map[string]interface{}{}
+	items, _ := object[key].([]interface{})
+	for _, item := range items {
+		if m, ok := item.(map[string]interface{}); ok {
+			result = append(result, m)
+		}
+	}
+	return result
+}
+
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateManifestSarif converts manifest findings into SARIF format
+func CreateManifestSarif(findings []ManifestFinding) format.SARIF {
+	ruleIDs := []string{}
+	for id := range manifestRules {
+		ruleIDs = append(ruleIDs, id)
+	}
+	sort.Strings(ruleIDs)
+
+	rules := []format.SarifRule{}
+	ruleIndex := map[string]int{}
+	for i, id := range ruleIDs {
+		ruleIndex[id] = i
+		rules = append(rules, format.SarifRule{
+			ID:               id,
+			Name:             id,
+			ShortDescription: &format.Message{Text: manifestRules[id]},
+			FullDescription:  &format.Message{Text: manifestRules[id]},
+		})
+	}
+
+	results := []format.Results{}
+	for _, f := range findings {
+		results = append(results, format.Results{
+			RuleID:    f.RuleID,
+			RuleIndex: ruleIndex[f.RuleID],
+			Level:     f.Level,
+			Message:   &format.Message{Text: fmt.Sprintf("%s: %s", f.Resource, f.Message)},
+			Locations: []format.Location{{
+				PhysicalLocation: format.PhysicalLocation{
+					ArtifactLocation: format.ArtifactLocation{URI: f.Source},
+					Region:           format.Region{StartLine: f.Line},
+				},
+			}},
+		})
+	}
+
+	return format.SARIF{
+		Schema:  "https://docs.oasis-open.org/sarif/sarif/v2.1.0/cos02/schemas/sarif-schema-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []format.Runs{{
+			Results: results,
+			Tool: format.Tool{Driver: format.Driver{
+				Name:           manifestCheckToolID,
+				InformationUri: "https://www.project-piper.io",
+				Rules:          rules,
+			}},
+		}},
+	}
+}
+
+// CreateManifestScanReport creates a scan report of the manifest findings
+func CreateManifestScanReport(findings []ManifestFinding, manifestCount int, kubernetesVersion string) reporting.ScanReport {
+	counts := map[string]int{}
+	for _, f := range findings {
+		counts[f.Level]++
+	}
+	report := reporting.ScanReport{
+		ReportTitle: "Kubernetes Manifest Check Report",
+		Subheaders: []reporting.Subheader{
+			{Description: "Kubernetes version", Details: kubernetesVersion},
+		},
+		Overview: []reporting.OverviewRow{
+			{Description: "Checked resources", Details: fmt.Sprint(manifestCount)},
+			{Description: "Errors", Details: fmt.Sprint(counts[LevelError])},
+			{Description: "Warnings", Details: fmt.Sprint(counts[LevelWarning])},
+		},
+		SuccessfulScan: counts[LevelError] == 0,
+	}
+	table := reporting.ScanDetailTable{
+		NoRowsMessage: "No findings",
+		Headers:       []string{"Rule", "Level", "Resource", "File", "Message"},
+		WithCounter:   true,
+		CounterHeader: "Entry#",
+	}
+	for _, f := range findings {
+		var style reporting.ColumnStyle = reporting.Grey
+		switch f.Level {
+		case LevelError:
+			style = reporting.Red
+		case LevelWarning:
+			style = reporting.Yellow
+		}
+		row := reporting.ScanRow{}
+		row.AddColumn(f.RuleID, 0)
+		row.AddColumn(f.Level, style)
+		row.AddColumn(f.Resource, 0)
+		row.AddColumn(fmt.Sprintf("%s:%d", f.Source, f.Line), 0)
+		row.AddColumn(f.Message, 0)
+		table.Rows = append(table.Rows, row)
+	}
+	report.DetailTable = table
+	return report
+}
@@ -0,0 +1,185 @@
+//go:build unit
+// +build unit
+
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const compliantDeployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-app
+spec:
+  selector:
+    matchLabels:
+      app: my-app
+  template:
+    metadata:
+      labels:
+        app: my-app
+    spec:
+      securityContext:
+        runAsNonRoot: true
+      containers:
+      - name: app
+        image: registry.example.com:5000/my-app:1.0.0
+        resources:
+          limits:
+            cpu: 500m
+            memory: 256Mi
+        livenessProbe:
+          httpGet:
+            path: /health
+            port: 8080
+        readinessProbe:
+          httpGet:
+            path: /ready
+            port: 8080
+`
+
+func TestSplitManifests(t *testing.T) {
+	t.Run("multiple documents", func(t *testing.T) {
+		content := "---\n# comment only\n---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n---\n\napiVersion: v1\nkind: Service\nmetadata:\n  name: b\n"
+		manifests, findings := SplitManifests("all.yaml", []byte(content))
+
+		assert.Empty(t, findings)
+		if assert.Len(t, manifests, 2) {
+			assert.Equal(t, 4, manifests[0].Line)
+			assert.Equal(t, "ConfigMap/a", manifests[0].resourceName())
+			assert.Equal(t, 10, manifests[1].Line)
+			assert.Equal(t, "all.yaml", manifests[1].Source)
+		}
+	})
+
+	t.Run("invalid yaml", func(t *testing.T) {
+		_, findings := SplitManifests("broken.yaml", []byte("kind: [unclosed"))
+		if assert.Len(t, findings, 1) {
+			assert.Equal(t, RuleInvalidYAML, findings[0].RuleID)
+			assert.Equal(t, "broken.yaml", findings[0].Source)
+		}
+	})
+}
+
+func TestCheckManifests(t *testing.T) {
+	check := func(content string, options ManifestCheckOptions) []ManifestFinding {
+		manifests, _ := SplitManifests("manifest.yaml", []byte(content))
+		if len(options.KubernetesVersion) == 0 {
+			options.KubernetesVersion = "1.32"
+		}
+		findings, err := CheckManifests(manifests, options)
+		assert.NoError(t, err)
+		return findings
+	}
+	ruleIDs := func(findings []ManifestFinding) []string {
+		ids := []string{}
+		for _, f := range findings {
+			ids = append(ids, f.RuleID)
+		}
+		return ids
+	}
+
+	t.Run("compliant deployment", func(t *testing.T) {
+		assert.Empty(t, check(compliantDeployment, ManifestCheckOptions{}))
+	})
+
+	t.Run("schema violations", func(t *testing.T) {
+		findings := check("apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: x\nspec:\n  replicas: two\n", ManifestCheckOptions{})
+		if assert.Len(t, findings, 1) {
+			assert.Equal(t, RuleSchema, findings[0].RuleID)
+			assert.Equal(t, LevelError, findings[0].Level)
+			assert.Contains(t, findings[0].Message, "replicas")
+			assert.Equal(t, "Deployment/x", findings[0].Resource)
+		}
+
+		findings = check("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: x\ndata:\n  a: b\nunknownField: true\n", ManifestCheckOptions{})
+		if assert.Len(t, findings, 1) {
+			assert.Contains(t, findings[0].Message, `unknown field "unknownField"`)
+		}
+
+		findings = check("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  labels: {}\n", ManifestCheckOptions{})
+		if assert.Len(t, findings, 1) {
+			assert.Equal(t, "metadata.name must be set", findings[0].Message)
+		}
+	})
+
+	t.Run("removed api", func(t *testing.T) {
+		cronJob := "apiVersion: batch/v1beta1\nkind: CronJob\nmetadata:\n  name: x\nspec:\n  schedule: '* * * * *'\n  jobTemplate:\n    spec:\n      template:\n        spec:\n          restartPolicy: Never\n          securityContext:\n            runAsNonRoot: true\n          containers:\n          - name: job\n            image: job:1.0\n            resources:\n              limits: {cpu: 1, memory: 1Gi}\n"
+
+		findings := check(cronJob, ManifestCheckOptions{KubernetesVersion: "v1.32.3"})
+		if assert.Len(t, findings, 1) {
+			assert.Equal(t, RuleRemovedAPI, findings[0].RuleID)
+			assert.Equal(t, "batch/v1beta1 CronJob was removed in Kubernetes 1.25", findings[0].Message)
+		}
+	})
+
+	t.Run("custom resources are not validated", func(t *testing.T) {
+		findings := check("apiVersion: example.com/v1\nkind: Widget\nmetadata:\n  name: x\n", ManifestCheckOptions{})
+		assert.Equal(t, []string{RuleUnknownKind}, ruleIDs(findings))
+		assert.Equal(t, LevelNote, findings[0].Level)
+	})
+
+	t.Run("policy violations", func(t *testing.T) {
+		pod := "apiVersion: v1\nkind: Pod\nmetadata:\n  name: x\nspec:\n  containers:\n  - name: app\n    image: nginx\n    securityContext:\n      runAsUser: 0\n"
+		findings := check(pod, ManifestCheckOptions{})
+		assert.Equal(t, []string{RuleResourceLimits, RuleRunAsNonRoot, RuleImageTag, RuleProbes}, ruleIDs(findings))
+		assert.Equal(t, "container 'app' uses image 'nginx' without fixed tag", findings[2].Message)
+	})
+
+	t.Run("disabled rules", func(t *testing.T) {
+		pod := "apiVersion: v1\nkind: Pod\nmetadata:\n  name: x\nspec:\n  containers:\n  - name: app\n    image: nginx:latest\n"
+		findings := check(pod, ManifestCheckOptions{DisabledRules: []string{RuleResourceLimits, RuleProbes, RuleRunAsNonRoot}})
+		assert.Equal(t, []string{RuleImageTag}, ruleIDs(findings))
+	})
+
+	t.Run("invalid kubernetes version", func(t *testing.T) {
+		_, err := CheckManifests(nil, ManifestCheckOptions{KubernetesVersion: "latest"})
+		assert.EqualError(t, err, "invalid Kubernetes version 'latest', expected format '1.<minor>'")
+	})
+
+	t.Run("unsupported kubernetes version", func(t *testing.T) {
+		_, err := CheckManifests(nil, ManifestCheckOptions{KubernetesVersion: "1.30"})
+		assert.EqualError(t, err, "unsupported Kubernetes version '1.30', supported versions are 1.32")
+	})
+}
+
+func TestHasFixedImageTag(t *testing.T) {
+	assert.True(t, hasFixedImageTag("nginx:1.25"))
+	assert.True(t, hasFixedImageTag("registry:5000/nginx:1.25"))
+	assert.True(t, hasFixedImageTag("nginx@sha256:abcdef"))
+	assert.False(t, hasFixedImageTag("nginx"))
+	assert.False(t, hasFixedImageTag("nginx:latest"))
+	assert.False(t, hasFixedImageTag("registry:5000/nginx"))
+}
+
+func TestManifestReports(t *testing.T) {
+	findings := []ManifestFinding{
+		{RuleID: RuleSchema, Level: LevelError, Message: "broken", Source: "a.yaml", Line: 3, Resource: "Deployment/x"},
+		{RuleID: RuleImageTag, Level: LevelWarning, Message: "latest", Source: "b.yaml", Line: 1, Resource: "Pod/y"},
+	}
+
+	t.Run("sarif", func(t *testing.T) {
+		sarif := CreateManifestSarif(findings)
+		assert.Equal(t, "2.1.0", sarif.Version)
+		run := sarif.Runs[0]
+		assert.Equal(t, len(manifestRules), len(run.Tool.Driver.Rules))
+		if assert.Len(t, run.Results, 2) {
+			assert.Equal(t, "Deployment/x: broken", run.Results[0].Message.Text)
+			assert.Equal(t, "a.yaml", run.Results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+			assert.Equal(t, 3, run.Results[0].Locations[0].PhysicalLocation.Region.StartLine)
+			assert.Equal(t, RuleSchema, run.Tool.Driver.Rules[run.Results[0].RuleIndex].ID)
+		}
+	})
+
+	t.Run("scan report", func(t *testing.T) {
+		report := CreateManifestScanReport(findings, 5, "1.30")
+		assert.False(t, report.SuccessfulScan)
+		assert.Equal(t, "5", report.Overview[0].Details)
+		assert.Equal(t, "1", report.Overview[1].Details)
+		assert.Len(t, report.DetailTable.Rows, 2)
+		assert.True(t, CreateManifestScanReport(findings[1:], 1, "1.30").SuccessfulScan)
+	})
+}
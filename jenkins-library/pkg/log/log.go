@@ -7,6 +7,7 @@ import (
 	"os"
 	"regexp"
 	"strings"
+	"sync"
 
 	"github.com/sirupsen/logrus"
 )
@@ -88,9 +89,11 @@ func (formatter *PiperLogFormatter) Format(entry *logrus.Entry) (bytes []byte, e
 		message = string(formattedMessage)
 	}
 
+	secretsMutex.RLock()
 	for _, secret := range secrets {
 		message = strings.Replace(message, secret, "****", -1)
 	}
+	secretsMutex.RUnlock()
 
 	return []byte(message), nil
 }
@@ -100,6 +103,9 @@ var LibraryRepository string
 var LibraryName string
 var logger *logrus.Entry
 var secrets []string
+
+// secretsMutex guards secrets, since secrets are registered while requests are sent in parallel
+var secretsMutex sync.RWMutex
 var stepErrors []StepError
 var lastPatternMatch string
 
@@ -184,6 +190,8 @@ func Notice(args ...interface{}) {
 // RegisterSecret registers a value which should be masked in every log message
 func RegisterSecret(secret string) {
 	if len(secret) > 0 {
+		secretsMutex.Lock()
+		defer secretsMutex.Unlock()
 		secrets = append(secrets, secret)
 		encoded := url.QueryEscape(secret)
 		if secret != encoded {
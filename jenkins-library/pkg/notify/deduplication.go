@@ -0,0 +1,82 @@
+package notify
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DeduplicationState records when a failure was last sent to a channel. It is persisted between pipeline runs.
+type DeduplicationState struct {
+	Notifications map[string]SentNotification `json:"notifications"`
+}
+
+// SentNotification is a failure which was sent to a channel
+type SentNotification struct {
+	Channel   string    `json:"channel"`
+	StepName  string    `json:"stepName,omitempty"`
+	StageName string    `json:"stageName,omitempty"`
+	Branch    string    `json:"branch,omitempty"`
+	Time      time.Time `json:"time"`
+}
+
+// NewDeduplicationState returns an empty state
+func NewDeduplicationState() *DeduplicationState {
+	return &DeduplicationState{Notifications: map[string]SentNotification{}}
+}
+
+// ParseDeduplicationState parses a persisted state, empty content results in an empty state
+func ParseDeduplicationState(content []byte) (*DeduplicationState, error) {
+	state := NewDeduplicationState()
+	if len(content) == 0 {
+		return state, nil
+	}
+	if err := json.Unmarshal(content, state); err != nil {
+		return nil, errors.Wrap(err, "failed to parse deduplication state")
+	}
+	if state.Notifications == nil {
+		state.Notifications = map[string]SentNotification{}
+	}
+	return state, nil
+}
+
+// Suppressed returns whether the same failure was already sent to the channel within the window
+func (s *DeduplicationState) Suppressed(channel string, event Event, now time.Time, window time.Duration) bool {
+	sent, ok := s.Notifications[notificationKey(channel, event)]
+	return ok && now.Sub(sent.Time) < window
+}
+
+// Record stores that the failure was sent to the channel
+func (s *DeduplicationState) Record(channel string, event Event, now time.Time) {
+	s.Notifications[notificationKey(channel, event)] = SentNotification{
+		Channel:   channel,
+		StepName:  event.StepName,
+		StageName: event.StageName,
+		Branch:    event.Branch,
+		Time:      now,
+	}
+}
+
+// Resolve forgets the failures in the stage and branch of the event, so that a recurring failure is sent again.
+// Events without step name resolve the failures of all steps of the stage.
+func (s *DeduplicationState) Resolve(event Event) {
+	for key, sent := range s.Notifications {
+		if sent.StageName == event.StageName && sent.Branch == event.Branch && (len(event.StepName) == 0 || sent.StepName == event.StepName) {
+			delete(s.Notifications, key)
+		}
+	}
+}
+
+// Prune removes the entries which are older than the window
+func (s *DeduplicationState) Prune(now time.Time, window time.Duration) {
+	for key, sent := range s.Notifications {
+		if now.Sub(sent.Time) >= window {
+			delete(s.Notifications, key)
+		}
+	}
+}
+
+func notificationKey(channel string, event Event) string {
+	return channel + "/" + event.Fingerprint()
+}
@@ -0,0 +1,239 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/ans"
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/piperenv"
+	"github.com/pkg/errors"
+)
+
+// DefaultTemplate is the message template of channels without template
+const DefaultTemplate = `{{ if eq .Event.Status "failure" }}Step '{{ .Event.StepName }}' failed{{ with .Event.StageName }} in stage '{{ . }}'{{ end }}` +
+	`{{ else }}Stage '{{ .Event.StageName }}' succeeded{{ end }}{{ with .Event.Branch }} on branch '{{ . }}'{{ end }}` +
+	`{{ with .Event.Message }}: {{ . }}{{ end }}{{ with .Event.BuildURL }} ({{ . }}){{ end }}`
+
+// Result is the outcome of the notification of a channel
+type Result struct {
+	Channel    string `json:"channel"`
+	StepName   string `json:"stepName,omitempty"`
+	Status     string `json:"status"`
+	Sent       bool   `json:"sent"`
+	Suppressed bool   `json:"suppressed,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Dispatcher sends events to the channels selected by the routing rules
+type Dispatcher struct {
+	Rules    []Rule
+	Channels map[string]Channel
+	// HTTPClient is used for webhooks, Slack and Microsoft Teams
+	HTTPClient piperhttp.Sender
+	// ANSClient is used for channels of type ans, it has to be configured with a service key
+	ANSClient ans.Client
+	// CPE provides the values of the common pipeline environment to the templates
+	CPE piperenv.CPEMap
+	// State and Window deduplicate repeated failures, without state every event is sent
+	State  *DeduplicationState
+	Window time.Duration
+	Getenv func(string) string
+	Now    func() time.Time
+}
+
+// Dispatch sends the event to all channels of the matching rules. Errors of single channels do not prevent the
+// notification of the other channels, they are returned in the results and as combined error.
+func (d *Dispatcher) Dispatch(event Event) ([]Result, error) {
+	now := d.now()
+	if event.Time.IsZero() {
+		event.Time = now
+	}
+	if event.Status == StatusSuccess && d.State != nil {
+		d.State.Resolve(event)
+	}
+
+	results := []Result{}
+	failed := []string{}
+	for _, name := range Route(d.Rules, event) {
+		result := Result{Channel: name, StepName: event.StepName, Status: event.Status}
+		if d.State != nil && event.Status == StatusFailure && d.State.Suppressed(name, event, now, d.Window) {
+			result.Suppressed = true
+			results = append(results, result)
+			continue
+		}
+		if err := d.send(d.Channels[name], event); err != nil {
+			result.Error = err.Error()
+			failed = append(failed, fmt.Sprintf("%v: %v", name, err))
+		} else {
+			result.Sent = true
+			if d.State != nil && event.Status == StatusFailure {
+				d.State.Record(name, event, now)
+			}
+		}
+		results = append(results, result)
+	}
+	if len(failed) > 0 {
+		return results, errors.Errorf("failed to notify channels: %v", strings.Join(failed, "; "))
+	}
+	return results, nil
+}
+
+func (d *Dispatcher) send(channel Channel, event Event) error {
+	message, err := d.render(channel.Template, event)
+	if err != nil {
+		return err
+	}
+	switch channel.Type {
+	case ChannelANS:
+		if d.ANSClient == nil {
+			return errors.New("no service key of the Alert Notification Service configured")
+		}
+		return d.ANSClient.Send(ansEvent(event, message))
+	case ChannelSlack:
+		return d.post(channel, map[string]string{"text": message})
+	case ChannelTeams:
+		return d.post(channel, teamsMessage(event, message))
+	case ChannelWebhook:
+		if len(channel.Template) == 0 {
+			return d.post(channel, event)
+		}
+		return d.post(channel, message)
+	}
+	return errors.Errorf("unsupported channel type '%v'", channel.Type)
+}
+
+func (d *Dispatcher) render(tmpl string, event Event) (string, error) {
+	if len(tmpl) == 0 {
+		tmpl = DefaultTemplate
+	}
+	cpe := d.CPE
+	if cpe == nil {
+		cpe = piperenv.CPEMap{}
+	}
+	rendered, err := cpe.ParseTemplateWithValues(tmpl, map[string]interface{}{"Event": event})
+	if err != nil {
+		return "", err
+	}
+	return rendered.String(), nil
+}
+
+// post sends the payload to the webhook of the channel. String payloads are sent as they are, other payloads are sent as JSON.
+func (d *Dispatcher) post(channel Channel, payload interface{}) error {
+	webhookURL := channel.URL
+	if len(channel.URLEnv) > 0 {
+		webhookURL = d.getenv(channel.URLEnv)
+	}
+	if len(webhookURL) == 0 {
+		return errors.Errorf("no webhook url available, environment variable '%v' is empty", channel.URLEnv)
+	}
+
+	body, ok := payload.(string)
+	if !ok {
+		content, err := json.Marshal(payload)
+		if err != nil {
+			return errors.Wrap(err, "failed to create request payload")
+		}
+		body = string(content)
+	}
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	for key, value := range channel.Headers {
+		header.Set(key, value)
+	}
+
+	// the webhook url usually contains a secret, therefore the errors of the client which contain the url are not returned
+	response, _ := d.HTTPClient.SendRequest(http.MethodPost, webhookURL, bytes.NewBufferString(body), header, nil)
+	if response == nil {
+		return errors.New("did not retrieve a HTTP response from the webhook")
+	}
+	if response.Body != nil {
+		defer response.Body.Close()
+	}
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		responseBody, _ := io.ReadAll(response.Body)
+		return errors.Errorf("webhook returned unexpected response status code %v: %v", response.StatusCode, string(responseBody))
+	}
+	return nil
+}
+
+func (d *Dispatcher) now() time.Time {
+	if d.Now != nil {
+		return d.Now()
+	}
+	return time.Now()
+}
+
+func (d *Dispatcher) getenv(key string) string {
+	if d.Getenv != nil {
+		return d.Getenv(key)
+	}
+	return os.Getenv(key)
+}
+
+func ansEvent(event Event, message string) ans.Event {
+	ansEvent := ans.Event{
+		EventType:      "PiperStageSucceeded",
+		EventTimestamp: event.Time.Unix(),
+		Severity:       "INFO",
+		Category:       "NOTIFICATION",
+		Subject:        subject(event),
+		Body:           message,
+		Tags:           map[string]interface{}{},
+		Resource: &ans.Resource{
+			ResourceName: "Pipeline",
+			ResourceType: "Pipeline",
+		},
+	}
+	if event.Status == StatusFailure {
+		ansEvent.EventType = "PiperStepFailed"
+		ansEvent.Severity = "ERROR"
+		ansEvent.Category = "EXCEPTION"
+	}
+	if len(event.JobURL) > 0 {
+		ansEvent.Resource.ResourceInstance = event.JobURL
+	}
+	tags := map[string]string{
+		"cicd:stepName":      event.StepName,
+		"cicd:stageName":     event.StageName,
+		"cicd:errorCategory": event.ErrorCategory,
+		"cicd:branch":        event.Branch,
+		"cicd:buildReason":   event.BuildReason,
+		"cicd:buildUrl":      event.BuildURL,
+		"cicd:commitId":      event.CommitID,
+	}
+	for key, value := range tags {
+		if len(value) > 0 {
+			ansEvent.Tags[key] = value
+		}
+	}
+	return ansEvent
+}
+
+func teamsMessage(event Event, message string) map[string]string {
+	themeColor := "2EB886"
+	if event.Status == StatusFailure {
+		themeColor = "D50000"
+	}
+	return map[string]string{
+		"@type":      "MessageCard",
+		"@context":   "https://schema.org/extensions",
+		"summary":    subject(event),
+		"title":      subject(event),
+		"text":       message,
+		"themeColor": themeColor,
+	}
+}
+
+func subject(event Event) string {
+	if event.Status == StatusFailure {
+		return fmt.Sprintf("Pipeline step %v failed", event.StepName)
+	}
+	return fmt.Sprintf("Pipeline stage %v succeeded", event.StageName)
+}
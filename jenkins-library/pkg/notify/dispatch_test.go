@@ -0,0 +1,241 @@
+//go:build unit
+// +build unit
+
+package notify_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/ans"
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/notify"
+	"github.com/SAP/jenkins-library/pkg/piperenv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type receivedRequest struct {
+	Path   string
+	Header http.Header
+	Body   string
+}
+
+type webhookReceiver struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []receivedRequest
+}
+
+func newWebhookReceiver(t *testing.T) *webhookReceiver {
+	receiver := &webhookReceiver{}
+	receiver.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		receiver.mu.Lock()
+		defer receiver.mu.Unlock()
+		receiver.requests = append(receiver.requests, receivedRequest{Path: r.URL.Path, Header: r.Header, Body: string(body)})
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(receiver.Close)
+	return receiver
+}
+
+func (r *webhookReceiver) received() []receivedRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]receivedRequest{}, r.requests...)
+}
+
+type ansClientMock struct {
+	events []ans.Event
+}
+
+func (a *ansClientMock) Send(event ans.Event) error {
+	a.events = append(a.events, event)
+	return nil
+}
+
+func (a *ansClientMock) CheckCorrectSetup() error {
+	return nil
+}
+
+func (a *ansClientMock) SetServiceKey(serviceKey ans.ServiceKey) {}
+
+func TestDispatch(t *testing.T) {
+	t.Parallel()
+	failure := notify.Event{
+		Status:        notify.StatusFailure,
+		StepName:      "mavenBuild",
+		StageName:     "Build",
+		Branch:        "main",
+		ErrorCategory: "build",
+		Message:       "compilation failed",
+		BuildURL:      "https://ci/job/1",
+		Time:          time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	t.Run("send to all channel types", func(t *testing.T) {
+		t.Parallel()
+		receiver := newWebhookReceiver(t)
+		ansClient := &ansClientMock{}
+		dispatcher := notify.Dispatcher{
+			Rules: []notify.Rule{{Name: "all", Channels: []string{"slack", "teams", "hook", "custom", "alerts"}}},
+			Channels: map[string]notify.Channel{
+				"slack":  {Name: "slack", Type: notify.ChannelSlack, URL: receiver.URL + "/slack"},
+				"teams":  {Name: "teams", Type: notify.ChannelTeams, URLEnv: "TEAMS_URL"},
+				"hook":   {Name: "hook", Type: notify.ChannelWebhook, URL: receiver.URL + "/hook", Headers: map[string]string{"X-Token": "secret"}},
+				"custom": {Name: "custom", Type: notify.ChannelWebhook, URL: receiver.URL + "/custom", Template: `{"version": "{{ cpe "artifactVersion" }}", "step": "{{ .Event.StepName }}"}`},
+				"alerts": {Name: "alerts", Type: notify.ChannelANS},
+			},
+			HTTPClient: &piperhttp.Client{},
+			ANSClient:  ansClient,
+			CPE:        piperenv.CPEMap{"artifactVersion": "1.2.3"},
+			Getenv:     func(key string) string { return map[string]string{"TEAMS_URL": receiver.URL + "/teams"}[key] },
+		}
+
+		results, err := dispatcher.Dispatch(failure)
+
+		require.NoError(t, err)
+		assert.Len(t, results, 5)
+		requests := receiver.received()
+		require.Len(t, requests, 4)
+
+		message := "Step 'mavenBuild' failed in stage 'Build' on branch 'main': compilation failed (https://ci/job/1)"
+		assert.Equal(t, "/slack", requests[0].Path)
+		assert.JSONEq(t, fmt.Sprintf(`{"text": %q}`, message), requests[0].Body)
+
+		assert.Equal(t, "/teams", requests[1].Path)
+		var card map[string]string
+		require.NoError(t, json.Unmarshal([]byte(requests[1].Body), &card))
+		assert.Equal(t, "MessageCard", card["@type"])
+		assert.Equal(t, message, card["text"])
+		assert.Equal(t, "Pipeline step mavenBuild failed", card["title"])
+
+		assert.Equal(t, "/hook", requests[2].Path)
+		assert.Equal(t, "secret", requests[2].Header.Get("X-Token"))
+		var event notify.Event
+		require.NoError(t, json.Unmarshal([]byte(requests[2].Body), &event))
+		assert.Equal(t, failure, event)
+
+		assert.Equal(t, "/custom", requests[3].Path)
+		assert.JSONEq(t, `{"version": "1.2.3", "step": "mavenBuild"}`, requests[3].Body)
+
+		require.Len(t, ansClient.events, 1)
+		assert.Equal(t, "ERROR", ansClient.events[0].Severity)
+		assert.Equal(t, "EXCEPTION", ansClient.events[0].Category)
+		assert.Equal(t, message, ansClient.events[0].Body)
+		assert.Equal(t, "build", ansClient.events[0].Tags["cicd:errorCategory"])
+		assert.NoError(t, ansClient.events[0].Validate())
+	})
+
+	t.Run("failing channel does not block other channels", func(t *testing.T) {
+		t.Parallel()
+		receiver := newWebhookReceiver(t)
+		dispatcher := notify.Dispatcher{
+			Rules: []notify.Rule{{Channels: []string{"broken", "slack"}}},
+			Channels: map[string]notify.Channel{
+				"broken": {Name: "broken", Type: notify.ChannelWebhook, URL: receiver.URL + "/broken"},
+				"slack":  {Name: "slack", Type: notify.ChannelSlack, URL: receiver.URL + "/slack"},
+			},
+			HTTPClient: &piperhttp.Client{},
+		}
+
+		results, err := dispatcher.Dispatch(failure)
+
+		assert.EqualError(t, err, "failed to notify channels: broken: webhook returned unexpected response status code 500: ")
+		assert.NotContains(t, err.Error(), receiver.URL)
+		assert.Len(t, receiver.received(), 2)
+		assert.False(t, results[0].Sent)
+		assert.True(t, results[1].Sent)
+	})
+
+	t.Run("repeated failures are deduplicated", func(t *testing.T) {
+		t.Parallel()
+		receiver := newWebhookReceiver(t)
+		now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+		dispatcher := notify.Dispatcher{
+			Rules:      []notify.Rule{{Status: []string{"failure", "success"}, Channels: []string{"slack"}}},
+			Channels:   map[string]notify.Channel{"slack": {Name: "slack", Type: notify.ChannelSlack, URL: receiver.URL + "/slack"}},
+			HTTPClient: &piperhttp.Client{},
+			State:      notify.NewDeduplicationState(),
+			Window:     time.Hour,
+			Now:        func() time.Time { return now },
+		}
+
+		_, err := dispatcher.Dispatch(failure)
+		require.NoError(t, err)
+		results, err := dispatcher.Dispatch(failure)
+		require.NoError(t, err)
+		assert.True(t, results[0].Suppressed)
+		assert.Len(t, receiver.received(), 1)
+
+		// a different failure of the same step is sent
+		otherFailure := failure
+		otherFailure.Message = "tests failed"
+		_, err = dispatcher.Dispatch(otherFailure)
+		require.NoError(t, err)
+		assert.Len(t, receiver.received(), 2)
+
+		// the failure is sent again after the window
+		now = now.Add(2 * time.Hour)
+		_, err = dispatcher.Dispatch(failure)
+		require.NoError(t, err)
+		assert.Len(t, receiver.received(), 3)
+
+		// a success of the stage resolves the failures
+		_, err = dispatcher.Dispatch(notify.Event{Status: notify.StatusSuccess, StageName: "Build", Branch: "main"})
+		require.NoError(t, err)
+		assert.Empty(t, dispatcher.State.Notifications)
+		assert.Len(t, receiver.received(), 4)
+	})
+}
+
+func TestDeduplicationState(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	state := notify.NewDeduplicationState()
+	state.Record("slack", notify.Event{Status: notify.StatusFailure, StepName: "a"}, now.Add(-2*time.Hour))
+	state.Record("slack", notify.Event{Status: notify.StatusFailure, StepName: "b"}, now)
+
+	state.Prune(now, time.Hour)
+	content, err := json.Marshal(state)
+	require.NoError(t, err)
+
+	parsed, err := notify.ParseDeduplicationState(content)
+	require.NoError(t, err)
+	assert.Len(t, parsed.Notifications, 1)
+	assert.True(t, parsed.Suppressed("slack", notify.Event{Status: notify.StatusFailure, StepName: "b"}, now, time.Hour))
+	assert.False(t, parsed.Suppressed("teams", notify.Event{Status: notify.StatusFailure, StepName: "b"}, now, time.Hour))
+
+	empty, err := notify.ParseDeduplicationState(nil)
+	require.NoError(t, err)
+	assert.Empty(t, empty.Notifications)
+
+	_, err = notify.ParseDeduplicationState([]byte("{"))
+	assert.EqualError(t, err, "failed to parse deduplication state: unexpected end of JSON input")
+}
+
+func TestEventFromErrorDetails(t *testing.T) {
+	t.Parallel()
+	event, err := notify.EventFromErrorDetails([]byte(`{"stepName": "mavenBuild", "category": "undefined", "message": "step execution failed", "error": "exit status 1", "result": "failure", "time": "2024-01-01T00:00:00Z"}`))
+	require.NoError(t, err)
+	assert.Equal(t, notify.Event{
+		Status:   notify.StatusFailure,
+		StepName: "mavenBuild",
+		Message:  "step execution failed: exit status 1",
+		Time:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}, event)
+
+	_, err = notify.EventFromErrorDetails([]byte("no json"))
+	assert.Error(t, err)
+}
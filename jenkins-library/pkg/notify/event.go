@@ -0,0 +1,76 @@
+package notify
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// StatusSuccess is the status of events of successful steps or stages
+	StatusSuccess = "success"
+	// StatusFailure is the status of events of failed steps
+	StatusFailure = "failure"
+)
+
+// Event is a pipeline event which is routed to the notification channels
+type Event struct {
+	Status        string    `json:"status"`
+	StepName      string    `json:"stepName,omitempty"`
+	StageName     string    `json:"stageName,omitempty"`
+	ErrorCategory string    `json:"errorCategory,omitempty"`
+	Message       string    `json:"message,omitempty"`
+	Branch        string    `json:"branch,omitempty"`
+	BuildReason   string    `json:"buildReason,omitempty"`
+	BuildURL      string    `json:"buildUrl,omitempty"`
+	JobURL        string    `json:"jobUrl,omitempty"`
+	CommitID      string    `json:"commitId,omitempty"`
+	Time          time.Time `json:"time"`
+}
+
+// Scope identifies the step, stage and branch of the event independently of its status
+func (e Event) Scope() string {
+	return strings.Join([]string{e.StepName, e.StageName, e.Branch}, "|")
+}
+
+// Fingerprint identifies repeated occurrences of the same failure
+func (e Event) Fingerprint() string {
+	hash := sha256.Sum256([]byte(strings.Join([]string{e.Scope(), e.Status, e.ErrorCategory, e.Message}, "\x00")))
+	return hex.EncodeToString(hash[:])
+}
+
+// errorDetails is the content of the error details file which is written by log.FatalHook for failed steps
+type errorDetails struct {
+	StepName string    `json:"stepName"`
+	Category string    `json:"category"`
+	Message  string    `json:"message"`
+	Error    string    `json:"error"`
+	Time     time.Time `json:"time"`
+}
+
+// EventFromErrorDetails creates a failure event from the error details of a failed step
+func EventFromErrorDetails(content []byte) (Event, error) {
+	var details errorDetails
+	if err := json.Unmarshal(content, &details); err != nil {
+		return Event{}, errors.Wrap(err, "failed to parse error details")
+	}
+	message := details.Message
+	if len(details.Error) > 0 && details.Error != "<nil>" && !strings.Contains(message, details.Error) {
+		message = strings.TrimSpace(message + ": " + details.Error)
+	}
+	category := details.Category
+	if category == "undefined" {
+		category = ""
+	}
+	return Event{
+		Status:        StatusFailure,
+		StepName:      details.StepName,
+		ErrorCategory: category,
+		Message:       message,
+		Time:          details.Time,
+	}, nil
+}
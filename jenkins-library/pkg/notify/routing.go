@@ -0,0 +1,151 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// ChannelANS sends events to the SAP Alert Notification Service
+	ChannelANS = "ans"
+	// ChannelWebhook posts events to a generic webhook
+	ChannelWebhook = "webhook"
+	// ChannelSlack posts messages to a Slack incoming webhook
+	ChannelSlack = "slack"
+	// ChannelTeams posts messages to a Microsoft Teams incoming webhook
+	ChannelTeams = "teams"
+)
+
+// Rule routes the events which match all of its conditions to its channels. Empty conditions match every event.
+type Rule struct {
+	Name string `json:"name"`
+	// Steps, Stages and Branches are glob patterns, e.g. 'release/*'
+	Steps    []string `json:"steps,omitempty"`
+	Stages   []string `json:"stages,omitempty"`
+	Branches []string `json:"branches,omitempty"`
+	// ErrorCategories are error categories as defined by log.ErrorCategory, e.g. 'build' or 'infrastructure'
+	ErrorCategories []string `json:"errorCategories,omitempty"`
+	BuildReasons    []string `json:"buildReasons,omitempty"`
+	// Status is the list of event states the rule applies to, by default only failures are routed
+	Status   []string `json:"status,omitempty"`
+	Channels []string `json:"channels"`
+}
+
+// Channel is a notification target
+type Channel struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	// URL is the URL of webhooks, alternatively URLEnv names an environment variable containing the URL
+	URL     string            `json:"url,omitempty"`
+	URLEnv  string            `json:"urlEnv,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	// Template is the message of the notification, for generic webhooks it is the complete request body
+	Template string `json:"template,omitempty"`
+}
+
+// Matches returns whether the event fulfills all conditions of the rule
+func (r Rule) Matches(event Event) bool {
+	status := r.Status
+	if len(status) == 0 {
+		status = []string{StatusFailure}
+	}
+	return matchesAny(status, event.Status, equalFold) &&
+		matchesAny(r.Steps, event.StepName, globMatch) &&
+		matchesAny(r.Stages, event.StageName, globMatch) &&
+		matchesAny(r.Branches, event.Branch, globMatch) &&
+		matchesAny(r.ErrorCategories, event.ErrorCategory, equalFold) &&
+		matchesAny(r.BuildReasons, event.BuildReason, equalFold)
+}
+
+func matchesAny(patterns []string, value string, match func(pattern, value string) bool) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if match(pattern, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func globMatch(pattern, value string) bool {
+	matched, _ := path.Match(pattern, value)
+	return matched
+}
+
+func equalFold(pattern, value string) bool {
+	return strings.EqualFold(pattern, value)
+}
+
+// Route returns the names of the channels of all rules which match the event, every channel is returned once
+func Route(rules []Rule, event Event) []string {
+	channels := []string{}
+	seen := map[string]bool{}
+	for _, rule := range rules {
+		if !rule.Matches(event) {
+			continue
+		}
+		for _, channel := range rule.Channels {
+			if !seen[channel] {
+				seen[channel] = true
+				channels = append(channels, channel)
+			}
+		}
+	}
+	return channels
+}
+
+// ParseConfiguration converts the rules and channels of the step configuration and validates them
+func ParseConfiguration(rulesConfig, channelsConfig []map[string]interface{}) ([]Rule, map[string]Channel, error) {
+	rules := []Rule{}
+	if err := strictConvert(rulesConfig, &rules); err != nil {
+		return nil, nil, errors.Wrap(err, "invalid notification rules")
+	}
+	channelList := []Channel{}
+	if err := strictConvert(channelsConfig, &channelList); err != nil {
+		return nil, nil, errors.Wrap(err, "invalid notification channels")
+	}
+
+	channels := map[string]Channel{}
+	for _, channel := range channelList {
+		if len(channel.Name) == 0 {
+			return nil, nil, errors.New("notification channel without name")
+		}
+		if _, ok := channels[channel.Name]; ok {
+			return nil, nil, errors.Errorf("notification channel '%v' is defined more than once", channel.Name)
+		}
+		switch channel.Type {
+		case ChannelANS:
+		case ChannelWebhook, ChannelSlack, ChannelTeams:
+			if len(channel.URL) == 0 && len(channel.URLEnv) == 0 {
+				return nil, nil, errors.Errorf("notification channel '%v' requires a url or urlEnv", channel.Name)
+			}
+		default:
+			return nil, nil, errors.Errorf("notification channel '%v' has unsupported type '%v'", channel.Name, channel.Type)
+		}
+		channels[channel.Name] = channel
+	}
+	for _, rule := range rules {
+		for _, channel := range rule.Channels {
+			if _, ok := channels[channel]; !ok {
+				return nil, nil, errors.Errorf("notification rule '%v' refers to unknown channel '%v'", rule.Name, channel)
+			}
+		}
+	}
+	return rules, channels, nil
+}
+
+func strictConvert(source interface{}, target interface{}) error {
+	content, err := json.Marshal(source)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(target)
+}
@@ -0,0 +1,93 @@
+//go:build unit
+// +build unit
+
+package notify_test
+
+import (
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/notify"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRuleMatches(t *testing.T) {
+	failure := notify.Event{Status: notify.StatusFailure, StepName: "mavenBuild", StageName: "Build", Branch: "release/1.0", ErrorCategory: "build", BuildReason: "Schedule"}
+
+	tt := []struct {
+		name     string
+		rule     notify.Rule
+		event    notify.Event
+		expected bool
+	}{
+		{name: "empty rule matches failures", rule: notify.Rule{}, event: failure, expected: true},
+		{name: "empty rule ignores successes", rule: notify.Rule{}, event: notify.Event{Status: notify.StatusSuccess}, expected: false},
+		{name: "status success", rule: notify.Rule{Status: []string{"success"}}, event: notify.Event{Status: notify.StatusSuccess}, expected: true},
+		{name: "step glob", rule: notify.Rule{Steps: []string{"maven*"}}, event: failure, expected: true},
+		{name: "branch glob", rule: notify.Rule{Branches: []string{"main", "release/*"}}, event: failure, expected: true},
+		{name: "branch mismatch", rule: notify.Rule{Branches: []string{"main"}}, event: failure, expected: false},
+		{name: "error category case insensitive", rule: notify.Rule{ErrorCategories: []string{"Build"}}, event: failure, expected: true},
+		{name: "error category mismatch", rule: notify.Rule{ErrorCategories: []string{"infrastructure"}}, event: failure, expected: false},
+		{name: "build reason", rule: notify.Rule{BuildReasons: []string{"Schedule"}}, event: failure, expected: true},
+		{name: "all conditions required", rule: notify.Rule{Stages: []string{"Build"}, BuildReasons: []string{"PullRequest"}}, event: failure, expected: false},
+	}
+	for _, test := range tt {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.rule.Matches(test.event))
+		})
+	}
+}
+
+func TestRoute(t *testing.T) {
+	rules := []notify.Rule{
+		{Name: "all", Channels: []string{"teams"}},
+		{Name: "infrastructure", ErrorCategories: []string{"infrastructure"}, Channels: []string{"ans", "teams"}},
+		{Name: "main", Branches: []string{"main"}, Channels: []string{"slack"}},
+	}
+	channels := notify.Route(rules, notify.Event{Status: notify.StatusFailure, ErrorCategory: "infrastructure", Branch: "feature"})
+	assert.Equal(t, []string{"teams", "ans"}, channels)
+
+	assert.Empty(t, notify.Route(rules, notify.Event{Status: notify.StatusSuccess}))
+}
+
+func TestParseConfiguration(t *testing.T) {
+	channelsConfig := []map[string]interface{}{
+		{"name": "alerts", "type": "ans"},
+		{"name": "team", "type": "teams", "urlEnv": "TEAMS_WEBHOOK"},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		rules, channels, err := notify.ParseConfiguration([]map[string]interface{}{
+			{"name": "build failures", "errorCategories": []interface{}{"build"}, "channels": []interface{}{"team", "alerts"}},
+		}, channelsConfig)
+		if assert.NoError(t, err) {
+			assert.Equal(t, []notify.Rule{{Name: "build failures", ErrorCategories: []string{"build"}, Channels: []string{"team", "alerts"}}}, rules)
+			assert.Equal(t, notify.Channel{Name: "team", Type: "teams", URLEnv: "TEAMS_WEBHOOK"}, channels["team"])
+			assert.Len(t, channels, 2)
+		}
+	})
+
+	t.Run("unknown property", func(t *testing.T) {
+		_, _, err := notify.ParseConfiguration([]map[string]interface{}{{"name": "r", "branch": "main"}}, channelsConfig)
+		assert.EqualError(t, err, `invalid notification rules: json: unknown field "branch"`)
+	})
+
+	t.Run("unknown channel", func(t *testing.T) {
+		_, _, err := notify.ParseConfiguration([]map[string]interface{}{{"name": "r", "channels": []interface{}{"mail"}}}, channelsConfig)
+		assert.EqualError(t, err, "notification rule 'r' refers to unknown channel 'mail'")
+	})
+
+	t.Run("webhook without url", func(t *testing.T) {
+		_, _, err := notify.ParseConfiguration(nil, []map[string]interface{}{{"name": "hook", "type": "webhook"}})
+		assert.EqualError(t, err, "notification channel 'hook' requires a url or urlEnv")
+	})
+
+	t.Run("unsupported type", func(t *testing.T) {
+		_, _, err := notify.ParseConfiguration(nil, []map[string]interface{}{{"name": "mail", "type": "email"}})
+		assert.EqualError(t, err, "notification channel 'mail' has unsupported type 'email'")
+	})
+
+	t.Run("duplicate channel", func(t *testing.T) {
+		_, _, err := notify.ParseConfiguration(nil, append(channelsConfig, map[string]interface{}{"name": "alerts", "type": "ans"}))
+		assert.EqualError(t, err, "notification channel 'alerts' is defined more than once")
+	})
+}
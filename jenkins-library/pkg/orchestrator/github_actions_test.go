@@ -335,3 +335,38 @@ func TestWorkflowFileName(t *testing.T) {
 		})
 	}
 }
+
+func TestPullRequestNumber(t *testing.T) {
+	defer resetEnv(os.Environ())
+	defer ResetConfigProvider()
+
+	for _, tt := range []struct {
+		name    string
+		headRef string
+		ref     string
+		want    int
+		wantErr string
+	}{
+		{"pull request", "feature", "refs/pull/42/merge", 42, ""},
+		{"no pull request", "", "refs/heads/main", 0, ""},
+		{"invalid number", "feature", "refs/pull/abc/merge", 0, "failed to determine pull request number from 'abc'"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Clearenv()
+			ResetConfigProvider()
+			_ = os.Setenv("GITHUB_ACTION", "run")
+			_ = os.Setenv("GITHUB_ACTIONS", "true")
+			_ = os.Setenv("GITHUB_HEAD_REF", tt.headRef)
+			_ = os.Setenv("GITHUB_REF", tt.ref)
+
+			number, err := PullRequestNumber()
+
+			if len(tt.wantErr) > 0 {
+				assert.ErrorContains(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, number)
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package orchestrator
 
 import (
+	"strconv"
 	"sync"
 	"time"
 
@@ -113,6 +114,23 @@ func GetOrchestratorConfigProvider(opts *Options) (ConfigProvider, error) {
 	return provider, nil
 }
 
+// PullRequestNumber returns the number of the pull request the pipeline runs for, or 0 if it is no pull request build
+func PullRequestNumber() (int, error) {
+	provider, err := GetOrchestratorConfigProvider(nil)
+	if err != nil {
+		return 0, err
+	}
+	if !provider.IsPullRequest() {
+		return 0, nil
+	}
+	key := provider.PullRequestConfig().Key
+	number, err := strconv.Atoi(key)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to determine pull request number from '%v'", key)
+	}
+	return number, nil
+}
+
 // DetectOrchestrator function determines in which orchestrator Piper is running by examining environment variables.
 func DetectOrchestrator() Orchestrator {
 	if isAzure() {
@@ -17,6 +17,16 @@ func (c *CPEMap) ParseTemplate(cpeTemplate string) (*bytes.Buffer, error) {
 }
 
 func (c *CPEMap) ParseTemplateWithDelimiter(cpeTemplate string, startDelimiter string, endDelimiter string) (*bytes.Buffer, error) {
+	return c.parseTemplate(cpeTemplate, startDelimiter, endDelimiter, nil)
+}
+
+// ParseTemplateWithValues allows to parse a template which contains references to the CPE as well as to additional values.
+// The values are accessible by their key, e.g. {{ .Event.StepName }}, the CPE is accessible via {{ .CPE }}.
+func (c *CPEMap) ParseTemplateWithValues(cpeTemplate string, values map[string]interface{}) (*bytes.Buffer, error) {
+	return c.parseTemplate(cpeTemplate, DEFAULT_START_DELIMITER, DEFAULT_END_DELIMITER, values)
+}
+
+func (c *CPEMap) parseTemplate(cpeTemplate string, startDelimiter string, endDelimiter string, values map[string]interface{}) (*bytes.Buffer, error) {
 	funcMap := template.FuncMap{
 		"cpe":         c.cpe,
 		"cpecustom":   c.custom,
@@ -33,11 +43,11 @@ func (c *CPEMap) ParseTemplateWithDelimiter(cpeTemplate string, startDelimiter s
 		return nil, fmt.Errorf("failed to parse cpe template '%v': %w", cpeTemplate, err)
 	}
 
-	tmplParams := struct {
-		CPE map[string]interface{}
-	}{
-		CPE: map[string]interface{}(*c),
+	tmplParams := map[string]interface{}{}
+	for key, value := range values {
+		tmplParams[key] = value
 	}
+	tmplParams["CPE"] = map[string]interface{}(*c)
 
 	var generated bytes.Buffer
 	err = tmpl.Execute(&generated, tmplParams)
@@ -69,6 +69,15 @@ func TestParseTemplateWithDelimiter(t *testing.T) {
 	}
 }
 
+func TestParseTemplateWithValues(t *testing.T) {
+	cpe := CPEMap{"git/branch": "main"}
+
+	res, err := cpe.ParseTemplateWithValues(`{{.Event.StepName}} failed on {{git "branch"}}`, map[string]interface{}{"Event": struct{ StepName string }{StepName: "mavenBuild"}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "mavenBuild failed on main", res.String())
+}
+
 func TestTemplateFunctionCpe(t *testing.T) {
 	t.Run("CPE from object", func(t *testing.T) {
 		tt := []struct {
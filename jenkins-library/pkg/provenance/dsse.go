@@ -0,0 +1,145 @@
+package provenance
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// PayloadType is the DSSE payload type of in-toto statements
+const PayloadType = "application/vnd.in-toto+json"
+
+// Envelope is a DSSE envelope, see https://github.com/secure-systems-lab/dsse
+type Envelope struct {
+	PayloadType string      `json:"payloadType"`
+	Payload     string      `json:"payload"`
+	Signatures  []Signature `json:"signatures"`
+}
+
+// Signature is the signature of a DSSE envelope
+type Signature struct {
+	KeyID string `json:"keyid,omitempty"`
+	Sig   string `json:"sig"`
+}
+
+// LoadSigner parses a PEM encoded, unencrypted ECDSA, Ed25519 or RSA private key
+func LoadSigner(keyPEM []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, fmt.Errorf("signing key is not PEM encoded")
+	}
+	var key interface{}
+	var err error
+	switch block.Type {
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported signing key type '%v'", block.Type)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse signing key")
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported signing key")
+	}
+	return signer, nil
+}
+
+// KeyID returns the hex encoded sha256 digest of the DER encoded public key
+func KeyID(publicKey crypto.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal public key")
+	}
+	digest := sha256.Sum256(der)
+	return hex.EncodeToString(digest[:]), nil
+}
+
+// Sign wraps the statement into a DSSE envelope signed by the signer.
+// Without signer the envelope carries no signature.
+func Sign(statement Statement, signer crypto.Signer) (Envelope, error) {
+	payload, err := json.Marshal(statement)
+	if err != nil {
+		return Envelope{}, errors.Wrap(err, "failed to marshal provenance statement")
+	}
+	envelope := Envelope{PayloadType: PayloadType, Payload: base64.StdEncoding.EncodeToString(payload), Signatures: []Signature{}}
+	if signer == nil {
+		return envelope, nil
+	}
+	message := pae(PayloadType, payload)
+	var signature []byte
+	if _, ok := signer.Public().(ed25519.PublicKey); ok {
+		signature, err = signer.Sign(rand.Reader, message, crypto.Hash(0))
+	} else {
+		digest := sha256.Sum256(message)
+		signature, err = signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	}
+	if err != nil {
+		return Envelope{}, errors.Wrap(err, "failed to sign provenance statement")
+	}
+	keyID, err := KeyID(signer.Public())
+	if err != nil {
+		return Envelope{}, err
+	}
+	envelope.Signatures = append(envelope.Signatures, Signature{KeyID: keyID, Sig: base64.StdEncoding.EncodeToString(signature)})
+	return envelope, nil
+}
+
+// Verify checks that the envelope has a valid signature of the public key and returns the statement
+func Verify(envelope Envelope, publicKey crypto.PublicKey) (Statement, error) {
+	payload, err := base64.StdEncoding.DecodeString(envelope.Payload)
+	if err != nil {
+		return Statement{}, errors.Wrap(err, "failed to decode payload")
+	}
+	message := pae(envelope.PayloadType, payload)
+	digest := sha256.Sum256(message)
+	verified := false
+	for _, signature := range envelope.Signatures {
+		sig, err := base64.StdEncoding.DecodeString(signature.Sig)
+		if err != nil {
+			continue
+		}
+		switch key := publicKey.(type) {
+		case *ecdsa.PublicKey:
+			verified = ecdsa.VerifyASN1(key, digest[:], sig)
+		case ed25519.PublicKey:
+			verified = ed25519.Verify(key, message, sig)
+		case *rsa.PublicKey:
+			verified = rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], sig) == nil
+		default:
+			return Statement{}, fmt.Errorf("unsupported public key type %T", publicKey)
+		}
+		if verified {
+			break
+		}
+	}
+	if !verified {
+		return Statement{}, fmt.Errorf("no valid signature found")
+	}
+	var statement Statement
+	if err := json.Unmarshal(payload, &statement); err != nil {
+		return Statement{}, errors.Wrap(err, "failed to parse provenance statement")
+	}
+	return statement, nil
+}
+
+// pae is the pre-authentication encoding of DSSE which is signed instead of the plain payload
+func pae(payloadType string, payload []byte) []byte {
+	return []byte(fmt.Sprintf("DSSEv1 %d %s %d %s", len(payloadType), payloadType, len(payload), payload))
+}
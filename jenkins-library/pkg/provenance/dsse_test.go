@@ -0,0 +1,81 @@
+//go:build unit
+// +build unit
+
+package provenance
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	statement := New(Options{BuildTool: "npm", StepName: "npmExecuteScripts", Subjects: []ResourceDescriptor{{Name: "app.tgz", Digest: map[string]string{"sha256": "ab"}}}})
+
+	ecdsaKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	ecDER, _ := x509.MarshalECPrivateKey(ecdsaKey)
+	_, ed25519Key, _ := ed25519.GenerateKey(rand.Reader)
+	ed25519DER, _ := x509.MarshalPKCS8PrivateKey(ed25519Key)
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+
+	keys := map[string]*pem.Block{
+		"ecdsa":   {Type: "EC PRIVATE KEY", Bytes: ecDER},
+		"ed25519": {Type: "PRIVATE KEY", Bytes: ed25519DER},
+		"rsa":     {Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)},
+	}
+	for name, block := range keys {
+		t.Run(name, func(t *testing.T) {
+			signer, err := LoadSigner(pem.EncodeToMemory(block))
+			require.NoError(t, err)
+
+			envelope, err := Sign(statement, signer)
+			require.NoError(t, err)
+
+			assert.Equal(t, PayloadType, envelope.PayloadType)
+			require.Len(t, envelope.Signatures, 1)
+			keyID, _ := KeyID(signer.Public())
+			assert.Equal(t, keyID, envelope.Signatures[0].KeyID)
+			verified, err := Verify(envelope, signer.Public())
+			require.NoError(t, err)
+			assert.Equal(t, statement.Subject, verified.Subject)
+		})
+	}
+
+	t.Run("tampered payload", func(t *testing.T) {
+		signer, _ := LoadSigner(pem.EncodeToMemory(keys["ecdsa"]))
+		envelope, err := Sign(statement, signer)
+		require.NoError(t, err)
+		other, _ := Sign(New(Options{BuildTool: "npm", StepName: "npmExecuteScripts"}), nil)
+		envelope.Payload = other.Payload
+
+		_, err = Verify(envelope, signer.Public())
+
+		assert.EqualError(t, err, "no valid signature found")
+	})
+
+	t.Run("unsigned", func(t *testing.T) {
+		envelope, err := Sign(statement, nil)
+		require.NoError(t, err)
+
+		assert.Empty(t, envelope.Signatures)
+		_, err = Verify(envelope, ecdsaKey.Public().(crypto.PublicKey))
+		assert.EqualError(t, err, "no valid signature found")
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		_, err := LoadSigner([]byte("no key"))
+		assert.EqualError(t, err, "signing key is not PEM encoded")
+
+		_, err = LoadSigner(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte{}}))
+		assert.EqualError(t, err, "unsupported signing key type 'CERTIFICATE'")
+	})
+}
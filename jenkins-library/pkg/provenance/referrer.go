@@ -0,0 +1,56 @@
+package provenance
+
+import (
+	"encoding/json"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/pkg/errors"
+)
+
+const (
+	// ArtifactType is the type of the OCI artifact carrying the provenance, it is stored as config media type
+	ArtifactType = "application/vnd.in-toto+json"
+	// EnvelopeMediaType is the media type of the layer containing the DSSE envelope
+	EnvelopeMediaType = "application/vnd.dsse.envelope.v1+json"
+)
+
+// PublishReferrer pushes the envelope as OCI artifact referring to the image, which has to be given by digest, e.g. registry/image@sha256:....
+// Registries without referrers API are supported by the fallback tag of the OCI distribution specification.
+func PublishReferrer(envelope Envelope, image string, options ...remote.Option) (name.Digest, error) {
+	subject, err := name.NewDigest(image)
+	if err != nil {
+		return name.Digest{}, errors.Wrapf(err, "invalid image reference '%v'", image)
+	}
+	descriptor, err := remote.Head(subject, options...)
+	if err != nil {
+		return name.Digest{}, errors.Wrapf(err, "failed to resolve image '%v'", image)
+	}
+
+	content, err := json.Marshal(envelope)
+	if err != nil {
+		return name.Digest{}, errors.Wrap(err, "failed to marshal provenance envelope")
+	}
+	artifact, err := mutate.Append(empty.Image, mutate.Addendum{Layer: static.NewLayer(content, EnvelopeMediaType)})
+	if err != nil {
+		return name.Digest{}, errors.Wrap(err, "failed to create provenance artifact")
+	}
+	artifact = mutate.MediaType(artifact, types.OCIManifestSchema1)
+	artifact = mutate.ConfigMediaType(artifact, ArtifactType)
+	artifact = mutate.Subject(artifact, v1.Descriptor{MediaType: descriptor.MediaType, Size: descriptor.Size, Digest: descriptor.Digest}).(v1.Image)
+
+	digest, err := artifact.Digest()
+	if err != nil {
+		return name.Digest{}, errors.Wrap(err, "failed to compute digest of provenance artifact")
+	}
+	target := subject.Context().Digest(digest.String())
+	if err := remote.Write(target, artifact, options...); err != nil {
+		return name.Digest{}, errors.Wrapf(err, "failed to push provenance of image '%v'", image)
+	}
+	return target, nil
+}
@@ -0,0 +1,65 @@
+//go:build unit
+// +build unit
+
+package provenance
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublishReferrer(t *testing.T) {
+	for _, referrersSupport := range []bool{true, false} {
+		t.Run(map[bool]string{true: "referrers API", false: "fallback tag"}[referrersSupport], func(t *testing.T) {
+			server := httptest.NewServer(registry.New(registry.WithReferrersSupport(referrersSupport)))
+			defer server.Close()
+			host := strings.TrimPrefix(server.URL, "http://")
+
+			image, err := random.Image(64, 1)
+			require.NoError(t, err)
+			tag, _ := name.NewTag(host + "/acme/app:1.0.0")
+			require.NoError(t, remote.Write(tag, image))
+			digest, _ := image.Digest()
+			imageRef := host + "/acme/app@" + digest.String()
+			envelope, err := Sign(New(Options{BuildTool: "docker", StepName: "kanikoExecute"}), nil)
+			require.NoError(t, err)
+
+			artifact, err := PublishReferrer(envelope, imageRef)
+			require.NoError(t, err)
+
+			subject, _ := name.NewDigest(imageRef)
+			referrers, err := remote.Referrers(subject)
+			require.NoError(t, err)
+			index, err := referrers.IndexManifest()
+			require.NoError(t, err)
+			require.Len(t, index.Manifests, 1)
+			assert.Equal(t, artifact.DigestStr(), index.Manifests[0].Digest.String())
+			assert.Equal(t, ArtifactType, index.Manifests[0].ArtifactType)
+
+			pushed, err := remote.Image(artifact)
+			require.NoError(t, err)
+			layers, _ := pushed.Layers()
+			require.Len(t, layers, 1)
+			content, _ := layers[0].Uncompressed()
+			raw, _ := io.ReadAll(content)
+			var pushedEnvelope Envelope
+			require.NoError(t, json.Unmarshal(raw, &pushedEnvelope))
+			assert.Equal(t, envelope, pushedEnvelope)
+		})
+	}
+
+	t.Run("image without digest", func(t *testing.T) {
+		_, err := PublishReferrer(Envelope{}, "registry.example.org/acme/app:1.0.0")
+		assert.Contains(t, err.Error(), "invalid image reference 'registry.example.org/acme/app:1.0.0'")
+	})
+}
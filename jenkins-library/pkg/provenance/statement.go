@@ -0,0 +1,186 @@
+package provenance
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/pkg/errors"
+)
+
+const (
+	// StatementType is the type of an in-toto statement
+	StatementType = "https://in-toto.io/Statement/v1"
+	// PredicateType is the type of a SLSA v1 provenance predicate
+	PredicateType = "https://slsa.dev/provenance/v1"
+	// BuildTypePrefix identifies the build types of piper, the build tool and step are appended
+	BuildTypePrefix = "https://github.com/SAP/jenkins-library/buildTypes/"
+)
+
+// Statement is an in-toto statement attesting the provenance of its subjects
+type Statement struct {
+	Type          string               `json:"_type"`
+	Subject       []ResourceDescriptor `json:"subject"`
+	PredicateType string               `json:"predicateType"`
+	Predicate     Predicate            `json:"predicate"`
+}
+
+// ResourceDescriptor describes an artifact, an image or a dependency of the build
+type ResourceDescriptor struct {
+	Name   string            `json:"name,omitempty"`
+	URI    string            `json:"uri,omitempty"`
+	Digest map[string]string `json:"digest,omitempty"`
+}
+
+// Predicate is the SLSA v1 provenance predicate
+type Predicate struct {
+	BuildDefinition BuildDefinition `json:"buildDefinition"`
+	RunDetails      RunDetails      `json:"runDetails"`
+}
+
+// BuildDefinition describes the inputs of the build
+type BuildDefinition struct {
+	BuildType            string                 `json:"buildType"`
+	ExternalParameters   map[string]interface{} `json:"externalParameters"`
+	InternalParameters   map[string]interface{} `json:"internalParameters,omitempty"`
+	ResolvedDependencies []ResourceDescriptor   `json:"resolvedDependencies,omitempty"`
+}
+
+// RunDetails describes the build platform and the build run
+type RunDetails struct {
+	Builder  Builder       `json:"builder"`
+	Metadata BuildMetadata `json:"metadata"`
+}
+
+// Builder identifies the build platform
+type Builder struct {
+	ID string `json:"id"`
+}
+
+// BuildMetadata describes the build run
+type BuildMetadata struct {
+	InvocationID string     `json:"invocationId,omitempty"`
+	StartedOn    *time.Time `json:"startedOn,omitempty"`
+	FinishedOn   *time.Time `json:"finishedOn,omitempty"`
+}
+
+// Options defines the content of a provenance statement
+type Options struct {
+	// BuildTool and StepName define the build type, e.g. maven and mavenBuild
+	BuildTool string
+	StepName  string
+	// BuilderID identifies the build platform, e.g. the URL of the pipeline job
+	BuilderID    string
+	InvocationID string
+	StartedOn    time.Time
+	FinishedOn   time.Time
+	// ExternalParameters are the parameters of the step controlled by the user
+	ExternalParameters map[string]interface{}
+	// BuildSettingsInfo as created by buildsettings.CreateBuildSettingsInfo is recorded as internal parameter
+	BuildSettingsInfo string
+	// Source is the git commit which has been built
+	Source       ResourceDescriptor
+	Dependencies []ResourceDescriptor
+	Subjects     []ResourceDescriptor
+}
+
+// New creates the provenance statement of a build
+func New(options Options) Statement {
+	statement := Statement{
+		Type:          StatementType,
+		Subject:       options.Subjects,
+		PredicateType: PredicateType,
+		Predicate: Predicate{
+			BuildDefinition: BuildDefinition{
+				BuildType:          fmt.Sprintf("%v%v/%v/v1", BuildTypePrefix, options.BuildTool, options.StepName),
+				ExternalParameters: options.ExternalParameters,
+			},
+			RunDetails: RunDetails{
+				Builder:  Builder{ID: options.BuilderID},
+				Metadata: BuildMetadata{InvocationID: options.InvocationID},
+			},
+		},
+	}
+	if statement.Predicate.BuildDefinition.ExternalParameters == nil {
+		statement.Predicate.BuildDefinition.ExternalParameters = map[string]interface{}{}
+	}
+	if len(options.BuildSettingsInfo) > 0 {
+		var buildSettings map[string]interface{}
+		if err := json.Unmarshal([]byte(options.BuildSettingsInfo), &buildSettings); err == nil {
+			statement.Predicate.BuildDefinition.InternalParameters = map[string]interface{}{"buildSettingsInfo": buildSettings}
+		}
+	}
+	if len(options.Source.URI) > 0 {
+		statement.Predicate.BuildDefinition.ResolvedDependencies = append(statement.Predicate.BuildDefinition.ResolvedDependencies, options.Source)
+	}
+	statement.Predicate.BuildDefinition.ResolvedDependencies = append(statement.Predicate.BuildDefinition.ResolvedDependencies, options.Dependencies...)
+	if !options.StartedOn.IsZero() {
+		startedOn := options.StartedOn.UTC()
+		statement.Predicate.RunDetails.Metadata.StartedOn = &startedOn
+	}
+	if !options.FinishedOn.IsZero() {
+		finishedOn := options.FinishedOn.UTC()
+		statement.Predicate.RunDetails.Metadata.FinishedOn = &finishedOn
+	}
+	return statement
+}
+
+// GitSource returns the descriptor of a commit of a git repository in the SPDX download location format
+func GitSource(repositoryURL, commitID string) ResourceDescriptor {
+	if len(repositoryURL) == 0 || len(commitID) == 0 {
+		return ResourceDescriptor{}
+	}
+	uri := repositoryURL
+	if !strings.HasPrefix(uri, "git+") {
+		uri = "git+" + uri
+	}
+	return ResourceDescriptor{URI: uri + "@" + commitID, Digest: map[string]string{"gitCommit": commitID}}
+}
+
+type fileReader interface {
+	FileRead(path string) ([]byte, error)
+}
+
+// FileSubject returns the descriptor of a file with its sha256 digest
+func FileSubject(utils fileReader, path string) (ResourceDescriptor, error) {
+	content, err := utils.FileRead(path)
+	if err != nil {
+		return ResourceDescriptor{}, errors.Wrapf(err, "failed to read %v", path)
+	}
+	digest := sha256.Sum256(content)
+	return ResourceDescriptor{Name: filepath.ToSlash(path), Digest: map[string]string{"sha256": hex.EncodeToString(digest[:])}}, nil
+}
+
+// ImageSubject returns the descriptor of an image, the digest has the format <algorithm>:<hex>
+func ImageSubject(image, digest string) (ResourceDescriptor, error) {
+	algorithm, value, found := strings.Cut(strings.TrimSpace(digest), ":")
+	if !found || len(algorithm) == 0 || len(value) == 0 {
+		return ResourceDescriptor{}, fmt.Errorf("invalid digest '%v' of image '%v'", digest, image)
+	}
+	return ResourceDescriptor{Name: image, Digest: map[string]string{algorithm: value}}, nil
+}
+
+// BomDependencies returns the components of the CycloneDX BOMs as dependencies identified by their package URL
+func BomDependencies(bomFiles []string) ([]ResourceDescriptor, error) {
+	dependencies := []ResourceDescriptor{}
+	known := map[string]bool{}
+	for _, bomFile := range bomFiles {
+		bom, err := piperutils.GetBom(bomFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read BOM %v", bomFile)
+		}
+		for _, component := range bom.Components {
+			if len(component.Purl) == 0 || known[component.Purl] {
+				continue
+			}
+			known[component.Purl] = true
+			dependencies = append(dependencies, ResourceDescriptor{Name: component.Name, URI: component.Purl})
+		}
+	}
+	return dependencies, nil
+}
@@ -0,0 +1,84 @@
+//go:build unit
+// +build unit
+
+package provenance
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	startedOn := time.Date(2024, 5, 1, 10, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+
+	statement := New(Options{
+		BuildTool:          "maven",
+		StepName:           "mavenBuild",
+		BuilderID:          "https://jenkins.example.org/job/app/",
+		InvocationID:       "https://jenkins.example.org/job/app/42/",
+		StartedOn:          startedOn,
+		FinishedOn:         startedOn.Add(time.Minute),
+		ExternalParameters: map[string]interface{}{"profiles": []string{"release"}},
+		BuildSettingsInfo:  `{"mavenBuild":[{"profiles":["release"]}]}`,
+		Source:             GitSource("https://github.com/acme/app.git", "0123abc"),
+		Dependencies:       []ResourceDescriptor{{Name: "junit", URI: "pkg:maven/junit/junit@4.13.2"}},
+		Subjects:           []ResourceDescriptor{{Name: "target/app.jar", Digest: map[string]string{"sha256": "ab"}}},
+	})
+
+	assert.Equal(t, StatementType, statement.Type)
+	assert.Equal(t, PredicateType, statement.PredicateType)
+	assert.Equal(t, "https://github.com/SAP/jenkins-library/buildTypes/maven/mavenBuild/v1", statement.Predicate.BuildDefinition.BuildType)
+	assert.Equal(t, []ResourceDescriptor{
+		{URI: "git+https://github.com/acme/app.git@0123abc", Digest: map[string]string{"gitCommit": "0123abc"}},
+		{Name: "junit", URI: "pkg:maven/junit/junit@4.13.2"},
+	}, statement.Predicate.BuildDefinition.ResolvedDependencies)
+	assert.Contains(t, statement.Predicate.BuildDefinition.InternalParameters, "buildSettingsInfo")
+	assert.Equal(t, "https://jenkins.example.org/job/app/", statement.Predicate.RunDetails.Builder.ID)
+	assert.Equal(t, time.UTC, statement.Predicate.RunDetails.Metadata.StartedOn.Location())
+	assert.Equal(t, startedOn.Add(time.Minute).UTC(), *statement.Predicate.RunDetails.Metadata.FinishedOn)
+}
+
+func TestSubjects(t *testing.T) {
+	t.Run("file", func(t *testing.T) {
+		utils := &mock.FilesMock{}
+		utils.AddFile("target/app.jar", []byte("jar"))
+
+		subject, err := FileSubject(utils, "target/app.jar")
+
+		require.NoError(t, err)
+		assert.Equal(t, ResourceDescriptor{Name: "target/app.jar", Digest: map[string]string{"sha256": "0163f1eea7894350060624d315234d40c508ab251ba121714e234503045faadd"}}, subject)
+	})
+
+	t.Run("image", func(t *testing.T) {
+		subject, err := ImageSubject("registry.example.org/app", "sha256:0123")
+
+		require.NoError(t, err)
+		assert.Equal(t, ResourceDescriptor{Name: "registry.example.org/app", Digest: map[string]string{"sha256": "0123"}}, subject)
+
+		_, err = ImageSubject("registry.example.org/app", "0123")
+		assert.EqualError(t, err, "invalid digest '0123' of image 'registry.example.org/app'")
+	})
+}
+
+func TestBomDependencies(t *testing.T) {
+	bomFile := filepath.Join(t.TempDir(), "bom-maven.xml")
+	require.NoError(t, os.WriteFile(bomFile, []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<bom xmlns="http://cyclonedx.org/schema/bom/1.4">
+  <components>
+    <component><name>junit</name><purl>pkg:maven/junit/junit@4.13.2</purl></component>
+    <component><name>junit</name><purl>pkg:maven/junit/junit@4.13.2</purl></component>
+    <component><name>local</name></component>
+  </components>
+</bom>`), 0o644))
+
+	dependencies, err := BomDependencies([]string{bomFile})
+
+	require.NoError(t, err)
+	assert.Equal(t, []ResourceDescriptor{{Name: "junit", URI: "pkg:maven/junit/junit@4.13.2"}}, dependencies)
+}
@@ -2,6 +2,7 @@ package python
 
 import (
 	"fmt"
+	"path/filepath"
 	"regexp"
 
 	"github.com/SAP/jenkins-library/pkg/log"
@@ -67,6 +68,53 @@ func CreateBOM(
 	return nil
 }
 
+// CreateBOMFromLockFile creates the BOM of a Poetry or uv project from its lock file, which contains the exact versions
+// of the runtime dependencies independent of the packages installed in the virtual environment
+func CreateBOMFromLockFile(
+	executeFn func(executable string, params ...string) error,
+	readFileFn func(path string) ([]byte, error),
+	virtualEnv string,
+	projectManager string,
+	cycloneDxVersion string,
+	cycloneDxSchemaVersion string,
+) error {
+	if err := InstallCycloneDX(executeFn, virtualEnv, cycloneDxVersion); err != nil {
+		return fmt.Errorf("failed to install cyclonedx module: %w", err)
+	}
+	outputArgs := []string{
+		"--output-file", BOMFilename,
+		"--output-format", "XML",
+		"--spec-version", cycloneDxSchemaVersion,
+	}
+
+	var args []string
+	switch projectManager {
+	case ProjectManagerPoetry:
+		args = append([]string{"poetry", "--no-dev"}, outputArgs...)
+	case ProjectManagerUv:
+		// cyclonedx-py does not read uv.lock, the locked runtime dependencies are exported as requirements file instead
+		requirementsFile := filepath.Join(virtualEnv, "requirements-uv.txt")
+		if err := executeFn(getBinary(virtualEnv, "uv"), "export", "--locked", "--no-dev", "--no-hashes", "--no-emit-project", "--format", "requirements-txt", "--output-file", requirementsFile); err != nil {
+			return fmt.Errorf("failed to export locked dependencies: %w", err)
+		}
+		args = append([]string{"requirements", requirementsFile}, outputArgs...)
+		if pyprojectHasMetadata(readFileFn, "pyproject.toml") {
+			args = append(args, "--pyproject", "pyproject.toml")
+		}
+	default:
+		return fmt.Errorf("creating the BOM from the lock file is not supported for %s", projectManager)
+	}
+
+	log.Entry().Debugf("creating BOM from %s lock file", projectManager)
+	if err := executeFn(getBinary(virtualEnv, "cyclonedx-py"), args...); err != nil {
+		return fmt.Errorf("failed to create BOM: %w", err)
+	}
+	if err := addPurlToRootComponent(BOMFilename); err != nil {
+		log.Entry().Warnf("failed to add purl to root component: %v", err)
+	}
+	return nil
+}
+
 // addPurlToRootComponent adds a purl element to the root component in the BOM
 // This is needed because cyclonedx-py doesn't generate purl when using --pyproject flag
 // Uses piperutils.UpdatePurl which leverages the official CycloneDX Go library
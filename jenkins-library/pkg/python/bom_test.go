@@ -108,6 +108,58 @@ build-backend = "setuptools.build_meta"
 		"--spec-version", "1.4"}, mockRunner.Calls[2].Params)
 }
 
+func TestCreateBOMFromLockFile(t *testing.T) {
+	t.Run("poetry", func(t *testing.T) {
+		mockRunner := mock.ExecMockRunner{}
+		mockFiles := mock.FilesMock{}
+
+		err := CreateBOMFromLockFile(mockRunner.RunExecutable, mockFiles.ReadFile, ".venv", ProjectManagerPoetry, "1.2.3", "1.4")
+
+		assert.NoError(t, err)
+		assert.Len(t, mockRunner.Calls, 2)
+		assert.Equal(t, ".venv/bin/cyclonedx-py", mockRunner.Calls[1].Exec)
+		assert.Equal(t, []string{
+			"poetry", "--no-dev",
+			"--output-file", "bom-pip.xml",
+			"--output-format", "XML",
+			"--spec-version", "1.4",
+		}, mockRunner.Calls[1].Params)
+	})
+
+	t.Run("uv", func(t *testing.T) {
+		mockRunner := mock.ExecMockRunner{}
+		mockFiles := mock.FilesMock{}
+		mockFiles.AddFile("pyproject.toml", []byte("[project]\nname = \"app\"\nversion = \"1.0.0\"\n"))
+
+		err := CreateBOMFromLockFile(mockRunner.RunExecutable, mockFiles.ReadFile, ".venv", ProjectManagerUv, "1.2.3", "1.4")
+
+		assert.NoError(t, err)
+		assert.Len(t, mockRunner.Calls, 3)
+		assert.Equal(t, ".venv/bin/uv", mockRunner.Calls[1].Exec)
+		assert.Equal(t, []string{
+			"export", "--locked", "--no-dev", "--no-hashes", "--no-emit-project",
+			"--format", "requirements-txt",
+			"--output-file", ".venv/requirements-uv.txt",
+		}, mockRunner.Calls[1].Params)
+		assert.Equal(t, []string{
+			"requirements", ".venv/requirements-uv.txt",
+			"--output-file", "bom-pip.xml",
+			"--output-format", "XML",
+			"--spec-version", "1.4",
+			"--pyproject", "pyproject.toml",
+		}, mockRunner.Calls[2].Params)
+	})
+
+	t.Run("pip is not supported", func(t *testing.T) {
+		mockRunner := mock.ExecMockRunner{}
+		mockFiles := mock.FilesMock{}
+
+		err := CreateBOMFromLockFile(mockRunner.RunExecutable, mockFiles.ReadFile, ".venv", ProjectManagerPip, "1.2.3", "1.4")
+
+		assert.EqualError(t, err, "creating the BOM from the lock file is not supported for pip")
+	})
+}
+
 func TestPyprojectHasMetadata(t *testing.T) {
 	t.Run("file does not exist", func(t *testing.T) {
 		mockFiles := mock.FilesMock{}
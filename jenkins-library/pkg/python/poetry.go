@@ -0,0 +1,62 @@
+package python
+
+import (
+	"fmt"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+)
+
+// poetryRepository is the name of the repository the packages are published to
+const poetryRepository = "piper"
+
+// BuildWithPoetry installs the locked dependencies into the virtual environment and builds the wheel and the source distribution.
+// Poetry uses the virtual environment defined by VIRTUAL_ENV.
+func BuildWithPoetry(
+	executeFn func(executable string, params ...string) error,
+	virtualEnv string,
+	buildArgs []string,
+) error {
+	if err := InstallPoetry(executeFn, virtualEnv); err != nil {
+		return fmt.Errorf("failed to install poetry: %w", err)
+	}
+	poetry := getBinary(virtualEnv, "poetry")
+
+	// fails if the lock file is not consistent with pyproject.toml
+	log.Entry().Debug("installing project dependencies")
+	if err := executeFn(poetry, "install", "--no-interaction"); err != nil {
+		return fmt.Errorf("failed to install project dependencies: %w", err)
+	}
+
+	log.Entry().Debug("building project")
+	return executeFn(poetry, append([]string{"build", "--no-interaction"}, buildArgs...)...)
+}
+
+// PublishWithPoetry publishes the wheel and the source distribution built by Poetry
+func PublishWithPoetry(
+	executeFn func(executable string, params ...string) error,
+	virtualEnv string,
+	repository string,
+	username string,
+	password string,
+) error {
+	poetry := getBinary(virtualEnv, "poetry")
+	if err := executeFn(poetry, "config", fmt.Sprintf("repositories.%s", poetryRepository), repository); err != nil {
+		return fmt.Errorf("failed to configure repository: %w", err)
+	}
+	return executeFn(
+		poetry,
+		"publish",
+		"--repository", poetryRepository,
+		"--username", username,
+		"--password", password,
+		"--no-interaction",
+	)
+}
+
+func InstallPoetry(
+	executeFn func(executable string, params ...string) error,
+	virtualEnv string,
+) error {
+	log.Entry().Debug("installing poetry")
+	return install(executeFn, virtualEnv, "poetry", "", nil)
+}
@@ -0,0 +1,53 @@
+//go:build unit
+// +build unit
+
+package python
+
+import (
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildWithPoetry(t *testing.T) {
+	// init
+	mockRunner := mock.ExecMockRunner{}
+
+	// test
+	err := BuildWithPoetry(mockRunner.RunExecutable, ".venv", []string{"--format", "wheel"})
+
+	// assert
+	assert.NoError(t, err)
+	assert.Len(t, mockRunner.Calls, 3)
+	assert.Equal(t, ".venv/bin/pip", mockRunner.Calls[0].Exec)
+	assert.Equal(t, []string{
+		"install",
+		"--upgrade",
+		"--root-user-action=ignore",
+		"poetry"}, mockRunner.Calls[0].Params)
+	assert.Equal(t, ".venv/bin/poetry", mockRunner.Calls[1].Exec)
+	assert.Equal(t, []string{"install", "--no-interaction"}, mockRunner.Calls[1].Params)
+	assert.Equal(t, ".venv/bin/poetry", mockRunner.Calls[2].Exec)
+	assert.Equal(t, []string{"build", "--no-interaction", "--format", "wheel"}, mockRunner.Calls[2].Params)
+}
+
+func TestPublishWithPoetry(t *testing.T) {
+	// init
+	mockRunner := mock.ExecMockRunner{}
+
+	// test
+	err := PublishWithPoetry(mockRunner.RunExecutable, ".venv", "https://my.repository.local/simple", "user", "password")
+
+	// assert
+	assert.NoError(t, err)
+	assert.Len(t, mockRunner.Calls, 2)
+	assert.Equal(t, ".venv/bin/poetry", mockRunner.Calls[0].Exec)
+	assert.Equal(t, []string{"config", "repositories.piper", "https://my.repository.local/simple"}, mockRunner.Calls[0].Params)
+	assert.Equal(t, []string{
+		"publish",
+		"--repository", "piper",
+		"--username", "user",
+		"--password", "password",
+		"--no-interaction"}, mockRunner.Calls[1].Params)
+}
@@ -0,0 +1,50 @@
+package python
+
+import (
+	"regexp"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+)
+
+const (
+	// ProjectManagerPip builds pyproject.toml projects with pip and the build module
+	ProjectManagerPip = "pip"
+	// ProjectManagerPoetry builds projects managed by Poetry, see https://python-poetry.org
+	ProjectManagerPoetry = "poetry"
+	// ProjectManagerUv builds projects managed by uv, see https://docs.astral.sh/uv
+	ProjectManagerUv = "uv"
+
+	PoetryLockFile = "poetry.lock"
+	UvLockFile     = "uv.lock"
+)
+
+var (
+	poetrySectionRegex = regexp.MustCompile(`(?m)^\s*\[tool\.poetry(\.[^\]]+)?\]`)
+	uvSectionRegex     = regexp.MustCompile(`(?m)^\s*\[tool\.uv(\.[^\]]+)?\]`)
+)
+
+// DetectProjectManager returns the project manager of a pyproject.toml project, identified by its lock file
+// or by its tool configuration in pyproject.toml
+func DetectProjectManager(
+	existsFn func(path string) (bool, error),
+	readFileFn func(path string) ([]byte, error),
+	pyprojectFile string,
+) string {
+	if exists, _ := existsFn(PoetryLockFile); exists {
+		return ProjectManagerPoetry
+	}
+	if exists, _ := existsFn(UvLockFile); exists {
+		return ProjectManagerUv
+	}
+	if content, err := readFileFn(pyprojectFile); err == nil {
+		if poetrySectionRegex.Match(content) {
+			log.Entry().Warnf("no %s found, dependencies are resolved during the build", PoetryLockFile)
+			return ProjectManagerPoetry
+		}
+		if uvSectionRegex.Match(content) {
+			log.Entry().Warnf("no %s found, dependencies are resolved during the build", UvLockFile)
+			return ProjectManagerUv
+		}
+	}
+	return ProjectManagerPip
+}
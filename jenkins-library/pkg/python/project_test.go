@@ -0,0 +1,50 @@
+//go:build unit
+// +build unit
+
+package python
+
+import (
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectProjectManager(t *testing.T) {
+	t.Run("poetry lock file", func(t *testing.T) {
+		mockFiles := mock.FilesMock{}
+		mockFiles.AddFile("pyproject.toml", []byte("[project]\nname = \"app\"\n"))
+		mockFiles.AddFile("poetry.lock", []byte(""))
+
+		assert.Equal(t, ProjectManagerPoetry, DetectProjectManager(mockFiles.FileExists, mockFiles.ReadFile, "pyproject.toml"))
+	})
+
+	t.Run("uv lock file", func(t *testing.T) {
+		mockFiles := mock.FilesMock{}
+		mockFiles.AddFile("pyproject.toml", []byte("[project]\nname = \"app\"\n"))
+		mockFiles.AddFile("uv.lock", []byte(""))
+
+		assert.Equal(t, ProjectManagerUv, DetectProjectManager(mockFiles.FileExists, mockFiles.ReadFile, "pyproject.toml"))
+	})
+
+	t.Run("poetry configuration without lock file", func(t *testing.T) {
+		mockFiles := mock.FilesMock{}
+		mockFiles.AddFile("pyproject.toml", []byte("[tool.poetry]\nname = \"app\"\nversion = \"1.0.0\"\n\n[tool.poetry.dependencies]\npython = \"^3.11\"\n"))
+
+		assert.Equal(t, ProjectManagerPoetry, DetectProjectManager(mockFiles.FileExists, mockFiles.ReadFile, "pyproject.toml"))
+	})
+
+	t.Run("uv configuration without lock file", func(t *testing.T) {
+		mockFiles := mock.FilesMock{}
+		mockFiles.AddFile("pyproject.toml", []byte("[project]\nname = \"app\"\n\n[tool.uv]\ndev-dependencies = []\n"))
+
+		assert.Equal(t, ProjectManagerUv, DetectProjectManager(mockFiles.FileExists, mockFiles.ReadFile, "pyproject.toml"))
+	})
+
+	t.Run("pip", func(t *testing.T) {
+		mockFiles := mock.FilesMock{}
+		mockFiles.AddFile("pyproject.toml", []byte("[project]\nname = \"app\"\n\n[tool.setuptools]\npackages = [\"app\"]\n"))
+
+		assert.Equal(t, ProjectManagerPip, DetectProjectManager(mockFiles.FileExists, mockFiles.ReadFile, "pyproject.toml"))
+	})
+}
@@ -0,0 +1,55 @@
+package python
+
+import (
+	"fmt"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+)
+
+// BuildWithUv installs the locked dependencies into the virtual environment and builds the wheel and the source distribution.
+// uv uses the virtual environment defined by VIRTUAL_ENV.
+func BuildWithUv(
+	executeFn func(executable string, params ...string) error,
+	virtualEnv string,
+	buildArgs []string,
+) error {
+	if err := InstallUv(executeFn, virtualEnv); err != nil {
+		return fmt.Errorf("failed to install uv: %w", err)
+	}
+	uv := getBinary(virtualEnv, "uv")
+
+	// fails if the lock file is not consistent with pyproject.toml, --inexact keeps uv itself in the virtual environment
+	log.Entry().Debug("installing project dependencies")
+	if err := executeFn(uv, "sync", "--locked", "--inexact", "--active"); err != nil {
+		return fmt.Errorf("failed to install project dependencies: %w", err)
+	}
+
+	log.Entry().Debug("building project")
+	return executeFn(uv, append([]string{"build"}, buildArgs...)...)
+}
+
+// PublishWithUv publishes the wheel and the source distribution built by uv
+func PublishWithUv(
+	executeFn func(executable string, params ...string) error,
+	virtualEnv string,
+	repository string,
+	username string,
+	password string,
+) error {
+	return executeFn(
+		getBinary(virtualEnv, "uv"),
+		"publish",
+		"--publish-url", repository,
+		"--username", username,
+		"--password", password,
+		"dist/*",
+	)
+}
+
+func InstallUv(
+	executeFn func(executable string, params ...string) error,
+	virtualEnv string,
+) error {
+	log.Entry().Debug("installing uv")
+	return install(executeFn, virtualEnv, "uv", "", nil)
+}
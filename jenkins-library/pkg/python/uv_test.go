@@ -0,0 +1,51 @@
+//go:build unit
+// +build unit
+
+package python
+
+import (
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildWithUv(t *testing.T) {
+	// init
+	mockRunner := mock.ExecMockRunner{}
+
+	// test
+	err := BuildWithUv(mockRunner.RunExecutable, ".venv", nil)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Len(t, mockRunner.Calls, 3)
+	assert.Equal(t, ".venv/bin/pip", mockRunner.Calls[0].Exec)
+	assert.Equal(t, []string{
+		"install",
+		"--upgrade",
+		"--root-user-action=ignore",
+		"uv"}, mockRunner.Calls[0].Params)
+	assert.Equal(t, ".venv/bin/uv", mockRunner.Calls[1].Exec)
+	assert.Equal(t, []string{"sync", "--locked", "--inexact", "--active"}, mockRunner.Calls[1].Params)
+	assert.Equal(t, ".venv/bin/uv", mockRunner.Calls[2].Exec)
+	assert.Equal(t, []string{"build"}, mockRunner.Calls[2].Params)
+}
+
+func TestPublishWithUv(t *testing.T) {
+	// init
+	mockRunner := mock.ExecMockRunner{}
+
+	// test
+	err := PublishWithUv(mockRunner.RunExecutable, ".venv", "https://my.repository.local/legacy/", "user", "password")
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, ".venv/bin/uv", mockRunner.Calls[0].Exec)
+	assert.Equal(t, []string{
+		"publish",
+		"--publish-url", "https://my.repository.local/legacy/",
+		"--username", "user",
+		"--password", "password",
+		"dist/*"}, mockRunner.Calls[0].Params)
+}
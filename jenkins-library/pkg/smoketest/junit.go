@@ -0,0 +1,66 @@
+package smoketest
+
+import (
+	"encoding/xml"
+	"fmt"
+	"time"
+)
+
+type junitTestSuites struct {
+	XMLName   xml.Name         `xml:"testsuites"`
+	TestSuite []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Content string `xml:",chardata"`
+}
+
+// JUnitReport renders the results as JUnit XML which can be consumed by the test views of the orchestrators
+func JUnitReport(suiteName string, results []Result, timestamp time.Time) ([]byte, error) {
+	suite := junitTestSuite{Name: suiteName, Tests: len(results), Timestamp: timestamp.UTC().Format("2006-01-02T15:04:05")}
+	var total time.Duration
+	for _, r := range results {
+		total += r.Duration
+		testCase := junitTestCase{
+			Name:      r.Name,
+			ClassName: suiteName,
+			Time:      seconds(r.Duration),
+			SystemOut: fmt.Sprintf("%s (%d attempt(s))", r.URL, r.Attempts),
+		}
+		if len(r.Failure) > 0 {
+			suite.Failures++
+			testCase.Failure = &junitFailure{Message: r.Failure, Type: "SmokeTestFailure", Content: r.Failure}
+		}
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+	suite.Time = seconds(total)
+
+	content, err := xml.MarshalIndent(junitTestSuites{TestSuite: []junitTestSuite{suite}}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), content...), nil
+}
+
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
@@ -0,0 +1,315 @@
+package smoketest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/pkg/errors"
+)
+
+// Check describes a single HTTP check
+type Check struct {
+	Name string `json:"name"`
+	// URL is either an absolute URL or a path which is resolved against every base URL
+	URL            string            `json:"url"`
+	Method         string            `json:"method,omitempty"`
+	Headers        map[string]string `json:"headers,omitempty"`
+	Body           string            `json:"body,omitempty"`
+	ExpectedStatus []int             `json:"expectedStatus,omitempty"`
+	JSONAssertions []JSONAssertion   `json:"jsonAssertions,omitempty"`
+	// MaxLatency is the latency budget of the request in milliseconds
+	MaxLatency int  `json:"maxLatency,omitempty"`
+	Retries    *int `json:"retries,omitempty"`
+	SkipAuth   bool `json:"skipAuth,omitempty"`
+}
+
+// JSONAssertion checks a value of a JSON response body addressed by a path like $.status or $.items[0].name
+type JSONAssertion struct {
+	Path     string      `json:"path"`
+	Equals   interface{} `json:"equals,omitempty"`
+	Contains string      `json:"contains,omitempty"`
+	Exists   *bool       `json:"exists,omitempty"`
+}
+
+// Result is the outcome of a check
+type Result struct {
+	Name     string
+	URL      string
+	Attempts int
+	Duration time.Duration
+	// Failure is empty if the check succeeded
+	Failure string
+}
+
+// Authenticator sets an authorization header on a request, e.g. xsuaa.XSUAA
+type Authenticator interface {
+	SetAuthHeaderIfNotPresent(header *http.Header) error
+}
+
+// Runner executes checks with retries and exponential backoff
+type Runner struct {
+	Client piperhttp.Sender
+	Auth   Authenticator
+	// Retries is the default number of retries of a failing check
+	Retries int
+	// RetryInterval is the initial wait time between two attempts which is doubled after every attempt
+	RetryInterval time.Duration
+	// MaxRetryInterval limits the wait time between two attempts
+	MaxRetryInterval time.Duration
+	Sleep            func(time.Duration)
+}
+
+// ParseChecks converts checks provided as generic configuration maps
+func ParseChecks(config []map[string]interface{}) ([]Check, error) {
+	checks := []Check{}
+	raw, err := json.Marshal(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read checks")
+	}
+	if err := json.Unmarshal(raw, &checks); err != nil {
+		return nil, errors.Wrap(err, "invalid check definition")
+	}
+	for i, c := range checks {
+		if len(c.URL) == 0 {
+			return nil, fmt.Errorf("check %d has no url", i+1)
+		}
+	}
+	return checks, nil
+}
+
+// ExpandChecks resolves relative check URLs against all base URLs. Base URLs without scheme (e.g. Cloud Foundry routes) use https.
+func ExpandChecks(checks []Check, baseURLs []string) ([]Check, error) {
+	expanded := []Check{}
+	for _, c := range checks {
+		if strings.HasPrefix(c.URL, "http://") || strings.HasPrefix(c.URL, "https://") {
+			expanded = append(expanded, c)
+			continue
+		}
+		if len(baseURLs) == 0 {
+			return nil, fmt.Errorf("check '%s' uses the relative url '%s' but no base url is available", c.Name, c.URL)
+		}
+		for _, base := range baseURLs {
+			if !strings.Contains(base, "://") {
+				base = "https://" + base
+			}
+			check := c
+			check.URL = strings.TrimRight(base, "/") + "/" + strings.TrimLeft(c.URL, "/")
+			if len(baseURLs) > 1 {
+				check.Name = fmt.Sprintf("%s (%s)", c.displayName(), base)
+			}
+			expanded = append(expanded, check)
+		}
+	}
+	return expanded, nil
+}
+
+func (c Check) displayName() string {
+	if len(c.Name) > 0 {
+		return c.Name
+	}
+	method := c.Method
+	if len(method) == 0 {
+		method = http.MethodGet
+	}
+	return fmt.Sprintf("%s %s", method, c.URL)
+}
+
+// Run executes all checks and returns their results
+func (r *Runner) Run(checks []Check) []Result {
+	results := []Result{}
+	for _, c := range checks {
+		results = append(results, r.runCheck(c))
+	}
+	return results
+}
+
+func (r *Runner) runCheck(c Check) Result {
+	retries := r.Retries
+	if c.Retries != nil {
+		retries = *c.Retries
+	}
+	wait := r.RetryInterval
+
+	result := Result{Name: c.displayName(), URL: c.URL}
+	for {
+		result.Attempts++
+		duration, err := r.execute(c)
+		result.Duration = duration
+		if err == nil {
+			result.Failure = ""
+			log.Entry().Infof("check '%s' succeeded after %d attempt(s) in %v", result.Name, result.Attempts, duration)
+			return result
+		}
+		result.Failure = err.Error()
+		log.Entry().Warnf("check '%s' failed (attempt %d of %d): %v", result.Name, result.Attempts, retries+1, err)
+		if result.Attempts > retries {
+			return result
+		}
+		if r.Sleep != nil {
+			r.Sleep(wait)
+		}
+		wait *= 2
+		if r.MaxRetryInterval > 0 && wait > r.MaxRetryInterval {
+			wait = r.MaxRetryInterval
+		}
+	}
+}
+
+func (r *Runner) execute(c Check) (time.Duration, error) {
+	method := c.Method
+	if len(method) == 0 {
+		method = http.MethodGet
+	}
+	header := http.Header{}
+	for k, v := range c.Headers {
+		header.Set(k, v)
+	}
+	if r.Auth != nil && !c.SkipAuth {
+		if err := r.Auth.SetAuthHeaderIfNotPresent(&header); err != nil {
+			return 0, errors.Wrap(err, "failed to retrieve access token")
+		}
+	}
+	var body io.Reader
+	if len(c.Body) > 0 {
+		body = bytes.NewBufferString(c.Body)
+	}
+
+	start := time.Now()
+	response, err := r.Client.SendRequest(method, c.URL, body, header, nil)
+	if response == nil || response.StatusCode == 0 {
+		if err == nil {
+			err = fmt.Errorf("no response received")
+		}
+		return time.Since(start), err
+	}
+	defer response.Body.Close()
+	content, err := io.ReadAll(response.Body)
+	duration := time.Since(start)
+	if err != nil {
+		return duration, errors.Wrap(err, "failed to read response body")
+	}
+
+	expected := c.ExpectedStatus
+	if len(expected) == 0 {
+		expected = []int{http.StatusOK}
+	}
+	if !containsStatus(expected, response.StatusCode) {
+		return duration, fmt.Errorf("unexpected status code %d, expected %v", response.StatusCode, expected)
+	}
+	if c.MaxLatency > 0 && duration > time.Duration(c.MaxLatency)*time.Millisecond {
+		return duration, fmt.Errorf("latency of %dms exceeds budget of %dms", duration.Milliseconds(), c.MaxLatency)
+	}
+	if len(c.JSONAssertions) > 0 {
+		var document interface{}
+		if err := json.Unmarshal(content, &document); err != nil {
+			return duration, errors.Wrap(err, "response body is no valid JSON")
+		}
+		for _, a := range c.JSONAssertions {
+			if err := a.check(document); err != nil {
+				return duration, err
+			}
+		}
+	}
+	return duration, nil
+}
+
+func containsStatus(codes []int, code int) bool {
+	for _, c := range codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+func (a JSONAssertion) check(document interface{}) error {
+	value, found, err := lookupJSONPath(document, a.Path)
+	if err != nil {
+		return err
+	}
+	if a.Exists != nil {
+		if *a.Exists != found {
+			return fmt.Errorf("expected '%s' to exist: %v", a.Path, *a.Exists)
+		}
+		if !found {
+			return nil
+		}
+	}
+	if !found {
+		return fmt.Errorf("'%s' not found in response", a.Path)
+	}
+	if a.Equals != nil && !jsonEqual(value, a.Equals) {
+		return fmt.Errorf("expected '%s' to equal '%v' but got '%v'", a.Path, a.Equals, value)
+	}
+	if len(a.Contains) > 0 && !strings.Contains(fmt.Sprint(value), a.Contains) {
+		return fmt.Errorf("expected '%s' to contain '%s' but got '%v'", a.Path, a.Contains, value)
+	}
+	return nil
+}
+
+func jsonEqual(actual, expected interface{}) bool {
+	// normalize the expected value which may come from YAML configuration (e.g. int instead of float64)
+	raw, err := json.Marshal(expected)
+	if err != nil {
+		return false
+	}
+	var normalized interface{}
+	if err := json.Unmarshal(raw, &normalized); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(actual, normalized)
+}
+
+// lookupJSONPath resolves simple JSON paths consisting of keys and array indices, e.g. $.items[0].status
+func lookupJSONPath(document interface{}, path string) (interface{}, bool, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, false, fmt.Errorf("invalid JSON path '%s', path has to start with '$'", path)
+	}
+	current := document
+	rest := path[1:]
+	for len(rest) > 0 {
+		switch rest[0] {
+		case '.':
+			end := strings.IndexAny(rest[1:], ".[")
+			key := rest[1:]
+			if end >= 0 {
+				key = rest[1 : end+1]
+			}
+			rest = rest[1+len(key):]
+			object, ok := current.(map[string]interface{})
+			if !ok {
+				return nil, false, nil
+			}
+			if current, ok = object[key]; !ok {
+				return nil, false, nil
+			}
+		case '[':
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, false, fmt.Errorf("invalid JSON path '%s'", path)
+			}
+			index, err := strconv.Atoi(rest[1:end])
+			if err != nil {
+				return nil, false, fmt.Errorf("invalid JSON path '%s', only numeric indices are supported", path)
+			}
+			rest = rest[end+1:]
+			array, ok := current.([]interface{})
+			if !ok || index < 0 || index >= len(array) {
+				return nil, false, nil
+			}
+			current = array[index]
+		default:
+			return nil, false, fmt.Errorf("invalid JSON path '%s'", path)
+		}
+	}
+	return current, true, nil
+}
@@ -0,0 +1,192 @@
+//go:build unit
+// +build unit
+
+package smoketest
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/stretchr/testify/assert"
+)
+
+type authMock struct{}
+
+func (a *authMock) SetAuthHeaderIfNotPresent(header *http.Header) error {
+	if len(header.Get("Authorization")) == 0 {
+		header.Set("Authorization", "bearer token")
+	}
+	return nil
+}
+
+func newTestRunner() (*Runner, *[]time.Duration) {
+	client := &piperhttp.Client{}
+	client.SetOptions(piperhttp.ClientOptions{MaxRetries: -1, UseDefaultTransport: true})
+	waits := []time.Duration{}
+	return &Runner{
+		Client:           client,
+		Retries:          3,
+		RetryInterval:    time.Second,
+		MaxRetryInterval: 3 * time.Second,
+		Sleep:            func(d time.Duration) { waits = append(waits, d) },
+	}, &waits
+}
+
+func intPtr(i int) *int { return &i }
+
+func TestRun(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Path {
+		case "/health":
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"status":"UP","components":[{"name":"db","status":"UP","replicas":2}]}`)
+		case "/flaky":
+			if requests < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			fmt.Fprint(w, "ok")
+		case "/secure":
+			if r.Header.Get("Authorization") != "bearer token" {
+				w.WriteHeader(http.StatusUnauthorized)
+			}
+		case "/slow":
+			time.Sleep(20 * time.Millisecond)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	t.Run("json assertions", func(t *testing.T) {
+		runner, _ := newTestRunner()
+		exists := false
+		results := runner.Run([]Check{{
+			Name: "health",
+			URL:  server.URL + "/health",
+			JSONAssertions: []JSONAssertion{
+				{Path: "$.status", Equals: "UP"},
+				{Path: "$.components[0].replicas", Equals: 2},
+				{Path: "$.components[0].name", Contains: "d"},
+				{Path: "$.components[1]", Exists: &exists},
+			},
+		}})
+		assert.Equal(t, []Result{{Name: "health", URL: server.URL + "/health", Attempts: 1, Duration: results[0].Duration}}, results)
+	})
+
+	t.Run("failed json assertion is retried", func(t *testing.T) {
+		runner, waits := newTestRunner()
+		results := runner.Run([]Check{{URL: server.URL + "/health", JSONAssertions: []JSONAssertion{{Path: "$.status", Equals: "DOWN"}}}})
+		assert.Equal(t, 4, results[0].Attempts)
+		assert.Equal(t, "expected '$.status' to equal 'DOWN' but got 'UP'", results[0].Failure)
+		assert.Equal(t, "GET "+server.URL+"/health", results[0].Name)
+		assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 3 * time.Second}, *waits)
+	})
+
+	t.Run("succeeds after retry", func(t *testing.T) {
+		requests = 0
+		runner, waits := newTestRunner()
+		results := runner.Run([]Check{{Name: "flaky", URL: server.URL + "/flaky"}})
+		assert.Empty(t, results[0].Failure)
+		assert.Equal(t, 3, results[0].Attempts)
+		assert.Len(t, *waits, 2)
+	})
+
+	t.Run("expected status and authentication", func(t *testing.T) {
+		runner, _ := newTestRunner()
+		runner.Auth = &authMock{}
+		results := runner.Run([]Check{
+			{Name: "authenticated", URL: server.URL + "/secure"},
+			{Name: "unauthenticated", URL: server.URL + "/secure", SkipAuth: true, ExpectedStatus: []int{401}},
+			{Name: "missing", URL: server.URL + "/missing", Retries: intPtr(0)},
+		})
+		assert.Empty(t, results[0].Failure)
+		assert.Empty(t, results[1].Failure)
+		assert.Equal(t, "unexpected status code 404, expected [200]", results[2].Failure)
+		assert.Equal(t, 1, results[2].Attempts)
+	})
+
+	t.Run("latency budget", func(t *testing.T) {
+		runner, _ := newTestRunner()
+		results := runner.Run([]Check{{Name: "slow", URL: server.URL + "/slow", MaxLatency: 1, Retries: intPtr(0)}})
+		assert.Contains(t, results[0].Failure, "exceeds budget of 1ms")
+	})
+
+	t.Run("connection error", func(t *testing.T) {
+		runner, _ := newTestRunner()
+		results := runner.Run([]Check{{Name: "down", URL: "http://127.0.0.1:1/health", Retries: intPtr(1)}})
+		assert.Equal(t, 2, results[0].Attempts)
+		assert.Contains(t, results[0].Failure, "HTTP GET request to http://127.0.0.1:1/health failed")
+	})
+}
+
+func TestParseAndExpandChecks(t *testing.T) {
+	checks, err := ParseChecks([]map[string]interface{}{
+		{"name": "health", "url": "/health", "expectedStatus": []interface{}{200, 204}, "retries": 1},
+		{"url": "https://other.example.com/ping"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{200, 204}, checks[0].ExpectedStatus)
+	assert.Equal(t, 1, *checks[0].Retries)
+
+	t.Run("multiple base urls", func(t *testing.T) {
+		expanded, err := ExpandChecks(checks, []string{"app.cfapps.example.com", "http://localhost:8080/"})
+		assert.NoError(t, err)
+		urls := []string{}
+		for _, c := range expanded {
+			urls = append(urls, c.URL)
+		}
+		assert.Equal(t, []string{"https://app.cfapps.example.com/health", "http://localhost:8080/health", "https://other.example.com/ping"}, urls)
+		assert.Equal(t, "health (https://app.cfapps.example.com)", expanded[0].Name)
+	})
+
+	t.Run("relative url without base url", func(t *testing.T) {
+		_, err := ExpandChecks(checks, nil)
+		assert.EqualError(t, err, "check 'health' uses the relative url '/health' but no base url is available")
+	})
+
+	t.Run("missing url", func(t *testing.T) {
+		_, err := ParseChecks([]map[string]interface{}{{"name": "x"}})
+		assert.EqualError(t, err, "check 1 has no url")
+	})
+}
+
+func TestLookupJSONPath(t *testing.T) {
+	document := map[string]interface{}{"a": map[string]interface{}{"b": []interface{}{"x", "y"}}}
+
+	value, found, err := lookupJSONPath(document, "$.a.b[1]")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "y", value)
+
+	_, found, err = lookupJSONPath(document, "$.a.c")
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	_, _, err = lookupJSONPath(document, "a.b")
+	assert.EqualError(t, err, "invalid JSON path 'a.b', path has to start with '$'")
+
+	_, _, err = lookupJSONPath(document, "$.a.b[*]")
+	assert.EqualError(t, err, "invalid JSON path '$.a.b[*]', only numeric indices are supported")
+}
+
+func TestJUnitReport(t *testing.T) {
+	report, err := JUnitReport("smokeTests", []Result{
+		{Name: "health", URL: "https://app/health", Attempts: 1, Duration: 120 * time.Millisecond},
+		{Name: "ping", URL: "https://app/ping", Attempts: 3, Duration: time.Second, Failure: "unexpected status code 500, expected [200]"},
+	}, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+
+	assert.NoError(t, err)
+	xml := string(report)
+	assert.True(t, strings.HasPrefix(xml, "<?xml"))
+	assert.Contains(t, xml, `<testsuite name="smokeTests" tests="2" failures="1" time="1.120" timestamp="2024-01-02T03:04:05">`)
+	assert.Contains(t, xml, `<testcase name="health" classname="smokeTests" time="0.120">`)
+	assert.Contains(t, xml, `<failure message="unexpected status code 500, expected [200]" type="SmokeTestFailure">`)
+}
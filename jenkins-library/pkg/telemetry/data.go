@@ -48,6 +48,8 @@ type CustomData struct {
 	BuildVersionCreation  string `json:"buildVersionCreation,omitempty"`
 	PullRequestMode       string `json:"pullRequestMode,omitempty"`
 	GroovyTemplateUsed    string `json:"groovyTemplateUsed,omitempty"`
+	BuildCacheResult      string `json:"buildCacheResult,omitempty"`
+	BuildCacheSize        string `json:"buildCacheSize,omitempty"`
 }
 
 // StepTelemetryData definition for telemetry reporting and monitoring
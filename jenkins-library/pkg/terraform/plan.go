@@ -0,0 +1,162 @@
+package terraform
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Plan is the subset of the JSON representation of a plan (`terraform show -json <plan>`) evaluated by piper
+type Plan struct {
+	FormatVersion   string           `json:"format_version"`
+	ResourceChanges []ResourceChange `json:"resource_changes"`
+}
+
+// ResourceChange describes the planned change of a single resource instance
+type ResourceChange struct {
+	Address string `json:"address"`
+	Type    string `json:"type"`
+	Change  struct {
+		Actions []string `json:"actions"`
+	} `json:"change"`
+}
+
+// ChangeCounts holds the number of planned changes by kind
+type ChangeCounts struct {
+	Create  int `json:"create"`
+	Update  int `json:"update"`
+	Replace int `json:"replace"`
+	Destroy int `json:"destroy"`
+}
+
+// PlanSummary summarises a plan in total and per resource type
+type PlanSummary struct {
+	Total           ChangeCounts            `json:"total"`
+	ByResourceType  map[string]ChangeCounts `json:"byResourceType"`
+	ChangedTypes    []string                `json:"-"`
+	DestroyedAssets []string                `json:"destroyed"`
+}
+
+// ReadPlan parses the JSON representation of a terraform plan
+func ReadPlan(planJson string) (Plan, error) {
+	var plan Plan
+	if err := json.Unmarshal([]byte(planJson), &plan); err != nil {
+		return plan, fmt.Errorf("failed to parse terraform plan: %w", err)
+	}
+	return plan, nil
+}
+
+// Summarize counts the planned changes of a plan. Replacements (delete and create) are counted separately and also count as destroy.
+func (p Plan) Summarize() PlanSummary {
+	summary := PlanSummary{ByResourceType: map[string]ChangeCounts{}, DestroyedAssets: []string{}}
+	for _, rc := range p.ResourceChanges {
+		counts, known := summary.ByResourceType[rc.Type]
+		actions := rc.Change.Actions
+		switch {
+		case slices.Contains(actions, "delete") && slices.Contains(actions, "create"):
+			counts.Replace++
+			summary.Total.Replace++
+			summary.DestroyedAssets = append(summary.DestroyedAssets, rc.Address)
+		case slices.Contains(actions, "delete"):
+			counts.Destroy++
+			summary.Total.Destroy++
+			summary.DestroyedAssets = append(summary.DestroyedAssets, rc.Address)
+		case slices.Contains(actions, "create"):
+			counts.Create++
+			summary.Total.Create++
+		case slices.Contains(actions, "update"):
+			counts.Update++
+			summary.Total.Update++
+		default:
+			// no-op and read do not change infrastructure
+			continue
+		}
+		summary.ByResourceType[rc.Type] = counts
+		if !known {
+			summary.ChangedTypes = append(summary.ChangedTypes, rc.Type)
+		}
+	}
+	sort.Strings(summary.ChangedTypes)
+	return summary
+}
+
+// HasChanges returns true if the plan changes any resource
+func (s PlanSummary) HasChanges() bool {
+	return s.Total != ChangeCounts{}
+}
+
+// Markdown renders the summary as markdown table, e.g. to be used as pull request comment
+func (s PlanSummary) Markdown(workspace string) string {
+	var b strings.Builder
+	b.WriteString("## Terraform plan summary")
+	if len(workspace) > 0 {
+		fmt.Fprintf(&b, " (workspace `%s`)", workspace)
+	}
+	b.WriteString("\n\n")
+	if !s.HasChanges() {
+		b.WriteString("No changes. Your infrastructure matches the configuration.\n")
+		return b.String()
+	}
+	fmt.Fprintf(&b, "**%d to create, %d to update, %d to replace, %d to destroy**\n\n", s.Total.Create, s.Total.Update, s.Total.Replace, s.Total.Destroy)
+	b.WriteString("| Resource type | Create | Update | Replace | Destroy |\n")
+	b.WriteString("|---|---:|---:|---:|---:|\n")
+	for _, t := range s.ChangedTypes {
+		c := s.ByResourceType[t]
+		fmt.Fprintf(&b, "| `%s` | %d | %d | %d | %d |\n", t, c.Create, c.Update, c.Replace, c.Destroy)
+	}
+	if len(s.DestroyedAssets) > 0 {
+		b.WriteString("\nResources to be destroyed or replaced:\n\n")
+		for _, a := range s.DestroyedAssets {
+			fmt.Fprintf(&b, "* `%s`\n", a)
+		}
+	}
+	return b.String()
+}
+
+// PlanPolicy defines conditions under which a plan is rejected
+type PlanPolicy struct {
+	// DenyDestroyInWorkspaces contains glob patterns of workspaces in which destroying (or replacing) resources is forbidden
+	DenyDestroyInWorkspaces []string
+	// ForbiddenResourceTypes contains glob patterns of resource types which must not be created, updated or replaced
+	ForbiddenResourceTypes []string
+}
+
+// Check returns a list of policy violations of the plan summary for the given workspace
+func (p PlanPolicy) Check(summary PlanSummary, workspace string) ([]string, error) {
+	violations := []string{}
+
+	destroys := summary.Total.Destroy + summary.Total.Replace
+	if destroys > 0 {
+		for _, pattern := range p.DenyDestroyInWorkspaces {
+			matched, err := filepath.Match(pattern, workspace)
+			if err != nil {
+				return nil, fmt.Errorf("invalid workspace pattern '%s': %w", pattern, err)
+			}
+			if matched {
+				violations = append(violations, fmt.Sprintf("plan destroys %d resource(s) in workspace '%s' (%s)", destroys, workspace, strings.Join(summary.DestroyedAssets, ", ")))
+				break
+			}
+		}
+	}
+
+	for _, t := range summary.ChangedTypes {
+		counts := summary.ByResourceType[t]
+		if counts.Create+counts.Update+counts.Replace == 0 {
+			continue
+		}
+		for _, pattern := range p.ForbiddenResourceTypes {
+			matched, err := filepath.Match(pattern, t)
+			if err != nil {
+				return nil, fmt.Errorf("invalid resource type pattern '%s': %w", pattern, err)
+			}
+			if matched {
+				violations = append(violations, fmt.Sprintf("plan changes forbidden resource type '%s'", t))
+				break
+			}
+		}
+	}
+	return violations, nil
+}
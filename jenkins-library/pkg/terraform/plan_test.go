@@ -0,0 +1,87 @@
+//go:build unit
+// +build unit
+
+package terraform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testPlanJson = `{
+  "format_version": "1.2",
+  "resource_changes": [
+    {"address": "aws_instance.web[0]", "type": "aws_instance", "change": {"actions": ["create"]}},
+    {"address": "aws_instance.web[1]", "type": "aws_instance", "change": {"actions": ["update"]}},
+    {"address": "aws_instance.db", "type": "aws_instance", "change": {"actions": ["delete", "create"]}},
+    {"address": "aws_iam_role.admin", "type": "aws_iam_role", "change": {"actions": ["create"]}},
+    {"address": "aws_s3_bucket.old", "type": "aws_s3_bucket", "change": {"actions": ["delete"]}},
+    {"address": "data.aws_ami.ubuntu", "type": "aws_ami", "change": {"actions": ["read"]}},
+    {"address": "aws_vpc.main", "type": "aws_vpc", "change": {"actions": ["no-op"]}}
+  ]
+}`
+
+func TestSummarize(t *testing.T) {
+	plan, err := ReadPlan(testPlanJson)
+	assert.NoError(t, err)
+
+	summary := plan.Summarize()
+
+	assert.Equal(t, ChangeCounts{Create: 2, Update: 1, Replace: 1, Destroy: 1}, summary.Total)
+	assert.Equal(t, []string{"aws_iam_role", "aws_instance", "aws_s3_bucket"}, summary.ChangedTypes)
+	assert.Equal(t, ChangeCounts{Create: 1, Update: 1, Replace: 1}, summary.ByResourceType["aws_instance"])
+	assert.Equal(t, []string{"aws_instance.db", "aws_s3_bucket.old"}, summary.DestroyedAssets)
+	assert.True(t, summary.HasChanges())
+}
+
+func TestReadPlanInvalid(t *testing.T) {
+	_, err := ReadPlan("not json")
+	assert.ErrorContains(t, err, "failed to parse terraform plan")
+}
+
+func TestMarkdown(t *testing.T) {
+	t.Run("with changes", func(t *testing.T) {
+		plan, _ := ReadPlan(testPlanJson)
+		md := plan.Summarize().Markdown("prod")
+
+		assert.Contains(t, md, "## Terraform plan summary (workspace `prod`)")
+		assert.Contains(t, md, "**2 to create, 1 to update, 1 to replace, 1 to destroy**")
+		assert.Contains(t, md, "| `aws_instance` | 1 | 1 | 1 | 0 |")
+		assert.Contains(t, md, "* `aws_s3_bucket.old`")
+	})
+
+	t.Run("without changes", func(t *testing.T) {
+		md := Plan{}.Summarize().Markdown("")
+		assert.Equal(t, "## Terraform plan summary\n\nNo changes. Your infrastructure matches the configuration.\n", md)
+	})
+}
+
+func TestPlanPolicy(t *testing.T) {
+	plan, _ := ReadPlan(testPlanJson)
+	summary := plan.Summarize()
+
+	t.Run("destroy in protected workspace", func(t *testing.T) {
+		violations, err := PlanPolicy{DenyDestroyInWorkspaces: []string{"prod*"}}.Check(summary, "production")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"plan destroys 2 resource(s) in workspace 'production' (aws_instance.db, aws_s3_bucket.old)"}, violations)
+	})
+
+	t.Run("destroy in unprotected workspace", func(t *testing.T) {
+		violations, err := PlanPolicy{DenyDestroyInWorkspaces: []string{"prod*"}}.Check(summary, "dev")
+		assert.NoError(t, err)
+		assert.Empty(t, violations)
+	})
+
+	t.Run("forbidden resource types", func(t *testing.T) {
+		violations, err := PlanPolicy{ForbiddenResourceTypes: []string{"aws_iam_*", "aws_s3_bucket"}}.Check(summary, "dev")
+		assert.NoError(t, err)
+		// deleting a forbidden resource type is allowed
+		assert.Equal(t, []string{"plan changes forbidden resource type 'aws_iam_role'"}, violations)
+	})
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		_, err := PlanPolicy{ForbiddenResourceTypes: []string{"["}}.Check(summary, "dev")
+		assert.ErrorContains(t, err, "invalid resource type pattern '['")
+	})
+}
@@ -0,0 +1,490 @@
+package testreport
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Coverage formats supported by ParseCoverage
+const (
+	FormatCobertura = "cobertura"
+	FormatJaCoCo    = "jacoco"
+	FormatLcov      = "lcov"
+	FormatGoCover   = "gocover"
+)
+
+// Coverage holds the number of hits per line of the source files, the paths of the files are slash separated
+// and relative to the root of the repository where possible
+type Coverage map[string]map[int]int
+
+// CoverageSummary counts the covered lines
+type CoverageSummary struct {
+	Lines        int     `json:"lines"`
+	CoveredLines int     `json:"coveredLines"`
+	Percentage   float64 `json:"percentage"`
+}
+
+// PathResolver resolves the source paths of coverage reports to paths relative to the root of the repository
+type PathResolver struct {
+	// WorkDir is the absolute path of the root of the repository
+	WorkDir string
+	// GoModules maps the paths of Go modules to their directories relative to WorkDir
+	GoModules map[string]string
+}
+
+// Merge adds the hits of another coverage, e.g. of another module or of another test run
+func (c Coverage) Merge(other Coverage) {
+	for file, lines := range other {
+		if _, ok := c[file]; !ok {
+			c[file] = map[int]int{}
+		}
+		for line, hits := range lines {
+			c[file][line] += hits
+		}
+	}
+}
+
+// Summary counts the covered lines of all files
+func (c Coverage) Summary() CoverageSummary {
+	summary := CoverageSummary{}
+	for _, lines := range c {
+		for _, hits := range lines {
+			summary.Lines++
+			if hits > 0 {
+				summary.CoveredLines++
+			}
+		}
+	}
+	summary.Percentage = percentage(summary.CoveredLines, summary.Lines)
+	return summary
+}
+
+// Files returns the paths of the files sorted alphabetically
+func (c Coverage) Files() []string {
+	files := make([]string, 0, len(c))
+	for file := range c {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	return files
+}
+
+// Lookup returns the coverage of a file of the repository. Since some formats, e.g. JaCoCo, only contain paths relative to a
+// source directory, a file also matches if its path ends with the path of the coverage or vice versa.
+func (c Coverage) Lookup(file string) (map[int]int, bool) {
+	if lines, ok := c[file]; ok {
+		return lines, true
+	}
+	match := ""
+	for candidate := range c {
+		if strings.HasSuffix(file, "/"+candidate) && len(candidate) > len(match) {
+			match = candidate
+		}
+	}
+	if len(match) == 0 {
+		// absolute paths of files outside of the repository, e.g. built in another container, end with the path of the file
+		for _, candidate := range c.Files() {
+			if strings.HasSuffix(candidate, "/"+file) {
+				match = candidate
+				break
+			}
+		}
+	}
+	if len(match) == 0 {
+		return nil, false
+	}
+	return c[match], true
+}
+
+func (c Coverage) add(file string, line, hits int) {
+	if line <= 0 {
+		return
+	}
+	if _, ok := c[file]; !ok {
+		c[file] = map[int]int{}
+	}
+	c[file][line] += hits
+}
+
+// ParseCoverage reads a coverage report in Cobertura, JaCoCo, lcov or Go cover profile format, the format is detected from the content.
+// The report path is relative to the root of the repository and used to resolve relative source paths.
+func ParseCoverage(content []byte, reportPath string, resolver PathResolver) (Coverage, string, error) {
+	trimmed := bytes.TrimSpace(content)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("mode:")):
+		coverage, err := parseGoCover(trimmed, resolver)
+		return coverage, FormatGoCover, err
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		root, err := xmlRoot(trimmed)
+		if err != nil {
+			return nil, "", errors.Wrapf(err, "failed to parse coverage report %v", reportPath)
+		}
+		switch root {
+		case "coverage":
+			coverage, err := parseCobertura(trimmed, reportPath, resolver)
+			return coverage, FormatCobertura, err
+		case "report":
+			coverage, err := parseJaCoCo(trimmed)
+			return coverage, FormatJaCoCo, err
+		}
+		return nil, "", fmt.Errorf("unsupported coverage report %v with root element '%v'", reportPath, root)
+	case bytes.Contains(trimmed, []byte("SF:")):
+		coverage, err := parseLcov(trimmed, reportPath, resolver)
+		return coverage, FormatLcov, err
+	}
+	return nil, "", fmt.Errorf("unsupported format of coverage report %v", reportPath)
+}
+
+func xmlRoot(content []byte) (string, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+	// JaCoCo reports reference a DTD which is not resolved
+	decoder.Strict = false
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return "", err
+		}
+		if element, ok := token.(xml.StartElement); ok {
+			return element.Name.Local, nil
+		}
+	}
+}
+
+type coberturaReport struct {
+	Sources  []string `xml:"sources>source"`
+	Packages []struct {
+		Classes []struct {
+			Filename string `xml:"filename,attr"`
+			Lines    []struct {
+				Number int `xml:"number,attr"`
+				Hits   int `xml:"hits,attr"`
+			} `xml:"lines>line"`
+		} `xml:"classes>class"`
+	} `xml:"packages>package"`
+}
+
+func parseCobertura(content []byte, reportPath string, resolver PathResolver) (Coverage, error) {
+	var report coberturaReport
+	if err := unmarshalXML(content, &report); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse Cobertura report %v", reportPath)
+	}
+	base := reportRoot(reportPath)
+	if len(report.Sources) > 0 {
+		base = resolver.resolve(report.Sources[0], base)
+	}
+	coverage := Coverage{}
+	for _, pkg := range report.Packages {
+		for _, class := range pkg.Classes {
+			file := resolver.resolve(class.Filename, base)
+			for _, line := range class.Lines {
+				coverage.add(file, line.Number, line.Hits)
+			}
+		}
+	}
+	return coverage, nil
+}
+
+type jacocoReport struct {
+	Packages []struct {
+		Name        string `xml:"name,attr"`
+		SourceFiles []struct {
+			Name  string `xml:"name,attr"`
+			Lines []struct {
+				Number       int `xml:"nr,attr"`
+				CoveredInstr int `xml:"ci,attr"`
+			} `xml:"line"`
+		} `xml:"sourcefile"`
+	} `xml:"package"`
+}
+
+// parseJaCoCo reads a JaCoCo XML report, the paths of the files are relative to the source directories, e.g. src/main/java
+func parseJaCoCo(content []byte) (Coverage, error) {
+	var report jacocoReport
+	if err := unmarshalXML(content, &report); err != nil {
+		return nil, errors.Wrap(err, "failed to parse JaCoCo report")
+	}
+	coverage := Coverage{}
+	for _, pkg := range report.Packages {
+		for _, sourceFile := range pkg.SourceFiles {
+			file := path.Join(pkg.Name, sourceFile.Name)
+			for _, line := range sourceFile.Lines {
+				// JaCoCo counts the covered instructions instead of hits
+				coverage.add(file, line.Number, line.CoveredInstr)
+			}
+		}
+	}
+	return coverage, nil
+}
+
+func parseLcov(content []byte, reportPath string, resolver PathResolver) (Coverage, error) {
+	coverage := Coverage{}
+	base := reportRoot(reportPath)
+	file := ""
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "SF:"):
+			file = resolver.resolve(strings.TrimPrefix(line, "SF:"), base)
+		case strings.HasPrefix(line, "DA:") && len(file) > 0:
+			fields := strings.Split(strings.TrimPrefix(line, "DA:"), ",")
+			if len(fields) < 2 {
+				return nil, fmt.Errorf("failed to parse lcov report %v: invalid line '%v'", reportPath, line)
+			}
+			number, err1 := strconv.Atoi(fields[0])
+			hits, err2 := strconv.Atoi(fields[1])
+			if err1 != nil || err2 != nil {
+				return nil, fmt.Errorf("failed to parse lcov report %v: invalid line '%v'", reportPath, line)
+			}
+			coverage.add(file, number, hits)
+		case line == "end_of_record":
+			file = ""
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse lcov report %v", reportPath)
+	}
+	return coverage, nil
+}
+
+// parseGoCover reads a Go cover profile, i.e. lines 'name.go:line.column,line.column numberOfStatements count'
+func parseGoCover(content []byte, resolver PathResolver) (Coverage, error) {
+	coverage := Coverage{}
+	// the lines of overlapping blocks are covered if one of the blocks is covered
+	blocks := map[string]map[int]int{}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "mode:") {
+			continue
+		}
+		separator := strings.LastIndex(line, ":")
+		fields := strings.Fields(line[separator+1:])
+		if separator < 0 || len(fields) != 3 {
+			return nil, fmt.Errorf("failed to parse Go cover profile: invalid line '%v'", line)
+		}
+		positions := strings.Split(fields[0], ",")
+		count, err := strconv.Atoi(fields[2])
+		if len(positions) != 2 || err != nil {
+			return nil, fmt.Errorf("failed to parse Go cover profile: invalid line '%v'", line)
+		}
+		start, err1 := strconv.Atoi(strings.SplitN(positions[0], ".", 2)[0])
+		end, err2 := strconv.Atoi(strings.SplitN(positions[1], ".", 2)[0])
+		if err1 != nil || err2 != nil {
+			return nil, fmt.Errorf("failed to parse Go cover profile: invalid line '%v'", line)
+		}
+		file := resolver.resolveGo(line[:separator])
+		if _, ok := blocks[file]; !ok {
+			blocks[file] = map[int]int{}
+		}
+		for number := start; number <= end; number++ {
+			if hits, ok := blocks[file][number]; !ok || count > hits {
+				blocks[file][number] = count
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to parse Go cover profile")
+	}
+	coverage.Merge(blocks)
+	return coverage, nil
+}
+
+func unmarshalXML(content []byte, v interface{}) error {
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+	decoder.Strict = false
+	return decoder.Decode(v)
+}
+
+// reportRoot returns the directory relative source paths of a report refer to. Tools like Jest, Karma or nyc write their reports
+// into the directory 'coverage' of the project.
+func reportRoot(reportPath string) string {
+	dir := filepath.ToSlash(filepath.Dir(reportPath))
+	segments := strings.Split(dir, "/")
+	for i := len(segments) - 1; i >= 0; i-- {
+		if segments[i] == "coverage" {
+			return path.Join(segments[:i]...)
+		}
+	}
+	return dir
+}
+
+// resolve returns the path of a source file relative to the root of the repository
+func (r PathResolver) resolve(file, base string) string {
+	file = filepath.ToSlash(file)
+	workDir := filepath.ToSlash(r.WorkDir)
+	if path.IsAbs(file) {
+		file = path.Clean(file)
+		if len(workDir) > 0 {
+			if relative, err := filepath.Rel(workDir, file); err == nil && !strings.HasPrefix(relative, "..") {
+				return filepath.ToSlash(relative)
+			}
+		}
+		return file
+	}
+	return path.Join(base, file)
+}
+
+// resolveGo returns the path of a file of a Go package relative to the root of the repository
+func (r PathResolver) resolveGo(file string) string {
+	module := ""
+	for candidate := range r.GoModules {
+		if (file == candidate || strings.HasPrefix(file, candidate+"/")) && len(candidate) > len(module) {
+			module = candidate
+		}
+	}
+	if len(module) == 0 {
+		return r.resolve(file, ".")
+	}
+	return path.Join(r.GoModules[module], strings.TrimPrefix(file, module+"/"))
+}
+
+type coberturaOutput struct {
+	XMLName      xml.Name                 `xml:"coverage"`
+	LineRate     string                   `xml:"line-rate,attr"`
+	BranchRate   string                   `xml:"branch-rate,attr"`
+	LinesCovered int                      `xml:"lines-covered,attr"`
+	LinesValid   int                      `xml:"lines-valid,attr"`
+	Version      string                   `xml:"version,attr"`
+	Timestamp    int64                    `xml:"timestamp,attr"`
+	Sources      []string                 `xml:"sources>source"`
+	Packages     []coberturaOutputPackage `xml:"packages>package"`
+}
+
+type coberturaOutputPackage struct {
+	Name       string                 `xml:"name,attr"`
+	LineRate   string                 `xml:"line-rate,attr"`
+	BranchRate string                 `xml:"branch-rate,attr"`
+	Complexity string                 `xml:"complexity,attr"`
+	Classes    []coberturaOutputClass `xml:"classes>class"`
+}
+
+type coberturaOutputClass struct {
+	Name       string                `xml:"name,attr"`
+	Filename   string                `xml:"filename,attr"`
+	LineRate   string                `xml:"line-rate,attr"`
+	BranchRate string                `xml:"branch-rate,attr"`
+	Complexity string                `xml:"complexity,attr"`
+	Methods    struct{}              `xml:"methods"`
+	Lines      []coberturaOutputLine `xml:"lines>line"`
+}
+
+type coberturaOutputLine struct {
+	Number int `xml:"number,attr"`
+	Hits   int `xml:"hits,attr"`
+}
+
+// WriteCobertura renders the coverage as Cobertura XML report with one package per directory
+func WriteCobertura(coverage Coverage, sourceDir string, timestamp time.Time) ([]byte, error) {
+	summary := coverage.Summary()
+	report := coberturaOutput{
+		LineRate:     rate(summary.CoveredLines, summary.Lines),
+		BranchRate:   "0",
+		LinesCovered: summary.CoveredLines,
+		LinesValid:   summary.Lines,
+		Version:      "piper",
+		Timestamp:    timestamp.UnixMilli(),
+		Sources:      []string{sourceDir},
+	}
+	packages := map[string]int{}
+	for _, file := range coverage.Files() {
+		dir := path.Dir(file)
+		index, ok := packages[dir]
+		if !ok {
+			index = len(report.Packages)
+			packages[dir] = index
+			report.Packages = append(report.Packages, coberturaOutputPackage{Name: dir, BranchRate: "0", Complexity: "0"})
+		}
+		class := coberturaOutputClass{Name: path.Base(file), Filename: file, BranchRate: "0", Complexity: "0"}
+		numbers := make([]int, 0, len(coverage[file]))
+		for number := range coverage[file] {
+			numbers = append(numbers, number)
+		}
+		sort.Ints(numbers)
+		covered := 0
+		for _, number := range numbers {
+			class.Lines = append(class.Lines, coberturaOutputLine{Number: number, Hits: coverage[file][number]})
+			if coverage[file][number] > 0 {
+				covered++
+			}
+		}
+		class.LineRate = rate(covered, len(numbers))
+		report.Packages[index].Classes = append(report.Packages[index].Classes, class)
+	}
+	for i := range report.Packages {
+		covered, total := 0, 0
+		for _, class := range report.Packages[i].Classes {
+			for _, line := range class.Lines {
+				total++
+				if line.Hits > 0 {
+					covered++
+				}
+			}
+		}
+		report.Packages[i].LineRate = rate(covered, total)
+	}
+
+	content, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), content...), nil
+}
+
+func rate(covered, total int) string {
+	if total == 0 {
+		return "0"
+	}
+	return strconv.FormatFloat(float64(covered)/float64(total), 'f', 4, 64)
+}
+
+func percentage(covered, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(int(float64(covered)/float64(total)*10000)) / 100
+}
+
+// ChangedLinesCoverage is the coverage of the lines changed by a pull request
+type ChangedLinesCoverage struct {
+	CoverageSummary
+	// Uncovered contains the changed lines which are not covered per file
+	Uncovered map[string][]int `json:"uncovered,omitempty"`
+}
+
+// ChangedLines returns the coverage of the changed lines per file. Changed lines which are not part of the coverage,
+// e.g. comments or files without tests in the coverage reports, are not taken into account.
+func (c Coverage) ChangedLines(changed map[string][]int) ChangedLinesCoverage {
+	result := ChangedLinesCoverage{Uncovered: map[string][]int{}}
+	for file, numbers := range changed {
+		lines, ok := c.Lookup(filepath.ToSlash(file))
+		if !ok {
+			continue
+		}
+		for _, number := range numbers {
+			hits, coverable := lines[number]
+			if !coverable {
+				continue
+			}
+			result.Lines++
+			if hits > 0 {
+				result.CoveredLines++
+			} else {
+				result.Uncovered[file] = append(result.Uncovered[file], number)
+			}
+		}
+	}
+	result.Percentage = percentage(result.CoveredLines, result.Lines)
+	return result
+}
@@ -0,0 +1,166 @@
+//go:build unit
+// +build unit
+
+package testreport
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	coberturaInput = `<?xml version="1.0" ?>
+<!DOCTYPE coverage SYSTEM "http://cobertura.sourceforge.net/xml/coverage-04.dtd">
+<coverage lines-valid="3" lines-covered="2" line-rate="0.6667" version="1.9" timestamp="1700000000000">
+  <sources>
+    <source>/home/runner/work/app/ui</source>
+  </sources>
+  <packages>
+    <package name="src">
+      <classes>
+        <class name="index.js" filename="src/index.js" line-rate="0.6667">
+          <lines>
+            <line number="1" hits="1"/>
+            <line number="2" hits="3"/>
+            <line number="4" hits="0"/>
+          </lines>
+        </class>
+      </classes>
+    </package>
+  </packages>
+</coverage>`
+	jacocoXMLReport = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<!DOCTYPE report PUBLIC "-//JACOCO//DTD Report 1.1//EN" "report.dtd">
+<report name="api">
+  <package name="com/example">
+    <class name="com/example/Calculator" sourcefilename="Calculator.java"/>
+    <sourcefile name="Calculator.java">
+      <line nr="3" mi="0" ci="3" mb="0" cb="0"/>
+      <line nr="5" mi="2" ci="0" mb="0" cb="0"/>
+    </sourcefile>
+  </package>
+</report>`
+	lcovReport = `TN:
+SF:src/app.ts
+FN:1,main
+DA:1,1
+DA:2,0
+end_of_record
+SF:/home/runner/work/app/shared/util.ts
+DA:7,2
+end_of_record
+`
+	goCoverProfile = `mode: set
+github.com/example/app/pkg/server/server.go:10.20,12.2 1 1
+github.com/example/app/pkg/server/server.go:12.2,14.3 2 0
+github.com/example/app/main.go:5.13,7.2 1 0
+`
+)
+
+func TestParseCoverage(t *testing.T) {
+	resolver := PathResolver{WorkDir: "/home/runner/work/app", GoModules: map[string]string{"github.com/example/app": "."}}
+
+	t.Run("Cobertura", func(t *testing.T) {
+		coverage, format, err := ParseCoverage([]byte(coberturaInput), "ui/coverage/cobertura-coverage.xml", resolver)
+
+		require.NoError(t, err)
+		assert.Equal(t, FormatCobertura, format)
+		assert.Equal(t, Coverage{"ui/src/index.js": {1: 1, 2: 3, 4: 0}}, coverage)
+	})
+
+	t.Run("JaCoCo", func(t *testing.T) {
+		coverage, format, err := ParseCoverage([]byte(jacocoXMLReport), "api/target/site/jacoco/jacoco.xml", resolver)
+
+		require.NoError(t, err)
+		assert.Equal(t, FormatJaCoCo, format)
+		assert.Equal(t, Coverage{"com/example/Calculator.java": {3: 3, 5: 0}}, coverage)
+	})
+
+	t.Run("lcov", func(t *testing.T) {
+		coverage, format, err := ParseCoverage([]byte(lcovReport), "web/coverage/lcov.info", resolver)
+
+		require.NoError(t, err)
+		assert.Equal(t, FormatLcov, format)
+		assert.Equal(t, Coverage{"web/src/app.ts": {1: 1, 2: 0}, "shared/util.ts": {7: 2}}, coverage)
+	})
+
+	t.Run("Go cover profile", func(t *testing.T) {
+		coverage, format, err := ParseCoverage([]byte(goCoverProfile), "cover.out", resolver)
+
+		require.NoError(t, err)
+		assert.Equal(t, FormatGoCover, format)
+		assert.Equal(t, Coverage{
+			"pkg/server/server.go": {10: 1, 11: 1, 12: 1, 13: 0, 14: 0},
+			"main.go":              {5: 0, 6: 0, 7: 0},
+		}, coverage)
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		_, _, err := ParseCoverage([]byte(`{"total": {}}`), "coverage-summary.json", resolver)
+
+		assert.EqualError(t, err, "unsupported format of coverage report coverage-summary.json")
+	})
+}
+
+func TestCoverage(t *testing.T) {
+	coverage := Coverage{"ui/src/index.js": {1: 1, 2: 0}}
+	coverage.Merge(Coverage{"ui/src/index.js": {2: 1, 3: 0}, "com/example/Calculator.java": {3: 3, 5: 0, 6: 1}})
+
+	t.Run("merge", func(t *testing.T) {
+		assert.Equal(t, Coverage{"ui/src/index.js": {1: 1, 2: 1, 3: 0}, "com/example/Calculator.java": {3: 3, 5: 0, 6: 1}}, coverage)
+		assert.Equal(t, CoverageSummary{Lines: 6, CoveredLines: 4, Percentage: 66.66}, coverage.Summary())
+	})
+
+	t.Run("changed lines", func(t *testing.T) {
+		changed := coverage.ChangedLines(map[string][]int{
+			"ui/src/index.js": {2, 3, 10},
+			"api/src/main/java/com/example/Calculator.java": {5, 6},
+			"README.md": {1},
+		})
+
+		assert.Equal(t, ChangedLinesCoverage{
+			CoverageSummary: CoverageSummary{Lines: 4, CoveredLines: 2, Percentage: 50},
+			Uncovered:       map[string][]int{"ui/src/index.js": {3}, "api/src/main/java/com/example/Calculator.java": {5}},
+		}, changed)
+	})
+
+	t.Run("lookup of files built outside the repository", func(t *testing.T) {
+		lines, ok := Coverage{"/build/app/ui/src/index.js": {1: 1}}.Lookup("ui/src/index.js")
+
+		assert.True(t, ok)
+		assert.Equal(t, map[int]int{1: 1}, lines)
+	})
+
+	t.Run("write Cobertura", func(t *testing.T) {
+		content, err := WriteCobertura(Coverage{"ui/src/index.js": {1: 1, 2: 0}}, ".", time.UnixMilli(1700000000000))
+
+		require.NoError(t, err)
+		assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<coverage line-rate="0.5000" branch-rate="0" lines-covered="1" lines-valid="2" version="piper" timestamp="1700000000000">
+  <sources>
+    <source>.</source>
+  </sources>
+  <packages>
+    <package name="ui/src" line-rate="0.5000" branch-rate="0" complexity="0">
+      <classes>
+        <class name="index.js" filename="ui/src/index.js" line-rate="0.5000" branch-rate="0" complexity="0">
+          <methods></methods>
+          <lines>
+            <line number="1" hits="1"></line>
+            <line number="2" hits="0"></line>
+          </lines>
+        </class>
+      </classes>
+    </package>
+  </packages>
+</coverage>`, string(content))
+
+		// the written report can be read again
+		parsed, _, err := ParseCoverage(content, "aggregated-cobertura-coverage.xml", PathResolver{})
+		require.NoError(t, err)
+		assert.Equal(t, Coverage{"ui/src/index.js": {1: 1, 2: 0}}, parsed)
+	})
+}
@@ -0,0 +1,293 @@
+package testreport
+
+import (
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Status is the outcome of a test case
+type Status string
+
+const (
+	StatusPassed  Status = "passed"
+	StatusFailed  Status = "failed"
+	StatusError   Status = "error"
+	StatusSkipped Status = "skipped"
+)
+
+// TestCase is the result of a test case, aggregated over all its executions
+type TestCase struct {
+	Suite     string        `json:"suite,omitempty"`
+	ClassName string        `json:"className,omitempty"`
+	Name      string        `json:"name"`
+	Duration  time.Duration `json:"-"`
+	Status    Status        `json:"status"`
+	Message   string        `json:"message,omitempty"`
+	Details   string        `json:"-"`
+	// Attempts is the number of executions of the test case, e.g. in reruns of failed tests
+	Attempts int `json:"attempts"`
+	// Flaky is true if the test case both failed and passed
+	Flaky bool `json:"flaky,omitempty"`
+}
+
+// ID identifies the test case by its class and name
+func (t TestCase) ID() string {
+	return t.ClassName + "#" + t.Name
+}
+
+// executionKey identifies the executions of a test case, test cases of other suites with the same ID are different test cases
+func (t TestCase) executionKey() string {
+	return t.Suite + "/" + t.ID()
+}
+
+// TestSummary counts the test cases by their outcome
+type TestSummary struct {
+	Total   int `json:"total"`
+	Passed  int `json:"passed"`
+	Failed  int `json:"failed"`
+	Errors  int `json:"errors"`
+	Skipped int `json:"skipped"`
+	Flaky   int `json:"flaky"`
+}
+
+// Summarize counts the test cases by their outcome
+func Summarize(tests []TestCase) TestSummary {
+	summary := TestSummary{Total: len(tests)}
+	for _, test := range tests {
+		switch test.Status {
+		case StatusPassed:
+			summary.Passed++
+		case StatusFailed:
+			summary.Failed++
+		case StatusError:
+			summary.Errors++
+		case StatusSkipped:
+			summary.Skipped++
+		}
+		if test.Flaky {
+			summary.Flaky++
+		}
+	}
+	return summary
+}
+
+// MergeTests combines the executions of the same test case of a suite, e.g. from reruns of failed tests.
+// A test case which failed in one execution and passed in another is flaky and considered as passed.
+func MergeTests(tests []TestCase) []TestCase {
+	merged := map[string]*TestCase{}
+	ids := []string{}
+	for _, test := range tests {
+		existing, ok := merged[test.executionKey()]
+		if !ok {
+			test := test
+			merged[test.executionKey()] = &test
+			ids = append(ids, test.executionKey())
+			continue
+		}
+		existing.Attempts += test.Attempts
+		existing.Duration += test.Duration
+		existing.Flaky = existing.Flaky || test.Flaky
+		switch {
+		case existing.Status == test.Status:
+		case existing.Status == StatusSkipped:
+			existing.Status, existing.Message, existing.Details = test.Status, test.Message, test.Details
+		case test.Status == StatusSkipped:
+		case existing.Status == StatusPassed || test.Status == StatusPassed:
+			if len(existing.Message) == 0 {
+				existing.Message, existing.Details = test.Message, test.Details
+			}
+			existing.Status, existing.Flaky = StatusPassed, true
+		}
+	}
+	result := make([]TestCase, 0, len(ids))
+	for _, id := range ids {
+		result = append(result, *merged[id])
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Suite != result[j].Suite {
+			return result[i].Suite < result[j].Suite
+		}
+		return result[i].ID() < result[j].ID()
+	})
+	return result
+}
+
+type junitSuite struct {
+	XMLName xml.Name     `xml:""`
+	Name    string       `xml:"name,attr"`
+	Suites  []junitSuite `xml:"testsuite"`
+	Cases   []junitCase  `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string         `xml:"name,attr"`
+	ClassName string         `xml:"classname,attr"`
+	Time      string         `xml:"time,attr"`
+	Failure   *junitProblem  `xml:"failure"`
+	Error     *junitProblem  `xml:"error"`
+	Skipped   *junitProblem  `xml:"skipped"`
+	Flaky     []junitProblem `xml:"flakyFailure"`
+	FlakyErr  []junitProblem `xml:"flakyError"`
+	Rerun     []junitProblem `xml:"rerunFailure"`
+	RerunErr  []junitProblem `xml:"rerunError"`
+}
+
+type junitProblem struct {
+	Message string `xml:"message,attr,omitempty"`
+	Type    string `xml:"type,attr,omitempty"`
+	Content string `xml:",chardata"`
+}
+
+// ParseJUnit reads the test cases of a JUnit XML report, including the reruns reported by the Maven Surefire plugin
+func ParseJUnit(content []byte) ([]TestCase, error) {
+	var root junitSuite
+	if err := xml.Unmarshal(content, &root); err != nil {
+		return nil, errors.Wrap(err, "failed to parse JUnit report")
+	}
+	if root.XMLName.Local != "testsuites" && root.XMLName.Local != "testsuite" {
+		return nil, fmt.Errorf("failed to parse JUnit report: unexpected root element '%v'", root.XMLName.Local)
+	}
+	return junitTests(root, ""), nil
+}
+
+func junitTests(suite junitSuite, parent string) []TestCase {
+	name := suite.Name
+	if suite.XMLName.Local == "testsuites" || len(name) == 0 {
+		name = parent
+	}
+	tests := []TestCase{}
+	for _, c := range suite.Cases {
+		test := TestCase{
+			Suite:     name,
+			ClassName: c.ClassName,
+			Name:      c.Name,
+			Duration:  junitDuration(c.Time),
+			Status:    StatusPassed,
+			Attempts:  1,
+		}
+		if len(test.ClassName) == 0 {
+			test.ClassName = name
+		}
+		switch {
+		case c.Failure != nil:
+			test.Status, test.Message, test.Details = StatusFailed, c.Failure.Message, strings.TrimSpace(c.Failure.Content)
+		case c.Error != nil:
+			test.Status, test.Message, test.Details = StatusError, c.Error.Message, strings.TrimSpace(c.Error.Content)
+		case c.Skipped != nil:
+			test.Status, test.Message = StatusSkipped, c.Skipped.Message
+		}
+		if flaky := append(c.Flaky, c.FlakyErr...); len(flaky) > 0 {
+			// the test passed in a rerun
+			test.Flaky, test.Attempts = true, 1+len(flaky)
+			test.Message, test.Details = flaky[0].Message, strings.TrimSpace(flaky[0].Content)
+		}
+		// the test failed in all reruns
+		test.Attempts += len(c.Rerun) + len(c.RerunErr)
+		tests = append(tests, test)
+	}
+	for _, child := range suite.Suites {
+		tests = append(tests, junitTests(child, name)...)
+	}
+	return tests
+}
+
+func junitDuration(value string) time.Duration {
+	seconds, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(value), ",", ""), 64)
+	if err != nil {
+		return 0
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
+
+type junitOutputSuites struct {
+	XMLName  xml.Name           `xml:"testsuites"`
+	Tests    int                `xml:"tests,attr"`
+	Failures int                `xml:"failures,attr"`
+	Errors   int                `xml:"errors,attr"`
+	Skipped  int                `xml:"skipped,attr"`
+	Time     string             `xml:"time,attr"`
+	Suites   []junitOutputSuite `xml:"testsuite"`
+}
+
+type junitOutputSuite struct {
+	Name     string            `xml:"name,attr"`
+	Tests    int               `xml:"tests,attr"`
+	Failures int               `xml:"failures,attr"`
+	Errors   int               `xml:"errors,attr"`
+	Skipped  int               `xml:"skipped,attr"`
+	Time     string            `xml:"time,attr"`
+	Cases    []junitOutputCase `xml:"testcase"`
+}
+
+type junitOutputCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitProblem `xml:"failure,omitempty"`
+	Error     *junitProblem `xml:"error,omitempty"`
+	Skipped   *junitProblem `xml:"skipped,omitempty"`
+	Flaky     *junitProblem `xml:"flakyFailure,omitempty"`
+}
+
+// WriteJUnit renders the test cases as JUnit XML report, flaky test cases are reported as in the format of the Maven Surefire plugin
+func WriteJUnit(tests []TestCase) ([]byte, error) {
+	report := junitOutputSuites{}
+	suites := map[string]int{}
+	var total time.Duration
+	durations := map[string]time.Duration{}
+	for _, test := range tests {
+		index, ok := suites[test.Suite]
+		if !ok {
+			index = len(report.Suites)
+			suites[test.Suite] = index
+			report.Suites = append(report.Suites, junitOutputSuite{Name: test.Suite})
+		}
+		suite := &report.Suites[index]
+		testCase := junitOutputCase{Name: test.Name, ClassName: test.ClassName, Time: seconds(test.Duration)}
+		problem := &junitProblem{Message: test.Message, Content: test.Details}
+		switch test.Status {
+		case StatusFailed:
+			testCase.Failure = problem
+			suite.Failures++
+		case StatusError:
+			testCase.Error = problem
+			suite.Errors++
+		case StatusSkipped:
+			testCase.Skipped = &junitProblem{Message: test.Message}
+			suite.Skipped++
+		default:
+			if test.Flaky {
+				testCase.Flaky = problem
+			}
+		}
+		suite.Tests++
+		suite.Cases = append(suite.Cases, testCase)
+		durations[test.Suite] += test.Duration
+		total += test.Duration
+	}
+	for i := range report.Suites {
+		suite := &report.Suites[i]
+		suite.Time = seconds(durations[suite.Name])
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Errors += suite.Errors
+		report.Skipped += suite.Skipped
+	}
+	report.Time = seconds(total)
+
+	content, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), content...), nil
+}
+
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
@@ -0,0 +1,133 @@
+//go:build unit
+// +build unit
+
+package testreport
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const surefireReport = `<?xml version="1.0" encoding="UTF-8"?>
+<testsuite name="com.example.CalculatorTest" tests="4" failures="1" errors="0" skipped="1" time="1.5">
+  <testcase name="add" classname="com.example.CalculatorTest" time="0.5"/>
+  <testcase name="divide" classname="com.example.CalculatorTest" time="1.0">
+    <failure message="expected 2 but was 3" type="AssertionError">at com.example.CalculatorTest.divide</failure>
+    <rerunFailure message="expected 2 but was 3" type="AssertionError"/>
+  </testcase>
+  <testcase name="parse" classname="com.example.CalculatorTest" time="0">
+    <skipped message="not implemented"/>
+  </testcase>
+  <testcase name="remote" classname="com.example.CalculatorTest" time="0.2">
+    <flakyFailure message="connection refused" type="IOException">at com.example.CalculatorTest.remote</flakyFailure>
+  </testcase>
+</testsuite>
+`
+
+const jestReport = `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="jest tests" tests="2" failures="0">
+  <testsuite name="ui" tests="2">
+    <testcase classname="App renders" name="App renders" time="0.01"/>
+    <testcase classname="App clicks" name="App clicks" time="0.02"/>
+  </testsuite>
+</testsuites>
+`
+
+func TestParseJUnit(t *testing.T) {
+	t.Run("surefire report with reruns", func(t *testing.T) {
+		tests, err := ParseJUnit([]byte(surefireReport))
+
+		require.NoError(t, err)
+		assert.Equal(t, []TestCase{
+			{Suite: "com.example.CalculatorTest", ClassName: "com.example.CalculatorTest", Name: "add", Duration: 500 * time.Millisecond, Status: StatusPassed, Attempts: 1},
+			{Suite: "com.example.CalculatorTest", ClassName: "com.example.CalculatorTest", Name: "divide", Duration: time.Second, Status: StatusFailed, Message: "expected 2 but was 3", Details: "at com.example.CalculatorTest.divide", Attempts: 2},
+			{Suite: "com.example.CalculatorTest", ClassName: "com.example.CalculatorTest", Name: "parse", Status: StatusSkipped, Message: "not implemented", Attempts: 1},
+			{Suite: "com.example.CalculatorTest", ClassName: "com.example.CalculatorTest", Name: "remote", Duration: 200 * time.Millisecond, Status: StatusPassed, Message: "connection refused", Details: "at com.example.CalculatorTest.remote", Attempts: 2, Flaky: true},
+		}, tests)
+	})
+
+	t.Run("nested test suites", func(t *testing.T) {
+		tests, err := ParseJUnit([]byte(jestReport))
+
+		require.NoError(t, err)
+		assert.Len(t, tests, 2)
+		assert.Equal(t, "ui", tests[0].Suite)
+		assert.Equal(t, "App renders", tests[0].ClassName)
+	})
+
+	t.Run("no JUnit report", func(t *testing.T) {
+		_, err := ParseJUnit([]byte(`<coverage/>`))
+
+		assert.EqualError(t, err, "failed to parse JUnit report: unexpected root element 'coverage'")
+	})
+}
+
+func TestMergeTests(t *testing.T) {
+	first := []TestCase{
+		{Suite: "s", ClassName: "c", Name: "stable", Status: StatusPassed, Attempts: 1},
+		{Suite: "s", ClassName: "c", Name: "flaky", Status: StatusFailed, Message: "timeout", Attempts: 1},
+		{Suite: "s", ClassName: "c", Name: "broken", Status: StatusFailed, Message: "assertion", Attempts: 1},
+	}
+	rerun := []TestCase{
+		{Suite: "s", ClassName: "c", Name: "flaky", Status: StatusPassed, Attempts: 1},
+		{Suite: "s", ClassName: "c", Name: "broken", Status: StatusFailed, Message: "assertion", Attempts: 1},
+	}
+
+	merged := MergeTests(append(first, rerun...))
+
+	assert.Equal(t, []TestCase{
+		{Suite: "s", ClassName: "c", Name: "broken", Status: StatusFailed, Message: "assertion", Attempts: 2},
+		{Suite: "s", ClassName: "c", Name: "flaky", Status: StatusPassed, Message: "timeout", Attempts: 2, Flaky: true},
+		{Suite: "s", ClassName: "c", Name: "stable", Status: StatusPassed, Attempts: 1},
+	}, merged)
+	assert.Equal(t, TestSummary{Total: 3, Passed: 2, Failed: 1, Flaky: 1}, Summarize(merged))
+}
+
+func TestMergeTestsOfOtherSuites(t *testing.T) {
+	api, err := ParseJUnit([]byte(`<testsuite name="api"><testcase classname="Util" name="parses"/></testsuite>`))
+	require.NoError(t, err)
+	ui, err := ParseJUnit([]byte(`<testsuite name="ui"><testcase classname="Util" name="parses"><failure message="wrong format"/></testcase></testsuite>`))
+	require.NoError(t, err)
+
+	merged := MergeTests(append(api, ui...))
+
+	assert.Equal(t, []TestCase{
+		{Suite: "api", ClassName: "Util", Name: "parses", Status: StatusPassed, Attempts: 1},
+		{Suite: "ui", ClassName: "Util", Name: "parses", Status: StatusFailed, Message: "wrong format", Attempts: 1},
+	}, merged)
+	assert.Equal(t, TestSummary{Total: 2, Passed: 1, Failed: 1}, Summarize(merged))
+}
+
+func TestWriteJUnit(t *testing.T) {
+	content, err := WriteJUnit([]TestCase{
+		{Suite: "s", ClassName: "c", Name: "broken", Duration: time.Second, Status: StatusFailed, Message: "assertion", Details: "trace", Attempts: 2},
+		{Suite: "s", ClassName: "c", Name: "flaky", Status: StatusPassed, Message: "timeout", Attempts: 2, Flaky: true},
+		{Suite: "t", ClassName: "d", Name: "skipped", Status: StatusSkipped},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites tests="3" failures="1" errors="0" skipped="1" time="1.000">
+  <testsuite name="s" tests="2" failures="1" errors="0" skipped="0" time="1.000">
+    <testcase name="broken" classname="c" time="1.000">
+      <failure message="assertion">trace</failure>
+    </testcase>
+    <testcase name="flaky" classname="c" time="0.000">
+      <flakyFailure message="timeout"></flakyFailure>
+    </testcase>
+  </testsuite>
+  <testsuite name="t" tests="1" failures="0" errors="0" skipped="1" time="0.000">
+    <testcase name="skipped" classname="d" time="0.000">
+      <skipped></skipped>
+    </testcase>
+  </testsuite>
+</testsuites>`, string(content))
+
+	// the written report can be read again
+	tests, err := ParseJUnit(content)
+	require.NoError(t, err)
+	assert.Equal(t, TestSummary{Total: 3, Passed: 1, Failed: 1, Skipped: 1, Flaky: 1}, Summarize(tests))
+}
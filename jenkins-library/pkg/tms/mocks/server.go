@@ -0,0 +1,394 @@
+//go:build !release
+// +build !release
+
+package mocks
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/SAP/jenkins-library/pkg/tms"
+	"github.com/ghodss/yaml"
+)
+
+// Status values of transport requests in the import queue of a node
+const (
+	StatusInitial   = "initial"
+	StatusSucceeded = "succeeded"
+	StatusError     = "error"
+
+	maxMultipartMemory = 32 << 20
+)
+
+var (
+	nodePath   = regexp.MustCompile(`^/v2/nodes/(\d+)/(mtaExtDescriptors|transportRequests)(?:/(\d+))?$`)
+	importPath = regexp.MustCompile(`^/v2/nodes/(\d+)/transportRequests/import$`)
+	actionPath = regexp.MustCompile(`^/v2/actions/(\d+)$`)
+)
+
+// Node is a transport node of the fake landscape
+type Node struct {
+	tms.Node
+	// ForwardTo are the names of the follow-on nodes of the transport route
+	ForwardTo []string
+	// Queue is the import queue of the node with the status of each transport request
+	Queue map[int64]string
+	// MtaExtDescriptors are the MTA extension descriptors of the node
+	MtaExtDescriptors []tms.MtaExtDescriptor
+}
+
+// File is a file uploaded to the fake service
+type File struct {
+	tms.FileInfo
+	Content   []byte
+	NamedUser string
+}
+
+// Server is a local fake of the API of the Cloud Transport Management service
+type Server struct {
+	*httptest.Server
+
+	mu sync.Mutex
+	// Nodes are the transport nodes by name
+	Nodes map[string]*Node
+	// Files are the uploaded files by ID
+	Files map[int64]*File
+	// TransportRequests are the created transport requests by ID
+	TransportRequests map[int64]*tms.TransportRequest
+	// Actions are the triggered imports by ID
+	Actions map[int64]*tms.Action
+	// ImportErrors are the action status of imports which fail, by node name, e.g. ERROR or FATAL
+	ImportErrors map[string]string
+	// RunningPolls is the number of status requests per action which report the action as still running
+	RunningPolls int
+	// Requests are the requests in the format 'METHOD path' received by the server
+	Requests []string
+
+	polls  map[int64]int
+	nextID int64
+}
+
+// NewServer starts a fake Cloud Transport Management service. The server is closed when Close is called.
+func NewServer() *Server {
+	s := &Server{
+		Nodes:             map[string]*Node{},
+		Files:             map[int64]*File{},
+		TransportRequests: map[int64]*tms.TransportRequest{},
+		Actions:           map[int64]*tms.Action{},
+		ImportErrors:      map[string]string{},
+		polls:             map[int64]int{},
+		nextID:            1000,
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// ServiceKey returns a service key of the fake service
+func (s *Server) ServiceKey() string {
+	return fmt.Sprintf(`{"uaa": {"url": "%[1]v", "clientid": "client", "clientsecret": "secret"}, "uri": "%[1]v"}`, s.URL)
+}
+
+// AddNode adds a transport node, which forwards imported transport requests to the given follow-on nodes
+func (s *Server) AddNode(name string, forwardTo ...string) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	node := &Node{Node: tms.Node{Id: s.newID(), Name: name}, ForwardTo: forwardTo, Queue: map[int64]string{}}
+	s.Nodes[name] = node
+	return node.Id
+}
+
+// TransportRequestStatus returns the status of the transport request in the import queue of the node
+func (s *Server) TransportRequestStatus(nodeName string, transportRequestID int64) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	node, ok := s.Nodes[nodeName]
+	if !ok {
+		return "", false
+	}
+	status, ok := node.Queue[transportRequestID]
+	return status, ok
+}
+
+// MtaExtDescriptors returns the MTA extension descriptors of the node
+func (s *Server) MtaExtDescriptors(nodeName string) []tms.MtaExtDescriptor {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if node, ok := s.Nodes[nodeName]; ok {
+		return append([]tms.MtaExtDescriptor{}, node.MtaExtDescriptors...)
+	}
+	return nil
+}
+
+func (s *Server) newID() int64 {
+	s.nextID++
+	return s.nextID
+}
+
+func (s *Server) nodeByID(id string) *Node {
+	for _, node := range s.Nodes {
+		if strconv.FormatInt(node.Id, 10) == id {
+			return node
+		}
+	}
+	return nil
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Requests = append(s.Requests, r.Method+" "+r.URL.Path)
+
+	if strings.TrimSuffix(r.URL.Path, "/") == "/oauth/token" {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"access_token": "token", "token_type": "bearer", "expires_in": 3600})
+		return
+	}
+	if !strings.EqualFold(r.Header.Get("Authorization"), "Bearer token") {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	switch {
+	case r.URL.Path == "/v2/nodes" && r.Method == http.MethodGet:
+		nodes := []tms.Node{}
+		for _, node := range s.Nodes {
+			nodes = append(nodes, node.Node)
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"nodes": nodes})
+	case r.URL.Path == "/v2/files/upload" && r.Method == http.MethodPost:
+		s.handleFileUpload(w, r)
+	case (r.URL.Path == "/v2/nodes/upload" || r.URL.Path == "/v2/nodes/export") && r.Method == http.MethodPost:
+		s.handleNodeUpload(w, r, strings.HasSuffix(r.URL.Path, "/export"))
+	case importPath.MatchString(r.URL.Path) && r.Method == http.MethodPost:
+		s.handleImport(w, r, s.nodeByID(importPath.FindStringSubmatch(r.URL.Path)[1]))
+	case actionPath.MatchString(r.URL.Path) && r.Method == http.MethodGet:
+		s.handleAction(w, actionPath.FindStringSubmatch(r.URL.Path)[1])
+	case nodePath.MatchString(r.URL.Path):
+		match := nodePath.FindStringSubmatch(r.URL.Path)
+		node := s.nodeByID(match[1])
+		if node == nil {
+			writeJSON(w, http.StatusNotFound, map[string]string{"errorMessage": "node not found"})
+			return
+		}
+		if match[2] == "transportRequests" && r.Method == http.MethodGet {
+			s.handleImportQueue(w, r, node)
+			return
+		}
+		s.handleMtaExtDescriptors(w, r, node, match[3])
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (s *Server) handleFileUpload(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseMultipartForm(maxMultipartMemory); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"errorMessage": err.Error()})
+		return
+	}
+	content, name, err := formFile(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"errorMessage": err.Error()})
+		return
+	}
+	file := &File{FileInfo: tms.FileInfo{Id: s.newID(), Name: name}, Content: content, NamedUser: r.FormValue("namedUser")}
+	s.Files[file.Id] = file
+	writeJSON(w, http.StatusCreated, file.FileInfo)
+}
+
+// handleNodeUpload adds the uploaded file as new transport request to the import queue of the node,
+// an export adds it to the import queues of the follow-on nodes
+func (s *Server) handleNodeUpload(w http.ResponseWriter, r *http.Request, export bool) {
+	var request tms.NodeUploadRequestEntity
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"errorMessage": err.Error()})
+		return
+	}
+	node, ok := s.Nodes[request.NodeName]
+	if !ok {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"errorMessage": fmt.Sprintf("node %v does not exist", request.NodeName)})
+		return
+	}
+	for _, entry := range request.Entries {
+		if id, err := strconv.ParseInt(entry.Uri, 10, 64); err != nil || s.Files[id] == nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"errorMessage": fmt.Sprintf("file %v does not exist", entry.Uri)})
+			return
+		}
+	}
+	targets := []*Node{node}
+	if export {
+		targets = s.followOnNodes(node)
+		if len(targets) == 0 {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"errorMessage": fmt.Sprintf("node %v has no follow-on nodes", node.Name)})
+			return
+		}
+	}
+
+	transportRequest := &tms.TransportRequest{Id: s.newID(), Description: request.Description, Status: StatusInitial}
+	s.TransportRequests[transportRequest.Id] = transportRequest
+	response := tms.NodeUploadResponseEntity{TransportRequestId: transportRequest.Id, TransportRequestDescription: transportRequest.Description}
+	for _, target := range targets {
+		target.Queue[transportRequest.Id] = StatusInitial
+		response.QueueEntries = append(response.QueueEntries, tms.QueueEntry{Id: s.newID(), NodeId: target.Id, NodeName: target.Name})
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) handleImportQueue(w http.ResponseWriter, r *http.Request, node *Node) {
+	status := r.URL.Query().Get("status")
+	transportRequests := []tms.TransportRequest{}
+	for id, queueStatus := range node.Queue {
+		if len(status) == 0 || status == queueStatus {
+			transportRequests = append(transportRequests, tms.TransportRequest{Id: id, Description: s.TransportRequests[id].Description, Status: queueStatus})
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"transportRequests": transportRequests})
+}
+
+// handleImport imports the transport requests into the node and forwards them to the follow-on nodes,
+// unless the import into the node is configured to fail
+func (s *Server) handleImport(w http.ResponseWriter, r *http.Request, node *Node) {
+	if node == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"errorMessage": "node not found"})
+		return
+	}
+	var request tms.ImportRequestEntity
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"errorMessage": err.Error()})
+		return
+	}
+	for _, id := range request.TransportRequests {
+		if node.Queue[id] != StatusInitial {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"errorMessage": fmt.Sprintf("transport request %v is not in the import queue of node %v", id, node.Name)})
+			return
+		}
+	}
+
+	action := &tms.Action{Id: s.newID(), Type: "IMPORT", Status: tms.ActionStatusSucceeded}
+	if status, fails := s.ImportErrors[node.Name]; fails {
+		action.Status = status
+	}
+	s.Actions[action.Id] = action
+	for _, id := range request.TransportRequests {
+		if action.Status != tms.ActionStatusSucceeded {
+			node.Queue[id] = StatusError
+			continue
+		}
+		node.Queue[id] = StatusSucceeded
+		for _, followOnNode := range s.followOnNodes(node) {
+			followOnNode.Queue[id] = StatusInitial
+		}
+	}
+	writeJSON(w, http.StatusOK, tms.ImportResponseEntity{ActionId: action.Id})
+}
+
+func (s *Server) handleAction(w http.ResponseWriter, id string) {
+	actionID, _ := strconv.ParseInt(id, 10, 64)
+	action, ok := s.Actions[actionID]
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]string{"errorMessage": "action not found"})
+		return
+	}
+	result := *action
+	if s.polls[actionID] < s.RunningPolls {
+		result.Status = "RUNNING"
+	}
+	s.polls[actionID]++
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) handleMtaExtDescriptors(w http.ResponseWriter, r *http.Request, node *Node, descriptorID string) {
+	switch {
+	case r.Method == http.MethodGet && len(descriptorID) == 0:
+		mtaID, mtaVersion := r.URL.Query().Get("mtaId"), r.URL.Query().Get("mtaVersion")
+		descriptors := []tms.MtaExtDescriptor{}
+		for _, descriptor := range node.MtaExtDescriptors {
+			if (len(mtaID) == 0 || descriptor.MtaId == mtaID) && (len(mtaVersion) == 0 || mtaVersion == "*" || descriptor.MtaVersion == mtaVersion) {
+				descriptors = append(descriptors, descriptor)
+			}
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"mtaExtDescriptors": descriptors})
+	case r.Method == http.MethodPost && len(descriptorID) == 0:
+		descriptor, err := readMtaExtDescriptor(r)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"errorMessage": err.Error()})
+			return
+		}
+		descriptor.Id = s.newID()
+		node.MtaExtDescriptors = append(node.MtaExtDescriptors, descriptor)
+		writeJSON(w, http.StatusCreated, descriptor)
+	case r.Method == http.MethodPut && len(descriptorID) > 0:
+		for i, existing := range node.MtaExtDescriptors {
+			if strconv.FormatInt(existing.Id, 10) != descriptorID {
+				continue
+			}
+			descriptor, err := readMtaExtDescriptor(r)
+			if err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"errorMessage": err.Error()})
+				return
+			}
+			descriptor.Id = existing.Id
+			node.MtaExtDescriptors[i] = descriptor
+			writeJSON(w, http.StatusOK, descriptor)
+			return
+		}
+		writeJSON(w, http.StatusNotFound, map[string]string{"errorMessage": "MTA extension descriptor not found"})
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) followOnNodes(node *Node) []*Node {
+	nodes := []*Node{}
+	for _, name := range node.ForwardTo {
+		if followOnNode, ok := s.Nodes[name]; ok {
+			nodes = append(nodes, followOnNode)
+		}
+	}
+	return nodes
+}
+
+// readMtaExtDescriptor reads the uploaded MTA extension descriptor, the IDs of the descriptor and of the extended MTA are taken from its content
+func readMtaExtDescriptor(r *http.Request) (tms.MtaExtDescriptor, error) {
+	if err := r.ParseMultipartForm(maxMultipartMemory); err != nil {
+		return tms.MtaExtDescriptor{}, err
+	}
+	content, _, err := formFile(r)
+	if err != nil {
+		return tms.MtaExtDescriptor{}, err
+	}
+	var mtaExt struct {
+		ID      string `json:"ID"`
+		Extends string `json:"extends"`
+	}
+	if err := yaml.Unmarshal(content, &mtaExt); err != nil || len(mtaExt.ID) == 0 || len(mtaExt.Extends) == 0 {
+		return tms.MtaExtDescriptor{}, fmt.Errorf("invalid MTA extension descriptor")
+	}
+	return tms.MtaExtDescriptor{
+		Description: r.FormValue("description"),
+		MtaId:       mtaExt.Extends,
+		MtaExtId:    mtaExt.ID,
+		MtaVersion:  r.FormValue("mtaVersion"),
+	}, nil
+}
+
+func formFile(r *http.Request) ([]byte, string, error) {
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		return nil, "", err
+	}
+	defer file.Close()
+	content, err := io.ReadAll(file)
+	return content, header.Filename, err
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -11,6 +11,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	piperHttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/log"
@@ -49,30 +50,43 @@ func (communicationInstance *CommunicationInstance) getOAuthToken() (string, err
 		communicationInstance.logger.Infof("uaaUrl: %v, clientId: %v", communicationInstance.uaaUrl, communicationInstance.clientId)
 	}
 
-	encodedUsernameColonPassword := b64.StdEncoding.EncodeToString([]byte(communicationInstance.clientId + ":" + communicationInstance.clientSecret))
-	log.RegisterSecret(encodedUsernameColonPassword)
-	header := http.Header{}
-	header.Add("Content-Type", "application/x-www-form-urlencoded")
-	header.Add("Authorization", "Basic "+encodedUsernameColonPassword)
+	// the token is shared with other clients and the following steps of the pipeline run
+	options := piperHttp.OAuth2Options{TokenURL: communicationInstance.uaaUrl, ClientID: communicationInstance.clientId, ClientSecret: communicationInstance.clientSecret}
+	token, err := piperHttp.CachedOAuth2Token(options, "password", func() (piperHttp.OAuth2Token, error) {
+		encodedUsernameColonPassword := b64.StdEncoding.EncodeToString([]byte(communicationInstance.clientId + ":" + communicationInstance.clientSecret))
+		log.RegisterSecret(encodedUsernameColonPassword)
+		header := http.Header{}
+		header.Add("Content-Type", "application/x-www-form-urlencoded")
+		header.Add("Authorization", "Basic "+encodedUsernameColonPassword)
 
-	urlFormData := url.Values{
-		"username":   {communicationInstance.clientId},
-		"password":   {communicationInstance.clientSecret},
-		"grant_type": {"password"},
-	}
+		urlFormData := url.Values{
+			"username":   {communicationInstance.clientId},
+			"password":   {communicationInstance.clientSecret},
+			"grant_type": {"password"},
+		}
 
-	data, err := sendRequest(communicationInstance, http.MethodPost, "/oauth/token/?grant_type=client_credentials&response_type=token", strings.NewReader(urlFormData.Encode()), header, http.StatusOK, true)
+		requestTime := time.Now()
+		data, err := sendRequest(communicationInstance, http.MethodPost, "/oauth/token/?grant_type=client_credentials&response_type=token", strings.NewReader(urlFormData.Encode()), header, http.StatusOK, true)
+		if err != nil {
+			return piperHttp.OAuth2Token{}, err
+		}
+
+		var token xsuaa.AuthToken
+		json.Unmarshal(data, &token)
+		log.RegisterSecret(token.AccessToken)
+		oauthToken := piperHttp.OAuth2Token{AccessToken: token.AccessToken, TokenType: token.TokenType}
+		if token.ExpiresIn > 0 {
+			oauthToken.ExpiresAt = requestTime.Add(token.ExpiresIn * time.Second)
+		}
+		return oauthToken, nil
+	})
 	if err != nil {
 		return "", err
 	}
 
-	var token xsuaa.AuthToken
-	json.Unmarshal(data, &token)
-
 	if communicationInstance.isVerbose {
 		communicationInstance.logger.Info("OAuth Token retrieved successfully")
 	}
-	log.RegisterSecret(token.AccessToken)
 	return token.TokenType + " " + token.AccessToken, nil
 }
 
@@ -367,3 +381,79 @@ func upload(communicationInstance *CommunicationInstance, uploadRequestData pipe
 	defer response.Body.Close()
 	return data, nil
 }
+
+func (communicationInstance *CommunicationInstance) GetTransportRequests(nodeId int64, status string) ([]TransportRequest, error) {
+	if communicationInstance.isVerbose {
+		communicationInstance.logger.Info("Obtaining transport requests started")
+		communicationInstance.logger.Infof("tmsUrl: %v, nodeId: %v, status: %v", communicationInstance.tmsUrl, nodeId, status)
+	}
+
+	header := http.Header{}
+	header.Add("Content-Type", "application/json")
+
+	var aTransportRequests []TransportRequest
+	urlPathAndQuery := fmt.Sprintf("/v2/nodes/%v/transportRequests", nodeId)
+	if status != "" {
+		urlPathAndQuery += "?status=" + url.QueryEscape(status)
+	}
+	data, err := sendRequest(communicationInstance, http.MethodGet, urlPathAndQuery, nil, header, http.StatusOK, false)
+	if err != nil {
+		return aTransportRequests, err
+	}
+
+	var getTransportRequestsResponse transportRequests
+	json.Unmarshal(data, &getTransportRequestsResponse)
+	aTransportRequests = getTransportRequestsResponse.TransportRequests
+	if communicationInstance.isVerbose {
+		communicationInstance.logger.Info("Transport requests obtained successfully")
+	}
+	return aTransportRequests, nil
+}
+
+func (communicationInstance *CommunicationInstance) ImportTransportRequests(nodeId int64, transportRequestIds []int64, namedUser string) (ImportResponseEntity, error) {
+	if communicationInstance.isVerbose {
+		communicationInstance.logger.Info("Import of transport requests started")
+		communicationInstance.logger.Infof("tmsUrl: %v, nodeId: %v, transportRequestIds: %v, namedUser: %v", communicationInstance.tmsUrl, nodeId, transportRequestIds, namedUser)
+	}
+
+	header := http.Header{}
+	header.Add("Content-Type", "application/json")
+
+	var importResponseEntity ImportResponseEntity
+	body := ImportRequestEntity{NamedUser: namedUser, TransportRequests: transportRequestIds}
+	bodyBytes, errMarshaling := json.Marshal(body)
+	if errMarshaling != nil {
+		return importResponseEntity, errors.Wrapf(errMarshaling, "unable to marshal request body %v", body)
+	}
+
+	data, errSendRequest := sendRequest(communicationInstance, http.MethodPost, fmt.Sprintf("/v2/nodes/%v/transportRequests/import", nodeId), bytes.NewReader(bodyBytes), header, http.StatusOK, false)
+	if errSendRequest != nil {
+		return importResponseEntity, errSendRequest
+	}
+
+	json.Unmarshal(data, &importResponseEntity)
+	communicationInstance.logger.Infof("Import of transport requests %v into node %v triggered, actionId: %v", transportRequestIds, nodeId, importResponseEntity.ActionId)
+	return importResponseEntity, nil
+}
+
+func (communicationInstance *CommunicationInstance) GetAction(actionId int64) (Action, error) {
+	if communicationInstance.isVerbose {
+		communicationInstance.logger.Info("Obtaining action started")
+		communicationInstance.logger.Infof("tmsUrl: %v, actionId: %v", communicationInstance.tmsUrl, actionId)
+	}
+
+	header := http.Header{}
+	header.Add("Content-Type", "application/json")
+
+	var action Action
+	data, err := sendRequest(communicationInstance, http.MethodGet, fmt.Sprintf("/v2/actions/%v", actionId), nil, header, http.StatusOK, false)
+	if err != nil {
+		return action, err
+	}
+
+	json.Unmarshal(data, &action)
+	if communicationInstance.isVerbose {
+		communicationInstance.logger.Infof("Action %v has status %v", action.Id, action.Status)
+	}
+	return action, nil
+}
@@ -97,6 +97,7 @@ func (um *uploaderMock) SetOptions(options piperHttp.ClientOptions) {
 func TestGetOAuthToken(t *testing.T) {
 	logger := log.Entry().WithField("package", "SAP/jenkins-library/pkg/tms_test")
 	t.Run("test success", func(t *testing.T) {
+		t.Cleanup(piperHttp.ResetTokenCache)
 		uploaderMock := uploaderMock{responseBody: `{"token_type":"bearer","access_token":"testOAuthToken","expires_in":54321}`, httpStatusCode: http.StatusOK}
 		communicationInstance := CommunicationInstance{uaaUrl: "https://dummy.sap.com", clientId: "testClientId", clientSecret: "testClientSecret", httpClient: &uploaderMock, logger: logger, isVerbose: false}
 
@@ -111,7 +112,24 @@ func TestGetOAuthToken(t *testing.T) {
 		assert.Equal(t, "bearer testOAuthToken", token, "Obtained token incorrect")
 	})
 
+	t.Run("test token is reused", func(t *testing.T) {
+		t.Cleanup(piperHttp.ResetTokenCache)
+		firstMock := uploaderMock{responseBody: `{"token_type":"bearer","access_token":"testOAuthToken","expires_in":54321}`, httpStatusCode: http.StatusOK}
+		communicationInstance := CommunicationInstance{uaaUrl: "https://dummy.sap.com", clientId: "testClientId", clientSecret: "testClientSecret", httpClient: &firstMock, logger: logger, isVerbose: false}
+		_, err := communicationInstance.getOAuthToken()
+		assert.NoError(t, err, "Error occurred, but none expected")
+		otherMock := uploaderMock{isTechnicalErrorExpected: true}
+		otherInstance := CommunicationInstance{uaaUrl: "https://dummy.sap.com", clientId: "testClientId", clientSecret: "testClientSecret", httpClient: &otherMock, logger: logger, isVerbose: false}
+
+		token, err := otherInstance.getOAuthToken()
+
+		assert.NoError(t, err, "Error occurred, but none expected")
+		assert.Empty(t, otherMock.urlCalled, "Token must not be requested again")
+		assert.Equal(t, "bearer testOAuthToken", token, "Obtained token incorrect")
+	})
+
 	t.Run("test error", func(t *testing.T) {
+		t.Cleanup(piperHttp.ResetTokenCache)
 		uploaderMock := uploaderMock{responseBody: `Bad request provided`, httpStatusCode: http.StatusBadRequest}
 		communicationInstance := CommunicationInstance{uaaUrl: "https://dummy.sap.com", clientId: "testClientId", clientSecret: "testClientSecret", httpClient: &uploaderMock, logger: logger, isVerbose: false}
 
@@ -601,6 +619,7 @@ func TestSendRequest(t *testing.T) {
 
 func TestNewCommunicationInstance(t *testing.T) {
 	t.Run("test success", func(t *testing.T) {
+		t.Cleanup(piperHttp.ResetTokenCache)
 		uploaderMock := uploaderMock{responseBody: `{"token_type":"bearer","access_token":"testOAuthToken","expires_in":54321}`, httpStatusCode: http.StatusOK}
 		communicationInstance, err := NewCommunicationInstance(&uploaderMock, "https://tms.dummy.sap.com", "https://dummy.sap.com", "testClientId", "testClientSecret", false, piperHttp.ClientOptions{})
 
@@ -613,6 +632,7 @@ func TestNewCommunicationInstance(t *testing.T) {
 	})
 
 	t.Run("test error", func(t *testing.T) {
+		t.Cleanup(piperHttp.ResetTokenCache)
 		uploaderMock := uploaderMock{responseBody: `Bad request provided`, httpStatusCode: http.StatusBadRequest}
 		_, err := NewCommunicationInstance(&uploaderMock, "https://tms.dummy.sap.com", "https://dummy.sap.com", "testClientId", "testClientSecret", false, piperHttp.ClientOptions{})
 
@@ -621,3 +641,68 @@ func TestNewCommunicationInstance(t *testing.T) {
 	})
 
 }
+
+func TestGetTransportRequests(t *testing.T) {
+	logger := log.Entry().WithField("package", "SAP/jenkins-library/pkg/tms_test")
+	t.Run("test success", func(t *testing.T) {
+		getTransportRequestsResponse := `{"transportRequests": [{"id": 42, "description": "Created by Piper", "status": "initial"}]}`
+		uploaderMock := uploaderMock{responseBody: getTransportRequestsResponse, httpStatusCode: http.StatusOK}
+		communicationInstance := CommunicationInstance{tmsUrl: "https://tms.dummy.sap.com", httpClient: &uploaderMock, logger: logger, isVerbose: false}
+
+		transportRequests, err := communicationInstance.GetTransportRequests(1, "initial")
+
+		assert.NoError(t, err, "Error occurred, but none expected")
+		assert.Equal(t, "https://tms.dummy.sap.com/v2/nodes/1/transportRequests?status=initial", uploaderMock.urlCalled, "Called url incorrect")
+		assert.Equal(t, http.MethodGet, uploaderMock.httpMethod, "Http method incorrect")
+		assert.Equal(t, []TransportRequest{{Id: 42, Description: "Created by Piper", Status: "initial"}}, transportRequests, "Transport requests incorrect")
+	})
+
+	t.Run("test error", func(t *testing.T) {
+		uploaderMock := uploaderMock{responseBody: `Bad request provided`, httpStatusCode: http.StatusBadRequest}
+		communicationInstance := CommunicationInstance{tmsUrl: "https://tms.dummy.sap.com", httpClient: &uploaderMock, logger: logger, isVerbose: false}
+
+		_, err := communicationInstance.GetTransportRequests(1, "")
+
+		assert.Error(t, err, "Error expected, but none occurred")
+		assert.Equal(t, "https://tms.dummy.sap.com/v2/nodes/1/transportRequests", uploaderMock.urlCalled, "Called url incorrect")
+	})
+}
+
+func TestImportTransportRequests(t *testing.T) {
+	logger := log.Entry().WithField("package", "SAP/jenkins-library/pkg/tms_test")
+	t.Run("test success", func(t *testing.T) {
+		uploaderMock := uploaderMock{responseBody: `{"actionId": 4711}`, httpStatusCode: http.StatusOK}
+		communicationInstance := CommunicationInstance{tmsUrl: "https://tms.dummy.sap.com/", httpClient: &uploaderMock, logger: logger, isVerbose: false}
+
+		importResponse, err := communicationInstance.ImportTransportRequests(2, []int64{42}, "techUser")
+
+		assert.NoError(t, err, "Error occurred, but none expected")
+		assert.Equal(t, "https://tms.dummy.sap.com/v2/nodes/2/transportRequests/import", uploaderMock.urlCalled, "Called url incorrect")
+		assert.Equal(t, http.MethodPost, uploaderMock.httpMethod, "Http method incorrect")
+		assert.Equal(t, `{"namedUser":"techUser","transportRequests":[42]}`, uploaderMock.requestBody, "Request body incorrect")
+		assert.Equal(t, int64(4711), importResponse.ActionId, "Action id incorrect")
+	})
+
+	t.Run("test error", func(t *testing.T) {
+		uploaderMock := uploaderMock{responseBody: `Bad request provided`, httpStatusCode: http.StatusBadRequest}
+		communicationInstance := CommunicationInstance{tmsUrl: "https://tms.dummy.sap.com", httpClient: &uploaderMock, logger: logger, isVerbose: false}
+
+		_, err := communicationInstance.ImportTransportRequests(2, []int64{42}, "techUser")
+
+		assert.Error(t, err, "Error expected, but none occurred")
+	})
+}
+
+func TestGetAction(t *testing.T) {
+	logger := log.Entry().WithField("package", "SAP/jenkins-library/pkg/tms_test")
+	t.Run("test success", func(t *testing.T) {
+		uploaderMock := uploaderMock{responseBody: `{"id": 4711, "type": "IMPORT", "status": "RUNNING"}`, httpStatusCode: http.StatusOK}
+		communicationInstance := CommunicationInstance{tmsUrl: "https://tms.dummy.sap.com", httpClient: &uploaderMock, logger: logger, isVerbose: true}
+
+		action, err := communicationInstance.GetAction(4711)
+
+		assert.NoError(t, err, "Error occurred, but none expected")
+		assert.Equal(t, "https://tms.dummy.sap.com/v2/actions/4711", uploaderMock.urlCalled, "Called url incorrect")
+		assert.Equal(t, Action{Id: 4711, Type: "IMPORT", Status: "RUNNING"}, action, "Action incorrect")
+	})
+}
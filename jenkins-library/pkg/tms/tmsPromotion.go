@@ -0,0 +1,205 @@
+package tms
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+)
+
+// Status values of TMS actions and of the node results of a promotion
+const (
+	ActionStatusSucceeded = "SUCCEEDED"
+	ActionStatusWarning   = "WARNING"
+	ActionStatusError     = "ERROR"
+	ActionStatusFatal     = "FATAL"
+	NodeStatusSkipped     = "SKIPPED"
+	NodeStatusTimeout     = "TIMEOUT"
+)
+
+// PromotionOptions configures the promotion of a transport request along a transport route
+type PromotionOptions struct {
+	TransportRequestId int64
+	// ImportNodes are the names of the nodes in the order of the transport route in which the transport request is imported
+	ImportNodes []string
+	NamedUser   string
+	// MtaId and MtaVersion are used to look up the MTA extension descriptor applied in each node
+	MtaId        string
+	MtaVersion   string
+	PollInterval time.Duration
+	Timeout      time.Duration
+	Sleep        func(time.Duration)
+}
+
+// NodeResult is the outcome of the import of a transport request into a node
+type NodeResult struct {
+	NodeName           string `json:"nodeName"`
+	NodeId             int64  `json:"nodeId"`
+	TransportRequestId int64  `json:"transportRequestId"`
+	ActionId           int64  `json:"actionId,omitempty"`
+	Status             string `json:"status"`
+	MtaExtDescriptorId int64  `json:"mtaExtDescriptorId,omitempty"`
+	Message            string `json:"message,omitempty"`
+}
+
+// PromoteTransportRequest imports a transport request into all nodes of a transport route one after another.
+// For each node it waits until the transport request is available in the import queue, triggers the import and polls the import status.
+// The promotion stops at the first node in which the import fails; the remaining nodes are reported as skipped.
+func PromoteTransportRequest(communicationInstance CommunicationInterface, options PromotionOptions) ([]NodeResult, error) {
+	results := []NodeResult{}
+	if options.Sleep == nil {
+		options.Sleep = time.Sleep
+	}
+
+	nodes, errGetNodes := communicationInstance.GetNodes()
+	if errGetNodes != nil {
+		log.SetErrorCategory(log.ErrorService)
+		return results, fmt.Errorf("failed to get nodes: %w", errGetNodes)
+	}
+	nodeIds := map[string]int64{}
+	for _, node := range nodes {
+		nodeIds[node.Name] = node.Id
+	}
+	for _, nodeName := range options.ImportNodes {
+		if _, ok := nodeIds[nodeName]; !ok {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return results, fmt.Errorf("node %v of the transport route does not exist", nodeName)
+		}
+	}
+
+	var promotionErr error
+	for _, nodeName := range options.ImportNodes {
+		result := NodeResult{NodeName: nodeName, NodeId: nodeIds[nodeName], TransportRequestId: options.TransportRequestId, Status: NodeStatusSkipped}
+		if promotionErr == nil {
+			promotionErr = importIntoNode(communicationInstance, options, &result)
+		}
+		results = append(results, result)
+	}
+	return results, promotionErr
+}
+
+func importIntoNode(communicationInstance CommunicationInterface, options PromotionOptions, result *NodeResult) error {
+	if len(options.MtaId) > 0 {
+		mtaExtDescriptor, errGetMtaExtDescriptor := communicationInstance.GetMtaExtDescriptor(result.NodeId, options.MtaId, options.MtaVersion)
+		if errGetMtaExtDescriptor != nil {
+			log.SetErrorCategory(log.ErrorService)
+			result.Status = ActionStatusError
+			result.Message = errGetMtaExtDescriptor.Error()
+			return fmt.Errorf("failed to get MTA extension descriptor of node %v: %w", result.NodeName, errGetMtaExtDescriptor)
+		}
+		result.MtaExtDescriptorId = mtaExtDescriptor.Id
+		if mtaExtDescriptor.Id != 0 {
+			log.Entry().Infof("MTA extension descriptor %v (%v) is applied in node %v", mtaExtDescriptor.Id, mtaExtDescriptor.MtaExtId, result.NodeName)
+		}
+	}
+
+	maxPolls := 1
+	if options.PollInterval > 0 {
+		maxPolls = int(options.Timeout / options.PollInterval)
+	}
+
+	// transport requests are forwarded asynchronously along the route, so wait until it arrives in the queue of the node
+	queued := false
+	for poll := 0; !queued; poll++ {
+		queue, errGetTransportRequests := communicationInstance.GetTransportRequests(result.NodeId, "initial")
+		if errGetTransportRequests != nil {
+			log.SetErrorCategory(log.ErrorService)
+			result.Status = ActionStatusError
+			result.Message = errGetTransportRequests.Error()
+			return fmt.Errorf("failed to get import queue of node %v: %w", result.NodeName, errGetTransportRequests)
+		}
+		for _, tr := range queue {
+			if tr.Id == result.TransportRequestId {
+				queued = true
+			}
+		}
+		if !queued {
+			if poll >= maxPolls {
+				log.SetErrorCategory(log.ErrorService)
+				result.Status = NodeStatusTimeout
+				result.Message = "transport request not found in import queue"
+				return fmt.Errorf("transport request %v did not arrive in the import queue of node %v within %v", result.TransportRequestId, result.NodeName, options.Timeout)
+			}
+			options.Sleep(options.PollInterval)
+		}
+	}
+
+	importResponse, errImport := communicationInstance.ImportTransportRequests(result.NodeId, []int64{result.TransportRequestId}, options.NamedUser)
+	if errImport != nil {
+		log.SetErrorCategory(log.ErrorService)
+		result.Status = ActionStatusError
+		result.Message = errImport.Error()
+		return fmt.Errorf("failed to import transport request %v into node %v: %w", result.TransportRequestId, result.NodeName, errImport)
+	}
+	result.ActionId = importResponse.ActionId
+
+	for poll := 0; ; poll++ {
+		action, errGetAction := communicationInstance.GetAction(importResponse.ActionId)
+		if errGetAction != nil {
+			log.SetErrorCategory(log.ErrorService)
+			result.Status = ActionStatusError
+			result.Message = errGetAction.Error()
+			return fmt.Errorf("failed to get status of import into node %v: %w", result.NodeName, errGetAction)
+		}
+		result.Status = action.Status
+		switch action.Status {
+		case ActionStatusSucceeded, ActionStatusWarning:
+			log.Entry().Infof("Import of transport request %v into node %v finished with status %v", result.TransportRequestId, result.NodeName, action.Status)
+			return nil
+		case ActionStatusError, ActionStatusFatal:
+			log.SetErrorCategory(log.ErrorService)
+			return fmt.Errorf("import of transport request %v into node %v failed with status %v", result.TransportRequestId, result.NodeName, action.Status)
+		}
+		if poll >= maxPolls {
+			log.SetErrorCategory(log.ErrorService)
+			result.Status = NodeStatusTimeout
+			return fmt.Errorf("import of transport request %v into node %v did not finish within %v", result.TransportRequestId, result.NodeName, options.Timeout)
+		}
+		options.Sleep(options.PollInterval)
+	}
+}
+
+// CreatePromotionReport creates a step report of the node results of a promotion
+func CreatePromotionReport(results []NodeResult) reporting.ScanReport {
+	successful := true
+	for _, result := range results {
+		if result.Status != ActionStatusSucceeded && result.Status != ActionStatusWarning {
+			successful = false
+		}
+	}
+	report := reporting.ScanReport{
+		ReportTitle: "TMS Transport Route Promotion",
+		Overview: []reporting.OverviewRow{
+			{Description: "Nodes", Details: fmt.Sprint(len(results))},
+		},
+		SuccessfulScan: successful,
+	}
+	if len(results) > 0 {
+		report.Subheaders = []reporting.Subheader{{Description: "Transport request", Details: fmt.Sprint(results[0].TransportRequestId)}}
+	}
+	table := reporting.ScanDetailTable{
+		NoRowsMessage: "No nodes configured",
+		Headers:       []string{"Node", "Status", "Import action", "MTA extension descriptor", "Message"},
+	}
+	for _, result := range results {
+		var style reporting.ColumnStyle = reporting.Grey
+		switch result.Status {
+		case ActionStatusSucceeded:
+			style = reporting.Green
+		case ActionStatusWarning:
+			style = reporting.Yellow
+		case ActionStatusError, ActionStatusFatal, NodeStatusTimeout:
+			style = reporting.Red
+		}
+		row := reporting.ScanRow{}
+		row.AddColumn(result.NodeName, 0)
+		row.AddColumn(result.Status, style)
+		row.AddColumn(result.ActionId, 0)
+		row.AddColumn(result.MtaExtDescriptorId, 0)
+		row.AddColumn(result.Message, 0)
+		table.Rows = append(table.Rows, row)
+	}
+	report.DetailTable = table
+	return report
+}
@@ -0,0 +1,148 @@
+//go:build unit
+// +build unit
+
+package tms
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type promotionCommunicationMock struct {
+	CommunicationInterface
+	nodes []Node
+	// queueAfterPolls is the number of queue polls before the transport request arrives in the queue
+	queueAfterPolls int
+	queuePolls      int
+	actionStatuses  []string
+	actionPolls     int
+	extDescriptors  map[int64]MtaExtDescriptor
+	imports         []int64
+	importErr       error
+}
+
+func (m *promotionCommunicationMock) GetNodes() ([]Node, error) {
+	return m.nodes, nil
+}
+
+func (m *promotionCommunicationMock) GetMtaExtDescriptor(nodeId int64, mtaId, mtaVersion string) (MtaExtDescriptor, error) {
+	return m.extDescriptors[nodeId], nil
+}
+
+func (m *promotionCommunicationMock) GetTransportRequests(nodeId int64, status string) ([]TransportRequest, error) {
+	m.queuePolls++
+	if m.queuePolls <= m.queueAfterPolls {
+		return []TransportRequest{{Id: 1}}, nil
+	}
+	return []TransportRequest{{Id: 1}, {Id: 42}}, nil
+}
+
+func (m *promotionCommunicationMock) ImportTransportRequests(nodeId int64, transportRequestIds []int64, namedUser string) (ImportResponseEntity, error) {
+	if m.importErr != nil {
+		return ImportResponseEntity{}, m.importErr
+	}
+	m.imports = append(m.imports, nodeId)
+	m.actionPolls = 0
+	return ImportResponseEntity{ActionId: 100 + nodeId}, nil
+}
+
+func (m *promotionCommunicationMock) GetAction(actionId int64) (Action, error) {
+	status := m.actionStatuses[len(m.actionStatuses)-1]
+	if m.actionPolls < len(m.actionStatuses) {
+		status = m.actionStatuses[m.actionPolls]
+	}
+	m.actionPolls++
+	return Action{Id: actionId, Status: status}, nil
+}
+
+func TestPromoteTransportRequest(t *testing.T) {
+	nodes := []Node{{Id: 1, Name: "DEV"}, {Id: 2, Name: "QA"}, {Id: 3, Name: "PROD"}}
+	options := PromotionOptions{
+		TransportRequestId: 42,
+		ImportNodes:        []string{"QA", "PROD"},
+		NamedUser:          "techUser",
+		PollInterval:       time.Second,
+		Timeout:            5 * time.Second,
+	}
+
+	t.Run("success along the route", func(t *testing.T) {
+		mock := &promotionCommunicationMock{nodes: nodes, queueAfterPolls: 2, actionStatuses: []string{"RUNNING", "SUCCEEDED"}, extDescriptors: map[int64]MtaExtDescriptor{3: {Id: 7, MtaExtId: "prod.mtaext"}}}
+		sleeps := 0
+		opts := options
+		opts.MtaId = "com.sap.app"
+		opts.MtaVersion = "*"
+		opts.Sleep = func(time.Duration) { sleeps++ }
+
+		results, err := PromoteTransportRequest(mock, opts)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{2, 3}, mock.imports)
+		assert.Equal(t, []NodeResult{
+			{NodeName: "QA", NodeId: 2, TransportRequestId: 42, ActionId: 102, Status: ActionStatusSucceeded},
+			{NodeName: "PROD", NodeId: 3, TransportRequestId: 42, ActionId: 103, Status: ActionStatusSucceeded, MtaExtDescriptorId: 7},
+		}, results)
+		// two polls until the transport request is queued and one poll per import while running
+		assert.Equal(t, 4, sleeps)
+	})
+
+	t.Run("failed import skips remaining nodes", func(t *testing.T) {
+		mock := &promotionCommunicationMock{nodes: nodes, actionStatuses: []string{"FATAL"}}
+		opts := options
+		opts.Sleep = func(time.Duration) {}
+
+		results, err := PromoteTransportRequest(mock, opts)
+
+		assert.EqualError(t, err, "import of transport request 42 into node QA failed with status FATAL")
+		assert.Equal(t, ActionStatusFatal, results[0].Status)
+		assert.Equal(t, NodeStatusSkipped, results[1].Status)
+		assert.False(t, CreatePromotionReport(results).SuccessfulScan)
+	})
+
+	t.Run("transport request does not arrive in queue", func(t *testing.T) {
+		mock := &promotionCommunicationMock{nodes: nodes, queueAfterPolls: 100, actionStatuses: []string{"SUCCEEDED"}}
+		opts := options
+		opts.Sleep = func(time.Duration) {}
+
+		results, err := PromoteTransportRequest(mock, opts)
+
+		assert.EqualError(t, err, "transport request 42 did not arrive in the import queue of node QA within 5s")
+		assert.Equal(t, NodeStatusTimeout, results[0].Status)
+		assert.Equal(t, 6, mock.queuePolls)
+	})
+
+	t.Run("import does not finish", func(t *testing.T) {
+		mock := &promotionCommunicationMock{nodes: nodes, actionStatuses: []string{"RUNNING"}}
+		opts := options
+		opts.Sleep = func(time.Duration) {}
+
+		results, err := PromoteTransportRequest(mock, opts)
+
+		assert.EqualError(t, err, "import of transport request 42 into node QA did not finish within 5s")
+		assert.Equal(t, NodeStatusTimeout, results[0].Status)
+	})
+
+	t.Run("import request fails", func(t *testing.T) {
+		mock := &promotionCommunicationMock{nodes: nodes, importErr: errors.New("forbidden")}
+		opts := options
+		opts.Sleep = func(time.Duration) {}
+
+		results, err := PromoteTransportRequest(mock, opts)
+
+		assert.EqualError(t, err, "failed to import transport request 42 into node QA: forbidden")
+		assert.Equal(t, "forbidden", results[0].Message)
+	})
+
+	t.Run("unknown node", func(t *testing.T) {
+		mock := &promotionCommunicationMock{nodes: nodes}
+		opts := options
+		opts.ImportNodes = []string{"QA", "UNKNOWN"}
+
+		_, err := PromoteTransportRequest(mock, opts)
+
+		assert.EqualError(t, err, "node UNKNOWN of the transport route does not exist")
+		assert.Empty(t, mock.imports)
+	})
+}
@@ -0,0 +1,67 @@
+//go:build unit
+// +build unit
+
+package tms_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	piperHttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/tms"
+	"github.com/SAP/jenkins-library/pkg/tms/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommunicationWithServer(t *testing.T) {
+	server := mocks.NewServer()
+	defer server.Close()
+	server.AddNode("DEV", "QA")
+	server.AddNode("QA", "PROD")
+	server.AddNode("PROD")
+	server.ImportErrors["PROD"] = tms.ActionStatusError
+	server.RunningPolls = 1
+	dir := t.TempDir()
+	mtaPath := filepath.Join(dir, "example.mtar")
+	require.NoError(t, os.WriteFile(mtaPath, []byte("mtar"), 0o644))
+	extPath := filepath.Join(dir, "qa.mtaext")
+	require.NoError(t, os.WriteFile(extPath, []byte("_schema-version: '3.1'\nID: example-qa\nextends: example\n"), 0o644))
+
+	communicationInstance, err := tms.NewCommunicationInstance(&piperHttp.Client{}, server.URL, server.URL, "client", "secret", false, piperHttp.ClientOptions{})
+	require.NoError(t, err)
+
+	fileInfo, err := communicationInstance.UploadFile(mtaPath, "piper")
+	require.NoError(t, err)
+	assert.Equal(t, "example.mtar", fileInfo.Name)
+	qaNodeID := server.Nodes["QA"].Id
+	_, err = communicationInstance.UploadMtaExtDescriptorToNode(qaNodeID, extPath, "1.0.0", "QA settings", "piper")
+	require.NoError(t, err)
+	uploadResponse, err := communicationInstance.UploadFileToNode(fileInfo, "DEV", "Example 1.0.0", "piper")
+	require.NoError(t, err)
+
+	results, err := tms.PromoteTransportRequest(communicationInstance, tms.PromotionOptions{
+		TransportRequestId: uploadResponse.TransportRequestId,
+		ImportNodes:        []string{"DEV", "QA", "PROD"},
+		NamedUser:          "piper",
+		MtaId:              "example",
+		MtaVersion:         "1.0.0",
+		PollInterval:       time.Millisecond,
+		Timeout:            time.Second,
+	})
+
+	assert.EqualError(t, err, fmt.Sprintf("import of transport request %v into node PROD failed with status ERROR", uploadResponse.TransportRequestId))
+	require.Len(t, results, 3)
+	assert.Equal(t, tms.ActionStatusSucceeded, results[0].Status)
+	assert.Equal(t, tms.ActionStatusSucceeded, results[1].Status)
+	assert.NotZero(t, results[1].MtaExtDescriptorId)
+	assert.Equal(t, tms.ActionStatusError, results[2].Status)
+	status, _ := server.TransportRequestStatus("QA", uploadResponse.TransportRequestId)
+	assert.Equal(t, mocks.StatusSucceeded, status)
+	status, _ = server.TransportRequestStatus("PROD", uploadResponse.TransportRequestId)
+	assert.Equal(t, mocks.StatusError, status)
+	assert.Equal(t, "example-qa", server.MtaExtDescriptors("QA")[0].MtaExtId)
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"os"
 	"sort"
 
 	"github.com/SAP/jenkins-library/pkg/command"
@@ -19,6 +20,9 @@ type TmsUtils interface {
 	command.ExecRunner
 	FileExists(filename string) (bool, error)
 	FileRead(path string) ([]byte, error)
+	FileWrite(path string, content []byte, perm os.FileMode) error
+	WriteFile(path string, content []byte, perm os.FileMode) error
+	MkdirAll(path string, perm os.FileMode) error
 }
 
 type uaa struct {
@@ -99,6 +103,31 @@ type Entry struct {
 	Uri string `json:"uri"`
 }
 
+type TransportRequest struct {
+	Id          int64  `json:"id"`
+	Description string `json:"description"`
+	Status      string `json:"status"`
+}
+
+type transportRequests struct {
+	TransportRequests []TransportRequest `json:"transportRequests"`
+}
+
+type ImportRequestEntity struct {
+	NamedUser         string  `json:"namedUser"`
+	TransportRequests []int64 `json:"transportRequests"`
+}
+
+type ImportResponseEntity struct {
+	ActionId int64 `json:"actionId"`
+}
+
+type Action struct {
+	Id     int64  `json:"id"`
+	Type   string `json:"type"`
+	Status string `json:"status"`
+}
+
 type CommunicationInterface interface {
 	GetNodes() ([]Node, error)
 	GetMtaExtDescriptor(nodeId int64, mtaId, mtaVersion string) (MtaExtDescriptor, error)
@@ -107,6 +136,9 @@ type CommunicationInterface interface {
 	UploadFile(file, namedUser string) (FileInfo, error)
 	UploadFileToNode(fileInfo FileInfo, nodeName, description, namedUser string) (NodeUploadResponseEntity, error)
 	ExportFileToNode(fileInfo FileInfo, nodeName, description, namedUser string) (NodeUploadResponseEntity, error)
+	GetTransportRequests(nodeId int64, status string) ([]TransportRequest, error)
+	ImportTransportRequests(nodeId int64, transportRequestIds []int64, namedUser string) (ImportResponseEntity, error)
+	GetAction(actionId int64) (Action, error)
 }
 
 type Options struct {
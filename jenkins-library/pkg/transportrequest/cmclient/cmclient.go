@@ -0,0 +1,95 @@
+package cmclient
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/command"
+	"github.com/pkg/errors"
+)
+
+const (
+	// BackendCTS is the backend type of the cm client for transports in an ABAP system
+	BackendCTS = "CTS"
+	// BackendSOLMAN is the default backend type of the cm client
+	BackendSOLMAN = "SOLMAN"
+
+	// StatusModifiable is the status of transport requests which are not yet released
+	StatusModifiable = "modifiable"
+	// StatusReleased is the status of released transport requests
+	StatusReleased = "released"
+)
+
+// Exec interface collecting everything which is execution related
+// and needed for calling the cm client.
+type Exec interface {
+	command.ExecRunner
+	GetExitCode() int
+}
+
+// Connection Everything we need for connecting to the backend
+type Connection struct {
+	Endpoint string
+	User     string
+	Password string
+}
+
+// Run executes the cm client command and returns its standard output. The standard output
+// is additionally written to the standard output of the exec.
+func Run(exec Exec, backendType string, connection Connection, cmOpts []string, cmd string, args ...string) (string, error) {
+	if len(cmOpts) > 0 {
+		exec.SetEnv([]string{fmt.Sprintf("CMCLIENT_OPTS=%s", strings.Join(cmOpts, " "))})
+	}
+
+	oldStdout := exec.GetStdout()
+	defer func() {
+		exec.Stdout(oldStdout)
+	}()
+	var cmClientStdout bytes.Buffer
+	if oldStdout != nil {
+		exec.Stdout(io.MultiWriter(&cmClientStdout, oldStdout))
+	} else {
+		exec.Stdout(&cmClientStdout)
+	}
+
+	params := []string{
+		"--endpoint", connection.Endpoint,
+		"--user", connection.User,
+		"--password", connection.Password,
+	}
+	// SOLMAN is the default of the cm client, the backend type is omitted in this case
+	if len(backendType) > 0 && backendType != BackendSOLMAN {
+		params = append(params, "-t", backendType)
+	}
+	params = append(params, cmd)
+	params = append(params, args...)
+
+	err := exec.RunExecutable("cmclient", params...)
+
+	exitCode := exec.GetExitCode()
+	if exitCode != 0 {
+		message := fmt.Sprintf("%s command returned with exit code '%d'", cmd, exitCode)
+		if err != nil {
+			err = errors.Wrap(err, message)
+		} else {
+			err = errors.New(message)
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(cmClientStdout.String()), nil
+}
+
+// ParseModifiable converts the output of the get-transport-modifiable command into the status of the transport request
+func ParseModifiable(output string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(output)) {
+	case "true":
+		return StatusModifiable, nil
+	case "false":
+		return StatusReleased, nil
+	}
+	return "", errors.Errorf("unexpected response '%s' when retrieving the status of the transport request", output)
+}
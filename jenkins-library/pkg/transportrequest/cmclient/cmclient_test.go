@@ -0,0 +1,82 @@
+//go:build unit
+// +build unit
+
+package cmclient
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+
+	connection := Connection{Endpoint: "https://example.org/cm", User: "me", Password: "******"}
+
+	t.Run("straight forward", func(t *testing.T) {
+		var out bytes.Buffer
+		e := &mock.ExecMockRunner{StdoutReturn: map[string]string{"^cmclient.*": "ABCK123456\n"}}
+		e.Stdout(&out)
+
+		output, err := Run(e, BackendCTS, connection, []string{"-Dprop=abc"}, "create-transport", "-tt", "W")
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, "ABCK123456", output)
+			assert.Equal(t, []mock.ExecCall{{
+				Exec: "cmclient",
+				Params: []string{
+					"--endpoint", "https://example.org/cm",
+					"--user", "me",
+					"--password", "******",
+					"-t", "CTS",
+					"create-transport", "-tt", "W",
+				},
+			}}, e.Calls)
+			assert.Equal(t, []string{"CMCLIENT_OPTS=-Dprop=abc"}, e.Env)
+			// the output is still written to the original stdout
+			assert.Equal(t, "ABCK123456\n", out.String())
+		}
+	})
+
+	t.Run("default backend type is omitted", func(t *testing.T) {
+		e := &mock.ExecMockRunner{}
+
+		_, err := Run(e, BackendSOLMAN, connection, nil, "release-transport")
+
+		if assert.NoError(t, err) {
+			assert.NotContains(t, e.Calls[0].Params, "-t")
+		}
+	})
+
+	t.Run("fail with error", func(t *testing.T) {
+		e := &mock.ExecMockRunner{ShouldFailOnCommand: map[string]error{"^cmclient.*": fmt.Errorf("connection refused")}}
+
+		_, err := Run(e, BackendSOLMAN, connection, nil, "release-transport")
+
+		assert.EqualError(t, err, "connection refused")
+	})
+
+	t.Run("fail via return code", func(t *testing.T) {
+		e := &mock.ExecMockRunner{ExitCode: 1}
+
+		_, err := Run(e, BackendSOLMAN, connection, nil, "release-transport")
+
+		assert.EqualError(t, err, "release-transport command returned with exit code '1'")
+	})
+}
+
+func TestParseModifiable(t *testing.T) {
+	status, err := ParseModifiable("true\n")
+	assert.NoError(t, err)
+	assert.Equal(t, StatusModifiable, status)
+
+	status, err = ParseModifiable("false")
+	assert.NoError(t, err)
+	assert.Equal(t, StatusReleased, status)
+
+	_, err = ParseModifiable("unknown")
+	assert.EqualError(t, err, "unexpected response 'unknown' when retrieving the status of the transport request")
+}
@@ -0,0 +1,118 @@
+package cts
+
+import (
+	"fmt"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/transportrequest/cmclient"
+	"github.com/pkg/errors"
+)
+
+// Exec Everything we need for calling the cm client
+type Exec interface {
+	cmclient.Exec
+}
+
+// CreateAction Collects all the properties we need for creating a transport request
+type CreateAction struct {
+	Connection Connection
+	// The transport type, typically 'W' (workbench) or 'C' (customizing)
+	TransportType string
+	// The system receiving the transport request
+	TargetSystemID string
+	Description    string
+	CMOpts         []string
+}
+
+// Perform creates a new transport request and returns its ID
+func (a *CreateAction) Perform(command Exec) (string, error) {
+	log.Entry().Infof("Creating new transport request via '%s'.", a.Connection.Endpoint)
+
+	if err := checkParameters(a.Connection, map[string]string{
+		"TransportType":  a.TransportType,
+		"TargetSystemID": a.TargetSystemID,
+		"Description":    a.Description,
+	}); err != nil {
+		return "", errors.Wrap(err, "cannot create transport request")
+	}
+	transportRequestID, err := cmclient.Run(command, cmclient.BackendCTS, connection(a.Connection), a.CMOpts,
+		"create-transport",
+		"-tt", a.TransportType,
+		"-ts", a.TargetSystemID,
+		"-d", a.Description,
+	)
+	if err == nil && len(transportRequestID) == 0 {
+		err = errors.New("no transport request ID returned")
+	}
+	if err != nil {
+		return "", errors.Wrap(err, "cannot create transport request")
+	}
+	log.Entry().Infof("Created transport request '%s' at '%s'. TransportType: '%s', TargetSystemId: '%s'",
+		transportRequestID, a.Connection.Endpoint, a.TransportType, a.TargetSystemID)
+	return transportRequestID, nil
+}
+
+// ReleaseAction Collects all the properties we need for releasing a transport request
+type ReleaseAction struct {
+	Connection         Connection
+	TransportRequestID string
+	CMOpts             []string
+}
+
+// Perform releases the transport request
+func (a *ReleaseAction) Perform(command Exec) error {
+	log.Entry().Infof("Releasing transport request '%s' via '%s'.", a.TransportRequestID, a.Connection.Endpoint)
+
+	if err := checkParameters(a.Connection, map[string]string{"TransportRequestID": a.TransportRequestID}); err != nil {
+		return errors.Wrap(err, "cannot release transport request")
+	}
+	if _, err := cmclient.Run(command, cmclient.BackendCTS, connection(a.Connection), a.CMOpts, "export-transport", "-tID", a.TransportRequestID); err != nil {
+		return errors.Wrapf(err, "cannot release transport request '%s'", a.TransportRequestID)
+	}
+	log.Entry().Infof("Released transport request '%s'.", a.TransportRequestID)
+	return nil
+}
+
+// StatusAction Collects all the properties we need for retrieving the status of a transport request
+type StatusAction struct {
+	Connection         Connection
+	TransportRequestID string
+	CMOpts             []string
+}
+
+// Perform returns the status of the transport request, which is either modifiable or released
+func (a *StatusAction) Perform(command Exec) (string, error) {
+	if err := checkParameters(a.Connection, map[string]string{"TransportRequestID": a.TransportRequestID}); err != nil {
+		return "", errors.Wrap(err, "cannot retrieve status of transport request")
+	}
+	output, err := cmclient.Run(command, cmclient.BackendCTS, connection(a.Connection), a.CMOpts, "get-transport-modifiable", "-tID", a.TransportRequestID)
+	if err == nil {
+		var status string
+		if status, err = cmclient.ParseModifiable(output); err == nil {
+			log.Entry().Infof("Transport request '%s' is %s.", a.TransportRequestID, status)
+			return status, nil
+		}
+	}
+	return "", errors.Wrapf(err, "cannot retrieve status of transport request '%s'", a.TransportRequestID)
+}
+
+func connection(c Connection) cmclient.Connection {
+	return cmclient.Connection{Endpoint: c.Endpoint, User: c.User, Password: c.Password}
+}
+
+// checkParameters checks the connection properties required by the cm client, the ABAP client is not required
+func checkParameters(c Connection, parameters map[string]string) error {
+	parameters["Connection.Endpoint"] = c.Endpoint
+	parameters["Connection.User"] = c.User
+	parameters["Connection.Password"] = c.Password
+	missingParameters := []string{}
+	for _, name := range []string{"Connection.Endpoint", "Connection.User", "Connection.Password", "TransportType", "TargetSystemID", "Description", "TransportRequestID"} {
+		if value, ok := parameters[name]; ok && len(value) == 0 {
+			missingParameters = append(missingParameters, name)
+		}
+	}
+	if len(missingParameters) != 0 {
+		return fmt.Errorf("the following parameters are not available %s", missingParameters)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+//go:build unit
+// +build unit
+
+package cts
+
+import (
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCTSTransportRequestLifecycle(t *testing.T) {
+
+	connection := Connection{Endpoint: "https://example.org:8000", Client: "001", User: "me", Password: "******"}
+	connectionParams := []string{"--endpoint", "https://example.org:8000", "--user", "me", "--password", "******", "-t", "CTS"}
+
+	t.Run("create", func(t *testing.T) {
+		e := &mock.ExecMockRunner{StdoutReturn: map[string]string{"^cmclient.*create-transport.*": "DEVK900123\n"}}
+
+		action := CreateAction{Connection: connection, TransportType: "W", TargetSystemID: "QAS", Description: "my transport"}
+		id, err := action.Perform(e)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, "DEVK900123", id)
+			assert.Equal(t, []mock.ExecCall{{
+				Exec:   "cmclient",
+				Params: append(connectionParams, "create-transport", "-tt", "W", "-ts", "QAS", "-d", "my transport"),
+			}}, e.Calls)
+		}
+	})
+
+	t.Run("create without response", func(t *testing.T) {
+		e := &mock.ExecMockRunner{}
+
+		action := CreateAction{Connection: connection, TransportType: "W", TargetSystemID: "QAS", Description: "my transport"}
+		_, err := action.Perform(e)
+
+		assert.EqualError(t, err, "cannot create transport request: no transport request ID returned")
+	})
+
+	t.Run("create with missing parameters", func(t *testing.T) {
+		e := &mock.ExecMockRunner{}
+
+		action := CreateAction{Connection: Connection{Endpoint: "https://example.org:8000"}, TransportType: "W"}
+		_, err := action.Perform(e)
+
+		assert.EqualError(t, err, "cannot create transport request: the following parameters are not available [Connection.User Connection.Password TargetSystemID Description]")
+		assert.Empty(t, e.Calls)
+	})
+
+	t.Run("release", func(t *testing.T) {
+		e := &mock.ExecMockRunner{}
+
+		action := ReleaseAction{Connection: connection, TransportRequestID: "DEVK900123"}
+		err := action.Perform(e)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, []mock.ExecCall{{Exec: "cmclient", Params: append(connectionParams, "export-transport", "-tID", "DEVK900123")}}, e.Calls)
+		}
+	})
+
+	t.Run("release fails", func(t *testing.T) {
+		e := &mock.ExecMockRunner{ExitCode: 1}
+
+		action := ReleaseAction{Connection: connection, TransportRequestID: "DEVK900123"}
+		err := action.Perform(e)
+
+		assert.EqualError(t, err, "cannot release transport request 'DEVK900123': export-transport command returned with exit code '1'")
+	})
+
+	t.Run("status", func(t *testing.T) {
+		e := &mock.ExecMockRunner{StdoutReturn: map[string]string{"^cmclient.*get-transport-modifiable -tID DEVK900123$": "false"}}
+
+		action := StatusAction{Connection: connection, TransportRequestID: "DEVK900123"}
+		status, err := action.Perform(e)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, "released", status)
+		}
+	})
+}
@@ -0,0 +1,132 @@
+package rfc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/config/validation"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/pkg/errors"
+)
+
+// CreateAction Collects all the properties we need for creating a transport request
+type CreateAction struct {
+	Connection  Connection
+	Description string
+	Verbose     bool
+}
+
+// Perform creates a new transport request and returns its ID
+func (action *CreateAction) Perform(command Exec) (string, error) {
+	log.Entry().Infof("Creating new transport request at '%s', client: '%s', instance: '%s'.",
+		action.Connection.Endpoint, action.Connection.Client, action.Connection.Instance)
+
+	if err := checkParameters(*action); err != nil {
+		return "", errors.Wrap(err, "cannot create transport request")
+	}
+
+	output, err := run(command, action.Connection, action.Verbose, []string{"TRANSPORT_DESCRIPTION" + eq + action.Description}, "createTransportRequest")
+	if err != nil {
+		return "", errors.Wrap(err, "cannot create transport request")
+	}
+	transportRequestID, err := parseTransportRequestID(output)
+	if err != nil {
+		return "", errors.Wrap(err, "cannot create transport request")
+	}
+	log.Entry().Infof("Created transport request '%s' at '%s', client: '%s', instance: '%s'.",
+		transportRequestID, action.Connection.Endpoint, action.Connection.Client, action.Connection.Instance)
+	return transportRequestID, nil
+}
+
+// ReleaseAction Collects all the properties we need for releasing a transport request
+type ReleaseAction struct {
+	Connection         Connection
+	TransportRequestID string
+	Verbose            bool
+}
+
+// Perform releases the transport request
+func (action *ReleaseAction) Perform(command Exec) error {
+	log.Entry().Infof("Releasing transport request '%s' at '%s', client: '%s', instance: '%s'.",
+		action.TransportRequestID, action.Connection.Endpoint, action.Connection.Client, action.Connection.Instance)
+
+	if err := checkParameters(*action); err != nil {
+		return errors.Wrap(err, "cannot release transport request")
+	}
+	if _, err := run(command, action.Connection, action.Verbose, nil, fmt.Sprintf("releaseTransport:%s", action.TransportRequestID)); err != nil {
+		return errors.Wrapf(err, "cannot release transport request '%s'", action.TransportRequestID)
+	}
+	log.Entry().Infof("Released transport request '%s'.", action.TransportRequestID)
+	return nil
+}
+
+func checkParameters(action interface{}) error {
+	missingParameters, err := validation.FindEmptyStringsInConfigStruct(action)
+	if err != nil {
+		return err
+	}
+	if len(missingParameters) != 0 {
+		return fmt.Errorf("the following parameters are not available %s", missingParameters)
+	}
+	return nil
+}
+
+// run calls the rfc client with the given cts command and returns its standard output
+func run(command Exec, connection Connection, verbose bool, env []string, ctsCommand string) (string, error) {
+	command.SetEnv(append([]string{
+		"ABAP_DEVELOPMENT_SERVER" + eq + connection.Endpoint,
+		"ABAP_DEVELOPMENT_USER" + eq + connection.User,
+		"ABAP_DEVELOPMENT_PASSWORD" + eq + connection.Password,
+		"ABAP_DEVELOPMENT_INSTANCE" + eq + connection.Instance,
+		"ABAP_DEVELOPMENT_CLIENT" + eq + connection.Client,
+		"VERBOSE" + eq + strconv.FormatBool(verbose),
+	}, env...))
+
+	oldStdout := command.GetStdout()
+	defer func() {
+		command.Stdout(oldStdout)
+	}()
+	var stdout bytes.Buffer
+	if oldStdout != nil {
+		command.Stdout(io.MultiWriter(&stdout, oldStdout))
+	} else {
+		command.Stdout(&stdout)
+	}
+
+	err := command.RunExecutable("cts", ctsCommand)
+
+	exitCode := command.GetExitCode()
+	if exitCode != 0 {
+		message := fmt.Sprintf("%s command returned with exit code '%d'", strings.Split(ctsCommand, ":")[0], exitCode)
+		if err != nil {
+			err = errors.Wrap(err, message)
+		} else {
+			err = errors.New(message)
+		}
+	}
+	return stdout.String(), err
+}
+
+// parseTransportRequestID extracts the ID from the JSON response of the rfc client, e.g. {"REQUESTID": "DEVK900123"}.
+// In verbose mode the response is preceded by log output.
+func parseTransportRequestID(output string) (string, error) {
+	start := strings.Index(output, "{")
+	end := strings.LastIndex(output, "}")
+	if start < 0 || end < start {
+		return "", errors.Errorf("unexpected response of the rfc client: '%s'", strings.TrimSpace(output))
+	}
+	var response struct {
+		RequestID string `json:"REQUESTID"`
+	}
+	if err := json.Unmarshal([]byte(output[start:end+1]), &response); err != nil {
+		return "", errors.Wrapf(err, "unexpected response of the rfc client: '%s'", strings.TrimSpace(output))
+	}
+	if len(response.RequestID) == 0 {
+		return "", errors.Errorf("no transport request ID returned by the rfc client: '%s'", strings.TrimSpace(output))
+	}
+	return response.RequestID, nil
+}
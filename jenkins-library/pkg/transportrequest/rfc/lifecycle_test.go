@@ -0,0 +1,83 @@
+//go:build unit
+// +build unit
+
+package rfc
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRFCTransportRequestLifecycle(t *testing.T) {
+
+	connection := Connection{
+		Endpoint: "https://example.org/rfc",
+		Client:   "001",
+		Instance: "DEV",
+		User:     "me",
+		Password: "******",
+	}
+
+	t.Run("create", func(t *testing.T) {
+		exec := mock.ExecMockRunner{StdoutReturn: map[string]string{"cts createTransportRequest": "Connecting to DEV\n{\"REQUESTID\": \"DEVK900123\"}\n"}}
+
+		action := CreateAction{Connection: connection, Description: "my transport", Verbose: true}
+		id, err := action.Perform(&exec)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, "DEVK900123", id)
+			assert.Equal(t, []mock.ExecCall{{Exec: "cts", Params: []string{"createTransportRequest"}}}, exec.Calls)
+			assert.Subset(t, exec.Env, []string{
+				"ABAP_DEVELOPMENT_SERVER=https://example.org/rfc",
+				"ABAP_DEVELOPMENT_USER=me",
+				"ABAP_DEVELOPMENT_PASSWORD=******",
+				"ABAP_DEVELOPMENT_INSTANCE=DEV",
+				"ABAP_DEVELOPMENT_CLIENT=001",
+				"TRANSPORT_DESCRIPTION=my transport",
+				"VERBOSE=true",
+			})
+		}
+	})
+
+	t.Run("create with invalid response", func(t *testing.T) {
+		exec := mock.ExecMockRunner{StdoutReturn: map[string]string{"cts createTransportRequest": "{\"MESSAGE\": \"no authorization\"}"}}
+
+		action := CreateAction{Connection: connection, Description: "my transport"}
+		_, err := action.Perform(&exec)
+
+		assert.EqualError(t, err, "cannot create transport request: no transport request ID returned by the rfc client: '{\"MESSAGE\": \"no authorization\"}'")
+	})
+
+	t.Run("create with missing parameters", func(t *testing.T) {
+		exec := mock.ExecMockRunner{}
+
+		action := CreateAction{Connection: connection}
+		_, err := action.Perform(&exec)
+
+		assert.EqualError(t, err, "cannot create transport request: the following parameters are not available [Description]")
+		assert.Empty(t, exec.Calls)
+	})
+
+	t.Run("release", func(t *testing.T) {
+		exec := mock.ExecMockRunner{}
+
+		action := ReleaseAction{Connection: connection, TransportRequestID: "DEVK900123"}
+		err := action.Perform(&exec)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, []mock.ExecCall{{Exec: "cts", Params: []string{"releaseTransport:DEVK900123"}}}, exec.Calls)
+		}
+	})
+
+	t.Run("release fails", func(t *testing.T) {
+		exec := mock.ExecMockRunner{ExitCode: 8, ShouldFailOnCommand: map[string]error{"cts releaseTransport:DEVK900123": fmt.Errorf("objects locked")}}
+
+		action := ReleaseAction{Connection: connection, TransportRequestID: "DEVK900123"}
+		err := action.Perform(&exec)
+
+		assert.EqualError(t, err, "cannot release transport request 'DEVK900123': releaseTransport command returned with exit code '8': objects locked")
+	})
+}
@@ -0,0 +1,76 @@
+package solman
+
+import (
+	"fmt"
+
+	"github.com/SAP/jenkins-library/pkg/config/validation"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/transportrequest/cmclient"
+	"github.com/pkg/errors"
+)
+
+// ReleaseAction Collects all the properties we need for releasing a transport request
+type ReleaseAction struct {
+	Connection         Connection
+	ChangeDocumentID   string
+	TransportRequestID string
+	CMOpts             []string
+}
+
+// Perform releases the transport request
+func (a *ReleaseAction) Perform(command Exec) error {
+	log.Entry().Infof("Releasing transport request '%s' of change document '%s' via '%s'.", a.TransportRequestID, a.ChangeDocumentID, a.Connection.Endpoint)
+
+	if err := checkParameters(*a); err != nil {
+		return errors.Wrap(err, "cannot release transport request")
+	}
+	_, err := cmclient.Run(command, cmclient.BackendSOLMAN, cmclient.Connection(a.Connection), a.CMOpts,
+		"release-transport",
+		"-cID", a.ChangeDocumentID,
+		"-tID", a.TransportRequestID,
+	)
+	if err != nil {
+		return errors.Wrapf(err, "cannot release transport request '%s'", a.TransportRequestID)
+	}
+	log.Entry().Infof("Released transport request '%s' of change document '%s'.", a.TransportRequestID, a.ChangeDocumentID)
+	return nil
+}
+
+// StatusAction Collects all the properties we need for retrieving the status of a transport request
+type StatusAction struct {
+	Connection         Connection
+	ChangeDocumentID   string
+	TransportRequestID string
+	CMOpts             []string
+}
+
+// Perform returns the status of the transport request, which is either modifiable or released
+func (a *StatusAction) Perform(command Exec) (string, error) {
+	if err := checkParameters(*a); err != nil {
+		return "", errors.Wrap(err, "cannot retrieve status of transport request")
+	}
+	output, err := cmclient.Run(command, cmclient.BackendSOLMAN, cmclient.Connection(a.Connection), a.CMOpts,
+		"get-transport-modifiable",
+		"-cID", a.ChangeDocumentID,
+		"-tID", a.TransportRequestID,
+	)
+	if err == nil {
+		var status string
+		if status, err = cmclient.ParseModifiable(output); err == nil {
+			log.Entry().Infof("Transport request '%s' of change document '%s' is %s.", a.TransportRequestID, a.ChangeDocumentID, status)
+			return status, nil
+		}
+	}
+	return "", errors.Wrapf(err, "cannot retrieve status of transport request '%s'", a.TransportRequestID)
+}
+
+func checkParameters(action interface{}) error {
+	missingParameters, err := validation.FindEmptyStringsInConfigStruct(action)
+	if err != nil {
+		return err
+	}
+	if len(missingParameters) != 0 {
+		return fmt.Errorf("the following parameters are not available %s", missingParameters)
+	}
+	return nil
+}
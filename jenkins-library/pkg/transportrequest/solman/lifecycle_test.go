@@ -0,0 +1,105 @@
+//go:build unit
+// +build unit
+
+package solman
+
+import (
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSolmanReleaseTransportRequest(t *testing.T) {
+
+	a := ReleaseAction{
+		Connection:         Connection{Endpoint: "https://example.org/solman", User: "me", Password: "******"},
+		ChangeDocumentID:   "123",
+		TransportRequestID: "ABCK123456",
+	}
+
+	t.Run("straight forward", func(t *testing.T) {
+		e := getExecMock()
+
+		examinee := a
+		err := examinee.Perform(e)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, []mock.ExecCall{{
+				Exec: "cmclient",
+				Params: []string{
+					"--endpoint", "https://example.org/solman",
+					"--user", "me",
+					"--password", "******",
+					"release-transport",
+					"-cID", "123",
+					"-tID", "ABCK123456",
+				},
+			}}, e.Calls)
+		}
+	})
+
+	t.Run("fail via return code", func(t *testing.T) {
+		e := getExecMock()
+		e.ExitCode = 1
+
+		examinee := a
+		err := examinee.Perform(e)
+
+		assert.EqualError(t, err, "cannot release transport request 'ABCK123456': release-transport command returned with exit code '1'")
+	})
+
+	t.Run("input missing", func(t *testing.T) {
+		e := getExecMock()
+
+		examinee := a
+		examinee.TransportRequestID = ""
+		err := examinee.Perform(e)
+
+		assert.EqualError(t, err, "cannot release transport request: the following parameters are not available [TransportRequestID]")
+		assert.Empty(t, e.Calls)
+	})
+}
+
+func TestSolmanTransportRequestStatus(t *testing.T) {
+
+	a := StatusAction{
+		Connection:         Connection{Endpoint: "https://example.org/solman", User: "me", Password: "******"},
+		ChangeDocumentID:   "123",
+		TransportRequestID: "ABCK123456",
+	}
+
+	t.Run("modifiable", func(t *testing.T) {
+		e := getExecMock()
+		e.StdoutReturn = map[string]string{"^cmclient.*get-transport-modifiable -cID 123 -tID ABCK123456$": "true"}
+
+		examinee := a
+		status, err := examinee.Perform(e)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, "modifiable", status)
+		}
+	})
+
+	t.Run("released", func(t *testing.T) {
+		e := getExecMock()
+		e.StdoutReturn = map[string]string{"^cmclient.*": "false"}
+
+		examinee := a
+		status, err := examinee.Perform(e)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, "released", status)
+		}
+	})
+
+	t.Run("unexpected response", func(t *testing.T) {
+		e := getExecMock()
+		e.StdoutReturn = map[string]string{"^cmclient.*": "not found"}
+
+		examinee := a
+		_, err := examinee.Perform(e)
+
+		assert.EqualError(t, err, "cannot retrieve status of transport request 'ABCK123456': unexpected response 'not found' when retrieving the status of the transport request")
+	})
+}
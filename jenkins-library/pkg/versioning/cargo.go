@@ -0,0 +1,149 @@
+package versioning
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/pkg/errors"
+)
+
+const (
+	// CargoBuildDescriptor is the manifest of a Rust package or workspace
+	CargoBuildDescriptor = "Cargo.toml"
+)
+
+var cargoVersionLine = regexp.MustCompile(`^(\s*version\s*=\s*)(["'])[^"']*(["'].*)$`)
+
+// Cargo utility to interact with Rust specific versioning
+type Cargo struct {
+	path                   string
+	readFile               func(string) ([]byte, error)
+	writeFile              func(string, []byte, os.FileMode) error
+	buildDescriptorContent string
+	manifest               cargoManifest
+}
+
+type cargoManifest struct {
+	Package struct {
+		Name string `toml:"name"`
+		// Version is either a string or inherited from the workspace, i.e. version.workspace = true
+		Version interface{} `toml:"version"`
+	} `toml:"package"`
+	Workspace struct {
+		Members []string `toml:"members"`
+		Package struct {
+			Version string `toml:"version"`
+		} `toml:"package"`
+	} `toml:"workspace"`
+}
+
+func (c *Cargo) init() error {
+	if c.readFile == nil {
+		c.readFile = os.ReadFile
+	}
+	if c.writeFile == nil {
+		c.writeFile = os.WriteFile
+	}
+	if len(c.buildDescriptorContent) > 0 {
+		return nil
+	}
+	content, err := c.readFile(c.path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read file '%v'", c.path)
+	}
+	var manifest cargoManifest
+	if _, err := toml.Decode(string(content), &manifest); err != nil {
+		return errors.Wrapf(err, "failed to parse file '%v'", c.path)
+	}
+	c.buildDescriptorContent = string(content)
+	c.manifest = manifest
+	return nil
+}
+
+// versionSection returns the section of the manifest defining the version, the package or the workspace
+func (c *Cargo) versionSection() (string, string, error) {
+	if version, ok := c.manifest.Package.Version.(string); ok && len(version) > 0 {
+		return "package", version, nil
+	}
+	if len(c.manifest.Workspace.Package.Version) > 0 {
+		return "workspace.package", c.manifest.Workspace.Package.Version, nil
+	}
+	if c.manifest.Package.Version != nil {
+		return "", "", fmt.Errorf("the version in file '%v' is inherited from the workspace, use the Cargo.toml of the workspace instead", c.path)
+	}
+	return "", "", fmt.Errorf("no version information found in file '%v'", c.path)
+}
+
+// VersioningScheme returns the relevant versioning scheme
+func (c *Cargo) VersioningScheme() string {
+	return "semver2"
+}
+
+// GetVersion returns the version of the package or of the workspace
+func (c *Cargo) GetVersion() (string, error) {
+	if err := c.init(); err != nil {
+		return "", err
+	}
+	_, version, err := c.versionSection()
+	return version, err
+}
+
+// SetVersion updates the version of the package or of the workspace, the remaining content is kept as is
+func (c *Cargo) SetVersion(version string) error {
+	if err := c.init(); err != nil {
+		return err
+	}
+	section, _, err := c.versionSection()
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(c.buildDescriptorContent, "\n")
+	currentSection := ""
+	updated := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") {
+			currentSection = strings.TrimSpace(strings.Trim(strings.TrimSpace(strings.SplitN(trimmed, "#", 2)[0]), "[]"))
+			continue
+		}
+		if currentSection == section && cargoVersionLine.MatchString(line) {
+			lines[i] = cargoVersionLine.ReplaceAllString(line, "${1}${2}"+version+"${3}")
+			updated = true
+			break
+		}
+	}
+	if !updated {
+		return fmt.Errorf("failed to update version in section [%v] of file '%v'", section, c.path)
+	}
+	c.buildDescriptorContent = strings.Join(lines, "\n")
+	if err := c.writeFile(c.path, []byte(c.buildDescriptorContent), 0o666); err != nil {
+		return errors.Wrapf(err, "failed to write file '%v'", c.path)
+	}
+	if section == "package" {
+		c.manifest.Package.Version = version
+	} else {
+		c.manifest.Workspace.Package.Version = version
+	}
+	return nil
+}
+
+// GetCoordinates returns the name and version of the package
+func (c *Cargo) GetCoordinates() (Coordinates, error) {
+	result := Coordinates{}
+	if err := c.init(); err != nil {
+		return result, err
+	}
+	if len(c.manifest.Package.Name) == 0 {
+		return result, fmt.Errorf("no package name found in file '%v'", c.path)
+	}
+	result.ArtifactID = c.manifest.Package.Name
+	version, err := c.GetVersion()
+	if err != nil {
+		return result, err
+	}
+	result.Version = version
+	return result, nil
+}
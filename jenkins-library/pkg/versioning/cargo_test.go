@@ -0,0 +1,118 @@
+//go:build unit
+// +build unit
+
+package versioning
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	cargoPackage = `[package]
+name = "hello" # the crate
+version = "0.1.0"
+edition = "2021"
+
+[dependencies]
+serde = { version = "1.0", features = ["derive"] }
+`
+	cargoWorkspace = `[workspace]
+members = ["server", "client"]
+resolver = "2"
+
+[workspace.package]
+version = '1.2.3'
+edition = "2021"
+
+[workspace.dependencies]
+tokio = { version = "1" }
+`
+	cargoWorkspaceMember = `[package]
+name = "server"
+version.workspace = true
+`
+)
+
+func TestCargo(t *testing.T) {
+	newCargo := func(content string) (*Cargo, map[string][]byte) {
+		written := map[string][]byte{}
+		return &Cargo{
+			path: "Cargo.toml",
+			readFile: func(path string) ([]byte, error) {
+				return []byte(content), nil
+			},
+			writeFile: func(path string, content []byte, perm os.FileMode) error {
+				written[path] = content
+				return nil
+			},
+		}, written
+	}
+
+	t.Run("package version", func(t *testing.T) {
+		cargo, written := newCargo(cargoPackage)
+
+		version, err := cargo.GetVersion()
+		require.NoError(t, err)
+		assert.Equal(t, "0.1.0", version)
+
+		require.NoError(t, cargo.SetVersion("0.2.0-20240101"))
+		assert.Equal(t, `[package]
+name = "hello" # the crate
+version = "0.2.0-20240101"
+edition = "2021"
+
+[dependencies]
+serde = { version = "1.0", features = ["derive"] }
+`, string(written["Cargo.toml"]))
+		version, _ = cargo.GetVersion()
+		assert.Equal(t, "0.2.0-20240101", version)
+
+		coordinates, err := cargo.GetCoordinates()
+		require.NoError(t, err)
+		assert.Equal(t, Coordinates{ArtifactID: "hello", Version: "0.2.0-20240101"}, coordinates)
+	})
+
+	t.Run("workspace version", func(t *testing.T) {
+		cargo, written := newCargo(cargoWorkspace)
+
+		version, err := cargo.GetVersion()
+		require.NoError(t, err)
+		assert.Equal(t, "1.2.3", version)
+
+		require.NoError(t, cargo.SetVersion("1.3.0"))
+		assert.Contains(t, string(written["Cargo.toml"]), "[workspace.package]\nversion = '1.3.0'\n")
+		assert.Contains(t, string(written["Cargo.toml"]), `tokio = { version = "1" }`)
+
+		_, err = cargo.GetCoordinates()
+		assert.EqualError(t, err, "no package name found in file 'Cargo.toml'")
+	})
+
+	t.Run("version inherited from the workspace", func(t *testing.T) {
+		cargo, _ := newCargo(cargoWorkspaceMember)
+
+		_, err := cargo.GetVersion()
+
+		assert.EqualError(t, err, "the version in file 'Cargo.toml' is inherited from the workspace, use the Cargo.toml of the workspace instead")
+	})
+
+	t.Run("no version", func(t *testing.T) {
+		cargo, _ := newCargo("[package]\nname = \"hello\"\n")
+
+		_, err := cargo.GetVersion()
+
+		assert.EqualError(t, err, "no version information found in file 'Cargo.toml'")
+	})
+
+	t.Run("read error", func(t *testing.T) {
+		cargo := &Cargo{path: "Cargo.toml", readFile: func(string) ([]byte, error) { return nil, fmt.Errorf("read error") }}
+
+		_, err := cargo.GetVersion()
+
+		assert.EqualError(t, err, "failed to read file 'Cargo.toml': read error")
+	})
+}
@@ -0,0 +1,163 @@
+package versioning
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// MSBuildPropsDescriptor contains the properties shared by all .NET projects of a repository
+	MSBuildPropsDescriptor = "Directory.Build.props"
+)
+
+// MSBuildProjectPatterns are the project files of the .NET languages
+var MSBuildProjectPatterns = []string{"*.csproj", "*.fsproj", "*.vbproj"}
+
+var msbuildPropertyGroup = regexp.MustCompile(`<PropertyGroup(\s[^>]*)?>`)
+
+// MSBuild utility to interact with the version of .NET projects, defined in a project file or in Directory.Build.props
+type MSBuild struct {
+	path                   string
+	readFile               func(string) ([]byte, error)
+	writeFile              func(string, []byte, os.FileMode) error
+	buildDescriptorContent string
+}
+
+func (m *MSBuild) init() error {
+	if m.readFile == nil {
+		m.readFile = os.ReadFile
+	}
+	if m.writeFile == nil {
+		m.writeFile = os.WriteFile
+	}
+	if len(m.buildDescriptorContent) > 0 {
+		return nil
+	}
+	content, err := m.readFile(m.path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read file '%v'", m.path)
+	}
+	m.buildDescriptorContent = string(content)
+	return nil
+}
+
+func msbuildProperty(name string) *regexp.Regexp {
+	return regexp.MustCompile(`(<` + name + `(?:\s[^>]*)?>)\s*([^<]*?)\s*(</` + name + `>)`)
+}
+
+// property returns the value of the first definition of a property
+func (m *MSBuild) property(name string) string {
+	match := msbuildProperty(name).FindStringSubmatch(m.buildDescriptorContent)
+	if match == nil {
+		return ""
+	}
+	return match[2]
+}
+
+// VersioningScheme returns the relevant versioning scheme
+func (m *MSBuild) VersioningScheme() string {
+	return "semver2"
+}
+
+// GetVersion returns the version defined by the property Version or by the properties VersionPrefix and VersionSuffix
+func (m *MSBuild) GetVersion() (string, error) {
+	if err := m.init(); err != nil {
+		return "", err
+	}
+	version := m.property("Version")
+	if len(version) == 0 || strings.Contains(version, "$(") {
+		prefix := m.property("VersionPrefix")
+		if len(prefix) == 0 {
+			if len(version) > 0 {
+				return "", fmt.Errorf("the version '%v' in file '%v' is not a literal value", version, m.path)
+			}
+			return "", fmt.Errorf("no version information found in file '%v'", m.path)
+		}
+		version = prefix
+		if suffix := m.property("VersionSuffix"); len(suffix) > 0 && !strings.Contains(suffix, "$(") {
+			version = fmt.Sprintf("%v-%v", prefix, suffix)
+		}
+	}
+	if strings.Contains(version, "$(") {
+		return "", fmt.Errorf("the version '%v' in file '%v' is not a literal value", version, m.path)
+	}
+	return version, nil
+}
+
+// SetVersion sets the property Version which takes precedence over VersionPrefix and VersionSuffix, the remaining content is kept as is
+func (m *MSBuild) SetVersion(version string) error {
+	if err := m.init(); err != nil {
+		return err
+	}
+	versionProperty := msbuildProperty("Version")
+	if match := versionProperty.FindStringSubmatchIndex(m.buildDescriptorContent); match != nil {
+		// only the first definition is replaced
+		m.buildDescriptorContent = m.buildDescriptorContent[:match[0]] +
+			m.buildDescriptorContent[match[2]:match[3]] + version + m.buildDescriptorContent[match[6]:match[7]] +
+			m.buildDescriptorContent[match[1]:]
+	} else {
+		group := msbuildPropertyGroup.FindStringIndex(m.buildDescriptorContent)
+		if group == nil {
+			return fmt.Errorf("no PropertyGroup found in file '%v'", m.path)
+		}
+		m.buildDescriptorContent = m.buildDescriptorContent[:group[1]] +
+			fmt.Sprintf("\n    <Version>%v</Version>", version) +
+			m.buildDescriptorContent[group[1]:]
+	}
+	if err := m.writeFile(m.path, []byte(m.buildDescriptorContent), 0o666); err != nil {
+		return errors.Wrapf(err, "failed to write file '%v'", m.path)
+	}
+	return nil
+}
+
+// GetCoordinates returns the package id and version of the project
+func (m *MSBuild) GetCoordinates() (Coordinates, error) {
+	result := Coordinates{}
+	if err := m.init(); err != nil {
+		return result, err
+	}
+	result.ArtifactID = m.property("PackageId")
+	if len(result.ArtifactID) == 0 {
+		result.ArtifactID = m.property("AssemblyName")
+	}
+	if len(result.ArtifactID) == 0 && filepath.Base(m.path) != MSBuildPropsDescriptor {
+		// the package id defaults to the name of the project file
+		result.ArtifactID = strings.TrimSuffix(filepath.Base(m.path), filepath.Ext(m.path))
+	}
+	version, err := m.GetVersion()
+	if err != nil {
+		return result, err
+	}
+	result.Version = version
+	return result, nil
+}
+
+// searchMSBuildDescriptor returns Directory.Build.props or the only project file of the current directory
+func searchMSBuildDescriptor(utils Utils) (string, error) {
+	if exists, _ := fileExists(MSBuildPropsDescriptor); exists {
+		return MSBuildPropsDescriptor, nil
+	}
+	projects := []string{}
+	if utils != nil {
+		for _, pattern := range MSBuildProjectPatterns {
+			matches, err := utils.Glob(pattern)
+			if err != nil {
+				return "", errors.Wrapf(err, "failed to search for %v", pattern)
+			}
+			projects = append(projects, matches...)
+		}
+	}
+	switch len(projects) {
+	case 0:
+		return "", fmt.Errorf("no build descriptor available, supported: %v", append([]string{MSBuildPropsDescriptor}, MSBuildProjectPatterns...))
+	case 1:
+		return projects[0], nil
+	default:
+		return "", fmt.Errorf("multiple project files found %v, please define the build descriptor to be used", projects)
+	}
+}
@@ -0,0 +1,149 @@
+//go:build unit
+// +build unit
+
+package versioning
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	msbuildProject = `<Project Sdk="Microsoft.NET.Sdk">
+
+  <PropertyGroup>
+    <TargetFramework>net8.0</TargetFramework>
+    <PackageId>Company.App</PackageId>
+    <Version>1.0.0</Version>
+  </PropertyGroup>
+
+</Project>
+`
+	msbuildProps = `<Project>
+  <PropertyGroup Label="Versioning">
+    <VersionPrefix>2.1.0</VersionPrefix>
+    <VersionSuffix>beta</VersionSuffix>
+  </PropertyGroup>
+</Project>
+`
+)
+
+func TestMSBuild(t *testing.T) {
+	newMSBuild := func(path, content string) (*MSBuild, map[string][]byte) {
+		written := map[string][]byte{}
+		return &MSBuild{
+			path: path,
+			readFile: func(path string) ([]byte, error) {
+				return []byte(content), nil
+			},
+			writeFile: func(path string, content []byte, perm os.FileMode) error {
+				written[path] = content
+				return nil
+			},
+		}, written
+	}
+
+	t.Run("version of project", func(t *testing.T) {
+		msbuild, written := newMSBuild("App.csproj", msbuildProject)
+
+		version, err := msbuild.GetVersion()
+		require.NoError(t, err)
+		assert.Equal(t, "1.0.0", version)
+
+		require.NoError(t, msbuild.SetVersion("1.0.1-20240101120000"))
+		assert.Equal(t, `<Project Sdk="Microsoft.NET.Sdk">
+
+  <PropertyGroup>
+    <TargetFramework>net8.0</TargetFramework>
+    <PackageId>Company.App</PackageId>
+    <Version>1.0.1-20240101120000</Version>
+  </PropertyGroup>
+
+</Project>
+`, string(written["App.csproj"]))
+
+		version, err = msbuild.GetVersion()
+		require.NoError(t, err)
+		assert.Equal(t, "1.0.1-20240101120000", version)
+	})
+
+	t.Run("version prefix and suffix", func(t *testing.T) {
+		msbuild, written := newMSBuild("Directory.Build.props", msbuildProps)
+
+		version, err := msbuild.GetVersion()
+		require.NoError(t, err)
+		assert.Equal(t, "2.1.0-beta", version)
+
+		require.NoError(t, msbuild.SetVersion("2.1.0"))
+		assert.Equal(t, `<Project>
+  <PropertyGroup Label="Versioning">
+    <Version>2.1.0</Version>
+    <VersionPrefix>2.1.0</VersionPrefix>
+    <VersionSuffix>beta</VersionSuffix>
+  </PropertyGroup>
+</Project>
+`, string(written["Directory.Build.props"]))
+
+		version, err = msbuild.GetVersion()
+		require.NoError(t, err)
+		assert.Equal(t, "2.1.0", version)
+	})
+
+	t.Run("version defined by properties", func(t *testing.T) {
+		msbuild, _ := newMSBuild("App.csproj", `<Project><PropertyGroup><Version>$(Major).0.0</Version></PropertyGroup></Project>`)
+
+		_, err := msbuild.GetVersion()
+
+		assert.EqualError(t, err, "the version '$(Major).0.0' in file 'App.csproj' is not a literal value")
+	})
+
+	t.Run("no version", func(t *testing.T) {
+		msbuild, _ := newMSBuild("App.csproj", `<Project Sdk="Microsoft.NET.Sdk"><PropertyGroup></PropertyGroup></Project>`)
+
+		_, err := msbuild.GetVersion()
+		assert.EqualError(t, err, "no version information found in file 'App.csproj'")
+
+		assert.NoError(t, msbuild.SetVersion("1.0.0"))
+		version, err := msbuild.GetVersion()
+		assert.NoError(t, err)
+		assert.Equal(t, "1.0.0", version)
+	})
+
+	t.Run("no property group", func(t *testing.T) {
+		msbuild, _ := newMSBuild("App.csproj", `<Project Sdk="Microsoft.NET.Sdk"></Project>`)
+
+		err := msbuild.SetVersion("1.0.0")
+
+		assert.EqualError(t, err, "no PropertyGroup found in file 'App.csproj'")
+	})
+
+	t.Run("coordinates", func(t *testing.T) {
+		msbuild, _ := newMSBuild("App.csproj", msbuildProject)
+
+		coordinates, err := msbuild.GetCoordinates()
+
+		assert.NoError(t, err)
+		assert.Equal(t, Coordinates{ArtifactID: "Company.App", Version: "1.0.0"}, coordinates)
+	})
+
+	t.Run("coordinates - default package id", func(t *testing.T) {
+		msbuild, _ := newMSBuild("src/App/App.csproj", `<Project><PropertyGroup><Version>3.0.0</Version></PropertyGroup></Project>`)
+
+		coordinates, err := msbuild.GetCoordinates()
+
+		assert.NoError(t, err)
+		assert.Equal(t, Coordinates{ArtifactID: "App", Version: "3.0.0"}, coordinates)
+	})
+
+	t.Run("read error", func(t *testing.T) {
+		msbuild := &MSBuild{path: "App.csproj", readFile: func(string) ([]byte, error) { return nil, fmt.Errorf("read error") }}
+
+		_, err := msbuild.GetVersion()
+
+		assert.EqualError(t, err, "failed to read file 'App.csproj': read error")
+	})
+}
@@ -22,6 +22,35 @@ type tomlCoordinates struct {
 		Name    string `toml:"name"`
 		Version string `toml:"version"`
 	} `toml:"project"`
+	Tool struct {
+		// Poetry defines the coordinates in [tool.poetry] unless they are defined in [project] as of Poetry 2
+		Poetry struct {
+			Name    string `toml:"name"`
+			Version string `toml:"version"`
+		} `toml:"poetry"`
+	} `toml:"tool"`
+}
+
+func (c tomlCoordinates) name() string {
+	if len(c.Project.Name) > 0 {
+		return c.Project.Name
+	}
+	return c.Tool.Poetry.Name
+}
+
+func (c tomlCoordinates) version() string {
+	if len(c.Project.Version) > 0 {
+		return c.Project.Version
+	}
+	return c.Tool.Poetry.Version
+}
+
+// versionSection returns the table defining the version, either [project] or [tool.poetry]
+func (c tomlCoordinates) versionSection() string {
+	if len(c.Project.Version) > 0 {
+		return "project"
+	}
+	return "tool.poetry"
 }
 
 func (p *Toml) init() error {
@@ -47,10 +76,10 @@ func (p *Toml) GetName() (string, error) {
 	if err := p.init(); err != nil {
 		return "", fmt.Errorf("failed to read file '%v': %w", p.Pip.path, err)
 	}
-	if len(p.coordinates.Project.Name) == 0 {
+	if len(p.coordinates.name()) == 0 {
 		return "", fmt.Errorf("no name information found in file '%v'", p.Pip.path)
 	}
-	return p.coordinates.Project.Name, nil
+	return p.coordinates.name(), nil
 }
 
 // // GetVersion returns the current version from the build descriptor
@@ -58,33 +87,41 @@ func (p *Toml) GetVersion() (string, error) {
 	if err := p.init(); err != nil {
 		return "", fmt.Errorf("failed to read file '%v': %w", p.Pip.path, err)
 	}
-	if len(p.coordinates.Project.Version) == 0 {
+	if len(p.coordinates.version()) == 0 {
 		return "", fmt.Errorf("no version information found in file '%v'", p.Pip.path)
 	}
-	return p.coordinates.Project.Version, nil
+	return p.coordinates.version(), nil
 }
 
-// SetVersion updates the version in the build descriptor
+// SetVersion updates the version in the build descriptor, versions of other tables like the dependencies are kept as is
 func (p *Toml) SetVersion(new string) error {
-	if current, err := p.GetVersion(); err != nil {
+	if _, err := p.GetVersion(); err != nil {
 		return err
-	} else {
-		// replace with single quotes
-		p.Pip.buildDescriptorContent = strings.ReplaceAll(
-			p.Pip.buildDescriptorContent,
-			fmt.Sprintf("version = '%v'", current),
-			fmt.Sprintf("version = '%v'", new))
-		// replace with double quotes as well
-		p.Pip.buildDescriptorContent = strings.ReplaceAll(
-			p.Pip.buildDescriptorContent,
-			fmt.Sprintf("version = \"%v\"", current),
-			fmt.Sprintf("version = \"%v\"", new))
-		err = p.Pip.writeFile(p.Pip.path, []byte(p.Pip.buildDescriptorContent), 0600)
-		if err != nil {
-			return fmt.Errorf("failed to write file '%v': %w", p.Pip.path, err)
+	}
+	section := p.coordinates.versionSection()
+	lines := strings.Split(p.Pip.buildDescriptorContent, "\n")
+	currentSection := ""
+	updated := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") {
+			currentSection = strings.TrimSpace(strings.Trim(strings.TrimSpace(strings.SplitN(trimmed, "#", 2)[0]), "[]"))
+			continue
+		}
+		if currentSection == section && cargoVersionLine.MatchString(line) {
+			lines[i] = cargoVersionLine.ReplaceAllString(line, "${1}${2}"+new+"${3}")
+			updated = true
+			break
 		}
-		return nil
 	}
+	if !updated {
+		return fmt.Errorf("failed to update version in section [%v] of file '%v'", section, p.Pip.path)
+	}
+	p.Pip.buildDescriptorContent = strings.Join(lines, "\n")
+	if err := p.Pip.writeFile(p.Pip.path, []byte(p.Pip.buildDescriptorContent), 0600); err != nil {
+		return fmt.Errorf("failed to write file '%v': %w", p.Pip.path, err)
+	}
+	return nil
 }
 
 // GetCoordinates returns the build descriptor coordinates
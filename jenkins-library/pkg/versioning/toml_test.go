@@ -17,6 +17,19 @@ const (
 	sampleToml  = `[project]
 name = "simple-python"
 version = "1.2.3"
+`
+	poetryToml = `[tool.poetry]
+name = "poetry-project"
+version = "0.3.0"
+description = ""
+
+[tool.poetry.dependencies]
+python = "^3.11"
+requests = "^2.31"
+
+[build-system]
+requires = ["poetry-core"]
+build-backend = "poetry.core.masonry.api"
 `
 	missingVersionToml = `[project]
 name = "simple-python"
@@ -112,6 +125,85 @@ func TestTomlSetVersion(t *testing.T) {
 	})
 }
 
+func TestTomlSetVersionKeepsDependencies(t *testing.T) {
+	t.Parallel()
+	t.Run("project", func(t *testing.T) {
+		fileUtils := piperMock.FilesMock{}
+		fileUtils.AddFile(TomlBuildDescriptor, []byte(`[project]
+name = "simple-python"
+version = "1.2.3"
+
+[tool.poetry.dependencies.shared]
+version = "1.2.3"
+`))
+		toml := Toml{Pip: Pip{path: TomlBuildDescriptor, fileExists: fileUtils.FileExists, readFile: fileUtils.FileRead, writeFile: fileUtils.FileWrite}}
+
+		err := toml.SetVersion("1.3.0")
+
+		assert.NoError(t, err)
+		content, _ := fileUtils.FileRead(TomlBuildDescriptor)
+		assert.Equal(t, `[project]
+name = "simple-python"
+version = "1.3.0"
+
+[tool.poetry.dependencies.shared]
+version = "1.2.3"
+`, string(content))
+	})
+
+	t.Run("poetry", func(t *testing.T) {
+		fileUtils := piperMock.FilesMock{}
+		fileUtils.AddFile(TomlBuildDescriptor, []byte(`[tool.poetry.dependencies.shared]
+version = '0.3.0'
+
+[tool.poetry]
+name = "poetry-project"
+version = '0.3.0'
+`))
+		toml := Toml{Pip: Pip{path: TomlBuildDescriptor, fileExists: fileUtils.FileExists, readFile: fileUtils.FileRead, writeFile: fileUtils.FileWrite}}
+
+		err := toml.SetVersion("0.4.0")
+
+		assert.NoError(t, err)
+		content, _ := fileUtils.FileRead(TomlBuildDescriptor)
+		assert.Equal(t, `[tool.poetry.dependencies.shared]
+version = '0.3.0'
+
+[tool.poetry]
+name = "poetry-project"
+version = '0.4.0'
+`, string(content))
+	})
+}
+
+func TestTomlPoetry(t *testing.T) {
+	t.Parallel()
+	fileUtils := piperMock.FilesMock{}
+	fileUtils.AddFile(TomlBuildDescriptor, []byte(poetryToml))
+
+	toml := Toml{
+		Pip: Pip{
+			path:       TomlBuildDescriptor,
+			fileExists: fileUtils.FileExists,
+			readFile:   fileUtils.FileRead,
+			writeFile:  fileUtils.FileWrite,
+		},
+	}
+
+	coordinates, err := toml.GetCoordinates()
+	assert.NoError(t, err)
+	assert.Equal(t, "poetry-project", coordinates.ArtifactID)
+	assert.Equal(t, "0.3.0", coordinates.Version)
+
+	err = toml.SetVersion("0.3.1")
+	assert.NoError(t, err)
+	content, _ := fileUtils.FileRead(TomlBuildDescriptor)
+	assert.Contains(t, string(content), "[tool.poetry]\nname = \"poetry-project\"\nversion = \"0.3.1\"\n")
+	coordinates, err = toml.GetCoordinates()
+	assert.NoError(t, err)
+	assert.Equal(t, "0.3.1", coordinates.Version)
+}
+
 func TestTomlGetCoordinates(t *testing.T) {
 	t.Parallel()
 	t.Run("success case - pyproject.toml", func(t *testing.T) {
@@ -85,6 +85,13 @@ func GetArtifact(buildTool, buildDescriptorFilePath string, opts *Options, utils
 	}
 
 	switch buildTool {
+	case "cargo":
+		if len(buildDescriptorFilePath) == 0 {
+			buildDescriptorFilePath = CargoBuildDescriptor
+		}
+		artifact = &Cargo{
+			path: buildDescriptorFilePath,
+		}
 	case "custom":
 		var err error
 		artifact, err = customArtifact(buildDescriptorFilePath, opts.VersionField, opts.VersionSection, opts.VersioningScheme)
@@ -99,6 +106,17 @@ func GetArtifact(buildTool, buildDescriptorFilePath string, opts *Options, utils
 			versionSource:    opts.VersionSource,
 			versioningScheme: opts.VersioningScheme,
 		}
+	case "dotnet":
+		if len(buildDescriptorFilePath) == 0 {
+			var err error
+			buildDescriptorFilePath, err = searchMSBuildDescriptor(utils)
+			if err != nil {
+				return artifact, err
+			}
+		}
+		artifact = &MSBuild{
+			path: buildDescriptorFilePath,
+		}
 	case "dub":
 		if len(buildDescriptorFilePath) == 0 {
 			buildDescriptorFilePath = "dub.json"
@@ -56,6 +56,54 @@ func TestGetArtifact(t *testing.T) {
 		assert.Equal(t, "docker", docker.VersioningScheme())
 	})
 
+	t.Run("cargo", func(t *testing.T) {
+		cargo, err := GetArtifact("cargo", "", &Options{}, nil)
+
+		assert.NoError(t, err)
+
+		theType, ok := cargo.(*Cargo)
+		assert.True(t, ok)
+		assert.Equal(t, "Cargo.toml", theType.path)
+		assert.Equal(t, "semver2", cargo.VersioningScheme())
+	})
+
+	t.Run("dotnet - Directory.Build.props", func(t *testing.T) {
+		utils := newVersioningMockUtils()
+		utils.AddFile("Directory.Build.props", []byte(""))
+		utils.AddFile("App.csproj", []byte(""))
+		fileExists = utils.FilesMock.FileExists
+
+		dotnet, err := GetArtifact("dotnet", "", &Options{}, utils)
+
+		assert.NoError(t, err)
+		theType, ok := dotnet.(*MSBuild)
+		assert.True(t, ok)
+		assert.Equal(t, "Directory.Build.props", theType.path)
+		assert.Equal(t, "semver2", dotnet.VersioningScheme())
+	})
+
+	t.Run("dotnet - project file", func(t *testing.T) {
+		utils := newVersioningMockUtils()
+		utils.AddFile("App.fsproj", []byte(""))
+		fileExists = utils.FilesMock.FileExists
+
+		dotnet, err := GetArtifact("dotnet", "", &Options{}, utils)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "App.fsproj", dotnet.(*MSBuild).path)
+	})
+
+	t.Run("dotnet - multiple project files", func(t *testing.T) {
+		utils := newVersioningMockUtils()
+		utils.AddFile("App.csproj", []byte(""))
+		utils.AddFile("Lib.csproj", []byte(""))
+		fileExists = utils.FilesMock.FileExists
+
+		_, err := GetArtifact("dotnet", "", &Options{}, utils)
+
+		assert.EqualError(t, err, "multiple project files found [App.csproj Lib.csproj], please define the build descriptor to be used")
+	})
+
 	t.Run("dub", func(t *testing.T) {
 		dub, err := GetArtifact("dub", "", &Options{VersionField: "theversion"}, nil)
 
@@ -175,6 +175,21 @@ func (c *ConfigOptions) addBuildToolDefaults(config *ScanOptions, utils Utils) e
 			{Name: "updateType", Value: "OVERRIDE", Force: true},
 			{Name: "docker.excludeBaseImage", Value: "true", Force: false},
 		},
+		"cargo": {
+			{Name: "fileSystemScan", Value: false, Force: true},
+			{Name: "ignoreSourceFiles", Value: true, Force: true},
+			{Name: "cargo.resolveDependencies", Value: true, Force: true},
+			{Name: "cargo.ignoreSourceFiles", Value: true, Force: true},
+			{Name: "cargo.runPreStep", Value: true},
+		},
+		"dotnet": {
+			{Name: "fileSystemScan", Value: false, Force: true},
+			{Name: "ignoreSourceFiles", Value: true, Force: true},
+			{Name: "nuget.resolveDependencies", Value: true, Force: true},
+			{Name: "nuget.resolveCsProjFiles", Value: true},
+			{Name: "nuget.ignoreSourceFiles", Value: true, Force: true},
+			{Name: "nuget.runPreStep", Value: true},
+		},
 		"dub": {
 			{Name: "ignoreSourceFiles", Value: true, Force: true},
 			{Name: "includes", Value: "**/*.d **/*.di"},
@@ -15,7 +15,8 @@ const authHeaderKey = "Authorization"
 const oneHourInSeconds = 3600.0
 
 // XSUAA contains the fields to authenticate to a xsuaa service instance on BTP to retrieve a access token
-// It also caches the latest retrieved access token
+// It also caches the latest retrieved access token.
+// Since the ANS log hook authenticates with it, it cannot use the OAuth2 support of the http package, which depends on the log package.
 type XSUAA struct {
 	OAuthURL        string
 	ClientID        string
@@ -156,6 +156,7 @@ public class CommonStepsTest extends BasePiperTest{
         'githubCommentIssue', //implementing new golang pattern without fields
         'githubSetCommitStatus', //implementing new golang pattern without fields
         'kubernetesDeploy', //implementing new golang pattern without fields
+        'kubernetesValidateManifests', //implementing new golang pattern without fields
         'piperExecuteBin', //implementing new golang pattern without fields
         'protecodeExecuteScan', //implementing new golang pattern without fields
         'xsDeploy', //implementing new golang pattern without fields
@@ -193,6 +194,7 @@ public class CommonStepsTest extends BasePiperTest{
         'whitesourceExecuteScan', //implementing new golang pattern without fields
         'uiVeri5ExecuteTests', //implementing new golang pattern without fields
         'integrationArtifactDeploy', //implementing new golang pattern without fields
+        'integrationArtifactDeployMultiTenant', //implementing new golang pattern without fields
         'integrationArtifactUpdateConfiguration', //implementing new golang pattern without fields
         'integrationArtifactGetMplStatus', //implementing new golang pattern without fields
         'integrationArtifactGetServiceEndpoint', //implementing new golang pattern without fields
@@ -202,6 +204,7 @@ public class CommonStepsTest extends BasePiperTest{
         'integrationArtifactTriggerIntegrationTest', //implementing new golang pattern without fields
         'integrationArtifactUnDeploy', //implementing new golang pattern without fields
         'integrationArtifactResource', //implementing new golang pattern without fields
+        'integrationPackageSync', //implementing new golang pattern without fields
         'containerExecuteStructureTests', //implementing new golang pattern without fields
         'transportRequestUploadSOLMAN', //implementing new golang pattern without fields
         'transportRequestReqIDFromGit', //implementing new golang pattern without fields
@@ -212,8 +215,14 @@ public class CommonStepsTest extends BasePiperTest{
         'writePipelineEnv', //implementing new golang pattern without fields
         'readPipelineEnv', //implementing new golang pattern without fields
         'transportRequestUploadCTS', //implementing new golang pattern without fields
+        'transportRequestLifecycleCTS', //implementing new golang pattern without fields
+        'transportRequestLifecycleRFC', //implementing new golang pattern without fields
+        'transportRequestLifecycleSOLMAN', //implementing new golang pattern without fields
         'isChangeInDevelopment', //implementing new golang pattern without fields
         'golangBuild', //implementing new golang pattern without fields
+        'cargoBuild', //implementing new golang pattern without fields
+        'dotnetBuild', //implementing new golang pattern without fields
+        'testResultsAggregate', //implementing new golang pattern without fields
         'helmExecute', //implementing new golang pattern without fields
         'apiProxyDownload', //implementing new golang pattern without fields
         'apiKeyValueMapDownload', //implementing new golang pattern without fields
@@ -221,18 +230,22 @@ public class CommonStepsTest extends BasePiperTest{
         'apiProxyUpload', //implementing new golang pattern without fields
         'gradleExecuteBuild', //implementing new golang pattern without fields
         'shellExecute', //implementing new golang pattern without fields
+        'smokeTestExecute', //implementing new golang pattern without fields
         'apiKeyValueMapUpload', //implementing new golang pattern without fields
         'apiProviderUpload', //implementing new golang pattern without fields
+        'apiManagementSync', //implementing new golang pattern without fields
         'pythonBuild', //implementing new golang pattern without fields
         'awsS3Upload',
         'apiProxyList', //implementing new golang pattern without fields
         'azureBlobUpload',
         'awsS3Upload',
         'ansSendEvent',
+        'pipelineNotify', //implementing new golang pattern without fields
         'apiProviderList', //implementing new golang pattern without fields
         'tmsUpload',
         'tmsExport',
         'imagePushToRegistry',
+        'imageSignature', //implementing new golang pattern without fields
         'gcpPublishEvent'
     ]
 
@@ -4,6 +4,8 @@ import groovy.transform.Field
 @Field String METADATA_FILE = 'metadata/containerExecuteStructureTests.yaml'
 
 void call(Map parameters = [:]) {
-    List credentials = []
+    List credentials = [
+        [type: 'file', id: 'dockerConfigJsonCredentialsId', env: ['PIPER_dockerConfigJSON']]
+    ]
     piperExecuteBin(parameters, STEP_NAME, METADATA_FILE, credentials)
 }
@@ -0,0 +1,13 @@
+import groovy.transform.Field
+
+@Field String STEP_NAME = getClass().getName()
+@Field String METADATA_FILE = 'metadata/imageSignature.yaml'
+
+void call(Map parameters = [:]) {
+    List credentials = [
+        [type: 'file', id: 'dockerConfigJsonCredentialsId', env: ['PIPER_dockerConfigJSON']],
+        [type: 'file', id: 'signingKeyCredentialsId', env: ['PIPER_signingKey']],
+        [type: 'token', id: 'signingKeyPasswordCredentialsId', env: ['PIPER_signingKeyPassword']]
+    ]
+    piperExecuteBin(parameters, STEP_NAME, METADATA_FILE, credentials)
+}
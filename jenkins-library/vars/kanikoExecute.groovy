@@ -4,6 +4,9 @@ import groovy.transform.Field
 @Field String METADATA_FILE = 'metadata/kanikoExecute.yaml'
 
 void call(Map parameters = [:]) {
-    List credentials = [[type: 'file', id: 'dockerConfigJsonCredentialsId', env: ['PIPER_dockerConfigJSON']]]
+    List credentials = [
+        [type: 'file', id: 'dockerConfigJsonCredentialsId', env: ['PIPER_dockerConfigJSON']],
+        [type: 'file', id: 'provenanceSigningKeyCredentialsId', env: ['PIPER_provenanceSigningKey']]
+    ]
     piperExecuteBin(parameters, STEP_NAME, METADATA_FILE, credentials)
 }
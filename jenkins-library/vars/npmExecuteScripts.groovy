@@ -13,8 +13,7 @@ import static groovy.json.JsonOutput.toJson
 void call(Map parameters = [:]) {
     final script = checkScript(this, parameters) ?: this
 
-    // No credentials required/supported as of now
-    List credentials = []
+    List credentials = [[type: 'file', id: 'provenanceSigningKeyCredentialsId', env: ['PIPER_provenanceSigningKey']]]
     parameters.dockerOptions = ['--cap-add=SYS_ADMIN'].plus(parameters.dockerOptions?:[])
     parameters = DownloadCacheUtils.injectDownloadCacheInParameters(script, parameters, BuildTool.NPM)
     piperExecuteBin(parameters, STEP_NAME, METADATA_FILE, credentials)
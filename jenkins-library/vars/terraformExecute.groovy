@@ -4,6 +4,9 @@ import groovy.transform.Field
 @Field String METADATA_FILE = 'metadata/terraformExecute.yaml'
 
 void call(Map parameters = [:]) {
-    List credentials = [[type: 'file', id: 'terraformSecrets', env: ['PIPER_terraformSecrets']]]
+    List credentials = [
+        [type: 'file', id: 'terraformSecrets', env: ['PIPER_terraformSecrets']],
+        [type: 'token', id: 'githubTokenCredentialsId', env: ['PIPER_githubToken']]
+    ]
     piperExecuteBin(parameters, STEP_NAME, METADATA_FILE, credentials)
 }